@@ -0,0 +1,25 @@
+package commands
+
+import "errors"
+
+// CreateGraphCommand represents a command to create a new graph for a user
+type CreateGraphCommand struct {
+	GraphID     string // Optional; generated when empty
+	UserID      string
+	Name        string
+	Description string
+}
+
+// Validate validates the CreateGraphCommand
+func (c CreateGraphCommand) Validate() error {
+	if c.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if c.Name == "" {
+		return errors.New("graph name is required")
+	}
+	if len(c.Name) > MaxTitleLength {
+		return errors.New("graph name exceeds maximum length")
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 type CreateNodeCommand struct {
 	NodeID  string   `json:"node_id" validate:"required"`
 	UserID  string   `json:"user_id" validate:"required"`
+	GraphID string   `json:"graph_id,omitempty"` // Empty creates the node in the user's default graph
 	Title   string   `json:"title" validate:"required,min=1,max=200"`
 	Content string   `json:"content" validate:"max=50000"`
 	Format  string   `json:"format" validate:"oneof=text markdown html json"`
@@ -0,0 +1,21 @@
+package commands
+
+import "errors"
+
+// DeleteGraphCommand represents a command to delete a graph with all of its
+// nodes, edges and versions
+type DeleteGraphCommand struct {
+	UserID  string
+	GraphID string
+}
+
+// Validate validates the DeleteGraphCommand
+func (c DeleteGraphCommand) Validate() error {
+	if c.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if c.GraphID == "" {
+		return errors.New("graph ID is required")
+	}
+	return nil
+}
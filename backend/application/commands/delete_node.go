@@ -6,6 +6,10 @@ import "errors"
 type DeleteNodeCommand struct {
 	UserID string
 	NodeID string
+
+	// ExpectedVersion, when set, rejects the delete unless the node is still
+	// at this version
+	ExpectedVersion *int
 }
 
 // Validate validates the DeleteNodeCommand
@@ -7,6 +7,8 @@ import (
 
 	"backend/application/commands"
 	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
 	"backend/domain/events"
 	"go.uber.org/zap"
@@ -20,6 +22,7 @@ type BulkDeleteNodesHandler struct {
 	graphRepo  ports.GraphRepository
 	eventStore ports.EventStore
 	eventBus   ports.EventBus
+	trash      *services.TrashService
 	logger     *zap.Logger
 }
 
@@ -44,7 +47,16 @@ func NewBulkDeleteNodesHandler(
 	}
 }
 
-// Handle executes the bulk delete command with transactional safety (all-or-nothing)
+// WithTrash makes bulk deletes move nodes into the trash bin instead of
+// removing them for good
+func (h *BulkDeleteNodesHandler) WithTrash(trash *services.TrashService) *BulkDeleteNodesHandler {
+	h.trash = trash
+	return h
+}
+
+// Handle executes the bulk delete command with transactional safety
+// (all-or-nothing). With the trash bin, nodes trashed before a failure are
+// restored before the error is returned.
 func (h *BulkDeleteNodesHandler) Handle(ctx context.Context, cmd commands.BulkDeleteNodesCommand) error {
 	// Validate command
 	if err := cmd.Validate(); err != nil {
@@ -143,25 +155,41 @@ func (h *BulkDeleteNodesHandler) Handle(ctx context.Context, cmd commands.BulkDe
 		nodeIDStrings[i] = info.nodeID.String()
 	}
 
-	if err := h.nodeRepo.DeleteBatch(ctx, nodeIDsToDelete); err != nil {
-		return fmt.Errorf("failed to delete nodes in batch: %w", err)
-	}
+	if h.trash != nil {
+		// Move the nodes and their edges into the trash bin; the trash
+		// service publishes a NodeTrashed event for each of them
+		nodes := make([]*entities.Node, 0, len(validNodes))
+		for _, info := range validNodes {
+			if node, ok := info.node.(*entities.Node); ok {
+				nodes = append(nodes, node)
+			}
+		}
+		trashed, err := h.trash.TrashNodes(ctx, nodes)
+		if err != nil {
+			h.restoreTrashed(ctx, cmd, trashed)
+			return fmt.Errorf("failed to move nodes to trash: %w", err)
+		}
+	} else {
+		if err := h.nodeRepo.DeleteBatch(ctx, nodeIDsToDelete); err != nil {
+			return fmt.Errorf("failed to delete nodes in batch: %w", err)
+		}
 
-	// NEW: Immediately delete associated edges using batch operation
-	// This is more efficient than transaction-based deletion
-	for graphID := range nodesByGraph {
-		if err := h.edgeRepo.DeleteByNodeIDs(ctx, graphID, nodeIDStrings); err != nil {
-			h.logger.Warn("Failed to delete edges for nodes in graph",
-				zap.String("graphID", graphID),
-				zap.Int("nodeCount", len(nodeIDStrings)),
-				zap.Error(err),
-			)
-			// Continue - async cleanup will handle any missed edges
-		} else {
-			h.logger.Info("Successfully deleted edges for nodes",
-				zap.String("graphID", graphID),
-				zap.Int("nodeCount", len(nodeIDStrings)),
-			)
+		// NEW: Immediately delete associated edges using batch operation
+		// This is more efficient than transaction-based deletion
+		for graphID := range nodesByGraph {
+			if err := h.edgeRepo.DeleteByNodeIDs(ctx, graphID, nodeIDStrings); err != nil {
+				h.logger.Warn("Failed to delete edges for nodes in graph",
+					zap.String("graphID", graphID),
+					zap.Int("nodeCount", len(nodeIDStrings)),
+					zap.Error(err),
+				)
+				// Continue - async cleanup will handle any missed edges
+			} else {
+				h.logger.Info("Successfully deleted edges for nodes",
+					zap.String("graphID", graphID),
+					zap.Int("nodeCount", len(nodeIDStrings)),
+				)
+			}
 		}
 	}
 
@@ -173,8 +201,13 @@ func (h *BulkDeleteNodesHandler) Handle(ctx context.Context, cmd commands.BulkDe
 	// Collect all events for batch publishing
 	var allEvents []events.DomainEvent
 
-	// Collect individual deletion events for async cleanup
-	for _, info := range validNodes {
+	// Collect individual deletion events for async cleanup. Trashed nodes
+	// keep their resources until they are purged, so they get none.
+	deletedNodes := validNodes
+	if h.trash != nil {
+		deletedNodes = nil
+	}
+	for _, info := range deletedNodes {
 		content := ""
 		if nodeEntity, ok := info.node.(interface{ Content() interface{ Title() string } }); ok {
 			content = nodeEntity.Content().Title()
@@ -230,6 +263,22 @@ func (h *BulkDeleteNodesHandler) Handle(ctx context.Context, cmd commands.BulkDe
 	return nil
 }
 
+// restoreTrashed brings back the nodes a failed bulk delete already moved into
+// the trash bin, so the command leaves none of its nodes deleted. They are
+// restored in reverse order: an edge between two of them is handed over to
+// the earlier node while it is still in the trash and reconnected with it.
+func (h *BulkDeleteNodesHandler) restoreTrashed(ctx context.Context, cmd commands.BulkDeleteNodesCommand, trashed []*ports.TrashedNode) {
+	for i := len(trashed) - 1; i >= 0; i-- {
+		if _, err := h.trash.Restore(ctx, cmd.UserID, trashed[i].NodeID); err != nil {
+			h.logger.Error("Failed to restore node after failed bulk delete",
+				zap.String("operationID", cmd.OperationID),
+				zap.String("nodeID", trashed[i].NodeID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 // nodeValidationInfo holds information about a validated node
 type nodeValidationInfo struct {
 	nodeID  valueobjects.NodeID
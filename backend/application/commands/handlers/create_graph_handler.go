@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend/application/commands"
+	"backend/application/ports"
+	"backend/domain/config"
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// CreateGraphHandler handles commands creating additional graphs for a user
+type CreateGraphHandler struct {
+	graphRepo    ports.GraphRepository
+	eventBus     ports.EventBus
+	domainConfig *config.DomainConfig
+	logger       *zap.Logger
+}
+
+// NewCreateGraphHandler creates a new create graph handler
+func NewCreateGraphHandler(
+	graphRepo ports.GraphRepository,
+	eventBus ports.EventBus,
+	domainConfig *config.DomainConfig,
+	logger *zap.Logger,
+) *CreateGraphHandler {
+	return &CreateGraphHandler{
+		graphRepo:    graphRepo,
+		eventBus:     eventBus,
+		domainConfig: domainConfig,
+		logger:       logger,
+	}
+}
+
+// Handle executes the create graph command
+func (h *CreateGraphHandler) Handle(ctx context.Context, cmd commands.CreateGraphCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return pkgerrors.NewValidationError(err.Error())
+	}
+
+	graph, err := aggregates.NewGraphWithID(aggregates.GraphID(cmd.GraphID), cmd.UserID, cmd.Name, cmd.Description, h.domainConfig)
+	if err != nil {
+		return err
+	}
+
+	// Graph names are unique per user, so they can be told apart in listings
+	if err := ensureUniqueGraphName(ctx, h.graphRepo, cmd.UserID, "", graph.Name()); err != nil {
+		return err
+	}
+
+	if err := h.graphRepo.Save(ctx, graph); err != nil {
+		return fmt.Errorf("failed to save graph: %w", err)
+	}
+
+	domainEvents := append(graph.GetUncommittedEvents(), events.NewGraphUpdatedEvent(graph.ID().String(), cmd.UserID, 0, 0))
+	if err := h.eventBus.PublishBatch(ctx, domainEvents); err != nil {
+		h.logger.Warn("Failed to publish graph created events",
+			zap.String("graphID", graph.ID().String()),
+			zap.Error(err),
+		)
+	}
+	graph.MarkEventsAsCommitted()
+
+	h.logger.Info("Graph created",
+		zap.String("graphID", graph.ID().String()),
+		zap.String("userID", cmd.UserID),
+	)
+	return nil
+}
+
+// ensureUniqueGraphName rejects a name another graph of the user already has.
+// The graph with exceptID is skipped, so a graph does not clash with itself.
+func ensureUniqueGraphName(ctx context.Context, graphRepo ports.GraphRepository, userID, exceptID, name string) error {
+	graphs, err := graphRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list graphs: %w", err)
+	}
+	for _, other := range graphs {
+		if other.ID().String() != exceptID && strings.EqualFold(other.Name(), name) {
+			return pkgerrors.NewConflictError(fmt.Sprintf("a graph named %q already exists", other.Name()))
+		}
+	}
+	return nil
+}
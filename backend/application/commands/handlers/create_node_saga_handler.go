@@ -9,7 +9,6 @@ import (
 	"backend/application/sagas"
 	"backend/application/services"
 	"backend/infrastructure/config"
-	"backend/infrastructure/persistence/dynamodb"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -31,7 +30,7 @@ func NewCreateNodeSagaHandler(
 	edgeService *services.EdgeService,
 	graphLazyService *services.GraphLazyService,
 	eventPublisher ports.EventPublisher,
-	distributedLock *dynamodb.DistributedLock,
+	distributedLock ports.DistributedLock,
 	operationStore ports.OperationStore,
 	edgeConfig *config.EdgeCreationConfig,
 	appConfig *config.Config,
@@ -59,6 +58,12 @@ func NewCreateNodeSagaHandler(
 	}
 }
 
+// WithNodeLimit caps the number of nodes a graph may hold; 0 disables the cap
+func (h *CreateNodeSagaHandler) WithNodeLimit(maxNodes int) *CreateNodeSagaHandler {
+	h.saga.WithNodeLimit(maxNodes)
+	return h
+}
+
 // Handle executes the create node command using saga pattern
 func (h *CreateNodeSagaHandler) Handle(ctx context.Context, cmd commands.CreateNodeCommand) error {
 	// Generate operation ID for tracking
@@ -81,6 +86,7 @@ func (h *CreateNodeSagaHandler) Handle(ctx context.Context, cmd commands.CreateN
 	// Prepare saga data
 	sagaData := &sagas.CreateNodeSagaData{
 		UserID:      cmd.UserID,
+		GraphID:     cmd.GraphID,
 		Title:       cmd.Title,
 		Content:     cmd.Content,
 		Tags:        cmd.Tags,
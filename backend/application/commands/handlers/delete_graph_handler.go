@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/commands"
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DeleteGraphHandler handles commands deleting a graph together with its
+// nodes, edges and versions
+type DeleteGraphHandler struct {
+	nodeRepo        ports.NodeRepository
+	edgeRepo        ports.EdgeRepository
+	graphRepo       ports.GraphRepository
+	versions        *services.GraphVersionService
+	eventBus        ports.EventBus
+	distributedLock ports.DistributedLock
+	trash           *services.TrashService
+	logger          *zap.Logger
+}
+
+// NewDeleteGraphHandler creates a new delete graph handler
+func NewDeleteGraphHandler(
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	versions *services.GraphVersionService,
+	eventBus ports.EventBus,
+	distributedLock ports.DistributedLock,
+	logger *zap.Logger,
+) *DeleteGraphHandler {
+	return &DeleteGraphHandler{
+		nodeRepo:        nodeRepo,
+		edgeRepo:        edgeRepo,
+		graphRepo:       graphRepo,
+		versions:        versions,
+		eventBus:        eventBus,
+		distributedLock: distributedLock,
+		logger:          logger,
+	}
+}
+
+// WithTrash makes deleting a graph move its nodes into the trash bin, like
+// deleting them one by one, instead of removing them for good. The graph is
+// gone, so they cannot be restored, but their history is purged with them.
+func (h *DeleteGraphHandler) WithTrash(trash *services.TrashService) *DeleteGraphHandler {
+	h.trash = trash
+	return h
+}
+
+// Handle executes the delete graph command
+func (h *DeleteGraphHandler) Handle(ctx context.Context, cmd commands.DeleteGraphCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return pkgerrors.NewValidationError(err.Error())
+	}
+
+	graph, err := h.graphRepo.GetByID(ctx, aggregates.GraphID(cmd.GraphID))
+	if err != nil || graph == nil || graph.UserID() != cmd.UserID {
+		return pkgerrors.NewNotFoundError("graph")
+	}
+	// New nodes without a graph land in the default graph, so it has to stay
+	if graph.IsDefault() {
+		return pkgerrors.NewConflictError("the default graph cannot be deleted")
+	}
+
+	// Transfers into the graph hold this lock; deleting underneath one would
+	// leave its copies without a graph
+	if h.distributedLock != nil {
+		lock, err := h.distributedLock.TryAcquire(ctx, "graph_transfer_"+cmd.GraphID, cmd.UserID, 5*time.Minute, 5*time.Second)
+		if err != nil {
+			return pkgerrors.NewConflictError("the graph is being modified").WithCause(err)
+		}
+		defer lock.Release(ctx)
+	}
+
+	nodes, err := h.nodeRepo.GetByGraphID(ctx, cmd.GraphID)
+	if err != nil {
+		return fmt.Errorf("failed to get graph nodes: %w", err)
+	}
+	nodeEvents, err := h.deleteNodes(ctx, cmd, nodes)
+	if err != nil {
+		return err
+	}
+
+	if err := h.graphRepo.Delete(ctx, aggregates.GraphID(cmd.GraphID)); err != nil {
+		return fmt.Errorf("failed to delete graph: %w", err)
+	}
+
+	// Versions of a deleted graph can no longer be rolled back to
+	if h.versions != nil {
+		if err := h.versions.DeleteVersions(ctx, cmd.GraphID); err != nil {
+			h.logger.Warn("Failed to delete versions of deleted graph",
+				zap.String("graphID", cmd.GraphID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	event := events.NewGraphDeletedEvent(cmd.GraphID, cmd.UserID)
+	if err := h.eventBus.PublishBatch(ctx, append(nodeEvents, event)); err != nil {
+		h.logger.Warn("Failed to publish graph deleted event",
+			zap.String("graphID", cmd.GraphID),
+			zap.Error(err),
+		)
+	}
+
+	h.logger.Info("Graph deleted",
+		zap.String("graphID", cmd.GraphID),
+		zap.String("userID", cmd.UserID),
+		zap.Int("nodes", len(nodes)),
+	)
+	return nil
+}
+
+// deleteNodes removes the nodes of the graph and their edges. Through the
+// trash bin this stops at the first node that cannot be trashed, leaving the
+// graph in place so the delete can be retried. Otherwise the nodes are removed
+// in one batch and the returned deletion events start the same asynchronous
+// cleanup as deleting a single node.
+func (h *DeleteGraphHandler) deleteNodes(ctx context.Context, cmd commands.DeleteGraphCommand, nodes []*entities.Node) ([]events.DomainEvent, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	if h.trash != nil {
+		if _, err := h.trash.TrashNodes(ctx, nodes); err != nil {
+			return nil, fmt.Errorf("failed to delete graph nodes: %w", err)
+		}
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(nodes))
+	nodeIDs := make([]valueobjects.NodeID, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID().String())
+		nodeIDs = append(nodeIDs, node.ID())
+	}
+	if err := h.edgeRepo.DeleteByNodeIDs(ctx, cmd.GraphID, ids); err != nil {
+		return nil, fmt.Errorf("failed to delete graph edges: %w", err)
+	}
+	if err := h.nodeRepo.DeleteBatch(ctx, nodeIDs); err != nil {
+		return nil, fmt.Errorf("failed to delete graph nodes: %w", err)
+	}
+
+	deleted := make([]events.DomainEvent, 0, len(nodes))
+	for _, node := range nodes {
+		deleted = append(deleted, events.NewNodeDeletedEvent(
+			node.ID(),
+			cmd.UserID,
+			cmd.GraphID,
+			node.Content().Title(),
+			node.GetTags(),
+			[]string{},
+			node.UpdatedAt(),
+		))
+	}
+	return deleted, nil
+}
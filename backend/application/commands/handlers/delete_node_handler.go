@@ -6,8 +6,11 @@ import (
 
 	"backend/application/commands"
 	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
 	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +21,7 @@ type DeleteNodeHandler struct {
 	graphRepo  ports.GraphRepository
 	eventStore ports.EventStore
 	eventBus   ports.EventBus
+	trash      *services.TrashService
 	logger     *zap.Logger
 }
 
@@ -40,6 +44,13 @@ func NewDeleteNodeHandler(
 	}
 }
 
+// WithTrash makes deletes move nodes into the trash bin instead of removing
+// them for good
+func (h *DeleteNodeHandler) WithTrash(trash *services.TrashService) *DeleteNodeHandler {
+	h.trash = trash
+	return h
+}
+
 // Handle executes the delete node command
 func (h *DeleteNodeHandler) Handle(ctx context.Context, cmd commands.DeleteNodeCommand) error {
 	// Validate command
@@ -63,6 +74,22 @@ func (h *DeleteNodeHandler) Handle(ctx context.Context, cmd commands.DeleteNodeC
 		return fmt.Errorf("node does not belong to user")
 	}
 
+	// Reject deletes based on a stale read of the node
+	if cmd.ExpectedVersion != nil && node.Version() != *cmd.ExpectedVersion {
+		return pkgerrors.NewVersionConflictError("node "+cmd.NodeID, *cmd.ExpectedVersion, node.Version())
+	}
+
+	if h.trash != nil {
+		if _, err := h.trash.Trash(ctx, node); err != nil {
+			return fmt.Errorf("failed to delete node: %w", err)
+		}
+		h.logger.Info("Node moved to trash",
+			zap.String("nodeID", cmd.NodeID),
+			zap.String("userID", cmd.UserID),
+		)
+		return nil
+	}
+
 	// Get the user's default graph ID for the async cleanup event
 	var graphID string
 	graph, err := h.graphRepo.GetUserDefaultGraph(ctx, cmd.UserID)
@@ -76,8 +103,8 @@ func (h *DeleteNodeHandler) Handle(ctx context.Context, cmd commands.DeleteNodeC
 		graphID = graph.ID().String()
 	}
 
-	// Delete the node
-	if err := h.nodeRepo.Delete(ctx, nodeID); err != nil {
+	// Delete the node, unless it changed since it was read above
+	if err := h.deleteNode(ctx, node); err != nil {
 		return fmt.Errorf("failed to delete node: %w", err)
 	}
 
@@ -109,3 +136,14 @@ func (h *DeleteNodeHandler) Handle(ctx context.Context, cmd commands.DeleteNodeC
 
 	return nil
 }
+
+// deleteNode removes the node at the version it was read, when the repository
+// supports conditional deletes
+func (h *DeleteNodeHandler) deleteNode(ctx context.Context, node *entities.Node) error {
+	if repo, ok := h.nodeRepo.(interface {
+		DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error
+	}); ok {
+		return repo.DeleteWithVersion(ctx, node.ID(), node.Version())
+	}
+	return h.nodeRepo.Delete(ctx, node.ID())
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/commands"
+	"backend/application/ports"
+	"backend/application/sagas"
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MoveNodesToGraphHandler handles commands moving or copying nodes between
+// graphs through the graph migration saga
+type MoveNodesToGraphHandler struct {
+	nodeRepo         ports.NodeRepository
+	edgeRepo         ports.EdgeRepository
+	graphRepo        ports.GraphRepository
+	eventBus         ports.EventBus
+	distributedLock  ports.DistributedLock
+	maxNodesPerGraph int
+	logger           *zap.Logger
+}
+
+// NewMoveNodesToGraphHandler creates a new move nodes to graph handler
+func NewMoveNodesToGraphHandler(
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	eventBus ports.EventBus,
+	distributedLock ports.DistributedLock,
+	maxNodesPerGraph int,
+	logger *zap.Logger,
+) *MoveNodesToGraphHandler {
+	return &MoveNodesToGraphHandler{
+		nodeRepo:         nodeRepo,
+		edgeRepo:         edgeRepo,
+		graphRepo:        graphRepo,
+		eventBus:         eventBus,
+		distributedLock:  distributedLock,
+		maxNodesPerGraph: maxNodesPerGraph,
+		logger:           logger,
+	}
+}
+
+// Handle executes the move nodes to graph command
+func (h *MoveNodesToGraphHandler) Handle(ctx context.Context, cmd commands.MoveNodesToGraphCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return pkgerrors.NewValidationError(err.Error())
+	}
+
+	// One transfer into a graph at a time, so concurrent transfers cannot
+	// both pass the node limit check
+	if h.distributedLock != nil {
+		lock, err := h.distributedLock.TryAcquire(ctx, "graph_transfer_"+cmd.TargetGraphID, cmd.UserID, 5*time.Minute, 5*time.Second)
+		if err != nil {
+			return pkgerrors.NewConflictError("another transfer into this graph is in progress").WithCause(err)
+		}
+		defer lock.Release(ctx)
+	}
+
+	saga := sagas.NewGraphMigrationSaga(
+		cmd.SourceGraphID,
+		cmd.TargetGraphID,
+		sagas.GraphMigrationOptions{
+			UserID:           cmd.UserID,
+			NodeIDs:          cmd.NodeIDs,
+			Move:             !cmd.Copy,
+			MaxNodesPerGraph: h.maxNodesPerGraph,
+		},
+		h.nodeRepo,
+		h.edgeRepo,
+		h.graphRepo,
+		h.logger,
+	)
+	result, err := saga.Execute(ctx)
+	if err != nil {
+		// Wrapped, so missing graphs or nodes and the node limit are still
+		// reported as such
+		return fmt.Errorf("failed to transfer nodes: %w", err)
+	}
+
+	graphIDs := []string{cmd.TargetGraphID}
+	if !cmd.Copy {
+		graphIDs = append(graphIDs, cmd.SourceGraphID)
+	}
+	domainEvents := make([]events.DomainEvent, 0, len(graphIDs))
+	for _, graphID := range graphIDs {
+		nodeCount, edgeCount := 0, 0
+		if stats, err := h.graphRepo.GetGraphStatistics(ctx, aggregates.GraphID(graphID)); err == nil {
+			nodeCount, edgeCount = stats.NodeCount, stats.EdgeCount
+		}
+		domainEvents = append(domainEvents, events.NewGraphUpdatedEvent(graphID, cmd.UserID, nodeCount, edgeCount))
+	}
+	if err := h.eventBus.PublishBatch(ctx, domainEvents); err != nil {
+		h.logger.Warn("Failed to publish graph updated events",
+			zap.String("targetGraphID", cmd.TargetGraphID),
+			zap.Error(err),
+		)
+	}
+
+	h.logger.Info("Nodes transferred between graphs",
+		zap.String("sourceGraphID", cmd.SourceGraphID),
+		zap.String("targetGraphID", cmd.TargetGraphID),
+		zap.Bool("copy", cmd.Copy),
+		zap.Int("nodes", len(result.NodeMapping)),
+		zap.Int("edges", result.CopiedEdges),
+		zap.Int("crossingEdges", len(result.CrossingEdges)),
+	)
+	return nil
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/commands"
+	"backend/application/ports"
+	"backend/application/sagas"
+	"backend/application/services"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// RollbackGraphHandler handles graph rollback commands through the graph
+// rollback saga
+type RollbackGraphHandler struct {
+	versions        *services.GraphVersionService
+	nodeRepo        ports.NodeRepository
+	edgeRepo        ports.EdgeRepository
+	graphRepo       ports.GraphRepository
+	eventBus        ports.EventBus
+	distributedLock ports.DistributedLock
+	logger          *zap.Logger
+}
+
+// NewRollbackGraphHandler creates a new rollback graph handler
+func NewRollbackGraphHandler(
+	versions *services.GraphVersionService,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	eventBus ports.EventBus,
+	distributedLock ports.DistributedLock,
+	logger *zap.Logger,
+) *RollbackGraphHandler {
+	return &RollbackGraphHandler{
+		versions:        versions,
+		nodeRepo:        nodeRepo,
+		edgeRepo:        edgeRepo,
+		graphRepo:       graphRepo,
+		eventBus:        eventBus,
+		distributedLock: distributedLock,
+		logger:          logger,
+	}
+}
+
+// Handle executes the rollback graph command
+func (h *RollbackGraphHandler) Handle(ctx context.Context, cmd commands.RollbackGraphCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return pkgerrors.NewValidationError(err.Error())
+	}
+
+	// One rollback per graph at a time; two interleaved rollbacks would each
+	// compute their changes from a state the other is rewriting
+	if h.distributedLock != nil {
+		lock, err := h.distributedLock.TryAcquire(ctx, "graph_rollback_"+cmd.GraphID, cmd.UserID, 5*time.Minute, 5*time.Second)
+		if err != nil {
+			return pkgerrors.NewConflictError("another rollback of this graph is in progress").WithCause(err)
+		}
+		defer lock.Release(ctx)
+	}
+
+	saga := sagas.NewGraphRollbackSaga(
+		cmd.UserID,
+		cmd.GraphID,
+		cmd.Version,
+		h.versions,
+		h.nodeRepo,
+		h.edgeRepo,
+		h.graphRepo,
+		h.logger,
+	)
+	result, err := saga.Execute(ctx)
+	if err != nil {
+		// Wrapped, so a missing graph or version is still reported as such
+		return fmt.Errorf("failed to roll back graph: %w", err)
+	}
+
+	nodeCount, edgeCount := len(result.Target.Nodes), len(result.Target.Edges)
+	if result.Result != nil {
+		nodeCount, edgeCount = result.Result.NodeCount, result.Result.EdgeCount
+	}
+	event := events.NewGraphRolledBackEvent(cmd.GraphID, cmd.UserID, cmd.Version, nodeCount, edgeCount, time.Now())
+	if err := h.eventBus.PublishBatch(ctx, []events.DomainEvent{event}); err != nil {
+		h.logger.Warn("Failed to publish graph rolled back event",
+			zap.String("graphID", cmd.GraphID),
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/commands"
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// UpdateGraphHandler handles commands renaming a graph or changing its
+// description
+type UpdateGraphHandler struct {
+	graphRepo ports.GraphRepository
+	eventBus  ports.EventBus
+	logger    *zap.Logger
+}
+
+// NewUpdateGraphHandler creates a new update graph handler
+func NewUpdateGraphHandler(graphRepo ports.GraphRepository, eventBus ports.EventBus, logger *zap.Logger) *UpdateGraphHandler {
+	return &UpdateGraphHandler{
+		graphRepo: graphRepo,
+		eventBus:  eventBus,
+		logger:    logger,
+	}
+}
+
+// Handle executes the update graph command
+func (h *UpdateGraphHandler) Handle(ctx context.Context, cmd commands.UpdateGraphCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return pkgerrors.NewValidationError(err.Error())
+	}
+
+	graph, err := h.graphRepo.GetByID(ctx, aggregates.GraphID(cmd.GraphID))
+	if err != nil || graph == nil || graph.UserID() != cmd.UserID {
+		return pkgerrors.NewNotFoundError("graph")
+	}
+
+	name, description := graph.Name(), graph.Description()
+	if cmd.Name != nil {
+		name = *cmd.Name
+	}
+	if cmd.Description != nil {
+		description = *cmd.Description
+	}
+	if name != graph.Name() {
+		if err := ensureUniqueGraphName(ctx, h.graphRepo, cmd.UserID, cmd.GraphID, name); err != nil {
+			return err
+		}
+	}
+
+	if err := graph.Rename(name, description); err != nil {
+		return err
+	}
+	if len(graph.GetUncommittedEvents()) == 0 {
+		return nil
+	}
+
+	if err := h.graphRepo.Save(ctx, graph); err != nil {
+		return fmt.Errorf("failed to save graph: %w", err)
+	}
+
+	domainEvents := append(graph.GetUncommittedEvents(),
+		events.NewGraphUpdatedEvent(cmd.GraphID, cmd.UserID, graph.NodeCount(), graph.EdgeCount()))
+	if err := h.eventBus.PublishBatch(ctx, domainEvents); err != nil {
+		h.logger.Warn("Failed to publish graph renamed events",
+			zap.String("graphID", cmd.GraphID),
+			zap.Error(err),
+		)
+	}
+	graph.MarkEventsAsCommitted()
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"backend/application/commands"
 	"backend/application/ports"
 	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -57,6 +58,11 @@ func (h *UpdateNodeHandler) Handle(ctx context.Context, cmd commands.UpdateNodeC
 		return fmt.Errorf("node does not belong to user")
 	}
 
+	// Reject changes based on a stale read of the node
+	if cmd.ExpectedVersion != nil && node.Version() != *cmd.ExpectedVersion {
+		return pkgerrors.NewVersionConflictError("node "+cmd.NodeID, *cmd.ExpectedVersion, node.Version())
+	}
+
 	// Apply updates using the Node's update methods
 	if cmd.Title != nil || cmd.Content != nil || cmd.Format != nil {
 		currentContent := node.Content()
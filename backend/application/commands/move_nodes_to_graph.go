@@ -0,0 +1,35 @@
+package commands
+
+import "errors"
+
+// MoveNodesToGraphCommand represents a command to move or copy nodes from one
+// of the user's graphs to another. Moved nodes keep their IDs; copies get new
+// ones. Edges between the transferred nodes come along; edges to nodes that
+// stay behind do not.
+type MoveNodesToGraphCommand struct {
+	UserID        string
+	SourceGraphID string
+	TargetGraphID string
+	NodeIDs       []string
+	Copy          bool // Keep the originals in the source graph
+}
+
+// Validate validates the MoveNodesToGraphCommand
+func (c MoveNodesToGraphCommand) Validate() error {
+	if c.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if c.SourceGraphID == "" || c.TargetGraphID == "" {
+		return errors.New("source and target graph IDs are required")
+	}
+	if c.SourceGraphID == c.TargetGraphID {
+		return errors.New("source and target graph must differ")
+	}
+	if len(c.NodeIDs) == 0 {
+		return errors.New("at least one node ID is required")
+	}
+	if len(c.NodeIDs) > 500 {
+		return errors.New("cannot move more than 500 nodes at once")
+	}
+	return nil
+}
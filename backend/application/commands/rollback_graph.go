@@ -0,0 +1,25 @@
+package commands
+
+import "errors"
+
+// RollbackGraphCommand represents a command to restore a graph to one of its
+// stored versions
+type RollbackGraphCommand struct {
+	UserID  string
+	GraphID string
+	Version int
+}
+
+// Validate validates the RollbackGraphCommand
+func (c RollbackGraphCommand) Validate() error {
+	if c.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if c.GraphID == "" {
+		return errors.New("graph ID is required")
+	}
+	if c.Version < 1 {
+		return errors.New("version must be positive")
+	}
+	return nil
+}
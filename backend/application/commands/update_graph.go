@@ -0,0 +1,29 @@
+package commands
+
+import "errors"
+
+// UpdateGraphCommand represents a command to rename a graph or change its
+// description. Nil fields are left unchanged.
+type UpdateGraphCommand struct {
+	UserID      string
+	GraphID     string
+	Name        *string
+	Description *string
+}
+
+// Validate validates the UpdateGraphCommand
+func (c UpdateGraphCommand) Validate() error {
+	if c.UserID == "" {
+		return errors.New("user ID is required")
+	}
+	if c.GraphID == "" {
+		return errors.New("graph ID is required")
+	}
+	if c.Name == nil && c.Description == nil {
+		return errors.New("nothing to update")
+	}
+	if c.Name != nil && len(*c.Name) > MaxTitleLength {
+		return errors.New("graph name exceeds maximum length")
+	}
+	return nil
+}
@@ -16,6 +16,10 @@ type UpdateNodeCommand struct {
 	Y       *float64
 	Z       *float64
 	Tags    *[]string
+
+	// ExpectedVersion, when set, rejects the update unless the node is still
+	// at this version
+	ExpectedVersion *int
 }
 
 // Validate validates the UpdateNodeCommand
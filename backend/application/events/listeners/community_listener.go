@@ -0,0 +1,159 @@
+package listeners
+
+import (
+	"context"
+
+	appevents "backend/application/events"
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// communityEventTypes are the events that change the nodes or edges of a
+// graph's communities
+var communityEventTypes = []string{
+	"NodeCreated",
+	"NodeCreatedWithPendingEdges",
+	"NodesConnected",
+	"NodesDisconnected",
+	"NodesAutoConnected",
+	"EdgeDeletedEvent",
+	"NodeRestoredEvent",
+	"NodeDeletedEvent",
+	"NodeTrashedEvent",
+	"NodePurgedEvent",
+	"BulkNodesDeletedEvent",
+	"GraphRolledBackEvent",
+	"GraphUpdatedEvent",
+	"GraphDeletedEvent",
+}
+
+// CommunityListener keeps the stored communities of each graph current by
+// revising them as nodes and edges change
+type CommunityListener struct {
+	appevents.BaseEventHandler
+	communities *services.CommunityDetectionService
+	nodeRepo    ports.NodeRepository
+	logger      *zap.Logger
+}
+
+// NewCommunityListener creates a new community listener
+func NewCommunityListener(communities *services.CommunityDetectionService, nodeRepo ports.NodeRepository, logger *zap.Logger) *CommunityListener {
+	return &CommunityListener{
+		BaseEventHandler: appevents.NewBaseEventHandler(
+			"CommunityListener",
+			60, // after the search indexes
+			communityEventTypes,
+		),
+		communities: communities,
+		nodeRepo:    nodeRepo,
+		logger:      logger,
+	}
+}
+
+// Subscribe subscribes the listener to node, edge and graph events
+func (l *CommunityListener) Subscribe(registry *appevents.HandlerRegistry) error {
+	if err := registry.Register(communityEventTypes, l); err != nil {
+		l.logger.Error("Failed to register community listener",
+			zap.Error(err),
+			zap.Strings("eventTypes", communityEventTypes))
+		return err
+	}
+	return nil
+}
+
+// Handle processes a domain event. Events arrive as values or pointers
+// depending on the publisher, so both are accepted.
+func (l *CommunityListener) Handle(ctx context.Context, event events.DomainEvent) error {
+	switch e := event.(type) {
+	case events.NodeCreated:
+		return l.update(ctx, e.GraphID, e.NodeID.String())
+	case *events.NodeCreated:
+		return l.update(ctx, e.GraphID, e.NodeID.String())
+	case *events.NodeCreatedWithPendingEdges:
+		return l.update(ctx, e.GraphID, e.NodeID)
+	case events.NodesConnected:
+		return l.updateAround(ctx, e.SourceID, e.TargetID)
+	case *events.NodesConnected:
+		return l.updateAround(ctx, e.SourceID, e.TargetID)
+	case events.NodesDisconnected:
+		return l.updateAround(ctx, e.SourceID, e.TargetID)
+	case *events.NodesDisconnected:
+		return l.updateAround(ctx, e.SourceID, e.TargetID)
+	case events.NodesAutoConnected:
+		return l.update(ctx, e.GraphID, e.NodeID.String())
+	case *events.NodesAutoConnected:
+		return l.update(ctx, e.GraphID, e.NodeID.String())
+	case events.EdgeDeletedEvent:
+		return l.updateAround(ctx, e.SourceNodeID, e.TargetNodeID)
+	case *events.EdgeDeletedEvent:
+		return l.updateAround(ctx, e.SourceNodeID, e.TargetNodeID)
+	case events.NodeRestoredEvent:
+		return l.update(ctx, e.GraphID, e.NodeID.String())
+	case *events.NodeRestoredEvent:
+		return l.update(ctx, e.GraphID, e.NodeID.String())
+	case events.NodeDeletedEvent:
+		return l.update(ctx, e.GraphID)
+	case *events.NodeDeletedEvent:
+		return l.update(ctx, e.GraphID)
+	case events.NodeTrashedEvent:
+		return l.update(ctx, e.GraphID)
+	case *events.NodeTrashedEvent:
+		return l.update(ctx, e.GraphID)
+	case events.NodePurgedEvent:
+		return l.update(ctx, e.GraphID)
+	case *events.NodePurgedEvent:
+		return l.update(ctx, e.GraphID)
+	case *events.BulkNodesDeletedEvent:
+		return l.communities.RemoveNodes(ctx, e.UserID, e.DeletedIDs)
+	case events.GraphRolledBackEvent:
+		return l.recompute(ctx, e.GraphID)
+	case *events.GraphRolledBackEvent:
+		return l.recompute(ctx, e.GraphID)
+	case events.GraphUpdatedEvent:
+		return l.update(ctx, e.GraphID)
+	case *events.GraphUpdatedEvent:
+		return l.update(ctx, e.GraphID)
+	case events.GraphDeletedEvent:
+		return l.communities.DeleteGraph(ctx, e.GraphID)
+	case *events.GraphDeletedEvent:
+		return l.communities.DeleteGraph(ctx, e.GraphID)
+	}
+	return nil
+}
+
+// update revises a graph's communities around the changed nodes
+func (l *CommunityListener) update(ctx context.Context, graphID string, changedNodeIDs ...string) error {
+	if graphID == "" {
+		return nil
+	}
+	_, err := l.communities.UpdateGraph(ctx, graphID, changedNodeIDs)
+	return err
+}
+
+// updateAround revises the communities around both ends of a changed edge.
+// Edge events do not carry the graph, so it is taken from a stored end.
+func (l *CommunityListener) updateAround(ctx context.Context, sourceID, targetID valueobjects.NodeID) error {
+	for _, id := range []valueobjects.NodeID{sourceID, targetID} {
+		node, err := l.nodeRepo.GetByID(ctx, id)
+		if err != nil {
+			if pkgerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		return l.update(ctx, node.GraphID(), sourceID.String(), targetID.String())
+	}
+	// Both ends are gone; their deletion events revise the graph
+	return nil
+}
+
+// recompute detects a graph's communities from scratch, as a rollback may
+// change any node or edge
+func (l *CommunityListener) recompute(ctx context.Context, graphID string) error {
+	_, err := l.communities.RecomputeGraph(ctx, graphID)
+	return err
+}
@@ -0,0 +1,112 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	appevents "backend/application/events"
+	"backend/application/ports"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// embeddingEventTypes are the events that give a node new text to embed
+var embeddingEventTypes = []string{
+	"NodeCreated",
+	"NodeCreatedWithPendingEdges",
+	"NodeContentUpdated",
+}
+
+// EmbeddingListener embeds nodes in process as they are created or edited.
+// It stands in for the embed-node Lambda when embeddings are computed
+// locally, so semantic search covers new notes without a re-embedding run.
+type EmbeddingListener struct {
+	appevents.BaseEventHandler
+	embeddings domainservices.EmbeddingService
+	nodeRepo   ports.NodeRepository
+	logger     *zap.Logger
+}
+
+// NewEmbeddingListener creates a new embedding listener
+func NewEmbeddingListener(embeddings domainservices.EmbeddingService, nodeRepo ports.NodeRepository, logger *zap.Logger) *EmbeddingListener {
+	return &EmbeddingListener{
+		BaseEventHandler: appevents.NewBaseEventHandler(
+			"EmbeddingListener",
+			40, // before the vector index, so it indexes the new embedding
+			embeddingEventTypes,
+		),
+		embeddings: embeddings,
+		nodeRepo:   nodeRepo,
+		logger:     logger,
+	}
+}
+
+// Subscribe subscribes the listener to node creation and content events
+func (l *EmbeddingListener) Subscribe(registry *appevents.HandlerRegistry) error {
+	if err := registry.Register(embeddingEventTypes, l); err != nil {
+		l.logger.Error("Failed to register embedding listener",
+			zap.Error(err),
+			zap.Strings("eventTypes", embeddingEventTypes))
+		return err
+	}
+	return nil
+}
+
+// Handle processes a domain event. Events arrive as values or pointers
+// depending on the publisher, so both are accepted.
+func (l *EmbeddingListener) Handle(ctx context.Context, event events.DomainEvent) error {
+	switch e := event.(type) {
+	case events.NodeCreated:
+		return l.embed(ctx, e.NodeID)
+	case *events.NodeCreated:
+		return l.embed(ctx, e.NodeID)
+	case *events.NodeCreatedWithPendingEdges:
+		nodeID, err := valueobjects.NewNodeIDFromString(e.NodeID)
+		if err != nil {
+			return err
+		}
+		return l.embed(ctx, nodeID)
+	case events.NodeContentUpdated:
+		return l.embed(ctx, e.NodeID)
+	case *events.NodeContentUpdated:
+		return l.embed(ctx, e.NodeID)
+	}
+	return nil
+}
+
+// embed stores an embedding of the node's current text unless it already
+// has one by the configured model
+func (l *EmbeddingListener) embed(ctx context.Context, nodeID valueobjects.NodeID) error {
+	node, err := l.nodeRepo.GetByID(ctx, nodeID)
+	if err != nil {
+		if pkgerrors.IsNotFound(err) {
+			// Deleted again before the event was handled
+			return nil
+		}
+		return err
+	}
+	if !node.NeedsEmbedding(l.embeddings.Model()) {
+		return nil
+	}
+	text := node.EmbeddingText()
+	if text == "" {
+		return nil
+	}
+
+	embedding, err := l.embeddings.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return fmt.Errorf("failed to embed node %s: %w", nodeID, err)
+	}
+	node.SetEmbedding(embedding)
+	if err := l.nodeRepo.Save(ctx, node); err != nil {
+		return fmt.Errorf("failed to save embedding of node %s: %w", nodeID, err)
+	}
+
+	l.logger.Debug("Embedded node",
+		zap.String("nodeID", nodeID.String()),
+		zap.String("model", embedding.Model()))
+	return nil
+}
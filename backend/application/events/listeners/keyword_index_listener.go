@@ -0,0 +1,49 @@
+package listeners
+
+import (
+	"context"
+
+	appevents "backend/application/events"
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/events"
+	"go.uber.org/zap"
+)
+
+// KeywordIndexListener keeps the keyword index in sync with node and graph events
+type KeywordIndexListener struct {
+	appevents.BaseEventHandler
+	index    *services.KeywordIndexService
+	nodeRepo ports.NodeRepository
+	logger   *zap.Logger
+}
+
+// NewKeywordIndexListener creates a new keyword index listener
+func NewKeywordIndexListener(index *services.KeywordIndexService, nodeRepo ports.NodeRepository, logger *zap.Logger) *KeywordIndexListener {
+	return &KeywordIndexListener{
+		BaseEventHandler: appevents.NewBaseEventHandler(
+			"KeywordIndexListener",
+			50, // after the handlers that record the change
+			searchIndexEventTypes,
+		),
+		index:    index,
+		nodeRepo: nodeRepo,
+		logger:   logger,
+	}
+}
+
+// Subscribe subscribes the listener to node and graph events
+func (l *KeywordIndexListener) Subscribe(registry *appevents.HandlerRegistry) error {
+	if err := registry.Register(searchIndexEventTypes, l); err != nil {
+		l.logger.Error("Failed to register keyword index listener",
+			zap.Error(err),
+			zap.Strings("eventTypes", searchIndexEventTypes))
+		return err
+	}
+	return nil
+}
+
+// Handle processes a domain event
+func (l *KeywordIndexListener) Handle(ctx context.Context, event events.DomainEvent) error {
+	return handleSearchIndexEvent(ctx, l.index, l.nodeRepo, event)
+}
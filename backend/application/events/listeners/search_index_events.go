@@ -0,0 +1,102 @@
+package listeners
+
+import (
+	"context"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+)
+
+// searchIndexEventTypes are the events that change which nodes a user's
+// search indexes should hold
+var searchIndexEventTypes = []string{
+	"NodeCreated",
+	"NodeCreatedWithPendingEdges",
+	"NodeContentUpdated",
+	"NodeRestoredEvent",
+	"NodeDeletedEvent",
+	"NodeTrashedEvent",
+	"NodePurgedEvent",
+	"BulkNodesDeletedEvent",
+	"GraphRolledBackEvent",
+	"GraphUpdatedEvent",
+	"GraphDeletedEvent",
+}
+
+// searchIndex is a per-user index of nodes kept current from events
+type searchIndex interface {
+	IndexNode(ctx context.Context, node *entities.Node)
+	RemoveNodes(ctx context.Context, userID string, nodeIDs []string)
+	SyncGraph(ctx context.Context, userID, graphID string) error
+	RemoveGraph(ctx context.Context, userID, graphID string)
+}
+
+// handleSearchIndexEvent applies a node or graph event to a search index.
+// Events arrive as values or pointers depending on the publisher, so both
+// are accepted.
+func handleSearchIndexEvent(ctx context.Context, index searchIndex, nodeRepo ports.NodeRepository, event events.DomainEvent) error {
+	switch e := event.(type) {
+	case events.NodeCreated:
+		return indexNode(ctx, index, nodeRepo, e.NodeID)
+	case *events.NodeCreated:
+		return indexNode(ctx, index, nodeRepo, e.NodeID)
+	case *events.NodeCreatedWithPendingEdges:
+		nodeID, err := valueobjects.NewNodeIDFromString(e.NodeID)
+		if err != nil {
+			return err
+		}
+		return indexNode(ctx, index, nodeRepo, nodeID)
+	case events.NodeContentUpdated:
+		return indexNode(ctx, index, nodeRepo, e.NodeID)
+	case *events.NodeContentUpdated:
+		return indexNode(ctx, index, nodeRepo, e.NodeID)
+	case events.NodeRestoredEvent:
+		return indexNode(ctx, index, nodeRepo, e.NodeID)
+	case *events.NodeRestoredEvent:
+		return indexNode(ctx, index, nodeRepo, e.NodeID)
+	case events.NodeDeletedEvent:
+		index.RemoveNodes(ctx, e.UserID, []string{e.NodeID.String()})
+	case *events.NodeDeletedEvent:
+		index.RemoveNodes(ctx, e.UserID, []string{e.NodeID.String()})
+	case events.NodeTrashedEvent:
+		index.RemoveNodes(ctx, e.UserID, []string{e.NodeID.String()})
+	case *events.NodeTrashedEvent:
+		index.RemoveNodes(ctx, e.UserID, []string{e.NodeID.String()})
+	case events.NodePurgedEvent:
+		index.RemoveNodes(ctx, e.UserID, []string{e.NodeID.String()})
+	case *events.NodePurgedEvent:
+		index.RemoveNodes(ctx, e.UserID, []string{e.NodeID.String()})
+	case *events.BulkNodesDeletedEvent:
+		index.RemoveNodes(ctx, e.UserID, e.DeletedIDs)
+	case events.GraphRolledBackEvent:
+		return index.SyncGraph(ctx, e.UserID, e.GraphID)
+	case *events.GraphRolledBackEvent:
+		return index.SyncGraph(ctx, e.UserID, e.GraphID)
+	case events.GraphUpdatedEvent:
+		return index.SyncGraph(ctx, e.UserID, e.GraphID)
+	case *events.GraphUpdatedEvent:
+		return index.SyncGraph(ctx, e.UserID, e.GraphID)
+	case events.GraphDeletedEvent:
+		index.RemoveGraph(ctx, e.UserID, e.GraphID)
+	case *events.GraphDeletedEvent:
+		index.RemoveGraph(ctx, e.UserID, e.GraphID)
+	}
+	return nil
+}
+
+// indexNode reloads a node so the index sees its stored state
+func indexNode(ctx context.Context, index searchIndex, nodeRepo ports.NodeRepository, nodeID valueobjects.NodeID) error {
+	node, err := nodeRepo.GetByID(ctx, nodeID)
+	if err != nil {
+		if pkgerrors.IsNotFound(err) {
+			// Deleted again before the event was handled
+			return nil
+		}
+		return err
+	}
+	index.IndexNode(ctx, node)
+	return nil
+}
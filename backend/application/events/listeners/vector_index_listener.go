@@ -0,0 +1,49 @@
+package listeners
+
+import (
+	"context"
+
+	appevents "backend/application/events"
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/events"
+	"go.uber.org/zap"
+)
+
+// VectorIndexListener keeps the vector index in sync with node and graph events
+type VectorIndexListener struct {
+	appevents.BaseEventHandler
+	index    *services.VectorIndexService
+	nodeRepo ports.NodeRepository
+	logger   *zap.Logger
+}
+
+// NewVectorIndexListener creates a new vector index listener
+func NewVectorIndexListener(index *services.VectorIndexService, nodeRepo ports.NodeRepository, logger *zap.Logger) *VectorIndexListener {
+	return &VectorIndexListener{
+		BaseEventHandler: appevents.NewBaseEventHandler(
+			"VectorIndexListener",
+			50, // after the handlers that record the change
+			searchIndexEventTypes,
+		),
+		index:    index,
+		nodeRepo: nodeRepo,
+		logger:   logger,
+	}
+}
+
+// Subscribe subscribes the listener to node and graph events
+func (l *VectorIndexListener) Subscribe(registry *appevents.HandlerRegistry) error {
+	if err := registry.Register(searchIndexEventTypes, l); err != nil {
+		l.logger.Error("Failed to register vector index listener",
+			zap.Error(err),
+			zap.Strings("eventTypes", searchIndexEventTypes))
+		return err
+	}
+	return nil
+}
+
+// Handle processes a domain event
+func (l *VectorIndexListener) Handle(ctx context.Context, event events.DomainEvent) error {
+	return handleSearchIndexEvent(ctx, l.index, l.nodeRepo, event)
+}
@@ -175,10 +175,13 @@ func (l *WebSocketListener) GetEventTypes() []string {
 		"NodeCreated",
 		"NodeUpdated",
 		"NodeDeleted",
+		"NodeTrashed",
+		"NodeRestored",
 		"EdgeCreated",
 		"EdgeDeleted",
 		"GraphUpdated",
 		"GraphDeleted",
+		"GraphRolledBack",
 	}
 }
 
@@ -0,0 +1,34 @@
+package ports
+
+import "context"
+
+// Chat message roles
+const (
+	ChatRoleSystem    = "system"
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+)
+
+// ChatMessage is one message of a chat completion conversation
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest asks a language model to continue a conversation
+type ChatRequest struct {
+	Messages    []ChatMessage
+	MaxTokens   int     // Upper bound of the answer length; zero leaves it to the model
+	Temperature float64 // Sampling temperature; zero is the most deterministic
+}
+
+// ChatClient generates answers with a language model
+type ChatClient interface {
+	// StreamChat generates the answer to req and passes it to onDelta piece by
+	// piece as it is generated. An error returned by onDelta stops the
+	// generation and is returned.
+	StreamChat(ctx context.Context, req ChatRequest, onDelta func(delta string) error) error
+
+	// Model returns the ID of the model answers are generated with
+	Model() string
+}
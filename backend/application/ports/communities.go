@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"backend/domain/core/entities"
+)
+
+// CommunityRepository persists the detected communities of each graph as one
+// partition, replaced as a whole on every update.
+type CommunityRepository interface {
+	// GetByGraphID returns the partition of a graph, or a not found error
+	// when its communities were never detected
+	GetByGraphID(ctx context.Context, graphID string) (*entities.CommunityPartition, error)
+
+	// Save replaces the partition of a graph
+	Save(ctx context.Context, partition *entities.CommunityPartition) error
+
+	// Delete removes the partition of a graph; deleting a missing partition
+	// is not an error
+	Delete(ctx context.Context, graphID string) error
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+
+	"backend/domain/versioning"
+)
+
+// GraphVersionStore persists named snapshots of graphs. Versions of a graph
+// are numbered from 1 and never reused.
+type GraphVersionStore interface {
+	// Save stores a new version; saving a version number that already exists
+	// for the graph fails with a conflict error
+	Save(ctx context.Context, snapshot *versioning.GraphSnapshot) error
+
+	// Get returns a stored version with its full graph state, or a not found
+	// error
+	Get(ctx context.Context, graphID string, version int) (*versioning.GraphSnapshot, error)
+
+	// Latest returns the newest version of a graph, or a not found error when
+	// the graph has none
+	Latest(ctx context.Context, graphID string) (*versioning.GraphSnapshot, error)
+
+	// List returns the version records of a graph without their state,
+	// newest first
+	List(ctx context.Context, graphID string) ([]*versioning.GraphVersion, error)
+
+	// Delete removes a version; deleting a missing version is not an error
+	Delete(ctx context.Context, graphID string, version int) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import "context"
+
+// KeywordIndexStore persists the serialized inverted index of each user's
+// node text, so keyword search does not have to re-tokenize every node after
+// a cold start
+type KeywordIndexStore interface {
+	// Load returns a user's serialized index, or a not found error when none
+	// has been saved
+	Load(ctx context.Context, userID string) ([]byte, error)
+
+	// Save stores a user's serialized index, replacing the previous one
+	Save(ctx context.Context, userID string, data []byte) error
+}
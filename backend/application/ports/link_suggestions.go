@@ -0,0 +1,70 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// LinkSuggestionStatus is where a link suggestion stands
+type LinkSuggestionStatus string
+
+const (
+	LinkSuggestionPending  LinkSuggestionStatus = "pending"
+	LinkSuggestionAccepted LinkSuggestionStatus = "accepted"
+	LinkSuggestionRejected LinkSuggestionStatus = "rejected"
+)
+
+// LinkSuggestion is a predicted edge offered to a user, together with the
+// signals it was scored on. Resolved suggestions are kept so the same pair
+// is not offered again.
+type LinkSuggestion struct {
+	ID               string               `json:"id"`
+	UserID           string               `json:"user_id"`
+	GraphID          string               `json:"graph_id"`
+	SourceID         string               `json:"source_id"`
+	TargetID         string               `json:"target_id"`
+	SourceTitle      string               `json:"source_title"`
+	TargetTitle      string               `json:"target_title"`
+	Score            float64              `json:"score"`
+	CommonNeighbours int                  `json:"common_neighbours"`
+	AdamicAdar       float64              `json:"adamic_adar"`
+	SameCommunity    bool                 `json:"same_community"`
+	Similarity       float64              `json:"similarity"`
+	Status           LinkSuggestionStatus `json:"status"`
+	EdgeID           string               `json:"edge_id,omitempty"` // The edge created on acceptance
+	CreatedAt        time.Time            `json:"created_at"`
+	ResolvedAt       *time.Time           `json:"resolved_at,omitempty"`
+}
+
+// LinkFeedback is what a user's accepted and rejected suggestions taught:
+// the score a suggestion must reach to be offered to them
+type LinkFeedback struct {
+	UserID    string    `json:"user_id"`
+	Threshold float64   `json:"threshold"`
+	Accepted  int       `json:"accepted"`
+	Rejected  int       `json:"rejected"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LinkSuggestionStore keeps link suggestions and the feedback on them, per user
+type LinkSuggestionStore interface {
+	// Put stores a suggestion, replacing an earlier one with the same ID
+	Put(ctx context.Context, suggestion *LinkSuggestion) error
+
+	// Get returns a user's suggestion, or a not found error
+	Get(ctx context.Context, userID, suggestionID string) (*LinkSuggestion, error)
+
+	// ListByUser returns all of a user's suggestions, whatever their status,
+	// highest score first
+	ListByUser(ctx context.Context, userID string) ([]*LinkSuggestion, error)
+
+	// Delete removes a suggestion; deleting a missing one is not an error
+	Delete(ctx context.Context, userID, suggestionID string) error
+
+	// GetFeedback returns a user's feedback, or a not found error if they
+	// never resolved a suggestion
+	GetFeedback(ctx context.Context, userID string) (*LinkFeedback, error)
+
+	// PutFeedback stores a user's feedback
+	PutFeedback(ctx context.Context, feedback *LinkFeedback) error
+}
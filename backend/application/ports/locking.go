@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Lock is a held lease on a named resource
+type Lock interface {
+	// Release gives up the lease; releasing an expired lock is not an error
+	Release(ctx context.Context) error
+}
+
+// DistributedLock grants exclusive leases on named resources across processes
+type DistributedLock interface {
+	// TryAcquire retries until the lease is granted or timeout elapses.
+	// The lease expires on its own after lockDuration if never released.
+	TryAcquire(ctx context.Context, resource, owner string, lockDuration, timeout time.Duration) (Lock, error)
+}
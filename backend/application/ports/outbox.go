@@ -0,0 +1,81 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// OutboxStatus is the delivery state of a stored event
+type OutboxStatus string
+
+const (
+	OutboxStatusPending      OutboxStatus = "pending"       // Waiting for its next delivery attempt
+	OutboxStatusPublished    OutboxStatus = "published"     // Delivered to the event bus
+	OutboxStatusDeadLettered OutboxStatus = "dead_lettered" // Gave up after the maximum attempts
+)
+
+// OutboxEntry is a stored event together with its delivery bookkeeping
+type OutboxEntry struct {
+	ID          string          `json:"id"` // Opaque, stable within a backend
+	EventType   string          `json:"event_type"`
+	AggregateID string          `json:"aggregate_id"`
+	UserID      string          `json:"user_id,omitempty"`
+	Version     int             `json:"version"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+
+	Status         OutboxStatus `json:"status"`
+	Attempts       int          `json:"attempts"`
+	LastError      string       `json:"last_error,omitempty"`
+	LastAttemptAt  *time.Time   `json:"last_attempt_at,omitempty"`
+	NextAttemptAt  *time.Time   `json:"next_attempt_at,omitempty"`
+	DeadLetteredAt *time.Time   `json:"dead_lettered_at,omitempty"`
+}
+
+// OutboxStats describes the undelivered part of the outbox
+type OutboxStats struct {
+	Pending         int        `json:"pending"`
+	DeadLettered    int        `json:"dead_lettered"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+}
+
+// Lag is how long the oldest pending event has been waiting for delivery
+func (s *OutboxStats) Lag(now time.Time) time.Duration {
+	if s.OldestPendingAt == nil || now.Before(*s.OldestPendingAt) {
+		return 0
+	}
+	return now.Sub(*s.OldestPendingAt)
+}
+
+// Outbox is the delivery queue of stored events. Events enter it as pending
+// when they are saved; the outbox processor publishes them, retrying with
+// backoff until they are published or dead-lettered.
+type Outbox interface {
+	// DueEntries returns up to limit pending entries whose next attempt is
+	// due at now, earliest due first
+	DueEntries(ctx context.Context, now time.Time, limit int) ([]*OutboxEntry, error)
+
+	// MarkPublished records a successful delivery and removes the entry from
+	// the pending queue
+	MarkPublished(ctx context.Context, id string) error
+
+	// ScheduleRetry records a failed attempt and defers the entry until nextAttemptAt
+	ScheduleRetry(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error
+
+	// DeadLetter records the final failed attempt and parks the entry
+	DeadLetter(ctx context.Context, id string, attempts int, lastError string) error
+
+	// GetEntry retrieves an entry by ID
+	GetEntry(ctx context.Context, id string) (*OutboxEntry, error)
+
+	// ListDeadLettered returns up to limit dead-lettered entries, most recent first
+	ListDeadLettered(ctx context.Context, limit int) ([]*OutboxEntry, error)
+
+	// Redrive returns a dead-lettered entry to the pending queue with a fresh
+	// attempt budget, due immediately
+	Redrive(ctx context.Context, id string) (*OutboxEntry, error)
+
+	// Stats counts pending and dead-lettered entries
+	Stats(ctx context.Context) (*OutboxStats, error)
+}
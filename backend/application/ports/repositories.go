@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"backend/domain/core/aggregates"
 	"backend/domain/core/entities"
@@ -173,6 +174,40 @@ type EventStore interface {
 	DeleteEventsBatch(ctx context.Context, aggregateIDs []string) error
 }
 
+// AggregateSnapshot is the serialized state of an aggregate at a version
+type AggregateSnapshot struct {
+	AggregateID   string
+	AggregateType string // "node" or "graph"
+	Version       int
+	State         []byte // JSON encoded aggregate snapshot
+	Timestamp     time.Time
+}
+
+// SnapshotStore keeps the latest snapshot of each aggregate so event-sourced
+// loads only replay the events recorded after it
+type SnapshotStore interface {
+	// GetSnapshot returns the latest snapshot, or nil when there is none
+	GetSnapshot(ctx context.Context, aggregateID string) (*AggregateSnapshot, error)
+
+	// SaveSnapshot replaces the latest snapshot of an aggregate
+	SaveSnapshot(ctx context.Context, snapshot *AggregateSnapshot) error
+}
+
+// RecordedEvent is a domain event together with its position in the event log
+type RecordedEvent struct {
+	Position int64  // Ordering key across all aggregates
+	EventID  string // Breaks ties between events at the same position
+	Event    events.DomainEvent
+}
+
+// EventStream reads the event log across all aggregates in append order, which
+// is what projection replays consume
+type EventStream interface {
+	// ReadEvents returns up to limit events at or after the given position,
+	// ordered by position and then event ID
+	ReadEvents(ctx context.Context, fromPosition int64, limit int) ([]RecordedEvent, error)
+}
+
 // UnitOfWork defines a transaction boundary for aggregate operations
 type UnitOfWork interface {
 	// Begin starts a new transaction
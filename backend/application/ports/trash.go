@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+)
+
+// TrashedNode is a deleted node waiting in the trash bin, together with the
+// edges it had when it was deleted
+type TrashedNode struct {
+	NodeID    string              `json:"node_id"`
+	UserID    string              `json:"user_id"`
+	GraphID   string              `json:"graph_id"`
+	Title     string              `json:"title"`
+	Node      entities.NodeBackup `json:"node"`
+	Edges     []*aggregates.Edge  `json:"edges"`
+	TrashedAt time.Time           `json:"trashed_at"`
+	PurgeAt   *time.Time          `json:"purge_at,omitempty"` // nil keeps the node until it is purged by hand
+}
+
+// IsExpired reports whether the node is due to be purged
+func (t *TrashedNode) IsExpired(now time.Time) bool {
+	return t.PurgeAt != nil && !t.PurgeAt.After(now)
+}
+
+// TrashStore keeps deleted nodes so they can be restored until they are purged.
+// Trashed nodes are no longer in the node repository, so queries never see them.
+type TrashStore interface {
+	// Put stores a trashed node, replacing an earlier entry for the same node
+	Put(ctx context.Context, trashed *TrashedNode) error
+
+	// Get returns a user's trashed node, or a not found error
+	Get(ctx context.Context, userID, nodeID string) (*TrashedNode, error)
+
+	// ListByUser returns a user's trashed nodes, most recently trashed first
+	ListByUser(ctx context.Context, userID string) ([]*TrashedNode, error)
+
+	// ListExpired returns up to limit trashed nodes of any user that are due
+	// to be purged at now
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*TrashedNode, error)
+
+	// Delete removes a trashed node; deleting a missing entry is not an error
+	Delete(ctx context.Context, userID, nodeID string) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import "context"
+
+// VectorIndexStore persists the serialized nearest-neighbour index of each
+// user's node embeddings, so a cold start can load it instead of rebuilding
+// it from every node
+type VectorIndexStore interface {
+	// Load returns a user's serialized index, or a not found error when none
+	// has been saved
+	Load(ctx context.Context, userID string) ([]byte, error)
+
+	// Save stores a user's serialized index, replacing the previous one
+	Save(ctx context.Context, userID string, data []byte) error
+}
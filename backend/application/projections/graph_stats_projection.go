@@ -2,6 +2,8 @@ package projections
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
@@ -20,9 +22,23 @@ type GraphStatistics struct {
 	LastUpdated        time.Time `json:"last_updated"`
 }
 
+// graphStatsEventTypes are the events GraphStatsProjection subscribes to.
+// The handler registry dispatches by the event's type name, which the
+// replayer also accepts, so these are type names rather than the values of
+// GetEventType.
+var graphStatsEventTypes = []string{
+	"NodeCreatedWithPendingEdges",
+	"NodeDeletedEvent",
+	"BulkNodesDeletedEvent",
+	"NodeTrashedEvent",
+	"NodeRestoredEvent",
+	"GraphRolledBackEvent",
+}
+
 // GraphStatsProjection maintains cached graph statistics
 // This projection listens to node and edge events to maintain up-to-date statistics
-// without expensive queries
+// without expensive queries. Its read model is captured in replay checkpoints,
+// so it can be rebuilt from the event store rather than living only in the cache.
 type GraphStatsProjection struct {
 	appevents.BaseEventHandler
 	cache  ports.Cache
@@ -37,11 +53,7 @@ func NewGraphStatsProjection(cache ports.Cache, logger *zap.Logger) *GraphStatsP
 		BaseEventHandler: appevents.NewBaseEventHandler(
 			"GraphStatsProjection",
 			5, // high priority
-			[]string{
-				"node.created.with.pending.edges",
-				"NodeDeleted", 
-				"BulkNodesDeleted",
-			},
+			graphStatsEventTypes,
 		),
 		cache:  cache,
 		logger: logger,
@@ -49,6 +61,19 @@ func NewGraphStatsProjection(cache ports.Cache, logger *zap.Logger) *GraphStatsP
 	}
 }
 
+// Compile-time interface check
+var _ StatefulProjection = (*GraphStatsProjection)(nil)
+
+// GetProjectionName returns the projection's name
+func (p *GraphStatsProjection) GetProjectionName() string {
+	return p.Name()
+}
+
+// GetEventTypes returns the event types this projection handles
+func (p *GraphStatsProjection) GetEventTypes() []string {
+	return append([]string{}, graphStatsEventTypes...)
+}
+
 // Handle processes domain events and updates statistics
 func (p *GraphStatsProjection) Handle(ctx context.Context, event events.DomainEvent) error {
 	switch e := event.(type) {
@@ -56,8 +81,17 @@ func (p *GraphStatsProjection) Handle(ctx context.Context, event events.DomainEv
 		return p.handleNodeCreated(ctx, e)
 	case *events.NodeDeletedEvent:
 		return p.handleNodeDeleted(ctx, e)
+	case events.NodeDeletedEvent:
+		return p.handleNodeDeleted(ctx, &e)
 	case *events.BulkNodesDeletedEvent:
 		return p.handleBulkNodesDeleted(ctx, e)
+	case events.NodeTrashedEvent:
+		// The node leaves its graph with its edges; purging it later changes nothing
+		return p.adjustCounts(ctx, e.GraphID, -1, -e.EdgeCount)
+	case events.NodeRestoredEvent:
+		return p.adjustCounts(ctx, e.GraphID, 1, e.RestoredEdges)
+	case events.GraphRolledBackEvent:
+		return p.setCounts(ctx, e.GraphID, e.NodeCount, e.EdgeCount)
 	default:
 		// Ignore unknown events
 		return nil
@@ -153,6 +187,44 @@ func (p *GraphStatsProjection) handleBulkNodesDeleted(ctx context.Context, event
 	return nil
 }
 
+// adjustCounts applies node and edge count changes to a graph's statistics
+func (p *GraphStatsProjection) adjustCounts(ctx context.Context, graphID string, nodeDelta, edgeDelta int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.getOrCreateStats(graphID)
+	p.storeCounts(ctx, stats, stats.NodeCount+nodeDelta, stats.EdgeCount+edgeDelta)
+	return nil
+}
+
+// setCounts replaces the counts of a graph, for events that report them
+func (p *GraphStatsProjection) setCounts(ctx context.Context, graphID string, nodeCount, edgeCount int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.storeCounts(ctx, p.getOrCreateStats(graphID), nodeCount, edgeCount)
+	return nil
+}
+
+// storeCounts updates stats and the cache; callers hold p.mu
+func (p *GraphStatsProjection) storeCounts(ctx context.Context, stats *GraphStatistics, nodeCount, edgeCount int) {
+	stats.NodeCount = max(nodeCount, 0)
+	stats.EdgeCount = max(edgeCount, 0)
+	stats.LastUpdated = time.Now()
+
+	if stats.NodeCount > 0 {
+		stats.AverageConnections = float64(stats.EdgeCount*2) / float64(stats.NodeCount)
+	} else {
+		stats.AverageConnections = 0
+	}
+
+	if err := p.cache.Set(ctx, p.getCacheKey(stats.GraphID), stats, 3600); err != nil {
+		p.logger.Warn("Failed to update cache for graph stats",
+			zap.String("graphID", stats.GraphID),
+			zap.Error(err))
+	}
+}
+
 // GetStats retrieves cached statistics for a graph
 func (p *GraphStatsProjection) GetStats(ctx context.Context, graphID string) (*GraphStatistics, error) {
 	// Try cache first
@@ -185,17 +257,49 @@ func (p *GraphStatsProjection) Reset(ctx context.Context) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.stats = make(map[string]*GraphStatistics)
-	
-	// Clear cache
-	if err := p.cache.Clear(ctx); err != nil {
-		p.logger.Warn("Failed to clear cache", zap.Error(err))
+	// Only drop this projection's entries; other read models share the cache
+	for graphID := range p.stats {
+		if err := p.cache.Delete(ctx, p.getCacheKey(graphID)); err != nil {
+			p.logger.Warn("Failed to clear cached graph stats",
+				zap.String("graphID", graphID),
+				zap.Error(err))
+		}
 	}
+	p.stats = make(map[string]*GraphStatistics)
 
 	p.logger.Info("Graph statistics projection reset")
 	return nil
 }
 
+// SnapshotState captures the statistics of every graph
+func (p *GraphStatsProjection) SnapshotState(ctx context.Context) (json.RawMessage, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return json.Marshal(p.stats)
+}
+
+// RestoreState replaces the statistics with captured ones and refreshes the cache
+func (p *GraphStatsProjection) RestoreState(ctx context.Context, state json.RawMessage) error {
+	stats := make(map[string]*GraphStatistics)
+	if err := json.Unmarshal(state, &stats); err != nil {
+		return fmt.Errorf("invalid graph stats state: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stats = stats
+	for graphID, graphStats := range stats {
+		if err := p.cache.Set(ctx, p.getCacheKey(graphID), graphStats, 3600); err != nil {
+			p.logger.Warn("Failed to update cache for graph stats",
+				zap.String("graphID", graphID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
 // getOrCreateStats gets or creates statistics for a graph
 func (p *GraphStatsProjection) getOrCreateStats(graphID string) *GraphStatistics {
 	if stats, exists := p.stats[graphID]; exists {
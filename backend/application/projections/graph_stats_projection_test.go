@@ -0,0 +1,79 @@
+package projections_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appevents "backend/application/events"
+	"backend/application/projections"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	"backend/infrastructure/di"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newDispatchedStats registers a stats projection the way WireEventHandlers
+// does, so events reach it only if its event types match the registry's keys
+func newDispatchedStats(t *testing.T) (*appevents.HandlerRegistry, *projections.GraphStatsProjection) {
+	t.Helper()
+	logger := zap.NewNop()
+	stats := projections.NewGraphStatsProjection(di.NewInMemoryCache(), logger)
+	registry := appevents.NewHandlerRegistry(logger)
+	require.NoError(t, registry.Register(stats.GetEventTypes(), stats))
+	return registry, stats
+}
+
+func graphStats(t *testing.T, stats *projections.GraphStatsProjection) *projections.GraphStatistics {
+	t.Helper()
+	result, err := stats.GetStats(context.Background(), "graph-1")
+	require.NoError(t, err)
+	return result
+}
+
+func TestGraphStatsProjection_DispatchedNodeEvents(t *testing.T) {
+	ctx := context.Background()
+	registry, stats := newDispatchedStats(t)
+	nodeIDs := []valueobjects.NodeID{valueobjects.NewNodeID(), valueobjects.NewNodeID(), valueobjects.NewNodeID()}
+	for _, nodeID := range nodeIDs {
+		require.NoError(t, registry.Dispatch(ctx, events.NewNodeCreatedWithPendingEdges(
+			nodeID, "graph-1", "user-1", "title", nil, nil, 0, nil)))
+	}
+	assert.Equal(t, 3, graphStats(t, stats).NodeCount)
+
+	now := time.Now()
+	require.NoError(t, registry.Dispatch(ctx, events.NewNodeTrashedEvent(nodeIDs[0], "user-1", "graph-1", "title", 0, nil, now)))
+	assert.Equal(t, 2, graphStats(t, stats).NodeCount)
+
+	require.NoError(t, registry.Dispatch(ctx, events.NewNodeRestoredEvent(nodeIDs[0], "user-1", "graph-1", "title", 2, now)))
+	restored := graphStats(t, stats)
+	assert.Equal(t, 3, restored.NodeCount)
+	assert.Equal(t, 2, restored.EdgeCount)
+
+	require.NoError(t, registry.Dispatch(ctx, events.NewNodeTrashedEvent(nodeIDs[1], "user-1", "graph-1", "title", 2, nil, now)))
+	trashed := graphStats(t, stats)
+	assert.Equal(t, 2, trashed.NodeCount)
+	assert.Equal(t, 0, trashed.EdgeCount)
+
+	require.NoError(t, registry.Dispatch(ctx, events.NewNodeDeletedEvent(nodeIDs[2], "user-1", "graph-1", "", nil, nil, now)))
+	assert.Equal(t, 1, graphStats(t, stats).NodeCount)
+}
+
+func TestGraphStatsProjection_DispatchedRollback(t *testing.T) {
+	ctx := context.Background()
+	registry, stats := newDispatchedStats(t)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, registry.Dispatch(ctx, events.NewNodeCreatedWithPendingEdges(
+			valueobjects.NewNodeID(), "graph-1", "user-1", "title", nil, nil, 0, nil)))
+	}
+
+	// A rollback reports the counts of the restored version
+	require.NoError(t, registry.Dispatch(ctx, events.NewGraphRolledBackEvent("graph-1", "user-1", 2, 2, 1, time.Now())))
+	rolledBack := graphStats(t, stats)
+	assert.Equal(t, 2, rolledBack.NodeCount)
+	assert.Equal(t, 1, rolledBack.EdgeCount)
+	assert.Equal(t, 1.0, rolledBack.AverageConnections)
+}
@@ -2,6 +2,7 @@ package projections
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -94,24 +95,53 @@ func GetEventType(event events.DomainEvent) string {
 // ProjectionPosition tracks the position of a projection in the event stream
 // This is crucial for event replay and ensuring exactly-once processing
 type ProjectionPosition struct {
-	ProjectionName string `json:"projection_name"`
-	Position       int64  `json:"position"`
-	LastEventID    string `json:"last_event_id"`
-	UpdatedAt      int64  `json:"updated_at"`
+	ProjectionName string          `json:"projection_name"`
+	Position       int64           `json:"position"`
+	LastEventID    string          `json:"last_event_id"`
+	UpdatedAt      int64           `json:"updated_at"`
+	State          json.RawMessage `json:"state,omitempty"` // Read model of a StatefulProjection as of Position
+}
+
+// IsAfter reports whether p is further along the event log than other
+func (p *ProjectionPosition) IsAfter(other *ProjectionPosition) bool {
+	if other == nil {
+		return true
+	}
+	if p.Position != other.Position {
+		return p.Position > other.Position
+	}
+	return p.LastEventID > other.LastEventID
 }
 
 // CheckpointStore manages projection positions for event replay
 type CheckpointStore interface {
-	// SavePosition saves the current position of a projection
+	// SavePosition saves the current position of a projection. It fails with
+	// a conflict error if the stored position is already further along, which
+	// fences off a concurrent replay of the same projection.
 	SavePosition(ctx context.Context, position *ProjectionPosition) error
 	
-	// GetPosition retrieves the last saved position for a projection
+	// GetPosition retrieves the last saved position for a projection, or nil
+	// if the projection has never been checkpointed
 	GetPosition(ctx context.Context, projectionName string) (*ProjectionPosition, error)
 	
 	// DeletePosition removes the position record for a projection (used during reset)
 	DeletePosition(ctx context.Context, projectionName string) error
 }
 
+// StatefulProjection is a projection whose read model can be captured and
+// restored. Replays store the captured state in the same checkpoint record as
+// the position, so the read model and the position always move together and
+// every event is applied exactly once, even across crashes.
+type StatefulProjection interface {
+	ProjectionHandler
+
+	// SnapshotState captures the current read model
+	SnapshotState(ctx context.Context) (json.RawMessage, error)
+
+	// RestoreState replaces the read model with a captured one
+	RestoreState(ctx context.Context, state json.RawMessage) error
+}
+
 // ProjectionStats provides metrics about projection processing
 type ProjectionStats struct {
 	ProjectionName   string
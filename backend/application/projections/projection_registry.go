@@ -128,21 +128,8 @@ func (r *ProjectionRegistry) processEvent(ctx context.Context, handler Projectio
 		}
 	}
 	
-	// Save checkpoint if checkpoint store is available
-	if r.checkpointStore != nil {
-		position := &ProjectionPosition{
-			ProjectionName: projectionName,
-			LastEventID:    event.GetAggregateID(),
-			UpdatedAt:      time.Now().Unix(),
-		}
-		
-		if err := r.checkpointStore.SavePosition(ctx, position); err != nil {
-			r.logger.Warn("Failed to save projection checkpoint",
-				zap.String("projection", projectionName),
-				zap.String("eventID", event.GetAggregateID()),
-				zap.Error(err))
-		}
-	}
+	// Checkpoints are written by the ProjectionReplayer, which knows each
+	// event's position in the log; live dispatch does not
 	
 	r.logger.Debug("Projection processed event",
 		zap.String("projection", projectionName),
@@ -0,0 +1,242 @@
+package projections
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DefaultReplayBatchSize is the number of events read and checkpointed at a time
+const DefaultReplayBatchSize = 500
+
+// ReplayOptions controls a projection replay
+type ReplayOptions struct {
+	// FromPosition skips events before this position, e.g. to backfill only
+	// recent history. A checkpoint further along takes precedence.
+	FromPosition int64
+
+	// Reset clears the projection and its checkpoint first, rebuilding the
+	// read model from scratch
+	Reset bool
+
+	// BatchSize is the number of events handled between checkpoints
+	BatchSize int
+}
+
+// ReplayResult summarizes a replay
+type ReplayResult struct {
+	ProjectionName string        `json:"projection_name"`
+	StartPosition  int64         `json:"start_position"`
+	FinalPosition  int64         `json:"final_position"`
+	EventsRead     int64         `json:"events_read"`
+	EventsApplied  int64         `json:"events_applied"`
+	Checkpoints    int           `json:"checkpoints"`
+	Duration       time.Duration `json:"duration"`
+}
+
+// ProjectionReplayer rebuilds projections from the event log. It streams
+// events in log order into one projection and checkpoints after every batch,
+// so an interrupted replay resumes where it stopped.
+//
+// For a StatefulProjection the read model is stored in the checkpoint
+// together with the position and restored before resuming, which gives
+// exactly-once application. Other projections may see the events of the
+// last unfinished batch again and must handle them idempotently.
+type ProjectionReplayer struct {
+	registry    *ProjectionRegistry
+	stream      ports.EventStream
+	checkpoints CheckpointStore
+	logger      *zap.Logger
+}
+
+// NewProjectionReplayer creates a new projection replayer
+func NewProjectionReplayer(
+	registry *ProjectionRegistry,
+	stream ports.EventStream,
+	checkpoints CheckpointStore,
+	logger *zap.Logger,
+) *ProjectionReplayer {
+	return &ProjectionReplayer{
+		registry:    registry,
+		stream:      stream,
+		checkpoints: checkpoints,
+		logger:      logger,
+	}
+}
+
+// Replay streams the event log into the named projection
+func (r *ProjectionReplayer) Replay(ctx context.Context, projectionName string, opts ReplayOptions) (*ReplayResult, error) {
+	projection, exists := r.registry.GetProjection(projectionName)
+	if !exists {
+		return nil, pkgerrors.NewNotFoundError(fmt.Sprintf("projection '%s'", projectionName))
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultReplayBatchSize
+	}
+
+	started := time.Now()
+	if opts.Reset {
+		if err := r.registry.ResetProjection(ctx, projectionName); err != nil {
+			return nil, err
+		}
+		if err := r.checkpoints.DeletePosition(ctx, projectionName); err != nil {
+			return nil, fmt.Errorf("failed to clear checkpoint for '%s': %w", projectionName, err)
+		}
+	}
+
+	checkpoint, err := r.resume(ctx, projection)
+	if err != nil {
+		return nil, err
+	}
+
+	from := opts.FromPosition
+	if checkpoint != nil && checkpoint.Position > from {
+		from = checkpoint.Position
+	}
+	result := &ReplayResult{
+		ProjectionName: projectionName,
+		StartPosition:  from,
+		FinalPosition:  from,
+	}
+
+	handled := handledTypes(projection)
+	last := checkpoint
+	limit := opts.BatchSize
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		batch, err := r.stream.ReadEvents(ctx, from, limit)
+		if err != nil {
+			return result, fmt.Errorf("failed to read events: %w", err)
+		}
+
+		applied := false
+		for _, recorded := range batch {
+			position := &ProjectionPosition{
+				ProjectionName: projectionName,
+				Position:       recorded.Position,
+				LastEventID:    recorded.EventID,
+			}
+			// Events at the checkpoint position are read again; skip those
+			// already applied
+			if !position.IsAfter(last) {
+				continue
+			}
+
+			result.EventsRead++
+			if eventType := recorded.Event.GetEventType(); handled[eventType] || handled[GetEventType(recorded.Event)] {
+				if err := r.registry.processEvent(ctx, projection, recorded.Event, eventType); err != nil {
+					return result, err
+				}
+				result.EventsApplied++
+			}
+			last = position
+			applied = true
+		}
+
+		if applied {
+			if err := r.checkpoint(ctx, projection, last); err != nil {
+				return result, err
+			}
+			result.Checkpoints++
+			result.FinalPosition = last.Position
+		}
+
+		if len(batch) < limit {
+			break
+		}
+		if applied {
+			// Reading resumes at the last position, which may hold further
+			// events; widen the read by those already seen
+			from = last.Position
+			limit = opts.BatchSize + countAt(batch, from)
+		} else {
+			// The whole batch had been applied before
+			limit += opts.BatchSize
+		}
+	}
+
+	result.Duration = time.Since(started)
+	r.logger.Info("Replayed projection",
+		zap.String("projection", projectionName),
+		zap.Int64("startPosition", result.StartPosition),
+		zap.Int64("finalPosition", result.FinalPosition),
+		zap.Int64("eventsRead", result.EventsRead),
+		zap.Int64("eventsApplied", result.EventsApplied),
+		zap.Duration("duration", result.Duration))
+
+	return result, nil
+}
+
+// resume loads the projection's checkpoint and, for stateful projections,
+// restores the read model it captured
+func (r *ProjectionReplayer) resume(ctx context.Context, projection ProjectionHandler) (*ProjectionPosition, error) {
+	name := projection.GetProjectionName()
+	checkpoint, err := r.checkpoints.GetPosition(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint for '%s': %w", name, err)
+	}
+	if checkpoint == nil {
+		return nil, nil
+	}
+
+	if stateful, ok := projection.(StatefulProjection); ok && len(checkpoint.State) > 0 {
+		if err := stateful.RestoreState(ctx, checkpoint.State); err != nil {
+			return nil, fmt.Errorf("failed to restore state of '%s': %w", name, err)
+		}
+	}
+
+	r.logger.Info("Resuming projection from checkpoint",
+		zap.String("projection", name),
+		zap.Int64("position", checkpoint.Position),
+		zap.String("lastEventID", checkpoint.LastEventID))
+	return checkpoint, nil
+}
+
+// checkpoint records the position, with the read model of stateful projections
+func (r *ProjectionReplayer) checkpoint(ctx context.Context, projection ProjectionHandler, position *ProjectionPosition) error {
+	record := *position
+	record.UpdatedAt = time.Now().Unix()
+
+	if stateful, ok := projection.(StatefulProjection); ok {
+		state, err := stateful.SnapshotState(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to capture state of '%s': %w", record.ProjectionName, err)
+		}
+		record.State = state
+	}
+
+	if err := r.checkpoints.SavePosition(ctx, &record); err != nil {
+		if pkgerrors.IsConflict(err) {
+			return fmt.Errorf("projection '%s' was advanced by another replay: %w", record.ProjectionName, err)
+		}
+		return fmt.Errorf("failed to save checkpoint for '%s': %w", record.ProjectionName, err)
+	}
+	return nil
+}
+
+// handledTypes returns the event types a projection subscribes to
+func handledTypes(projection ProjectionHandler) map[string]bool {
+	types := make(map[string]bool)
+	for _, eventType := range projection.GetEventTypes() {
+		types[eventType] = true
+	}
+	return types
+}
+
+// countAt returns how many of the recorded events sit at the given position
+func countAt(batch []ports.RecordedEvent, position int64) int {
+	count := 0
+	for _, recorded := range batch {
+		if recorded.Position == position {
+			count++
+		}
+	}
+	return count
+}
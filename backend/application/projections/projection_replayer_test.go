@@ -0,0 +1,178 @@
+package projections_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/application/projections"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	"backend/infrastructure/di"
+	"backend/infrastructure/persistence/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type replayFixture struct {
+	events      *memory.InMemoryEventStore
+	checkpoints *memory.InMemoryCheckpointStore
+	replayer    *projections.ProjectionReplayer
+}
+
+func newReplayFixture(t *testing.T) *replayFixture {
+	t.Helper()
+	db := memory.NewInMemoryDatabase()
+	eventStore := memory.NewInMemoryEventStore(db)
+	checkpoints := memory.NewInMemoryCheckpointStore(db)
+	return &replayFixture{
+		events:      eventStore,
+		checkpoints: checkpoints,
+		replayer:    newReplayer(t, eventStore, checkpoints),
+	}
+}
+
+// newReplayer builds a replayer around a fresh projection instance, as a new
+// process would after a restart
+func newReplayer(t *testing.T, eventStore *memory.InMemoryEventStore, checkpoints *memory.InMemoryCheckpointStore) *projections.ProjectionReplayer {
+	t.Helper()
+	logger := zap.NewNop()
+	stats := projections.NewGraphStatsProjection(di.NewInMemoryCache(), logger)
+	registry := projections.NewProjectionRegistry(checkpoints, logger)
+	require.NoError(t, registry.Register(stats))
+	return projections.NewProjectionReplayer(registry, eventStore, checkpoints, logger)
+}
+
+func (f *replayFixture) restart(t *testing.T) {
+	f.replayer = newReplayer(t, f.events, f.checkpoints)
+}
+
+func (f *replayFixture) nodeCreated(t *testing.T, graphID string, count int) {
+	t.Helper()
+	batch := make([]events.DomainEvent, 0, count)
+	for i := 0; i < count; i++ {
+		batch = append(batch, events.NewNodeCreatedWithPendingEdges(
+			valueobjects.NewNodeID(), graphID, "user-1", "title", nil, nil, 0, nil))
+	}
+	require.NoError(t, f.events.SaveEvents(context.Background(), batch))
+}
+
+func (f *replayFixture) nodeDeleted(t *testing.T, graphID string) {
+	t.Helper()
+	event := events.NewNodeDeletedEvent(valueobjects.NewNodeID(), "user-1", graphID, "", nil, nil, time.Now())
+	require.NoError(t, f.events.SaveEvents(context.Background(), []events.DomainEvent{event}))
+}
+
+func nodeCount(t *testing.T, f *replayFixture) int {
+	t.Helper()
+	position, err := f.checkpoints.GetPosition(context.Background(), "GraphStatsProjection")
+	require.NoError(t, err)
+	require.NotNil(t, position)
+
+	restored := projections.NewGraphStatsProjection(di.NewInMemoryCache(), zap.NewNop())
+	require.NoError(t, restored.RestoreState(context.Background(), position.State))
+	stats, err := restored.GetStats(context.Background(), "graph-1")
+	require.NoError(t, err)
+	return stats.NodeCount
+}
+
+func TestProjectionReplayer_BackfillsInBatches(t *testing.T) {
+	f := newReplayFixture(t)
+	f.nodeCreated(t, "graph-1", 5)
+	f.nodeDeleted(t, "graph-1")
+
+	result, err := f.replayer.Replay(context.Background(), "GraphStatsProjection", projections.ReplayOptions{BatchSize: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(6), result.EventsRead)
+	assert.Equal(t, int64(6), result.EventsApplied)
+	assert.Equal(t, 3, result.Checkpoints)
+	assert.Equal(t, 4, nodeCount(t, f))
+}
+
+func TestProjectionReplayer_ResumesWithoutReapplying(t *testing.T) {
+	ctx := context.Background()
+	f := newReplayFixture(t)
+	f.nodeCreated(t, "graph-1", 3)
+
+	_, err := f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, nodeCount(t, f))
+
+	// A new process picks up the state stored with the checkpoint and only
+	// applies what was appended since
+	f.nodeCreated(t, "graph-1", 2)
+	f.restart(t)
+	result, err := f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.EventsApplied)
+	assert.Equal(t, 5, nodeCount(t, f))
+
+	// Nothing new: the checkpoint stays where it is
+	result, err = f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, result.EventsRead)
+	assert.Zero(t, result.Checkpoints)
+	assert.Equal(t, 5, nodeCount(t, f))
+}
+
+func TestProjectionReplayer_ResetRebuildsFromScratch(t *testing.T) {
+	ctx := context.Background()
+	f := newReplayFixture(t)
+	f.nodeCreated(t, "graph-1", 4)
+
+	_, err := f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{})
+	require.NoError(t, err)
+
+	result, err := f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{Reset: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), result.EventsApplied)
+	assert.Equal(t, 4, nodeCount(t, f))
+}
+
+func TestProjectionReplayer_UnknownProjection(t *testing.T) {
+	f := newReplayFixture(t)
+
+	_, err := f.replayer.Replay(context.Background(), "Missing", projections.ReplayOptions{})
+	assert.Error(t, err)
+}
+
+func TestCheckpointStore_RejectsRegression(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryCheckpointStore(memory.NewInMemoryDatabase())
+
+	require.NoError(t, store.SavePosition(ctx, &projections.ProjectionPosition{
+		ProjectionName: "p", Position: 10, LastEventID: "b",
+	}))
+	assert.Error(t, store.SavePosition(ctx, &projections.ProjectionPosition{
+		ProjectionName: "p", Position: 10, LastEventID: "a",
+	}))
+	assert.Error(t, store.SavePosition(ctx, &projections.ProjectionPosition{
+		ProjectionName: "p", Position: 9, LastEventID: "z",
+	}))
+	require.NoError(t, store.SavePosition(ctx, &projections.ProjectionPosition{
+		ProjectionName: "p", Position: 11, LastEventID: "a",
+	}))
+
+	position, err := store.GetPosition(ctx, "p")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), position.Position)
+}
+
+func TestProjectionReplayer_SingleEventBatches(t *testing.T) {
+	ctx := context.Background()
+	f := newReplayFixture(t)
+	f.nodeCreated(t, "graph-1", 2)
+
+	_, err := f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{BatchSize: 1})
+	require.NoError(t, err)
+
+	f.nodeCreated(t, "graph-1", 2)
+	f.restart(t)
+	result, err := f.replayer.Replay(ctx, "GraphStatsProjection", projections.ReplayOptions{BatchSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.EventsApplied)
+	assert.Equal(t, 4, nodeCount(t, f))
+}
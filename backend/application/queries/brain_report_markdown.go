@@ -0,0 +1,122 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownEscaper escapes the characters that would turn note titles and
+// keywords into Markdown formatting or break table cells
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "|", `\|`, "#", `\#`,
+	"\r\n", " ", "\n", " ", "\r", " ",
+)
+
+// Markdown renders the report as a Markdown document
+func (r *GenerateBrainReportResult) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Brain report: %s\n\n", md(r.GraphName))
+	fmt.Fprintf(&b, "_Generated %s from version %d._\n\n", r.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"), r.Version)
+	fmt.Fprintf(&b, "**%s**, %s, %s and %s.\n",
+		plural(r.NodeCount, "note"), plural(r.EdgeCount, "link"),
+		plural(r.CommunityCount, "community"), plural(r.OrphanCount, "orphaned note"))
+
+	b.WriteString("\n## Top communities\n\n")
+	if len(r.Communities) == 0 {
+		b.WriteString("No communities have been detected yet.\n")
+	}
+	for i, c := range r.Communities {
+		fmt.Fprintf(&b, "%d. **%s**: %s, cohesion %.2f", i+1, md(c.Name), plural(c.MemberCount, "note"), c.Cohesion)
+		if len(c.Keywords) > 0 {
+			fmt.Fprintf(&b, "; keywords: %s", mdList(c.Keywords))
+		}
+		if c.CentralTitle != "" {
+			fmt.Fprintf(&b, "; central note: %s", md(c.CentralTitle))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Hub notes\n\n")
+	if len(r.Hubs) == 0 {
+		b.WriteString("No note is linked yet.\n")
+	} else {
+		b.WriteString("| Note | Links | PageRank |\n|---|---:|---:|\n")
+		for _, hub := range r.Hubs {
+			fmt.Fprintf(&b, "| %s | %d | %.4f |\n", md(hub.Title), hub.Degree, hub.PageRank)
+		}
+	}
+
+	b.WriteString("\n## Bridge notes\n\n")
+	if len(r.Bridges) == 0 {
+		b.WriteString("No note links separate communities.\n")
+	}
+	for _, bridge := range r.Bridges {
+		fmt.Fprintf(&b, "- **%s** (%s risk): %s\n", md(bridge.Title), strings.ToLower(bridge.RiskLevel), bridge.Summary)
+	}
+
+	b.WriteString("\n## Newest clusters\n\n")
+	if len(r.NewestClusters) == 0 {
+		b.WriteString("No clusters yet.\n")
+	}
+	for _, c := range r.NewestClusters {
+		fmt.Fprintf(&b, "- **%s**: %d of %s added in the last %d days, on average %s\n",
+			md(c.Name), c.RecentMembers, plural(c.MemberCount, "note"), r.Growth.WindowDays,
+			c.AverageCreatedAt.UTC().Format("2006-01-02"))
+	}
+
+	fmt.Fprintf(&b, "\n## Orphaned notes (%d)\n\n", r.OrphanCount)
+	if r.OrphanCount == 0 {
+		b.WriteString("Every note is linked.\n")
+	}
+	for _, orphan := range r.Orphans {
+		fmt.Fprintf(&b, "- %s\n", md(orphan.Title))
+	}
+	if more := r.OrphanCount - len(r.Orphans); more > 0 {
+		fmt.Fprintf(&b, "- _and %d more_\n", more)
+	}
+
+	fmt.Fprintf(&b, "\n## Growth over the last %d days\n\n", r.Growth.WindowDays)
+	fmt.Fprintf(&b, "%s added, %s added, %s edited.\n",
+		plural(r.Growth.NodesAdded, "note"), plural(r.Growth.EdgesAdded, "link"), plural(r.Growth.NodesUpdated, "older note"))
+	if len(r.Growth.Weeks) > 0 {
+		b.WriteString("\n| Week of | Notes | Links |\n|---|---:|---:|\n")
+		for _, week := range r.Growth.Weeks {
+			fmt.Fprintf(&b, "| %s | %d | %d |\n", week.Start.UTC().Format("2006-01-02"), week.Nodes, week.Edges)
+		}
+	}
+
+	b.WriteString("\n## Suggested follow-ups\n\n")
+	if len(r.FollowUps) == 0 {
+		b.WriteString("Nothing stands out.\n")
+	}
+	for _, followUp := range r.FollowUps {
+		fmt.Fprintf(&b, "- %s\n", followUp.Message)
+	}
+
+	return b.String()
+}
+
+// md escapes text for inline Markdown
+func md(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+func mdList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = md(item)
+	}
+	return strings.Join(escaped, ", ")
+}
+
+// plural formats a count with its noun, pluralized as needed
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,87 @@
+package queries
+
+import (
+	"fmt"
+
+	"backend/domain/core/entities"
+)
+
+const (
+	// defaultPathCount is the number of paths returned when the query sets none
+	defaultPathCount = 3
+	// maxPathCount bounds the alternative paths of one query
+	maxPathCount = 10
+)
+
+// FindPathsQuery asks for the cheapest paths between two nodes of a graph.
+// Strong, heavy edges are cheap to follow and weak, light ones expensive.
+type FindPathsQuery struct {
+	UserID       string   `json:"user_id"`
+	GraphID      string   `json:"graph_id"`
+	FromID       string   `json:"from_id"`
+	ToID         string   `json:"to_id"`
+	K            int      `json:"k"`             // Alternative paths returned; defaults to 3
+	ExcludeTypes []string `json:"exclude_types"` // Edge types the paths may not use
+	Directed     bool     `json:"directed"`      // Only follow edges from source to target
+}
+
+// Validate validates the query
+func (q FindPathsQuery) Validate() error {
+	if q.UserID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if q.GraphID == "" {
+		return fmt.Errorf("graph ID is required")
+	}
+	if q.FromID == "" || q.ToID == "" {
+		return fmt.Errorf("from and to node IDs are required")
+	}
+	if q.K < 0 || q.K > maxPathCount {
+		return fmt.Errorf("k must be between 1 and %d", maxPathCount)
+	}
+	for _, t := range q.ExcludeTypes {
+		if !entities.EdgeType(t).IsValid() {
+			return fmt.Errorf("invalid edge type: %s", t)
+		}
+	}
+	return nil
+}
+
+// PathCount returns the number of paths to find
+func (q FindPathsQuery) PathCount() int {
+	if q.K == 0 {
+		return defaultPathCount
+	}
+	return q.K
+}
+
+// FindPathsResult lists the paths found, cheapest first. It is empty when the
+// nodes are not connected.
+type FindPathsResult struct {
+	GraphID string      `json:"graph_id"`
+	FromID  string      `json:"from_id"`
+	ToID    string      `json:"to_id"`
+	Paths   []GraphPath `json:"paths"`
+}
+
+// GraphPath is one path between the two nodes
+type GraphPath struct {
+	Rank  int            `json:"rank"`
+	Cost  float64        `json:"cost"`
+	Hops  int            `json:"hops"`
+	Steps []GraphPathHop `json:"steps"`
+}
+
+// GraphPathHop is one edge followed by a path, with why it connects its nodes
+type GraphPathHop struct {
+	FromID      string  `json:"from_id"`
+	FromTitle   string  `json:"from_title"`
+	ToID        string  `json:"to_id"`
+	ToTitle     string  `json:"to_title"`
+	EdgeID      string  `json:"edge_id"`
+	EdgeType    string  `json:"edge_type"`
+	Weight      float64 `json:"weight"`
+	Cost        float64 `json:"cost"`
+	Direction   string  `json:"direction"` // "forward" along the edge or "backward" against it
+	Explanation string  `json:"explanation"`
+}
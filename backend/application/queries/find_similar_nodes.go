@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"backend/application/ports"
+	"backend/application/services"
 	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
 )
 
 // FindSimilarNodesQuery represents a query to find similar nodes
@@ -48,7 +51,8 @@ type FindSimilarNodesResult struct {
 
 // FindSimilarNodesHandler handles finding similar nodes
 type FindSimilarNodesHandler struct {
-	nodeRepo ports.NodeRepository
+	nodeRepo    ports.NodeRepository
+	vectorIndex *services.VectorIndexService
 }
 
 // NewFindSimilarNodesHandler creates a new handler
@@ -58,6 +62,12 @@ func NewFindSimilarNodesHandler(nodeRepo ports.NodeRepository) *FindSimilarNodes
 	}
 }
 
+// WithVectorIndex answers semantic similarity from the vector index
+func (h *FindSimilarNodesHandler) WithVectorIndex(vectorIndex *services.VectorIndexService) *FindSimilarNodesHandler {
+	h.vectorIndex = vectorIndex
+	return h
+}
+
 // Handle executes the query
 func (h *FindSimilarNodesHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
 	q, ok := query.(*FindSimilarNodesQuery)
@@ -65,6 +75,10 @@ func (h *FindSimilarNodesHandler) Handle(ctx context.Context, query interface{})
 		return nil, fmt.Errorf("invalid query type")
 	}
 
+	if q.SimilarityType == "semantic" && h.vectorIndex != nil {
+		return h.findSemantic(ctx, q)
+	}
+
 	// Get the reference node
 	// Note: This is simplified - the actual nodeRepo.GetByID might need adjustment
 	// to work with string IDs instead of NodeID value objects
@@ -113,6 +127,47 @@ func (h *FindSimilarNodesHandler) Handle(ctx context.Context, query interface{})
 	}, nil
 }
 
+// findSemantic returns the nodes whose embeddings are nearest to the
+// reference node's embedding
+func (h *FindSimilarNodesHandler) findSemantic(ctx context.Context, q *FindSimilarNodesQuery) (*FindSimilarNodesResult, error) {
+	nodeID, err := valueobjects.NewNodeIDFromString(q.NodeID)
+	if err != nil {
+		return nil, pkgerrors.NewValidationError("invalid node ID")
+	}
+	reference, err := h.nodeRepo.GetByID(ctx, nodeID)
+	if err != nil || reference.UserID() != q.UserID {
+		return nil, pkgerrors.NewNotFoundError("node")
+	}
+
+	neighbours, err := h.vectorIndex.SimilarTo(ctx, q.UserID, reference, q.MaxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vector index: %w", err)
+	}
+
+	similarNodes := make([]SimilarNode, 0, len(neighbours))
+	for _, neighbour := range neighbours {
+		id, err := valueobjects.NewNodeIDFromString(neighbour.ID)
+		if err != nil {
+			continue
+		}
+		node, err := h.nodeRepo.GetByID(ctx, id)
+		if err != nil {
+			// Removed since it was indexed
+			continue
+		}
+		similarNodes = append(similarNodes, SimilarNode{
+			Node:       node,
+			Similarity: neighbour.Score,
+			Reason:     "semantic similarity",
+		})
+	}
+
+	return &FindSimilarNodesResult{
+		Nodes:      similarNodes,
+		TotalFound: len(similarNodes),
+	}, nil
+}
+
 // calculateSimilarity calculates similarity between nodes
 func (h *FindSimilarNodesHandler) calculateSimilarity(node *entities.Node, similarityType string) float64 {
 	// This is a placeholder implementation
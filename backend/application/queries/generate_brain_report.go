@@ -0,0 +1,116 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+)
+
+// GenerateBrainReportQuery asks for a summary of the shape of a graph: its
+// main topics, the notes holding it together, the notes left out and how it
+// has grown lately
+type GenerateBrainReportQuery struct {
+	UserID  string `validate:"required"`
+	GraphID string `validate:"required"`
+}
+
+// Validate validates the query
+func (q GenerateBrainReportQuery) Validate() error {
+	if q.UserID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if q.GraphID == "" {
+		return fmt.Errorf("graph ID is required")
+	}
+	return nil
+}
+
+// GenerateBrainReportResult is the report on a graph. Lists are sorted most
+// relevant first and cut to a readable length; the counts cover everything.
+type GenerateBrainReportResult struct {
+	GraphID        string            `json:"graph_id"`
+	GraphName      string            `json:"graph_name"`
+	Version        int               `json:"version"`
+	NodeCount      int               `json:"node_count"`
+	EdgeCount      int               `json:"edge_count"`
+	CommunityCount int               `json:"community_count"`
+	Communities    []ReportCommunity `json:"communities"`     // Largest first
+	Hubs           []ReportNode      `json:"hubs"`            // By PageRank
+	Bridges        []ReportBridge    `json:"bridges"`         // Riskiest to remove first
+	NewestClusters []ReportCluster   `json:"newest_clusters"` // By average age of their notes
+	Orphans        []ReportNode      `json:"orphans"`         // Newest first
+	OrphanCount    int               `json:"orphan_count"`
+	Growth         ReportGrowth      `json:"growth"`
+	FollowUps      []ReportFollowUp  `json:"follow_ups"`
+	GeneratedAt    time.Time         `json:"generated_at"`
+}
+
+// ReportCommunity is a detected community of notes
+type ReportCommunity struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Keywords      []string `json:"keywords"`
+	MemberCount   int      `json:"member_count"`
+	Cohesion      float64  `json:"cohesion"`
+	CentralNodeID string   `json:"central_node_id,omitempty"`
+	CentralTitle  string   `json:"central_title,omitempty"`
+}
+
+// ReportCluster is a community with how recently its notes were written
+type ReportCluster struct {
+	ReportCommunity
+	AverageCreatedAt time.Time `json:"average_created_at"`
+	RecentMembers    int       `json:"recent_members"` // Notes added within the growth window
+}
+
+// ReportNode is a note of the report
+type ReportNode struct {
+	NodeID   string  `json:"node_id"`
+	Title    string  `json:"title"`
+	Degree   int     `json:"degree"`
+	PageRank float64 `json:"pagerank,omitempty"`
+}
+
+// ReportBridge is a note linking several communities, with the impact of
+// removing it
+type ReportBridge struct {
+	NodeID        string `json:"node_id"`
+	Title         string `json:"title"`
+	RiskLevel     string `json:"risk_level"`
+	Communities   int    `json:"communities"`    // Communities within two hops
+	AffectedNodes int    `json:"affected_nodes"` // Notes within two hops
+	Summary       string `json:"summary"`
+}
+
+// ReportGrowth counts the notes and links added over the growth window, in
+// total and per week
+type ReportGrowth struct {
+	WindowDays   int          `json:"window_days"`
+	Since        time.Time    `json:"since"`
+	NodesAdded   int          `json:"nodes_added"`
+	NodesUpdated int          `json:"nodes_updated"` // Older notes edited within the window
+	EdgesAdded   int          `json:"edges_added"`
+	Weeks        []GrowthWeek `json:"weeks"`
+}
+
+// GrowthWeek counts what was added in one week of the growth window
+type GrowthWeek struct {
+	Start time.Time `json:"start"`
+	Nodes int       `json:"nodes"`
+	Edges int       `json:"edges"`
+}
+
+// ReportFollowUp suggests something to do next, with the notes concerned
+type ReportFollowUp struct {
+	Kind    string   `json:"kind"`
+	Message string   `json:"message"`
+	NodeIDs []string `json:"node_ids,omitempty"`
+}
+
+// Report follow-up kinds
+const (
+	FollowUpLinkOrphans       = "link_orphans"
+	FollowUpReinforceBridge   = "reinforce_bridge"
+	FollowUpDevelopCluster    = "develop_cluster"
+	FollowUpDetectCommunities = "detect_communities"
+	FollowUpRevisitHub        = "revisit_hub"
+)
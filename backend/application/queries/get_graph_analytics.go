@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetGraphAnalyticsQuery retrieves the centrality and structure analytics of
+// a graph
+type GetGraphAnalyticsQuery struct {
+	UserID  string `validate:"required"`
+	GraphID string `validate:"required"`
+	Top     int    // Entries of each ranked list; defaults to 10
+}
+
+// Validate validates the query
+func (q GetGraphAnalyticsQuery) Validate() error {
+	if q.UserID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if q.GraphID == "" {
+		return fmt.Errorf("graph ID is required")
+	}
+	if q.Top < 0 {
+		return fmt.Errorf("top must not be negative")
+	}
+	return nil
+}
+
+// GetGraphAnalyticsResult ranks the nodes of a graph by centrality and lists
+// its structural weak points. Ranked lists are sorted best first.
+type GetGraphAnalyticsResult struct {
+	GraphID            string            `json:"graph_id"`
+	Version            int               `json:"version"`
+	NodeCount          int               `json:"node_count"`
+	EdgeCount          int               `json:"edge_count"`
+	PageRank           []RankedGraphNode `json:"pagerank"`
+	Betweenness        []RankedGraphNode `json:"betweenness"`
+	Eigenvector        []RankedGraphNode `json:"eigenvector"`
+	CoreNumber         []RankedGraphNode `json:"core_number"`
+	Degeneracy         int               `json:"degeneracy"` // Largest core number
+	Bridges            []GraphBridgeLink `json:"bridges"`
+	BridgeCount        int               `json:"bridge_count"`
+	ArticulationPoints []RankedGraphNode `json:"articulation_points"` // Ranked by betweenness
+	ArticulationCount  int               `json:"articulation_count"`
+	ComputedAt         time.Time         `json:"computed_at"`
+}
+
+// RankedGraphNode is a node with its value for one measure
+type RankedGraphNode struct {
+	NodeID string  `json:"node_id"`
+	Title  string  `json:"title"`
+	Score  float64 `json:"score"`
+}
+
+// GraphBridgeLink is a link whose removal disconnects its two nodes
+type GraphBridgeLink struct {
+	SourceID    string `json:"source_id"`
+	SourceTitle string `json:"source_title"`
+	TargetID    string `json:"target_id"`
+	TargetTitle string `json:"target_title"`
+}
+
+// Truncate returns a copy of the result with every list cut to top entries
+func (r *GetGraphAnalyticsResult) Truncate(top int) *GetGraphAnalyticsResult {
+	out := *r
+	out.PageRank = firstRanked(r.PageRank, top)
+	out.Betweenness = firstRanked(r.Betweenness, top)
+	out.Eigenvector = firstRanked(r.Eigenvector, top)
+	out.CoreNumber = firstRanked(r.CoreNumber, top)
+	out.ArticulationPoints = firstRanked(r.ArticulationPoints, top)
+	out.Bridges = r.Bridges[:min(top, len(r.Bridges))]
+	return &out
+}
+
+func firstRanked(nodes []RankedGraphNode, top int) []RankedGraphNode {
+	return nodes[:min(top, len(nodes))]
+}
@@ -0,0 +1,279 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+)
+
+const (
+	// maxRelatedSeeds bounds the seeds of one related nodes query
+	maxRelatedSeeds = 20
+	// maxRelatedResults bounds the related nodes returned by one query
+	maxRelatedResults = 100
+)
+
+// GetRelatedNodesQuery asks for the nodes most related to one or more seed
+// nodes, ranked by personalized PageRank over the weighted edges and
+// optionally blended with semantic similarity.
+type GetRelatedNodesQuery struct {
+	UserID         string   `json:"user_id"`
+	NodeID         string   `json:"node_id"`
+	SeedIDs        []string `json:"seed_ids"`        // Further seeds, walked from together with NodeID
+	Limit          int      `json:"limit"`           // Related nodes returned
+	SemanticWeight float64  `json:"semantic_weight"` // Share of the score from embedding similarity, 0 to 1
+	MaxPaths       int      `json:"max_paths"`       // Paths explaining each related node
+}
+
+// Validate validates the query
+func (q *GetRelatedNodesQuery) Validate() error {
+	if q.UserID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if q.NodeID == "" {
+		return fmt.Errorf("node ID is required")
+	}
+	if len(q.SeedIDs) >= maxRelatedSeeds {
+		return fmt.Errorf("at most %d seed nodes are allowed", maxRelatedSeeds)
+	}
+	if q.SemanticWeight < 0 || q.SemanticWeight > 1 {
+		return fmt.Errorf("semantic weight must be between 0 and 1")
+	}
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	if q.Limit > maxRelatedResults {
+		q.Limit = maxRelatedResults
+	}
+	if q.MaxPaths <= 0 {
+		q.MaxPaths = 3
+	}
+	return nil
+}
+
+// GetRelatedNodesResult holds the related nodes, most related first.
+type GetRelatedNodesResult struct {
+	Seeds []string      `json:"seeds"`
+	Nodes []RelatedNode `json:"nodes"`
+	Total int           `json:"total"`
+}
+
+// RelatedNode is a node related to the seeds, with the paths that relate it.
+type RelatedNode struct {
+	NodeID     string        `json:"node_id"`
+	Title      string        `json:"title"`
+	Score      float64       `json:"score"`      // Blended score
+	Structural float64       `json:"structural"` // Personalized PageRank relative to the best related node
+	Semantic   float64       `json:"semantic"`   // Best cosine similarity to a seed
+	Paths      []RelatedPath `json:"paths"`
+}
+
+// RelatedPath is one of the strongest paths from a seed to a related node.
+type RelatedPath struct {
+	Steps    []RelatedPathStep `json:"steps"`
+	Strength float64           `json:"strength"` // Probability of a walk from the seed taking this path
+}
+
+// RelatedPathStep is a node along a related path.
+type RelatedPathStep struct {
+	NodeID string `json:"node_id"`
+	Title  string `json:"title"`
+}
+
+// GetRelatedNodesHandler handles related nodes queries
+type GetRelatedNodesHandler struct {
+	nodeRepo ports.NodeRepository
+	edgeRepo ports.EdgeRepository
+	ranker   *domainservices.PersonalizedPageRankService
+}
+
+// NewGetRelatedNodesHandler creates a new handler
+func NewGetRelatedNodesHandler(nodeRepo ports.NodeRepository, edgeRepo ports.EdgeRepository) *GetRelatedNodesHandler {
+	return &GetRelatedNodesHandler{
+		nodeRepo: nodeRepo,
+		edgeRepo: edgeRepo,
+		ranker:   domainservices.NewPersonalizedPageRankService(),
+	}
+}
+
+// Handle executes the query
+func (h *GetRelatedNodesHandler) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(*GetRelatedNodesQuery)
+	if !ok {
+		return nil, fmt.Errorf("invalid query type")
+	}
+
+	seeds, err := h.loadSeeds(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	nodes, edges, err := h.loadGraphs(ctx, q.UserID, seeds)
+	if err != nil {
+		return nil, err
+	}
+
+	seedIDs := make([]valueobjects.NodeID, len(seeds))
+	isSeed := make(map[string]bool, len(seeds))
+	for i, seed := range seeds {
+		seedIDs[i] = seed.ID()
+		isSeed[seed.ID().String()] = true
+	}
+	ppr := h.ranker.Rank(edges, seedIDs, nil)
+
+	// PageRank mass is spread over the whole graph, so scale it to the best
+	// related node to make it comparable with cosine similarity
+	topScore := 0.0
+	for id, score := range ppr.Scores {
+		if !isSeed[id] {
+			topScore = max(topScore, score)
+		}
+	}
+
+	semanticWeight := q.SemanticWeight
+	seedEmbeddings := make([]valueobjects.Embedding, 0, len(seeds))
+	for _, seed := range seeds {
+		if !seed.Embedding().IsZero() {
+			seedEmbeddings = append(seedEmbeddings, seed.Embedding())
+		}
+	}
+	if len(seedEmbeddings) == 0 {
+		// Nothing to compare with; rank by structure alone
+		semanticWeight = 0
+	}
+
+	related := make([]RelatedNode, 0)
+	for id, node := range nodes {
+		if isSeed[id] {
+			continue
+		}
+		structural := 0.0
+		if topScore > 0 {
+			structural = ppr.Scores[id] / topScore
+		}
+		semantic := 0.0
+		if semanticWeight > 0 && !node.Embedding().IsZero() {
+			for _, seedEmbedding := range seedEmbeddings {
+				semantic = max(semantic, seedEmbedding.CosineSimilarity(node.Embedding()))
+			}
+		}
+		score := (1-semanticWeight)*structural + semanticWeight*semantic
+		if score <= 0 {
+			continue
+		}
+		related = append(related, RelatedNode{
+			NodeID:     id,
+			Title:      node.Content().Title(),
+			Score:      score,
+			Structural: structural,
+			Semantic:   semantic,
+		})
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].NodeID < related[j].NodeID
+	})
+
+	total := len(related)
+	if len(related) > q.Limit {
+		related = related[:q.Limit]
+	}
+	for i := range related {
+		related[i].Paths = relatedPaths(ppr.Paths(related[i].NodeID, q.MaxPaths), nodes)
+	}
+
+	seedIDStrings := make([]string, len(seeds))
+	for i, seed := range seeds {
+		seedIDStrings[i] = seed.ID().String()
+	}
+	return &GetRelatedNodesResult{
+		Seeds: seedIDStrings,
+		Nodes: related,
+		Total: total,
+	}, nil
+}
+
+// loadSeeds loads the seed nodes of a query, which must belong to the user
+func (h *GetRelatedNodesHandler) loadSeeds(ctx context.Context, q *GetRelatedNodesQuery) ([]*entities.Node, error) {
+	ids := append([]string{q.NodeID}, q.SeedIDs...)
+	seeds := make([]*entities.Node, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, rawID := range ids {
+		if seen[rawID] {
+			continue
+		}
+		seen[rawID] = true
+		id, err := valueobjects.NewNodeIDFromString(rawID)
+		if err != nil {
+			return nil, pkgerrors.NewValidationError(fmt.Sprintf("invalid node ID: %s", rawID))
+		}
+		node, err := h.nodeRepo.GetByID(ctx, id)
+		if err != nil || node == nil || node.UserID() != q.UserID {
+			return nil, pkgerrors.NewNotFoundError("node")
+		}
+		seeds = append(seeds, node)
+	}
+	return seeds, nil
+}
+
+// loadGraphs loads the user's nodes and the edges of every graph a seed is in
+func (h *GetRelatedNodesHandler) loadGraphs(ctx context.Context, userID string, seeds []*entities.Node) (map[string]*entities.Node, []*aggregates.Edge, error) {
+	nodes := make(map[string]*entities.Node)
+	var edges []*aggregates.Edge
+	loaded := make(map[string]bool)
+	for _, seed := range seeds {
+		graphID := seed.GraphID()
+		if graphID == "" || loaded[graphID] {
+			nodes[seed.ID().String()] = seed
+			continue
+		}
+		loaded[graphID] = true
+
+		graphNodes, err := h.nodeRepo.GetByGraphID(ctx, graphID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load nodes: %w", err)
+		}
+		for _, node := range graphNodes {
+			if node.UserID() == userID {
+				nodes[node.ID().String()] = node
+			}
+		}
+		graphEdges, err := h.edgeRepo.GetByGraphID(ctx, graphID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load edges: %w", err)
+		}
+		edges = append(edges, graphEdges...)
+	}
+
+	// Walk only between nodes the user owns
+	owned := make([]*aggregates.Edge, 0, len(edges))
+	for _, edge := range edges {
+		if nodes[edge.SourceID.String()] != nil && nodes[edge.TargetID.String()] != nil {
+			owned = append(owned, edge)
+		}
+	}
+	return nodes, owned, nil
+}
+
+func relatedPaths(paths []domainservices.WalkPath, nodes map[string]*entities.Node) []RelatedPath {
+	out := make([]RelatedPath, len(paths))
+	for i, path := range paths {
+		steps := make([]RelatedPathStep, len(path.Steps))
+		for j, id := range path.Steps {
+			steps[j] = RelatedPathStep{NodeID: id}
+			if node := nodes[id]; node != nil {
+				steps[j].Title = node.Content().Title()
+			}
+		}
+		out[i] = RelatedPath{Steps: steps, Strength: path.Strength}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/application/queries"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	domainservices "backend/domain/services"
+	"go.uber.org/zap"
+)
+
+// FindPathsHandler handles the FindPathsQuery
+type FindPathsHandler struct {
+	graphRepo  ports.GraphRepository
+	nodeRepo   ports.NodeRepository
+	edgeRepo   ports.EdgeRepository
+	pathFinder *domainservices.PathFinderService
+	logger     *zap.Logger
+}
+
+// NewFindPathsHandler creates a new handler instance
+func NewFindPathsHandler(
+	graphRepo ports.GraphRepository,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	logger *zap.Logger,
+) *FindPathsHandler {
+	return &FindPathsHandler{
+		graphRepo:  graphRepo,
+		nodeRepo:   nodeRepo,
+		edgeRepo:   edgeRepo,
+		pathFinder: domainservices.NewPathFinderService(),
+		logger:     logger,
+	}
+}
+
+// Handle executes the query
+func (h *FindPathsHandler) Handle(ctx context.Context, query queries.FindPathsQuery) (*queries.FindPathsResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	fromID, err := valueobjects.NewNodeIDFromString(query.FromID)
+	if err != nil {
+		return nil, err
+	}
+	toID, err := valueobjects.NewNodeIDFromString(query.ToID)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := h.graphRepo.GetByID(ctx, aggregates.GraphID(query.GraphID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph: %w", err)
+	}
+	if graph == nil {
+		return nil, fmt.Errorf("graph not found")
+	}
+	if graph.UserID() != query.UserID {
+		return nil, fmt.Errorf("unauthorized access to graph")
+	}
+
+	nodes, err := h.nodeRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	edges, err := h.edgeRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+	snapshot, err := buildGraphSnapshot(graph, nodes, edges, h.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := domainservices.DefaultPathCostConfig()
+	cfg.Directed = query.Directed
+	for _, t := range query.ExcludeTypes {
+		cfg.ExcludedTypes = append(cfg.ExcludedTypes, entities.EdgeType(t))
+	}
+
+	found, err := h.pathFinder.KShortestPaths(
+		snapshot.graph,
+		fromID,
+		toID,
+		query.PathCount(),
+		cfg,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &queries.FindPathsResult{
+		GraphID: query.GraphID,
+		FromID:  query.FromID,
+		ToID:    query.ToID,
+		Paths:   make([]queries.GraphPath, 0, len(found)),
+	}
+	for i, path := range found {
+		steps := make([]queries.GraphPathHop, 0, len(path.Hops))
+		for _, hop := range path.Hops {
+			steps = append(steps, pathStep(hop, snapshot.titles))
+		}
+		result.Paths = append(result.Paths, queries.GraphPath{
+			Rank:  i + 1,
+			Cost:  path.Cost,
+			Hops:  len(path.Hops),
+			Steps: steps,
+		})
+	}
+
+	h.logger.Debug("Paths found",
+		zap.String("graphID", query.GraphID),
+		zap.Int("count", len(result.Paths)))
+
+	return result, nil
+}
+
+// pathStep describes one hop, reading the edge in the direction it was walked
+func pathStep(hop domainservices.PathHop, titles map[valueobjects.NodeID]string) queries.GraphPathHop {
+	fromTitle, toTitle := titles[hop.FromID], titles[hop.ToID]
+	direction := "forward"
+	if hop.Reversed {
+		direction = "backward"
+	}
+	return queries.GraphPathHop{
+		FromID:      hop.FromID.String(),
+		FromTitle:   fromTitle,
+		ToID:        hop.ToID.String(),
+		ToTitle:     toTitle,
+		EdgeID:      hop.Edge.ID,
+		EdgeType:    hop.Edge.Type.String(),
+		Weight:      hop.Edge.Weight,
+		Cost:        hop.Cost,
+		Direction:   direction,
+		Explanation: fmt.Sprintf("%q %s %q (weight %.2f)", fromTitle, edgeRelation(hop.Edge.Type, hop.Reversed), toTitle, hop.Edge.Weight),
+	}
+}
+
+// edgeRelation phrases what an edge of the given type says about its source
+// and target, or the other way round when it was walked backwards
+func edgeRelation(edgeType entities.EdgeType, reversed bool) string {
+	switch edgeType {
+	case entities.EdgeTypeReference:
+		if reversed {
+			return "is referenced by"
+		}
+		return "references"
+	case entities.EdgeTypeHierarchical:
+		if reversed {
+			return "is a child of"
+		}
+		return "is the parent of"
+	case entities.EdgeTypeStrong:
+		return "is strongly connected to"
+	case entities.EdgeTypeWeak:
+		return "is loosely connected to"
+	case entities.EdgeTypeTemporal:
+		return "is close in time to"
+	default:
+		return "is connected to"
+	}
+}
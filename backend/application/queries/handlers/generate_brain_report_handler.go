@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	"backend/application/queries"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// reportCommunities is the number of largest communities reported
+	reportCommunities = 5
+	// reportHubs is the number of hub notes reported
+	reportHubs = 10
+	// reportBridges is the number of bridge notes reported
+	reportBridges = 5
+	// reportBridgeCandidates bounds the impact analyses run per report; the
+	// best connected notes linking several communities are analysed first
+	reportBridgeCandidates = 20
+	// reportNewestClusters is the number of newest clusters reported
+	reportNewestClusters = 3
+	// reportOrphans is the number of orphaned notes listed
+	reportOrphans = 20
+	// reportGrowthDays is the growth window
+	reportGrowthDays = 30
+	// reportCacheTTL is how long generated reports are kept, in seconds
+	reportCacheTTL = 3600
+)
+
+// GenerateBrainReportHandler handles the GenerateBrainReportQuery
+type GenerateBrainReportHandler struct {
+	cache         ports.Cache
+	graphRepo     ports.GraphRepository
+	nodeRepo      ports.NodeRepository
+	edgeRepo      ports.EdgeRepository
+	communityRepo ports.CommunityRepository
+	analytics     *domainservices.GraphAnalyticsService
+	impact        *domainservices.ImpactAnalysisService
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewGenerateBrainReportHandler creates a new handler instance
+func NewGenerateBrainReportHandler(
+	cache ports.Cache,
+	graphRepo ports.GraphRepository,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	communityRepo ports.CommunityRepository,
+	logger *zap.Logger,
+) *GenerateBrainReportHandler {
+	return &GenerateBrainReportHandler{
+		cache:         cache,
+		graphRepo:     graphRepo,
+		nodeRepo:      nodeRepo,
+		edgeRepo:      edgeRepo,
+		communityRepo: communityRepo,
+		analytics:     domainservices.NewGraphAnalyticsService(),
+		impact:        domainservices.NewImpactAnalysisService(),
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Handle executes the query
+func (h *GenerateBrainReportHandler) Handle(ctx context.Context, query queries.GenerateBrainReportQuery) (*queries.GenerateBrainReportResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	graph, err := h.graphRepo.GetByID(ctx, aggregates.GraphID(query.GraphID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph: %w", err)
+	}
+	if graph == nil {
+		return nil, fmt.Errorf("graph not found")
+	}
+	if graph.UserID() != query.UserID {
+		return nil, fmt.Errorf("unauthorized access to graph")
+	}
+
+	nodes, err := h.nodeRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	edges, err := h.edgeRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+	partition, err := h.communityRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		if !pkgerrors.IsNotFound(err) {
+			// Report without communities rather than not at all
+			h.logger.Warn("Failed to load communities for brain report",
+				zap.String("graphID", query.GraphID),
+				zap.Error(err))
+		}
+		partition = nil
+	}
+
+	// Communities can be recomputed without the structure changing, so the
+	// key also carries the partition's last update
+	var partitionStamp int64
+	if partition != nil {
+		partitionStamp = partition.UpdatedAt.UnixNano()
+	}
+	cacheKey := fmt.Sprintf("graph:report:%s:v%d:%x:%d", query.GraphID, graph.Version(), structureFingerprint(nodes, edges), partitionStamp)
+	if cachedValue, found := h.cache.Get(ctx, cacheKey); found {
+		if result, ok := cachedValue.(*queries.GenerateBrainReportResult); ok {
+			h.logger.Debug("Brain report retrieved from cache",
+				zap.String("graphID", query.GraphID))
+			return result, nil
+		}
+	}
+
+	orphans, err := h.nodeRepo.FindOrphanedNodes(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphaned nodes: %w", err)
+	}
+
+	result, err := h.generate(graph, nodes, edges, partition, orphans)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.cache.Set(ctx, cacheKey, result, reportCacheTTL); err != nil {
+		h.logger.Warn("Failed to cache brain report",
+			zap.String("graphID", query.GraphID),
+			zap.Error(err))
+	}
+
+	return result, nil
+}
+
+// generate builds every section of the report
+func (h *GenerateBrainReportHandler) generate(
+	graph *aggregates.Graph,
+	nodes []*entities.Node,
+	edges []*aggregates.Edge,
+	partition *entities.CommunityPartition,
+	orphans []*entities.Node,
+) (*queries.GenerateBrainReportResult, error) {
+	snapshot, err := buildGraphSnapshot(graph, nodes, edges, h.logger)
+	if err != nil {
+		return nil, err
+	}
+	analytics, err := h.analytics.Analyze(snapshot.graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze graph: %w", err)
+	}
+	neighbours := neighbourSets(snapshot.graph)
+
+	now := h.now()
+	result := &queries.GenerateBrainReportResult{
+		GraphID:     graph.ID().String(),
+		GraphName:   graph.Name(),
+		Version:     graph.Version(),
+		NodeCount:   len(snapshot.titles),
+		EdgeCount:   snapshot.edgeCount,
+		OrphanCount: len(orphans),
+		GeneratedAt: now,
+	}
+
+	for _, ranked := range rankNodes(analytics.PageRank, snapshot.titles) {
+		if len(result.Hubs) == reportHubs {
+			break
+		}
+		id, err := valueobjects.NewNodeIDFromString(ranked.NodeID)
+		if err != nil || len(neighbours[id]) == 0 {
+			continue
+		}
+		result.Hubs = append(result.Hubs, queries.ReportNode{
+			NodeID:   ranked.NodeID,
+			Title:    ranked.Title,
+			Degree:   len(neighbours[id]),
+			PageRank: ranked.Score,
+		})
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return orphans[i].CreatedAt().After(orphans[j].CreatedAt())
+	})
+	for _, orphan := range orphans[:min(reportOrphans, len(orphans))] {
+		result.Orphans = append(result.Orphans, queries.ReportNode{
+			NodeID: orphan.ID().String(),
+			Title:  orphan.Content().Title(),
+		})
+	}
+
+	since := now.AddDate(0, 0, -reportGrowthDays)
+	result.Growth = reportGrowth(nodes, edges, since)
+	if partition != nil {
+		result.CommunityCount = len(partition.Communities)
+		result.Communities = largestReportCommunities(partition, snapshot.titles)
+		result.NewestClusters = newestReportClusters(partition, nodes, snapshot.titles, since)
+	}
+	result.Bridges = h.reportBridges(snapshot, nodes, neighbours)
+	result.FollowUps = reportFollowUps(result)
+
+	return result, nil
+}
+
+// reportBridges runs impact analysis on the notes whose neighbours belong to
+// more than one community
+func (h *GenerateBrainReportHandler) reportBridges(
+	snapshot *graphSnapshot,
+	nodes []*entities.Node,
+	neighbours map[valueobjects.NodeID]map[valueobjects.NodeID]bool,
+) []queries.ReportBridge {
+	byID := make(map[valueobjects.NodeID]*entities.Node, len(nodes))
+	for _, node := range nodes {
+		if _, ok := snapshot.titles[node.ID()]; ok {
+			byID[node.ID()] = node
+		}
+	}
+
+	candidates := make([]valueobjects.NodeID, 0)
+	for id := range byID {
+		communities := make(map[string]bool)
+		for neighbour := range neighbours[id] {
+			if node, ok := byID[neighbour]; ok && node.CommunityID() != "" {
+				communities[node.CommunityID()] = true
+			}
+		}
+		if len(communities) > 1 {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if di, dj := len(neighbours[candidates[i]]), len(neighbours[candidates[j]]); di != dj {
+			return di > dj
+		}
+		return candidates[i].String() < candidates[j].String()
+	})
+	candidates = candidates[:min(reportBridgeCandidates, len(candidates))]
+
+	bridges := make([]queries.ReportBridge, 0, len(candidates))
+	for _, id := range candidates {
+		impact, err := h.impact.Analyze(snapshot.graph, id, byID, 2)
+		if err != nil {
+			h.logger.Warn("Failed to analyze bridge impact",
+				zap.String("nodeID", id.String()),
+				zap.Error(err))
+			continue
+		}
+		bridges = append(bridges, queries.ReportBridge{
+			NodeID:        id.String(),
+			Title:         snapshot.titles[id],
+			RiskLevel:     string(impact.RiskLevel),
+			Communities:   impact.AffectedCommunityCount,
+			AffectedNodes: impact.TotalAffectedNodes,
+			Summary:       impact.Summary,
+		})
+	}
+
+	risk := map[string]int{
+		string(domainservices.RiskCritical): 0,
+		string(domainservices.RiskHigh):     1,
+		string(domainservices.RiskMedium):   2,
+		string(domainservices.RiskLow):      3,
+	}
+	sort.SliceStable(bridges, func(i, j int) bool {
+		if risk[bridges[i].RiskLevel] != risk[bridges[j].RiskLevel] {
+			return risk[bridges[i].RiskLevel] < risk[bridges[j].RiskLevel]
+		}
+		return bridges[i].AffectedNodes > bridges[j].AffectedNodes
+	})
+	return bridges[:min(reportBridges, len(bridges))]
+}
+
+// neighbourSets lists the distinct neighbours of every node, treating edges
+// as undirected
+func neighbourSets(graph *aggregates.Graph) map[valueobjects.NodeID]map[valueobjects.NodeID]bool {
+	neighbours := make(map[valueobjects.NodeID]map[valueobjects.NodeID]bool)
+	link := func(a, b valueobjects.NodeID) {
+		if neighbours[a] == nil {
+			neighbours[a] = make(map[valueobjects.NodeID]bool)
+		}
+		neighbours[a][b] = true
+	}
+	for _, edge := range graph.GetEdges() {
+		if edge.SourceID == edge.TargetID {
+			continue
+		}
+		link(edge.SourceID, edge.TargetID)
+		link(edge.TargetID, edge.SourceID)
+	}
+	return neighbours
+}
+
+func reportCommunity(c *entities.Community, titles map[valueobjects.NodeID]string) queries.ReportCommunity {
+	community := queries.ReportCommunity{
+		ID:            c.ID,
+		Name:          c.Name,
+		Keywords:      c.Keywords,
+		MemberCount:   c.MemberCount,
+		Cohesion:      c.CohesionScore,
+		CentralNodeID: c.CentralNodeID,
+	}
+	if id, err := valueobjects.NewNodeIDFromString(c.CentralNodeID); err == nil {
+		community.CentralTitle = titles[id]
+	}
+	return community
+}
+
+// largestReportCommunities returns the largest communities
+func largestReportCommunities(partition *entities.CommunityPartition, titles map[valueobjects.NodeID]string) []queries.ReportCommunity {
+	communities := make([]*entities.Community, len(partition.Communities))
+	copy(communities, partition.Communities)
+	sort.SliceStable(communities, func(i, j int) bool {
+		if communities[i].MemberCount != communities[j].MemberCount {
+			return communities[i].MemberCount > communities[j].MemberCount
+		}
+		return communities[i].ID < communities[j].ID
+	})
+
+	result := make([]queries.ReportCommunity, 0, reportCommunities)
+	for _, c := range communities[:min(reportCommunities, len(communities))] {
+		result = append(result, reportCommunity(c, titles))
+	}
+	return result
+}
+
+// newestReportClusters returns the communities whose notes are, on average,
+// the most recent
+func newestReportClusters(
+	partition *entities.CommunityPartition,
+	nodes []*entities.Node,
+	titles map[valueobjects.NodeID]string,
+	since time.Time,
+) []queries.ReportCluster {
+	created := make(map[string]time.Time, len(nodes))
+	for _, node := range nodes {
+		created[node.ID().String()] = node.CreatedAt()
+	}
+
+	clusters := make([]queries.ReportCluster, 0, len(partition.Communities))
+	for _, c := range partition.Communities {
+		var total int64
+		var count, recent int
+		for _, memberID := range c.MemberIDs {
+			at, ok := created[memberID]
+			if !ok {
+				continue
+			}
+			total += at.Unix()
+			count++
+			if at.After(since) {
+				recent++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		clusters = append(clusters, queries.ReportCluster{
+			ReportCommunity:  reportCommunity(c, titles),
+			AverageCreatedAt: time.Unix(total/int64(count), 0).UTC(),
+			RecentMembers:    recent,
+		})
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if !clusters[i].AverageCreatedAt.Equal(clusters[j].AverageCreatedAt) {
+			return clusters[i].AverageCreatedAt.After(clusters[j].AverageCreatedAt)
+		}
+		return clusters[i].ID < clusters[j].ID
+	})
+	return clusters[:min(reportNewestClusters, len(clusters))]
+}
+
+// reportGrowth counts what was added since the start of the growth window,
+// week by week; the last week may be shorter
+func reportGrowth(nodes []*entities.Node, edges []*aggregates.Edge, since time.Time) queries.ReportGrowth {
+	const week = 7 * 24 * time.Hour
+	growth := queries.ReportGrowth{
+		WindowDays: reportGrowthDays,
+		Since:      since,
+		Weeks:      make([]queries.GrowthWeek, (reportGrowthDays+6)/7),
+	}
+	for i := range growth.Weeks {
+		growth.Weeks[i].Start = since.Add(time.Duration(i) * week)
+	}
+	weekOf := func(at time.Time) int {
+		return min(int(at.Sub(since)/week), len(growth.Weeks)-1)
+	}
+
+	for _, node := range nodes {
+		switch {
+		case node.CreatedAt().After(since):
+			growth.NodesAdded++
+			growth.Weeks[weekOf(node.CreatedAt())].Nodes++
+		case node.UpdatedAt().After(since):
+			growth.NodesUpdated++
+		}
+	}
+	for _, edge := range edges {
+		if edge.CreatedAt.After(since) {
+			growth.EdgesAdded++
+			growth.Weeks[weekOf(edge.CreatedAt)].Edges++
+		}
+	}
+	return growth
+}
+
+// reportFollowUps suggests what to work on next from the rest of the report
+func reportFollowUps(report *queries.GenerateBrainReportResult) []queries.ReportFollowUp {
+	followUps := make([]queries.ReportFollowUp, 0)
+
+	if report.OrphanCount > 0 {
+		ids := make([]string, 0, 5)
+		for _, orphan := range report.Orphans[:min(5, len(report.Orphans))] {
+			ids = append(ids, orphan.NodeID)
+		}
+		message := fmt.Sprintf("Link your %d orphaned notes, starting with %q", report.OrphanCount, report.Orphans[0].Title)
+		if report.OrphanCount == 1 {
+			message = fmt.Sprintf("Link your orphaned note %q", report.Orphans[0].Title)
+		}
+		followUps = append(followUps, queries.ReportFollowUp{
+			Kind:    queries.FollowUpLinkOrphans,
+			Message: message,
+			NodeIDs: ids,
+		})
+	}
+
+	for _, bridge := range report.Bridges[:min(2, len(report.Bridges))] {
+		followUps = append(followUps, queries.ReportFollowUp{
+			Kind: queries.FollowUpReinforceBridge,
+			Message: fmt.Sprintf("%q holds %d communities together; link their notes to each other so they do not hang on one note",
+				bridge.Title, bridge.Communities),
+			NodeIDs: []string{bridge.NodeID},
+		})
+	}
+
+	if report.CommunityCount == 0 && report.NodeCount > 0 {
+		followUps = append(followUps, queries.ReportFollowUp{
+			Kind:    queries.FollowUpDetectCommunities,
+			Message: "Detect communities to group your notes into topics",
+		})
+	}
+	for _, cluster := range report.NewestClusters {
+		if cluster.RecentMembers == 0 {
+			continue
+		}
+		followUp := queries.ReportFollowUp{
+			Kind: queries.FollowUpDevelopCluster,
+			Message: fmt.Sprintf("%q is your newest cluster, with %d notes added in the last %d days; write a note summarising it",
+				cluster.Name, cluster.RecentMembers, report.Growth.WindowDays),
+		}
+		if cluster.CentralNodeID != "" {
+			followUp.NodeIDs = []string{cluster.CentralNodeID}
+		}
+		followUps = append(followUps, followUp)
+		break
+	}
+
+	if report.Growth.NodesAdded == 0 && len(report.Hubs) > 0 {
+		hub := report.Hubs[0]
+		followUps = append(followUps, queries.ReportFollowUp{
+			Kind: queries.FollowUpRevisitHub,
+			Message: fmt.Sprintf("No notes were added in the last %d days; revisit %q to pick up the thread",
+				report.Growth.WindowDays, hub.Title),
+			NodeIDs: []string{hub.NodeID},
+		})
+	}
+
+	return followUps
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	"backend/application/queries"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	domainservices "backend/domain/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAnalyticsTop is the length of ranked lists when the query sets none
+	defaultAnalyticsTop = 10
+	// analyticsCacheTTL is how long computed analytics are kept, in seconds
+	analyticsCacheTTL = 3600
+)
+
+// GetGraphAnalyticsHandler handles the GetGraphAnalyticsQuery
+type GetGraphAnalyticsHandler struct {
+	cache     ports.Cache
+	graphRepo ports.GraphRepository
+	nodeRepo  ports.NodeRepository
+	edgeRepo  ports.EdgeRepository
+	analytics *domainservices.GraphAnalyticsService
+	logger    *zap.Logger
+}
+
+// NewGetGraphAnalyticsHandler creates a new handler instance
+func NewGetGraphAnalyticsHandler(
+	cache ports.Cache,
+	graphRepo ports.GraphRepository,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	logger *zap.Logger,
+) *GetGraphAnalyticsHandler {
+	return &GetGraphAnalyticsHandler{
+		cache:     cache,
+		graphRepo: graphRepo,
+		nodeRepo:  nodeRepo,
+		edgeRepo:  edgeRepo,
+		analytics: domainservices.NewGraphAnalyticsService(),
+		logger:    logger,
+	}
+}
+
+// Handle executes the query
+func (h *GetGraphAnalyticsHandler) Handle(ctx context.Context, query queries.GetGraphAnalyticsQuery) (*queries.GetGraphAnalyticsResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	top := query.Top
+	if top == 0 {
+		top = defaultAnalyticsTop
+	}
+
+	graph, err := h.graphRepo.GetByID(ctx, aggregates.GraphID(query.GraphID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph: %w", err)
+	}
+	if graph == nil {
+		return nil, fmt.Errorf("graph not found")
+	}
+	if graph.UserID() != query.UserID {
+		return nil, fmt.Errorf("unauthorized access to graph")
+	}
+
+	nodes, err := h.nodeRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	edges, err := h.edgeRepo.GetByGraphID(ctx, query.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+
+	// The graph version does not move with every node and edge change, so
+	// the key also fingerprints the structure the analytics depend on
+	cacheKey := fmt.Sprintf("graph:analytics:%s:v%d:%x", query.GraphID, graph.Version(), structureFingerprint(nodes, edges))
+	if cachedValue, found := h.cache.Get(ctx, cacheKey); found {
+		if result, ok := cachedValue.(*queries.GetGraphAnalyticsResult); ok {
+			h.logger.Debug("Graph analytics retrieved from cache",
+				zap.String("graphID", query.GraphID))
+			return result.Truncate(top), nil
+		}
+	}
+
+	result, err := h.compute(graph, nodes, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.cache.Set(ctx, cacheKey, result, analyticsCacheTTL); err != nil {
+		h.logger.Warn("Failed to cache graph analytics",
+			zap.String("graphID", query.GraphID),
+			zap.Error(err))
+	}
+
+	return result.Truncate(top), nil
+}
+
+// compute runs the analytics over a snapshot of the graph and ranks every
+// node on every measure
+func (h *GetGraphAnalyticsHandler) compute(
+	graph *aggregates.Graph,
+	nodes []*entities.Node,
+	edges []*aggregates.Edge,
+) (*queries.GetGraphAnalyticsResult, error) {
+	snapshot, err := buildGraphSnapshot(graph, nodes, edges, h.logger)
+	if err != nil {
+		return nil, err
+	}
+	titles := snapshot.titles
+
+	analytics, err := h.analytics.Analyze(snapshot.graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze graph: %w", err)
+	}
+
+	result := &queries.GetGraphAnalyticsResult{
+		GraphID:     graph.ID().String(),
+		Version:     graph.Version(),
+		NodeCount:   len(titles),
+		EdgeCount:   snapshot.edgeCount,
+		PageRank:    rankNodes(analytics.PageRank, titles),
+		Betweenness: rankNodes(analytics.Betweenness, titles),
+		Eigenvector: rankNodes(analytics.Eigenvector, titles),
+		Degeneracy:  analytics.Degeneracy(),
+		Bridges:     make([]queries.GraphBridgeLink, 0, len(analytics.Bridges)),
+		BridgeCount: len(analytics.Bridges),
+		ComputedAt:  time.Now(),
+	}
+
+	cores := make(map[valueobjects.NodeID]float64, len(analytics.CoreNumber))
+	for id, core := range analytics.CoreNumber {
+		cores[id] = float64(core)
+	}
+	result.CoreNumber = rankNodes(cores, titles)
+
+	articulation := make(map[valueobjects.NodeID]float64, len(analytics.ArticulationPoints))
+	for _, id := range analytics.ArticulationPoints {
+		articulation[id] = analytics.Betweenness[id]
+	}
+	result.ArticulationPoints = rankNodes(articulation, titles)
+	result.ArticulationCount = len(result.ArticulationPoints)
+
+	// Bridges that split the most shortest paths are the most fragile
+	bridges := analytics.Bridges
+	sort.SliceStable(bridges, func(i, j int) bool {
+		return bridgeScore(analytics, bridges[i]) > bridgeScore(analytics, bridges[j])
+	})
+	for _, b := range bridges {
+		result.Bridges = append(result.Bridges, queries.GraphBridgeLink{
+			SourceID:    b.SourceID.String(),
+			SourceTitle: titles[b.SourceID],
+			TargetID:    b.TargetID.String(),
+			TargetTitle: titles[b.TargetID],
+		})
+	}
+
+	return result, nil
+}
+
+func bridgeScore(analytics *domainservices.GraphAnalytics, b domainservices.GraphBridge) float64 {
+	return analytics.Betweenness[b.SourceID] + analytics.Betweenness[b.TargetID]
+}
+
+// rankNodes sorts nodes by score, highest first, breaking ties by ID
+func rankNodes(scores map[valueobjects.NodeID]float64, titles map[valueobjects.NodeID]string) []queries.RankedGraphNode {
+	ranked := make([]queries.RankedGraphNode, 0, len(scores))
+	for id, score := range scores {
+		ranked = append(ranked, queries.RankedGraphNode{
+			NodeID: id.String(),
+			Title:  titles[id],
+			Score:  score,
+		})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].NodeID < ranked[j].NodeID
+	})
+	return ranked
+}
+
+// structureFingerprint hashes the nodes with their versions, so edited titles
+// show up, and the edges with their weights
+func structureFingerprint(nodes []*entities.Node, edges []*aggregates.Edge) uint64 {
+	parts := make([]string, 0, len(nodes)+len(edges))
+	for _, node := range nodes {
+		parts = append(parts, fmt.Sprintf("n:%s:%d", node.ID().String(), node.Version()))
+	}
+	for _, edge := range edges {
+		parts = append(parts, fmt.Sprintf("e:%s:%s:%g", edge.SourceID.String(), edge.TargetID.String(), edge.Weight))
+	}
+	sort.Strings(parts)
+
+	h := fnv.New64a()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
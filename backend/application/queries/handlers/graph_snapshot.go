@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"go.uber.org/zap"
+)
+
+// graphSnapshot is a graph aggregate rebuilt from the stored nodes and edges,
+// as the repository's graph may not hold them
+type graphSnapshot struct {
+	graph     *aggregates.Graph
+	titles    map[valueobjects.NodeID]string
+	edgeCount int
+}
+
+// buildGraphSnapshot loads nodes and edges into a fresh aggregate, skipping
+// the ones it rejects
+func buildGraphSnapshot(
+	graph *aggregates.Graph,
+	nodes []*entities.Node,
+	edges []*aggregates.Edge,
+	logger *zap.Logger,
+) (*graphSnapshot, error) {
+	snapshot, err := aggregates.NewGraph(graph.UserID(), graph.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graph snapshot: %w", err)
+	}
+	result := &graphSnapshot{
+		graph:  snapshot,
+		titles: make(map[valueobjects.NodeID]string, len(nodes)),
+	}
+	for _, node := range nodes {
+		if err := snapshot.LoadNode(node); err != nil {
+			logger.Warn("Failed to load node into graph", zap.Error(err))
+			continue
+		}
+		result.titles[node.ID()] = node.Content().Title()
+	}
+	for _, edge := range edges {
+		if err := snapshot.LoadEdge(edge); err != nil {
+			logger.Warn("Failed to load edge into graph", zap.Error(err))
+			continue
+		}
+		result.edgeCount++
+	}
+	return result, nil
+}
@@ -2,20 +2,28 @@ package queries
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"backend/application/services"
+	"backend/domain/search"
+	pkgerrors "backend/pkg/errors"
 )
 
 // HybridSearchQuery represents a query for hybrid BM25 + semantic search.
+// Query uses the search query language, e.g.
+// `tag:ml status:published created:>2025-01-01 "exact phrase" -excluded`.
 type HybridSearchQuery struct {
 	UserID string `json:"user_id"`
 	Query  string `json:"query"`
 	Limit  int    `json:"limit"`
 	Offset int    `json:"offset"`
+
+	parsed *search.Query
 }
 
-// Validate validates the query.
+// Validate validates the query. A query that does not parse returns a
+// *search.ParseError.
 func (q *HybridSearchQuery) Validate() error {
 	if q.UserID == "" {
 		return fmt.Errorf("user ID is required")
@@ -23,29 +31,76 @@ func (q *HybridSearchQuery) Validate() error {
 	if q.Query == "" {
 		return fmt.Errorf("search query is required")
 	}
+	parsed, err := search.Parse(q.Query)
+	if err != nil {
+		return err
+	}
+	if parsed.IsEmpty() {
+		return fmt.Errorf("search query is required")
+	}
+	q.parsed = parsed
 	if q.Limit <= 0 {
 		q.Limit = 20
 	}
 	return nil
 }
 
+// NewSearchQueryError converts a query parse error into a validation error
+// that tells the client where the query is wrong.
+func NewSearchQueryError(err error) error {
+	var parseErr *search.ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+	return pkgerrors.NewValidationError(parseErr.Error()).
+		WithCode("INVALID_SEARCH_QUERY").
+		WithDetails(map[string]interface{}{
+			"position": parseErr.Position,
+			"reason":   parseErr.Message,
+		})
+}
+
 // HybridSearchResult holds ranked search results with scoring metadata.
 type HybridSearchResult struct {
 	Results []HybridSearchResultItem `json:"results"`
 	Total   int                      `json:"total"`
 	Query   string                   `json:"query"`
+	Facets  HybridSearchFacets       `json:"facets"`
 }
 
 // HybridSearchResultItem is a single result entry.
 type HybridSearchResultItem struct {
-	NodeID        string   `json:"node_id"`
-	Title         string   `json:"title"`
-	Body          string   `json:"body"`
-	Score         float64  `json:"score"`
-	BM25Score     float64  `json:"bm25_score"`
-	SemanticScore float64  `json:"semantic_score"`
-	Sources       []string `json:"sources"`
-	Tags          []string `json:"tags"`
+	NodeID        string                `json:"node_id"`
+	Title         string                `json:"title"`
+	Body          string                `json:"body"`
+	Score         float64               `json:"score"`
+	BM25Score     float64               `json:"bm25_score"`
+	SemanticScore float64               `json:"semantic_score"`
+	Sources       []string              `json:"sources"`
+	Tags          []string              `json:"tags"`
+	Snippets      []HybridSearchSnippet `json:"snippets"`
+}
+
+// HybridSearchSnippet is a passage of a result that matched the query.
+type HybridSearchSnippet struct {
+	Field       string `json:"field"`       // "title" or "body"
+	Text        string `json:"text"`        // Plain passage
+	Highlighted string `json:"highlighted"` // HTML escaped passage with matches in <mark> tags
+}
+
+// HybridSearchFacets counts facet values over all matching nodes.
+type HybridSearchFacets struct {
+	Tags         []HybridSearchFacetValue `json:"tags"`
+	Status       []HybridSearchFacetValue `json:"status"`
+	Community    []HybridSearchFacetValue `json:"community"`
+	Format       []HybridSearchFacetValue `json:"format"`
+	CreatedMonth []HybridSearchFacetValue `json:"created_month"`
+}
+
+// HybridSearchFacetValue is one facet value with its number of matches.
+type HybridSearchFacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
 }
 
 // HybridSearchHandler handles hybrid search queries using the domain search service.
@@ -65,18 +120,30 @@ func (h *HybridSearchHandler) Handle(ctx context.Context, query interface{}) (in
 		return nil, fmt.Errorf("invalid query type")
 	}
 
-	results, err := h.searchService.Search(ctx, q.UserID, q.Query, q.Limit+q.Offset)
+	parsed := q.parsed
+	if parsed == nil {
+		var err error
+		if parsed, err = search.Parse(q.Query); err != nil {
+			return nil, NewSearchQueryError(err)
+		}
+	}
+
+	response, err := h.searchService.SearchPage(ctx, q.UserID, parsed, q.Limit+q.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
+	result := &HybridSearchResult{
+		Results: []HybridSearchResultItem{},
+		Total:   response.Total,
+		Query:   q.Query,
+		Facets:  toSearchFacets(response.Facets),
+	}
+
 	// Apply offset
+	results := response.Results
 	if q.Offset >= len(results) {
-		return &HybridSearchResult{
-			Results: []HybridSearchResultItem{},
-			Total:   len(results),
-			Query:   q.Query,
-		}, nil
+		return result, nil
 	}
 	results = results[q.Offset:]
 	if len(results) > q.Limit {
@@ -87,6 +154,10 @@ func (h *HybridSearchHandler) Handle(ctx context.Context, query interface{}) (in
 	items := make([]HybridSearchResultItem, len(results))
 	for i, r := range results {
 		content := r.Node.Content()
+		snippets := make([]HybridSearchSnippet, len(r.Snippets))
+		for j, sn := range r.Snippets {
+			snippets[j] = HybridSearchSnippet{Field: sn.Field, Text: sn.Text, Highlighted: sn.Highlighted}
+		}
 		items[i] = HybridSearchResultItem{
 			NodeID:        r.Node.ID().String(),
 			Title:         content.Title(),
@@ -96,12 +167,27 @@ func (h *HybridSearchHandler) Handle(ctx context.Context, query interface{}) (in
 			SemanticScore: r.SemanticScore,
 			Sources:       r.Sources,
 			Tags:          r.Node.GetTags(),
+			Snippets:      snippets,
 		}
 	}
+	result.Results = items
 
-	return &HybridSearchResult{
-		Results: items,
-		Total:   len(results) + q.Offset, // Approximate total
-		Query:   q.Query,
-	}, nil
+	return result, nil
+}
+
+func toSearchFacets(facets *search.Facets) HybridSearchFacets {
+	convert := func(counts []search.FacetCount) []HybridSearchFacetValue {
+		values := make([]HybridSearchFacetValue, len(counts))
+		for i, c := range counts {
+			values[i] = HybridSearchFacetValue{Value: c.Value, Count: c.Count}
+		}
+		return values
+	}
+	return HybridSearchFacets{
+		Tags:         convert(facets.Tags),
+		Status:       convert(facets.Status),
+		Community:    convert(facets.Community),
+		Format:       convert(facets.Format),
+		CreatedMonth: convert(facets.CreatedMonth),
+	}
 }
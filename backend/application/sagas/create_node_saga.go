@@ -13,7 +13,7 @@ import (
 	"backend/domain/events"
 	domainservices "backend/domain/services"
 	"backend/infrastructure/config"
-	"backend/infrastructure/persistence/dynamodb"
+	pkgerrors "backend/pkg/errors"
 
 	"go.uber.org/zap"
 )
@@ -28,6 +28,9 @@ type CreateNodeSagaData struct {
 	X, Y, Z  float64
 	Metadata map[string]interface{}
 
+	// Graph to create the node in; empty uses the user's default graph
+	GraphID string
+
 	// Operation tracking
 	OperationID string
 	StartTime   time.Time
@@ -35,10 +38,9 @@ type CreateNodeSagaData struct {
 	// State between steps
 	Graph           *aggregates.Graph
 	LazyGraph       *aggregates.GraphLazy
-	GraphID         string
 	Node            *entities.Node
 	IsLazyMode      bool
-	Lock            *dynamodb.Lock
+	Lock            ports.Lock
 	SyncEdges       []aggregates.EdgeCandidate
 	AsyncCandidates []aggregates.EdgeCandidate
 	CreatedEdgeIDs  []string
@@ -60,11 +62,12 @@ type CreateNodeSaga struct {
 	edgeService      *services.EdgeService
 	graphLazyService *services.GraphLazyService
 	eventPublisher   ports.EventPublisher
-	distributedLock  *dynamodb.DistributedLock
+	distributedLock  ports.DistributedLock
 	operationStore   ports.OperationStore
 	edgeConfig       *config.EdgeCreationConfig
 	appConfig        *config.Config
 	logger           *zap.Logger
+	maxNodesPerGraph int
 }
 
 // NewCreateNodeSaga creates a new create node saga
@@ -76,7 +79,7 @@ func NewCreateNodeSaga(
 	edgeService *services.EdgeService,
 	graphLazyService *services.GraphLazyService,
 	eventPublisher ports.EventPublisher,
-	distributedLock *dynamodb.DistributedLock,
+	distributedLock ports.DistributedLock,
 	operationStore ports.OperationStore,
 	edgeConfig *config.EdgeCreationConfig,
 	appConfig *config.Config,
@@ -98,6 +101,12 @@ func NewCreateNodeSaga(
 	}
 }
 
+// WithNodeLimit caps the number of nodes a graph may hold; 0 disables the cap
+func (cns *CreateNodeSaga) WithNodeLimit(maxNodes int) *CreateNodeSaga {
+	cns.maxNodesPerGraph = maxNodes
+	return cns
+}
+
 // BuildSaga constructs the saga with all steps
 func (cns *CreateNodeSaga) BuildSaga(operationID string) *Saga {
 	return NewSagaBuilder("CreateNode", cns.logger).
@@ -220,7 +229,14 @@ func (cns *CreateNodeSaga) ensureGraph(ctx context.Context, data interface{}) (i
 	// Determine if we should use lazy loading
 	d.IsLazyMode = cns.appConfig.EnableLazyLoading && cns.graphLazyService != nil
 	
-	if d.IsLazyMode {
+	if d.GraphID != "" {
+		// The caller picked the graph, which must exist and belong to the user
+		graph, err := cns.graphRepo.GetByID(ctx, aggregates.GraphID(d.GraphID))
+		if err != nil || graph == nil || graph.UserID() != d.UserID {
+			return nil, pkgerrors.NewNotFoundError("graph " + d.GraphID)
+		}
+		d.Graph = graph
+	} else if d.IsLazyMode {
 		// Use lazy-loaded graph
 		cns.logger.Info("Using lazy-loaded graph for node creation",
 			zap.String("user_id", d.UserID),
@@ -237,8 +253,17 @@ func (cns *CreateNodeSaga) ensureGraph(ctx context.Context, data interface{}) (i
 		}
 		
 		d.Graph = defaultGraph
-		d.GraphID = string(defaultGraph.ID())
-		
+	} else {
+		// Use regular graph loading
+		graph, err := cns.ensureGraphWithLock(ctx, d.UserID)
+		if err != nil {
+			return nil, err
+		}
+		d.Graph = graph
+	}
+	d.GraphID = string(d.Graph.ID())
+	
+	if d.IsLazyMode {
 		// Register with lazy service
 		lazyGraph, err := cns.graphLazyService.GetOrCreateForUser(ctx, d.UserID, d.GraphID)
 		if err != nil {
@@ -249,14 +274,10 @@ func (cns *CreateNodeSaga) ensureGraph(ctx context.Context, data interface{}) (i
 		} else {
 			d.LazyGraph = lazyGraph
 		}
-	} else {
-		// Use regular graph loading
-		graph, err := cns.ensureGraphWithLock(ctx, d.UserID)
-		if err != nil {
-			return nil, err
-		}
-		d.Graph = graph
-		d.GraphID = string(graph.ID())
+	}
+	
+	if err := ensureGraphCapacity(ctx, cns.nodeRepo, d.GraphID, 1, cns.maxNodesPerGraph); err != nil {
+		return nil, err
 	}
 	
 	return d, nil
@@ -548,7 +569,7 @@ func (cns *CreateNodeSaga) compensateEdges(ctx context.Context, data interface{}
 func (cns *CreateNodeSaga) updateGraph(ctx context.Context, data interface{}) (interface{}, error) {
 	d := data.(*CreateNodeSagaData)
 	
-	if d.IsLazyMode {
+	if d.IsLazyMode && !d.GraphCreated {
 		// No need to save entire graph in lazy mode
 		return d, nil
 	}
@@ -690,7 +711,7 @@ func (cns *CreateNodeSaga) ensureGraphWithLock(ctx context.Context, userID strin
 	
 	// Need to create with lock
 	lockResource := fmt.Sprintf("default_graph_creation_%s", userID)
-	lock, err := cns.distributedLock.TryAcquireLock(
+	lock, err := cns.distributedLock.TryAcquire(
 		ctx, lockResource, userID, 30*time.Second, 5*time.Second,
 	)
 	if err != nil {
@@ -0,0 +1,45 @@
+package sagas
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// nodeCounter is implemented by node repositories that can count a graph's
+// nodes without loading them
+type nodeCounter interface {
+	CountNodesByGraph(ctx context.Context, graphID string) (int64, error)
+}
+
+// ensureGraphCapacity rejects adding nodes that would take a graph past
+// maxNodes. A limit of 0 or less disables the check.
+func ensureGraphCapacity(ctx context.Context, nodeRepo ports.NodeRepository, graphID string, adding, maxNodes int) error {
+	if maxNodes <= 0 || adding <= 0 {
+		return nil
+	}
+
+	var count int
+	if counter, ok := nodeRepo.(nodeCounter); ok {
+		n, err := counter.CountNodesByGraph(ctx, graphID)
+		if err != nil {
+			return fmt.Errorf("failed to count graph nodes: %w", err)
+		}
+		count = int(n)
+	} else {
+		nodes, err := nodeRepo.GetByGraphID(ctx, graphID)
+		if err != nil {
+			return fmt.Errorf("failed to count graph nodes: %w", err)
+		}
+		count = len(nodes)
+	}
+
+	if count+adding > maxNodes {
+		return pkgerrors.NewValidationError(fmt.Sprintf(
+			"maximum nodes reached: graph has %d nodes, adding %d would exceed the limit of %d",
+			count, adding, maxNodes))
+	}
+	return nil
+}
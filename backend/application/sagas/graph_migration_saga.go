@@ -9,35 +9,58 @@ import (
 	"backend/domain/core/aggregates"
 	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // GraphMigrationSaga handles the complex process of migrating a graph
-// This includes copying all nodes, edges, and metadata from one graph to another
+// This includes transferring nodes, the edges between them, and metadata from
+// one graph to another. Copies get new IDs; a move re-keys the nodes and their
+// edges into the target graph under the IDs they already have.
 type GraphMigrationSaga struct {
 	saga          *Saga
 	sourceGraphID string
 	targetGraphID string
+	options       GraphMigrationOptions
 	nodeRepo      ports.NodeRepository
 	edgeRepo      ports.EdgeRepository
 	graphRepo     ports.GraphRepository
 	logger        *zap.Logger
 
 	// Track migrated entities for rollback
-	migratedNodes []string
-	migratedEdges []string
-	nodeMapping   map[string]string // Maps old node IDs to new node IDs
+	migratedNodes []*entities.Node
+	migratedEdges []*aggregates.Edge
+	removedEdges  []*aggregates.Edge
+	nodeMapping   map[string]string // Maps source node IDs to target node IDs
+	communities   map[string]string // Community of each moved node in the source graph
+}
+
+// nodeGraphMover is implemented by node repositories that key nodes by graph,
+// where moving a node replaces its stored item instead of updating it
+type nodeGraphMover interface {
+	MoveToGraph(ctx context.Context, node *entities.Node, fromGraphID string) error
+}
+
+// GraphMigrationOptions narrows down what a migration does
+type GraphMigrationOptions struct {
+	UserID           string   // Owner both graphs must belong to; empty skips the check
+	NodeIDs          []string // Nodes to migrate; empty migrates the whole source graph
+	Move             bool     // Move the nodes, keeping their IDs, instead of copying them
+	MaxNodesPerGraph int      // Node limit of the target graph; 0 disables it
 }
 
 // GraphMigrationData holds data passed between saga steps
 type GraphMigrationData struct {
 	SourceGraph    *aggregates.Graph
 	TargetGraph    *aggregates.Graph
-	Nodes          []*entities.Node
-	Edges          []*aggregates.Edge
-	NodeMapping    map[string]string
+	Nodes          []*entities.Node   // Nodes being migrated
+	Edges          []*aggregates.Edge // Source edges touching the migrated nodes
+	NodeMapping    map[string]string  // Source node IDs to target node IDs; identical on a move
+	CopiedEdges    int
+	SkippedEdges   int                // Number of crossing edges
+	CrossingEdges  []*aggregates.Edge // Edges to nodes outside the migration; not carried over, and removed from the source graph on a move
 	StartTime      time.Time
 	CompletedSteps int
 }
@@ -45,6 +68,7 @@ type GraphMigrationData struct {
 // NewGraphMigrationSaga creates a new graph migration saga
 func NewGraphMigrationSaga(
 	sourceID, targetID string,
+	options GraphMigrationOptions,
 	nodeRepo ports.NodeRepository,
 	edgeRepo ports.EdgeRepository,
 	graphRepo ports.GraphRepository,
@@ -53,23 +77,27 @@ func NewGraphMigrationSaga(
 	gms := &GraphMigrationSaga{
 		sourceGraphID: sourceID,
 		targetGraphID: targetID,
+		options:       options,
 		nodeRepo:      nodeRepo,
 		edgeRepo:      edgeRepo,
 		graphRepo:     graphRepo,
 		logger:        logger,
-		migratedNodes: make([]string, 0),
-		migratedEdges: make([]string, 0),
+		migratedNodes: make([]*entities.Node, 0),
+		migratedEdges: make([]*aggregates.Edge, 0),
 		nodeMapping:   make(map[string]string),
+		communities:   make(map[string]string),
 	}
 
 	// Build the saga with all steps
 	gms.saga = NewSagaBuilder("GraphMigration", logger).
 		WithMetadata("source_graph_id", sourceID).
 		WithMetadata("target_graph_id", targetID).
+		WithMetadata("move", options.Move).
 		WithCompensableStep("ValidateGraphs", gms.validateGraphs, gms.compensateValidation).
-		WithCompensableStep("CopyNodes", gms.copyNodes, gms.compensateNodes).
-		WithCompensableStep("CopyEdges", gms.copyEdges, gms.compensateEdges).
-		WithCompensableStep("UpdateMetadata", gms.updateMetadata, gms.compensateMetadata).
+		WithCompensableStep("TransferNodes", gms.transferNodes, gms.compensateNodes).
+		WithCompensableStep("TransferEdges", gms.transferEdges, gms.compensateEdges).
+		WithCompensableStep("RemoveSourceEdges", gms.removeSourceEdges, gms.compensateRemovedSourceEdges).
+		WithStep("UpdateMetadata", gms.updateMetadata).
 		WithStep("FinalizeMigration", gms.finalizeMigration).
 		Build()
 
@@ -77,70 +105,88 @@ func NewGraphMigrationSaga(
 }
 
 // Execute runs the graph migration saga
-func (gms *GraphMigrationSaga) Execute(ctx context.Context) error {
+func (gms *GraphMigrationSaga) Execute(ctx context.Context) (*GraphMigrationData, error) {
 	initialData := &GraphMigrationData{
 		NodeMapping: make(map[string]string),
 		StartTime:   time.Now(),
 	}
 
-	_, err := gms.saga.Execute(ctx, initialData)
+	result, err := gms.saga.Execute(ctx, initialData)
 	if err != nil {
 		gms.logger.Error("Graph migration failed",
 			zap.String("source_graph_id", gms.sourceGraphID),
 			zap.String("target_graph_id", gms.targetGraphID),
 			zap.Error(err),
 		)
-		return err
+		return nil, err
 	}
 
 	gms.logger.Info("Graph migration completed successfully",
 		zap.String("source_graph_id", gms.sourceGraphID),
 		zap.String("target_graph_id", gms.targetGraphID),
+		zap.Bool("move", gms.options.Move),
 		zap.Int("migrated_nodes", len(gms.migratedNodes)),
 		zap.Int("migrated_edges", len(gms.migratedEdges)),
 	)
 
-	return nil
+	return result.(*GraphMigrationData), nil
 }
 
-// Step 1: Validate both graphs exist and are accessible
+// Step 1: Validate both graphs exist and are accessible, and pick the nodes
+// to migrate
 func (gms *GraphMigrationSaga) validateGraphs(ctx context.Context, data interface{}) (interface{}, error) {
 	migrationData := data.(*GraphMigrationData)
 
-	// Validate source graph
-	sourceGraphID := aggregates.GraphID(gms.sourceGraphID)
+	if gms.sourceGraphID == gms.targetGraphID {
+		return nil, pkgerrors.NewValidationError("source and target graph must differ")
+	}
 
-	sourceGraph, err := gms.graphRepo.GetByID(ctx, sourceGraphID)
+	sourceGraph, err := gms.getGraph(ctx, gms.sourceGraphID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get source graph: %w", err)
 	}
 	migrationData.SourceGraph = sourceGraph
 
-	// Validate target graph
-	targetGraphID := aggregates.GraphID(gms.targetGraphID)
-
-	targetGraph, err := gms.graphRepo.GetByID(ctx, targetGraphID)
+	targetGraph, err := gms.getGraph(ctx, gms.targetGraphID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get target graph: %w", err)
 	}
 	migrationData.TargetGraph = targetGraph
 
-	// Check if target graph is empty (optional safety check)
-	metadata := targetGraph.Metadata()
-	if nodeCount, ok := metadata["nodeCount"].(int); ok && nodeCount > 0 {
-		gms.logger.Warn("Target graph is not empty",
-			zap.String("target_graph_id", gms.targetGraphID),
-			zap.Int("node_count", nodeCount),
-		)
+	nodes, err := gms.nodeRepo.GetByGraphID(ctx, gms.sourceGraphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes from source graph: %w", err)
+	}
+	if len(gms.options.NodeIDs) > 0 {
+		byID := make(map[string]*entities.Node, len(nodes))
+		for _, node := range nodes {
+			byID[node.ID().String()] = node
+		}
+		selected := make([]*entities.Node, 0, len(gms.options.NodeIDs))
+		seen := make(map[string]bool, len(gms.options.NodeIDs))
+		for _, id := range gms.options.NodeIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			node, ok := byID[id]
+			if !ok {
+				return nil, pkgerrors.NewNotFoundError(fmt.Sprintf("node %s in graph %s", id, gms.sourceGraphID))
+			}
+			selected = append(selected, node)
+		}
+		nodes = selected
 	}
+	migrationData.Nodes = nodes
 
-	sourceMetadata := sourceGraph.Metadata()
-	sourceNodeCount, _ := sourceMetadata["nodeCount"].(int)
-	sourceEdgeCount, _ := sourceMetadata["edgeCount"].(int)
+	if err := ensureGraphCapacity(ctx, gms.nodeRepo, gms.targetGraphID, len(nodes), gms.options.MaxNodesPerGraph); err != nil {
+		return nil, err
+	}
 
 	gms.logger.Info("Graphs validated",
-		zap.String("source_nodes", fmt.Sprintf("%d", sourceNodeCount)),
-		zap.String("source_edges", fmt.Sprintf("%d", sourceEdgeCount)),
+		zap.String("source_graph_id", gms.sourceGraphID),
+		zap.String("target_graph_id", gms.targetGraphID),
+		zap.Int("nodes", len(nodes)),
 	)
 
 	return migrationData, nil
@@ -151,53 +197,48 @@ func (gms *GraphMigrationSaga) compensateValidation(ctx context.Context, data in
 	return nil
 }
 
-// Step 2: Copy all nodes from source to target
-func (gms *GraphMigrationSaga) copyNodes(ctx context.Context, data interface{}) (interface{}, error) {
+// Step 2: Copy the nodes into the target graph, or move them there under
+// their own IDs
+func (gms *GraphMigrationSaga) transferNodes(ctx context.Context, data interface{}) (interface{}, error) {
 	migrationData := data.(*GraphMigrationData)
 
-	// Get all nodes from source graph
-	nodes, err := gms.nodeRepo.GetByGraphID(ctx, gms.sourceGraphID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nodes from source graph: %w", err)
-	}
-
-	migrationData.Nodes = nodes
-	gms.logger.Info("Copying nodes", zap.Int("count", len(nodes)))
-
-	// Copy each node to the target graph
-	for _, node := range nodes {
-		// Create new node with new ID but same content
-		newNodeID := valueobjects.NewNodeID()
-
-		// Create new node with same content but new ID and graph
-		newNode, err := entities.NewNode(
-			newNodeID.String(),
-			node.Content(),
-			node.Position(),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create new node: %w", err)
-		}
-
-		// Copy tags (assuming tags are part of the node structure)
-		// Tags might need to be handled differently based on implementation
-
-		// Copy metadata if the node has a way to expose it
-		// Metadata handling would depend on the actual Node entity structure
+	gms.logger.Info("Transferring nodes",
+		zap.Int("count", len(migrationData.Nodes)),
+		zap.Bool("move", gms.options.Move),
+	)
 
-		// Save the new node
-		if err := gms.nodeRepo.Save(ctx, newNode); err != nil {
-			return nil, fmt.Errorf("failed to save migrated node: %w", err)
+	for _, node := range migrationData.Nodes {
+		transferred := node
+		if gms.options.Move {
+			gms.communities[node.ID().String()] = node.CommunityID()
+			node.MoveToGraph(gms.targetGraphID)
+			if err := gms.moveNode(ctx, node, gms.sourceGraphID); err != nil {
+				node.MoveToGraph(gms.sourceGraphID)
+				node.SetCommunityID(gms.communities[node.ID().String()])
+				gms.compensateNodes(ctx, migrationData)
+				return nil, fmt.Errorf("failed to move node %s: %w", node.ID().String(), err)
+			}
+		} else {
+			// Same content, tags and metadata under a new ID in the target graph
+			copied, err := node.CopyToGraph(gms.targetGraphID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to copy node %s: %w", node.ID().String(), err)
+			}
+			if err := gms.nodeRepo.Save(ctx, copied); err != nil {
+				gms.compensateNodes(ctx, migrationData)
+				return nil, fmt.Errorf("failed to save migrated node: %w", err)
+			}
+			transferred = copied
 		}
 
 		// Track migration
-		gms.migratedNodes = append(gms.migratedNodes, newNode.ID().String())
-		gms.nodeMapping[node.ID().String()] = newNode.ID().String()
-		migrationData.NodeMapping[node.ID().String()] = newNode.ID().String()
+		gms.migratedNodes = append(gms.migratedNodes, transferred)
+		gms.nodeMapping[node.ID().String()] = transferred.ID().String()
+		migrationData.NodeMapping[node.ID().String()] = transferred.ID().String()
 
 		gms.logger.Debug("Node migrated",
 			zap.String("old_id", node.ID().String()),
-			zap.String("new_id", newNode.ID().String()),
+			zap.String("new_id", transferred.ID().String()),
 		)
 	}
 
@@ -207,81 +248,97 @@ func (gms *GraphMigrationSaga) copyNodes(ctx context.Context, data interface{})
 func (gms *GraphMigrationSaga) compensateNodes(ctx context.Context, data interface{}) error {
 	gms.logger.Info("Compensating node migration", zap.Int("count", len(gms.migratedNodes)))
 
-	// Delete all migrated nodes
-	for _, nodeID := range gms.migratedNodes {
-		nodeIDObj, err := valueobjects.NewNodeIDFromString(nodeID)
-		if err != nil {
-			gms.logger.Error("Failed to parse node ID during compensation", zap.String("node_id", nodeID))
-			continue
+	for _, node := range gms.migratedNodes {
+		var err error
+		if gms.options.Move {
+			// Move the node back where it came from
+			node.MoveToGraph(gms.sourceGraphID)
+			node.SetCommunityID(gms.communities[node.ID().String()])
+			err = gms.moveNode(ctx, node, gms.targetGraphID)
+		} else {
+			err = gms.nodeRepo.Delete(ctx, node.ID())
 		}
-
-		if err := gms.nodeRepo.Delete(ctx, nodeIDObj); err != nil {
-			gms.logger.Error("Failed to delete migrated node during compensation",
-				zap.String("node_id", nodeID),
+		if err != nil {
+			gms.logger.Error("Failed to undo node migration during compensation",
+				zap.String("node_id", node.ID().String()),
 				zap.Error(err),
 			)
 		}
 	}
+	gms.migratedNodes = gms.migratedNodes[:0]
 
 	return nil
 }
 
-// Step 3: Copy all edges with updated node references
-func (gms *GraphMigrationSaga) copyEdges(ctx context.Context, data interface{}) (interface{}, error) {
+// moveNode stores a node whose graph changed from fromGraphID
+func (gms *GraphMigrationSaga) moveNode(ctx context.Context, node *entities.Node, fromGraphID string) error {
+	if mover, ok := gms.nodeRepo.(nodeGraphMover); ok {
+		return mover.MoveToGraph(ctx, node, fromGraphID)
+	}
+	return gms.nodeRepo.Save(ctx, node)
+}
+
+// Step 3: Carry the edges between migrated nodes over to the target graph.
+// Copied edges get new IDs and point at the copies; moved edges keep theirs.
+// Edges to nodes that stay behind are reported as crossing edges.
+func (gms *GraphMigrationSaga) transferEdges(ctx context.Context, data interface{}) (interface{}, error) {
 	migrationData := data.(*GraphMigrationData)
 
-	// Get all edges from source graph
 	edges, err := gms.edgeRepo.GetByGraphID(ctx, gms.sourceGraphID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get edges from source graph: %w", err)
 	}
 
-	migrationData.Edges = edges
-	gms.logger.Info("Copying edges", zap.Int("count", len(edges)))
-
-	// Copy each edge with updated node references
+	migrationData.Edges = make([]*aggregates.Edge, 0)
+	migrationData.CrossingEdges = make([]*aggregates.Edge, 0)
 	for _, edge := range edges {
-		// Map old node IDs to new ones
 		newSourceID, sourceExists := gms.nodeMapping[edge.SourceID.String()]
 		newTargetID, targetExists := gms.nodeMapping[edge.TargetID.String()]
+		if !sourceExists && !targetExists {
+			continue
+		}
+		migrationData.Edges = append(migrationData.Edges, edge)
 
 		if !sourceExists || !targetExists {
-			gms.logger.Warn("Skipping edge with unmapped nodes",
+			migrationData.CrossingEdges = append(migrationData.CrossingEdges, edge)
+			migrationData.SkippedEdges++
+			gms.logger.Debug("Skipping edge to node outside the migration",
 				zap.String("source", edge.SourceID.String()),
 				zap.String("target", edge.TargetID.String()),
 			)
 			continue
 		}
 
-		// Create new edge with mapped node IDs
-		newSourceNodeID, _ := valueobjects.NewNodeIDFromString(newSourceID)
-		newTargetNodeID, _ := valueobjects.NewNodeIDFromString(newTargetID)
-
-		newEdge := &aggregates.Edge{
-			ID:        fmt.Sprintf("%s", uuid.New()),
-			SourceID:  newSourceNodeID,
-			TargetID:  newTargetNodeID,
-			Type:      edge.Type,
-			Weight:    edge.Weight,
-			CreatedAt: time.Now(),
-			Metadata:  make(map[string]interface{}),
-		}
+		newEdge := edge
+		if !gms.options.Move {
+			newSourceNodeID, _ := valueobjects.NewNodeIDFromString(newSourceID)
+			newTargetNodeID, _ := valueobjects.NewNodeIDFromString(newTargetID)
+
+			newEdge = &aggregates.Edge{
+				ID:            uuid.New().String(),
+				SourceID:      newSourceNodeID,
+				TargetID:      newTargetNodeID,
+				Type:          edge.Type,
+				Weight:        edge.Weight,
+				Bidirectional: edge.Bidirectional,
+				CreatedAt:     time.Now(),
+				Metadata:      make(map[string]interface{}),
+			}
 
-		// Copy edge metadata if any
-		if edge.Metadata != nil {
+			// Copy edge metadata if any
 			for key, value := range edge.Metadata {
 				newEdge.Metadata[key] = value
 			}
 		}
 
-		// Save the new edge
 		if err := gms.edgeRepo.Save(ctx, gms.targetGraphID, newEdge); err != nil {
+			gms.compensateEdges(ctx, migrationData)
 			return nil, fmt.Errorf("failed to save migrated edge: %w", err)
 		}
 
 		// Track migration
-		edgeID := fmt.Sprintf("%s->%s", newSourceID, newTargetID)
-		gms.migratedEdges = append(gms.migratedEdges, edgeID)
+		gms.migratedEdges = append(gms.migratedEdges, newEdge)
+		migrationData.CopiedEdges++
 
 		gms.logger.Debug("Edge migrated",
 			zap.String("old_source", edge.SourceID.String()),
@@ -291,6 +348,11 @@ func (gms *GraphMigrationSaga) copyEdges(ctx context.Context, data interface{})
 		)
 	}
 
+	gms.logger.Info("Edges transferred",
+		zap.Int("transferred", migrationData.CopiedEdges),
+		zap.Int("crossing", len(migrationData.CrossingEdges)),
+	)
+
 	return migrationData, nil
 }
 
@@ -298,60 +360,77 @@ func (gms *GraphMigrationSaga) compensateEdges(ctx context.Context, data interfa
 	gms.logger.Info("Compensating edge migration", zap.Int("count", len(gms.migratedEdges)))
 
 	// Delete all migrated edges
-	for _, edgeID := range gms.migratedEdges {
-		// Parse edge ID (format: source->target)
-		var source, target string
-		fmt.Sscanf(edgeID, "%s->%s", &source, &target)
-
-		if err := gms.edgeRepo.Delete(ctx, gms.targetGraphID, source, target); err != nil {
+	for _, edge := range gms.migratedEdges {
+		if err := gms.edgeRepo.Delete(ctx, gms.targetGraphID, edge.SourceID.String(), edge.TargetID.String()); err != nil {
 			gms.logger.Error("Failed to delete migrated edge during compensation",
-				zap.String("edge_id", edgeID),
+				zap.String("edge_id", edge.ID),
 				zap.Error(err),
 			)
 		}
 	}
+	gms.migratedEdges = gms.migratedEdges[:0]
 
 	return nil
 }
 
-// Step 4: Update graph metadata
-func (gms *GraphMigrationSaga) updateMetadata(ctx context.Context, data interface{}) (interface{}, error) {
+// Step 4: When moving, remove the edges of the moved nodes from the source
+// graph. Crossing edges go too: their other end is no longer in the graph.
+func (gms *GraphMigrationSaga) removeSourceEdges(ctx context.Context, data interface{}) (interface{}, error) {
 	migrationData := data.(*GraphMigrationData)
-
-	// Update target graph metadata
-	targetGraph := migrationData.TargetGraph
-
-	// Note: The graph's metadata is updated automatically when nodes are added
-	// No need to manually update counts
-
-	// Update migration metadata
-	// Store migration metadata separately as the graph doesn't have a SetMetadata method
-	// This would typically be stored in a separate migration audit table
-	migrationMetadata := map[string]interface{}{
-		"migrated_from":  gms.sourceGraphID,
-		"migration_date": time.Now().Format(time.RFC3339),
-		"migrated_nodes": len(gms.migratedNodes),
-		"migrated_edges": len(gms.migratedEdges),
+	if !gms.options.Move || len(migrationData.Edges) == 0 {
+		return migrationData, nil
 	}
-	migrationData.TargetGraph = targetGraph // Update the graph reference
-	gms.logger.Info("Migration metadata prepared", zap.Any("metadata", migrationMetadata))
 
-	// Save updated graph
-	if err := gms.graphRepo.Save(ctx, targetGraph); err != nil {
-		return nil, fmt.Errorf("failed to update target graph metadata: %w", err)
+	nodeIDs := make([]string, 0, len(migrationData.Nodes))
+	for _, node := range migrationData.Nodes {
+		nodeIDs = append(nodeIDs, node.ID().String())
 	}
+	if err := gms.edgeRepo.DeleteByNodeIDs(ctx, gms.sourceGraphID, nodeIDs); err != nil {
+		return nil, fmt.Errorf("failed to remove edges of moved nodes: %w", err)
+	}
+	gms.removedEdges = migrationData.Edges
 
 	return migrationData, nil
 }
 
-func (gms *GraphMigrationSaga) compensateMetadata(ctx context.Context, data interface{}) error {
-	// Since we don't have direct metadata manipulation on the graph,
-	// compensation would typically involve logging or updating external metadata store
-	gms.logger.Info("Compensating metadata changes")
+func (gms *GraphMigrationSaga) compensateRemovedSourceEdges(ctx context.Context, data interface{}) error {
+	gms.logger.Info("Compensating source edge removal", zap.Int("edges", len(gms.removedEdges)))
+
+	for _, edge := range gms.removedEdges {
+		if err := gms.edgeRepo.Save(ctx, gms.sourceGraphID, edge); err != nil {
+			gms.logger.Error("Failed to restore edge during compensation",
+				zap.String("edge_id", edge.ID),
+				zap.Error(err),
+			)
+		}
+	}
+	gms.removedEdges = nil
+
 	return nil
 }
 
-// Step 5: Finalize migration
+// Step 5: Update graph metadata. The counts are derived from stored state, so
+// a failure here leaves them stale rather than wrong and is not compensated.
+func (gms *GraphMigrationSaga) updateMetadata(ctx context.Context, data interface{}) (interface{}, error) {
+	migrationData := data.(*GraphMigrationData)
+
+	graphIDs := []string{gms.targetGraphID}
+	if gms.options.Move {
+		graphIDs = append(graphIDs, gms.sourceGraphID)
+	}
+	for _, graphID := range graphIDs {
+		if err := gms.graphRepo.UpdateGraphMetadata(ctx, graphID); err != nil {
+			gms.logger.Warn("Failed to update graph metadata after migration",
+				zap.String("graph_id", graphID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return migrationData, nil
+}
+
+// Step 6: Finalize migration
 func (gms *GraphMigrationSaga) finalizeMigration(ctx context.Context, data interface{}) (interface{}, error) {
 	migrationData := data.(*GraphMigrationData)
 
@@ -360,14 +439,20 @@ func (gms *GraphMigrationSaga) finalizeMigration(ctx context.Context, data inter
 		zap.String("duration", duration.String()),
 		zap.Int("nodes_migrated", len(gms.migratedNodes)),
 		zap.Int("edges_migrated", len(gms.migratedEdges)),
-	)
-
-	// Since the graph doesn't have a SetMetadata method, we would handle archiving differently
-	// This could be done through a separate archival service or by updating a status field
-	gms.logger.Info("Migration finalized - source graph should be marked as archived in external system",
-		zap.String("source_graph", gms.sourceGraphID),
-		zap.String("target_graph", gms.targetGraphID),
+		zap.Int("edges_crossing", len(migrationData.CrossingEdges)),
 	)
 
 	return migrationData, nil
 }
+
+// getGraph loads a graph, reporting graphs of other users as not found
+func (gms *GraphMigrationSaga) getGraph(ctx context.Context, graphID string) (*aggregates.Graph, error) {
+	graph, err := gms.graphRepo.GetByID(ctx, aggregates.GraphID(graphID))
+	if err != nil || graph == nil {
+		return nil, pkgerrors.NewNotFoundError("graph " + graphID)
+	}
+	if gms.options.UserID != "" && graph.UserID() != gms.options.UserID {
+		return nil, pkgerrors.NewNotFoundError("graph " + graphID)
+	}
+	return graph, nil
+}
@@ -0,0 +1,372 @@
+package sagas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/versioning"
+
+	"go.uber.org/zap"
+)
+
+// GraphRollbackSaga restores a graph to one of its stored versions.
+// The current state is recorded as a version first, then nodes and edges are
+// changed to match the target version. A failed step undoes its own partial
+// work and the saga compensates the steps before it, which puts back the
+// nodes and edges they changed.
+type GraphRollbackSaga struct {
+	saga          *Saga
+	userID        string
+	graphID       string
+	targetVersion int
+	versions      *services.GraphVersionService
+	nodeRepo      ports.NodeRepository
+	edgeRepo      ports.EdgeRepository
+	graphRepo     ports.GraphRepository
+	logger        *zap.Logger
+
+	// Track applied changes for compensation
+	removedEdges  []*aggregates.Edge
+	removedNodes  []*entities.Node
+	replacedNodes []*entities.Node // Stored state of nodes overwritten from the target
+	createdNodes  []*entities.Node
+	addedEdges    []*aggregates.Edge
+}
+
+// GraphRollbackData holds data passed between saga steps
+type GraphRollbackData struct {
+	Target     *versioning.GraphSnapshot
+	Nodes      []*entities.Node   // Nodes of the graph before the rollback
+	Edges      []*aggregates.Edge // Edges of the graph before the rollback
+	Checkpoint *versioning.GraphVersion
+	Result     *versioning.GraphVersion
+	StartTime  time.Time
+}
+
+// NewGraphRollbackSaga creates a new graph rollback saga
+func NewGraphRollbackSaga(
+	userID, graphID string,
+	targetVersion int,
+	versions *services.GraphVersionService,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	logger *zap.Logger,
+) *GraphRollbackSaga {
+	grs := &GraphRollbackSaga{
+		userID:        userID,
+		graphID:       graphID,
+		targetVersion: targetVersion,
+		versions:      versions,
+		nodeRepo:      nodeRepo,
+		edgeRepo:      edgeRepo,
+		graphRepo:     graphRepo,
+		logger:        logger,
+	}
+
+	// Edges go before the nodes they connect and come back after them
+	grs.saga = NewSagaBuilder("GraphRollback", logger).
+		WithMetadata("graph_id", graphID).
+		WithMetadata("target_version", targetVersion).
+		WithStep("LoadVersion", grs.loadVersion).
+		WithStep("CheckpointCurrentState", grs.checkpointCurrentState).
+		WithCompensableStep("RemoveEdges", grs.removeEdges, grs.compensateRemovedEdges).
+		WithCompensableStep("RemoveNodes", grs.removeNodes, grs.compensateRemovedNodes).
+		WithCompensableStep("RestoreNodes", grs.restoreNodes, grs.compensateRestoredNodes).
+		WithCompensableStep("RestoreEdges", grs.restoreEdges, grs.compensateRestoredEdges).
+		WithStep("RecordRollback", grs.recordRollback).
+		Build()
+
+	return grs
+}
+
+// Execute runs the rollback saga and returns the version recording the
+// rolled back state
+func (grs *GraphRollbackSaga) Execute(ctx context.Context) (*GraphRollbackData, error) {
+	result, err := grs.saga.Execute(ctx, &GraphRollbackData{StartTime: time.Now()})
+	if err != nil {
+		grs.logger.Error("Graph rollback failed",
+			zap.String("graph_id", grs.graphID),
+			zap.Int("target_version", grs.targetVersion),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	data := result.(*GraphRollbackData)
+	grs.logger.Info("Graph rollback completed successfully",
+		zap.String("graph_id", grs.graphID),
+		zap.Int("target_version", grs.targetVersion),
+		zap.Int("removed_nodes", len(grs.removedNodes)),
+		zap.Int("replaced_nodes", len(grs.replacedNodes)),
+		zap.Int("created_nodes", len(grs.createdNodes)),
+		zap.Int("removed_edges", len(grs.removedEdges)),
+		zap.Int("added_edges", len(grs.addedEdges)),
+		zap.Duration("duration", time.Since(data.StartTime)),
+	)
+	return data, nil
+}
+
+// Step 1: Load the target version and the current state of the graph
+func (grs *GraphRollbackSaga) loadVersion(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	target, err := grs.versions.GetVersion(ctx, grs.userID, grs.graphID, grs.targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	rollbackData.Target = target
+
+	nodes, err := grs.nodeRepo.GetByGraphID(ctx, grs.graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	rollbackData.Nodes = nodes
+
+	edges, err := grs.edgeRepo.GetByGraphID(ctx, grs.graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+	rollbackData.Edges = edges
+
+	return rollbackData, nil
+}
+
+// Step 2: Record the current state, so the rollback itself can be undone
+func (grs *GraphRollbackSaga) checkpointCurrentState(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	checkpoint, err := grs.versions.CreateVersion(ctx, grs.userID, grs.graphID, "",
+		fmt.Sprintf("Before rollback to version %d", grs.targetVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record current state: %w", err)
+	}
+	rollbackData.Checkpoint = checkpoint
+
+	return rollbackData, nil
+}
+
+// Step 3: Remove the edges the target version does not have, or has with
+// different attributes
+func (grs *GraphRollbackSaga) removeEdges(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	targetEdges := make(map[string]*aggregates.Edge, len(rollbackData.Target.Edges))
+	for _, edge := range rollbackData.Target.Edges {
+		targetEdges[versioning.EdgeKey(edge)] = edge
+	}
+
+	for _, edge := range rollbackData.Edges {
+		if target, ok := targetEdges[versioning.EdgeKey(edge)]; ok && !versioning.EdgeChanged(edge, target) {
+			continue
+		}
+		if err := grs.edgeRepo.Delete(ctx, grs.graphID, edge.SourceID.String(), edge.TargetID.String()); err != nil {
+			grs.compensateRemovedEdges(ctx, rollbackData)
+			return nil, fmt.Errorf("failed to remove edge %s: %w", versioning.EdgeKey(edge), err)
+		}
+		grs.removedEdges = append(grs.removedEdges, edge)
+	}
+
+	return rollbackData, nil
+}
+
+func (grs *GraphRollbackSaga) compensateRemovedEdges(ctx context.Context, data interface{}) error {
+	grs.logger.Info("Compensating edge removal", zap.Int("count", len(grs.removedEdges)))
+
+	for _, edge := range grs.removedEdges {
+		if err := grs.edgeRepo.Save(ctx, grs.graphID, edge); err != nil {
+			grs.logger.Error("Failed to restore edge during compensation",
+				zap.String("edge", versioning.EdgeKey(edge)),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Step 4: Remove the nodes the target version does not have
+func (grs *GraphRollbackSaga) removeNodes(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	targetNodes := make(map[string]bool, len(rollbackData.Target.Nodes))
+	for _, backup := range rollbackData.Target.Nodes {
+		targetNodes[backup.Snapshot.ID] = true
+	}
+
+	for _, node := range rollbackData.Nodes {
+		if targetNodes[node.ID().String()] {
+			continue
+		}
+		if err := grs.nodeRepo.Delete(ctx, node.ID()); err != nil {
+			grs.compensateRemovedNodes(ctx, rollbackData)
+			return nil, fmt.Errorf("failed to remove node %s: %w", node.ID().String(), err)
+		}
+		grs.removedNodes = append(grs.removedNodes, node)
+	}
+
+	return rollbackData, nil
+}
+
+func (grs *GraphRollbackSaga) compensateRemovedNodes(ctx context.Context, data interface{}) error {
+	grs.logger.Info("Compensating node removal", zap.Int("count", len(grs.removedNodes)))
+
+	for _, removed := range grs.removedNodes {
+		node, err := entities.NodeFromBackup(removed.Backup())
+		if err == nil {
+			err = grs.nodeRepo.Save(ctx, node)
+		}
+		if err != nil {
+			grs.logger.Error("Failed to restore node during compensation",
+				zap.String("node_id", removed.ID().String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Step 5: Bring back the nodes of the target version. Nodes that still exist
+// are saved as their next version; nodes that are gone are created again.
+func (grs *GraphRollbackSaga) restoreNodes(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	current := make(map[string]*entities.Node, len(rollbackData.Nodes))
+	for _, node := range rollbackData.Nodes {
+		current[node.ID().String()] = node
+	}
+
+	for _, backup := range rollbackData.Target.Nodes {
+		existing, ok := current[backup.Snapshot.ID]
+		if ok && len(versioning.ChangedNodeFields(existing.Backup(), backup)) == 0 {
+			continue
+		}
+
+		var node *entities.Node
+		var err error
+		if ok {
+			node, err = entities.NodeFromBackupOver(backup, existing)
+		} else {
+			node, err = entities.NodeFromBackup(backup)
+		}
+		if err == nil {
+			err = grs.nodeRepo.Save(ctx, node)
+		}
+		if err != nil {
+			grs.compensateRestoredNodes(ctx, rollbackData)
+			return nil, fmt.Errorf("failed to restore node %s: %w", backup.Snapshot.ID, err)
+		}
+
+		if ok {
+			grs.replacedNodes = append(grs.replacedNodes, existing)
+		} else {
+			grs.createdNodes = append(grs.createdNodes, node)
+		}
+	}
+
+	return rollbackData, nil
+}
+
+func (grs *GraphRollbackSaga) compensateRestoredNodes(ctx context.Context, data interface{}) error {
+	grs.logger.Info("Compensating node restore",
+		zap.Int("replaced", len(grs.replacedNodes)),
+		zap.Int("created", len(grs.createdNodes)),
+	)
+
+	for _, created := range grs.createdNodes {
+		if err := grs.nodeRepo.Delete(ctx, created.ID()); err != nil {
+			grs.logger.Error("Failed to remove restored node during compensation",
+				zap.String("node_id", created.ID().String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	for _, replaced := range grs.replacedNodes {
+		stored, err := grs.nodeRepo.GetByID(ctx, replaced.ID())
+		if err == nil {
+			var node *entities.Node
+			node, err = entities.NodeFromBackupOver(replaced.Backup(), stored)
+			if err == nil {
+				err = grs.nodeRepo.Save(ctx, node)
+			}
+		}
+		if err != nil {
+			grs.logger.Error("Failed to put back node during compensation",
+				zap.String("node_id", replaced.ID().String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Step 6: Add the edges of the target version that are missing
+func (grs *GraphRollbackSaga) restoreEdges(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	remaining := make(map[string]bool, len(rollbackData.Edges))
+	for _, edge := range rollbackData.Edges {
+		remaining[versioning.EdgeKey(edge)] = true
+	}
+	for _, edge := range grs.removedEdges {
+		delete(remaining, versioning.EdgeKey(edge))
+	}
+
+	for _, edge := range rollbackData.Target.Edges {
+		if remaining[versioning.EdgeKey(edge)] {
+			continue
+		}
+		if err := grs.edgeRepo.Save(ctx, grs.graphID, edge); err != nil {
+			grs.compensateRestoredEdges(ctx, rollbackData)
+			return nil, fmt.Errorf("failed to restore edge %s: %w", versioning.EdgeKey(edge), err)
+		}
+		grs.addedEdges = append(grs.addedEdges, edge)
+	}
+
+	return rollbackData, nil
+}
+
+func (grs *GraphRollbackSaga) compensateRestoredEdges(ctx context.Context, data interface{}) error {
+	grs.logger.Info("Compensating edge restore", zap.Int("count", len(grs.addedEdges)))
+
+	for _, edge := range grs.addedEdges {
+		if err := grs.edgeRepo.Delete(ctx, grs.graphID, edge.SourceID.String(), edge.TargetID.String()); err != nil {
+			grs.logger.Error("Failed to remove restored edge during compensation",
+				zap.String("edge", versioning.EdgeKey(edge)),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Step 7: Refresh the graph's counts and record the rolled back state
+func (grs *GraphRollbackSaga) recordRollback(ctx context.Context, data interface{}) (interface{}, error) {
+	rollbackData := data.(*GraphRollbackData)
+
+	if err := grs.graphRepo.UpdateGraphMetadata(ctx, grs.graphID); err != nil {
+		grs.logger.Warn("Failed to update graph metadata after rollback",
+			zap.String("graph_id", grs.graphID),
+			zap.Error(err),
+		)
+	}
+
+	// The rollback has been applied at this point; failing to record it only
+	// leaves the checkpoint as the newest version
+	result, err := grs.versions.CreateVersion(ctx, grs.userID, grs.graphID, "",
+		fmt.Sprintf("Rolled back to version %d", grs.targetVersion))
+	if err != nil {
+		grs.logger.Warn("Failed to record rolled back state",
+			zap.String("graph_id", grs.graphID),
+			zap.Error(err),
+		)
+	}
+	rollbackData.Result = result
+
+	return rollbackData, nil
+}
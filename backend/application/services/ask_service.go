@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/search"
+	"go.uber.org/zap"
+)
+
+const (
+	// askHopDecay scales the score of a node reached over an edge, per hop
+	askHopDecay = 0.5
+	// minExcerptTokens is the smallest body excerpt worth adding to a
+	// context pack; a source that does not fit one is left out
+	minExcerptTokens = 32
+	// noContextAnswer is the answer when no note relates to the question
+	noContextAnswer = "I couldn't find anything in your notes about that."
+)
+
+// askSystemPrompt tells the model to answer from the context pack only
+const askSystemPrompt = `You answer questions using only the user's notes below. ` +
+	`Cite the notes you use with their numbers in square brackets, like [1] or [2][3]. ` +
+	`If the notes do not contain the answer, say so instead of guessing.`
+
+// AskConfig configures retrieval for questions.
+type AskConfig struct {
+	Candidates    int     // Search results a question starts from
+	Hops          int     // Edges followed from each search result, 0 to 2
+	MinEdgeWeight float64 // Weakest edge followed
+	MaxNeighbors  int     // Strongest edges followed per node
+	ContextTokens int     // Estimated token budget of the context pack
+	AnswerTokens  int     // Upper bound of the answer length; zero leaves it to the model
+}
+
+// DefaultAskConfig returns reasonable defaults.
+func DefaultAskConfig() *AskConfig {
+	return &AskConfig{
+		Candidates:    8,
+		Hops:          1,
+		MinEdgeWeight: 0.5,
+		MaxNeighbors:  3,
+		ContextTokens: 3000,
+		AnswerTokens:  800,
+	}
+}
+
+// AskSource is a node in the context pack of a question. The answer cites it
+// by Ref, as in [1].
+type AskSource struct {
+	Ref       int     `json:"ref"`
+	NodeID    string  `json:"node_id"`
+	Title     string  `json:"title"`
+	Score     float64 `json:"score"` // Relevance relative to the best search result
+	Hop       int     `json:"hop"`   // 0 for search results, otherwise the edges followed to reach the node
+	ViaNodeID string  `json:"via_node_id,omitempty"`
+	Truncated bool    `json:"truncated"` // Only the start of the body fit the token budget
+
+	excerpt string
+}
+
+// AskContext is the context pack a question is answered from.
+type AskContext struct {
+	Question string      `json:"question"`
+	Sources  []AskSource `json:"sources"`
+	Tokens   int         `json:"tokens"` // Estimated tokens of the packed notes
+}
+
+// AskService answers questions about a user's notes: it retrieves the
+// notes that best match a question with hybrid search, adds the notes
+// strongly connected to them, packs as many as fit a token budget and has a
+// language model answer from them with citations.
+type AskService struct {
+	search   *HybridSearchService
+	nodeRepo ports.NodeRepository
+	edgeRepo ports.EdgeRepository
+	chat     ports.ChatClient
+	config   *AskConfig
+	logger   *zap.Logger
+}
+
+// NewAskService creates a new ask service.
+func NewAskService(
+	search *HybridSearchService,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	chat ports.ChatClient,
+	config *AskConfig,
+	logger *zap.Logger,
+) *AskService {
+	if config == nil {
+		config = DefaultAskConfig()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &AskService{
+		search:   search,
+		nodeRepo: nodeRepo,
+		edgeRepo: edgeRepo,
+		chat:     chat,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// askCandidate is a node considered for a context pack
+type askCandidate struct {
+	node  *entities.Node
+	score float64
+	hop   int
+	via   string
+}
+
+// Retrieve assembles the context pack for a question. Sources are ordered by
+// relevance and numbered from 1.
+func (s *AskService) Retrieve(ctx context.Context, userID, question string) (*AskContext, error) {
+	results, err := s.search.SearchQuery(ctx, userID, search.TextQuery(question), s.config.Candidates)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	candidates := make([]*askCandidate, 0, len(results))
+	byID := make(map[string]*askCandidate, len(results))
+	for _, r := range results {
+		// RRF scores are only meaningful relative to each other
+		score := 1.0
+		if top := results[0].Score; top > 0 {
+			score = r.Score / top
+		}
+		c := &askCandidate{node: r.Node, score: score}
+		candidates = append(candidates, c)
+		byID[r.Node.ID().String()] = c
+	}
+
+	frontier := candidates
+	for hop := 1; hop <= min(s.config.Hops, 2) && len(frontier) > 0; hop++ {
+		frontier = s.expand(ctx, userID, frontier, hop, byID)
+		candidates = append(candidates, frontier...)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	return s.pack(question, candidates), nil
+}
+
+// expand returns the nodes reached over strong edges from the frontier that
+// are not candidates yet, and raises the score of candidates reached again
+func (s *AskService) expand(ctx context.Context, userID string, frontier []*askCandidate, hop int, byID map[string]*askCandidate) []*askCandidate {
+	next := make([]*askCandidate, 0)
+	for _, from := range frontier {
+		fromID := from.node.ID().String()
+		edges, err := s.edgeRepo.GetByNodeID(ctx, fromID)
+		if err != nil {
+			s.logger.Warn("Failed to load edges for question context", zap.String("nodeID", fromID), zap.Error(err))
+			continue
+		}
+		strong := make([]*aggregates.Edge, 0, len(edges))
+		for _, e := range edges {
+			if e.Weight >= s.config.MinEdgeWeight {
+				strong = append(strong, e)
+			}
+		}
+		sort.SliceStable(strong, func(i, j int) bool { return strong[i].Weight > strong[j].Weight })
+		if len(strong) > s.config.MaxNeighbors {
+			strong = strong[:s.config.MaxNeighbors]
+		}
+
+		for _, e := range strong {
+			neighbourID := e.TargetID
+			if neighbourID.String() == fromID {
+				neighbourID = e.SourceID
+			}
+			score := from.score * e.Weight * askHopDecay
+			if existing, ok := byID[neighbourID.String()]; ok {
+				existing.score = max(existing.score, score)
+				continue
+			}
+			node, err := s.loadNode(ctx, neighbourID)
+			if err != nil || node.UserID() != userID {
+				continue
+			}
+			c := &askCandidate{node: node, score: score, hop: hop, via: fromID}
+			byID[neighbourID.String()] = c
+			next = append(next, c)
+		}
+	}
+	return next
+}
+
+func (s *AskService) loadNode(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	node, err := s.nodeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node %s not found", id.String())
+	}
+	return node, nil
+}
+
+// pack adds candidates in order while they fit the token budget, cutting a
+// body short when only part of it fits
+func (s *AskService) pack(question string, candidates []*askCandidate) *AskContext {
+	pack := &AskContext{Question: question, Sources: make([]AskSource, 0, len(candidates))}
+	budget := s.config.ContextTokens
+	for _, c := range candidates {
+		content := c.node.Content()
+		source := AskSource{
+			Ref:       len(pack.Sources) + 1,
+			NodeID:    c.node.ID().String(),
+			Title:     content.Title(),
+			Score:     c.score,
+			Hop:       c.hop,
+			ViaNodeID: c.via,
+			excerpt:   content.Body(),
+		}
+
+		remaining := budget - pack.Tokens - estimateTokens(sourceHeader(source))
+		if tokens := estimateTokens(source.excerpt); tokens > remaining {
+			if remaining < minExcerptTokens {
+				continue
+			}
+			source.excerpt = truncateToTokens(source.excerpt, remaining)
+			source.Truncated = true
+		}
+		pack.Tokens += estimateTokens(sourceHeader(source)) + estimateTokens(source.excerpt)
+		pack.Sources = append(pack.Sources, source)
+	}
+	return pack
+}
+
+// Answer streams the answer to the question of a context pack to onDelta.
+// Without sources it answers that the notes say nothing about the question
+// rather than letting the model guess.
+func (s *AskService) Answer(ctx context.Context, pack *AskContext, onDelta func(delta string) error) error {
+	if len(pack.Sources) == 0 {
+		return onDelta(noContextAnswer)
+	}
+	err := s.chat.StreamChat(ctx, ports.ChatRequest{
+		Messages:  askMessages(pack),
+		MaxTokens: s.config.AnswerTokens,
+	}, onDelta)
+	if err != nil {
+		return fmt.Errorf("failed to generate answer: %w", err)
+	}
+	return nil
+}
+
+// Model returns the ID of the model questions are answered with.
+func (s *AskService) Model() string {
+	return s.chat.Model()
+}
+
+// askMessages builds the conversation that asks the model the question of a
+// context pack
+func askMessages(pack *AskContext) []ports.ChatMessage {
+	var notes strings.Builder
+	for _, source := range pack.Sources {
+		notes.WriteString(sourceHeader(source))
+		if source.excerpt != "" {
+			notes.WriteString(source.excerpt)
+			notes.WriteString("\n")
+		}
+		notes.WriteString("\n")
+	}
+	return []ports.ChatMessage{
+		{Role: ports.ChatRoleSystem, Content: askSystemPrompt},
+		{Role: ports.ChatRoleUser, Content: "Notes:\n\n" + notes.String() + "Question: " + pack.Question},
+	}
+}
+
+func sourceHeader(source AskSource) string {
+	return fmt.Sprintf("[%d] %s (node %s)\n", source.Ref, source.Title, source.NodeID)
+}
+
+// estimateTokens approximates the tokens of text at four characters each,
+// which is close for English prose with common tokenizers
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// truncateToTokens cuts text to about tokens, at a word boundary, and marks
+// the cut
+func truncateToTokens(text string, tokens int) string {
+	limit := tokens*4 - 1 // Room for the ellipsis
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndexAny(cut, " \n\t"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
@@ -3,25 +3,50 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"backend/application/ports"
 	"backend/domain/core/aggregates"
 	"backend/domain/core/entities"
 	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
 	"go.uber.org/zap"
 )
 
+// DefaultModularityDrift is how far modularity may fall below its baseline
+// before an incremental update is replaced by a full recompute.
+const DefaultModularityDrift = 0.05
+
+// Community tree depths, counting the top level.
+const (
+	DefaultCommunityTreeDepth = 3
+	MaxCommunityTreeDepth     = 5
+)
+
 // CommunityDetectionService orchestrates Leiden community detection
 // across a user's graph, assigns community IDs to nodes, and extracts
 // keyword-based names for each community.
+//
+// Detected communities are stored per graph. Node and edge changes revise
+// the stored partition with local moves around the changed nodes; a full
+// Leiden run only happens on request, for a graph without a partition, or
+// when modularity drifts more than the allowed amount below its best value
+// since the last full run.
 type CommunityDetectionService struct {
-	graphRepo ports.GraphRepository
-	nodeRepo  ports.NodeRepository
-	edgeRepo  ports.EdgeRepository
-	config    *domainservices.LeidenConfig
-	logger    *zap.Logger
+	graphRepo     ports.GraphRepository
+	nodeRepo      ports.NodeRepository
+	edgeRepo      ports.EdgeRepository
+	communityRepo ports.CommunityRepository
+	config        *domainservices.LeidenConfig
+	drift         float64
+	logger        *zap.Logger
+
+	// mu serializes updates, so concurrent events never overwrite each
+	// other's partition
+	mu sync.Mutex
 }
 
 // NewCommunityDetectionService creates a new service.
@@ -29,22 +54,33 @@ func NewCommunityDetectionService(
 	graphRepo ports.GraphRepository,
 	nodeRepo ports.NodeRepository,
 	edgeRepo ports.EdgeRepository,
+	communityRepo ports.CommunityRepository,
 	logger *zap.Logger,
 ) *CommunityDetectionService {
 	return &CommunityDetectionService{
-		graphRepo: graphRepo,
-		nodeRepo:  nodeRepo,
-		edgeRepo:  edgeRepo,
-		config:    domainservices.DefaultLeidenConfig(),
-		logger:    logger,
+		graphRepo:     graphRepo,
+		nodeRepo:      nodeRepo,
+		edgeRepo:      edgeRepo,
+		communityRepo: communityRepo,
+		config:        domainservices.DefaultLeidenConfig(),
+		drift:         DefaultModularityDrift,
+		logger:        logger,
 	}
 }
 
+// SetModularityDrift sets how far modularity may fall below its baseline
+// before a full recompute runs.
+func (s *CommunityDetectionService) SetModularityDrift(drift float64) {
+	s.drift = drift
+}
+
 // DetectionResult holds the full output of community detection.
 type DetectionResult struct {
-	Communities []CommunityInfo `json:"communities"`
-	Modularity  float64         `json:"modularity"`
-	NodeCount   int             `json:"node_count"`
+	Communities   []CommunityInfo `json:"communities"`
+	Modularity    float64         `json:"modularity"`
+	NodeCount     int             `json:"node_count"`
+	FullRecompute bool            `json:"full_recompute"` // False when the previous partition was revised
+	RecomputedAt  time.Time       `json:"recomputed_at"`  // Time of the last full recompute
 }
 
 // CommunityInfo is the per-community metadata returned by detection.
@@ -57,100 +93,310 @@ type CommunityInfo struct {
 	MemberIDs     []string `json:"member_ids"`
 }
 
-// DetectCommunities runs Leiden on a user's default graph,
+// DetectCommunities runs Leiden from scratch on a user's default graph,
 // assigns community IDs to nodes, and returns community metadata.
 func (s *CommunityDetectionService) DetectCommunities(ctx context.Context, userID string) (*DetectionResult, error) {
-	// Load graph
 	graph, err := s.graphRepo.GetUserDefaultGraph(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get graph: %w", err)
 	}
+	return s.RecomputeGraph(ctx, graph.ID().String())
+}
 
-	// Load nodes and edges
-	nodes, err := s.nodeRepo.GetByGraphID(ctx, graph.ID().String())
+// GetCommunities returns the stored communities of a user's default graph,
+// detecting them first if they never were.
+func (s *CommunityDetectionService) GetCommunities(ctx context.Context, userID string) (*DetectionResult, error) {
+	graph, err := s.graphRepo.GetUserDefaultGraph(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load nodes: %w", err)
+		return nil, fmt.Errorf("failed to get graph: %w", err)
+	}
+	graphID := graph.ID().String()
+
+	partition, err := s.communityRepo.GetByGraphID(ctx, graphID)
+	if pkgerrors.IsNotFound(err) {
+		return s.RecomputeGraph(ctx, graphID)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load communities: %w", err)
+	}
+	return detectionResult(partition, false), nil
+}
+
+// RecomputeGraph runs Leiden from scratch on a graph and stores the result.
+// Communities keep their IDs where they overlap the previous partition.
+func (s *CommunityDetectionService) RecomputeGraph(ctx context.Context, graphID string) (*DetectionResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(ctx, graphID, nil, true)
+}
+
+// UpdateGraph revises the stored partition of a graph after the nodes with
+// the given IDs, or their edges, changed. Nodes added or removed since the
+// last update are picked up without being listed.
+func (s *CommunityDetectionService) UpdateGraph(ctx context.Context, graphID string, changedNodeIDs []string) (*DetectionResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(ctx, graphID, changedNodeIDs, false)
+}
 
-	edges, err := s.edgeRepo.GetByGraphID(ctx, graph.ID().String())
+// RemoveNodes revises the partitions of a user's graphs that held any of the
+// given nodes, for deletions that do not say which graph they were in.
+func (s *CommunityDetectionService) RemoveNodes(ctx context.Context, userID string, nodeIDs []string) error {
+	graphs, err := s.graphRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load user graphs: %w", err)
+	}
+	for _, graph := range graphs {
+		graphID := graph.ID().String()
+		partition, err := s.communityRepo.GetByGraphID(ctx, graphID)
+		if pkgerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load communities: %w", err)
+		}
+		assignments := partition.Assignments()
+		for _, id := range nodeIDs {
+			if _, ok := assignments[id]; ok {
+				if _, err := s.UpdateGraph(ctx, graphID, nil); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteGraph removes the stored communities of a deleted graph.
+func (s *CommunityDetectionService) DeleteGraph(ctx context.Context, graphID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.communityRepo.Delete(ctx, graphID)
+}
+
+// update revises or recomputes the partition of a graph and stores it.
+// The caller holds s.mu.
+func (s *CommunityDetectionService) update(ctx context.Context, graphID string, changed []string, full bool) (*DetectionResult, error) {
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes: %w", err)
+	}
+	edges, err := s.edgeRepo.GetByGraphID(ctx, graphID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load edges: %w", err)
 	}
 
-	if len(nodes) == 0 {
-		return &DetectionResult{Communities: []CommunityInfo{}}, nil
+	previous, err := s.communityRepo.GetByGraphID(ctx, graphID)
+	if err != nil && !pkgerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to load communities: %w", err)
 	}
+	if previous == nil {
+		full = true
+	}
+	previousIDs := previousAssignments(previous)
 
-	// Build Leiden graph from domain objects.
 	leidenGraph, nodeMap := buildLeidenGraph(nodes, edges)
 
-	// Run Leiden.
-	result := domainservices.RunLeiden(leidenGraph, s.config)
+	var result *domainservices.LeidenResult
+	baseline := 0.0
+	if !full {
+		result = domainservices.UpdateLeiden(leidenGraph, previousIDs, changed, s.config)
+		baseline = previous.BaselineModularity
+		if result.Modularity > baseline {
+			baseline = result.Modularity
+		}
+		if baseline-result.Modularity > s.drift {
+			s.logger.Info("Community modularity drifted, recomputing",
+				zap.String("graphID", graphID),
+				zap.Float64("modularity", result.Modularity),
+				zap.Float64("baseline", baseline),
+			)
+			full = true
+		}
+	}
+	if full {
+		recomputed := domainservices.AlignCommunities(domainservices.RunLeiden(leidenGraph, s.config), previousIDs)
+		// Leiden is randomized; keep the revised partition if it is better
+		if result == nil || recomputed.Modularity >= result.Modularity {
+			result = recomputed
+		}
+		baseline = result.Modularity
+	}
 
 	s.logger.Info("Leiden community detection complete",
-		zap.String("userID", userID),
+		zap.String("graphID", graphID),
 		zap.Int("nodes", len(nodes)),
 		zap.Int("communities", len(result.Communities)),
 		zap.Float64("modularity", result.Modularity),
+		zap.Bool("full", full),
 	)
 
-	// Build community metadata and assign to nodes.
-	communities := make([]CommunityInfo, 0, len(result.Communities))
+	now := time.Now()
+	partition := &entities.CommunityPartition{
+		GraphID:            graphID,
+		Communities:        buildCommunities(graphID, leidenGraph, result, nodeMap, previous, now),
+		Modularity:         result.Modularity,
+		BaselineModularity: baseline,
+		RecomputedAt:       now,
+		UpdatedAt:          now,
+	}
+	if !full {
+		partition.RecomputedAt = previous.RecomputedAt
+	}
+	if err := s.communityRepo.Save(ctx, partition); err != nil {
+		return nil, fmt.Errorf("failed to save communities: %w", err)
+	}
 
-	for commID, memberIDs := range result.Communities {
-		// Extract text from members for keyword naming.
-		texts := make([]string, 0, len(memberIDs))
-		for _, nid := range memberIDs {
-			if n, ok := nodeMap[nid]; ok {
-				c := n.Content()
-				texts = append(texts, c.Title()+" "+c.Body())
-			}
+	// Persist the nodes whose community changed.
+	assignments := partition.Assignments()
+	for _, node := range nodes {
+		communityID := assignments[node.ID().String()]
+		if node.CommunityID() == communityID {
+			continue
 		}
+		node.SetCommunityID(communityID)
+		if err := s.nodeRepo.Save(ctx, node); err != nil {
+			s.logger.Warn("Failed to persist community assignment",
+				zap.String("nodeID", node.ID().String()),
+				zap.Error(err),
+			)
+		}
+	}
 
-		keywords := domainservices.CommunityKeywords(texts, 5)
-		cohesion := domainservices.CohesionScore(leidenGraph, memberIDs)
+	return detectionResult(partition, full), nil
+}
 
-		name := "Cluster " + strconv.Itoa(commID)
-		if len(keywords) > 0 {
-			name = keywords[0]
-			if len(keywords) > 1 {
-				name += " & " + keywords[1]
-			}
+// previousAssignments maps node IDs to the numeric community IDs of a stored
+// partition
+func previousAssignments(partition *entities.CommunityPartition) map[string]int {
+	previous := make(map[string]int)
+	if partition == nil {
+		return previous
+	}
+	for nodeID, communityID := range partition.Assignments() {
+		if id, err := strconv.Atoi(communityID); err == nil {
+			previous[nodeID] = id
 		}
+	}
+	return previous
+}
 
+// buildCommunities names every community of a result. Communities whose
+// members did not change keep their stored name, keywords and creation time.
+func buildCommunities(
+	graphID string,
+	leidenGraph *domainservices.LeidenGraph,
+	result *domainservices.LeidenResult,
+	nodeMap map[string]*entities.Node,
+	previous *entities.CommunityPartition,
+	now time.Time,
+) []*entities.Community {
+	stored := make(map[string]*entities.Community)
+	if previous != nil {
+		for _, c := range previous.Communities {
+			stored[c.ID] = c
+		}
+	}
+
+	commIDs := make([]int, 0, len(result.Communities))
+	for commID := range result.Communities {
+		commIDs = append(commIDs, commID)
+	}
+	sort.Ints(commIDs)
+
+	communities := make([]*entities.Community, 0, len(commIDs))
+	for _, commID := range commIDs {
+		memberIDs := append([]string(nil), result.Communities[commID]...)
+		sort.Strings(memberIDs)
 		commIDStr := strconv.Itoa(commID)
-		communities = append(communities, CommunityInfo{
+
+		if c, ok := stored[commIDStr]; ok && equalStrings(c.MemberIDs, memberIDs) {
+			c.GraphID = graphID
+			c.CohesionScore = domainservices.CohesionScore(leidenGraph, memberIDs)
+			c.UpdatedAt = now
+			communities = append(communities, c)
+			continue
+		}
+
+		keywords := domainservices.CommunityKeywords(memberTexts(memberIDs, nodeMap), 5)
+		name := communityName(keywords, "Cluster "+commIDStr)
+
+		createdAt := now
+		if c, ok := stored[commIDStr]; ok {
+			createdAt = c.CreatedAt
+		}
+		communities = append(communities, &entities.Community{
 			ID:            commIDStr,
+			GraphID:       graphID,
 			Name:          name,
 			Keywords:      keywords,
-			CohesionScore: cohesion,
+			CohesionScore: domainservices.CohesionScore(leidenGraph, memberIDs),
 			MemberCount:   len(memberIDs),
 			MemberIDs:     memberIDs,
+			CreatedAt:     createdAt,
+			UpdatedAt:     now,
 		})
+	}
+	return communities
+}
 
-		// Assign community ID to each member node.
-		for _, nid := range memberIDs {
-			if n, ok := nodeMap[nid]; ok {
-				n.SetCommunityID(commIDStr)
-			}
+// memberTexts returns the title and body of each member, for keyword naming.
+func memberTexts(memberIDs []string, nodeMap map[string]*entities.Node) []string {
+	texts := make([]string, 0, len(memberIDs))
+	for _, nid := range memberIDs {
+		if n, ok := nodeMap[nid]; ok {
+			c := n.Content()
+			texts = append(texts, c.Title()+" "+c.Body())
 		}
 	}
+	return texts
+}
 
-	// Persist updated nodes (community assignments).
-	for _, node := range nodes {
-		if err := s.nodeRepo.Save(ctx, node); err != nil {
-			s.logger.Warn("Failed to persist community assignment",
-				zap.String("nodeID", node.ID().String()),
-				zap.Error(err),
-			)
-		}
+// communityName names a community after its top two keywords.
+func communityName(keywords []string, fallback string) string {
+	if len(keywords) == 0 {
+		return fallback
+	}
+	name := keywords[0]
+	if len(keywords) > 1 {
+		name += " & " + keywords[1]
 	}
+	return name
+}
 
-	return &DetectionResult{
-		Communities: communities,
-		Modularity:  result.Modularity,
-		NodeCount:   len(nodes),
-	}, nil
+// detectionResult converts a stored partition to the API result.
+func detectionResult(partition *entities.CommunityPartition, full bool) *DetectionResult {
+	result := &DetectionResult{
+		Communities:   make([]CommunityInfo, 0, len(partition.Communities)),
+		Modularity:    partition.Modularity,
+		FullRecompute: full,
+		RecomputedAt:  partition.RecomputedAt,
+	}
+	for _, c := range partition.Communities {
+		result.Communities = append(result.Communities, CommunityInfo{
+			ID:            c.ID,
+			Name:          c.Name,
+			Keywords:      c.Keywords,
+			CohesionScore: c.CohesionScore,
+			MemberCount:   c.MemberCount,
+			MemberIDs:     c.MemberIDs,
+		})
+		result.NodeCount += c.MemberCount
+	}
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // buildLeidenGraph converts domain objects to the compact Leiden representation.
@@ -191,6 +437,7 @@ func CreateCommunityEntities(graphID string, result *DetectionResult) []*entitie
 			Keywords:      ci.Keywords,
 			CohesionScore: ci.CohesionScore,
 			MemberCount:   ci.MemberCount,
+			MemberIDs:     ci.MemberIDs,
 			CreatedAt:     now,
 			UpdatedAt:     now,
 		})
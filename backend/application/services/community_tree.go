@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"backend/domain/core/entities"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// CommunityTreeResult is the topic hierarchy of a graph.
+type CommunityTreeResult struct {
+	GraphID     string               `json:"graph_id"`
+	Depth       int                  `json:"depth"`
+	Modularity  float64              `json:"modularity"` // Of the top-level partition
+	NodeCount   int                  `json:"node_count"`
+	Communities []*CommunityTreeNode `json:"communities"`
+}
+
+// CommunityTreeNode is a community with the subtopics it splits into.
+type CommunityTreeNode struct {
+	ID            string               `json:"id"`
+	Level         int                  `json:"level"`
+	Name          string               `json:"name"`
+	Keywords      []string             `json:"keywords"`
+	CohesionScore float64              `json:"cohesion_score"`
+	MemberCount   int                  `json:"member_count"`
+	MemberIDs     []string             `json:"member_ids"`
+	Children      []*CommunityTreeNode `json:"children,omitempty"`
+}
+
+// GetCommunityTree builds a topic hierarchy for a user's default graph. The
+// top level is the stored partition, so its IDs and names match
+// GetCommunities; large communities are split by running Leiden again inside
+// them at a higher resolution, down to depth levels.
+func (s *CommunityDetectionService) GetCommunityTree(ctx context.Context, userID string, depth int) (*CommunityTreeResult, error) {
+	if depth == 0 {
+		depth = DefaultCommunityTreeDepth
+	}
+	if depth < 1 || depth > MaxCommunityTreeDepth {
+		return nil, pkgerrors.NewValidationError(fmt.Sprintf("depth must be between 1 and %d", MaxCommunityTreeDepth))
+	}
+
+	graph, err := s.graphRepo.GetUserDefaultGraph(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph: %w", err)
+	}
+	graphID := graph.ID().String()
+
+	partition, err := s.communityRepo.GetByGraphID(ctx, graphID)
+	if pkgerrors.IsNotFound(err) {
+		if _, err := s.RecomputeGraph(ctx, graphID); err != nil {
+			return nil, err
+		}
+		partition, err = s.communityRepo.GetByGraphID(ctx, graphID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load communities: %w", err)
+	}
+
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes: %w", err)
+	}
+	edges, err := s.edgeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edges: %w", err)
+	}
+	leidenGraph, nodeMap := buildLeidenGraph(nodes, edges)
+
+	// The stored partition may still list a node removed since it was saved
+	top := make(map[int][]string, len(partition.Communities))
+	stored := make(map[string]*entities.Community, len(partition.Communities))
+	for _, c := range partition.Communities {
+		id, err := strconv.Atoi(c.ID)
+		if err != nil {
+			continue
+		}
+		for _, memberID := range c.MemberIDs {
+			if _, ok := nodeMap[memberID]; ok {
+				top[id] = append(top[id], memberID)
+			}
+		}
+		stored[c.ID] = c
+	}
+
+	cfg := domainservices.DefaultHierarchyConfig()
+	cfg.Leiden.Resolution = s.config.Resolution
+	cfg.Leiden.MinCommunitySize = s.config.MinCommunitySize
+	cfg.MaxDepth = depth
+	trees := domainservices.BuildCommunityTree(leidenGraph, top, cfg)
+
+	result := &CommunityTreeResult{
+		GraphID:     graphID,
+		Depth:       depth,
+		Modularity:  partition.Modularity,
+		Communities: make([]*CommunityTreeNode, 0, len(trees)),
+	}
+	for _, tree := range trees {
+		result.Communities = append(result.Communities, communityTreeNode(tree, stored[tree.ID], nil, leidenGraph, nodeMap))
+		result.NodeCount += len(tree.Members)
+	}
+
+	s.logger.Debug("Community tree built",
+		zap.String("graphID", graphID),
+		zap.Int("depth", depth),
+		zap.Int("communities", len(result.Communities)),
+	)
+
+	return result, nil
+}
+
+// communityTreeNode names a community of the tree and its subtopics. A
+// top-level community keeps its stored name; a subtopic is named after the
+// keywords that set it apart from its parent.
+func communityTreeNode(
+	tree *domainservices.CommunityTree,
+	stored *entities.Community,
+	parentKeywords []string,
+	leidenGraph *domainservices.LeidenGraph,
+	nodeMap map[string]*entities.Node,
+) *CommunityTreeNode {
+	node := &CommunityTreeNode{
+		ID:            tree.ID,
+		Level:         tree.Level,
+		CohesionScore: domainservices.CohesionScore(leidenGraph, tree.Members),
+		MemberCount:   len(tree.Members),
+		MemberIDs:     tree.Members,
+	}
+
+	if stored != nil {
+		node.Name = stored.Name
+		node.Keywords = stored.Keywords
+	} else {
+		exclude := make(map[string]bool, len(parentKeywords))
+		for _, k := range parentKeywords {
+			exclude[k] = true
+		}
+		candidates := domainservices.CommunityKeywords(memberTexts(tree.Members, nodeMap), 5+len(parentKeywords))
+		node.Keywords = make([]string, 0, 5)
+		for _, k := range candidates {
+			if !exclude[k] && len(node.Keywords) < 5 {
+				node.Keywords = append(node.Keywords, k)
+			}
+		}
+		node.Name = communityName(node.Keywords, "Subtopic "+tree.ID)
+	}
+
+	for _, child := range tree.Children {
+		node.Children = append(node.Children, communityTreeNode(child, nil, node.Keywords, leidenGraph, nodeMap))
+	}
+	return node
+}
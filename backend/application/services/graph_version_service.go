@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/versioning"
+	pkgerrors "backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// GraphVersionService records named versions of graphs and compares them.
+// A version holds the complete state of the graph's nodes and edges, so a
+// graph can later be rolled back to it.
+type GraphVersionService struct {
+	store      ports.GraphVersionStore
+	nodeRepo   ports.NodeRepository
+	edgeRepo   ports.EdgeRepository
+	graphRepo  ports.GraphRepository
+	versioning *versioning.VersioningService
+	logger     *zap.Logger
+}
+
+// NewGraphVersionService creates a new graph version service
+func NewGraphVersionService(
+	store ports.GraphVersionStore,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	versioningService *versioning.VersioningService,
+	logger *zap.Logger,
+) *GraphVersionService {
+	return &GraphVersionService{
+		store:      store,
+		nodeRepo:   nodeRepo,
+		edgeRepo:   edgeRepo,
+		graphRepo:  graphRepo,
+		versioning: versioningService,
+		logger:     logger,
+	}
+}
+
+// CreateVersion records the current state of a graph as its next version and
+// prunes the versions beyond the retention limit
+func (s *GraphVersionService) CreateVersion(ctx context.Context, userID, graphID, name, description string) (*versioning.GraphVersion, error) {
+	graph, err := s.getGraph(ctx, userID, graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.store.Latest(ctx, graphID)
+	if err != nil {
+		if !pkgerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get latest version: %w", err)
+		}
+		previous = nil
+	}
+
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	edges, err := s.edgeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+
+	snapshot, err := s.versioning.CreateSnapshot(graph, nodes, edges, previous, userID, name, description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Save(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Graph version created",
+		zap.String("graphID", graphID),
+		zap.Int("version", snapshot.Version.Version),
+		zap.Int("nodes", snapshot.Version.NodeCount),
+		zap.Int("edges", snapshot.Version.EdgeCount),
+	)
+
+	s.prune(ctx, graphID)
+	return &snapshot.Version, nil
+}
+
+// ListVersions returns the versions of a graph, newest first
+func (s *GraphVersionService) ListVersions(ctx context.Context, userID, graphID string) ([]*versioning.GraphVersion, error) {
+	if _, err := s.getGraph(ctx, userID, graphID); err != nil {
+		return nil, err
+	}
+	return s.store.List(ctx, graphID)
+}
+
+// GetVersion returns a version of a graph with its full state
+func (s *GraphVersionService) GetVersion(ctx context.Context, userID, graphID string, version int) (*versioning.GraphSnapshot, error) {
+	if _, err := s.getGraph(ctx, userID, graphID); err != nil {
+		return nil, err
+	}
+	return s.store.Get(ctx, graphID, version)
+}
+
+// Diff compares two versions of a graph. A to version of 0 compares the from
+// version with the current state of the graph.
+func (s *GraphVersionService) Diff(ctx context.Context, userID, graphID string, from, to int) (*versioning.SnapshotDiff, error) {
+	graph, err := s.getGraph(ctx, userID, graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSnapshot, err := s.store.Get(ctx, graphID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var toSnapshot *versioning.GraphSnapshot
+	if to == 0 {
+		toSnapshot, err = s.currentState(ctx, graph)
+	} else {
+		toSnapshot, err = s.store.Get(ctx, graphID, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.versioning.DiffSnapshots(fromSnapshot, toSnapshot)
+}
+
+// currentState captures the stored state of a graph without recording it
+func (s *GraphVersionService) currentState(ctx context.Context, graph *aggregates.Graph) (*versioning.GraphSnapshot, error) {
+	graphID := graph.ID().String()
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	edges, err := s.edgeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+	return s.versioning.CaptureState(graph, nodes, edges)
+}
+
+// DeleteVersions removes every version of a graph. It is meant for graphs
+// that are being deleted, so ownership is left to the caller.
+func (s *GraphVersionService) DeleteVersions(ctx context.Context, graphID string) error {
+	versions, err := s.store.List(ctx, graphID)
+	if err != nil {
+		return fmt.Errorf("failed to list graph versions: %w", err)
+	}
+	for _, version := range versions {
+		if err := s.store.Delete(ctx, graphID, version.Version); err != nil {
+			return fmt.Errorf("failed to delete graph version %d: %w", version.Version, err)
+		}
+	}
+	return nil
+}
+
+// getGraph loads a graph owned by the user. Graphs of other users are
+// reported as not found.
+func (s *GraphVersionService) getGraph(ctx context.Context, userID, graphID string) (*aggregates.Graph, error) {
+	graph, err := s.graphRepo.GetByID(ctx, aggregates.GraphID(graphID))
+	if err != nil || graph == nil || graph.UserID() != userID {
+		return nil, pkgerrors.NewNotFoundError("graph")
+	}
+	return graph, nil
+}
+
+// prune deletes the versions beyond the retention limit. Failing to prune is
+// not an error; the next version retries.
+func (s *GraphVersionService) prune(ctx context.Context, graphID string) {
+	versions, err := s.store.List(ctx, graphID)
+	if err != nil {
+		s.logger.Warn("Failed to list graph versions for pruning",
+			zap.String("graphID", graphID),
+			zap.Error(err),
+		)
+		return
+	}
+	for _, version := range s.versioning.VersionsToPrune(versions) {
+		if err := s.store.Delete(ctx, graphID, version.Version); err != nil {
+			s.logger.Warn("Failed to prune graph version",
+				zap.String("graphID", graphID),
+				zap.Int("version", version.Version),
+				zap.Error(err),
+			)
+		}
+	}
+}
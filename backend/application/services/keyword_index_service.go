@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// KeywordIndexConfig configures the keyword index service.
+type KeywordIndexConfig struct {
+	// SyncInterval is how often a loaded index is reconciled with the node
+	// repository, which picks up changes made by other processes. Zero
+	// disables periodic reconciliation.
+	SyncInterval time.Duration
+	// SaveEvery is the number of changes after which an index is persisted;
+	// zero or less persists after every change.
+	SaveEvery int
+}
+
+// DefaultKeywordIndexConfig returns reasonable defaults.
+func DefaultKeywordIndexConfig() *KeywordIndexConfig {
+	return &KeywordIndexConfig{
+		SyncInterval: 15 * time.Minute,
+		SaveEvery:    100,
+	}
+}
+
+// KeywordIndexService keeps an inverted index of each user's node text, so
+// BM25 keyword search only reads the postings of the query terms instead of
+// tokenizing every node on every search. A user's index is loaded on first
+// use, from the store when one was saved, and reconciled with the node
+// repository; afterwards node events keep it current. Like the vector index
+// it is a cache of the node repository and can always be rebuilt from it.
+type KeywordIndexService struct {
+	store    ports.KeywordIndexStore // nil keeps indexes in memory only
+	nodeRepo ports.NodeRepository
+	config   *KeywordIndexConfig
+	logger   *zap.Logger
+
+	mu    sync.Mutex
+	users map[string]*userKeywordIndex
+}
+
+// userKeywordIndex is the loaded index of one user
+type userKeywordIndex struct {
+	mu       sync.RWMutex
+	index    *domainservices.InvertedIndex
+	graphs   map[string]string // node ID -> graph ID
+	changes  int               // changes since the index was last persisted
+	syncedAt time.Time
+}
+
+// NewKeywordIndexService creates a new keyword index service.
+func NewKeywordIndexService(
+	store ports.KeywordIndexStore,
+	nodeRepo ports.NodeRepository,
+	config *KeywordIndexConfig,
+	logger *zap.Logger,
+) *KeywordIndexService {
+	if config == nil {
+		config = DefaultKeywordIndexConfig()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &KeywordIndexService{
+		store:    store,
+		nodeRepo: nodeRepo,
+		config:   config,
+		logger:   logger,
+		users:    make(map[string]*userKeywordIndex),
+	}
+}
+
+// keywordText is the text of a node that keyword search matches against
+func keywordText(node *entities.Node) string {
+	content := node.Content()
+	return content.Title() + " " + content.Body()
+}
+
+// Score returns up to limit of the user's nodes matching the query terms,
+// sorted by BM25 score descending. A limit of zero returns every match.
+func (s *KeywordIndexService) Score(ctx context.Context, userID string, queryTerms []string, limit int) ([]domainservices.ScoredDocument, error) {
+	if len(queryTerms) == 0 {
+		return nil, nil
+	}
+	u, err := s.acquire(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.index.Score(queryTerms, limit), nil
+}
+
+// IndexNode adds or replaces a node's text. Nodes of users whose index is not
+// loaded are ignored; the next load picks them up.
+func (s *KeywordIndexService) IndexNode(ctx context.Context, node *entities.Node) {
+	u := s.loaded(node.UserID())
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	changed := s.upsert(u, node)
+	u.mu.Unlock()
+
+	if changed {
+		s.changed(ctx, node.UserID(), u, 1)
+	}
+}
+
+// RemoveNodes removes nodes from a user's index.
+func (s *KeywordIndexService) RemoveNodes(ctx context.Context, userID string, nodeIDs []string) {
+	u := s.loaded(userID)
+	if u == nil {
+		return
+	}
+
+	removed := 0
+	u.mu.Lock()
+	for _, id := range nodeIDs {
+		if u.index.Remove(id) {
+			removed++
+		}
+		delete(u.graphs, id)
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, removed)
+}
+
+// SyncGraph reconciles the nodes of one graph with the index, for changes
+// that replace many nodes at once such as rollbacks and moves.
+func (s *KeywordIndexService) SyncGraph(ctx context.Context, userID, graphID string) error {
+	u := s.loaded(userID)
+	if u == nil {
+		return nil
+	}
+
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	changes := 0
+	present := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		if node.UserID() != userID {
+			continue
+		}
+		present[node.ID().String()] = struct{}{}
+		if s.upsert(u, node) {
+			changes++
+		}
+	}
+	for id, g := range u.graphs {
+		if _, ok := present[id]; g == graphID && !ok {
+			u.index.Remove(id)
+			delete(u.graphs, id)
+			changes++
+		}
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, changes)
+	return nil
+}
+
+// RemoveGraph removes every node of a graph from the user's index.
+func (s *KeywordIndexService) RemoveGraph(ctx context.Context, userID, graphID string) {
+	u := s.loaded(userID)
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	removed := 0
+	for id, g := range u.graphs {
+		if g == graphID {
+			u.index.Remove(id)
+			delete(u.graphs, id)
+			removed++
+		}
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, removed)
+}
+
+// Sync reconciles a user's index with the node repository now, loading it if
+// needed.
+func (s *KeywordIndexService) Sync(ctx context.Context, userID string) error {
+	u := s.entry(userID)
+	u.mu.Lock()
+	if u.index == nil {
+		u.mu.Unlock()
+		_, err := s.acquire(ctx, userID)
+		return err
+	}
+	changes, err := s.reconcile(ctx, userID, u)
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.changed(ctx, userID, u, changes)
+	return nil
+}
+
+// Flush persists every index with unsaved changes.
+func (s *KeywordIndexService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	users := make(map[string]*userKeywordIndex, len(s.users))
+	for userID, u := range s.users {
+		users[userID] = u
+	}
+	s.mu.Unlock()
+
+	var lastErr error
+	for userID, u := range users {
+		u.mu.RLock()
+		dirty := u.index != nil && u.changes > 0
+		u.mu.RUnlock()
+		if !dirty {
+			continue
+		}
+		if err := s.save(ctx, userID, u); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// entry returns the user's index slot, creating an empty one
+func (s *KeywordIndexService) entry(userID string) *userKeywordIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userID]
+	if !ok {
+		u = &userKeywordIndex{}
+		s.users[userID] = u
+	}
+	return u
+}
+
+// loaded returns the user's index if it has been loaded
+func (s *KeywordIndexService) loaded(userID string) *userKeywordIndex {
+	s.mu.Lock()
+	u, ok := s.users[userID]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if u.index == nil {
+		return nil
+	}
+	return u
+}
+
+// acquire returns the user's index, loading it on first use and reconciling
+// it when the last reconciliation is older than the sync interval
+func (s *KeywordIndexService) acquire(ctx context.Context, userID string) (*userKeywordIndex, error) {
+	u := s.entry(userID)
+
+	u.mu.RLock()
+	fresh := u.index != nil && (s.config.SyncInterval <= 0 || time.Since(u.syncedAt) < s.config.SyncInterval)
+	u.mu.RUnlock()
+	if fresh {
+		return u, nil
+	}
+
+	u.mu.Lock()
+	changes := 0
+	if u.index == nil {
+		restored := s.restore(ctx, userID, u)
+		n, err := s.reconcile(ctx, userID, u)
+		if err != nil {
+			u.index = nil
+			u.mu.Unlock()
+			return nil, err
+		}
+		changes = n
+		if !restored && u.index.Len() > 0 {
+			// Keep a fresh build right away so the next cold start skips tokenizing
+			changes = s.config.SaveEvery
+		}
+	} else if s.config.SyncInterval > 0 && time.Since(u.syncedAt) >= s.config.SyncInterval {
+		n, err := s.reconcile(ctx, userID, u)
+		if err != nil {
+			// A stale index still answers searches
+			s.logger.Warn("Failed to reconcile keyword index", zap.String("userID", userID), zap.Error(err))
+		}
+		changes = n
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, changes)
+	return u, nil
+}
+
+// restore loads the user's saved index into u, or starts an empty one.
+// Reports whether a saved index was used. The caller holds u.mu.
+func (s *KeywordIndexService) restore(ctx context.Context, userID string, u *userKeywordIndex) bool {
+	u.index = domainservices.NewInvertedIndex()
+	u.graphs = make(map[string]string)
+	u.changes = 0
+	if s.store == nil {
+		return false
+	}
+
+	data, err := s.store.Load(ctx, userID)
+	if err != nil {
+		if !pkgerrors.IsNotFound(err) {
+			s.logger.Warn("Failed to load keyword index, rebuilding", zap.String("userID", userID), zap.Error(err))
+		}
+		return false
+	}
+	if err := u.index.UnmarshalBinary(data); err != nil {
+		s.logger.Warn("Discarding unreadable keyword index", zap.String("userID", userID), zap.Error(err))
+		u.index = domainservices.NewInvertedIndex()
+		return false
+	}
+	return true
+}
+
+// reconcile brings the index in line with the user's nodes, re-tokenizing
+// only text that is missing or changed. The caller holds u.mu.
+func (s *KeywordIndexService) reconcile(ctx context.Context, userID string, u *userKeywordIndex) (int, error) {
+	nodes, err := s.nodeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	changes := 0
+	present := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		present[node.ID().String()] = struct{}{}
+		if s.upsert(u, node) {
+			changes++
+		}
+	}
+	for _, id := range u.index.IDs() {
+		if _, ok := present[id]; !ok {
+			u.index.Remove(id)
+			changes++
+		}
+	}
+	for id := range u.graphs {
+		if _, ok := present[id]; !ok {
+			delete(u.graphs, id)
+		}
+	}
+
+	u.syncedAt = time.Now()
+	return changes, nil
+}
+
+// upsert indexes a node's current text. Reports whether the index changed.
+// The caller holds u.mu.
+func (s *KeywordIndexService) upsert(u *userKeywordIndex, node *entities.Node) bool {
+	id := node.ID().String()
+	u.graphs[id] = node.GraphID()
+	return u.index.Add(id, keywordText(node))
+}
+
+// changed records changes to an index and persists it once enough have
+// accumulated
+func (s *KeywordIndexService) changed(ctx context.Context, userID string, u *userKeywordIndex, changes int) {
+	if changes <= 0 {
+		return
+	}
+	u.mu.Lock()
+	u.changes += changes
+	due := u.changes >= s.config.SaveEvery
+	u.mu.Unlock()
+
+	if due {
+		if err := s.save(ctx, userID, u); err != nil {
+			s.logger.Warn("Failed to persist keyword index", zap.String("userID", userID), zap.Error(err))
+		}
+	}
+}
+
+// save persists a user's index
+func (s *KeywordIndexService) save(ctx context.Context, userID string, u *userKeywordIndex) error {
+	if s.store == nil {
+		return nil
+	}
+
+	u.mu.Lock()
+	data, err := u.index.MarshalBinary()
+	pending := u.changes
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Save(ctx, userID, data); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	u.changes -= pending
+	u.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"backend/application/commands"
+	"backend/application/commands/bus"
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Link suggestion thresholds. A user's threshold starts at the default and
+// is moved between the bounds by the suggestions they accept and reject.
+const (
+	DefaultLinkThreshold = 0.35
+	MinLinkThreshold     = 0.2
+	MaxLinkThreshold     = 0.9
+)
+
+// Link suggestion limits per refresh
+const (
+	DefaultLinkSuggestionLimit = 20
+	MaxLinkSuggestionLimit     = 100
+)
+
+// linkThresholdRate is the share of the distance to a resolved suggestion's
+// score, give or take linkThresholdMargin, that the threshold moves
+const (
+	linkThresholdRate   = 0.25
+	linkThresholdMargin = 0.05
+)
+
+// CommandSender sends commands; the command bus implements it
+type CommandSender interface {
+	Send(ctx context.Context, cmd bus.Command) error
+}
+
+// LinkPredictionService suggests edges a graph is missing. Pairs of nodes
+// are scored on shared neighbours, Adamic-Adar, community co-membership and
+// hybrid content similarity; the best are stored as pending suggestions,
+// which the user accepts, creating the edge, or rejects.
+//
+// Resolved suggestions are remembered, so a pair is never offered twice,
+// and tune the user's threshold: a rejection raises it towards the rejected
+// score, an acceptance lowers it towards the accepted one.
+type LinkPredictionService struct {
+	store         ports.LinkSuggestionStore
+	graphRepo     ports.GraphRepository
+	nodeRepo      ports.NodeRepository
+	edgeRepo      ports.EdgeRepository
+	communityRepo ports.CommunityRepository
+	commands      CommandSender
+	predictor     *domainservices.LinkPredictor
+	config        *domainservices.LinkPredictionConfig
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewLinkPredictionService creates a new link prediction service
+func NewLinkPredictionService(
+	store ports.LinkSuggestionStore,
+	graphRepo ports.GraphRepository,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	communityRepo ports.CommunityRepository,
+	commands CommandSender,
+	logger *zap.Logger,
+) *LinkPredictionService {
+	return &LinkPredictionService{
+		store:         store,
+		graphRepo:     graphRepo,
+		nodeRepo:      nodeRepo,
+		edgeRepo:      edgeRepo,
+		communityRepo: communityRepo,
+		commands:      commands,
+		predictor:     domainservices.NewLinkPredictor(nil),
+		config:        domainservices.DefaultLinkPredictionConfig(),
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Refresh scores the missing edges of a graph and replaces its pending
+// suggestions with the best limit of them. An empty graphID selects the
+// user's default graph.
+func (s *LinkPredictionService) Refresh(ctx context.Context, userID, graphID string, limit int) ([]*ports.LinkSuggestion, error) {
+	if limit == 0 {
+		limit = DefaultLinkSuggestionLimit
+	}
+	if limit < 1 || limit > MaxLinkSuggestionLimit {
+		return nil, pkgerrors.NewValidationError(fmt.Sprintf("limit must be between 1 and %d", MaxLinkSuggestionLimit))
+	}
+	graphID, err := s.resolveGraph(ctx, userID, graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes: %w", err)
+	}
+	edges, err := s.edgeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edges: %w", err)
+	}
+	var communities map[string]string
+	partition, err := s.communityRepo.GetByGraphID(ctx, graphID)
+	switch {
+	case err == nil:
+		communities = partition.Assignments()
+	case !pkgerrors.IsNotFound(err):
+		// Predict without the community signal rather than not at all
+		s.logger.Warn("Failed to load communities for link prediction",
+			zap.String("graphID", graphID),
+			zap.Error(err),
+		)
+	}
+
+	feedback, err := s.Feedback(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cfg := *s.config
+	cfg.MinScore = feedback.Threshold
+	predicted := s.predictor.Predict(nodes, edges, communities, &cfg)
+
+	existing, err := s.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load link suggestions: %w", err)
+	}
+	known := make(map[string]*ports.LinkSuggestion, len(existing))
+	for _, suggestion := range existing {
+		known[suggestion.ID] = suggestion
+	}
+	titles := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		titles[node.ID().String()] = node.Content().Title()
+	}
+
+	now := s.now()
+	suggestions := make([]*ports.LinkSuggestion, 0, limit)
+	kept := make(map[string]bool, limit)
+	for _, link := range predicted {
+		if len(suggestions) == limit {
+			break
+		}
+		id := linkSuggestionID(graphID, link.SourceID, link.TargetID)
+		createdAt := now
+		if previous, ok := known[id]; ok {
+			if previous.Status != ports.LinkSuggestionPending {
+				continue
+			}
+			createdAt = previous.CreatedAt
+		}
+		suggestions = append(suggestions, &ports.LinkSuggestion{
+			ID:               id,
+			UserID:           userID,
+			GraphID:          graphID,
+			SourceID:         link.SourceID.String(),
+			TargetID:         link.TargetID.String(),
+			SourceTitle:      titles[link.SourceID.String()],
+			TargetTitle:      titles[link.TargetID.String()],
+			Score:            link.Score,
+			CommonNeighbours: link.CommonNeighbours,
+			AdamicAdar:       link.AdamicAdar,
+			SameCommunity:    link.SameCommunity,
+			Similarity:       link.Similarity,
+			Status:           ports.LinkSuggestionPending,
+			CreatedAt:        createdAt,
+		})
+		kept[id] = true
+	}
+
+	for _, previous := range existing {
+		if previous.GraphID == graphID && previous.Status == ports.LinkSuggestionPending && !kept[previous.ID] {
+			if err := s.store.Delete(ctx, userID, previous.ID); err != nil {
+				return nil, fmt.Errorf("failed to remove stale link suggestion: %w", err)
+			}
+		}
+	}
+	for _, suggestion := range suggestions {
+		if err := s.store.Put(ctx, suggestion); err != nil {
+			return nil, fmt.Errorf("failed to store link suggestion: %w", err)
+		}
+	}
+
+	s.logger.Info("Link suggestions refreshed",
+		zap.String("graphID", graphID),
+		zap.String("userID", userID),
+		zap.Int("candidates", len(predicted)),
+		zap.Int("suggestions", len(suggestions)),
+		zap.Float64("threshold", feedback.Threshold),
+	)
+	return suggestions, nil
+}
+
+// List returns a user's pending suggestions, highest score first. An empty
+// graphID lists the suggestions of every graph.
+func (s *LinkPredictionService) List(ctx context.Context, userID, graphID string) ([]*ports.LinkSuggestion, error) {
+	all, err := s.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load link suggestions: %w", err)
+	}
+	pending := make([]*ports.LinkSuggestion, 0, len(all))
+	for _, suggestion := range all {
+		if suggestion.Status == ports.LinkSuggestionPending && (graphID == "" || suggestion.GraphID == graphID) {
+			pending = append(pending, suggestion)
+		}
+	}
+	return pending, nil
+}
+
+// Accept creates the suggested edge and records the acceptance
+func (s *LinkPredictionService) Accept(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error) {
+	suggestion, err := s.pending(ctx, userID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A node removed since the refresh makes the suggestion moot
+	for _, nodeID := range []string{suggestion.SourceID, suggestion.TargetID} {
+		id, err := valueobjects.NewNodeIDFromString(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		if node, err := s.nodeRepo.GetByID(ctx, id); err != nil || node == nil {
+			if err := s.store.Delete(ctx, userID, suggestionID); err != nil {
+				s.logger.Warn("Failed to remove link suggestion of missing node",
+					zap.String("suggestionID", suggestionID),
+					zap.Error(err),
+				)
+			}
+			return nil, pkgerrors.NewNotFoundError("node")
+		}
+	}
+
+	// An edge the user drew themselves since the refresh still counts
+	err = s.commands.Send(ctx, commands.CreateEdgeCommand{
+		EdgeID:   uuid.New().String(),
+		UserID:   userID,
+		GraphID:  suggestion.GraphID,
+		SourceID: suggestion.SourceID,
+		TargetID: suggestion.TargetID,
+		Type:     string(entities.EdgeTypeNormal),
+		Weight:   suggestion.Score,
+		Metadata: map[string]interface{}{"suggestion_id": suggestion.ID},
+	})
+	if err != nil && !pkgerrors.IsConflict(err) {
+		return nil, fmt.Errorf("failed to create suggested edge: %w", err)
+	}
+
+	suggestion.EdgeID = s.edgeBetween(ctx, suggestion.SourceID, suggestion.TargetID)
+	return s.resolve(ctx, suggestion, ports.LinkSuggestionAccepted)
+}
+
+// edgeBetween returns the ID of the edge joining two nodes, which the graph
+// assigns when it connects them
+func (s *LinkPredictionService) edgeBetween(ctx context.Context, sourceID, targetID string) string {
+	edges, err := s.edgeRepo.GetByNodeID(ctx, sourceID)
+	if err != nil {
+		s.logger.Warn("Failed to look up accepted edge",
+			zap.String("sourceID", sourceID),
+			zap.Error(err),
+		)
+		return ""
+	}
+	for _, edge := range edges {
+		if (edge.SourceID.String() == sourceID && edge.TargetID.String() == targetID) ||
+			(edge.SourceID.String() == targetID && edge.TargetID.String() == sourceID) {
+			return edge.ID
+		}
+	}
+	return ""
+}
+
+// Reject records the rejection; the pair is not suggested again
+func (s *LinkPredictionService) Reject(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error) {
+	suggestion, err := s.pending(ctx, userID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, suggestion, ports.LinkSuggestionRejected)
+}
+
+// Feedback returns what a user's resolved suggestions taught, starting from
+// the default threshold
+func (s *LinkPredictionService) Feedback(ctx context.Context, userID string) (*ports.LinkFeedback, error) {
+	feedback, err := s.store.GetFeedback(ctx, userID)
+	if pkgerrors.IsNotFound(err) {
+		return &ports.LinkFeedback{UserID: userID, Threshold: DefaultLinkThreshold}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load link feedback: %w", err)
+	}
+	return feedback, nil
+}
+
+func (s *LinkPredictionService) pending(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error) {
+	suggestion, err := s.store.Get(ctx, userID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != ports.LinkSuggestionPending {
+		return nil, pkgerrors.NewConflictError(fmt.Sprintf("link suggestion was already %s", suggestion.Status))
+	}
+	return suggestion, nil
+}
+
+// resolve stores the outcome of a suggestion and tunes the user's threshold
+func (s *LinkPredictionService) resolve(ctx context.Context, suggestion *ports.LinkSuggestion, status ports.LinkSuggestionStatus) (*ports.LinkSuggestion, error) {
+	now := s.now()
+	suggestion.Status = status
+	suggestion.ResolvedAt = &now
+	if err := s.store.Put(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("failed to store link suggestion: %w", err)
+	}
+
+	// The outcome is recorded; a lost threshold update only costs tuning
+	feedback, err := s.Feedback(ctx, suggestion.UserID)
+	if err == nil {
+		feedback.Threshold = tuneLinkThreshold(feedback.Threshold, suggestion.Score, status == ports.LinkSuggestionAccepted)
+		if status == ports.LinkSuggestionAccepted {
+			feedback.Accepted++
+		} else {
+			feedback.Rejected++
+		}
+		feedback.UpdatedAt = now
+		err = s.store.PutFeedback(ctx, feedback)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to update link feedback",
+			zap.String("userID", suggestion.UserID),
+			zap.Error(err),
+		)
+	}
+
+	s.logger.Info("Link suggestion resolved",
+		zap.String("suggestionID", suggestion.ID),
+		zap.String("userID", suggestion.UserID),
+		zap.String("status", string(status)),
+	)
+	return suggestion, nil
+}
+
+// resolveGraph returns the ID of a graph the user owns, defaulting to their
+// default graph
+func (s *LinkPredictionService) resolveGraph(ctx context.Context, userID, graphID string) (string, error) {
+	if graphID == "" {
+		graph, err := s.graphRepo.GetUserDefaultGraph(ctx, userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get graph: %w", err)
+		}
+		return graph.ID().String(), nil
+	}
+	graph, err := s.graphRepo.GetByID(ctx, aggregates.GraphID(graphID))
+	if err != nil {
+		return "", fmt.Errorf("failed to get graph: %w", err)
+	}
+	if graph == nil {
+		return "", fmt.Errorf("graph not found")
+	}
+	if graph.UserID() != userID {
+		return "", fmt.Errorf("unauthorized access to graph")
+	}
+	return graphID, nil
+}
+
+// tuneLinkThreshold moves a threshold part of the way past a resolved
+// suggestion's score: above a rejected one, below an accepted one. Outcomes
+// that agree with the threshold leave it where it is.
+func tuneLinkThreshold(threshold, score float64, accepted bool) float64 {
+	if accepted {
+		if target := score - linkThresholdMargin; target < threshold {
+			threshold -= linkThresholdRate * (threshold - target)
+		}
+	} else {
+		if target := score + linkThresholdMargin; target > threshold {
+			threshold += linkThresholdRate * (target - threshold)
+		}
+	}
+	return math.Max(MinLinkThreshold, math.Min(MaxLinkThreshold, threshold))
+}
+
+// linkSuggestionID derives a stable ID from a graph and an unordered node
+// pair, so refreshes update a pending suggestion rather than adding another
+func linkSuggestionID(graphID string, a, b valueobjects.NodeID) string {
+	first, second := a.String(), b.String()
+	if second < first {
+		first, second = second, first
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(graphID+"|"+first+"|"+second)).String()
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	domainservices "backend/domain/services"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ReembedConfig configures the re-embedding job.
+type ReembedConfig struct {
+	// BatchSize is the number of nodes embedded per call to the embedding
+	// service; progress is reported after each batch.
+	BatchSize int
+}
+
+// DefaultReembedConfig returns reasonable defaults.
+func DefaultReembedConfig() *ReembedConfig {
+	return &ReembedConfig{BatchSize: 32}
+}
+
+// ReembedProgress is the progress of a re-embedding job, reported as the
+// result of its operation.
+type ReembedProgress struct {
+	Model      string `json:"model"`
+	Dimensions int    `json:"dimensions"`
+	Total      int    `json:"total"`     // Nodes of the user
+	Processed  int    `json:"processed"` // Nodes looked at so far
+	Embedded   int    `json:"embedded"`
+	Skipped    int    `json:"skipped"` // Nodes whose embedding was already current
+	Failed     int    `json:"failed"`
+}
+
+// ReembedService re-embeds all nodes of a user, typically after the
+// embedding model changed. Nodes that already have an embedding of their
+// current content by the configured model are skipped, so a job that was
+// interrupted resumes where it stopped when it is started again. Progress is
+// reported through the operation store.
+type ReembedService struct {
+	nodeRepo    ports.NodeRepository
+	embeddings  domainservices.EmbeddingService
+	operations  ports.OperationStore
+	vectorIndex *VectorIndexService // optional
+	config      *ReembedConfig
+	logger      *zap.Logger
+
+	mu      sync.Mutex
+	running map[string]string // user ID -> operation ID
+}
+
+// NewReembedService creates a new re-embedding service.
+func NewReembedService(
+	nodeRepo ports.NodeRepository,
+	embeddings domainservices.EmbeddingService,
+	operations ports.OperationStore,
+	config *ReembedConfig,
+	logger *zap.Logger,
+) *ReembedService {
+	if config == nil || config.BatchSize <= 0 {
+		config = DefaultReembedConfig()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ReembedService{
+		nodeRepo:   nodeRepo,
+		embeddings: embeddings,
+		operations: operations,
+		config:     config,
+		logger:     logger,
+		running:    make(map[string]string),
+	}
+}
+
+// WithVectorIndex reconciles the user's vector index once a job finishes.
+func (s *ReembedService) WithVectorIndex(vectorIndex *VectorIndexService) *ReembedService {
+	s.vectorIndex = vectorIndex
+	return s
+}
+
+// Model returns the embedding model nodes are re-embedded with.
+func (s *ReembedService) Model() string {
+	return s.embeddings.Model()
+}
+
+// Start re-embeds the user's nodes in the background and returns the ID of
+// the operation reporting its progress. When a job for the user is already
+// running, its operation ID is returned instead of starting another.
+func (s *ReembedService) Start(ctx context.Context, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if operationID, ok := s.running[userID]; ok {
+		return operationID, nil
+	}
+
+	operationID := uuid.New().String()
+	if err := s.operations.Store(ctx, s.operation(operationID, userID, time.Now(), ReembedProgress{})); err != nil {
+		return "", fmt.Errorf("failed to store operation: %w", err)
+	}
+	s.running[userID] = operationID
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, userID)
+			s.mu.Unlock()
+		}()
+		// The job outlives the request that started it
+		if _, err := s.Run(context.Background(), userID, operationID); err != nil {
+			s.logger.Error("Re-embedding failed",
+				zap.String("userID", userID),
+				zap.String("operationID", operationID),
+				zap.Error(err),
+			)
+		}
+	}()
+	return operationID, nil
+}
+
+// Run re-embeds the user's nodes and returns the final progress. When
+// operationID is not empty, progress is written to that operation after each
+// batch and it is completed or failed at the end. Failing to embed a batch
+// counts its nodes as failed and continues; failing to load the nodes fails
+// the job.
+func (s *ReembedService) Run(ctx context.Context, userID, operationID string) (ReembedProgress, error) {
+	startedAt := time.Now()
+	progress := ReembedProgress{Model: s.embeddings.Model(), Dimensions: s.embeddings.Dimensions()}
+
+	nodes, err := s.nodeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("failed to load nodes: %w", err)
+		s.finish(ctx, operationID, userID, startedAt, progress, err)
+		return progress, err
+	}
+	progress.Total = len(nodes)
+
+	for start := 0; start < len(nodes); start += s.config.BatchSize {
+		if err := ctx.Err(); err != nil {
+			s.finish(ctx, operationID, userID, startedAt, progress, err)
+			return progress, err
+		}
+		end := min(start+s.config.BatchSize, len(nodes))
+		s.embedBatch(ctx, nodes[start:end], &progress)
+		s.report(ctx, operationID, userID, startedAt, progress)
+	}
+
+	if s.vectorIndex != nil {
+		if err := s.vectorIndex.Sync(ctx, userID); err != nil {
+			s.logger.Warn("Failed to sync vector index after re-embedding", zap.String("userID", userID), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Re-embedding complete",
+		zap.String("userID", userID),
+		zap.String("model", progress.Model),
+		zap.Int("embedded", progress.Embedded),
+		zap.Int("skipped", progress.Skipped),
+		zap.Int("failed", progress.Failed),
+	)
+	s.finish(ctx, operationID, userID, startedAt, progress, nil)
+	return progress, nil
+}
+
+// embedBatch embeds the nodes of a batch that need it and saves them
+func (s *ReembedService) embedBatch(ctx context.Context, batch []*entities.Node, progress *ReembedProgress) {
+	model := s.embeddings.Model()
+	pending := make([]*entities.Node, 0, len(batch))
+	texts := make([]string, 0, len(batch))
+	for _, node := range batch {
+		progress.Processed++
+		if !node.NeedsEmbedding(model) {
+			progress.Skipped++
+			continue
+		}
+		text := node.EmbeddingText()
+		if text == "" {
+			progress.Skipped++
+			continue
+		}
+		pending = append(pending, node)
+		texts = append(texts, text)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	embeddings, err := s.embeddings.GenerateEmbeddings(ctx, texts)
+	if err != nil || len(embeddings) != len(pending) {
+		s.logger.Warn("Failed to embed batch", zap.Int("nodes", len(pending)), zap.Error(err))
+		progress.Failed += len(pending)
+		return
+	}
+
+	for i, node := range pending {
+		// Reload the node so edits made while the batch was embedded are kept;
+		// a node whose content changed meanwhile is embedded by its own update
+		current, err := s.nodeRepo.GetByID(ctx, node.ID())
+		if err != nil {
+			progress.Failed++
+			continue
+		}
+		if current.EmbeddingText() != texts[i] {
+			progress.Skipped++
+			continue
+		}
+		current.SetEmbedding(embeddings[i])
+		if err := s.nodeRepo.Save(ctx, current); err != nil {
+			s.logger.Warn("Failed to save node embedding", zap.String("nodeID", node.ID().String()), zap.Error(err))
+			progress.Failed++
+			continue
+		}
+		progress.Embedded++
+	}
+}
+
+func (s *ReembedService) operation(operationID, userID string, startedAt time.Time, progress ReembedProgress) *ports.OperationResult {
+	return &ports.OperationResult{
+		OperationID: operationID,
+		Status:      ports.OperationStatusPending,
+		StartedAt:   startedAt,
+		Result:      progress,
+		Metadata: map[string]interface{}{
+			"user_id": userID,
+			"type":    "reembed",
+			"model":   s.embeddings.Model(),
+		},
+	}
+}
+
+// report records the progress of a running job
+func (s *ReembedService) report(ctx context.Context, operationID, userID string, startedAt time.Time, progress ReembedProgress) {
+	if operationID == "" {
+		return
+	}
+	if err := s.operations.Update(ctx, operationID, s.operation(operationID, userID, startedAt, progress)); err != nil {
+		s.logger.Warn("Failed to report re-embedding progress", zap.String("operationID", operationID), zap.Error(err))
+	}
+}
+
+// finish completes the job's operation, or fails it with err
+func (s *ReembedService) finish(ctx context.Context, operationID, userID string, startedAt time.Time, progress ReembedProgress, err error) {
+	if operationID == "" {
+		return
+	}
+	result := s.operation(operationID, userID, startedAt, progress)
+	completedAt := time.Now()
+	result.CompletedAt = &completedAt
+	result.Status = ports.OperationStatusCompleted
+	if err != nil {
+		result.Status = ports.OperationStatusFailed
+		result.Error = err.Error()
+	}
+	// The job's context may be cancelled already
+	if updateErr := s.operations.Update(context.WithoutCancel(ctx), operationID, result); updateErr != nil {
+		s.logger.Warn("Failed to complete re-embedding operation", zap.String("operationID", operationID), zap.Error(updateErr))
+	}
+}
@@ -3,24 +3,36 @@ package services
 import (
 	"context"
 	"math"
+	"sort"
+	"strings"
 
 	"backend/application/ports"
 	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/search"
 	domainservices "backend/domain/services"
+	"backend/domain/specifications"
 )
 
 const (
 	// rrfK is the Reciprocal Rank Fusion constant. K=60 is standard in the literature.
 	rrfK = 60
+	// semanticCandidates is how many nearest neighbours the vector index returns
+	// for fusion. Ranks beyond it add almost nothing to an RRF score.
+	semanticCandidates = 100
+	// maxKeywordMatches bounds the BM25 matches read from the keyword index.
+	// Totals and facets cover the keyword matches up to this many.
+	maxKeywordMatches = 1000
 )
 
 // SearchResult represents a single search result with scoring metadata.
 type SearchResult struct {
 	Node          *entities.Node
-	Score         float64  // Combined RRF score
-	BM25Score     float64  // Raw BM25 score (0 if not found by BM25)
-	SemanticScore float64  // Raw cosine similarity (0 if not found by semantic)
-	Sources       []string // Which methods found this node: "bm25", "semantic"
+	Score         float64          // Combined RRF score
+	BM25Score     float64          // Raw BM25 score (0 if not found by BM25)
+	SemanticScore float64          // Raw cosine similarity (0 if not found by semantic)
+	Sources       []string         // Which methods found this node: "bm25", "semantic", or "filter" for filter-only queries
+	Snippets      []search.Snippet // Best matching passages of the title and body
 }
 
 // SearchConfig configures the hybrid search service.
@@ -42,6 +54,8 @@ type HybridSearchService struct {
 	embeddingService domainservices.EmbeddingService
 	textAnalyzer     domainservices.TextAnalyzer
 	nodeRepo         ports.NodeRepository
+	vectorIndex      *VectorIndexService
+	keywordIndex     *KeywordIndexService
 	config           *SearchConfig
 }
 
@@ -72,64 +86,265 @@ func NewHybridSearchService(
 	}
 }
 
-// Search performs a hybrid search across the user's nodes.
+// WithVectorIndex makes semantic search query the vector index instead of
+// comparing the query with every node.
+func (s *HybridSearchService) WithVectorIndex(vectorIndex *VectorIndexService) *HybridSearchService {
+	s.vectorIndex = vectorIndex
+	return s
+}
+
+// WithKeywordIndex makes BM25 search read the keyword index instead of
+// tokenizing every node. Together with a vector index, search no longer loads
+// all of the user's nodes.
+func (s *HybridSearchService) WithKeywordIndex(keywordIndex *KeywordIndexService) *HybridSearchService {
+	s.keywordIndex = keywordIndex
+	return s
+}
+
+// SearchResponse is a page of ranked results, with the total and the facets
+// of every matching node.
+type SearchResponse struct {
+	Results []SearchResult
+	Total   int
+	Facets  *search.Facets
+}
+
+// Search parses a query in the search query language and performs a hybrid
+// search across the user's nodes. An invalid query returns a *search.ParseError.
 func (s *HybridSearchService) Search(ctx context.Context, userID string, query string, limit int) ([]SearchResult, error) {
+	parsed, err := search.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.SearchQuery(ctx, userID, parsed, limit)
+}
+
+// SearchQuery performs a hybrid search for a parsed query and returns the
+// best results.
+func (s *HybridSearchService) SearchQuery(ctx context.Context, userID string, query *search.Query, limit int) ([]SearchResult, error) {
+	response, err := s.SearchPage(ctx, userID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// SearchPage performs a hybrid search for a parsed query. Its free text ranks
+// the results and its filters must be satisfied by every result; a query with
+// filters only returns the matching nodes, most recently updated first.
+// Results carry highlighted snippets of the passages that matched.
+func (s *HybridSearchService) SearchPage(ctx context.Context, userID string, query *search.Query, limit int) (*SearchResponse, error) {
 	if limit <= 0 {
 		limit = s.config.MaxResults
 	}
 
-	// Load all user nodes — at personal scale (<10K) this is fine
-	nodes, err := s.nodeRepo.GetByUserID(ctx, userID)
+	filter := query.Specification()
+	text := query.FreeText()
+	var matches []SearchResult
+	var err error
+	switch {
+	case text != "":
+		matches, err = s.rank(ctx, userID, text, filter, limit)
+	case filter != nil:
+		matches, err = s.filterOnly(ctx, userID, filter)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if len(nodes) == 0 {
-		return nil, nil
+
+	nodes := make([]*entities.Node, len(matches))
+	for i, m := range matches {
+		nodes[i] = m.Node
+	}
+	response := &SearchResponse{
+		Total:  len(matches),
+		Facets: search.CountFacets(nodes),
 	}
 
-	// Build node index for quick lookup
-	nodeIndex := make(map[string]*entities.Node, len(nodes))
-	for _, n := range nodes {
-		nodeIndex[n.ID().String()] = n
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	if text != "" {
+		highlighter := search.NewHighlighter(s.textAnalyzer.ExtractKeywords(text), query.Phrases()).
+			WithMatcher(domainservices.NewTermMatcher(s.textAnalyzer, text).Match)
+		for i := range matches {
+			content := matches[i].Node.Content()
+			matches[i].Snippets = highlighter.Snippets(content.Title(), content.Body())
+		}
 	}
+	response.Results = matches
+	return response, nil
+}
+
+// rank fuses the keyword and semantic rankings of text and returns every
+// ranked node satisfying filter, when there is one
+func (s *HybridSearchService) rank(ctx context.Context, userID, text string, filter specifications.NodeSpecification, limit int) ([]SearchResult, error) {
+	// The scorers analyze the terms, detecting the language from all of them
+	queryTerms := strings.Fields(text)
 
 	// --- BM25 ---
-	queryTerms := s.textAnalyzer.ExtractKeywords(query)
-	docs := make([]domainservices.DocumentRecord, len(nodes))
-	for i, n := range nodes {
-		content := n.Content()
-		docs[i] = domainservices.DocumentRecord{
-			ID:   n.ID().String(),
-			Text: content.Title() + " " + content.Body(),
+	var bm25Results []domainservices.ScoredDocument
+	indexed := false
+	if s.keywordIndex != nil {
+		if matches, err := s.keywordIndex.Score(ctx, userID, queryTerms, maxKeywordMatches); err == nil {
+			bm25Results = matches
+			indexed = true
+		}
+		// Fall back to scoring every node below
+	}
+
+	// Without both indexes, load all user nodes — fine at personal scale (<10K)
+	var nodes []*entities.Node
+	if !indexed || (s.embeddingService != nil && s.vectorIndex == nil) {
+		var err error
+		nodes, err = s.nodeRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodes) == 0 {
+			return nil, nil
 		}
 	}
-	bm25Results := s.bm25.Score(queryTerms, docs)
+
+	if !indexed {
+		docs := make([]domainservices.DocumentRecord, len(nodes))
+		for i, n := range nodes {
+			docs[i] = domainservices.DocumentRecord{
+				ID:   n.ID().String(),
+				Text: keywordText(n),
+			}
+		}
+		bm25Results = s.bm25.Score(queryTerms, docs)
+	}
 
 	// --- Semantic ---
 	var semanticResults []domainservices.ScoredDocument
 	if s.embeddingService != nil {
-		semanticResults = s.semanticSearch(ctx, query, nodes)
+		semanticResults = s.semanticSearch(ctx, userID, text, nodes, limit)
 	}
 
 	// --- RRF Fusion ---
-	merged := s.mergeWithRRF(bm25Results, semanticResults, nodeIndex)
+	ranked := fuseWithRRF(bm25Results, semanticResults)
 
-	// Limit results
-	if len(merged) > limit {
-		merged = merged[:limit]
+	var nodeIndex map[string]*entities.Node
+	if nodes != nil {
+		nodeIndex = make(map[string]*entities.Node, len(nodes))
+		for _, n := range nodes {
+			nodeIndex[n.ID().String()] = n
+		}
+	} else {
+		// Only the ranked nodes are read; totals and facets need all of them
+		var err error
+		nodeIndex, err = s.loadNodes(ctx, userID, ranked)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]SearchResult, 0, len(ranked))
+	for _, entry := range ranked {
+		node, ok := nodeIndex[entry.id]
+		if !ok || (filter != nil && !filter.IsSatisfiedBy(node)) {
+			continue
+		}
+		results = append(results, SearchResult{
+			Node:          node,
+			Score:         math.Round(entry.rrfScore*10000) / 10000,
+			BM25Score:     entry.bm25Score,
+			SemanticScore: entry.semanticScore,
+			Sources:       entry.sources,
+		})
 	}
 
-	return merged, nil
+	return results, nil
 }
 
-// semanticSearch embeds the query and computes cosine similarity against all nodes with embeddings.
-func (s *HybridSearchService) semanticSearch(ctx context.Context, query string, nodes []*entities.Node) []domainservices.ScoredDocument {
+// filterOnly returns the user's nodes satisfying filter, most recently
+// updated first
+func (s *HybridSearchService) filterOnly(ctx context.Context, userID string, filter specifications.NodeSpecification) ([]SearchResult, error) {
+	nodes, err := s.nodeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*entities.Node, 0)
+	for _, n := range nodes {
+		if filter.IsSatisfiedBy(n) {
+			matches = append(matches, n)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].UpdatedAt().After(matches[j].UpdatedAt())
+	})
+
+	results := make([]SearchResult, len(matches))
+	for i, n := range matches {
+		results[i] = SearchResult{Node: n, Sources: []string{"filter"}}
+	}
+	return results, nil
+}
+
+// loadNodes reads the user's nodes among the ranked entries. Nodes removed
+// since they were indexed are left out.
+func (s *HybridSearchService) loadNodes(ctx context.Context, userID string, ranked []rankedEntry) (map[string]*entities.Node, error) {
+	ids := make([]valueobjects.NodeID, 0, len(ranked))
+	for _, entry := range ranked {
+		id, err := valueobjects.NewNodeIDFromString(entry.id)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	loaded := make(map[valueobjects.NodeID]*entities.Node, len(ids))
+	if batch, ok := s.nodeRepo.(nodeBatchLoader); ok {
+		var err error
+		if loaded, err = batch.GetNodesByIDs(ctx, ids); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, id := range ids {
+			if node, err := s.nodeRepo.GetByID(ctx, id); err == nil {
+				loaded[id] = node
+			}
+		}
+	}
+
+	nodes := make(map[string]*entities.Node, len(loaded))
+	for id, node := range loaded {
+		if node != nil && node.UserID() == userID {
+			nodes[id.String()] = node
+		}
+	}
+	return nodes, nil
+}
+
+// semanticSearch embeds the query and returns the nearest nodes from the vector index,
+// or computes cosine similarity against all nodes with embeddings when there is no index.
+func (s *HybridSearchService) semanticSearch(ctx context.Context, userID, query string, nodes []*entities.Node, limit int) []domainservices.ScoredDocument {
 	queryEmbedding, err := s.embeddingService.GenerateEmbedding(ctx, query)
 	if err != nil {
 		// Degrade gracefully — BM25 results still work
 		return nil
 	}
 
+	if s.vectorIndex != nil {
+		k := semanticCandidates
+		if limit > k {
+			k = limit
+		}
+		if neighbours, err := s.vectorIndex.Search(ctx, userID, queryEmbedding, k); err == nil {
+			results := make([]domainservices.ScoredDocument, 0, len(neighbours))
+			for _, n := range neighbours {
+				if n.Score > 0 {
+					results = append(results, n)
+				}
+			}
+			return results
+		}
+		// Fall back to the full scan below
+	}
+
 	results := make([]domainservices.ScoredDocument, 0)
 	for _, n := range nodes {
 		if !n.HasEmbedding() {
@@ -148,24 +363,32 @@ func (s *HybridSearchService) semanticSearch(ctx context.Context, query string,
 	return results
 }
 
-// mergeWithRRF combines BM25 and semantic rankings using Reciprocal Rank Fusion.
-func (s *HybridSearchService) mergeWithRRF(
+// nodeBatchLoader is implemented by node repositories that can read many
+// nodes in one round trip
+type nodeBatchLoader interface {
+	GetNodesByIDs(ctx context.Context, nodeIDs []valueobjects.NodeID) (map[valueobjects.NodeID]*entities.Node, error)
+}
+
+// rankedEntry is one document of the fused ranking
+type rankedEntry struct {
+	id            string
+	rrfScore      float64
+	bm25Score     float64
+	semanticScore float64
+	sources       []string
+}
+
+// fuseWithRRF combines BM25 and semantic rankings using Reciprocal Rank Fusion.
+func fuseWithRRF(
 	bm25Results []domainservices.ScoredDocument,
 	semanticResults []domainservices.ScoredDocument,
-	nodeIndex map[string]*entities.Node,
-) []SearchResult {
-	type mergedEntry struct {
-		rrfScore      float64
-		bm25Score     float64
-		semanticScore float64
-		sources       []string
-	}
-
-	merged := make(map[string]*mergedEntry)
+) []rankedEntry {
+	merged := make(map[string]*rankedEntry)
 
 	for i, doc := range bm25Results {
 		rrfScore := 1.0 / float64(rrfK+i+1)
-		merged[doc.ID] = &mergedEntry{
+		merged[doc.ID] = &rankedEntry{
+			id:        doc.ID,
 			rrfScore:  rrfScore,
 			bm25Score: doc.Score,
 			sources:   []string{"bm25"},
@@ -179,7 +402,8 @@ func (s *HybridSearchService) mergeWithRRF(
 			existing.semanticScore = doc.Score
 			existing.sources = append(existing.sources, "semantic")
 		} else {
-			merged[doc.ID] = &mergedEntry{
+			merged[doc.ID] = &rankedEntry{
+				id:            doc.ID,
 				rrfScore:      rrfScore,
 				semanticScore: doc.Score,
 				sources:       []string{"semantic"},
@@ -187,26 +411,16 @@ func (s *HybridSearchService) mergeWithRRF(
 		}
 	}
 
-	results := make([]SearchResult, 0, len(merged))
-	for id, entry := range merged {
-		node, ok := nodeIndex[id]
-		if !ok {
-			continue
-		}
-		results = append(results, SearchResult{
-			Node:          node,
-			Score:         math.Round(entry.rrfScore*10000) / 10000,
-			BM25Score:     entry.bm25Score,
-			SemanticScore: entry.semanticScore,
-			Sources:       entry.sources,
-		})
+	results := make([]rankedEntry, 0, len(merged))
+	for _, entry := range merged {
+		results = append(results, *entry)
 	}
 
 	// Sort by RRF score descending
 	for i := 1; i < len(results); i++ {
 		key := results[i]
 		j := i - 1
-		for j >= 0 && results[j].Score < key.Score {
+		for j >= 0 && results[j].rrfScore < key.rrfScore {
 			results[j+1] = results[j]
 			j--
 		}
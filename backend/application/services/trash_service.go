@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/config"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// TrashService implements the trash bin. Deleting a node moves it and its
+// edges into the trash store, where it stays restorable until it is purged:
+// by hand, or once DomainConfig.NodeTTL has passed since it was trashed.
+// A NodeTTL of zero keeps trashed nodes until they are purged by hand.
+type TrashService struct {
+	trash      ports.TrashStore
+	nodeRepo   ports.NodeRepository
+	edgeRepo   ports.EdgeRepository
+	graphRepo  ports.GraphRepository
+	eventStore ports.EventStore
+	eventBus   ports.EventBus
+	retention  time.Duration
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// TrashRestoreResult describes a restored node
+type TrashRestoreResult struct {
+	Node          *entities.Node
+	RestoredEdges int
+	SkippedEdges  int // Edges whose other node is gone or itself in the trash
+}
+
+// NewTrashService creates a new trash service
+func NewTrashService(
+	trash ports.TrashStore,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	eventStore ports.EventStore,
+	eventBus ports.EventBus,
+	cfg *config.DomainConfig,
+	logger *zap.Logger,
+) *TrashService {
+	if cfg == nil {
+		cfg = config.DefaultDomainConfig()
+	}
+	return &TrashService{
+		trash:      trash,
+		nodeRepo:   nodeRepo,
+		edgeRepo:   edgeRepo,
+		graphRepo:  graphRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		retention:  cfg.NodeTTL,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Trash moves a node and its edges into the trash bin
+func (s *TrashService) Trash(ctx context.Context, node *entities.Node) (*ports.TrashedNode, error) {
+	trashed, err := s.TrashNodes(ctx, []*entities.Node{node})
+	if err != nil {
+		return nil, err
+	}
+	return trashed[0], nil
+}
+
+// TrashNodes moves nodes and their edges into the trash bin. It stops at the
+// first node that cannot be trashed and returns the nodes trashed before it
+// together with the error.
+func (s *TrashService) TrashNodes(ctx context.Context, nodes []*entities.Node) ([]*ports.TrashedNode, error) {
+	trashed := make([]*ports.TrashedNode, 0, len(nodes))
+	var trashErr error
+	for _, node := range nodes {
+		entry, err := s.trashNode(ctx, node)
+		if err != nil {
+			trashErr = fmt.Errorf("failed to trash node %s: %w", node.ID().String(), err)
+			break
+		}
+		trashed = append(trashed, entry)
+	}
+
+	graphIDs := make(map[string]bool)
+	trashedEvents := make([]events.DomainEvent, 0, len(trashed))
+	for _, entry := range trashed {
+		graphIDs[entry.GraphID] = true
+		nodeID, _ := valueobjects.NewNodeIDFromString(entry.NodeID)
+		trashedEvents = append(trashedEvents, events.NewNodeTrashedEvent(
+			nodeID, entry.UserID, entry.GraphID, entry.Title, len(entry.Edges), entry.PurgeAt, entry.TrashedAt,
+		))
+	}
+	for graphID := range graphIDs {
+		s.updateGraphMetadata(ctx, graphID)
+	}
+	if len(trashedEvents) > 0 {
+		if err := s.eventBus.PublishBatch(ctx, trashedEvents); err != nil {
+			s.logger.Warn("Failed to publish node trashed events", zap.Error(err))
+		}
+	}
+
+	return trashed, trashErr
+}
+
+// trashNode stores the node and its edges in the trash bin, then removes them
+// from the repositories. The node is removed at the version it was read, when
+// the repository supports conditional deletes.
+func (s *TrashService) trashNode(ctx context.Context, node *entities.Node) (*ports.TrashedNode, error) {
+	nodeID := node.ID().String()
+	graphID := node.GraphID()
+
+	edges, err := s.edgeRepo.GetByNodeID(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edges: %w", err)
+	}
+
+	now := s.now()
+	entry := &ports.TrashedNode{
+		NodeID:    nodeID,
+		UserID:    node.UserID(),
+		GraphID:   graphID,
+		Title:     node.Content().Title(),
+		Node:      node.Backup(),
+		Edges:     edges,
+		TrashedAt: now,
+	}
+	if s.retention > 0 {
+		purgeAt := now.Add(s.retention)
+		entry.PurgeAt = &purgeAt
+	}
+
+	if err := s.trash.Put(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to store trashed node: %w", err)
+	}
+
+	if err := s.deleteNode(ctx, node); err != nil {
+		// Keep the trash bin consistent with the node, which is still there
+		if cleanupErr := s.trash.Delete(ctx, entry.UserID, nodeID); cleanupErr != nil {
+			s.logger.Error("Failed to remove trash entry of node that was not deleted",
+				zap.String("nodeID", nodeID),
+				zap.Error(cleanupErr),
+			)
+		}
+		return nil, err
+	}
+
+	if graphID != "" && len(edges) > 0 {
+		if err := s.edgeRepo.DeleteByNodeID(ctx, graphID, nodeID); err != nil {
+			s.logger.Warn("Failed to delete edges of trashed node",
+				zap.String("nodeID", nodeID),
+				zap.String("graphID", graphID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return entry, nil
+}
+
+func (s *TrashService) deleteNode(ctx context.Context, node *entities.Node) error {
+	if repo, ok := s.nodeRepo.(interface {
+		DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error
+	}); ok {
+		return repo.DeleteWithVersion(ctx, node.ID(), node.Version())
+	}
+	return s.nodeRepo.Delete(ctx, node.ID())
+}
+
+// List returns a user's trashed nodes, most recently trashed first
+func (s *TrashService) List(ctx context.Context, userID string) ([]*ports.TrashedNode, error) {
+	return s.trash.ListByUser(ctx, userID)
+}
+
+// Restore brings a trashed node back into its graph and reconnects the edges
+// it had. Edges to nodes that are gone, or still in the trash, are skipped;
+// the latter are handed over to the other node's trash entry, so restoring
+// that node later reconnects them.
+func (s *TrashService) Restore(ctx context.Context, userID, nodeID string) (*TrashRestoreResult, error) {
+	entry, err := s.trash.Get(ctx, userID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.graphRepo.GetByID(ctx, aggregates.GraphID(entry.GraphID)); err != nil {
+		return nil, pkgerrors.NewConflictError("the graph of this node no longer exists").WithCause(err)
+	}
+
+	node, err := entities.NodeFromBackup(entry.Node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild trashed node: %w", err)
+	}
+	if err := s.nodeRepo.Save(ctx, node); err != nil {
+		return nil, fmt.Errorf("failed to restore node: %w", err)
+	}
+
+	result := &TrashRestoreResult{Node: node}
+	for _, edge := range entry.Edges {
+		otherID := edge.TargetID
+		if otherID.Equals(node.ID()) {
+			otherID = edge.SourceID
+		}
+		if other, err := s.nodeRepo.GetByID(ctx, otherID); err != nil || other == nil {
+			s.handOverEdge(ctx, userID, otherID.String(), edge)
+			result.SkippedEdges++
+			continue
+		}
+		if err := s.edgeRepo.Save(ctx, entry.GraphID, edge); err != nil {
+			s.logger.Warn("Failed to restore edge",
+				zap.String("nodeID", nodeID),
+				zap.String("sourceID", edge.SourceID.String()),
+				zap.String("targetID", edge.TargetID.String()),
+				zap.Error(err),
+			)
+			result.SkippedEdges++
+			continue
+		}
+		result.RestoredEdges++
+	}
+
+	if err := s.trash.Delete(ctx, userID, nodeID); err != nil {
+		s.logger.Warn("Failed to remove trash entry of restored node",
+			zap.String("nodeID", nodeID),
+			zap.Error(err),
+		)
+	}
+	s.updateGraphMetadata(ctx, entry.GraphID)
+
+	event := events.NewNodeRestoredEvent(node.ID(), userID, entry.GraphID, entry.Title, result.RestoredEdges, s.now())
+	if err := s.eventBus.PublishBatch(ctx, []events.DomainEvent{event}); err != nil {
+		s.logger.Warn("Failed to publish node restored event", zap.Error(err))
+	}
+
+	s.logger.Info("Node restored from trash",
+		zap.String("nodeID", nodeID),
+		zap.String("userID", userID),
+		zap.Int("restoredEdges", result.RestoredEdges),
+		zap.Int("skippedEdges", result.SkippedEdges),
+	)
+	return result, nil
+}
+
+// handOverEdge adds an edge that could not be restored to the trash entry of
+// its other node, if that node is in the trash
+func (s *TrashService) handOverEdge(ctx context.Context, userID, otherID string, edge *aggregates.Edge) {
+	other, err := s.trash.Get(ctx, userID, otherID)
+	if err != nil {
+		return
+	}
+	for _, existing := range other.Edges {
+		if existing.SourceID.Equals(edge.SourceID) && existing.TargetID.Equals(edge.TargetID) {
+			return
+		}
+	}
+	other.Edges = append(other.Edges, edge)
+	if err := s.trash.Put(ctx, other); err != nil {
+		s.logger.Warn("Failed to hand over edge to trashed node",
+			zap.String("nodeID", otherID),
+			zap.Error(err),
+		)
+	}
+}
+
+// Purge permanently deletes a trashed node
+func (s *TrashService) Purge(ctx context.Context, userID, nodeID string) error {
+	entry, err := s.trash.Get(ctx, userID, nodeID)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, entry)
+}
+
+// PurgeExpired permanently deletes up to limit trashed nodes whose retention
+// has passed and returns how many were purged
+func (s *TrashService) PurgeExpired(ctx context.Context, limit int) (int, error) {
+	expired, err := s.trash.ListExpired(ctx, s.now(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired trash: %w", err)
+	}
+
+	purged := 0
+	for _, entry := range expired {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+		if err := s.purge(ctx, entry); err != nil {
+			s.logger.Warn("Failed to purge trashed node",
+				zap.String("nodeID", entry.NodeID),
+				zap.Error(err),
+			)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purge removes the event history of a trashed node and then its trash entry,
+// so a failed purge is retried on the next run
+func (s *TrashService) purge(ctx context.Context, entry *ports.TrashedNode) error {
+	nodeID, err := valueobjects.NewNodeIDFromString(entry.NodeID)
+	if err != nil {
+		return fmt.Errorf("invalid trashed node ID: %w", err)
+	}
+
+	// A restore that could not remove its trash entry leaves a stale one
+	// behind; the node is live again, so its history must be kept
+	if live, err := s.nodeRepo.GetByID(ctx, nodeID); err == nil && live != nil {
+		return s.trash.Delete(ctx, entry.UserID, entry.NodeID)
+	}
+
+	if s.eventStore != nil {
+		if err := s.eventStore.DeleteEvents(ctx, entry.NodeID); err != nil {
+			return fmt.Errorf("failed to delete events: %w", err)
+		}
+	}
+	if err := s.trash.Delete(ctx, entry.UserID, entry.NodeID); err != nil {
+		return fmt.Errorf("failed to delete trash entry: %w", err)
+	}
+
+	event := events.NewNodePurgedEvent(nodeID, entry.UserID, entry.GraphID, s.now())
+	if err := s.eventBus.PublishBatch(ctx, []events.DomainEvent{event}); err != nil {
+		s.logger.Warn("Failed to publish node purged event", zap.Error(err))
+	}
+
+	s.logger.Info("Purged trashed node",
+		zap.String("nodeID", entry.NodeID),
+		zap.String("userID", entry.UserID),
+	)
+	return nil
+}
+
+func (s *TrashService) updateGraphMetadata(ctx context.Context, graphID string) {
+	if graphID == "" {
+		return
+	}
+	if err := s.graphRepo.UpdateGraphMetadata(ctx, graphID); err != nil {
+		s.logger.Warn("Failed to update graph metadata",
+			zap.String("graphID", graphID),
+			zap.Error(err),
+		)
+	}
+}
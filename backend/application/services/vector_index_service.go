@@ -0,0 +1,469 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	domainservices "backend/domain/services"
+	pkgerrors "backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// VectorIndexConfig configures the vector index service.
+type VectorIndexConfig struct {
+	HNSW *domainservices.HNSWConfig
+	// SyncInterval is how often a loaded index is reconciled with the node
+	// repository, which picks up embeddings written by other processes such
+	// as the embedding worker. Zero disables periodic reconciliation.
+	SyncInterval time.Duration
+	// SaveEvery is the number of changes after which an index is persisted;
+	// zero or less persists after every change.
+	SaveEvery int
+	// Model is the embedding model in use. Embeddings another model produced
+	// are left out of the index until they are re-embedded; empty indexes
+	// every embedding.
+	Model string
+}
+
+// DefaultVectorIndexConfig returns reasonable defaults.
+func DefaultVectorIndexConfig() *VectorIndexConfig {
+	return &VectorIndexConfig{
+		HNSW:         domainservices.DefaultHNSWConfig(),
+		SyncInterval: 5 * time.Minute,
+		SaveEvery:    100,
+	}
+}
+
+// VectorIndexService keeps an approximate nearest-neighbour index of each
+// user's node embeddings, so semantic search does not compare the query with
+// every node. A user's index is loaded on first use, from the store when one
+// was saved, and reconciled with the node repository; afterwards node events
+// keep it current. The index is a cache of the node repository and can always
+// be rebuilt from it.
+type VectorIndexService struct {
+	store    ports.VectorIndexStore // nil keeps indexes in memory only
+	nodeRepo ports.NodeRepository
+	config   *VectorIndexConfig
+	logger   *zap.Logger
+
+	mu    sync.Mutex
+	users map[string]*userVectorIndex
+}
+
+// userVectorIndex is the loaded index of one user
+type userVectorIndex struct {
+	mu       sync.RWMutex
+	index    *domainservices.HNSW
+	graphs   map[string]string // node ID -> graph ID
+	changes  int               // changes since the index was last persisted
+	syncedAt time.Time
+}
+
+// NewVectorIndexService creates a new vector index service.
+func NewVectorIndexService(
+	store ports.VectorIndexStore,
+	nodeRepo ports.NodeRepository,
+	config *VectorIndexConfig,
+	logger *zap.Logger,
+) *VectorIndexService {
+	if config == nil {
+		config = DefaultVectorIndexConfig()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &VectorIndexService{
+		store:    store,
+		nodeRepo: nodeRepo,
+		config:   config,
+		logger:   logger,
+		users:    make(map[string]*userVectorIndex),
+	}
+}
+
+// Search returns up to k of the user's nodes closest to the embedding, sorted
+// by cosine similarity descending.
+func (s *VectorIndexService) Search(ctx context.Context, userID string, embedding valueobjects.Embedding, k int) ([]domainservices.ScoredDocument, error) {
+	if embedding.IsZero() || k <= 0 {
+		return nil, nil
+	}
+	u, err := s.acquire(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.index.Search(embedding.Vector(), k)
+}
+
+// SimilarTo returns up to k of the user's nodes closest to the given node,
+// excluding the node itself. A node without an embedding has no neighbours.
+func (s *VectorIndexService) SimilarTo(ctx context.Context, userID string, node *entities.Node, k int) ([]domainservices.ScoredDocument, error) {
+	if !node.HasEmbedding() {
+		return nil, nil
+	}
+	results, err := s.Search(ctx, userID, node.Embedding(), k+1)
+	if err != nil {
+		return nil, err
+	}
+
+	id := node.ID().String()
+	similar := make([]domainservices.ScoredDocument, 0, k)
+	for _, r := range results {
+		if r.ID == id {
+			continue
+		}
+		similar = append(similar, r)
+		if len(similar) == k {
+			break
+		}
+	}
+	return similar, nil
+}
+
+// IndexNode adds or replaces a node's embedding, or removes the node when it
+// has none. Nodes of users whose index is not loaded are ignored; the next
+// load picks them up.
+func (s *VectorIndexService) IndexNode(ctx context.Context, node *entities.Node) {
+	u := s.loaded(node.UserID())
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	changed := s.upsert(u, node)
+	u.mu.Unlock()
+
+	if changed {
+		s.changed(ctx, node.UserID(), u, 1)
+	}
+}
+
+// RemoveNodes removes nodes from a user's index.
+func (s *VectorIndexService) RemoveNodes(ctx context.Context, userID string, nodeIDs []string) {
+	u := s.loaded(userID)
+	if u == nil {
+		return
+	}
+
+	removed := 0
+	u.mu.Lock()
+	for _, id := range nodeIDs {
+		if u.index.Remove(id) {
+			removed++
+		}
+		delete(u.graphs, id)
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, removed)
+}
+
+// SyncGraph reconciles the nodes of one graph with the index, for changes
+// that replace many nodes at once such as rollbacks and moves.
+func (s *VectorIndexService) SyncGraph(ctx context.Context, userID, graphID string) error {
+	u := s.loaded(userID)
+	if u == nil {
+		return nil
+	}
+
+	nodes, err := s.nodeRepo.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	changes := 0
+	present := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		if node.UserID() != userID {
+			continue
+		}
+		present[node.ID().String()] = struct{}{}
+		if s.upsert(u, node) {
+			changes++
+		}
+	}
+	for id, g := range u.graphs {
+		if _, ok := present[id]; g == graphID && !ok {
+			u.index.Remove(id)
+			delete(u.graphs, id)
+			changes++
+		}
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, changes)
+	return nil
+}
+
+// RemoveGraph removes every node of a graph from the user's index.
+func (s *VectorIndexService) RemoveGraph(ctx context.Context, userID, graphID string) {
+	u := s.loaded(userID)
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	removed := 0
+	for id, g := range u.graphs {
+		if g == graphID {
+			u.index.Remove(id)
+			delete(u.graphs, id)
+			removed++
+		}
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, removed)
+}
+
+// Sync reconciles a user's index with the node repository now, loading it if
+// needed.
+func (s *VectorIndexService) Sync(ctx context.Context, userID string) error {
+	u := s.entry(userID)
+	u.mu.Lock()
+	if u.index == nil {
+		u.mu.Unlock()
+		_, err := s.acquire(ctx, userID)
+		return err
+	}
+	changes, err := s.reconcile(ctx, userID, u)
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.changed(ctx, userID, u, changes)
+	return nil
+}
+
+// Flush persists every index with unsaved changes.
+func (s *VectorIndexService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	users := make(map[string]*userVectorIndex, len(s.users))
+	for userID, u := range s.users {
+		users[userID] = u
+	}
+	s.mu.Unlock()
+
+	var lastErr error
+	for userID, u := range users {
+		u.mu.RLock()
+		dirty := u.index != nil && u.changes > 0
+		u.mu.RUnlock()
+		if !dirty {
+			continue
+		}
+		if err := s.save(ctx, userID, u); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// entry returns the user's index slot, creating an empty one
+func (s *VectorIndexService) entry(userID string) *userVectorIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userID]
+	if !ok {
+		u = &userVectorIndex{}
+		s.users[userID] = u
+	}
+	return u
+}
+
+// loaded returns the user's index if it has been loaded
+func (s *VectorIndexService) loaded(userID string) *userVectorIndex {
+	s.mu.Lock()
+	u, ok := s.users[userID]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if u.index == nil {
+		return nil
+	}
+	return u
+}
+
+// acquire returns the user's index, loading it on first use and reconciling
+// it when the last reconciliation is older than the sync interval
+func (s *VectorIndexService) acquire(ctx context.Context, userID string) (*userVectorIndex, error) {
+	u := s.entry(userID)
+
+	u.mu.RLock()
+	fresh := u.index != nil && (s.config.SyncInterval <= 0 || time.Since(u.syncedAt) < s.config.SyncInterval)
+	u.mu.RUnlock()
+	if fresh {
+		return u, nil
+	}
+
+	u.mu.Lock()
+	changes := 0
+	if u.index == nil {
+		restored := s.restore(ctx, userID, u)
+		n, err := s.reconcile(ctx, userID, u)
+		if err != nil {
+			u.index = nil
+			u.mu.Unlock()
+			return nil, err
+		}
+		changes = n
+		if !restored && u.index.Len() > 0 {
+			// A fresh build is the expensive case, so keep it right away
+			changes = s.config.SaveEvery
+		}
+	} else if s.config.SyncInterval > 0 && time.Since(u.syncedAt) >= s.config.SyncInterval {
+		n, err := s.reconcile(ctx, userID, u)
+		if err != nil {
+			// A stale index still answers searches
+			s.logger.Warn("Failed to reconcile vector index", zap.String("userID", userID), zap.Error(err))
+		}
+		changes = n
+	}
+	u.mu.Unlock()
+
+	s.changed(ctx, userID, u, changes)
+	return u, nil
+}
+
+// restore loads the user's saved index into u, or starts an empty one.
+// Reports whether a saved index was used. The caller holds u.mu.
+func (s *VectorIndexService) restore(ctx context.Context, userID string, u *userVectorIndex) bool {
+	u.index = domainservices.NewHNSW(s.config.HNSW)
+	u.graphs = make(map[string]string)
+	u.changes = 0
+	if s.store == nil {
+		return false
+	}
+
+	data, err := s.store.Load(ctx, userID)
+	if err != nil {
+		if !pkgerrors.IsNotFound(err) {
+			s.logger.Warn("Failed to load vector index, rebuilding", zap.String("userID", userID), zap.Error(err))
+		}
+		return false
+	}
+	if err := u.index.UnmarshalBinary(data); err != nil {
+		s.logger.Warn("Discarding unreadable vector index", zap.String("userID", userID), zap.Error(err))
+		u.index = domainservices.NewHNSW(s.config.HNSW)
+		return false
+	}
+	return true
+}
+
+// reconcile brings the index in line with the user's nodes, inserting only
+// embeddings that are missing or changed. The caller holds u.mu.
+func (s *VectorIndexService) reconcile(ctx context.Context, userID string, u *userVectorIndex) (int, error) {
+	nodes, err := s.nodeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	changes := 0
+	present := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		present[node.ID().String()] = struct{}{}
+		if s.upsert(u, node) {
+			changes++
+		}
+	}
+	for _, id := range u.index.IDs() {
+		if _, ok := present[id]; !ok {
+			u.index.Remove(id)
+			changes++
+		}
+	}
+	for id := range u.graphs {
+		if _, ok := present[id]; !ok {
+			delete(u.graphs, id)
+		}
+	}
+
+	u.syncedAt = time.Now()
+	return changes, nil
+}
+
+// upsert indexes a node's current embedding. Reports whether the index
+// changed. The caller holds u.mu.
+func (s *VectorIndexService) upsert(u *userVectorIndex, node *entities.Node) bool {
+	id := node.ID().String()
+	if !s.indexable(node) {
+		delete(u.graphs, id)
+		return u.index.Remove(id)
+	}
+
+	u.graphs[id] = node.GraphID()
+	vector := node.Embedding().Vector()
+	if u.index.Matches(id, vector) {
+		return false
+	}
+	if err := u.index.Add(id, vector); err != nil {
+		s.logger.Warn("Failed to index node embedding", zap.String("nodeID", id), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// indexable reports whether a node has an embedding of the configured model.
+// Embeddings without a recorded model predate provenance and are kept.
+func (s *VectorIndexService) indexable(node *entities.Node) bool {
+	if !node.HasEmbedding() {
+		return false
+	}
+	model := node.Embedding().Model()
+	return s.config.Model == "" || model == "" || model == s.config.Model
+}
+
+// changed records changes to an index and persists it once enough have
+// accumulated
+func (s *VectorIndexService) changed(ctx context.Context, userID string, u *userVectorIndex, changes int) {
+	if changes <= 0 {
+		return
+	}
+	u.mu.Lock()
+	u.changes += changes
+	due := u.changes >= s.config.SaveEvery
+	u.mu.Unlock()
+
+	if due {
+		if err := s.save(ctx, userID, u); err != nil {
+			s.logger.Warn("Failed to persist vector index", zap.String("userID", userID), zap.Error(err))
+		}
+	}
+}
+
+// save persists a user's index
+func (s *VectorIndexService) save(ctx context.Context, userID string, u *userVectorIndex) error {
+	if s.store == nil {
+		return nil
+	}
+
+	u.mu.Lock()
+	data, err := u.index.MarshalBinary()
+	pending := u.changes
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Save(ctx, userID, data); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	u.changes -= pending
+	u.mu.Unlock()
+	return nil
+}
@@ -12,7 +12,6 @@ import (
 	"backend/infrastructure/config"
 	"backend/infrastructure/di"
 	"backend/infrastructure/messaging"
-	"backend/infrastructure/messaging/eventbridge"
 	"backend/interfaces/http/rest"
 
 	"go.uber.org/zap"
@@ -39,6 +38,10 @@ func main() {
 	err = di.WireEventHandlers(
 		container.EventHandlerRegistry,
 		container.OperationEventListener,
+		container.EmbeddingListener,
+		container.VectorIndexListener,
+		container.KeywordIndexListener,
+		container.CommunityListener,
 		container.GraphStatsProjection,
 		container.Logger,
 	)
@@ -46,8 +49,10 @@ func main() {
 		log.Fatalf("Failed to wire event handlers: %v", err)
 	}
 
-	// Set up local event dispatcher for EventBridge
-	if eventBus, ok := container.EventBus.(*eventbridge.EventBridgePublisher); ok {
+	// Set up local event dispatcher (EventBridge publisher or offline local bus)
+	if eventBus, ok := container.EventBus.(interface {
+		SetLocalDispatcher(*messaging.EventDispatcher)
+	}); ok {
 		dispatcher := messaging.NewEventDispatcher(container.EventHandlerRegistry, container.Logger)
 		eventBus.SetLocalDispatcher(dispatcher)
 		container.Logger.Info("Local event dispatcher configured")
@@ -62,6 +67,12 @@ func main() {
 	)
 	router.SetCommunityService(container.CommunityService)
 	router.SetAnalysisService(container.AnalysisService)
+	router.SetOutbox(container.Outbox)
+	router.SetTrashService(container.TrashService)
+	router.SetReembedService(container.ReembedService)
+	router.SetGraphVersionService(container.GraphVersionService)
+	router.SetAskService(container.AskService)
+	router.SetLinkPredictionService(container.LinkPredictionService)
 
 	// Setup routes
 	handler := router.Setup()
@@ -102,6 +113,18 @@ func main() {
 		container.Logger.Error("Server shutdown error", zap.Error(err))
 	}
 
+	// Persist search index changes so the next start does not rebuild them
+	if container.VectorIndexService != nil {
+		if err := container.VectorIndexService.Flush(shutdownCtx); err != nil {
+			container.Logger.Error("Failed to persist vector indexes", zap.Error(err))
+		}
+	}
+	if container.KeywordIndexService != nil {
+		if err := container.KeywordIndexService.Flush(shutdownCtx); err != nil {
+			container.Logger.Error("Failed to persist keyword indexes", zap.Error(err))
+		}
+	}
+
 	// Clean up resources
 	if err := container.Logger.Sync(); err != nil {
 		log.Printf("Failed to sync logger: %v", err)
@@ -8,24 +8,24 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"time"
 
 	awsevents "github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
 	"backend/application/ports"
+	appservices "backend/application/services"
 	"backend/domain/core/valueobjects"
 	"backend/domain/events"
 	"backend/domain/services"
 	"backend/infrastructure/config"
 	"backend/infrastructure/di"
-	"backend/infrastructure/embeddings"
 	"go.uber.org/zap"
 )
 
 var (
 	nodeRepo         ports.NodeRepository
 	embeddingService services.EmbeddingService
+	reembedService   *appservices.ReembedService
 	logger           *zap.Logger
 	cfg              *config.Config
 )
@@ -45,17 +45,9 @@ func init() {
 	logger = container.Logger
 	nodeRepo = container.NodeRepo
 
-	embeddingService = embeddings.NewOpenAICompatibleService(
-		&embeddings.OpenAICompatibleConfig{
-			BaseURL:    cfg.Embedding.BaseURL,
-			APIKey:     cfg.Embedding.APIKey,
-			Model:      cfg.Embedding.Model,
-			Dimensions: cfg.Embedding.Dimensions,
-			BatchSize:  64,
-			Timeout:    30 * time.Second,
-		},
-		logger,
-	)
+	// Nil when embedding is disabled; the provider is picked by EMBEDDING_PROVIDER
+	embeddingService = container.EmbeddingService
+	reembedService = container.ReembedService
 
 	log.Println("Embed-node handler initialized successfully")
 }
@@ -68,7 +60,7 @@ type nodeEventDetail struct {
 }
 
 func handler(ctx context.Context, event json.RawMessage) error {
-	if !cfg.Embedding.Enabled {
+	if embeddingService == nil {
 		logger.Debug("Embedding generation is disabled, skipping")
 		return nil
 	}
@@ -110,13 +102,13 @@ func embedNode(ctx context.Context, nodeID string) error {
 		return fmt.Errorf("failed to load node %s: %w", nodeID, err)
 	}
 
-	// Build text for embedding: title + body
-	content := node.Content()
-	text := content.Title()
-	if body := content.Body(); body != "" {
-		text += "\n" + body
+	model := embeddingService.Model()
+	if !node.NeedsEmbedding(model) {
+		logger.Debug("Embedding is current, skipping", zap.String("nodeID", nodeID), zap.String("model", model))
+		return nil
 	}
 
+	text := node.EmbeddingText()
 	if text == "" {
 		logger.Warn("Node has no content to embed", zap.String("nodeID", nodeID))
 		return nil
@@ -135,6 +127,7 @@ func embedNode(ctx context.Context, nodeID string) error {
 
 	logger.Info("Embedded node",
 		zap.String("nodeID", nodeID),
+		zap.String("model", model),
 		zap.Int("dimensions", embedding.Dimensions()),
 	)
 
@@ -146,17 +139,21 @@ func main() {
 		log.Println("Starting embed-node Lambda")
 		lambda.Start(handler)
 	} else {
-		if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		switch {
+		case len(os.Args) > 1 && os.Args[1] == "backfill":
 			runBackfill(context.Background())
-		} else {
-			log.Println("Usage: embed-node backfill")
-			log.Println("  Generates embeddings for all nodes that don't have one yet.")
+		case len(os.Args) > 2 && os.Args[1] == "reembed":
+			runReembed(context.Background(), os.Args[2])
+		default:
+			log.Println("Usage: embed-node backfill | reembed <userID>")
+			log.Println("  backfill  Generates embeddings for all nodes whose embedding is missing or out of date.")
+			log.Println("  reembed   Re-embeds all nodes of a user with the configured model.")
 		}
 	}
 }
 
 func runBackfill(ctx context.Context) {
-	if !cfg.Embedding.Enabled {
+	if embeddingService == nil {
 		log.Fatal("Embedding is disabled. Set EMBEDDING_ENABLED=true to run backfill.")
 	}
 
@@ -178,7 +175,7 @@ func runBackfill(ctx context.Context) {
 	log.Printf("Found %d nodes to process", total)
 
 	for i, node := range nodes {
-		if node.HasEmbedding() {
+		if !node.NeedsEmbedding(embeddingService.Model()) {
 			skipped++
 			continue
 		}
@@ -200,6 +197,22 @@ func runBackfill(ctx context.Context) {
 		}
 	}
 
-	log.Printf("Backfill complete: %d embedded, %d skipped (already had embedding), %d failed out of %d total",
+	log.Printf("Backfill complete: %d embedded, %d skipped (embedding current), %d failed out of %d total",
 		embedded, skipped, failed, total)
 }
+
+func runReembed(ctx context.Context, userID string) {
+	if reembedService == nil {
+		log.Fatal("Embedding is disabled. Set EMBEDDING_ENABLED=true to run reembed.")
+	}
+
+	log.Printf("Re-embedding nodes of user %s with model %s...", userID, embeddingService.Model())
+
+	progress, err := reembedService.Run(ctx, userID, "")
+	if err != nil {
+		log.Fatalf("Re-embedding failed: %v", err)
+	}
+
+	log.Printf("Re-embedding complete: %d embedded, %d skipped (embedding current), %d failed out of %d total",
+		progress.Embedded, progress.Skipped, progress.Failed, progress.Total)
+}
@@ -73,6 +73,10 @@ func init() {
 	)
 	router.SetCommunityService(container.CommunityService)
 	router.SetAnalysisService(container.AnalysisService)
+	router.SetOutbox(container.Outbox)
+	router.SetTrashService(container.TrashService)
+	router.SetGraphVersionService(container.GraphVersionService)
+	router.SetAskService(container.AskService)
 
 	// Setup routes
 	handler := router.Setup()
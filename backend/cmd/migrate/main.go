@@ -0,0 +1,92 @@
+// Package main implements a CLI that runs data migrations against the
+// DynamoDB table, such as backfilling the keys of a newly added index onto
+// items written before it existed. Migrations are idempotent, so an
+// interrupted run can simply be started again.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"backend/infrastructure/config"
+	"backend/infrastructure/di"
+	"backend/infrastructure/persistence/dynamodb"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// migration runs against the table and returns a printable result
+type migration struct {
+	description string
+	run         func(ctx context.Context, client *awsdynamodb.Client, cfg *config.Config, logger *zap.Logger, dryRun bool) (interface{}, error)
+}
+
+var migrations = map[string]migration{
+	"activity-index": {
+		description: "Backfill GSI5 keys (and GSI6 keys of public graphs) on nodes and graphs saved before ActivityIndex existed",
+		run: func(ctx context.Context, client *awsdynamodb.Client, cfg *config.Config, logger *zap.Logger, dryRun bool) (interface{}, error) {
+			return dynamodb.NewActivityIndexBackfill(client, cfg.DynamoDBTable, logger).Run(ctx, dryRun)
+		},
+	},
+}
+
+func main() {
+	name := flag.String("migration", "", "name of the migration to run (lists migrations when empty)")
+	dryRun := flag.Bool("dry-run", false, "count the items the migration would change without writing")
+	flag.Parse()
+
+	if *name == "" {
+		names := make([]string, 0, len(migrations))
+		for migrationName := range migrations {
+			names = append(names, migrationName)
+		}
+		sort.Strings(names)
+		fmt.Println("Available migrations:")
+		for _, migrationName := range names {
+			fmt.Printf("  %-16s %s\n", migrationName, migrations[migrationName].description)
+		}
+		return
+	}
+	selected, ok := migrations[*name]
+	if !ok {
+		log.Fatalf("Unknown migration %s", *name)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if !cfg.UsesDynamoDB() {
+		log.Fatalf("Migrations only apply to the DynamoDB persistence backend")
+	}
+
+	logger, err := di.ProvideLogger(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	awsCfg, err := di.ProvideAWSConfig(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to load AWS configuration: %v", err)
+	}
+
+	result, err := selected.run(ctx, di.ProvideDynamoDBClient(awsCfg), cfg, logger, *dryRun)
+	if err != nil {
+		log.Fatalf("Migration %s failed: %v", *name, err)
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(output))
+}
@@ -0,0 +1,67 @@
+// Package main implements a CLI that rebuilds projections from the event log.
+// Use it to backfill a newly added projection or to rebuild a corrupted one;
+// progress is checkpointed, so an interrupted run continues where it stopped.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"backend/application/projections"
+	"backend/infrastructure/config"
+	"backend/infrastructure/di"
+)
+
+func main() {
+	name := flag.String("projection", "", "name of the projection to replay (lists projections when empty)")
+	from := flag.Int64("from", 0, "event log position to start from; a later checkpoint takes precedence")
+	reset := flag.Bool("reset", false, "clear the projection and its checkpoint before replaying")
+	batch := flag.Int("batch", projections.DefaultReplayBatchSize, "events handled between checkpoints")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	container, err := di.InitializeContainer(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize container: %v", err)
+	}
+	defer container.Logger.Sync()
+
+	if *name == "" {
+		var names []string
+		for projectionName := range container.ProjectionRegistry.GetStats() {
+			names = append(names, projectionName)
+		}
+		sort.Strings(names)
+		fmt.Println("Available projections:")
+		for _, projectionName := range names {
+			fmt.Printf("  %s\n", projectionName)
+		}
+		return
+	}
+
+	result, err := container.ProjectionReplayer.Replay(ctx, *name, projections.ReplayOptions{
+		FromPosition: *from,
+		Reset:        *reset,
+		BatchSize:    *batch,
+	})
+	if err != nil {
+		log.Fatalf("Failed to replay projection %s: %v", *name, err)
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(output))
+}
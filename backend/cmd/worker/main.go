@@ -8,10 +8,11 @@ import (
 	"syscall"
 	"time"
 
+	"backend/application/ports"
+	"backend/application/services"
 	"backend/infrastructure/config"
 	"backend/infrastructure/di"
 	"backend/infrastructure/messaging"
-	"backend/infrastructure/messaging/eventbridge"
 
 	"go.uber.org/zap"
 )
@@ -37,6 +38,10 @@ func main() {
 	err = di.WireEventHandlers(
 		container.EventHandlerRegistry,
 		container.OperationEventListener,
+		container.EmbeddingListener,
+		container.VectorIndexListener,
+		container.KeywordIndexListener,
+		container.CommunityListener,
 		container.GraphStatsProjection,
 		container.Logger,
 	)
@@ -47,8 +52,10 @@ func main() {
 	// Create event dispatcher for processing events
 	dispatcher := messaging.NewEventDispatcher(container.EventHandlerRegistry, container.Logger)
 
-	// Set up local event dispatcher for EventBridge if available
-	if eventBus, ok := container.EventBus.(*eventbridge.EventBridgePublisher); ok {
+	// Set up local event dispatcher for the event bus if available
+	if eventBus, ok := container.EventBus.(interface {
+		SetLocalDispatcher(*messaging.EventDispatcher)
+	}); ok {
 		eventBus.SetLocalDispatcher(dispatcher)
 		container.Logger.Info("Local event dispatcher configured for worker")
 	}
@@ -58,14 +65,17 @@ func main() {
 		zap.String("environment", cfg.Environment),
 	)
 
-	// Start event processing worker
-	go startEventProcessor(ctx, dispatcher, container.Logger)
+	// Start the outbox relay
+	if outboxProcessor := newOutboxProcessor(container, dispatcher); outboxProcessor != nil {
+		outboxProcessor.Start(ctx)
+		defer outboxProcessor.Stop()
+	}
 
 	// Start saga processor worker (if we had saga infrastructure)
 	// go startSagaProcessor(ctx, container, container.Logger)
 
 	// Start periodic cleanup worker
-	go startCleanupWorker(ctx, container.Logger)
+	go startCleanupWorker(ctx, container.TrashService, container.Logger)
 
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
@@ -97,32 +107,38 @@ func main() {
 	log.Println("Worker service stopped")
 }
 
-// startEventProcessor starts a background worker to process domain events
-func startEventProcessor(ctx context.Context, dispatcher *messaging.EventDispatcher, logger *zap.Logger) {
-	logger.Info("Starting event processor worker")
-
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			logger.Info("Event processor shutting down")
-			return
-		case <-ticker.C:
-			// In a real implementation, this would:
-			// 1. Poll for unprocessed events from a queue
-			// 2. Process them using the dispatcher
-			// 3. Mark them as processed
+// newOutboxProcessor creates the relay for stored events, or nil when there is
+// nothing to relay. With the file persistence backend pending events go to
+// the local dispatcher; with DynamoDB they are published to the event bus
+// when the relay is enabled, and otherwise reach the worker through
+// EventBridge.
+func newOutboxProcessor(container *di.Container, dispatcher *messaging.EventDispatcher) *messaging.OutboxProcessor {
+	if container.Outbox == nil {
+		return nil
+	}
 
-			// For now, just log that we're running
-			logger.Debug("Event processor tick")
+	var publisher ports.EventPublisher = dispatcher
+	if container.Config.UsesDynamoDB() {
+		if !container.Config.Outbox.RelayEnabled {
+			return nil
 		}
+		publisher = container.EventBus
 	}
+
+	return messaging.NewOutboxProcessor(
+		container.Outbox,
+		publisher,
+		container.Metrics,
+		container.Logger,
+		di.OutboxProcessorConfig(container.Config),
+	)
 }
 
+// trashPurgeBatchSize bounds how many expired trashed nodes a cleanup cycle purges
+const trashPurgeBatchSize = 100
+
 // startCleanupWorker starts a background worker for periodic cleanup tasks
-func startCleanupWorker(ctx context.Context, logger *zap.Logger) {
+func startCleanupWorker(ctx context.Context, trash *services.TrashService, logger *zap.Logger) {
 	logger.Info("Starting cleanup worker")
 
 	// Run cleanup every hour
@@ -137,7 +153,17 @@ func startCleanupWorker(ctx context.Context, logger *zap.Logger) {
 		case <-ticker.C:
 			logger.Info("Running periodic cleanup tasks")
 
-			// In a real implementation, this would:
+			// Purge trashed nodes whose retention has passed
+			if trash != nil {
+				purged, err := trash.PurgeExpired(ctx, trashPurgeBatchSize)
+				if err != nil {
+					logger.Error("Failed to purge expired trash", zap.Error(err))
+				} else if purged > 0 {
+					logger.Info("Purged expired trashed nodes", zap.Int("count", purged))
+				}
+			}
+
+			// In a real implementation, this would also:
 			// 1. Clean up expired sessions
 			// 2. Archive old events
 			// 3. Perform database maintenance
@@ -146,4 +172,4 @@ func startCleanupWorker(ctx context.Context, logger *zap.Logger) {
 			logger.Debug("Cleanup cycle completed")
 		}
 	}
-}
\ No newline at end of file
+}
@@ -35,6 +35,16 @@ type UpdateGraphRequest struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// TransferNodesRequest represents the request to move or copy nodes to another graph
+type TransferNodesRequest struct {
+	// Graph receiving the nodes (required)
+	// @example "550e8400-e29b-41d4-a716-446655440002"
+	TargetGraphID string `json:"target_graph_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440002"`
+
+	// Nodes to transfer (required, at most 500)
+	NodeIDs []string `json:"node_ids" binding:"required"`
+}
+
 // CreateEdgeRequest represents the request to create an edge
 type CreateEdgeRequest struct {
 	// Source node ID (required)
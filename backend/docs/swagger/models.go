@@ -28,6 +28,9 @@ type CreateNodeRequest struct {
 	// @example ["architecture", "patterns", "cqrs"]
 	Tags []string `json:"tags,omitempty" example:"architecture,patterns,cqrs"`
 
+	// Graph to create the node in (optional, defaults to the user's default graph)
+	GraphID string `json:"graph_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+
 	// 3D position coordinates
 	Position Position3D `json:"position,omitempty"`
 
@@ -124,6 +124,9 @@ func ProductionDomainConfig() *DomainConfig {
 	config.AllowEmptyContent = false
 	config.RequireUniqueNodeTitles = true
 
+	// Deleted nodes stay in the trash bin for 30 days before they are purged
+	config.NodeTTL = 30 * 24 * time.Hour
+
 	return config
 }
 
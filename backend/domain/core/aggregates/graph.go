@@ -2,6 +2,7 @@ package aggregates
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"backend/domain/config"
@@ -12,6 +13,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// defaultGraphName is the name of the graph nodes land in when no graph is
+// chosen; repositories find a user's default graph by it
+const defaultGraphName = "Default Graph"
+
 // GraphID represents a unique graph identifier
 type GraphID string
 
@@ -38,6 +43,7 @@ type Graph struct {
 	createdAt   time.Time
 	updatedAt   time.Time
 	version     int
+	persisted   int // version last read from or written to storage
 	events      []events.DomainEvent
 	config      *config.DomainConfig // Domain configuration for business rules
 }
@@ -115,6 +121,27 @@ func NewGraph(userID, name string) (*Graph, error) {
 
 // NewGraphWithConfig creates a new graph aggregate with specific configuration
 func NewGraphWithConfig(userID, name string, cfg *config.DomainConfig) (*Graph, error) {
+	return newGraph(NewGraphID(), userID, name, "", cfg)
+}
+
+// NewGraphWithID creates a new named graph under an ID chosen by the caller,
+// so a command can report the ID of the graph it creates. Unlike NewGraph a
+// name is required, and the default graph's name is reserved.
+func NewGraphWithID(id GraphID, userID, name, description string, cfg *config.DomainConfig) (*Graph, error) {
+	if id == "" {
+		id = NewGraphID()
+	}
+	if cfg == nil {
+		cfg = config.DefaultDomainConfig()
+	}
+	name = strings.TrimSpace(name)
+	if err := validateGraphName(name, cfg); err != nil {
+		return nil, err
+	}
+	return newGraph(id, userID, name, strings.TrimSpace(description), cfg)
+}
+
+func newGraph(id GraphID, userID, name, description string, cfg *config.DomainConfig) (*Graph, error) {
 	if userID == "" {
 		return nil, pkgerrors.NewValidationError("userID is required")
 	}
@@ -126,13 +153,16 @@ func NewGraphWithConfig(userID, name string, cfg *config.DomainConfig) (*Graph,
 	if name == "" {
 		name = cfg.DefaultGraphName
 	}
+	if description == "" {
+		description = "Knowledge graph for " + name
+	}
 
 	now := time.Now()
 	graph := &Graph{
-		id:          NewGraphID(),
+		id:          id,
 		userID:      userID,
 		name:        name,
-		description: "Knowledge graph for " + name,
+		description: description,
 		nodes:       make(map[valueobjects.NodeID]*entities.Node),
 		edges:       make(map[string]*Edge),
 		config:      cfg,
@@ -155,9 +185,10 @@ func NewGraphWithConfig(userID, name string, cfg *config.DomainConfig) (*Graph,
 			Timestamp:   now,
 			Version:     1,
 		},
-		GraphID: graph.id.String(),
-		UserID:  userID,
-		Name:    name,
+		GraphID:     graph.id.String(),
+		UserID:      userID,
+		Name:        name,
+		Description: description,
 	})
 
 	return graph, nil
@@ -203,6 +234,26 @@ func ReconstructGraph(
 	return graph, nil
 }
 
+// RestorePersistedState sets the stored version once a repository has
+// rehydrated a graph through ReconstructGraph
+func (g *Graph) RestorePersistedState(version int) {
+	if version > 0 {
+		g.version = version
+	}
+	g.persisted = g.version
+}
+
+// PersistedVersion returns the version the graph had when it was last loaded
+// or saved; 0 for graphs that have never been persisted
+func (g *Graph) PersistedVersion() int {
+	return g.persisted
+}
+
+// MarkPersisted records that the graph's current version has been written
+func (g *Graph) MarkPersisted() {
+	g.persisted = g.version
+}
+
 // ID returns the graph's unique identifier
 func (g *Graph) ID() GraphID {
 	return g.id
@@ -289,7 +340,45 @@ func (g *Graph) UpdatedAt() time.Time {
 // IsDefault returns whether this is the user's default graph
 func (g *Graph) IsDefault() bool {
 	// For now, the first graph is the default
-	return g.name == "Default Graph"
+	return g.name == defaultGraphName
+}
+
+// Rename changes the graph's name and description. The default graph keeps
+// its name, since that is what makes it the default, and no other graph may
+// take it.
+func (g *Graph) Rename(name, description string) error {
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	if name == g.name && description == g.description {
+		return nil
+	}
+	if g.IsDefault() && name != g.name {
+		return pkgerrors.NewValidationError("the default graph cannot be renamed")
+	}
+	if name != g.name {
+		if err := validateGraphName(name, g.config); err != nil {
+			return err
+		}
+	}
+
+	g.name = name
+	g.description = description
+	g.updatedAt = time.Now()
+	g.version++
+
+	g.addEvent(events.GraphRenamed{
+		BaseEvent: events.BaseEvent{
+			AggregateID: g.id.String(),
+			EventType:   "graph.renamed",
+			Timestamp:   g.updatedAt,
+			Version:     g.version,
+		},
+		GraphID:     g.id.String(),
+		Name:        name,
+		Description: description,
+	})
+
+	return nil
 }
 
 // AddNode adds a node to the graph
@@ -318,7 +407,7 @@ func (g *Graph) AddNode(node *entities.Node) error {
 			AggregateID: g.id.String(),
 			EventType:   "graph.node_added",
 			Timestamp:   g.updatedAt,
-			Version:     1,
+			Version:     g.version,
 		},
 		GraphID: g.id.String(),
 		NodeID:  nodeID,
@@ -407,11 +496,13 @@ func (g *Graph) ConnectNodes(sourceID, targetID valueobjects.NodeID, edgeType en
 			AggregateID: g.id.String(),
 			EventType:   "graph.nodes_connected",
 			Timestamp:   g.updatedAt,
-			Version:     1,
+			Version:     g.version,
 		},
 		SourceID: sourceID,
 		TargetID: targetID,
 		EdgeType: string(edgeType),
+		EdgeID:   edge.ID,
+		Weight:   edge.Weight,
 	})
 
 	return edge, nil
@@ -453,7 +544,7 @@ func (g *Graph) DisconnectNodes(sourceID, targetID valueobjects.NodeID) (*Edge,
 			AggregateID: g.id.String(),
 			EventType:   "graph.nodes_disconnected",
 			Timestamp:   g.updatedAt,
-			Version:     1,
+			Version:     g.version,
 		},
 		SourceID: sourceID,
 		TargetID: targetID,
@@ -492,13 +583,8 @@ func (g *Graph) LoadEdge(edge *Edge) error {
 		g.edges[edgeKey] = edge
 		g.metadata.EdgeCount++
 
-		// Update the source node's connections with error handling
-		if err := sourceNode.ConnectTo(edge.TargetID, edge.Type); err != nil {
-			// Rollback the edge addition if node connection fails
-			delete(g.edges, edgeKey)
-			g.metadata.EdgeCount--
-			return fmt.Errorf("failed to update node connections: %w", err)
-		}
+		// Restore the source node's reference without recording a new connection
+		sourceNode.RestoreConnection(edge.ID, edge.TargetID, edge.Type)
 	}
 
 	return nil
@@ -540,7 +626,7 @@ func (g *Graph) RemoveNode(nodeID valueobjects.NodeID) error {
 			AggregateID: g.id.String(),
 			EventType:   "graph.node_removed",
 			Timestamp:   g.updatedAt,
-			Version:     1,
+			Version:     g.version,
 		},
 		GraphID: g.id.String(),
 		NodeID:  nodeID,
@@ -739,6 +825,20 @@ func (g *Graph) MarkEventsAsCommitted() {
 
 // Private helper methods
 
+// validateGraphName checks a name given to a graph by its owner
+func validateGraphName(name string, cfg *config.DomainConfig) error {
+	if name == "" {
+		return pkgerrors.NewValidationError("graph name is required")
+	}
+	if name == defaultGraphName {
+		return pkgerrors.NewValidationError(fmt.Sprintf("the name %q is reserved for the default graph", defaultGraphName))
+	}
+	if cfg != nil && cfg.MaxTitleLength > 0 && len(name) > cfg.MaxTitleLength {
+		return pkgerrors.NewValidationError(fmt.Sprintf("graph name exceeds %d characters", cfg.MaxTitleLength))
+	}
+	return nil
+}
+
 func (g *Graph) addEvent(event events.DomainEvent) {
 	g.events = append(g.events, event)
 }
@@ -819,10 +919,9 @@ func (g *Graph) DiscoverAndConnectEdges(
 			continue
 		}
 		// Set the weight based on similarity
-		edge.Weight = candidate.Similarity
+		g.setEdgeWeight(edge, candidate.Similarity)
 	}
 
-	g.incrementVersion()
 	return syncEdges, asyncCandidates, nil
 }
 
@@ -930,8 +1029,15 @@ func (g *Graph) IsWellConnected() bool {
 	return true
 }
 
-// incrementVersion increases the version number for optimistic locking
-func (g *Graph) incrementVersion() {
-	g.version++
-	g.updatedAt = time.Now()
+// setEdgeWeight changes the weight of an edge created in this session and
+// keeps its pending connection event in step
+func (g *Graph) setEdgeWeight(edge *Edge, weight float64) {
+	edge.Weight = weight
+	for i := len(g.events) - 1; i >= 0; i-- {
+		if connected, ok := g.events[i].(events.NodesConnected); ok && connected.EdgeID == edge.ID {
+			connected.Weight = weight
+			g.events[i] = connected
+			return
+		}
+	}
 }
@@ -0,0 +1,268 @@
+package aggregates
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/domain/config"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// NodeSource looks up the node a graph event refers to while the graph is
+// replayed. Nodes are aggregates of their own, so the graph stream only
+// records membership; the node itself comes from its own stream or store.
+type NodeSource func(id valueobjects.NodeID) (*entities.Node, bool)
+
+// GraphSnapshot is the structure of a graph at a given version: its identity,
+// member node IDs and edges
+type GraphSnapshot struct {
+	ID          string         `json:"id"`
+	UserID      string         `json:"user_id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	IsPublic    bool           `json:"is_public"`
+	NodeIDs     []string       `json:"node_ids"`
+	Edges       []EdgeSnapshot `json:"edges"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Version     int            `json:"version"`
+}
+
+// EdgeSnapshot is the stored form of an Edge
+type EdgeSnapshot struct {
+	ID            string            `json:"id"`
+	SourceID      string            `json:"source_id"`
+	TargetID      string            `json:"target_id"`
+	Type          entities.EdgeType `json:"type"`
+	Weight        float64           `json:"weight"`
+	Bidirectional bool              `json:"bidirectional"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// Snapshot captures the structure of the graph
+func (g *Graph) Snapshot() GraphSnapshot {
+	nodeIDs := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		nodeIDs = append(nodeIDs, id.String())
+	}
+	sort.Strings(nodeIDs)
+
+	edges := make([]EdgeSnapshot, 0, len(g.edges))
+	for _, edge := range g.edges {
+		edges = append(edges, EdgeSnapshot{
+			ID:            edge.ID,
+			SourceID:      edge.SourceID.String(),
+			TargetID:      edge.TargetID.String(),
+			Type:          edge.Type,
+			Weight:        edge.Weight,
+			Bidirectional: edge.Bidirectional,
+			CreatedAt:     edge.CreatedAt,
+		})
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+
+	return GraphSnapshot{
+		ID:          g.id.String(),
+		UserID:      g.userID,
+		Name:        g.name,
+		Description: g.description,
+		IsPublic:    g.metadata.IsPublic,
+		NodeIDs:     nodeIDs,
+		Edges:       edges,
+		CreatedAt:   g.createdAt,
+		UpdatedAt:   g.updatedAt,
+		Version:     g.version,
+	}
+}
+
+// RehydrateGraph rebuilds a graph by replaying its history on top of an
+// optional snapshot, resolving member nodes through nodes. Without a snapshot
+// the history must start with graph.created. Nodes that can no longer be
+// resolved, for example because they were deleted outright, are left out
+// together with their edges.
+func RehydrateGraph(snapshot *GraphSnapshot, history []events.DomainEvent, nodes NodeSource) (*Graph, error) {
+	ordered := make([]events.DomainEvent, len(history))
+	copy(ordered, history)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GetVersion() < ordered[j].GetVersion()
+	})
+
+	var graph *Graph
+	if snapshot != nil {
+		graph = graphFromSnapshot(*snapshot, nodes)
+	} else {
+		if len(ordered) == 0 {
+			return nil, pkgerrors.NewNotFoundError("graph history")
+		}
+		created, ok := ordered[0].(events.GraphCreated)
+		if !ok {
+			return nil, fmt.Errorf("graph history must start with graph.created, got %s", ordered[0].GetEventType())
+		}
+		description := created.Description
+		if description == "" {
+			// Recorded before graph.created carried the description
+			description = "Knowledge graph for " + created.Name
+		}
+		graph = newReplayGraph(created.GraphID, created.UserID, created.Name, description, created.Timestamp)
+		graph.version = created.Version
+		ordered = ordered[1:]
+	}
+
+	for _, event := range ordered {
+		if snapshot != nil && event.GetVersion() <= snapshot.Version {
+			continue
+		}
+		if err := graph.Apply(event, nodes); err != nil {
+			return nil, err
+		}
+	}
+
+	// A replayed history describes what has been stored
+	graph.persisted = graph.version
+	return graph, nil
+}
+
+// newReplayGraph creates the empty shell a replay starts from
+func newReplayGraph(id, userID, name, description string, createdAt time.Time) *Graph {
+	return &Graph{
+		id:          GraphID(id),
+		userID:      userID,
+		name:        name,
+		description: description,
+		nodes:       make(map[valueobjects.NodeID]*entities.Node),
+		edges:       make(map[string]*Edge),
+		config:      config.DefaultDomainConfig(),
+		metadata: GraphMetadata{
+			ViewSettings: ViewSettings{
+				Layout:     LayoutForceDirected,
+				ShowLabels: true,
+			},
+		},
+		createdAt: createdAt,
+		updatedAt: createdAt,
+		version:   1,
+		events:    []events.DomainEvent{},
+	}
+}
+
+// graphFromSnapshot rebuilds a graph from a snapshot
+func graphFromSnapshot(snapshot GraphSnapshot, nodes NodeSource) *Graph {
+	graph := newReplayGraph(snapshot.ID, snapshot.UserID, snapshot.Name, snapshot.Description, snapshot.CreatedAt)
+	graph.metadata.IsPublic = snapshot.IsPublic
+	graph.updatedAt = snapshot.UpdatedAt
+	graph.version = snapshot.Version
+
+	for _, id := range snapshot.NodeIDs {
+		nodeID, err := valueobjects.NewNodeIDFromString(id)
+		if err != nil {
+			continue
+		}
+		graph.addReplayedNode(nodeID, nodes)
+	}
+	for _, edge := range snapshot.Edges {
+		sourceID, err := valueobjects.NewNodeIDFromString(edge.SourceID)
+		if err != nil {
+			continue
+		}
+		targetID, err := valueobjects.NewNodeIDFromString(edge.TargetID)
+		if err != nil {
+			continue
+		}
+		graph.addReplayedEdge(&Edge{
+			ID:            edge.ID,
+			SourceID:      sourceID,
+			TargetID:      targetID,
+			Type:          edge.Type,
+			Weight:        edge.Weight,
+			Bidirectional: edge.Bidirectional,
+			CreatedAt:     edge.CreatedAt,
+		})
+	}
+
+	return graph
+}
+
+// Apply mutates the graph according to a recorded event without validating
+// or raising new events. Events that do not change the graph structure are
+// ignored.
+func (g *Graph) Apply(event events.DomainEvent, nodes NodeSource) error {
+	if event.GetAggregateID() != g.id.String() {
+		return fmt.Errorf("event %s belongs to aggregate %s, not graph %s",
+			event.GetEventType(), event.GetAggregateID(), g.id)
+	}
+
+	switch e := event.(type) {
+	case events.GraphCreated:
+		return pkgerrors.NewConflictError("graph already created")
+	case events.GraphRenamed:
+		g.name = e.Name
+		g.description = e.Description
+	case events.NodeAddedToGraph:
+		g.addReplayedNode(e.NodeID, nodes)
+	case events.NodeRemovedFromGraph:
+		for key, edge := range g.edges {
+			if edge.SourceID.Equals(e.NodeID) || edge.TargetID.Equals(e.NodeID) {
+				delete(g.edges, key)
+			}
+		}
+		delete(g.nodes, e.NodeID)
+	case events.NodesConnected:
+		edgeID := e.EdgeID
+		if edgeID == "" {
+			edgeID = uuid.New().String()
+		}
+		weight := e.Weight
+		if weight == 0 {
+			weight = 1.0
+		}
+		g.addReplayedEdge(&Edge{
+			ID:        edgeID,
+			SourceID:  e.SourceID,
+			TargetID:  e.TargetID,
+			Type:      entities.EdgeType(e.EdgeType),
+			Weight:    weight,
+			CreatedAt: e.Timestamp,
+		})
+	case events.NodesDisconnected:
+		delete(g.edges, g.makeEdgeKey(e.SourceID, e.TargetID))
+	default:
+		return nil
+	}
+
+	g.metadata.NodeCount = len(g.nodes)
+	g.metadata.EdgeCount = len(g.edges)
+	g.updatedAt = event.GetTimestamp()
+	if event.GetVersion() > g.version {
+		g.version = event.GetVersion()
+	}
+	return nil
+}
+
+// addReplayedNode adds a member node if it can still be resolved
+func (g *Graph) addReplayedNode(id valueobjects.NodeID, nodes NodeSource) {
+	if nodes == nil {
+		return
+	}
+	if node, ok := nodes(id); ok && node != nil {
+		g.nodes[id] = node
+		g.metadata.NodeCount = len(g.nodes)
+	}
+}
+
+// addReplayedEdge adds an edge whose endpoints are both members
+func (g *Graph) addReplayedEdge(edge *Edge) {
+	if _, ok := g.nodes[edge.SourceID]; !ok {
+		return
+	}
+	if _, ok := g.nodes[edge.TargetID]; !ok {
+		return
+	}
+	g.edges[g.makeEdgeKey(edge.SourceID, edge.TargetID)] = edge
+	g.metadata.EdgeCount = len(g.edges)
+}
@@ -0,0 +1,192 @@
+package aggregates
+
+import (
+	"encoding/json"
+	"testing"
+
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRehydrateNode_ReplaysHistory(t *testing.T) {
+	node := createTestNode(t, "Original")
+	require.NoError(t, node.AddTag("go"))
+
+	content, err := valueobjects.NewNodeContent("Updated", "New body", valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	require.NoError(t, node.UpdateContent(content))
+
+	position, err := valueobjects.NewPosition3D(5, 6, 7)
+	require.NoError(t, err)
+	require.NoError(t, node.MoveTo(position))
+	require.NoError(t, node.AddTag("events"))
+	require.NoError(t, node.Publish())
+
+	rebuilt, err := entities.RehydrateNode(nil, node.GetUncommittedEvents())
+	require.NoError(t, err)
+
+	assert.Equal(t, node.ID(), rebuilt.ID())
+	assert.Equal(t, node.Version(), rebuilt.Version())
+	assert.Equal(t, "Updated", rebuilt.Content().Title())
+	assert.Equal(t, position, rebuilt.Position())
+	assert.Equal(t, []string{"go", "events"}, rebuilt.GetTags())
+	assert.True(t, rebuilt.IsPublished())
+	assert.Empty(t, rebuilt.GetUncommittedEvents())
+}
+
+func TestRehydrateNode_FromSnapshot(t *testing.T) {
+	node := createTestNode(t, "Snapshot")
+	target := createTestNode(t, "Target")
+	require.NoError(t, node.ConnectTo(target.ID(), entities.EdgeTypeNormal))
+	history := node.GetUncommittedEvents()
+
+	// Round-trip the snapshot through JSON as the snapshot stores do
+	data, err := json.Marshal(node.Snapshot())
+	require.NoError(t, err)
+	var snapshot entities.NodeSnapshot
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+
+	require.NoError(t, node.Archive())
+	later := node.GetUncommittedEvents()[len(history):]
+
+	// Events already covered by the snapshot are skipped
+	rebuilt, err := entities.RehydrateNode(&snapshot, append(history, later...))
+	require.NoError(t, err)
+
+	assert.Equal(t, node.Version(), rebuilt.Version())
+	assert.True(t, rebuilt.IsArchived())
+	assert.Empty(t, rebuilt.GetConnections())
+
+	atSnapshot, err := entities.RehydrateNode(&snapshot, nil)
+	require.NoError(t, err)
+	assert.True(t, atSnapshot.HasConnectionTo(target.ID()))
+}
+
+func TestRehydrateNode_RequiresCreation(t *testing.T) {
+	node := createTestNode(t, "Partial")
+	require.NoError(t, node.Publish())
+
+	_, err := entities.RehydrateNode(nil, node.GetUncommittedEvents()[1:])
+	assert.Error(t, err)
+}
+
+func TestRehydrateGraph_ReplaysStructure(t *testing.T) {
+	graph := createTestGraph(t)
+	a := createTestNode(t, "Alpha")
+	b := createTestNode(t, "Beta")
+	c := createTestNode(t, "Gamma")
+	require.NoError(t, graph.AddNode(a))
+	require.NoError(t, graph.AddNode(b))
+	require.NoError(t, graph.AddNode(c))
+
+	_, err := graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+	_, err = graph.ConnectNodes(b.ID(), c.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+	_, err = graph.DisconnectNodes(a.ID(), b.ID())
+	require.NoError(t, err)
+
+	history := graphEvents(graph)
+	rebuilt, err := RehydrateGraph(nil, history, nodeSourceOf(a, b, c))
+	require.NoError(t, err)
+
+	assert.Equal(t, graph.ID(), rebuilt.ID())
+	assert.Equal(t, graph.Name(), rebuilt.Name())
+	assert.Equal(t, graph.Version(), rebuilt.Version())
+	assert.Equal(t, 3, rebuilt.NodeCount())
+	assert.Equal(t, 1, rebuilt.EdgeCount())
+	require.Len(t, rebuilt.Snapshot().Edges, 1)
+	edge := rebuilt.Snapshot().Edges[0]
+	assert.Equal(t, graph.Snapshot().Edges[0].ID, edge.ID)
+	assert.Equal(t, b.ID().String(), edge.SourceID)
+	assert.Equal(t, c.ID().String(), edge.TargetID)
+	assert.Empty(t, graphEvents(rebuilt))
+}
+
+func TestRehydrateGraph_AtEarlierVersion(t *testing.T) {
+	graph := createTestGraph(t)
+	a := createTestNode(t, "Alpha")
+	b := createTestNode(t, "Beta")
+	require.NoError(t, graph.AddNode(a))
+	require.NoError(t, graph.AddNode(b))
+	_, err := graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+	connectedAt := graph.Version()
+	require.NoError(t, graph.RemoveNode(b.ID()))
+
+	var history []events.DomainEvent
+	for _, event := range graphEvents(graph) {
+		if event.GetVersion() <= connectedAt {
+			history = append(history, event)
+		}
+	}
+
+	rebuilt, err := RehydrateGraph(nil, history, nodeSourceOf(a, b))
+	require.NoError(t, err)
+	assert.Equal(t, connectedAt, rebuilt.Version())
+	assert.Equal(t, 2, rebuilt.NodeCount())
+	assert.Equal(t, 1, rebuilt.EdgeCount())
+}
+
+func TestRehydrateGraph_FromSnapshot(t *testing.T) {
+	graph := createTestGraph(t)
+	a := createTestNode(t, "Alpha")
+	b := createTestNode(t, "Beta")
+	require.NoError(t, graph.AddNode(a))
+	require.NoError(t, graph.AddNode(b))
+	_, err := graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+
+	snapshot := graph.Snapshot()
+	before := len(graphEvents(graph))
+	require.NoError(t, graph.RemoveNode(b.ID()))
+
+	rebuilt, err := RehydrateGraph(&snapshot, graphEvents(graph)[before:], nodeSourceOf(a, b))
+	require.NoError(t, err)
+	assert.Equal(t, graph.Version(), rebuilt.Version())
+	assert.Equal(t, 1, rebuilt.NodeCount())
+	assert.Equal(t, 0, rebuilt.EdgeCount())
+}
+
+// graphEvents returns the graph's own uncommitted events, without those of
+// its member nodes
+func graphEvents(graph *Graph) []events.DomainEvent {
+	var result []events.DomainEvent
+	for _, event := range graph.GetUncommittedEvents() {
+		if event.GetAggregateID() == graph.ID().String() {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func nodeSourceOf(nodes ...*entities.Node) NodeSource {
+	byID := make(map[valueobjects.NodeID]*entities.Node, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID()] = node
+	}
+	return func(id valueobjects.NodeID) (*entities.Node, bool) {
+		node, ok := byID[id]
+		return node, ok
+	}
+}
+
+func TestPersistedVersion_TracksStoredState(t *testing.T) {
+	node := createTestNode(t, "Tracked")
+	assert.Zero(t, node.PersistedVersion(), "new nodes have never been stored")
+
+	node.MarkPersisted()
+	require.NoError(t, node.Publish())
+	assert.Equal(t, node.Version()-1, node.PersistedVersion())
+
+	rebuilt, err := entities.RehydrateNode(nil, node.GetUncommittedEvents())
+	require.NoError(t, err)
+	assert.Equal(t, rebuilt.Version(), rebuilt.PersistedVersion())
+
+	graph := createTestGraph(t)
+	graph.RestorePersistedState(7)
+	assert.Equal(t, 7, graph.PersistedVersion())
+}
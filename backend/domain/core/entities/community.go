@@ -11,7 +11,32 @@ type Community struct {
 	Keywords       []string
 	CohesionScore  float64
 	MemberCount    int
+	MemberIDs      []string
 	CentralNodeID  string // Most connected node within the community
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
+
+// CommunityPartition is the community assignment of every node of a graph,
+// kept current by incremental updates between full recomputes.
+type CommunityPartition struct {
+	GraphID     string
+	Communities []*Community
+	Modularity  float64
+	// BaselineModularity is the best modularity since the last full
+	// recompute; updates that drift too far below it trigger another one
+	BaselineModularity float64
+	RecomputedAt       time.Time
+	UpdatedAt          time.Time
+}
+
+// Assignments maps each member node ID to its community ID
+func (p *CommunityPartition) Assignments() map[string]string {
+	assignments := make(map[string]string)
+	for _, c := range p.Communities {
+		for _, id := range c.MemberIDs {
+			assignments[id] = c.ID
+		}
+	}
+	return assignments
+}
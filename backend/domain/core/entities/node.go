@@ -36,6 +36,9 @@ type Node struct {
 	version   int
 	status    NodeStatus
 
+	// Version last read from or written to storage; 0 until persisted
+	persistedVersion int
+
 	// Vector embedding for semantic similarity (nullable — computed async)
 	embedding *valueobjects.Embedding
 
@@ -99,7 +102,7 @@ func NewNode(userID string, content valueobjects.NodeContent, position valueobje
 
 	// Note: graphID will be set later when node is added to a graph
 	// Tags will be populated when AddTag is called
-	node.addEvent(events.NewNodeCreated(
+	created := events.NewNodeCreated(
 		node.id,
 		userID,
 		"", // graphID will be set when SetGraphID is called
@@ -108,7 +111,10 @@ func NewNode(userID string, content valueobjects.NodeContent, position valueobje
 		keywords,
 		[]string{}, // tags will be populated when AddTag is called
 		now,
-	))
+	)
+	created.Format = string(content.Format())
+	created.Position = position
+	node.addEvent(created)
 
 	return node, nil
 }
@@ -149,6 +155,32 @@ func ReconstructNode(
 	return node, nil
 }
 
+// RestorePersistedState sets the stored version and modification time once a
+// repository has finished rehydrating a node through ReconstructNode and the
+// regular mutators (which would otherwise bump updatedAt to now). Events the
+// mutators raised along the way describe stored state, not new changes, so
+// they are discarded.
+func (n *Node) RestorePersistedState(version int, updatedAt time.Time) {
+	if version > 0 {
+		n.version = version
+	}
+	n.persistedVersion = n.version
+	n.updatedAt = updatedAt
+	n.events = []events.DomainEvent{}
+}
+
+// PersistedVersion returns the version the node had when it was last loaded
+// or saved, which is the version a conditional write expects to replace. It
+// is 0 for nodes that have never been persisted.
+func (n *Node) PersistedVersion() int {
+	return n.persistedVersion
+}
+
+// MarkPersisted records that the node's current version has been written
+func (n *Node) MarkPersisted() {
+	n.persistedVersion = n.version
+}
+
 // ID returns the node's unique identifier
 func (n *Node) ID() valueobjects.NodeID {
 	return n.id
@@ -221,6 +253,29 @@ func (n *Node) SetEmbedding(embedding valueobjects.Embedding) {
 	n.updatedAt = time.Now()
 }
 
+// EmbeddingText returns the text an embedding of the node is generated from:
+// the title, followed by the body on a new line when there is one.
+func (n *Node) EmbeddingText() string {
+	text := n.content.Title()
+	if body := n.content.Body(); body != "" {
+		text += "\n" + body
+	}
+	return text
+}
+
+// NeedsEmbedding reports whether the node lacks an embedding of its current
+// content by model. An embedding of unknown provenance always needs
+// replacing; an empty model only compares the content.
+func (n *Node) NeedsEmbedding(model string) bool {
+	if !n.HasEmbedding() {
+		return true
+	}
+	if model != "" && n.embedding.Model() != model {
+		return true
+	}
+	return n.embedding.ContentHash() != valueobjects.EmbeddingContentHash(n.EmbeddingText())
+}
+
 // CommunityID returns the node's community assignment (empty if unassigned).
 func (n *Node) CommunityID() string {
 	return n.communityID
@@ -251,7 +306,9 @@ func (n *Node) UpdateContent(content valueobjects.NodeContent) error {
 	n.updatedAt = time.Now()
 	n.version++
 
-	n.addEvent(events.NewNodeContentUpdated(n.id, oldContent, content, n.updatedAt))
+	event := events.NewNodeContentUpdated(n.id, oldContent, content, n.updatedAt)
+	event.Version = n.version
+	n.addEvent(event)
 
 	return nil
 }
@@ -269,8 +326,11 @@ func (n *Node) MoveTo(position valueobjects.Position) error {
 	oldPosition := n.position
 	n.position = position
 	n.updatedAt = time.Now()
+	n.version++
 
-	n.addEvent(events.NewNodeMoved(n.id, oldPosition, position, n.updatedAt))
+	event := events.NewNodeMoved(n.id, oldPosition, position, n.updatedAt)
+	event.Version = n.version
+	n.addEvent(event)
 
 	return nil
 }
@@ -313,12 +373,34 @@ func (n *Node) ConnectToWithConfig(targetID valueobjects.NodeID, edgeType EdgeTy
 
 	n.edges = append(n.edges, edgeRef)
 	n.updatedAt = time.Now()
+	n.version++
 
-	n.addEvent(events.NewNodesConnected(n.id, targetID, string(edgeType), n.updatedAt))
+	event := events.NewNodesConnected(n.id, targetID, string(edgeType), n.updatedAt)
+	event.EdgeID = edgeRef.EdgeID
+	event.Version = n.version
+	n.addEvent(event)
 
 	return nil
 }
 
+// RestoreConnection re-attaches an already persisted connection while a
+// repository rebuilds the node; it raises no event and keeps the version
+func (n *Node) RestoreConnection(edgeID string, targetID valueobjects.NodeID, edgeType EdgeType) {
+	for _, edge := range n.edges {
+		if edge.TargetID.Equals(targetID) && edge.Type == edgeType {
+			return
+		}
+	}
+	if edgeID == "" {
+		edgeID = generateEdgeID()
+	}
+	n.edges = append(n.edges, EdgeReference{
+		EdgeID:   edgeID,
+		TargetID: targetID,
+		Type:     edgeType,
+	})
+}
+
 // Disconnect removes a connection to another node
 func (n *Node) Disconnect(targetID valueobjects.NodeID) error {
 	found := false
@@ -338,8 +420,11 @@ func (n *Node) Disconnect(targetID valueobjects.NodeID) error {
 
 	n.edges = newEdges
 	n.updatedAt = time.Now()
+	n.version++
 
-	n.addEvent(events.NewNodesDisconnected(n.id, targetID, n.updatedAt))
+	event := events.NewNodesDisconnected(n.id, targetID, n.updatedAt)
+	event.Version = n.version
+	n.addEvent(event)
 
 	return nil
 }
@@ -358,7 +443,9 @@ func (n *Node) Publish() error {
 	n.updatedAt = time.Now()
 	n.version++
 
-	n.addEvent(events.NewNodePublished(n.id, n.updatedAt))
+	event := events.NewNodePublished(n.id, n.updatedAt)
+	event.Version = n.version
+	n.addEvent(event)
 
 	return nil
 }
@@ -376,7 +463,9 @@ func (n *Node) Archive() error {
 	// Remove all connections when archiving
 	n.edges = []EdgeReference{}
 
-	n.addEvent(events.NewNodeArchived(n.id, n.updatedAt))
+	event := events.NewNodeArchived(n.id, n.updatedAt)
+	event.Version = n.version
+	n.addEvent(event)
 
 	return nil
 }
@@ -410,15 +499,7 @@ func (n *Node) AddTagWithConfig(tag string, cfg *config.DomainConfig) error {
 
 	n.metadata.Tags = append(n.metadata.Tags, tag)
 	n.updatedAt = time.Now()
-
-	// Update the NodeCreated event with the new tags
-	for i, event := range n.events {
-		if nodeCreated, ok := event.(events.NodeCreated); ok {
-			nodeCreated.Tags = n.GetTags()
-			n.events[i] = nodeCreated
-			break
-		}
-	}
+	n.recordTagsChanged()
 
 	return nil
 }
@@ -442,10 +523,38 @@ func (n *Node) RemoveTag(tag string) error {
 
 	n.metadata.Tags = newTags
 	n.updatedAt = time.Now()
+	n.recordTagsChanged()
 
 	return nil
 }
 
+// recordTagsChanged records the current tags in a pending NodeCreated or
+// NodeTagsUpdated event when there is one, so that replacing a node's tags one
+// call at a time still produces a single change
+func (n *Node) recordTagsChanged() {
+	for i, event := range n.events {
+		if nodeCreated, ok := event.(events.NodeCreated); ok {
+			nodeCreated.Tags = n.GetTags()
+			n.events[i] = nodeCreated
+			return
+		}
+	}
+
+	if last := len(n.events) - 1; last >= 0 {
+		if tagsUpdated, ok := n.events[last].(events.NodeTagsUpdated); ok {
+			tagsUpdated.Tags = n.GetTags()
+			tagsUpdated.Timestamp = n.updatedAt
+			n.events[last] = tagsUpdated
+			return
+		}
+	}
+
+	n.version++
+	event := events.NewNodeTagsUpdated(n.id, n.GetTags(), n.updatedAt)
+	event.Version = n.version
+	n.addEvent(event)
+}
+
 // GetConnections returns all edge references
 func (n *Node) GetConnections() []EdgeReference {
 	// Return a copy to maintain encapsulation
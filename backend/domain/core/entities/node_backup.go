@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"backend/domain/core/valueobjects"
+)
+
+// NodeBackup is the complete state of a node. It is kept while the node sits
+// in the trash bin, so unlike NodeSnapshot it includes the attributes that are
+// not event-sourced: restoring the node brings back everything it had.
+type NodeBackup struct {
+	Snapshot       NodeSnapshot `json:"snapshot"`
+	Metadata       Metadata     `json:"metadata"`
+	Embedding      []float64    `json:"embedding,omitempty"`
+	EmbeddingModel string       `json:"embedding_model,omitempty"` // Model that produced the embedding
+	EmbeddingHash  string       `json:"embedding_hash,omitempty"`  // Hash of the embedded text
+	CommunityID    string       `json:"community_id,omitempty"`
+}
+
+// Backup captures the complete state of the node
+func (n *Node) Backup() NodeBackup {
+	backup := NodeBackup{
+		Snapshot:    n.Snapshot(),
+		Metadata:    n.metadata,
+		CommunityID: n.communityID,
+	}
+	backup.Metadata.Tags = append([]string{}, n.metadata.Tags...)
+	backup.Metadata.Categories = append([]string{}, n.metadata.Categories...)
+	backup.Metadata.Properties = copyProperties(n.metadata.Properties)
+	if n.HasEmbedding() {
+		backup.Embedding = n.embedding.Vector()
+		backup.EmbeddingModel = n.embedding.Model()
+		backup.EmbeddingHash = n.embedding.ContentHash()
+	}
+	return backup
+}
+
+// NodeFromBackup rebuilds a node from a backup. The node is treated as never
+// persisted, so saving it creates it again.
+func NodeFromBackup(backup NodeBackup) (*Node, error) {
+	node, err := NodeFromSnapshot(backup.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := node.metadata.Tags
+	node.metadata = backup.Metadata
+	node.metadata.Tags = tags
+	node.metadata.Categories = append([]string{}, backup.Metadata.Categories...)
+	node.metadata.Properties = copyProperties(backup.Metadata.Properties)
+	if node.metadata.Properties == nil {
+		node.metadata.Properties = make(map[string]interface{})
+	}
+
+	if len(backup.Embedding) > 0 {
+		embedding, err := valueobjects.NewEmbedding(backup.Embedding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup embedding: %w", err)
+		}
+		embedding = embedding.WithProvenance(backup.EmbeddingModel, backup.EmbeddingHash)
+		node.embedding = &embedding
+	}
+	node.communityID = backup.CommunityID
+
+	return node, nil
+}
+
+// NodeFromBackupOver rebuilds a node from a backup as the next version of
+// current, the stored node it replaces, so saving it passes the repository's
+// version check like any other update of current.
+func NodeFromBackupOver(backup NodeBackup, current *Node) (*Node, error) {
+	if current == nil || backup.Snapshot.ID != current.id.String() {
+		return nil, fmt.Errorf("backup does not belong to the node it replaces")
+	}
+	node, err := NodeFromBackup(backup)
+	if err != nil {
+		return nil, err
+	}
+	node.version = current.version + 1
+	node.persistedVersion = current.persistedVersion
+	node.updatedAt = time.Now()
+	return node, nil
+}
+
+// CopyToGraph returns a copy of the node under a new ID in another graph.
+// The copy keeps the content, tags, metadata and embedding. Connections and
+// the community assignment belong to the original's graph and are left out.
+// The copy is treated as never persisted, so saving it creates it.
+func (n *Node) CopyToGraph(graphID string) (*Node, error) {
+	backup := n.Backup()
+	now := time.Now()
+	backup.Snapshot.ID = valueobjects.NewNodeID().String()
+	backup.Snapshot.GraphID = graphID
+	backup.Snapshot.Connections = nil
+	backup.Snapshot.CreatedAt = now
+	backup.Snapshot.UpdatedAt = now
+	backup.Snapshot.Version = 1
+	backup.CommunityID = ""
+	return NodeFromBackup(backup)
+}
+
+// MoveToGraph re-homes the node in another graph under the same ID. The
+// community assignment belongs to the old graph and is cleared. Graph
+// membership is not event-sourced, so the version is left alone and the move
+// raises no event.
+func (n *Node) MoveToGraph(graphID string) {
+	n.graphID = graphID
+	n.communityID = ""
+	n.updatedAt = time.Now()
+}
+
+func copyProperties(properties map[string]interface{}) map[string]interface{} {
+	if properties == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		copied[key] = value
+	}
+	return copied
+}
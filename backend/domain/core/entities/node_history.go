@@ -0,0 +1,273 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+)
+
+// NodeSnapshot is the state of a node at a given version. Event-sourced
+// repositories persist it periodically so that loading a node only replays
+// the events recorded after it.
+type NodeSnapshot struct {
+	ID          string                   `json:"id"`
+	UserID      string                   `json:"user_id"`
+	GraphID     string                   `json:"graph_id"`
+	Content     valueobjects.NodeContent `json:"content"`
+	Position    valueobjects.Position    `json:"position"`
+	Status      NodeStatus               `json:"status"`
+	Tags        []string                 `json:"tags"`
+	Connections []ConnectionSnapshot     `json:"connections"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	Version     int                      `json:"version"`
+}
+
+// ConnectionSnapshot is the stored form of an EdgeReference
+type ConnectionSnapshot struct {
+	EdgeID   string   `json:"edge_id"`
+	TargetID string   `json:"target_id"`
+	Type     EdgeType `json:"type"`
+}
+
+// Snapshot captures the event-sourced state of the node. Derived attributes
+// such as the embedding and community are not part of the event stream and
+// are left out.
+func (n *Node) Snapshot() NodeSnapshot {
+	connections := make([]ConnectionSnapshot, 0, len(n.edges))
+	for _, edge := range n.edges {
+		connections = append(connections, ConnectionSnapshot{
+			EdgeID:   edge.EdgeID,
+			TargetID: edge.TargetID.String(),
+			Type:     edge.Type,
+		})
+	}
+
+	return NodeSnapshot{
+		ID:          n.id.String(),
+		UserID:      n.userID,
+		GraphID:     n.graphID,
+		Content:     n.content,
+		Position:    n.position,
+		Status:      n.status,
+		Tags:        n.GetTags(),
+		Connections: connections,
+		CreatedAt:   n.createdAt,
+		UpdatedAt:   n.updatedAt,
+		Version:     n.version,
+	}
+}
+
+// NodeFromSnapshot rebuilds a node from a snapshot
+func NodeFromSnapshot(snapshot NodeSnapshot) (*Node, error) {
+	id, err := valueobjects.NewNodeIDFromString(snapshot.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot node ID: %w", err)
+	}
+
+	node, err := ReconstructNode(
+		id,
+		snapshot.UserID,
+		snapshot.Content,
+		snapshot.Position,
+		snapshot.GraphID,
+		snapshot.CreatedAt,
+		snapshot.UpdatedAt,
+		snapshot.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	node.metadata.Tags = append([]string{}, snapshot.Tags...)
+	for _, connection := range snapshot.Connections {
+		targetID, err := valueobjects.NewNodeIDFromString(connection.TargetID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot connection: %w", err)
+		}
+		node.RestoreConnection(connection.EdgeID, targetID, connection.Type)
+	}
+	node.version = snapshot.Version
+
+	return node, nil
+}
+
+// AdoptUnsourcedState copies the attributes that are not captured by node
+// events from a stored copy of the same node: the graph it belongs to, the
+// embedding, the community assignment and the presentational metadata. Tags
+// are event-sourced and are left untouched.
+func (n *Node) AdoptUnsourcedState(from *Node) {
+	if from == nil {
+		return
+	}
+	if from.graphID != "" {
+		n.graphID = from.graphID
+	}
+	n.embedding = from.embedding
+	n.communityID = from.communityID
+
+	tags := n.metadata.Tags
+	n.metadata = from.metadata
+	n.metadata.Tags = tags
+	n.metadata.Categories = append([]string{}, from.metadata.Categories...)
+	if from.metadata.Properties != nil {
+		n.metadata.Properties = make(map[string]interface{}, len(from.metadata.Properties))
+		for k, v := range from.metadata.Properties {
+			n.metadata.Properties[k] = v
+		}
+	}
+}
+
+// RehydrateNode rebuilds a node by replaying its history on top of an optional
+// snapshot. Without a snapshot the history must start with the node's
+// creation. Events are applied in version order, and events at or below the
+// snapshot version are skipped, so passing the full stream is safe.
+func RehydrateNode(snapshot *NodeSnapshot, history []events.DomainEvent) (*Node, error) {
+	ordered := make([]events.DomainEvent, len(history))
+	copy(ordered, history)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GetVersion() < ordered[j].GetVersion()
+	})
+
+	var node *Node
+	if snapshot != nil {
+		restored, err := NodeFromSnapshot(*snapshot)
+		if err != nil {
+			return nil, err
+		}
+		node = restored
+	} else {
+		if len(ordered) == 0 {
+			return nil, pkgerrors.NewNotFoundError("node history")
+		}
+		created, ok := ordered[0].(events.NodeCreated)
+		if !ok {
+			return nil, fmt.Errorf("node history must start with node.created, got %s", ordered[0].GetEventType())
+		}
+		restored, err := nodeFromCreated(created)
+		if err != nil {
+			return nil, err
+		}
+		node = restored
+		ordered = ordered[1:]
+	}
+
+	for _, event := range ordered {
+		if snapshot != nil && event.GetVersion() <= snapshot.Version {
+			continue
+		}
+		if err := node.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+
+	// A replayed history describes what has been stored
+	node.persistedVersion = node.version
+	return node, nil
+}
+
+// HistoryRecordsState reports whether the events replayed on top of snapshot
+// carry the node state they change. Events stored before content and
+// positions were serialized hold them as empty JSON objects: a creation or
+// content update without a format, or a move between two zero positions,
+// which MoveTo never raises. Replaying such a history would blank the node.
+func HistoryRecordsState(snapshot *NodeSnapshot, history []events.DomainEvent) bool {
+	for _, event := range history {
+		if snapshot != nil && event.GetVersion() <= snapshot.Version {
+			continue
+		}
+		switch e := event.(type) {
+		case events.NodeCreated:
+			if e.Format == "" {
+				return false
+			}
+		case events.NodeContentUpdated:
+			if e.NewContent.Format() == "" {
+				return false
+			}
+		case events.NodeMoved:
+			if e.OldPosition.Equals(e.NewPosition) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nodeFromCreated builds the initial node state from its creation event
+func nodeFromCreated(created events.NodeCreated) (*Node, error) {
+	format := valueobjects.ContentFormat(created.Format)
+	if format == "" {
+		format = valueobjects.FormatMarkdown
+	}
+	content, err := valueobjects.NewNodeContent(created.Title, created.Content, format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content in node.created: %w", err)
+	}
+
+	node, err := ReconstructNode(
+		created.NodeID,
+		created.UserID,
+		content,
+		created.Position,
+		created.GraphID,
+		created.Timestamp,
+		created.Timestamp,
+		StatusDraft,
+	)
+	if err != nil {
+		return nil, err
+	}
+	node.metadata.Tags = append([]string{}, created.Tags...)
+	node.version = created.Version
+
+	return node, nil
+}
+
+// Apply mutates the node according to a recorded event. Unlike the command
+// methods it performs no validation and raises no events: the event is a fact
+// that has already happened. Events that do not change node state are ignored.
+func (n *Node) Apply(event events.DomainEvent) error {
+	if event.GetAggregateID() != n.id.String() {
+		return fmt.Errorf("event %s belongs to aggregate %s, not node %s",
+			event.GetEventType(), event.GetAggregateID(), n.id)
+	}
+
+	switch e := event.(type) {
+	case events.NodeCreated:
+		return pkgerrors.NewConflictError("node already created")
+	case events.NodeContentUpdated:
+		n.content = e.NewContent
+	case events.NodeMoved:
+		n.position = e.NewPosition
+	case events.NodePublished:
+		n.status = StatusPublished
+	case events.NodeArchived:
+		n.status = StatusArchived
+		n.edges = []EdgeReference{}
+	case events.NodeTagsUpdated:
+		n.metadata.Tags = append([]string{}, e.Tags...)
+	case events.NodesConnected:
+		n.RestoreConnection(e.EdgeID, e.TargetID, EdgeType(e.EdgeType))
+	case events.NodesDisconnected:
+		edges := make([]EdgeReference, 0, len(n.edges))
+		for _, edge := range n.edges {
+			if !edge.TargetID.Equals(e.TargetID) {
+				edges = append(edges, edge)
+			}
+		}
+		n.edges = edges
+	default:
+		return nil
+	}
+
+	n.updatedAt = event.GetTimestamp()
+	if event.GetVersion() > n.version {
+		n.version = event.GetVersion()
+	}
+	return nil
+}
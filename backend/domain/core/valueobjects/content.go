@@ -1,6 +1,7 @@
 package valueobjects
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -122,6 +123,34 @@ func (c NodeContent) Summary(maxLength int) string {
 	return string(runes[:maxLength-3]) + "..."
 }
 
+// contentJSON is the wire form of NodeContent
+type contentJSON struct {
+	Title  string        `json:"title"`
+	Body   string        `json:"body"`
+	Format ContentFormat `json:"format"`
+}
+
+// MarshalJSON implements json.Marshaler so content survives event
+// serialization. Without it the unexported fields marshalled as {}, which is
+// how content updates stored before it carry their content.
+func (c NodeContent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(contentJSON{Title: c.title, Body: c.body, Format: c.format})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Stored content was validated when
+// it was created, so it is restored as-is rather than re-validated against the
+// current limits.
+func (c *NodeContent) UnmarshalJSON(data []byte) error {
+	var wire contentJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	c.title = wire.Title
+	c.body = wire.Body
+	c.format = wire.Format
+	return nil
+}
+
 func isValidFormat(format ContentFormat) bool {
 	switch format {
 	case FormatPlainText, FormatMarkdown, FormatHTML, FormatJSON:
@@ -1,6 +1,7 @@
 package valueobjects
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -370,4 +371,16 @@ func BenchmarkNodeContent_Summary(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = content.Summary(100)
 	}
-}
\ No newline at end of file
+}
+
+func TestNodeContent_JSONRoundTrip(t *testing.T) {
+	content, err := NewNodeContent("Event sourcing", "Replay the stream", FormatMarkdown)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(content)
+	require.NoError(t, err)
+
+	var decoded NodeContent
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, content.Equals(decoded))
+}
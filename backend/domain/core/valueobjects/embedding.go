@@ -1,7 +1,9 @@
 package valueobjects
 
 import (
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"math"
 
@@ -9,10 +11,20 @@ import (
 )
 
 // Embedding is a value object representing a vector embedding for semantic similarity.
-// It wraps a float64 slice and provides cosine similarity computation.
+// It wraps a float64 slice and provides cosine similarity computation. Its
+// provenance records the model that produced it and a hash of the embedded
+// text; embeddings stored before provenance was recorded have neither.
 type Embedding struct {
-	vector     []float64
-	dimensions int
+	vector      []float64
+	dimensions  int
+	model       string
+	contentHash string
+}
+
+// EmbeddingContentHash returns the hash recorded with an embedding of text.
+func EmbeddingContentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
 }
 
 // NewEmbedding creates an Embedding from a float64 slice with dimension validation.
@@ -73,6 +85,36 @@ func (e Embedding) Dimensions() int {
 	return e.dimensions
 }
 
+// Model returns the ID of the model that produced the embedding, or "" when
+// unknown.
+func (e Embedding) Model() string {
+	return e.model
+}
+
+// ContentHash returns the EmbeddingContentHash of the embedded text, or ""
+// when unknown.
+func (e Embedding) ContentHash() string {
+	return e.contentHash
+}
+
+// WithProvenance returns a copy of the embedding recording the model that
+// produced it and the hash of the embedded text.
+func (e Embedding) WithProvenance(model, contentHash string) Embedding {
+	e.model = model
+	e.contentHash = contentHash
+	return e
+}
+
+// IsCompatible reports whether the embedding can be compared with other: both
+// have the same dimensions and, when both models are known, the same model.
+// Vectors of different models live in unrelated spaces even at equal size.
+func (e Embedding) IsCompatible(other Embedding) bool {
+	if e.dimensions != other.dimensions {
+		return false
+	}
+	return e.model == "" || other.model == "" || e.model == other.model
+}
+
 // IsZero returns true if the embedding has no vector data.
 func (e Embedding) IsZero() bool {
 	return e.dimensions == 0
@@ -94,12 +136,12 @@ func (e Embedding) ToBytes() []byte {
 
 // CosineSimilarity computes the cosine similarity between this embedding and another.
 // Returns a value between -1.0 and 1.0, where 1.0 means identical direction.
-// Returns 0.0 if either embedding is zero or dimensions don't match.
+// Returns 0.0 if either embedding is zero or they are not compatible.
 func (e Embedding) CosineSimilarity(other Embedding) float64 {
 	if e.IsZero() || other.IsZero() {
 		return 0.0
 	}
-	if e.dimensions != other.dimensions {
+	if !e.IsCompatible(other) {
 		return 0.0
 	}
 
@@ -129,3 +129,37 @@ func TestEmbedding_Equals(t *testing.T) {
 	assert.True(t, a.Equals(b))
 	assert.False(t, a.Equals(c))
 }
+
+func TestEmbedding_Provenance(t *testing.T) {
+	emb, _ := NewEmbedding([]float64{1.0, 0.0})
+	assert.Empty(t, emb.Model())
+	assert.Empty(t, emb.ContentHash())
+
+	hash := EmbeddingContentHash("title\nbody")
+	stamped := emb.WithProvenance("model-a", hash)
+	assert.Equal(t, "model-a", stamped.Model())
+	assert.Equal(t, hash, stamped.ContentHash())
+	assert.Empty(t, emb.Model(), "original embedding is unchanged")
+	assert.Equal(t, emb.Vector(), stamped.Vector())
+
+	assert.Equal(t, hash, EmbeddingContentHash("title\nbody"))
+	assert.NotEqual(t, hash, EmbeddingContentHash("title\nbody!"))
+}
+
+func TestEmbedding_IsCompatible(t *testing.T) {
+	a, _ := NewEmbedding([]float64{1.0, 0.0})
+	b, _ := NewEmbedding([]float64{1.0, 0.0})
+	wide, _ := NewEmbedding([]float64{1.0, 0.0, 0.0})
+
+	modelA := a.WithProvenance("model-a", "")
+	modelB := b.WithProvenance("model-b", "")
+
+	assert.True(t, a.IsCompatible(b), "unknown models are assumed compatible")
+	assert.True(t, modelA.IsCompatible(b))
+	assert.True(t, modelA.IsCompatible(a.WithProvenance("model-a", "other")))
+	assert.False(t, modelA.IsCompatible(modelB))
+	assert.False(t, a.IsCompatible(wide))
+
+	assert.InDelta(t, 1.0, modelA.CosineSimilarity(b), 1e-9)
+	assert.Equal(t, 0.0, modelA.CosineSimilarity(modelB), "vectors of different models are not compared")
+}
@@ -1,8 +1,10 @@
 package valueobjects
 
 import (
-	pkgerrors "backend/pkg/errors"
+	"encoding/json"
 	"math"
+
+	pkgerrors "backend/pkg/errors"
 )
 
 // Position is a value object representing node coordinates in 2D/3D space
@@ -75,6 +77,33 @@ func (p Position) Midpoint(other Position) Position {
 	}
 }
 
+// positionJSON is the wire form of Position
+type positionJSON struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// MarshalJSON implements json.Marshaler. Without it the unexported fields
+// marshalled as {}, which is how moves stored before it carry their positions.
+func (p Position) MarshalJSON() ([]byte, error) {
+	return json.Marshal(positionJSON{X: p.x, Y: p.y, Z: p.z})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (p *Position) UnmarshalJSON(data []byte) error {
+	var wire positionJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	position, err := NewPosition3D(wire.X, wire.Y, wire.Z)
+	if err != nil {
+		return err
+	}
+	*p = position
+	return nil
+}
+
 // isValidCoordinate checks if a coordinate is a valid finite number
 func isValidCoordinate(v float64) bool {
 	return !math.IsNaN(v) && !math.IsInf(v, 0)
@@ -1,6 +1,7 @@
 package valueobjects
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 
@@ -517,4 +518,17 @@ func BenchmarkPosition_Midpoint(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = pos1.Midpoint(pos2)
 	}
-}
\ No newline at end of file
+}
+
+func TestPosition_JSONRoundTrip(t *testing.T) {
+	position, err := NewPosition3D(1.5, -2, 3)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(position)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"x":1.5,"y":-2,"z":3}`, string(data))
+
+	var decoded Position
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, position.Equals(decoded))
+}
@@ -39,6 +39,11 @@ type NodeCreated struct {
 	Content  string              `json:"content"`
 	Keywords []string            `json:"keywords"`
 	Tags     []string            `json:"tags"`
+
+	// Format and Position complete the initial state so the node can be
+	// rebuilt from its event stream
+	Format   string                `json:"format,omitempty"`
+	Position valueobjects.Position `json:"position"`
 }
 
 // NewNodeCreated creates a NodeCreated event
@@ -144,6 +149,27 @@ func NewNodeArchived(nodeID valueobjects.NodeID, timestamp time.Time) NodeArchiv
 	}
 }
 
+// NodeTagsUpdated is raised when the tags of an existing node change
+type NodeTagsUpdated struct {
+	BaseEvent
+	NodeID valueobjects.NodeID `json:"node_id"`
+	Tags   []string            `json:"tags"`
+}
+
+// NewNodeTagsUpdated creates a NodeTagsUpdated event
+func NewNodeTagsUpdated(nodeID valueobjects.NodeID, tags []string, timestamp time.Time) NodeTagsUpdated {
+	return NodeTagsUpdated{
+		BaseEvent: BaseEvent{
+			AggregateID: nodeID.String(),
+			EventType:   "node.tags_updated",
+			Timestamp:   timestamp,
+			Version:     1,
+		},
+		NodeID: nodeID,
+		Tags:   tags,
+	}
+}
+
 // Edge Events
 
 // NodesConnected is raised when two nodes are connected
@@ -152,6 +178,8 @@ type NodesConnected struct {
 	SourceID valueobjects.NodeID `json:"source_id"`
 	TargetID valueobjects.NodeID `json:"target_id"`
 	EdgeType string              `json:"edge_type"`
+	EdgeID   string              `json:"edge_id,omitempty"`
+	Weight   float64             `json:"weight,omitempty"`
 }
 
 // NodesAutoConnected is raised when nodes are automatically connected via edge discovery
@@ -246,6 +274,117 @@ func NewNodeDeletedEvent(nodeID valueobjects.NodeID, userID string, graphID stri
 	}
 }
 
+// Trash Bin Events
+
+// NodeTrashedEvent is raised when a node and its edges are moved to the trash bin
+type NodeTrashedEvent struct {
+	BaseEvent
+	NodeID    valueobjects.NodeID `json:"node_id"`
+	UserID    string              `json:"user_id"`
+	GraphID   string              `json:"graph_id"`
+	Title     string              `json:"title"`
+	EdgeCount int                 `json:"edge_count"`
+	PurgeAt   *time.Time          `json:"purge_at,omitempty"`
+}
+
+// NewNodeTrashedEvent creates a NodeTrashedEvent
+func NewNodeTrashedEvent(nodeID valueobjects.NodeID, userID, graphID, title string, edgeCount int, purgeAt *time.Time, timestamp time.Time) NodeTrashedEvent {
+	return NodeTrashedEvent{
+		BaseEvent: BaseEvent{
+			AggregateID: nodeID.String(),
+			EventType:   "NodeTrashed",
+			Timestamp:   timestamp,
+			Version:     1,
+		},
+		NodeID:    nodeID,
+		UserID:    userID,
+		GraphID:   graphID,
+		Title:     title,
+		EdgeCount: edgeCount,
+		PurgeAt:   purgeAt,
+	}
+}
+
+// NodeRestoredEvent is raised when a trashed node is restored with its edges
+type NodeRestoredEvent struct {
+	BaseEvent
+	NodeID        valueobjects.NodeID `json:"node_id"`
+	UserID        string              `json:"user_id"`
+	GraphID       string              `json:"graph_id"`
+	Title         string              `json:"title"`
+	RestoredEdges int                 `json:"restored_edges"`
+}
+
+// NewNodeRestoredEvent creates a NodeRestoredEvent
+func NewNodeRestoredEvent(nodeID valueobjects.NodeID, userID, graphID, title string, restoredEdges int, timestamp time.Time) NodeRestoredEvent {
+	return NodeRestoredEvent{
+		BaseEvent: BaseEvent{
+			AggregateID: nodeID.String(),
+			EventType:   "NodeRestored",
+			Timestamp:   timestamp,
+			Version:     1,
+		},
+		NodeID:        nodeID,
+		UserID:        userID,
+		GraphID:       graphID,
+		Title:         title,
+		RestoredEdges: restoredEdges,
+	}
+}
+
+// NodePurgedEvent is raised when a trashed node is deleted for good
+type NodePurgedEvent struct {
+	BaseEvent
+	NodeID  valueobjects.NodeID `json:"node_id"`
+	UserID  string              `json:"user_id"`
+	GraphID string              `json:"graph_id"`
+}
+
+// NewNodePurgedEvent creates a NodePurgedEvent
+func NewNodePurgedEvent(nodeID valueobjects.NodeID, userID, graphID string, timestamp time.Time) NodePurgedEvent {
+	return NodePurgedEvent{
+		BaseEvent: BaseEvent{
+			AggregateID: nodeID.String(),
+			EventType:   "NodePurged",
+			Timestamp:   timestamp,
+			Version:     1,
+		},
+		NodeID:  nodeID,
+		UserID:  userID,
+		GraphID: graphID,
+	}
+}
+
+// Graph Version Events
+
+// GraphRolledBackEvent is raised when a graph has been rolled back to one of
+// its versions. The counts are those of the graph after the rollback.
+type GraphRolledBackEvent struct {
+	BaseEvent
+	GraphID   string `json:"graph_id"`
+	UserID    string `json:"user_id"`
+	ToVersion int    `json:"to_version"`
+	NodeCount int    `json:"node_count"`
+	EdgeCount int    `json:"edge_count"`
+}
+
+// NewGraphRolledBackEvent creates a GraphRolledBackEvent
+func NewGraphRolledBackEvent(graphID, userID string, toVersion, nodeCount, edgeCount int, timestamp time.Time) GraphRolledBackEvent {
+	return GraphRolledBackEvent{
+		BaseEvent: BaseEvent{
+			AggregateID: graphID,
+			EventType:   "GraphRolledBack",
+			Timestamp:   timestamp,
+			Version:     1,
+		},
+		GraphID:   graphID,
+		UserID:    userID,
+		ToVersion: toVersion,
+		NodeCount: nodeCount,
+		EdgeCount: edgeCount,
+	}
+}
+
 // Edge Deletion Events
 
 // EdgeDeletedEvent is raised when an edge is deleted
@@ -278,9 +417,18 @@ func NewEdgeDeletedEvent(edgeID string, sourceID, targetID valueobjects.NodeID,
 // GraphCreated is raised when a new graph is created
 type GraphCreated struct {
 	BaseEvent
-	GraphID string `json:"graph_id"`
-	UserID  string `json:"user_id"`
-	Name    string `json:"name"`
+	GraphID     string `json:"graph_id"`
+	UserID      string `json:"user_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// GraphRenamed is raised when a graph's name or description changes
+type GraphRenamed struct {
+	BaseEvent
+	GraphID     string `json:"graph_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // NodeAddedToGraph is raised when a node is added to a graph
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// decoders maps stored event types to their concrete domain event structs.
+// Events that are raised and handled as pointers are decoded as pointers.
+var decoders = map[string]func([]byte) (DomainEvent, error){
+	"node.created":             decodeAs[NodeCreated],
+	"node.content_updated":     decodeAs[NodeContentUpdated],
+	"node.moved":               decodeAs[NodeMoved],
+	"node.published":           decodeAs[NodePublished],
+	"node.archived":            decodeAs[NodeArchived],
+	"node.tags_updated":        decodeAs[NodeTagsUpdated],
+	"nodes.connected":          decodeAs[NodesConnected],
+	"nodes.auto_connected":     decodeAs[NodesAutoConnected],
+	"nodes.disconnected":       decodeAs[NodesDisconnected],
+	"NodeDeleted":              decodeAs[NodeDeletedEvent],
+	"NodeTrashed":              decodeAs[NodeTrashedEvent],
+	"NodeRestored":             decodeAs[NodeRestoredEvent],
+	"NodePurged":               decodeAs[NodePurgedEvent],
+	"GraphRolledBack":          decodeAs[GraphRolledBackEvent],
+	"EdgeDeleted":              decodeAs[EdgeDeletedEvent],
+	"BulkNodesDeleted":         decodePtr[BulkNodesDeletedEvent],
+	"graph.created":            decodeAs[GraphCreated],
+	"graph.renamed":            decodeAs[GraphRenamed],
+	"graph.node_added":         decodeAs[NodeAddedToGraph],
+	"graph.node_removed":       decodeAs[NodeRemovedFromGraph],
+	"graph.nodes_connected":    decodeAs[NodesConnected],
+	"graph.nodes_disconnected": decodeAs[NodesDisconnected],
+	TypeNodeCreatedWithPending: decodePtr[NodeCreatedWithPendingEdges],
+}
+
+// Decode rebuilds a concrete domain event from its JSON payload. Unknown event
+// types come back as a BaseEvent carrying the given envelope fields, so callers
+// can still order and filter them.
+func Decode(eventType, aggregateID string, version int, timestamp time.Time, data []byte) (DomainEvent, error) {
+	if decode, ok := decoders[eventType]; ok {
+		event, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s event: %w", eventType, err)
+		}
+		return event, nil
+	}
+	return BaseEvent{
+		AggregateID: aggregateID,
+		EventType:   eventType,
+		Timestamp:   timestamp,
+		Version:     version,
+	}, nil
+}
+
+func decodeAs[T DomainEvent](data []byte) (DomainEvent, error) {
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func decodePtr[T any](data []byte) (DomainEvent, error) {
+	event := new(T)
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	domainEvent, ok := any(event).(DomainEvent)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a domain event", event)
+	}
+	return domainEvent, nil
+}
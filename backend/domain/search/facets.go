@@ -0,0 +1,94 @@
+package search
+
+import (
+	"sort"
+
+	"backend/domain/core/entities"
+)
+
+// maxFacetValues bounds the values reported per facet
+const maxFacetValues = 50
+
+// FacetCount is the number of matching nodes with one facet value.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// Facets summarises a set of matching nodes for drill-down filtering. Every
+// facet corresponds to a query field, except content format.
+type Facets struct {
+	Tags         []FacetCount
+	Status       []FacetCount
+	Community    []FacetCount
+	Format       []FacetCount
+	CreatedMonth []FacetCount // YYYY-MM in UTC, newest first
+}
+
+// CountFacets counts the facet values of nodes. Values are ordered by count
+// descending, except months which are ordered by time.
+func CountFacets(nodes []*entities.Node) *Facets {
+	tags := make(map[string]int)
+	status := make(map[string]int)
+	community := make(map[string]int)
+	format := make(map[string]int)
+	months := make(map[string]int)
+
+	for _, n := range nodes {
+		seen := make(map[string]bool)
+		for _, tag := range n.GetTags() {
+			if !seen[tag] {
+				seen[tag] = true
+				tags[tag]++
+			}
+		}
+		status[string(n.Status())]++
+		if id := n.CommunityID(); id != "" {
+			community[id]++
+		}
+		format[string(n.Content().Format())]++
+		months[n.CreatedAt().UTC().Format("2006-01")]++
+	}
+
+	return &Facets{
+		Tags:         byCount(tags),
+		Status:       byCount(status),
+		Community:    byCount(community),
+		Format:       byCount(format),
+		CreatedMonth: byValue(months),
+	}
+}
+
+func byCount(counts map[string]int) []FacetCount {
+	out := toFacetCounts(counts)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Value < out[j].Value
+	})
+	return truncateFacet(out)
+}
+
+func byValue(counts map[string]int) []FacetCount {
+	out := toFacetCounts(counts)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Value > out[j].Value
+	})
+	return truncateFacet(out)
+}
+
+func toFacetCounts(counts map[string]int) []FacetCount {
+	out := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		out = append(out, FacetCount{Value: value, Count: count})
+	}
+	return out
+}
+
+func truncateFacet(counts []FacetCount) []FacetCount {
+	if len(counts) > maxFacetValues {
+		return counts[:maxFacetValues]
+	}
+	return counts
+}
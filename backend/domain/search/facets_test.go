@@ -0,0 +1,41 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"backend/domain/core/entities"
+)
+
+func TestCountFacets(t *testing.T) {
+	a := newTestNode(t, "A", "body", "ml", "go")
+	a.SetCommunityID("c1")
+	b := newTestNode(t, "B", "body", "ml")
+	c := newTestNode(t, "C", "body")
+	c.SetCommunityID("c1")
+
+	facets := CountFacets([]*entities.Node{a, b, c})
+
+	wantTags := []FacetCount{{Value: "ml", Count: 2}, {Value: "go", Count: 1}}
+	if len(facets.Tags) != len(wantTags) {
+		t.Fatalf("expected %v, got %v", wantTags, facets.Tags)
+	}
+	for i, tag := range wantTags {
+		if facets.Tags[i] != tag {
+			t.Errorf("expected %v, got %v", wantTags, facets.Tags)
+		}
+	}
+	if len(facets.Status) != 1 || facets.Status[0] != (FacetCount{Value: "draft", Count: 3}) {
+		t.Errorf("unexpected status facet %v", facets.Status)
+	}
+	if len(facets.Community) != 1 || facets.Community[0].Count != 2 {
+		t.Errorf("nodes without a community are not counted, got %v", facets.Community)
+	}
+	if len(facets.Format) != 1 || facets.Format[0].Value != "markdown" {
+		t.Errorf("unexpected format facet %v", facets.Format)
+	}
+	month := time.Now().UTC().Format("2006-01")
+	if len(facets.CreatedMonth) != 1 || facets.CreatedMonth[0] != (FacetCount{Value: month, Count: 3}) {
+		t.Errorf("unexpected month facet %v", facets.CreatedMonth)
+	}
+}
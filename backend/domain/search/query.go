@@ -0,0 +1,322 @@
+// Package search implements the structured search query language, e.g.
+//
+//	tag:ml status:published created:>2025-01-01 community:xyz "exact phrase" -excluded
+//
+// A query is parsed into an AST of clauses. Free text terms and phrases are
+// ranked by keyword and semantic search; field filters, phrases and negated
+// clauses are translated into node specifications that every result must
+// satisfy.
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"backend/domain/core/entities"
+)
+
+// Field is a node attribute a clause filters on.
+type Field string
+
+const (
+	FieldTag       Field = "tag"
+	FieldStatus    Field = "status"
+	FieldCreated   Field = "created"
+	FieldUpdated   Field = "updated"
+	FieldCommunity Field = "community"
+	FieldGraph     Field = "graph"
+)
+
+// fields lists the supported fields in the order they are reported in errors
+var fields = []Field{FieldTag, FieldStatus, FieldCreated, FieldUpdated, FieldCommunity, FieldGraph}
+
+// isDate reports whether the field holds a timestamp
+func (f Field) isDate() bool {
+	return f == FieldCreated || f == FieldUpdated
+}
+
+// Operator compares a date field with a clause value.
+type Operator string
+
+const (
+	OpEqual        Operator = "="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+)
+
+// ClauseKind distinguishes the clause types of a query.
+type ClauseKind int
+
+const (
+	// ClauseTerm is a single free text word
+	ClauseTerm ClauseKind = iota
+	// ClausePhrase is a quoted sequence of words that must appear in order
+	ClausePhrase
+	// ClauseField filters on a node attribute
+	ClauseField
+)
+
+// Clause is one element of a parsed query.
+type Clause struct {
+	Kind    ClauseKind
+	Negated bool   // Prefixed with '-': matching nodes are excluded
+	Text    string // Term or phrase text; the raw value for field clauses
+
+	Field    Field
+	Operator Operator  // Date fields only
+	Time     time.Time // Parsed value of date fields
+	Day      bool      // The date value has no time of day and covers the whole day
+}
+
+// Query is the AST of a search query.
+type Query struct {
+	Raw     string
+	Clauses []Clause
+}
+
+// ParseError describes why a query could not be parsed.
+type ParseError struct {
+	Position int // Byte offset in the query where the problem starts
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid search query at position %d: %s", e.Position, e.Message)
+}
+
+// FreeText returns the words and phrases that should rank results, without
+// operators and negated clauses.
+func (q *Query) FreeText() string {
+	parts := make([]string, 0, len(q.Clauses))
+	for _, c := range q.Clauses {
+		if c.Negated || c.Kind == ClauseField {
+			continue
+		}
+		parts = append(parts, c.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// HasFilters reports whether any clause restricts results beyond ranking.
+func (q *Query) HasFilters() bool {
+	for _, c := range q.Clauses {
+		if c.Negated || c.Kind != ClauseTerm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty reports whether the query has no clauses.
+func (q *Query) IsEmpty() bool {
+	return len(q.Clauses) == 0
+}
+
+// Parse parses a search query. Whitespace separates clauses; double quotes
+// group words into a phrase or a field value; a leading '-' negates a clause.
+func Parse(input string) (*Query, error) {
+	p := &parser{input: input}
+	query := &Query{Raw: input}
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		clause, err := p.clause()
+		if err != nil {
+			return nil, err
+		}
+		query.Clauses = append(query.Clauses, clause)
+	}
+	return query, nil
+}
+
+// TextQuery returns a query that ranks by the words of text without
+// interpreting operators, for natural language input such as questions.
+// Punctuation around words is dropped.
+func TextQuery(text string) *Query {
+	query := &Query{Raw: text}
+	for _, field := range strings.Fields(text) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if word != "" {
+			query.Clauses = append(query.Clauses, Clause{Kind: ClauseTerm, Text: word})
+		}
+	}
+	return query
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	return p.input[p.pos]
+}
+
+// atSpace reports whether the character at the current position is
+// whitespace, and how many bytes it takes. Characters are decoded whole so
+// that bytes inside a multi-byte character are never taken for spaces.
+func (p *parser) atSpace() (bool, int) {
+	r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+	return unicode.IsSpace(r), size
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() {
+		space, size := p.atSpace()
+		if !space {
+			return
+		}
+		p.pos += size
+	}
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) *ParseError {
+	return &ParseError{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// clause parses one clause starting at the current position
+func (p *parser) clause() (Clause, error) {
+	start := p.pos
+	negated := false
+	if p.peek() == '-' {
+		negated = true
+		p.pos++
+		if space, _ := p.atSpace(); p.done() || space {
+			return Clause{}, p.errorf(start, "'-' must be followed by a term, phrase or field")
+		}
+	}
+
+	if p.peek() == '"' {
+		text, err := p.quoted()
+		if err != nil {
+			return Clause{}, err
+		}
+		return Clause{Kind: ClausePhrase, Negated: negated, Text: text}, nil
+	}
+
+	wordStart := p.pos
+	word := p.word()
+	if colon := strings.IndexByte(word, ':'); colon > 0 {
+		// Words like "re:", "10:30" or URLs are not fields; only a known
+		// field name makes the rest a field value
+		if field, ok := lookupField(strings.ToLower(word[:colon])); ok {
+			// The value starts right after the colon and may be quoted
+			p.pos = wordStart + colon + 1
+			return p.fieldClause(field, negated, p.pos)
+		}
+	}
+
+	return Clause{Kind: ClauseTerm, Negated: negated, Text: word}, nil
+}
+
+// word consumes characters up to the next space or quote
+func (p *parser) word() string {
+	start := p.pos
+	for !p.done() && p.peek() != '"' {
+		space, size := p.atSpace()
+		if space {
+			break
+		}
+		p.pos += size
+	}
+	return p.input[start:p.pos]
+}
+
+// quoted consumes a double quoted string and returns its content
+func (p *parser) quoted() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	end := strings.IndexByte(p.input[p.pos:], '"')
+	if end < 0 {
+		return "", p.errorf(start, "unterminated quote")
+	}
+	text := strings.TrimSpace(p.input[p.pos : p.pos+end])
+	p.pos += end + 1
+	if text == "" {
+		return "", p.errorf(start, "empty quotes")
+	}
+	return text, nil
+}
+
+func (p *parser) fieldClause(field Field, negated bool, valueStart int) (Clause, error) {
+	clause := Clause{Kind: ClauseField, Negated: negated, Field: field}
+
+	if field.isDate() {
+		clause.Operator = p.operator()
+	}
+
+	var value string
+	if !p.done() && p.peek() == '"' {
+		quoted, err := p.quoted()
+		if err != nil {
+			return Clause{}, err
+		}
+		value = quoted
+	} else {
+		value = p.word()
+	}
+	if value == "" {
+		return Clause{}, p.errorf(valueStart, "field %q needs a value", field)
+	}
+	clause.Text = value
+
+	switch {
+	case field.isDate():
+		t, day, err := parseDate(value)
+		if err != nil {
+			return Clause{}, p.errorf(valueStart, "field %q expects a date like 2025-01-31 or 2025-01-31T15:04:05Z, got %q", field, value)
+		}
+		clause.Time = t
+		clause.Day = day
+	case field == FieldStatus:
+		status := entities.NodeStatus(strings.ToLower(value))
+		if status != entities.StatusDraft && status != entities.StatusPublished && status != entities.StatusArchived {
+			return Clause{}, p.errorf(valueStart, "unknown status %q, expected draft, published or archived", value)
+		}
+		clause.Text = string(status)
+	}
+	return clause, nil
+}
+
+// operator consumes a comparison operator, defaulting to equality
+func (p *parser) operator() Operator {
+	for _, op := range []Operator{OpGreaterEqual, OpLessEqual, OpGreater, OpLess, OpEqual} {
+		if strings.HasPrefix(p.input[p.pos:], string(op)) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return OpEqual
+}
+
+// parseDate accepts a calendar day or a full RFC 3339 timestamp. Reports
+// whether the value was a whole day.
+func parseDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+func lookupField(name string) (Field, bool) {
+	for _, f := range fields {
+		if string(f) == name {
+			return f, true
+		}
+	}
+	return "", false
+}
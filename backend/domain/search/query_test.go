@@ -0,0 +1,223 @@
+package search
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+func TestParse_Clauses(t *testing.T) {
+	q, err := Parse(`tag:ml status:Published created:>2025-01-01 community:xyz "exact  phrase" -excluded neural`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.Clauses) != 7 {
+		t.Fatalf("expected 7 clauses, got %d", len(q.Clauses))
+	}
+
+	want := []Clause{
+		{Kind: ClauseField, Field: FieldTag, Text: "ml"},
+		{Kind: ClauseField, Field: FieldStatus, Text: "published"},
+		{Kind: ClauseField, Field: FieldCreated, Operator: OpGreater, Text: "2025-01-01",
+			Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Day: true},
+		{Kind: ClauseField, Field: FieldCommunity, Text: "xyz"},
+		{Kind: ClausePhrase, Text: "exact  phrase"},
+		{Kind: ClauseTerm, Negated: true, Text: "excluded"},
+		{Kind: ClauseTerm, Text: "neural"},
+	}
+	for i, c := range q.Clauses {
+		if c != want[i] {
+			t.Errorf("clause %d: expected %+v, got %+v", i, want[i], c)
+		}
+	}
+
+	if got := q.FreeText(); got != "exact  phrase neural" {
+		t.Errorf("unexpected free text %q", got)
+	}
+	if !q.HasFilters() {
+		t.Error("expected filters")
+	}
+}
+
+func TestParse_QuotedFieldValueAndNegatedField(t *testing.T) {
+	q, err := Parse(`-tag:"machine learning" updated:<=2025-02-03T10:00:00Z`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.Clauses) != 2 {
+		t.Fatalf("expected 2 clauses, got %d", len(q.Clauses))
+	}
+	if c := q.Clauses[0]; !c.Negated || c.Field != FieldTag || c.Text != "machine learning" {
+		t.Errorf("unexpected tag clause %+v", c)
+	}
+	if c := q.Clauses[1]; c.Operator != OpLessEqual || c.Day || c.Time.Hour() != 10 {
+		t.Errorf("unexpected date clause %+v", c)
+	}
+	if q.FreeText() != "" {
+		t.Errorf("expected no free text, got %q", q.FreeText())
+	}
+}
+
+func TestParse_PlainTextHasNoFilters(t *testing.T) {
+	q, err := Parse("  machine   learning ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.HasFilters() || q.Specification() != nil {
+		t.Error("plain terms should only rank")
+	}
+	if q.FreeText() != "machine learning" {
+		t.Errorf("unexpected free text %q", q.FreeText())
+	}
+}
+
+func TestTextQuery_IgnoresOperators(t *testing.T) {
+	q := TextQuery(`What did I note about "tag:ml" -- and re-ranking?`)
+	if q.HasFilters() || q.Specification() != nil {
+		t.Error("a text query should only rank")
+	}
+	if got := q.FreeText(); got != "What did I note about tag:ml and re-ranking" {
+		t.Errorf("unexpected free text %q", got)
+	}
+}
+
+func TestParse_NonASCIITerms(t *testing.T) {
+	// U+00E0 and U+00E9 end in the bytes 0xA0 and 0xA9, and Cyrillic letters
+	// in 0x80-0xBF, which must not be taken for spaces
+	q, err := Parse("voilà résumé -Россия tag:café")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Clause{
+		{Kind: ClauseTerm, Text: "voilà"},
+		{Kind: ClauseTerm, Text: "résumé"},
+		{Kind: ClauseTerm, Negated: true, Text: "Россия"},
+		{Kind: ClauseField, Field: FieldTag, Text: "café"},
+	}
+	if len(q.Clauses) != len(want) {
+		t.Fatalf("expected %d clauses, got %+v", len(want), q.Clauses)
+	}
+	for i, c := range q.Clauses {
+		if c != want[i] {
+			t.Errorf("clause %d: expected %+v, got %+v", i, want[i], c)
+		}
+	}
+
+	// Unicode whitespace still separates clauses
+	q, err = Parse("naïve\u00a0bayes\u2003über")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := q.FreeText(); got != "naïve bayes über" {
+		t.Errorf("unexpected free text %q", got)
+	}
+}
+
+func TestParse_UnknownFieldIsTerm(t *testing.T) {
+	for _, input := range []string{"re: budget", "meeting at 10:30", "https://example.com", "colour:red"} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", input, err)
+			continue
+		}
+		if q.HasFilters() {
+			t.Errorf("%q: expected only terms, got %+v", input, q.Clauses)
+		}
+		if got := q.FreeText(); got != input {
+			t.Errorf("%q: unexpected free text %q", input, got)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+	}{
+		{`"unterminated phrase`, 0},
+		{`tag:`, 4},
+		{`created:>yesterday`, 8},
+		{`status:deleted`, 7},
+		{`ok - alone`, 3},
+		{`""`, 0},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: expected a parse error, got %v", tt.query, err)
+			continue
+		}
+		if parseErr.Position != tt.position {
+			t.Errorf("%q: expected position %d, got %d (%s)", tt.query, tt.position, parseErr.Position, parseErr.Message)
+		}
+	}
+}
+
+func newTestNode(t *testing.T, title, body string, tags ...string) *entities.Node {
+	t.Helper()
+	content, err := valueobjects.NewNodeContent(title, body, valueobjects.FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	position, err := valueobjects.NewPosition3D(0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := entities.NewNode("user-1", content, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.SetGraphID("g1")
+	for _, tag := range tags {
+		if err := node.AddTag(tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return node
+}
+
+func TestQuery_Specification(t *testing.T) {
+	node := newTestNode(t, "Neural networks", "An exact phrase, with punctuation.", "ML")
+	node.SetCommunityID("c1")
+	today := time.Now().UTC().Format("2006-01-02")
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`tag:ml`, true},
+		{`tag:cooking`, false},
+		{`-tag:ml`, false},
+		{`status:draft`, true},
+		{`status:published`, false},
+		{`community:c1 graph:g1`, true},
+		{`community:c2`, false},
+		{`"exact phrase"`, true},
+		{`"phrase exact"`, false},
+		{`-punctuation`, false},
+		{`-missing`, true},
+		{`created:` + today, true},
+		{`created:>` + today, false},
+		{`created:>=` + today, true},
+		{`updated:<` + today, false},
+		{`created:<=` + today, true},
+		{`created:<2000-01-01`, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.query, err)
+		}
+		spec := q.Specification()
+		if spec == nil {
+			t.Fatalf("%q: expected a specification", tt.query)
+		}
+		if got := spec.IsSatisfiedBy(node); got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// snippetWords is the length of a body snippet in words
+	snippetWords = 24
+	// snippetLeadWords is how many words of context precede the first match
+	snippetLeadWords = 4
+	// maxBodySnippets is how many passages are taken from a body
+	maxBodySnippets = 2
+)
+
+// Snippet is a passage of a node's title or body with the query matches
+// marked.
+type Snippet struct {
+	Field       string // "title" or "body"
+	Text        string // Plain passage, with "…" where it was cut
+	Highlighted string // Text, HTML escaped, with each match wrapped in <mark></mark>
+	Matches     int    // Number of matched words in the passage
+}
+
+// Highlighter finds and marks the passages of a text that best match a
+// query's terms and phrases.
+type Highlighter struct {
+	terms   map[string]bool
+	phrases [][]string
+	match   func(word string) bool
+}
+
+// NewHighlighter creates a highlighter for single word terms and phrases.
+// Matching ignores case and punctuation.
+func NewHighlighter(terms []string, phrases []string) *Highlighter {
+	h := &Highlighter{terms: make(map[string]bool)}
+	for _, term := range terms {
+		for _, w := range splitWords(term) {
+			h.terms[w] = true
+		}
+	}
+	for _, phrase := range phrases {
+		if words := splitWords(phrase); len(words) > 0 {
+			h.phrases = append(h.phrases, words)
+		}
+	}
+	return h
+}
+
+// WithMatcher also marks every word for which match reports true, such as
+// inflections and close spellings of the terms that keyword search matched.
+// match receives words as written.
+func (h *Highlighter) WithMatcher(match func(word string) bool) *Highlighter {
+	h.match = match
+	return h
+}
+
+// Phrases returns the text of the query's positive phrase clauses.
+func (q *Query) Phrases() []string {
+	phrases := make([]string, 0)
+	for _, c := range q.Clauses {
+		if c.Kind == ClausePhrase && !c.Negated {
+			phrases = append(phrases, c.Text)
+		}
+	}
+	return phrases
+}
+
+// Snippets returns a title snippet when the title matches, followed by the
+// best matching body passages. When nothing in the body matches, its opening
+// passage is returned so every hit has some context.
+func (h *Highlighter) Snippets(title, body string) []Snippet {
+	snippets := make([]Snippet, 0, 1+maxBodySnippets)
+
+	titleWords := tokenize(title)
+	if marked := h.mark(titleWords); countMarked(marked) > 0 {
+		snippets = append(snippets, buildSnippet("title", title, titleWords, marked, 0, len(titleWords)))
+	}
+
+	bodyWords := tokenize(body)
+	if len(bodyWords) == 0 {
+		return snippets
+	}
+	marked := h.mark(bodyWords)
+	windows := bestWindows(marked, maxBodySnippets)
+	if len(windows) == 0 {
+		windows = []int{0}
+	}
+	for _, start := range windows {
+		end := start + snippetWords
+		if end > len(bodyWords) {
+			end = len(bodyWords)
+		}
+		snippets = append(snippets, buildSnippet("body", body, bodyWords, marked, start, end))
+	}
+	return snippets
+}
+
+// word is a run of letters and digits within a text
+type word struct {
+	start, end int // byte offsets
+	lower      string
+}
+
+func tokenize(text string) []word {
+	words := make([]word, 0)
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			words = append(words, word{start: start, end: i, lower: strings.ToLower(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{start: start, end: len(text), lower: strings.ToLower(text[start:])})
+	}
+	return words
+}
+
+func splitWords(text string) []string {
+	words := tokenize(text)
+	out := make([]string, len(words))
+	for i, w := range words {
+		out[i] = w.lower
+	}
+	return out
+}
+
+// mark reports for each word whether it is part of a match
+func (h *Highlighter) mark(words []word) []bool {
+	marked := make([]bool, len(words))
+	for i, w := range words {
+		if h.terms[w.lower] || (h.match != nil && h.match(w.lower)) {
+			marked[i] = true
+		}
+	}
+	for _, phrase := range h.phrases {
+		for i := 0; i+len(phrase) <= len(words); i++ {
+			match := true
+			for j, p := range phrase {
+				if words[i+j].lower != p {
+					match = false
+					break
+				}
+			}
+			if match {
+				for j := range phrase {
+					marked[i+j] = true
+				}
+			}
+		}
+	}
+	return marked
+}
+
+func countMarked(marked []bool) int {
+	n := 0
+	for _, m := range marked {
+		if m {
+			n++
+		}
+	}
+	return n
+}
+
+// bestWindows picks up to n non-overlapping windows with the most matched
+// words, each starting a little before a match, in text order
+func bestWindows(marked []bool, n int) []int {
+	type window struct{ start, matches int }
+	candidates := make([]window, 0)
+	seen := make(map[int]bool)
+	for i, m := range marked {
+		if !m {
+			continue
+		}
+		// Start a little before the match, but keep the window full near the end
+		start := i - snippetLeadWords
+		if start+snippetWords > len(marked) {
+			start = len(marked) - snippetWords
+		}
+		if start < 0 {
+			start = 0
+		}
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		end := start + snippetWords
+		if end > len(marked) {
+			end = len(marked)
+		}
+		candidates = append(candidates, window{start: start, matches: countMarked(marked[start:end])})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].matches > candidates[j].matches
+	})
+
+	chosen := make([]int, 0, n)
+	for _, c := range candidates {
+		if len(chosen) == n {
+			break
+		}
+		overlaps := false
+		for _, start := range chosen {
+			if c.start < start+snippetWords && start < c.start+snippetWords {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			chosen = append(chosen, c.start)
+		}
+	}
+	sort.Ints(chosen)
+	return chosen
+}
+
+// buildSnippet renders words[from:to] of text, marking matched runs
+func buildSnippet(field, text string, words []word, marked []bool, from, to int) Snippet {
+	var plain, highlighted strings.Builder
+	if from > 0 {
+		plain.WriteString("…")
+		highlighted.WriteString("…")
+	}
+
+	matches := 0
+	for i := from; i < to; i++ {
+		if i > from {
+			gap := collapseSpace(text[words[i-1].end:words[i].start])
+			// Between two matched words the mark stays open, so a phrase
+			// is highlighted as one
+			plain.WriteString(gap)
+			highlighted.WriteString(html.EscapeString(gap))
+		}
+
+		w := text[words[i].start:words[i].end]
+		plain.WriteString(w)
+		if marked[i] {
+			matches++
+			if i == from || !marked[i-1] {
+				highlighted.WriteString("<mark>")
+			}
+			highlighted.WriteString(html.EscapeString(w))
+			if i == to-1 || !marked[i+1] {
+				highlighted.WriteString("</mark>")
+			}
+		} else {
+			highlighted.WriteString(html.EscapeString(w))
+		}
+	}
+
+	// Keep punctuation that closes the passage, like a full stop
+	tail := ""
+	if to < len(words) {
+		tail = "…"
+	} else if last := words[to-1].end; last < len(text) {
+		tail = strings.TrimSpace(text[last:])
+		if utf8.RuneCountInString(tail) > 3 {
+			tail = ""
+		}
+	}
+	plain.WriteString(tail)
+	highlighted.WriteString(html.EscapeString(tail))
+
+	return Snippet{
+		Field:       field,
+		Text:        plain.String(),
+		Highlighted: highlighted.String(),
+		Matches:     matches,
+	}
+}
+
+// collapseSpace shortens the text between two words to a single space when it
+// holds whitespace, keeping punctuation
+func collapseSpace(gap string) string {
+	if strings.TrimSpace(gap) == gap {
+		return gap
+	}
+	fields := strings.Fields(gap)
+	if len(fields) == 0 {
+		return " "
+	}
+	joined := strings.Join(fields, " ")
+	if first, _ := utf8.DecodeRuneInString(gap); unicode.IsSpace(first) {
+		joined = " " + joined
+	}
+	if last, _ := utf8.DecodeLastRuneInString(gap); unicode.IsSpace(last) {
+		joined += " "
+	}
+	return joined
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlighter_TitleAndBody(t *testing.T) {
+	h := NewHighlighter([]string{"neural"}, []string{"exact phrase"})
+	snippets := h.Snippets("Neural nets", "Some <b>text</b> with an Exact   phrase and neural stuff.")
+
+	if len(snippets) != 2 {
+		t.Fatalf("expected a title and a body snippet, got %d", len(snippets))
+	}
+	if snippets[0].Field != "title" || snippets[0].Highlighted != "<mark>Neural</mark> nets" {
+		t.Errorf("unexpected title snippet %+v", snippets[0])
+	}
+
+	body := snippets[1]
+	if body.Text != "Some <b>text</b> with an Exact phrase and neural stuff." {
+		t.Errorf("unexpected body text %q", body.Text)
+	}
+	want := "Some &lt;b&gt;text&lt;/b&gt; with an <mark>Exact phrase</mark> and <mark>neural</mark> stuff."
+	if body.Highlighted != want {
+		t.Errorf("expected %q, got %q", want, body.Highlighted)
+	}
+	if body.Matches != 3 {
+		t.Errorf("expected 3 matched words, got %d", body.Matches)
+	}
+}
+
+func TestHighlighter_PicksBestPassages(t *testing.T) {
+	filler := strings.Repeat("lorem ipsum dolor sit amet ", 20)
+	body := filler + "the graph database stores graph data " + filler + "a single graph here " + filler
+	h := NewHighlighter([]string{"graph"}, nil)
+
+	snippets := h.Snippets("Untitled", body)
+	if len(snippets) != 2 {
+		t.Fatalf("expected two body passages, got %d", len(snippets))
+	}
+	if !strings.HasPrefix(snippets[0].Text, "…") || !strings.HasSuffix(snippets[0].Text, "…") {
+		t.Errorf("cut passages should be marked, got %q", snippets[0].Text)
+	}
+	if snippets[0].Matches != 2 || snippets[1].Matches != 1 {
+		t.Errorf("expected passages in text order with 2 and 1 matches, got %d and %d",
+			snippets[0].Matches, snippets[1].Matches)
+	}
+	if words := len(strings.Fields(strings.Trim(snippets[0].Text, "…"))); words > snippetWords {
+		t.Errorf("passage has %d words, expected at most %d", words, snippetWords)
+	}
+}
+
+func TestHighlighter_NoMatchReturnsOpening(t *testing.T) {
+	h := NewHighlighter([]string{"absent"}, nil)
+	snippets := h.Snippets("Title", "First words of the body.")
+	if len(snippets) != 1 || snippets[0].Field != "body" || snippets[0].Matches != 0 {
+		t.Fatalf("expected the opening passage, got %+v", snippets)
+	}
+	if snippets[0].Text != "First words of the body." {
+		t.Errorf("unexpected text %q", snippets[0].Text)
+	}
+
+	if got := h.Snippets("Title", ""); len(got) != 0 {
+		t.Errorf("expected no snippets for an empty body, got %+v", got)
+	}
+}
+
+func TestHighlighter_WithMatcher(t *testing.T) {
+	stems := map[string]bool{"run": true, "running": true, "runs": true}
+	h := NewHighlighter(nil, nil).WithMatcher(func(word string) bool { return stems[word] })
+
+	snippets := h.Snippets("Runs", "She was running home.")
+	if len(snippets) != 2 {
+		t.Fatalf("expected a title and a body snippet, got %d", len(snippets))
+	}
+	if snippets[0].Highlighted != "<mark>Runs</mark>" {
+		t.Errorf("unexpected title snippet %q", snippets[0].Highlighted)
+	}
+	if want := "She was <mark>running</mark> home."; snippets[1].Highlighted != want {
+		t.Errorf("expected %q, got %q", want, snippets[1].Highlighted)
+	}
+}
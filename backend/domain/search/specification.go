@@ -0,0 +1,80 @@
+package search
+
+import (
+	"time"
+
+	"backend/domain/core/entities"
+	"backend/domain/specifications"
+)
+
+// Specification translates the query's filters into a node specification.
+// Plain terms only rank results and are not part of it. Returns nil when the
+// query has no filters.
+func (q *Query) Specification() specifications.NodeSpecification {
+	var spec specifications.Specification[*entities.Node]
+	for _, c := range q.Clauses {
+		if c.Kind == ClauseTerm && !c.Negated {
+			continue
+		}
+		clauseSpec := c.specification()
+		if c.Negated {
+			clauseSpec = clauseSpec.Not()
+		}
+		if spec == nil {
+			spec = clauseSpec
+		} else {
+			spec = spec.And(clauseSpec)
+		}
+	}
+	if spec == nil {
+		return nil
+	}
+	return spec
+}
+
+// specification returns the positive specification of a clause
+func (c Clause) specification() specifications.Specification[*entities.Node] {
+	switch c.Kind {
+	case ClauseTerm, ClausePhrase:
+		return specifications.NewNodeContainsPhraseSpec(c.Text)
+	}
+
+	switch c.Field {
+	case FieldTag:
+		return specifications.NewNodeHasTagsSpec([]string{c.Text}, true)
+	case FieldStatus:
+		return specifications.NewNodeStatusSpec(entities.NodeStatus(c.Text))
+	case FieldCommunity:
+		return specifications.NewNodeCommunitySpec(c.Text)
+	case FieldGraph:
+		return specifications.NewNodeGraphSpec(c.Text)
+	case FieldCreated:
+		from, to := c.timeRange()
+		return specifications.NewNodeCreatedBetweenSpec(from, to)
+	case FieldUpdated:
+		from, to := c.timeRange()
+		return specifications.NewNodeUpdatedBetweenSpec(from, to)
+	}
+	return specifications.NewBaseSpecification(func(*entities.Node) bool { return true })
+}
+
+// timeRange turns a date comparison into a half-open range. A calendar day
+// covers all of it, a timestamp covers its second.
+func (c Clause) timeRange() (from, to time.Time) {
+	unit := time.Second
+	if c.Day {
+		unit = 24 * time.Hour
+	}
+	switch c.Operator {
+	case OpGreater:
+		return c.Time.Add(unit), time.Time{}
+	case OpGreaterEqual:
+		return c.Time, time.Time{}
+	case OpLess:
+		return time.Time{}, c.Time
+	case OpLessEqual:
+		return time.Time{}, c.Time.Add(unit)
+	default:
+		return c.Time, c.Time.Add(unit)
+	}
+}
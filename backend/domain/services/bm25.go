@@ -33,21 +33,16 @@ type ScoredDocument struct {
 }
 
 // Score computes BM25 scores for each document against the query terms.
+// Query terms and documents are analyzed alike, and query terms missing from
+// the documents match close spellings at a reduced weight.
 // Returns documents sorted by score descending, excluding zero-score documents.
 func (s *BM25Scorer) Score(queryTerms []string, documents []DocumentRecord) []ScoredDocument {
 	if len(queryTerms) == 0 || len(documents) == 0 {
 		return nil
 	}
 
-	// Normalize query terms
-	normalizedQuery := make([]string, 0, len(queryTerms))
-	for _, t := range queryTerms {
-		lower := strings.ToLower(t)
-		if lower != "" {
-			normalizedQuery = append(normalizedQuery, lower)
-		}
-	}
-	if len(normalizedQuery) == 0 {
+	analyzedQuery := analyzeQuery(s.textAnalyzer, queryTerms)
+	if len(analyzedQuery) == 0 {
 		return nil
 	}
 
@@ -76,6 +71,7 @@ func (s *BM25Scorer) Score(queryTerms []string, documents []DocumentRecord) []Sc
 	}
 
 	avgDL := totalLength / n
+	expandedQuery := expandQueryTerms(analyzedQuery, termDFs)
 
 	// Score each document
 	results := make([]ScoredDocument, 0, len(documents))
@@ -84,18 +80,17 @@ func (s *BM25Scorer) Score(queryTerms []string, documents []DocumentRecord) []Sc
 		tf := docTermFreqs[i]
 		dl := docLengths[i]
 
-		for _, term := range normalizedQuery {
-			freq := float64(tf[term])
-			if freq == 0 {
-				continue
+		for _, group := range expandedQuery {
+			// A query term counts once, with its best matching spelling
+			best := 0.0
+			for _, wt := range group {
+				freq := float64(tf[wt.term])
+				if freq == 0 {
+					continue
+				}
+				best = math.Max(best, wt.weight*bm25TermScore(freq, float64(termDFs[wt.term]), n, dl, avgDL))
 			}
-
-			df := float64(termDFs[term])
-			// IDF: log((N - df + 0.5) / (df + 0.5) + 1)
-			idf := math.Log((n-df+0.5)/(df+0.5) + 1)
-			// TF component with length normalization
-			tfNorm := (freq * (bm25K1 + 1)) / (freq + bm25K1*(1-bm25B+bm25B*(dl/avgDL)))
-			score += idf * tfNorm
+			score += best
 		}
 
 		if score > 0 {
@@ -114,29 +109,24 @@ type DocumentRecord struct {
 	Text string
 }
 
-// tokenize splits text into lowercase tokens, preserving duplicates for TF counting.
+// tokenize returns the analyzed terms of text, preserving duplicates for TF counting.
 func (s *BM25Scorer) tokenize(text string) []string {
-	text = strings.ToLower(text)
-	var tokens []string
-	var current strings.Builder
-	for _, r := range text {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			current.WriteRune(r)
-		} else if current.Len() > 0 {
-			word := current.String()
-			if len(word) > 1 {
-				tokens = append(tokens, word)
-			}
-			current.Reset()
-		}
-	}
-	if current.Len() > 0 {
-		word := current.String()
-		if len(word) > 1 {
-			tokens = append(tokens, word)
-		}
-	}
-	return tokens
+	return s.textAnalyzer.Analyze(text)
+}
+
+// analyzeQuery analyzes query terms together, so their language is detected
+// from the whole query.
+func analyzeQuery(analyzer TextAnalyzer, queryTerms []string) []string {
+	return analyzer.Analyze(strings.Join(queryTerms, " "))
+}
+
+// bm25TermScore is the contribution of one query term to a document's score.
+func bm25TermScore(freq, df, n, dl, avgDL float64) float64 {
+	// IDF: log((N - df + 0.5) / (df + 0.5) + 1)
+	idf := math.Log((n-df+0.5)/(df+0.5) + 1)
+	// TF component with length normalization
+	tfNorm := (freq * (bm25K1 + 1)) / (freq + bm25K1*(1-bm25B+bm25B*(dl/avgDL)))
+	return idf * tfNorm
 }
 
 // SortScoredDocuments sorts in-place by score descending.
@@ -8,6 +8,8 @@ import (
 
 // EmbeddingService generates vector embeddings from text content.
 // Implementations may call external APIs (Bedrock, OpenAI) or run local models.
+// Embeddings carry their provenance: the model ID and the
+// valueobjects.EmbeddingContentHash of the text they were generated from.
 type EmbeddingService interface {
 	// GenerateEmbedding produces a vector embedding for a single text input.
 	GenerateEmbedding(ctx context.Context, text string) (valueobjects.Embedding, error)
@@ -17,4 +19,7 @@ type EmbeddingService interface {
 
 	// Dimensions returns the number of dimensions produced by this service's model.
 	Dimensions() int
+
+	// Model returns the ID of the model embeddings are generated with.
+	Model() string
 }
@@ -0,0 +1,169 @@
+package services
+
+import "sort"
+
+const (
+	// fuzzyDecay scales the score of a term matched with one edit; every
+	// further edit scales it again
+	fuzzyDecay = 0.5
+	// maxFuzzyExpansions bounds the spellings a query term is expanded to
+	maxFuzzyExpansions = 3
+)
+
+// weightedTerm is an index term a query term matches and the weight its
+// BM25 score counts with
+type weightedTerm struct {
+	term   string
+	weight float64
+}
+
+// maxEdits returns how many edits a query term may be corrected by. Short
+// terms have too many neighbours to correct.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// expandQueryTerms maps each analyzed query term to the index terms it
+// matches: itself when it is in the vocabulary, otherwise the closest
+// vocabulary terms within maxEdits, so typos still find documents.
+func expandQueryTerms[V any](terms []string, vocabulary map[string]V) [][]weightedTerm {
+	expanded := make([][]weightedTerm, 0, len(terms))
+	for _, term := range terms {
+		if _, ok := vocabulary[term]; ok {
+			expanded = append(expanded, []weightedTerm{{term: term, weight: 1}})
+			continue
+		}
+		k := maxEdits(term)
+		if k == 0 {
+			continue
+		}
+
+		type candidate struct {
+			term  string
+			edits int
+		}
+		candidates := make([]candidate, 0)
+		query := []rune(term)
+		for v := range vocabulary {
+			if d := EditDistance(query, []rune(v), k); d <= k {
+				candidates = append(candidates, candidate{term: v, edits: d})
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].edits != candidates[j].edits {
+				return candidates[i].edits < candidates[j].edits
+			}
+			return candidates[i].term < candidates[j].term
+		})
+		if len(candidates) > maxFuzzyExpansions {
+			candidates = candidates[:maxFuzzyExpansions]
+		}
+
+		group := make([]weightedTerm, len(candidates))
+		for i, c := range candidates {
+			weight := 1.0
+			for e := 0; e < c.edits; e++ {
+				weight *= fuzzyDecay
+			}
+			group[i] = weightedTerm{term: c.term, weight: weight}
+		}
+		expanded = append(expanded, group)
+	}
+	return expanded
+}
+
+// EditDistance returns the Damerau-Levenshtein distance between a and b,
+// counting an insertion, deletion, substitution or swap of adjacent
+// characters as one edit. Once the distance is known to exceed limit,
+// limit+1 is returned early.
+func EditDistance(a, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	// Three rows of the dynamic programming matrix: two back, previous, current
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	prevMin := 0
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, prev2[j-2]+1)
+			}
+			curr[j] = d
+			rowMin = min(rowMin, d)
+		}
+		// A swap reaches back two rows, so both must be out of reach
+		if rowMin > limit && prevMin > limit {
+			return limit + 1
+		}
+		prevMin = rowMin
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return min(prev[len(b)], limit+1)
+}
+
+// TermMatcher reports whether words match the terms of a query after
+// analysis, exactly or within the edits fuzzy expansion allows. It marks the
+// words of a result that a keyword search matched.
+type TermMatcher struct {
+	analyzer TextAnalyzer
+	terms    map[string]bool
+	matched  map[string]bool
+}
+
+// NewTermMatcher creates a matcher for the terms of query.
+func NewTermMatcher(analyzer TextAnalyzer, query string) *TermMatcher {
+	if analyzer == nil {
+		analyzer = NewDefaultTextAnalyzer()
+	}
+	terms := make(map[string]bool)
+	for _, term := range analyzer.Analyze(query) {
+		terms[term] = true
+	}
+	return &TermMatcher{analyzer: analyzer, terms: terms, matched: make(map[string]bool)}
+}
+
+// Match reports whether word is an inflection or a close spelling of a
+// query term.
+func (m *TermMatcher) Match(word string) bool {
+	if matched, ok := m.matched[word]; ok {
+		return matched
+	}
+	matched := false
+	for _, analyzed := range m.analyzer.Analyze(word) {
+		if m.terms[analyzed] {
+			matched = true
+			break
+		}
+		for term := range m.terms {
+			if k := maxEdits(term); k > 0 && EditDistance([]rune(term), []rune(analyzed), k) <= k {
+				matched = true
+				break
+			}
+		}
+	}
+	m.matched[word] = matched
+	return matched
+}
@@ -0,0 +1,97 @@
+package services
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"neural", "neural", 2, 0},
+		{"nueral", "neural", 2, 1}, // swap
+		{"neurl", "neural", 2, 1},  // insertion
+		{"neurall", "neural", 2, 1},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 1, 2}, // stops past the limit
+		{"abc", "abcdef", 2, 3},
+		{"", "ab", 2, 2},
+	}
+	for _, tt := range tests {
+		if got := EditDistance([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+			t.Errorf("EditDistance(%q, %q, %d) = %d, expected %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestExpandQueryTerms(t *testing.T) {
+	vocabulary := map[string]int{"neural": 1, "network": 1, "natur": 1, "cat": 1}
+
+	groups := expandQueryTerms([]string{"neural", "nueral", "netwrok", "cta", "zzzz"}, vocabulary)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 matched terms, got %d: %v", len(groups), groups)
+	}
+	if g := groups[0]; len(g) != 1 || g[0].term != "neural" || g[0].weight != 1 {
+		t.Errorf("an indexed term should match only itself, got %v", g)
+	}
+	if g := groups[1]; len(g) != 1 || g[0].term != "neural" || g[0].weight != fuzzyDecay {
+		t.Errorf("expected a reduced weight match of neural, got %v", g)
+	}
+	if g := groups[2]; len(g) != 1 || g[0].term != "network" {
+		t.Errorf("expected netwrok to match network, got %v", g)
+	}
+}
+
+func TestBM25Scorer_InflectionsAndTypos(t *testing.T) {
+	scorer := NewBM25Scorer(NewDefaultTextAnalyzer())
+	docs := []DocumentRecord{
+		{ID: "running", Text: "Notes on running a marathon"},
+		{ID: "cooking", Text: "Cooking recipes for the weekend"},
+	}
+
+	for _, query := range [][]string{{"runs"}, {"runing"}, {"marathno"}} {
+		results := scorer.Score(query, docs)
+		if len(results) != 1 || results[0].ID != "running" {
+			t.Errorf("query %v: expected only the running note, got %v", query, results)
+		}
+	}
+
+	exact := scorer.Score([]string{"marathon"}, docs)
+	typo := scorer.Score([]string{"marathno"}, docs)
+	if typo[0].Score >= exact[0].Score {
+		t.Errorf("a typo should score below the exact term: %f >= %f", typo[0].Score, exact[0].Score)
+	}
+}
+
+func TestInvertedIndex_FuzzyMatchesBM25Scorer(t *testing.T) {
+	index := NewInvertedIndex()
+	for _, doc := range invertedIndexCorpus {
+		index.Add(doc.ID, doc.Text)
+	}
+
+	for _, query := range [][]string{
+		{"machin", "lerning"},
+		{"pastas", "pizzza"},
+		{"netwroks"},
+	} {
+		assertSameScores(t, index, invertedIndexCorpus, query)
+	}
+	if results := index.Score([]string{"lerning"}, 0); len(results) != 3 {
+		t.Errorf("expected the 3 learning documents, got %v", results)
+	}
+}
+
+func TestTermMatcher(t *testing.T) {
+	matcher := NewTermMatcher(NewDefaultTextAnalyzer(), "running netwrok the")
+
+	for _, word := range []string{"runs", "Running", "run", "networks"} {
+		if !matcher.Match(word) {
+			t.Errorf("expected %q to match", word)
+		}
+	}
+	for _, word := range []string{"the", "rung", "walking"} {
+		if matcher.Match(word) {
+			t.Errorf("expected %q not to match", word)
+		}
+	}
+}
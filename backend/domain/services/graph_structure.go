@@ -0,0 +1,335 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+// eigenvectorIterations and eigenvectorTolerance bound the power iteration
+// of eigenvector centrality
+const (
+	eigenvectorIterations = 100
+	eigenvectorTolerance  = 1e-9
+)
+
+// GraphAnalytics holds the centrality and structure measures of a graph.
+// Edges are treated as undirected throughout.
+type GraphAnalytics struct {
+	// Betweenness is the share of shortest paths through each node,
+	// relative to the most central node
+	Betweenness map[valueobjects.NodeID]float64
+	// PageRank is the weighted PageRank of each node; the values sum to 1
+	PageRank map[valueobjects.NodeID]float64
+	// Eigenvector is the weighted eigenvector centrality of each node,
+	// relative to the most central node
+	Eigenvector map[valueobjects.NodeID]float64
+	// CoreNumber is the largest k for which the node is in the k-core, the
+	// subgraph in which every node has at least k neighbours
+	CoreNumber map[valueobjects.NodeID]int
+	// Bridges are the links whose removal disconnects their two nodes
+	Bridges []GraphBridge
+	// ArticulationPoints are the nodes whose removal splits their component
+	ArticulationPoints []valueobjects.NodeID
+}
+
+// GraphBridge is a link between two nodes that no other path connects.
+type GraphBridge struct {
+	SourceID valueobjects.NodeID
+	TargetID valueobjects.NodeID
+}
+
+// Degeneracy returns the largest core number, the k of the graph's
+// innermost core.
+func (a *GraphAnalytics) Degeneracy() int {
+	k := 0
+	for _, core := range a.CoreNumber {
+		k = max(k, core)
+	}
+	return k
+}
+
+// Analyze computes every centrality and structure measure of the graph from
+// one snapshot of its nodes and edges.
+func (s *GraphAnalyticsService) Analyze(graph *aggregates.Graph) (*GraphAnalytics, error) {
+	betweenness, err := s.CalculateCentrality(graph)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := graph.Nodes()
+	if err != nil {
+		return nil, err
+	}
+	edges := graph.GetEdges()
+	g := newStructureGraph(nodes, edges)
+
+	coreNumbers := g.coreNumbers()
+	bridges, articulation := g.bridgesAndArticulationPoints()
+
+	analytics := &GraphAnalytics{
+		Betweenness:        betweenness,
+		PageRank:           g.pageRank(edges),
+		Eigenvector:        g.byNode(g.eigenvector()),
+		CoreNumber:         make(map[valueobjects.NodeID]int, len(g.ids)),
+		Bridges:            make([]GraphBridge, 0, len(bridges)),
+		ArticulationPoints: make([]valueobjects.NodeID, 0, len(articulation)),
+	}
+	for i, core := range coreNumbers {
+		analytics.CoreNumber[g.ids[i]] = core
+	}
+	for _, b := range bridges {
+		analytics.Bridges = append(analytics.Bridges, GraphBridge{SourceID: g.ids[b[0]], TargetID: g.ids[b[1]]})
+	}
+	for _, i := range articulation {
+		analytics.ArticulationPoints = append(analytics.ArticulationPoints, g.ids[i])
+	}
+	return analytics, nil
+}
+
+// structureGraph is an undirected graph with nodes numbered in ID order.
+// Parallel edges are merged, adding up their weights, and self loops are
+// dropped.
+type structureGraph struct {
+	ids     []valueobjects.NodeID
+	adj     [][]int
+	weights [][]float64
+}
+
+func newStructureGraph(nodes map[valueobjects.NodeID]*entities.Node, edges []*aggregates.Edge) *structureGraph {
+	g := &structureGraph{ids: make([]valueobjects.NodeID, 0, len(nodes))}
+	for id := range nodes {
+		g.ids = append(g.ids, id)
+	}
+	sort.Slice(g.ids, func(i, j int) bool { return g.ids[i].String() < g.ids[j].String() })
+	index := make(map[valueobjects.NodeID]int, len(g.ids))
+	for i, id := range g.ids {
+		index[id] = i
+	}
+
+	linked := make([]map[int]float64, len(g.ids))
+	for i := range linked {
+		linked[i] = make(map[int]float64)
+	}
+	for _, edge := range edges {
+		u, okU := index[edge.SourceID]
+		v, okV := index[edge.TargetID]
+		if !okU || !okV || u == v {
+			continue
+		}
+		weight := edge.Weight
+		if weight <= 0 {
+			// Keep the link for the structure measures without weighting it
+			weight = 0
+		}
+		linked[u][v] += weight
+		linked[v][u] += weight
+	}
+
+	g.adj = make([][]int, len(g.ids))
+	g.weights = make([][]float64, len(g.ids))
+	for u, neighbours := range linked {
+		for v := range neighbours {
+			g.adj[u] = append(g.adj[u], v)
+		}
+		sort.Ints(g.adj[u])
+		g.weights[u] = make([]float64, len(g.adj[u]))
+		for k, v := range g.adj[u] {
+			g.weights[u][k] = neighbours[v]
+		}
+	}
+	return g
+}
+
+func (g *structureGraph) byNode(values []float64) map[valueobjects.NodeID]float64 {
+	out := make(map[valueobjects.NodeID]float64, len(values))
+	for i, v := range values {
+		out[g.ids[i]] = v
+	}
+	return out
+}
+
+// pageRank is personalized PageRank that restarts at any node, which is
+// plain PageRank with restarts for nodes without edges
+func (g *structureGraph) pageRank(edges []*aggregates.Edge) map[valueobjects.NodeID]float64 {
+	ppr := NewPersonalizedPageRankService().Rank(edges, g.ids, nil)
+	out := make(map[valueobjects.NodeID]float64, len(g.ids))
+	for _, id := range g.ids {
+		out[id] = ppr.Scores[id.String()]
+	}
+	return out
+}
+
+// eigenvector runs power iteration on the weighted adjacency matrix plus the
+// identity. The shift keeps the iteration from oscillating on bipartite
+// graphs without changing the eigenvectors.
+func (g *structureGraph) eigenvector() []float64 {
+	n := len(g.ids)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1 / math.Sqrt(float64(n))
+	}
+	for iter := 0; iter < eigenvectorIterations; iter++ {
+		next := make([]float64, n)
+		for u := range next {
+			next[u] = x[u]
+			for k, v := range g.adj[u] {
+				next[u] += g.weights[u][k] * x[v]
+			}
+		}
+		norm := 0.0
+		for _, v := range next {
+			norm += v * v
+		}
+		if norm == 0 {
+			return next
+		}
+		norm = math.Sqrt(norm)
+		delta := 0.0
+		for i := range next {
+			next[i] /= norm
+			delta += math.Abs(next[i] - x[i])
+		}
+		x = next
+		if delta < eigenvectorTolerance {
+			break
+		}
+	}
+
+	// Isolated nodes only hold what the shift gave them; they are not central
+	top := 0.0
+	for u, v := range x {
+		if len(g.adj[u]) == 0 {
+			x[u] = 0
+			continue
+		}
+		top = max(top, v)
+	}
+	if top > 0 {
+		for i := range x {
+			x[i] /= top
+		}
+	}
+	return x
+}
+
+// coreNumbers peels the graph: it repeatedly removes a node of least
+// remaining degree, and each node's core number is the largest such degree
+// seen up to its removal
+func (g *structureGraph) coreNumbers() []int {
+	n := len(g.ids)
+	degree := make([]int, n)
+	maxDegree := 0
+	for u := range g.adj {
+		degree[u] = len(g.adj[u])
+		maxDegree = max(maxDegree, degree[u])
+	}
+	buckets := make([][]int, maxDegree+1)
+	for u, d := range degree {
+		buckets[d] = append(buckets[d], u)
+	}
+
+	core := make([]int, n)
+	removed := make([]bool, n)
+	k := 0
+	for d, done := 0, 0; done < n; {
+		if len(buckets[d]) == 0 {
+			d++
+			continue
+		}
+		u := buckets[d][len(buckets[d])-1]
+		buckets[d] = buckets[d][:len(buckets[d])-1]
+		// Nodes are queued again when their degree drops; skip stale entries
+		if removed[u] || degree[u] != d {
+			continue
+		}
+		removed[u] = true
+		done++
+		k = max(k, d)
+		core[u] = k
+		for _, v := range g.adj[u] {
+			if !removed[v] {
+				degree[v]--
+				buckets[degree[v]] = append(buckets[degree[v]], v)
+				d = min(d, degree[v])
+			}
+		}
+	}
+	return core
+}
+
+// bridgesAndArticulationPoints runs Tarjan's lowlink depth-first search,
+// iteratively so large graphs cannot overflow the stack. An edge to a child
+// whose subtree reaches no higher than the child is a bridge; a node with a
+// child whose subtree reaches no higher than the node is an articulation
+// point, except a search root, which is one when it has several children.
+func (g *structureGraph) bridgesAndArticulationPoints() ([][2]int, []int) {
+	n := len(g.ids)
+	discovered := make([]int, n) // 0 until visited, then the visit order from 1
+	low := make([]int, n)
+	isArticulation := make([]bool, n)
+	var bridges [][2]int
+
+	type frame struct {
+		node, parent, next int
+	}
+	order := 0
+	for root := 0; root < n; root++ {
+		if discovered[root] != 0 {
+			continue
+		}
+		order++
+		discovered[root], low[root] = order, order
+		rootChildren := 0
+		stack := []frame{{node: root, parent: -1}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			u := top.node
+			if top.next < len(g.adj[u]) {
+				v := g.adj[u][top.next]
+				top.next++
+				if v == top.parent {
+					continue
+				}
+				if discovered[v] != 0 {
+					low[u] = min(low[u], discovered[v])
+					continue
+				}
+				order++
+				discovered[v], low[v] = order, order
+				if u == root {
+					rootChildren++
+				}
+				stack = append(stack, frame{node: v, parent: u})
+				continue
+			}
+
+			// u is finished; report to its parent
+			stack = stack[:len(stack)-1]
+			if top.parent < 0 {
+				continue
+			}
+			p := top.parent
+			low[p] = min(low[p], low[u])
+			if low[u] > discovered[p] {
+				bridges = append(bridges, [2]int{p, u})
+			}
+			if p != root && low[u] >= discovered[p] {
+				isArticulation[p] = true
+			}
+		}
+		if rootChildren > 1 {
+			isArticulation[root] = true
+		}
+	}
+
+	var articulation []int
+	for u, ok := range isArticulation {
+		if ok {
+			articulation = append(articulation, u)
+		}
+	}
+	return bridges, articulation
+}
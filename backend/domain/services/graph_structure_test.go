@@ -0,0 +1,137 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+// bowtie builds two triangles a-b-c and d-e-f joined by the link c-d, with
+// g left unconnected
+func bowtie(t *testing.T) *testGraph {
+	t.Helper()
+	tg := newTestGraph(t, []string{"a", "b", "c", "d", "e", "f", "g"})
+	tg.addEdge(t, "a", "b", 1)
+	tg.addEdge(t, "b", "c", 1)
+	tg.addEdge(t, "c", "a", 1)
+	tg.addEdge(t, "c", "d", 1)
+	tg.addEdge(t, "d", "e", 1)
+	tg.addEdge(t, "e", "f", 1)
+	tg.addEdge(t, "f", "d", 1)
+	return tg
+}
+
+func TestGraphAnalytics_BridgesAndArticulationPoints(t *testing.T) {
+	tg := bowtie(t)
+	analytics, err := NewGraphAnalyticsService().Analyze(tg.graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(analytics.Bridges) != 1 {
+		t.Fatalf("expected the c-d link as the only bridge, got %d bridges", len(analytics.Bridges))
+	}
+	bridge := analytics.Bridges[0]
+	ends := map[string]bool{bridge.SourceID.String(): true, bridge.TargetID.String(): true}
+	if !ends[tg.ids["c"].String()] || !ends[tg.ids["d"].String()] {
+		t.Errorf("expected bridge c-d, got %v", bridge)
+	}
+
+	if len(analytics.ArticulationPoints) != 2 {
+		t.Fatalf("expected c and d as articulation points, got %d", len(analytics.ArticulationPoints))
+	}
+	for _, id := range analytics.ArticulationPoints {
+		if id != tg.ids["c"] && id != tg.ids["d"] {
+			t.Errorf("unexpected articulation point %s", id)
+		}
+	}
+}
+
+func TestGraphAnalytics_LineGraphArticulationPoints(t *testing.T) {
+	// Every link of a path is a bridge and every inner node is a cut vertex
+	tg := newTestGraph(t, []string{"a", "b", "c", "d"})
+	tg.addEdge(t, "a", "b", 1)
+	tg.addEdge(t, "b", "c", 1)
+	tg.addEdge(t, "c", "d", 1)
+
+	analytics, err := NewGraphAnalyticsService().Analyze(tg.graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analytics.Bridges) != 3 {
+		t.Errorf("expected 3 bridges, got %d", len(analytics.Bridges))
+	}
+	if len(analytics.ArticulationPoints) != 2 {
+		t.Errorf("expected b and c as articulation points, got %d", len(analytics.ArticulationPoints))
+	}
+}
+
+func TestGraphAnalytics_CoreNumbers(t *testing.T) {
+	// A 4-clique a-b-c-d with a tail d-e-f
+	tg := newTestGraph(t, []string{"a", "b", "c", "d", "e", "f", "g"})
+	for _, pair := range [][2]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}, {"e", "f"}} {
+		tg.addEdge(t, pair[0], pair[1], 1)
+	}
+
+	analytics, err := NewGraphAnalyticsService().Analyze(tg.graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"a": 3, "b": 3, "c": 3, "d": 3, "e": 1, "f": 1, "g": 0}
+	for name, core := range want {
+		if got := analytics.CoreNumber[tg.ids[name]]; got != core {
+			t.Errorf("core number of %s = %d, want %d", name, got, core)
+		}
+	}
+	if analytics.Degeneracy() != 3 {
+		t.Errorf("degeneracy = %d, want 3", analytics.Degeneracy())
+	}
+}
+
+func TestGraphAnalytics_Centralities(t *testing.T) {
+	// Star: the hub outranks the leaves on every measure
+	tg := newTestGraph(t, []string{"hub", "a", "b", "c", "lonely"})
+	tg.addEdge(t, "hub", "a", 1)
+	tg.addEdge(t, "hub", "b", 1)
+	tg.addEdge(t, "c", "hub", 1)
+
+	analytics, err := NewGraphAnalyticsService().Analyze(tg.graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hub, leaf := tg.ids["hub"], tg.ids["a"]
+	if analytics.PageRank[hub] <= analytics.PageRank[leaf] {
+		t.Errorf("PageRank: hub %f should beat leaf %f", analytics.PageRank[hub], analytics.PageRank[leaf])
+	}
+	total := 0.0
+	for _, pr := range analytics.PageRank {
+		total += pr
+	}
+	if math.Abs(total-1) > 1e-4 {
+		t.Errorf("PageRank sums to %f, want 1", total)
+	}
+
+	if math.Abs(analytics.Eigenvector[hub]-1) > 1e-6 {
+		t.Errorf("eigenvector of hub = %f, want 1", analytics.Eigenvector[hub])
+	}
+	if analytics.Eigenvector[leaf] >= analytics.Eigenvector[hub] {
+		t.Errorf("eigenvector: leaf %f should trail hub", analytics.Eigenvector[leaf])
+	}
+	if analytics.Eigenvector[tg.ids["lonely"]] != 0 {
+		t.Errorf("isolated node should have no eigenvector centrality, got %f", analytics.Eigenvector[tg.ids["lonely"]])
+	}
+	if analytics.Betweenness[hub] != 1 {
+		t.Errorf("betweenness of hub = %f, want 1", analytics.Betweenness[hub])
+	}
+}
+
+func TestGraphAnalytics_EmptyGraph(t *testing.T) {
+	tg := newTestGraph(t, []string{})
+	analytics, err := NewGraphAnalyticsService().Analyze(tg.graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analytics.PageRank) != 0 || len(analytics.Bridges) != 0 || analytics.Degeneracy() != 0 {
+		t.Errorf("expected empty analytics, got %+v", analytics)
+	}
+}
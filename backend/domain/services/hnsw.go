@@ -0,0 +1,701 @@
+package services
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// HNSWConfig holds parameters for the HNSW approximate nearest-neighbour index.
+type HNSWConfig struct {
+	// M is the number of links kept per node on the upper layers (2*M on layer 0).
+	M int
+	// EfConstruction is the candidate list size used while inserting.
+	EfConstruction int
+	// EfSearch is the candidate list size used while searching: higher = better recall, slower.
+	EfSearch int
+	// Seed for deterministic level assignment (0 = random).
+	Seed int64
+}
+
+// DefaultHNSWConfig returns defaults that keep recall above 95% for embedding-sized vectors.
+func DefaultHNSWConfig() *HNSWConfig {
+	return &HNSWConfig{
+		M:              16,
+		EfConstruction: 200,
+		EfSearch:       64,
+		Seed:           0,
+	}
+}
+
+// ErrHNSWCorrupt is returned when serialized index data cannot be decoded.
+var ErrHNSWCorrupt = errors.New("hnsw: corrupt index data")
+
+const (
+	hnswMagic   = "HNSW"
+	hnswVersion = 1
+	// hnswMinTombstones is the number of removed vectors tolerated before the
+	// graph is compacted, regardless of its size.
+	hnswMinTombstones = 64
+)
+
+// HNSW is a Hierarchical Navigable Small World graph over unit-normalized
+// vectors, searched by cosine similarity. Removed vectors are tombstoned and
+// keep routing searches until enough accumulate to compact the graph.
+// It is not safe for concurrent use.
+type HNSW struct {
+	cfg       HNSWConfig
+	levelMult float64
+	rng       *rand.Rand
+
+	dims     int
+	nodes    []hnswNode
+	ids      map[string]int32
+	entry    int32
+	maxLevel int
+	deleted  int
+}
+
+type hnswNode struct {
+	id      string
+	vector  []float32
+	links   [][]int32 // links[l] holds the neighbours on layer l
+	deleted bool
+}
+
+type hnswCandidate struct {
+	idx  int32
+	dist float32
+}
+
+// NewHNSW creates an empty index. Zero config values fall back to the defaults.
+func NewHNSW(cfg *HNSWConfig) *HNSW {
+	defaults := DefaultHNSWConfig()
+	if cfg == nil {
+		cfg = defaults
+	}
+
+	c := *cfg
+	if c.M < 2 {
+		c.M = defaults.M
+	}
+	if c.EfConstruction <= 0 {
+		c.EfConstruction = defaults.EfConstruction
+	}
+	if c.EfConstruction < c.M {
+		c.EfConstruction = c.M
+	}
+	if c.EfSearch <= 0 {
+		c.EfSearch = defaults.EfSearch
+	}
+
+	rng := rand.New(rand.NewSource(c.Seed))
+	if c.Seed == 0 {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+
+	h := &HNSW{cfg: c, rng: rng}
+	h.setM(c.M)
+	h.reset()
+	return h
+}
+
+// Len returns the number of live vectors.
+func (h *HNSW) Len() int {
+	return len(h.ids)
+}
+
+// Dimensions returns the dimensionality of the indexed vectors, 0 when empty.
+func (h *HNSW) Dimensions() int {
+	return h.dims
+}
+
+// Contains reports whether a live vector is stored under id.
+func (h *HNSW) Contains(id string) bool {
+	_, ok := h.ids[id]
+	return ok
+}
+
+// IDs returns the IDs of all live vectors in no particular order.
+func (h *HNSW) IDs() []string {
+	ids := make([]string, 0, len(h.ids))
+	for id := range h.ids {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Matches reports whether id is stored with exactly this vector, so callers can
+// skip re-inserting unchanged embeddings.
+func (h *HNSW) Matches(id string, vector []float64) bool {
+	idx, ok := h.ids[id]
+	if !ok {
+		return false
+	}
+	v, err := normalizeVector(vector)
+	if err != nil {
+		return false
+	}
+	return equalVectors(h.nodes[idx].vector, v)
+}
+
+// Add inserts or replaces the vector stored under id. Vectors must share the
+// dimensionality of the index unless the index holds nothing else.
+func (h *HNSW) Add(id string, vector []float64) error {
+	if id == "" {
+		return errors.New("hnsw: empty id")
+	}
+	if len(id) > math.MaxUint16 {
+		return errors.New("hnsw: id too long")
+	}
+	v, err := normalizeVector(vector)
+	if err != nil {
+		return err
+	}
+
+	idx, exists := h.ids[id]
+	if h.dims != 0 && len(v) != h.dims {
+		others := h.Len()
+		if exists {
+			others--
+		}
+		if others > 0 {
+			return fmt.Errorf("hnsw: vector has %d dimensions, index expects %d", len(v), h.dims)
+		}
+		// Nothing else is indexed, so the embedding model changed: start over
+		h.reset()
+		exists = false
+	}
+
+	if exists {
+		if equalVectors(h.nodes[idx].vector, v) {
+			return nil
+		}
+		h.tombstone(idx)
+	}
+
+	h.dims = len(v)
+	h.insert(id, v)
+	h.maybeCompact()
+	return nil
+}
+
+// Remove deletes the vector stored under id. Returns false if it was not indexed.
+func (h *HNSW) Remove(id string) bool {
+	idx, ok := h.ids[id]
+	if !ok {
+		return false
+	}
+	h.tombstone(idx)
+	if h.Len() == 0 {
+		h.reset()
+		return true
+	}
+	h.maybeCompact()
+	return true
+}
+
+// Search returns up to k live vectors closest to query, sorted by cosine
+// similarity descending.
+func (h *HNSW) Search(query []float64, k int) ([]ScoredDocument, error) {
+	if k <= 0 || h.Len() == 0 {
+		return nil, nil
+	}
+	q, err := normalizeVector(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(q) != h.dims {
+		return nil, fmt.Errorf("hnsw: query has %d dimensions, index expects %d", len(q), h.dims)
+	}
+
+	ep := h.entry
+	epDist := h.distance(q, ep)
+	for l := h.maxLevel; l > 0; l-- {
+		ep, epDist = h.greedyClosest(q, ep, epDist, l)
+	}
+
+	ef := h.cfg.EfSearch
+	if ef < k {
+		ef = k
+	}
+	// Tombstones occupy candidate slots, so widen the beam to make up for them
+	if h.deleted > 0 {
+		ef += minInt(h.deleted, ef)
+	}
+
+	candidates := h.searchLayer(q, []hnswCandidate{{idx: ep, dist: epDist}}, ef, 0)
+	results := make([]ScoredDocument, 0, minInt(k, len(candidates)))
+	for _, c := range candidates {
+		node := &h.nodes[c.idx]
+		if node.deleted {
+			continue
+		}
+		results = append(results, ScoredDocument{ID: node.id, Score: 1 - float64(c.dist)})
+		if len(results) == k {
+			break
+		}
+	}
+	return results, nil
+}
+
+// MarshalBinary encodes the index, including its graph links, so it can be
+// restored without re-inserting every vector.
+func (h *HNSW) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(hnswMagic)
+	buf.WriteByte(hnswVersion)
+
+	header := []uint32{
+		uint32(h.cfg.M),
+		uint32(h.cfg.EfConstruction),
+		uint32(h.dims),
+		uint32(int32(h.maxLevel)),
+		uint32(h.entry),
+		uint32(len(h.nodes)),
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+
+	for i := range h.nodes {
+		node := &h.nodes[i]
+		if err := binary.Write(&buf, binary.LittleEndian, uint16(len(node.id))); err != nil {
+			return nil, err
+		}
+		buf.WriteString(node.id)
+		if node.deleted {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		buf.WriteByte(byte(len(node.links)))
+		if err := binary.Write(&buf, binary.LittleEndian, node.vector); err != nil {
+			return nil, err
+		}
+		for _, links := range node.links {
+			if err := binary.Write(&buf, binary.LittleEndian, uint16(len(links))); err != nil {
+				return nil, err
+			}
+			if err := binary.Write(&buf, binary.LittleEndian, links); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the index contents with data produced by
+// MarshalBinary. The graph keeps the M it was built with; EfSearch and the
+// random source stay as configured.
+func (h *HNSW) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	magic := make([]byte, len(hnswMagic))
+	if _, err := r.Read(magic); err != nil || string(magic) != hnswMagic {
+		return ErrHNSWCorrupt
+	}
+	version, err := r.ReadByte()
+	if err != nil || version != hnswVersion {
+		return fmt.Errorf("%w: unsupported version", ErrHNSWCorrupt)
+	}
+
+	header := make([]uint32, 6)
+	if err := binary.Read(r, binary.LittleEndian, header); err != nil {
+		return ErrHNSWCorrupt
+	}
+	m, efConstruction, dims := int(header[0]), int(header[1]), int(header[2])
+	maxLevel, entry, count := int(int32(header[3])), int32(header[4]), int(header[5])
+	if m < 2 || count > r.Len() || (count > 0 && (entry < 0 || int(entry) >= count)) {
+		return ErrHNSWCorrupt
+	}
+
+	nodes := make([]hnswNode, count)
+	ids := make(map[string]int32, count)
+	deleted := 0
+	for i := range nodes {
+		var idLen uint16
+		if err := binary.Read(r, binary.LittleEndian, &idLen); err != nil {
+			return ErrHNSWCorrupt
+		}
+		id := make([]byte, idLen)
+		if _, err := r.Read(id); err != nil && idLen > 0 {
+			return ErrHNSWCorrupt
+		}
+		flag, err := r.ReadByte()
+		if err != nil {
+			return ErrHNSWCorrupt
+		}
+		levels, err := r.ReadByte()
+		if err != nil || levels == 0 || dims*4 > r.Len() {
+			return ErrHNSWCorrupt
+		}
+
+		node := hnswNode{id: string(id), deleted: flag == 1, vector: make([]float32, dims), links: make([][]int32, levels)}
+		if err := binary.Read(r, binary.LittleEndian, node.vector); err != nil {
+			return ErrHNSWCorrupt
+		}
+		for l := range node.links {
+			var n uint16
+			if err := binary.Read(r, binary.LittleEndian, &n); err != nil || int(n)*4 > r.Len() {
+				return ErrHNSWCorrupt
+			}
+			links := make([]int32, n)
+			if err := binary.Read(r, binary.LittleEndian, links); err != nil {
+				return ErrHNSWCorrupt
+			}
+			for _, link := range links {
+				if link < 0 || int(link) >= count {
+					return ErrHNSWCorrupt
+				}
+			}
+			node.links[l] = links
+		}
+
+		if node.deleted {
+			deleted++
+		} else {
+			ids[node.id] = int32(i)
+		}
+		nodes[i] = node
+	}
+	if r.Len() != 0 {
+		return ErrHNSWCorrupt
+	}
+	// Links may only point at nodes that exist on the same layer
+	for i := range nodes {
+		for l, links := range nodes[i].links {
+			for _, link := range links {
+				if len(nodes[link].links) <= l {
+					return ErrHNSWCorrupt
+				}
+			}
+		}
+	}
+	if count > 0 && len(nodes[entry].links) != maxLevel+1 {
+		return ErrHNSWCorrupt
+	}
+
+	h.cfg.EfConstruction = efConstruction
+	h.setM(m)
+	h.dims = dims
+	h.nodes = nodes
+	h.ids = ids
+	h.deleted = deleted
+	h.entry = -1
+	h.maxLevel = -1
+	if count > 0 {
+		h.entry = entry
+		h.maxLevel = maxLevel
+	}
+	return nil
+}
+
+func (h *HNSW) setM(m int) {
+	h.cfg.M = m
+	h.levelMult = 1 / math.Log(float64(m))
+}
+
+func (h *HNSW) reset() {
+	h.dims = 0
+	h.nodes = nil
+	h.ids = make(map[string]int32)
+	h.entry = -1
+	h.maxLevel = -1
+	h.deleted = 0
+}
+
+func (h *HNSW) tombstone(idx int32) {
+	node := &h.nodes[idx]
+	node.deleted = true
+	delete(h.ids, node.id)
+	h.deleted++
+}
+
+// maybeCompact rebuilds the graph from the live vectors once tombstones make
+// up a large share of it.
+func (h *HNSW) maybeCompact() {
+	threshold := h.Len() / 4
+	if threshold < hnswMinTombstones {
+		threshold = hnswMinTombstones
+	}
+	if h.deleted <= threshold {
+		return
+	}
+
+	live := make([]hnswNode, 0, h.Len())
+	for _, node := range h.nodes {
+		if !node.deleted {
+			live = append(live, node)
+		}
+	}
+	dims := h.dims
+	h.reset()
+	h.dims = dims
+	for _, node := range live {
+		h.insert(node.id, node.vector)
+	}
+}
+
+func (h *HNSW) randomLevel() int {
+	level := int(-math.Log(1-h.rng.Float64()) * h.levelMult)
+	if level > math.MaxUint8-1 {
+		level = math.MaxUint8 - 1
+	}
+	return level
+}
+
+func (h *HNSW) maxLinks(level int) int {
+	if level == 0 {
+		return 2 * h.cfg.M
+	}
+	return h.cfg.M
+}
+
+func (h *HNSW) insert(id string, v []float32) {
+	level := h.randomLevel()
+	idx := int32(len(h.nodes))
+	h.nodes = append(h.nodes, hnswNode{id: id, vector: v, links: make([][]int32, level+1)})
+	h.ids[id] = idx
+
+	if h.entry < 0 {
+		h.entry = idx
+		h.maxLevel = level
+		return
+	}
+
+	ep := h.entry
+	epDist := h.distance(v, ep)
+	for l := h.maxLevel; l > level; l-- {
+		ep, epDist = h.greedyClosest(v, ep, epDist, l)
+	}
+
+	entries := []hnswCandidate{{idx: ep, dist: epDist}}
+	for l := minInt(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLayer(v, entries, h.cfg.EfConstruction, l)
+		neighbours := h.selectNeighbours(candidates, h.cfg.M)
+
+		links := make([]int32, len(neighbours))
+		for i, n := range neighbours {
+			links[i] = n.idx
+		}
+		h.nodes[idx].links[l] = links
+		for _, n := range neighbours {
+			h.connect(n.idx, idx, l)
+		}
+		entries = candidates
+	}
+
+	if level > h.maxLevel {
+		h.maxLevel = level
+		h.entry = idx
+	}
+}
+
+// connect adds a link from -> to on a layer, pruning the neighbour list with
+// the selection heuristic when it overflows.
+func (h *HNSW) connect(from, to int32, level int) {
+	node := &h.nodes[from]
+	node.links[level] = append(node.links[level], to)
+	limit := h.maxLinks(level)
+	if len(node.links[level]) <= limit {
+		return
+	}
+
+	candidates := make([]hnswCandidate, len(node.links[level]))
+	for i, n := range node.links[level] {
+		candidates[i] = hnswCandidate{idx: n, dist: h.distance(node.vector, n)}
+	}
+	sortCandidates(candidates)
+
+	selected := h.selectNeighbours(candidates, limit)
+	links := make([]int32, len(selected))
+	for i, n := range selected {
+		links[i] = n.idx
+	}
+	node.links[level] = links
+}
+
+// selectNeighbours picks up to m candidates (sorted by distance ascending),
+// preferring ones that are closer to the base than to any already selected
+// neighbour so links spread out in different directions. Pruned candidates
+// fill any remaining slots.
+func (h *HNSW) selectNeighbours(candidates []hnswCandidate, m int) []hnswCandidate {
+	if len(candidates) <= m {
+		return candidates
+	}
+
+	selected := make([]hnswCandidate, 0, m)
+	pruned := make([]hnswCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if len(selected) >= m {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if h.distance(h.nodes[c.idx].vector, s.idx) < c.dist {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, c)
+		} else {
+			pruned = append(pruned, c)
+		}
+	}
+	for _, c := range pruned {
+		if len(selected) >= m {
+			break
+		}
+		selected = append(selected, c)
+	}
+	return selected
+}
+
+// greedyClosest walks a layer towards q until no neighbour is closer.
+func (h *HNSW) greedyClosest(q []float32, ep int32, epDist float32, level int) (int32, float32) {
+	for changed := true; changed; {
+		changed = false
+		for _, n := range h.nodes[ep].links[level] {
+			if d := h.distance(q, n); d < epDist {
+				ep, epDist, changed = n, d, true
+			}
+		}
+	}
+	return ep, epDist
+}
+
+// searchLayer runs a beam search of width ef on one layer and returns the
+// closest nodes found, sorted by distance ascending.
+func (h *HNSW) searchLayer(q []float32, entries []hnswCandidate, ef, level int) []hnswCandidate {
+	visited := make(map[int32]struct{}, ef*4)
+	candidates := &candidateMinHeap{}
+	results := &candidateMaxHeap{}
+
+	for _, e := range entries {
+		if _, seen := visited[e.idx]; seen {
+			continue
+		}
+		visited[e.idx] = struct{}{}
+		heap.Push(candidates, e)
+		heap.Push(results, e)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && c.dist > (*results)[0].dist {
+			break
+		}
+		for _, n := range h.nodes[c.idx].links[level] {
+			if _, seen := visited[n]; seen {
+				continue
+			}
+			visited[n] = struct{}{}
+
+			d := h.distance(q, n)
+			if results.Len() < ef || d < (*results)[0].dist {
+				heap.Push(candidates, hnswCandidate{idx: n, dist: d})
+				heap.Push(results, hnswCandidate{idx: n, dist: d})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make([]hnswCandidate, results.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(results).(hnswCandidate)
+	}
+	return out
+}
+
+// distance is the cosine distance between q and a stored vector. Both are unit length.
+func (h *HNSW) distance(q []float32, idx int32) float32 {
+	v := h.nodes[idx].vector
+	var dot float32
+	for i := range q {
+		dot += q[i] * v[i]
+	}
+	return 1 - dot
+}
+
+func normalizeVector(vector []float64) ([]float32, error) {
+	if len(vector) == 0 {
+		return nil, errors.New("hnsw: empty vector")
+	}
+	var norm float64
+	for _, x := range vector {
+		norm += x * x
+	}
+	if norm == 0 || math.IsNaN(norm) || math.IsInf(norm, 0) {
+		return nil, errors.New("hnsw: vector must have a finite, non-zero magnitude")
+	}
+	norm = math.Sqrt(norm)
+
+	v := make([]float32, len(vector))
+	for i, x := range vector {
+		v[i] = float32(x / norm)
+	}
+	return v, nil
+}
+
+func equalVectors(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortCandidates(candidates []hnswCandidate) {
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && candidates[j].dist < candidates[j-1].dist; j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+type candidateMinHeap []hnswCandidate
+
+func (h candidateMinHeap) Len() int            { return len(h) }
+func (h candidateMinHeap) Less(i, j int) bool  { return h[i].dist < h[j].dist }
+func (h candidateMinHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateMinHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *candidateMinHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+type candidateMaxHeap []hnswCandidate
+
+func (h candidateMaxHeap) Len() int            { return len(h) }
+func (h candidateMaxHeap) Less(i, j int) bool  { return h[i].dist > h[j].dist }
+func (h candidateMaxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateMaxHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *candidateMaxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
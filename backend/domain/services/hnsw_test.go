@@ -0,0 +1,229 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func randomVectors(n, dims int, seed int64) [][]float64 {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([][]float64, n)
+	for i := range vectors {
+		v := make([]float64, dims)
+		for j := range v {
+			v[j] = rng.NormFloat64()
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+func bruteForceTopK(vectors [][]float64, query []float64, k int) []ScoredDocument {
+	q, _ := normalizeVector(query)
+	docs := make([]ScoredDocument, 0, len(vectors))
+	for i, vector := range vectors {
+		v, _ := normalizeVector(vector)
+		var dot float64
+		for j := range q {
+			dot += float64(q[j]) * float64(v[j])
+		}
+		docs = append(docs, ScoredDocument{ID: fmt.Sprintf("n%d", i), Score: dot})
+	}
+	SortScoredDocuments(docs)
+	if len(docs) > k {
+		docs = docs[:k]
+	}
+	return docs
+}
+
+func buildIndex(t *testing.T, vectors [][]float64) *HNSW {
+	t.Helper()
+	index := NewHNSW(&HNSWConfig{M: 12, EfConstruction: 100, EfSearch: 64, Seed: 7})
+	for i, v := range vectors {
+		if err := index.Add(fmt.Sprintf("n%d", i), v); err != nil {
+			t.Fatalf("add n%d: %v", i, err)
+		}
+	}
+	return index
+}
+
+func TestHNSW_Empty(t *testing.T) {
+	index := NewHNSW(nil)
+	results, err := index.Search([]float64{1, 0}, 5)
+	if err != nil || results != nil {
+		t.Errorf("expected no results from an empty index, got %v, %v", results, err)
+	}
+	if index.Remove("missing") {
+		t.Error("expected Remove on an empty index to return false")
+	}
+}
+
+func TestHNSW_RecallAgainstBruteForce(t *testing.T) {
+	vectors := randomVectors(2000, 32, 1)
+	index := buildIndex(t, vectors)
+	if index.Len() != len(vectors) {
+		t.Fatalf("expected %d vectors, got %d", len(vectors), index.Len())
+	}
+
+	const k = 10
+	queries := randomVectors(50, 32, 2)
+	hits := 0
+	for _, q := range queries {
+		exact := make(map[string]bool, k)
+		for _, doc := range bruteForceTopK(vectors, q, k) {
+			exact[doc.ID] = true
+		}
+
+		results, err := index.Search(q, k)
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+		if len(results) != k {
+			t.Fatalf("expected %d results, got %d", k, len(results))
+		}
+		for i, r := range results {
+			if exact[r.ID] {
+				hits++
+			}
+			if i > 0 && r.Score > results[i-1].Score {
+				t.Errorf("results not sorted by score at %d", i)
+			}
+		}
+	}
+
+	recall := float64(hits) / float64(len(queries)*k)
+	if recall < 0.9 {
+		t.Errorf("expected recall@%d >= 0.9, got %.3f", k, recall)
+	}
+}
+
+func TestHNSW_FindsExactMatch(t *testing.T) {
+	vectors := randomVectors(300, 16, 3)
+	index := buildIndex(t, vectors)
+
+	results, err := index.Search(vectors[42], 1)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "n42" {
+		t.Fatalf("expected n42 as nearest neighbour, got %v", results)
+	}
+	if results[0].Score < 0.9999 {
+		t.Errorf("expected similarity ~1 for identical vectors, got %f", results[0].Score)
+	}
+}
+
+func TestHNSW_RemoveAndReplace(t *testing.T) {
+	vectors := randomVectors(300, 16, 4)
+	index := buildIndex(t, vectors)
+
+	if !index.Remove("n42") {
+		t.Fatal("expected n42 to be removed")
+	}
+	if index.Contains("n42") || index.Len() != 299 {
+		t.Fatalf("expected n42 gone and 299 vectors left, got %d", index.Len())
+	}
+	results, _ := index.Search(vectors[42], 10)
+	for _, r := range results {
+		if r.ID == "n42" {
+			t.Fatal("removed vector returned by search")
+		}
+	}
+
+	// Replacing a vector moves it to its new neighbourhood
+	if err := index.Add("n7", vectors[99]); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if !index.Matches("n7", vectors[99]) || index.Matches("n7", vectors[7]) {
+		t.Error("expected n7 to hold the replacement vector")
+	}
+	results, _ = index.Search(vectors[99], 2)
+	ids := map[string]bool{}
+	for _, r := range results {
+		ids[r.ID] = true
+	}
+	if !ids["n7"] || !ids["n99"] {
+		t.Errorf("expected n7 and n99 as the closest pair, got %v", results)
+	}
+}
+
+func TestHNSW_CompactsTombstones(t *testing.T) {
+	vectors := randomVectors(400, 16, 5)
+	index := buildIndex(t, vectors)
+
+	for i := 0; i < 300; i++ {
+		index.Remove(fmt.Sprintf("n%d", i))
+	}
+	if index.Len() != 100 {
+		t.Fatalf("expected 100 live vectors, got %d", index.Len())
+	}
+	if index.deleted > hnswMinTombstones {
+		t.Errorf("expected tombstones to be compacted, %d remain", index.deleted)
+	}
+
+	results, err := index.Search(vectors[350], 1)
+	if err != nil || len(results) != 1 || results[0].ID != "n350" {
+		t.Errorf("expected n350 after compaction, got %v, %v", results, err)
+	}
+}
+
+func TestHNSW_DimensionMismatch(t *testing.T) {
+	index := NewHNSW(nil)
+	if err := index.Add("a", []float64{1, 0, 0}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := index.Add("b", []float64{1, 0}); err == nil {
+		t.Error("expected an error for a vector with different dimensions")
+	}
+	if _, err := index.Search([]float64{1, 0}, 1); err == nil {
+		t.Error("expected an error for a query with different dimensions")
+	}
+	if err := index.Add("c", []float64{0, 0, 0}); err == nil {
+		t.Error("expected an error for a zero vector")
+	}
+
+	// With nothing else indexed, a new model's dimensions replace the old ones
+	if err := index.Add("a", []float64{0, 1}); err != nil {
+		t.Fatalf("expected re-dimensioning the only vector to succeed: %v", err)
+	}
+	if index.Dimensions() != 2 {
+		t.Errorf("expected 2 dimensions, got %d", index.Dimensions())
+	}
+}
+
+func TestHNSW_MarshalRoundTrip(t *testing.T) {
+	vectors := randomVectors(500, 24, 6)
+	index := buildIndex(t, vectors)
+	for i := 0; i < 20; i++ {
+		index.Remove(fmt.Sprintf("n%d", i))
+	}
+
+	data, err := index.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	restored := NewHNSW(&HNSWConfig{EfSearch: 64})
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if restored.Len() != index.Len() || restored.Dimensions() != index.Dimensions() {
+		t.Fatalf("expected %d vectors of %d dims, got %d of %d",
+			index.Len(), index.Dimensions(), restored.Len(), restored.Dimensions())
+	}
+
+	for _, q := range randomVectors(10, 24, 7) {
+		want, _ := index.Search(q, 5)
+		got, _ := restored.Search(q, 5)
+		if fmt.Sprint(want) != fmt.Sprint(got) {
+			t.Errorf("restored index returned %v, want %v", got, want)
+		}
+	}
+
+	if err := restored.UnmarshalBinary(data[:len(data)/2]); err == nil {
+		t.Error("expected truncated data to be rejected")
+	}
+	if err := restored.UnmarshalBinary([]byte("nope")); err == nil {
+		t.Error("expected garbage to be rejected")
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// ErrInvertedIndexCorrupt is returned when serialized index data cannot be decoded.
+var ErrInvertedIndexCorrupt = errors.New("inverted index: corrupt index data")
+
+// invertedIndexVersion changes whenever the analysis of text does, so older
+// indexes are rebuilt
+const invertedIndexVersion = 2
+
+// InvertedIndex holds BM25 term postings, document lengths and corpus
+// statistics for a set of documents, so a query only visits the postings of
+// its own terms. Scores are identical to BM25Scorer over the same documents
+// and analyzer. It is not safe for concurrent use.
+type InvertedIndex struct {
+	analyzer    TextAnalyzer
+	postings    map[string]map[string]int // term -> document ID -> term frequency
+	docs        map[string]*indexedDocument
+	totalLength int
+}
+
+// indexedDocument is what the index remembers about one document
+type indexedDocument struct {
+	length      int
+	terms       []string // distinct terms, to drop the document's postings
+	fingerprint uint64   // hash of the indexed text, to skip unchanged documents
+}
+
+// NewInvertedIndex creates an empty index that analyzes text with the
+// default text analyzer.
+func NewInvertedIndex() *InvertedIndex {
+	return NewInvertedIndexWithAnalyzer(nil)
+}
+
+// NewInvertedIndexWithAnalyzer creates an empty index that analyzes text with
+// analyzer.
+func NewInvertedIndexWithAnalyzer(analyzer TextAnalyzer) *InvertedIndex {
+	if analyzer == nil {
+		analyzer = NewDefaultTextAnalyzer()
+	}
+	return &InvertedIndex{
+		analyzer: analyzer,
+		postings: make(map[string]map[string]int),
+		docs:     make(map[string]*indexedDocument),
+	}
+}
+
+// Len returns the number of indexed documents.
+func (x *InvertedIndex) Len() int {
+	return len(x.docs)
+}
+
+// Terms returns the number of distinct terms.
+func (x *InvertedIndex) Terms() int {
+	return len(x.postings)
+}
+
+// AverageLength returns the mean document length in tokens.
+func (x *InvertedIndex) AverageLength() float64 {
+	if len(x.docs) == 0 {
+		return 0
+	}
+	return float64(x.totalLength) / float64(len(x.docs))
+}
+
+// DocumentFrequency returns the number of documents containing the analyzed term.
+func (x *InvertedIndex) DocumentFrequency(term string) int {
+	return len(x.postings[term])
+}
+
+// IDs returns the IDs of all indexed documents in no particular order.
+func (x *InvertedIndex) IDs() []string {
+	ids := make([]string, 0, len(x.docs))
+	for id := range x.docs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Matches reports whether id is indexed with exactly this text.
+func (x *InvertedIndex) Matches(id, text string) bool {
+	doc, ok := x.docs[id]
+	return ok && doc.fingerprint == fingerprintText(text)
+}
+
+// Add indexes a document, replacing an earlier version of it. Reports whether
+// the index changed.
+func (x *InvertedIndex) Add(id, text string) bool {
+	fingerprint := fingerprintText(text)
+	if doc, ok := x.docs[id]; ok {
+		if doc.fingerprint == fingerprint {
+			return false
+		}
+		x.Remove(id)
+	}
+
+	tokens := x.analyzer.Analyze(text)
+	tf := make(map[string]int)
+	for _, token := range tokens {
+		tf[token]++
+	}
+	x.insert(id, &indexedDocument{length: len(tokens), fingerprint: fingerprint}, tf)
+	return true
+}
+
+// Remove drops a document from the index. Returns false if it was not indexed.
+func (x *InvertedIndex) Remove(id string) bool {
+	doc, ok := x.docs[id]
+	if !ok {
+		return false
+	}
+	for _, term := range doc.terms {
+		postings := x.postings[term]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	x.totalLength -= doc.length
+	delete(x.docs, id)
+	return true
+}
+
+// Score returns the documents matching any query term with their BM25 scores,
+// sorted by score descending. A limit above zero keeps only the best ones.
+// Query terms are analyzed like documents; a term that is not indexed matches
+// its closest indexed spellings at a reduced weight.
+func (x *InvertedIndex) Score(queryTerms []string, limit int) []ScoredDocument {
+	if len(queryTerms) == 0 || len(x.docs) == 0 {
+		return nil
+	}
+	analyzedQuery := analyzeQuery(x.analyzer, queryTerms)
+	if len(analyzedQuery) == 0 {
+		return nil
+	}
+
+	n := float64(len(x.docs))
+	avgDL := x.AverageLength()
+	scores := make(map[string]float64)
+	for _, group := range expandQueryTerms(analyzedQuery, x.postings) {
+		// A query term counts once per document, with its best matching spelling
+		best := make(map[string]float64)
+		for _, wt := range group {
+			postings := x.postings[wt.term]
+			df := float64(len(postings))
+			for id, freq := range postings {
+				dl := float64(x.docs[id].length)
+				best[id] = math.Max(best[id], wt.weight*bm25TermScore(float64(freq), df, n, dl, avgDL))
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+		}
+	}
+
+	results := make([]ScoredDocument, 0, len(scores))
+	for id, score := range scores {
+		if score > 0 {
+			results = append(results, ScoredDocument{ID: id, Score: score})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// storedInvertedIndex is the serialized form: the per-document term
+// frequencies, from which the postings are rebuilt without re-tokenizing.
+type storedInvertedIndex struct {
+	Version   int
+	Documents []storedIndexedDocument
+}
+
+type storedIndexedDocument struct {
+	ID          string
+	Fingerprint uint64
+	Length      int
+	Terms       []string
+	Frequencies []int
+}
+
+// MarshalBinary encodes the index.
+func (x *InvertedIndex) MarshalBinary() ([]byte, error) {
+	stored := storedInvertedIndex{
+		Version:   invertedIndexVersion,
+		Documents: make([]storedIndexedDocument, 0, len(x.docs)),
+	}
+	for id, doc := range x.docs {
+		frequencies := make([]int, len(doc.terms))
+		for i, term := range doc.terms {
+			frequencies[i] = x.postings[term][id]
+		}
+		stored.Documents = append(stored.Documents, storedIndexedDocument{
+			ID:          id,
+			Fingerprint: doc.fingerprint,
+			Length:      doc.length,
+			Terms:       doc.terms,
+			Frequencies: frequencies,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(stored); err != nil {
+		return nil, fmt.Errorf("failed to encode inverted index: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the index contents with data produced by MarshalBinary.
+func (x *InvertedIndex) UnmarshalBinary(data []byte) error {
+	var stored storedInvertedIndex
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&stored); err != nil {
+		return ErrInvertedIndexCorrupt
+	}
+	if stored.Version != invertedIndexVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvertedIndexCorrupt, stored.Version)
+	}
+
+	restored := NewInvertedIndexWithAnalyzer(x.analyzer)
+	for _, doc := range stored.Documents {
+		if len(doc.Terms) != len(doc.Frequencies) {
+			return ErrInvertedIndexCorrupt
+		}
+		tf := make(map[string]int, len(doc.Terms))
+		for i, term := range doc.Terms {
+			tf[term] = doc.Frequencies[i]
+		}
+		restored.insert(doc.ID, &indexedDocument{length: doc.Length, fingerprint: doc.Fingerprint}, tf)
+	}
+	*x = *restored
+	return nil
+}
+
+// insert adds a document that is not indexed yet
+func (x *InvertedIndex) insert(id string, doc *indexedDocument, tf map[string]int) {
+	doc.terms = make([]string, 0, len(tf))
+	for term, freq := range tf {
+		postings, ok := x.postings[term]
+		if !ok {
+			postings = make(map[string]int)
+			x.postings[term] = postings
+		}
+		postings[id] = freq
+		doc.terms = append(doc.terms, term)
+	}
+	x.docs[id] = doc
+	x.totalLength += doc.length
+}
+
+func fingerprintText(text string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	return h.Sum64()
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+var invertedIndexCorpus = []DocumentRecord{
+	{ID: "1", Text: "machine learning neural networks deep learning"},
+	{ID: "2", Text: "cooking recipes for pasta and pizza"},
+	{ID: "3", Text: "machine learning in production systems"},
+	{ID: "4", Text: "deep sea diving and marine biology"},
+	{ID: "5", Text: ""},
+	{ID: "6", Text: "Learning to cook: pasta, machine-made or by hand"},
+}
+
+// assertSameScores checks that the index scores exactly like BM25Scorer
+func assertSameScores(t *testing.T, index *InvertedIndex, docs []DocumentRecord, query []string) {
+	t.Helper()
+	want := NewBM25Scorer(NewDefaultTextAnalyzer()).Score(query, docs)
+	got := index.Score(query, 0)
+	if len(got) != len(want) {
+		t.Fatalf("query %v: expected %d results, got %d", query, len(want), len(got))
+	}
+	wantScores := make(map[string]float64, len(want))
+	for _, r := range want {
+		wantScores[r.ID] = r.Score
+	}
+	for _, r := range got {
+		if math.Abs(wantScores[r.ID]-r.Score) > 1e-9 {
+			t.Errorf("query %v: doc %s scored %f, expected %f", query, r.ID, r.Score, wantScores[r.ID])
+		}
+	}
+}
+
+func TestInvertedIndex_MatchesBM25Scorer(t *testing.T) {
+	index := NewInvertedIndex()
+	for _, doc := range invertedIndexCorpus {
+		index.Add(doc.ID, doc.Text)
+	}
+
+	for _, query := range [][]string{
+		{"machine", "learning"},
+		{"pasta"},
+		{"deep", "deep", "Learning"},
+		{"nothing"},
+	} {
+		assertSameScores(t, index, invertedIndexCorpus, query)
+	}
+}
+
+func TestInvertedIndex_IncrementalUpdates(t *testing.T) {
+	index := NewInvertedIndex()
+	for _, doc := range invertedIndexCorpus {
+		index.Add(doc.ID, doc.Text)
+	}
+
+	if index.Add("2", invertedIndexCorpus[1].Text) {
+		t.Error("re-adding unchanged text should not change the index")
+	}
+	if !index.Add("2", "machine shop for pasta makers") {
+		t.Error("changed text should change the index")
+	}
+	if !index.Remove("4") {
+		t.Error("expected doc 4 to be removed")
+	}
+	if index.Remove("4") {
+		t.Error("removing twice should report no change")
+	}
+	if index.DocumentFrequency("marine") != 0 {
+		t.Error("postings of a removed document should be dropped")
+	}
+
+	docs := []DocumentRecord{
+		invertedIndexCorpus[0],
+		{ID: "2", Text: "machine shop for pasta makers"},
+		invertedIndexCorpus[2],
+		invertedIndexCorpus[4],
+		invertedIndexCorpus[5],
+	}
+	if index.Len() != len(docs) {
+		t.Fatalf("expected %d documents, got %d", len(docs), index.Len())
+	}
+	assertSameScores(t, index, docs, []string{"machine", "pasta"})
+}
+
+func TestInvertedIndex_Limit(t *testing.T) {
+	index := NewInvertedIndex()
+	for i := 0; i < 50; i++ {
+		index.Add(fmt.Sprintf("doc-%d", i), fmt.Sprintf("common term plus filler %d", i))
+	}
+
+	results := index.Score([]string{"common"}, 10)
+	if len(results) != 10 {
+		t.Fatalf("expected 10 results, got %d", len(results))
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1].Score < results[i].Score {
+			t.Errorf("results not sorted at %d", i)
+		}
+	}
+}
+
+func TestInvertedIndex_MarshalRoundtrip(t *testing.T) {
+	index := NewInvertedIndex()
+	for _, doc := range invertedIndexCorpus {
+		index.Add(doc.ID, doc.Text)
+	}
+
+	data, err := index.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	restored := NewInvertedIndex()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if restored.Len() != index.Len() || restored.Terms() != index.Terms() {
+		t.Errorf("expected %d docs and %d terms, got %d and %d",
+			index.Len(), index.Terms(), restored.Len(), restored.Terms())
+	}
+	if restored.AverageLength() != index.AverageLength() {
+		t.Errorf("expected average length %f, got %f", index.AverageLength(), restored.AverageLength())
+	}
+	if !restored.Matches("3", invertedIndexCorpus[2].Text) {
+		t.Error("fingerprints should survive a roundtrip")
+	}
+	assertSameScores(t, restored, invertedIndexCorpus, []string{"machine", "learning"})
+
+	if err := restored.UnmarshalBinary([]byte("garbage")); err == nil {
+		t.Error("expected an error for corrupt data")
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+)
+
+// HierarchyConfig holds parameters for building a community hierarchy.
+type HierarchyConfig struct {
+	// Leiden configures each run; its Resolution applies to the top level.
+	Leiden *LeidenConfig
+	// MaxDepth is the number of levels, counting the top level.
+	MaxDepth int
+	// MinSplitSize is the smallest community that is split into subtopics.
+	MinSplitSize int
+	// ResolutionStep multiplies the resolution at each level down, so deeper
+	// levels favour smaller communities.
+	ResolutionStep float64
+	// MinSplitModularity is the modularity a split must reach within its
+	// community to be kept; weaker splits leave the community as a leaf.
+	MinSplitModularity float64
+}
+
+// DefaultHierarchyConfig returns defaults for topic trees. Runs are seeded so
+// the same graph always yields the same tree.
+func DefaultHierarchyConfig() *HierarchyConfig {
+	leiden := DefaultLeidenConfig()
+	leiden.Seed = 1
+	return &HierarchyConfig{
+		Leiden:             leiden,
+		MaxDepth:           3,
+		MinSplitSize:       6,
+		ResolutionStep:     1.5,
+		MinSplitModularity: 0.1,
+	}
+}
+
+// CommunityTree is a community and the subcommunities it splits into.
+type CommunityTree struct {
+	// ID is the top-level community ID, followed by the index of each
+	// subcommunity on the way down, e.g. "3.0.2".
+	ID string
+	// Level is 0 for top-level communities.
+	Level int
+	// Members are the external IDs of the community's nodes, sorted.
+	Members []string
+	// Resolution is the resolution the community was detected at.
+	Resolution float64
+	// Modularity is the quality of the split into Children, measured within
+	// the community. Zero for leaves.
+	Modularity float64
+	// Children are the subcommunities, largest first.
+	Children []*CommunityTree
+}
+
+// BuildCommunityTree builds a topic hierarchy from a top-level partition by
+// running Leiden again inside each large community, at a resolution raised
+// by cfg.ResolutionStep per level. Pass nil for top to detect the top level
+// with RunLeiden first.
+func BuildCommunityTree(g *LeidenGraph, top map[int][]string, cfg *HierarchyConfig) []*CommunityTree {
+	if cfg == nil {
+		cfg = DefaultHierarchyConfig()
+	}
+	if cfg.Leiden == nil {
+		withDefaults := *cfg
+		withDefaults.Leiden = DefaultLeidenConfig()
+		cfg = &withDefaults
+	}
+	if top == nil {
+		top = RunLeiden(g, cfg.Leiden).Communities
+	}
+
+	ids := make([]int, 0, len(top))
+	for id := range top {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	trees := make([]*CommunityTree, 0, len(ids))
+	for _, id := range ids {
+		tree := &CommunityTree{
+			ID:         strconv.Itoa(id),
+			Members:    sortedCopy(top[id]),
+			Resolution: cfg.Leiden.Resolution,
+		}
+		splitCommunity(g, tree, cfg)
+		trees = append(trees, tree)
+	}
+	return trees
+}
+
+// splitCommunity runs Leiden on the subgraph of a community and recurses
+// into the parts, if the split is worth keeping.
+func splitCommunity(g *LeidenGraph, tree *CommunityTree, cfg *HierarchyConfig) {
+	if tree.Level+1 >= cfg.MaxDepth || len(tree.Members) < cfg.MinSplitSize {
+		return
+	}
+
+	levelCfg := *cfg.Leiden
+	levelCfg.Resolution = tree.Resolution * cfg.ResolutionStep
+	result := RunLeiden(inducedSubgraph(g, tree.Members), &levelCfg)
+	if len(result.Communities) < 2 || result.Modularity < cfg.MinSplitModularity {
+		return
+	}
+
+	parts := make([][]string, 0, len(result.Communities))
+	for _, members := range result.Communities {
+		parts = append(parts, sortedCopy(members))
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		if len(parts[i]) != len(parts[j]) {
+			return len(parts[i]) > len(parts[j])
+		}
+		return parts[i][0] < parts[j][0]
+	})
+
+	tree.Modularity = result.Modularity
+	tree.Children = make([]*CommunityTree, 0, len(parts))
+	for i, members := range parts {
+		child := &CommunityTree{
+			ID:         tree.ID + "." + strconv.Itoa(i),
+			Level:      tree.Level + 1,
+			Members:    members,
+			Resolution: levelCfg.Resolution,
+		}
+		splitCommunity(g, child, cfg)
+		tree.Children = append(tree.Children, child)
+	}
+}
+
+// inducedSubgraph returns the subgraph of g spanned by the given nodes.
+func inducedSubgraph(g *LeidenGraph, members []string) *LeidenGraph {
+	inside := make(map[int]bool, len(members))
+	for _, id := range members {
+		if i, ok := g.nodeIndex[id]; ok {
+			inside[i] = true
+		}
+	}
+
+	// Walk members in order so seeded runs see the same edge order
+	var edges []LeidenEdge
+	for _, id := range members {
+		i, ok := g.nodeIndex[id]
+		if !ok {
+			continue
+		}
+		for _, e := range g.Adj[i] {
+			// Each undirected edge is stored at both ends; take it once
+			if inside[e.Target] && i < e.Target {
+				edges = append(edges, LeidenEdge{
+					Source: g.NodeIDs[i],
+					Target: g.NodeIDs[e.Target],
+					Weight: e.Weight,
+				})
+			}
+		}
+	}
+	return NewLeidenGraph(members, edges)
+}
+
+func sortedCopy(ids []string) []string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+)
+
+// cliqueEdges connects every pair of the given nodes
+func cliqueEdges(ids ...string) []LeidenEdge {
+	var edges []LeidenEdge
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			edges = append(edges, LeidenEdge{Source: ids[i], Target: ids[j], Weight: 1.0})
+		}
+	}
+	return edges
+}
+
+// nestedClusterGraph has two themes, a and b, each made of two cliques of
+// four joined by a single edge. The themes are joined by a weak edge.
+func nestedClusterGraph() (*LeidenGraph, map[int][]string) {
+	var nodes []string
+	var edges []LeidenEdge
+	top := map[int][]string{}
+	for theme, prefix := range []string{"a", "b"} {
+		for sub := 0; sub < 2; sub++ {
+			var clique []string
+			for k := 0; k < 4; k++ {
+				clique = append(clique, fmt.Sprintf("%s%d%d", prefix, sub, k))
+			}
+			nodes = append(nodes, clique...)
+			edges = append(edges, cliqueEdges(clique...)...)
+			top[theme] = append(top[theme], clique...)
+		}
+		edges = append(edges, LeidenEdge{Source: prefix + "00", Target: prefix + "10", Weight: 1.0})
+	}
+	edges = append(edges, LeidenEdge{Source: "a00", Target: "b00", Weight: 0.1})
+	return NewLeidenGraph(nodes, edges), top
+}
+
+func TestBuildCommunityTree_SplitsThemesIntoSubtopics(t *testing.T) {
+	g, top := nestedClusterGraph()
+
+	trees := BuildCommunityTree(g, top, nil)
+
+	if len(trees) != 2 {
+		t.Fatalf("expected 2 top-level communities, got %d", len(trees))
+	}
+	for _, tree := range trees {
+		if tree.Level != 0 || len(tree.Members) != 8 {
+			t.Errorf("community %s: level %d with %d members", tree.ID, tree.Level, len(tree.Members))
+		}
+		if len(tree.Children) != 2 {
+			t.Fatalf("community %s should split into 2 subtopics, got %d", tree.ID, len(tree.Children))
+		}
+		if tree.Modularity <= 0 {
+			t.Errorf("community %s: expected positive split modularity, got %f", tree.ID, tree.Modularity)
+		}
+		for i, child := range tree.Children {
+			if want := fmt.Sprintf("%s.%d", tree.ID, i); child.ID != want {
+				t.Errorf("child ID = %s, want %s", child.ID, want)
+			}
+			if child.Level != 1 || len(child.Members) != 4 {
+				t.Errorf("subtopic %s: level %d with %d members", child.ID, child.Level, len(child.Members))
+			}
+			if child.Resolution <= tree.Resolution {
+				t.Errorf("subtopic %s should use a higher resolution", child.ID)
+			}
+			// Each subtopic is one clique
+			prefix := child.Members[0][:2]
+			for _, m := range child.Members {
+				if m[:2] != prefix {
+					t.Errorf("subtopic %s mixes cliques: %v", child.ID, child.Members)
+					break
+				}
+			}
+			if len(child.Children) != 0 {
+				t.Errorf("subtopic %s is below the split size and should be a leaf", child.ID)
+			}
+		}
+	}
+}
+
+func TestBuildCommunityTree_MaxDepth(t *testing.T) {
+	g, top := nestedClusterGraph()
+	cfg := DefaultHierarchyConfig()
+	cfg.MaxDepth = 1
+
+	trees := BuildCommunityTree(g, top, cfg)
+
+	for _, tree := range trees {
+		if len(tree.Children) != 0 {
+			t.Errorf("community %s should not be split at depth 1", tree.ID)
+		}
+	}
+}
+
+func TestBuildCommunityTree_KeepsCliqueWhole(t *testing.T) {
+	ids := []string{"c1", "c2", "c3", "c4", "c5", "c6"}
+	g := NewLeidenGraph(ids, cliqueEdges(ids...))
+
+	trees := BuildCommunityTree(g, map[int][]string{0: ids}, nil)
+
+	if len(trees) != 1 || len(trees[0].Children) != 0 {
+		t.Errorf("a clique has no subtopics, got %+v", trees)
+	}
+}
+
+func TestBuildCommunityTree_DetectsTopLevel(t *testing.T) {
+	g, _ := nestedClusterGraph()
+
+	trees := BuildCommunityTree(g, nil, nil)
+
+	members := 0
+	for _, tree := range trees {
+		members += len(tree.Members)
+	}
+	if len(trees) < 2 || members != g.N {
+		t.Errorf("expected the top level to cover all %d nodes, got %d communities with %d members", g.N, len(trees), members)
+	}
+}
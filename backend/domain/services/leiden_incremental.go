@@ -0,0 +1,247 @@
+package services
+
+import "sort"
+
+// UpdateLeiden revises a previous partition after part of the graph changed,
+// instead of detecting communities from scratch.
+//
+// Nodes keep the community they had in previous; nodes it does not know start
+// in a community of their own. Local moves then start from the new and changed
+// nodes, their neighbours and the remaining members of communities that lost
+// a node, and spread to the neighbours of every node that moves until no move
+// gains modularity. Refinement and small community merging run as in
+// RunLeiden, but community IDs are not compacted, so unaffected communities
+// keep their IDs.
+func UpdateLeiden(g *LeidenGraph, previous map[string]int, changed []string, cfg *LeidenConfig) *LeidenResult {
+	if cfg == nil {
+		cfg = DefaultLeidenConfig()
+	}
+	n := g.N
+	if n == 0 {
+		return &LeidenResult{
+			Communities:   map[int][]string{},
+			NodeCommunity: map[string]int{},
+		}
+	}
+
+	// Seed from the previous partition; new nodes get fresh IDs
+	nextID := 0
+	for _, c := range previous {
+		if c >= nextID {
+			nextID = c + 1
+		}
+	}
+	community := make([]int, n)
+	for i, id := range g.NodeIDs {
+		if c, ok := previous[id]; ok {
+			community[i] = c
+			continue
+		}
+		community[i] = nextID
+		nextID++
+	}
+
+	strength := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for _, e := range g.Adj[i] {
+			strength[i] += e.Weight
+		}
+	}
+	m2 := 2.0 * g.TotalWeight
+	if m2 == 0 {
+		return buildResult(g, community)
+	}
+
+	queue := newMoveQueue(n)
+	touch := func(i int) {
+		queue.push(i)
+		for _, e := range g.Adj[i] {
+			queue.push(e.Target)
+		}
+	}
+	for _, id := range changed {
+		if i, ok := g.nodeIndex[id]; ok {
+			touch(i)
+		}
+	}
+	for i, id := range g.NodeIDs {
+		if _, ok := previous[id]; !ok {
+			touch(i)
+		}
+	}
+	// Communities that lost members may have fallen apart or become
+	// better off joining a neighbour
+	present := make(map[int]int, len(previous))
+	for _, c := range community {
+		present[c]++
+	}
+	lost := make(map[int]bool)
+	for id, c := range previous {
+		if _, ok := g.nodeIndex[id]; !ok && present[c] > 0 {
+			lost[c] = true
+		}
+	}
+	for i, c := range community {
+		if lost[c] {
+			queue.push(i)
+		}
+	}
+
+	commStrength := communityStrengths(community, strength)
+	// Every move gains modularity, so the queue drains; the cap only guards
+	// against float noise moving nodes back and forth
+	for budget := cfg.MaxIterations * n; budget > 0; budget-- {
+		i, ok := queue.pop()
+		if !ok {
+			break
+		}
+		if !moveNode(g, community, strength, commStrength, i, m2, cfg.Resolution) {
+			continue
+		}
+		for _, e := range g.Adj[i] {
+			if community[e.Target] != community[i] {
+				queue.push(e.Target)
+			}
+		}
+	}
+
+	community = refine(g, community, strength, m2, cfg.Resolution, nil)
+	if cfg.MinCommunitySize > 1 {
+		mergeSmall(g, community, strength, m2, cfg)
+	}
+
+	return buildResult(g, community)
+}
+
+// AlignCommunities renumbers the communities of a fresh result so each takes
+// the ID of the previous community it shares the most members with, which
+// keeps IDs stable across full recomputes. Communities matching no previous
+// one get IDs above every previous ID.
+func AlignCommunities(result *LeidenResult, previous map[string]int) *LeidenResult {
+	type overlap struct {
+		next, prev, members int
+	}
+	counts := make(map[[2]int]int)
+	for id, c := range result.NodeCommunity {
+		if p, ok := previous[id]; ok {
+			counts[[2]int{c, p}]++
+		}
+	}
+	overlaps := make([]overlap, 0, len(counts))
+	for pair, members := range counts {
+		overlaps = append(overlaps, overlap{next: pair[0], prev: pair[1], members: members})
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		a, b := overlaps[i], overlaps[j]
+		if a.members != b.members {
+			return a.members > b.members
+		}
+		if a.next != b.next {
+			return a.next < b.next
+		}
+		return a.prev < b.prev
+	})
+
+	// Greedily give each previous ID to its largest overlap
+	mapping := make(map[int]int, len(result.Communities))
+	taken := make(map[int]bool)
+	for _, o := range overlaps {
+		if _, mapped := mapping[o.next]; mapped || taken[o.prev] {
+			continue
+		}
+		mapping[o.next] = o.prev
+		taken[o.prev] = true
+	}
+
+	nextID := 0
+	for _, c := range previous {
+		if c >= nextID {
+			nextID = c + 1
+		}
+	}
+	labels := make([]int, 0, len(result.Communities))
+	for c := range result.Communities {
+		labels = append(labels, c)
+	}
+	sort.Ints(labels)
+	for _, c := range labels {
+		if _, mapped := mapping[c]; !mapped {
+			mapping[c] = nextID
+			nextID++
+		}
+	}
+
+	aligned := &LeidenResult{
+		Communities:   make(map[int][]string, len(result.Communities)),
+		NodeCommunity: make(map[string]int, len(result.NodeCommunity)),
+		Modularity:    result.Modularity,
+	}
+	for c, members := range result.Communities {
+		aligned.Communities[mapping[c]] = members
+	}
+	for id, c := range result.NodeCommunity {
+		aligned.NodeCommunity[id] = mapping[c]
+	}
+	return aligned
+}
+
+// moveNode moves node i to the neighbouring community with the greatest
+// modularity gain, keeping commStrength current. Returns true if it moved.
+func moveNode(g *LeidenGraph, community []int, strength []float64, commStrength map[int]float64, i int, m2, gamma float64) bool {
+	oldComm := community[i]
+	ki := strength[i]
+
+	weights := make(map[int]float64)
+	for _, e := range g.Adj[i] {
+		weights[community[e.Target]] += e.Weight
+	}
+
+	// Gains are relative to node i sitting alone
+	stay := weights[oldComm] - gamma*ki*(commStrength[oldComm]-ki)/m2
+	bestComm, bestGain := oldComm, stay
+	for c, w := range weights {
+		if c == oldComm {
+			continue
+		}
+		gain := w - gamma*ki*commStrength[c]/m2
+		if gain > bestGain+1e-12 {
+			bestComm, bestGain = c, gain
+		}
+	}
+	if bestComm == oldComm {
+		return false
+	}
+
+	community[i] = bestComm
+	commStrength[oldComm] -= ki
+	commStrength[bestComm] += ki
+	return true
+}
+
+// moveQueue is a FIFO of node indices holding each node at most once
+type moveQueue struct {
+	items  []int
+	queued []bool
+}
+
+func newMoveQueue(n int) *moveQueue {
+	return &moveQueue{queued: make([]bool, n)}
+}
+
+func (q *moveQueue) push(i int) {
+	if q.queued[i] {
+		return
+	}
+	q.queued[i] = true
+	q.items = append(q.items, i)
+}
+
+func (q *moveQueue) pop() (int, bool) {
+	if len(q.items) == 0 {
+		return 0, false
+	}
+	i := q.items[0]
+	q.items = q.items[1:]
+	q.queued[i] = false
+	return i, true
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+)
+
+// twoClusterEdges are two triangles joined by a weak bridge
+func twoClusterEdges() []LeidenEdge {
+	return []LeidenEdge{
+		{Source: "a1", Target: "a2", Weight: 1.0},
+		{Source: "a2", Target: "a3", Weight: 1.0},
+		{Source: "a1", Target: "a3", Weight: 1.0},
+		{Source: "b1", Target: "b2", Weight: 1.0},
+		{Source: "b2", Target: "b3", Weight: 1.0},
+		{Source: "b1", Target: "b3", Weight: 1.0},
+		{Source: "a3", Target: "b1", Weight: 0.1},
+	}
+}
+
+func TestUpdateLeiden_NewNodeJoinsItsNeighbours(t *testing.T) {
+	previous := map[string]int{"a1": 3, "a2": 3, "a3": 3, "b1": 7, "b2": 7, "b3": 7}
+	edges := append(twoClusterEdges(),
+		LeidenEdge{Source: "a4", Target: "a1", Weight: 1.0},
+		LeidenEdge{Source: "a4", Target: "a2", Weight: 1.0},
+	)
+	g := NewLeidenGraph([]string{"a1", "a2", "a3", "a4", "b1", "b2", "b3"}, edges)
+
+	result := UpdateLeiden(g, previous, []string{"a4"}, nil)
+
+	if result.NodeCommunity["a4"] != 3 {
+		t.Errorf("new node should join community 3, got %d", result.NodeCommunity["a4"])
+	}
+	for _, id := range []string{"b1", "b2", "b3"} {
+		if result.NodeCommunity[id] != 7 {
+			t.Errorf("untouched node %s should keep community 7, got %d", id, result.NodeCommunity[id])
+		}
+	}
+	if result.Modularity <= 0 {
+		t.Errorf("expected positive modularity, got %f", result.Modularity)
+	}
+}
+
+func TestUpdateLeiden_WithoutPreviousPartition(t *testing.T) {
+	nodes := []string{"a1", "a2", "a3", "b1", "b2", "b3"}
+	g := NewLeidenGraph(nodes, twoClusterEdges())
+
+	result := UpdateLeiden(g, nil, nodes, nil)
+
+	if result.NodeCommunity["a1"] != result.NodeCommunity["a3"] ||
+		result.NodeCommunity["b1"] != result.NodeCommunity["b3"] {
+		t.Error("each cluster should form one community")
+	}
+	if result.NodeCommunity["a1"] == result.NodeCommunity["b1"] {
+		t.Error("clusters should be in different communities")
+	}
+}
+
+func TestUpdateLeiden_RemovedNodeSplitsCommunity(t *testing.T) {
+	// a-b-c was one community; without b, a and c are no longer connected
+	previous := map[string]int{"a": 0, "b": 0, "c": 0, "d": 1, "e": 1}
+	g := NewLeidenGraph(
+		[]string{"a", "c", "d", "e"},
+		[]LeidenEdge{
+			{Source: "a", Target: "d", Weight: 1.0},
+			{Source: "c", Target: "e", Weight: 1.0},
+		},
+	)
+	cfg := DefaultLeidenConfig()
+	cfg.MinCommunitySize = 1
+
+	result := UpdateLeiden(g, previous, nil, cfg)
+
+	if result.NodeCommunity["a"] == result.NodeCommunity["c"] {
+		t.Error("disconnected former members should not share a community")
+	}
+	if result.NodeCommunity["a"] != result.NodeCommunity["d"] {
+		t.Error("a should move to its only neighbour's community")
+	}
+}
+
+func TestUpdateLeiden_KeepsUnchangedPartition(t *testing.T) {
+	nodes := []string{"a1", "a2", "a3", "b1", "b2", "b3"}
+	g := NewLeidenGraph(nodes, twoClusterEdges())
+	cfg := DefaultLeidenConfig()
+	cfg.Seed = 42
+	full := RunLeiden(g, cfg)
+
+	// Nothing changed: the partition is kept as is
+	result := UpdateLeiden(g, full.NodeCommunity, nil, cfg)
+	for id, c := range full.NodeCommunity {
+		if result.NodeCommunity[id] != c {
+			t.Errorf("node %s moved from %d to %d", id, c, result.NodeCommunity[id])
+		}
+	}
+	if result.Modularity != full.Modularity {
+		t.Errorf("modularity = %f, want %f", result.Modularity, full.Modularity)
+	}
+}
+
+func TestAlignCommunities(t *testing.T) {
+	result := &LeidenResult{
+		Communities: map[int][]string{
+			0: {"b1", "b2", "b3"},
+			1: {"a1", "a2"},
+			2: {"c1"},
+		},
+		NodeCommunity: map[string]int{"b1": 0, "b2": 0, "b3": 0, "a1": 1, "a2": 1, "c1": 2},
+	}
+	previous := map[string]int{"a1": 4, "a2": 4, "b1": 9, "b2": 9, "b3": 4}
+
+	aligned := AlignCommunities(result, previous)
+
+	if aligned.NodeCommunity["b1"] != 9 {
+		t.Errorf("b community should keep ID 9, got %d", aligned.NodeCommunity["b1"])
+	}
+	if aligned.NodeCommunity["a1"] != 4 {
+		t.Errorf("a community should keep ID 4, got %d", aligned.NodeCommunity["a1"])
+	}
+	if aligned.NodeCommunity["c1"] != 10 {
+		t.Errorf("new community should get the next free ID 10, got %d", aligned.NodeCommunity["c1"])
+	}
+	if len(aligned.Communities[9]) != 3 {
+		t.Errorf("expected 3 members in community 9, got %v", aligned.Communities[9])
+	}
+}
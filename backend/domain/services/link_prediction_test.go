@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	"backend/domain/core/entities"
+)
+
+func (tg *testGraph) predict(communities map[string]string, cfg *LinkPredictionConfig) []PredictedLink {
+	nodes := make([]*entities.Node, 0, len(tg.nodes))
+	for _, node := range tg.nodes {
+		nodes = append(nodes, node)
+	}
+	return NewLinkPredictor(nil).Predict(nodes, tg.graph.GetEdges(), communities, cfg)
+}
+
+func (tg *testGraph) findLink(links []PredictedLink, a, b string) (PredictedLink, bool) {
+	for _, link := range links {
+		if (link.SourceID == tg.ids[a] && link.TargetID == tg.ids[b]) ||
+			(link.SourceID == tg.ids[b] && link.TargetID == tg.ids[a]) {
+			return link, true
+		}
+	}
+	return PredictedLink{}, false
+}
+
+func TestPredictLinks_ClosesSquares(t *testing.T) {
+	// a-b-c-d-a: the diagonals share two neighbours each
+	tg := newTestGraph(t, []string{"a", "b", "c", "d", "e"})
+	tg.addEdge(t, "a", "b", 1)
+	tg.addEdge(t, "b", "c", 1)
+	tg.addEdge(t, "c", "d", 1)
+	tg.addEdge(t, "d", "a", 1)
+
+	links := tg.predict(nil, nil)
+
+	if len(links) != 2 {
+		t.Fatalf("expected the two diagonals, got %d links", len(links))
+	}
+	for _, pair := range [][2]string{{"a", "c"}, {"b", "d"}} {
+		link, ok := tg.findLink(links, pair[0], pair[1])
+		if !ok {
+			t.Errorf("expected %s-%s to be predicted", pair[0], pair[1])
+			continue
+		}
+		if link.CommonNeighbours != 2 {
+			t.Errorf("%s-%s: expected 2 common neighbours, got %d", pair[0], pair[1], link.CommonNeighbours)
+		}
+		if link.Score <= 0 || link.Score > 1 {
+			t.Errorf("%s-%s: score %f out of range", pair[0], pair[1], link.Score)
+		}
+	}
+	if _, ok := tg.findLink(links, "a", "b"); ok {
+		t.Error("existing edges must not be predicted")
+	}
+	if _, ok := tg.findLink(links, "a", "e"); ok {
+		t.Error("a node without shared neighbours or community is not a candidate")
+	}
+}
+
+func TestPredictLinks_AdamicAdarFavoursSelectiveNeighbours(t *testing.T) {
+	// x and y share a hub; u and w share a node linking only them
+	tg := newTestGraph(t, []string{"x", "y", "h", "p1", "p2", "p3", "p4", "u", "w", "q"})
+	for _, n := range []string{"x", "y", "p1", "p2", "p3", "p4"} {
+		tg.addEdge(t, "h", n, 1)
+	}
+	tg.addEdge(t, "u", "q", 1)
+	tg.addEdge(t, "q", "w", 1)
+	cfg := DefaultLinkPredictionConfig()
+	cfg.MinScore = 0
+
+	links := tg.predict(nil, cfg)
+
+	viaHub, ok := tg.findLink(links, "x", "y")
+	if !ok {
+		t.Fatal("expected x-y to be predicted")
+	}
+	viaQ, ok := tg.findLink(links, "u", "w")
+	if !ok {
+		t.Fatal("expected u-w to be predicted")
+	}
+	if viaQ.AdamicAdar <= viaHub.AdamicAdar {
+		t.Errorf("a selective neighbour should count more: %f <= %f", viaQ.AdamicAdar, viaHub.AdamicAdar)
+	}
+	if viaQ.Score <= viaHub.Score {
+		t.Errorf("u-w should outscore x-y: %f <= %f", viaQ.Score, viaHub.Score)
+	}
+}
+
+func TestPredictLinks_CommunityCoMembers(t *testing.T) {
+	tg := newTestGraph(t, []string{"a", "b", "c"})
+	tg.addEdge(t, "a", "b", 1)
+	communities := map[string]string{
+		tg.ids["a"].String(): "1",
+		tg.ids["b"].String(): "1",
+		tg.ids["c"].String(): "1",
+	}
+
+	links := tg.predict(communities, nil)
+
+	link, ok := tg.findLink(links, "a", "c")
+	if !ok {
+		t.Fatal("community co-members should be candidates")
+	}
+	if !link.SameCommunity || link.CommonNeighbours != 0 {
+		t.Errorf("unexpected signals: %+v", link)
+	}
+
+	// Without the community the pair has nothing in common
+	if _, ok := tg.findLink(tg.predict(nil, nil), "a", "c"); ok {
+		t.Error("a-c should not be predicted without a shared community")
+	}
+}
+
+func TestPredictLinks_MinScore(t *testing.T) {
+	tg := newTestGraph(t, []string{"a", "b", "c"})
+	tg.addEdge(t, "a", "b", 1)
+	tg.addEdge(t, "b", "c", 1)
+	cfg := DefaultLinkPredictionConfig()
+	cfg.MinScore = 1.1
+
+	if links := tg.predict(nil, cfg); len(links) != 0 {
+		t.Errorf("expected no links above the minimum score, got %d", len(links))
+	}
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
+)
+
+// PathCostConfig controls how paths are scored and which edges they may use.
+type PathCostConfig struct {
+	// TypeFactors scale the cost of an edge by its type; types without a
+	// factor cost 1
+	TypeFactors map[entities.EdgeType]float64
+	// ExcludedTypes are edge types paths may not use
+	ExcludedTypes []entities.EdgeType
+	// Directed only follows edges from source to target, and bidirectional
+	// edges both ways; otherwise every edge is followed both ways
+	Directed bool
+	// MinWeight is the weight assumed for weaker edges, so that edges
+	// without weight are expensive rather than infinitely so
+	MinWeight float64
+}
+
+// DefaultPathCostConfig returns sensible defaults: strong and structural
+// links are cheap to follow, weak and merely temporal ones expensive.
+func DefaultPathCostConfig() *PathCostConfig {
+	return &PathCostConfig{
+		TypeFactors: map[entities.EdgeType]float64{
+			entities.EdgeTypeStrong:       0.8,
+			entities.EdgeTypeHierarchical: 0.9,
+			entities.EdgeTypeReference:    1.0,
+			entities.EdgeTypeNormal:       1.0,
+			entities.EdgeTypeTemporal:     1.2,
+			entities.EdgeTypeWeak:         1.5,
+		},
+		MinWeight: 0.01,
+	}
+}
+
+// EdgeCost returns the cost of following an edge: its type factor divided
+// by its weight, so a path of few strong edges beats one of many weak ones.
+func (c *PathCostConfig) EdgeCost(edge *aggregates.Edge) float64 {
+	factor, ok := c.TypeFactors[edge.Type]
+	if !ok {
+		factor = 1
+	}
+	return factor / math.Max(edge.Weight, c.MinWeight)
+}
+
+func (c *PathCostConfig) excludes(edgeType entities.EdgeType) bool {
+	for _, t := range c.ExcludedTypes {
+		if t == edgeType {
+			return true
+		}
+	}
+	return false
+}
+
+// PathHop is one edge followed by a path.
+type PathHop struct {
+	FromID   valueobjects.NodeID
+	ToID     valueobjects.NodeID
+	Edge     *aggregates.Edge
+	Cost     float64
+	Reversed bool // The edge points from ToID to FromID
+}
+
+// WeightedPath is a path between two nodes and its total cost.
+type WeightedPath struct {
+	NodeIDs []valueobjects.NodeID
+	Hops    []PathHop
+	Cost    float64
+}
+
+// PathFinderService finds cheapest paths through a graph, weighing edges by
+// weight and type.
+type PathFinderService struct{}
+
+// NewPathFinderService creates a new service.
+func NewPathFinderService() *PathFinderService {
+	return &PathFinderService{}
+}
+
+// ShortestPath returns the cheapest path from one node to another, or a
+// not found error when none exists.
+func (s *PathFinderService) ShortestPath(
+	graph *aggregates.Graph,
+	fromID, toID valueobjects.NodeID,
+	cfg *PathCostConfig,
+) (*WeightedPath, error) {
+	paths, err := s.KShortestPaths(graph, fromID, toID, 1, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, pkgerrors.NewNotFoundError("path between nodes")
+	}
+	return &paths[0], nil
+}
+
+// KShortestPaths returns up to k cheapest loopless paths from one node to
+// another, cheapest first, with Yen's algorithm: every further path leaves a
+// cheaper path at some node (the spur) and reaches the target without the
+// edges the cheaper paths with the same beginning took from there. Each
+// search is A* guided by the hop distance to the target.
+func (s *PathFinderService) KShortestPaths(
+	graph *aggregates.Graph,
+	fromID, toID valueobjects.NodeID,
+	k int,
+	cfg *PathCostConfig,
+) ([]WeightedPath, error) {
+	if cfg == nil {
+		cfg = DefaultPathCostConfig()
+	}
+	nodes, err := graph.Nodes()
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := nodes[fromID]; !exists {
+		return nil, pkgerrors.NewNotFoundError("start node")
+	}
+	if _, exists := nodes[toID]; !exists {
+		return nil, pkgerrors.NewNotFoundError("end node")
+	}
+	if k <= 0 {
+		return []WeightedPath{}, nil
+	}
+
+	g := newPathGraph(nodes, graph.GetEdges(), cfg)
+	from, to := g.index[fromID], g.index[toID]
+	g.setTarget(to)
+
+	first, ok := g.search(from, to, nil, nil)
+	if !ok {
+		return []WeightedPath{}, nil
+	}
+	found := []*candidatePath{first}
+	seen := map[string]bool{first.key(): true}
+	candidates := &candidateHeap{}
+
+	for len(found) < k {
+		previous := found[len(found)-1]
+		for i := 0; i < len(previous.nodes)-1; i++ {
+			spur := previous.nodes[i]
+			root := previous.nodes[:i+1]
+
+			// Leave the spur by an edge no found path with this root took
+			bannedArcs := make(map[[2]int]bool)
+			for _, p := range found {
+				if len(p.nodes) > i+1 && equalInts(p.nodes[:i+1], root) {
+					bannedArcs[[2]int{p.nodes[i], p.nodes[i+1]}] = true
+				}
+			}
+			// and never return to the root, which keeps paths loopless
+			bannedNodes := make([]bool, len(g.ids))
+			for _, n := range root[:i] {
+				bannedNodes[n] = true
+			}
+
+			spurPath, ok := g.search(spur, to, bannedNodes, bannedArcs)
+			if !ok {
+				continue
+			}
+			candidate := previous.prefix(i).join(spurPath)
+			if key := candidate.key(); !seen[key] {
+				seen[key] = true
+				heap.Push(candidates, candidate)
+			}
+		}
+		if candidates.Len() == 0 {
+			break
+		}
+		found = append(found, heap.Pop(candidates).(*candidatePath))
+	}
+
+	paths := make([]WeightedPath, len(found))
+	for i, p := range found {
+		paths[i] = g.weightedPath(p)
+	}
+	return paths, nil
+}
+
+// pathArc is an edge as followed from one node to another
+type pathArc struct {
+	to       int
+	cost     float64
+	edge     *aggregates.Edge
+	reversed bool
+}
+
+// pathGraph numbers the nodes in ID order and keeps the cheapest arc from
+// each node to each neighbour
+type pathGraph struct {
+	ids     []valueobjects.NodeID
+	index   map[valueobjects.NodeID]int
+	out     [][]pathArc
+	minCost float64
+	hops    []int // Hops from each node to the search target; -1 when unreachable
+}
+
+func newPathGraph(nodes map[valueobjects.NodeID]*entities.Node, edges []*aggregates.Edge, cfg *PathCostConfig) *pathGraph {
+	g := &pathGraph{
+		ids:     make([]valueobjects.NodeID, 0, len(nodes)),
+		index:   make(map[valueobjects.NodeID]int, len(nodes)),
+		minCost: math.Inf(1),
+	}
+	for id := range nodes {
+		g.ids = append(g.ids, id)
+	}
+	sort.Slice(g.ids, func(i, j int) bool { return g.ids[i].String() < g.ids[j].String() })
+	for i, id := range g.ids {
+		g.index[id] = i
+	}
+
+	cheapest := make([]map[int]pathArc, len(g.ids))
+	for i := range cheapest {
+		cheapest[i] = make(map[int]pathArc)
+	}
+	add := func(from, to int, arc pathArc) {
+		if current, ok := cheapest[from][to]; !ok || arc.cost < current.cost ||
+			(arc.cost == current.cost && arc.edge.ID < current.edge.ID) {
+			cheapest[from][to] = arc
+		}
+	}
+	for _, edge := range edges {
+		if cfg.excludes(edge.Type) {
+			continue
+		}
+		u, okU := g.index[edge.SourceID]
+		v, okV := g.index[edge.TargetID]
+		if !okU || !okV || u == v {
+			continue
+		}
+		cost := cfg.EdgeCost(edge)
+		add(u, v, pathArc{to: v, cost: cost, edge: edge})
+		if !cfg.Directed || edge.Bidirectional {
+			add(v, u, pathArc{to: u, cost: cost, edge: edge, reversed: true})
+		}
+	}
+
+	g.out = make([][]pathArc, len(g.ids))
+	for u, arcs := range cheapest {
+		for _, arc := range arcs {
+			g.out[u] = append(g.out[u], arc)
+			g.minCost = math.Min(g.minCost, arc.cost)
+		}
+		sort.Slice(g.out[u], func(i, j int) bool { return g.out[u][i].to < g.out[u][j].to })
+	}
+	if math.IsInf(g.minCost, 1) {
+		// No arcs; only a path from a node to itself exists
+		g.minCost = 0
+	}
+	return g
+}
+
+// setTarget counts the hops from every node to the target, walking the
+// arcs backwards, for the A* heuristic
+func (g *pathGraph) setTarget(target int) {
+	incoming := make([][]int, len(g.ids))
+	for u, arcs := range g.out {
+		for _, arc := range arcs {
+			incoming[arc.to] = append(incoming[arc.to], u)
+		}
+	}
+	g.hops = make([]int, len(g.ids))
+	for i := range g.hops {
+		g.hops[i] = -1
+	}
+	g.hops[target] = 0
+	queue := []int{target}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range incoming[v] {
+			if g.hops[u] < 0 {
+				g.hops[u] = g.hops[v] + 1
+				queue = append(queue, u)
+			}
+		}
+	}
+}
+
+// search runs A* from one node to the target set by setTarget, avoiding the
+// banned nodes and arcs. Every hop costs at least the cheapest arc, so hops
+// times that cost never overestimates the remaining cost and is consistent,
+// which lets each node be settled once.
+func (g *pathGraph) search(from, to int, bannedNodes []bool, bannedArcs map[[2]int]bool) (*candidatePath, bool) {
+	if g.hops[from] < 0 {
+		return nil, false
+	}
+	estimate := func(u int) float64 {
+		return float64(g.hops[u]) * g.minCost
+	}
+
+	cost := map[int]float64{from: 0}
+	previous := make(map[int]pathArc)
+	previousNode := make(map[int]int)
+	settled := make(map[int]bool)
+	open := &searchHeap{{node: from, priority: estimate(from)}}
+	for open.Len() > 0 {
+		u := heap.Pop(open).(searchItem).node
+		if settled[u] {
+			continue
+		}
+		settled[u] = true
+		if u == to {
+			break
+		}
+		for _, arc := range g.out[u] {
+			v := arc.to
+			if settled[v] || g.hops[v] < 0 || (bannedNodes != nil && bannedNodes[v]) || bannedArcs[[2]int{u, v}] {
+				continue
+			}
+			next := cost[u] + arc.cost
+			if current, ok := cost[v]; ok && next >= current {
+				continue
+			}
+			cost[v] = next
+			previous[v] = arc
+			previousNode[v] = u
+			heap.Push(open, searchItem{node: v, priority: next + estimate(v)})
+		}
+	}
+	if !settled[to] {
+		return nil, false
+	}
+
+	path := &candidatePath{nodes: []int{to}, cost: cost[to]}
+	for v := to; v != from; v = previousNode[v] {
+		path.nodes = append(path.nodes, previousNode[v])
+		path.arcs = append(path.arcs, previous[v])
+	}
+	reverseInts(path.nodes)
+	for i, j := 0, len(path.arcs)-1; i < j; i, j = i+1, j-1 {
+		path.arcs[i], path.arcs[j] = path.arcs[j], path.arcs[i]
+	}
+	return path, true
+}
+
+func (g *pathGraph) weightedPath(p *candidatePath) WeightedPath {
+	path := WeightedPath{
+		NodeIDs: make([]valueobjects.NodeID, len(p.nodes)),
+		Hops:    make([]PathHop, len(p.arcs)),
+		Cost:    p.cost,
+	}
+	for i, n := range p.nodes {
+		path.NodeIDs[i] = g.ids[n]
+	}
+	for i, arc := range p.arcs {
+		path.Hops[i] = PathHop{
+			FromID:   g.ids[p.nodes[i]],
+			ToID:     g.ids[arc.to],
+			Edge:     arc.edge,
+			Cost:     arc.cost,
+			Reversed: arc.reversed,
+		}
+	}
+	return path
+}
+
+// candidatePath is a path by node number; arcs[i] leads from nodes[i] to
+// nodes[i+1]
+type candidatePath struct {
+	nodes []int
+	arcs  []pathArc
+	cost  float64
+}
+
+// prefix returns the path up to and including its i-th node
+func (p *candidatePath) prefix(i int) *candidatePath {
+	root := &candidatePath{
+		nodes: append([]int(nil), p.nodes[:i+1]...),
+		arcs:  append([]pathArc(nil), p.arcs[:i]...),
+	}
+	for _, arc := range root.arcs {
+		root.cost += arc.cost
+	}
+	return root
+}
+
+// join appends a path starting at p's last node
+func (p *candidatePath) join(next *candidatePath) *candidatePath {
+	return &candidatePath{
+		nodes: append(p.nodes, next.nodes[1:]...),
+		arcs:  append(p.arcs, next.arcs...),
+		cost:  p.cost + next.cost,
+	}
+}
+
+func (p *candidatePath) key() string {
+	parts := make([]string, len(p.nodes))
+	for i, n := range p.nodes {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+// candidateHeap orders candidate paths by cost, then by hops, then by nodes
+type candidateHeap []*candidatePath
+
+func (h candidateHeap) Len() int { return len(h) }
+func (h candidateHeap) Less(i, j int) bool {
+	if h[i].cost != h[j].cost {
+		return h[i].cost < h[j].cost
+	}
+	if len(h[i].nodes) != len(h[j].nodes) {
+		return len(h[i].nodes) < len(h[j].nodes)
+	}
+	return h[i].key() < h[j].key()
+}
+func (h candidateHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x interface{}) { *h = append(*h, x.(*candidatePath)) }
+func (h *candidateHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+type searchItem struct {
+	node     int
+	priority float64
+}
+
+type searchHeap []searchItem
+
+func (h searchHeap) Len() int { return len(h) }
+func (h searchHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].node < h[j].node
+}
+func (h searchHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *searchHeap) Push(x interface{}) { *h = append(*h, x.(searchItem)) }
+func (h *searchHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	pkgerrors "backend/pkg/errors"
+)
+
+func (tg *testGraph) addTypedEdge(t *testing.T, from, to string, weight float64, edgeType entities.EdgeType) {
+	t.Helper()
+	edge := &aggregates.Edge{
+		ID:        from + "->" + to,
+		SourceID:  tg.ids[from],
+		TargetID:  tg.ids[to],
+		Type:      edgeType,
+		Weight:    weight,
+		CreatedAt: time.Now(),
+	}
+	if err := tg.graph.LoadEdge(edge); err != nil {
+		t.Fatalf("failed to load edge %s->%s: %v", from, to, err)
+	}
+}
+
+func (tg *testGraph) names(path WeightedPath) []string {
+	byID := make(map[string]string, len(tg.ids))
+	for name, id := range tg.ids {
+		byID[id.String()] = name
+	}
+	out := make([]string, len(path.NodeIDs))
+	for i, id := range path.NodeIDs {
+		out[i] = byID[id.String()]
+	}
+	return out
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestShortestPath_PrefersStrongEdgesOverFewerHops(t *testing.T) {
+	tg := newTestGraph(t, []string{"a", "b", "c"})
+	tg.addEdge(t, "a", "b", 0.9)
+	tg.addEdge(t, "b", "c", 0.9)
+	tg.addEdge(t, "a", "c", 0.1)
+
+	path, err := NewPathFinderService().ShortestPath(tg.graph, tg.ids["a"], tg.ids["c"], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tg.names(*path); !equalNames(got, []string{"a", "b", "c"}) {
+		t.Errorf("expected path a-b-c, got %v", got)
+	}
+	if math.Abs(path.Cost-2/0.9) > 1e-9 {
+		t.Errorf("cost = %f, want %f", path.Cost, 2/0.9)
+	}
+	if len(path.Hops) != 2 || path.Hops[0].Edge.ID != "a->b" {
+		t.Errorf("unexpected hops %+v", path.Hops)
+	}
+}
+
+func TestShortestPath_EdgeTypes(t *testing.T) {
+	tg := newTestGraph(t, []string{"a", "b", "c", "d"})
+	tg.addTypedEdge(t, "a", "b", 1, entities.EdgeTypeWeak)
+	tg.addTypedEdge(t, "b", "d", 1, entities.EdgeTypeWeak)
+	tg.addTypedEdge(t, "a", "c", 1, entities.EdgeTypeStrong)
+	tg.addTypedEdge(t, "c", "d", 1, entities.EdgeTypeStrong)
+
+	svc := NewPathFinderService()
+	path, err := svc.ShortestPath(tg.graph, tg.ids["a"], tg.ids["d"], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tg.names(*path); !equalNames(got, []string{"a", "c", "d"}) {
+		t.Errorf("strong edges should be cheaper, got %v", got)
+	}
+
+	cfg := DefaultPathCostConfig()
+	cfg.ExcludedTypes = []entities.EdgeType{entities.EdgeTypeStrong}
+	path, err = svc.ShortestPath(tg.graph, tg.ids["a"], tg.ids["d"], cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tg.names(*path); !equalNames(got, []string{"a", "b", "d"}) {
+		t.Errorf("excluded types must not be used, got %v", got)
+	}
+}
+
+func TestShortestPath_Direction(t *testing.T) {
+	tg := newTestGraph(t, []string{"a", "b"})
+	tg.addEdge(t, "b", "a", 1)
+
+	svc := NewPathFinderService()
+	path, err := svc.ShortestPath(tg.graph, tg.ids["a"], tg.ids["b"], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !path.Hops[0].Reversed {
+		t.Error("following b->a from a should be reported as reversed")
+	}
+
+	cfg := DefaultPathCostConfig()
+	cfg.Directed = true
+	_, err = svc.ShortestPath(tg.graph, tg.ids["a"], tg.ids["b"], cfg)
+	if !pkgerrors.IsNotFound(err) {
+		t.Errorf("expected no path against the edge direction, got %v", err)
+	}
+}
+
+func TestKShortestPaths_Yen(t *testing.T) {
+	// Classic example: c-d-f-h and its alternatives
+	tg := newTestGraph(t, []string{"c", "d", "e", "f", "g", "h"})
+	for _, e := range []struct {
+		from, to string
+		cost     float64
+	}{
+		{"c", "d", 3}, {"c", "e", 2}, {"d", "f", 4}, {"e", "d", 1},
+		{"e", "f", 2}, {"e", "g", 3}, {"f", "g", 2}, {"f", "h", 1}, {"g", "h", 2},
+	} {
+		// Weight is the inverse of the wanted cost for normal edges
+		tg.addEdge(t, e.from, e.to, 1/e.cost)
+	}
+
+	cfg := DefaultPathCostConfig()
+	cfg.Directed = true
+	paths, err := NewPathFinderService().KShortestPaths(tg.graph, tg.ids["c"], tg.ids["h"], 3, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected 3 paths, got %d", len(paths))
+	}
+	if got := tg.names(paths[0]); !equalNames(got, []string{"c", "e", "f", "h"}) {
+		t.Errorf("best path = %v", got)
+	}
+	if got := tg.names(paths[1]); !equalNames(got, []string{"c", "e", "g", "h"}) {
+		t.Errorf("second path = %v", got)
+	}
+	// Three paths tie for third place
+	for i, want := range []float64{5, 7, 8} {
+		if math.Abs(paths[i].Cost-want) > 1e-9 {
+			t.Errorf("path %d cost = %f, want %f", i, paths[i].Cost, want)
+		}
+	}
+
+	// Asking for more paths than exist returns every loopless path
+	all, err := NewPathFinderService().KShortestPaths(tg.graph, tg.ids["c"], tg.ids["h"], 50, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Cost < all[i-1].Cost {
+			t.Errorf("paths out of order at %d", i)
+		}
+	}
+	for _, p := range all {
+		seen := make(map[string]bool)
+		for _, id := range p.NodeIDs {
+			if seen[id.String()] {
+				t.Errorf("path %v repeats a node", tg.names(p))
+			}
+			seen[id.String()] = true
+		}
+	}
+	if len(all) != 7 {
+		t.Errorf("expected 7 loopless paths, got %d", len(all))
+	}
+}
+
+func TestKShortestPaths_UnknownNode(t *testing.T) {
+	tg := newTestGraph(t, []string{"a"})
+	other := newTestGraph(t, []string{"x"})
+
+	_, err := NewPathFinderService().KShortestPaths(tg.graph, tg.ids["a"], other.ids["x"], 3, nil)
+	if !pkgerrors.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/valueobjects"
+)
+
+func (tg *testGraph) edgeList() []*aggregates.Edge {
+	edges := make([]*aggregates.Edge, 0)
+	for _, e := range tg.graph.Edges() {
+		edges = append(edges, e)
+	}
+	return edges
+}
+
+func (tg *testGraph) rank(seeds ...string) *PersonalizedPageRank {
+	ids := make([]valueobjects.NodeID, len(seeds))
+	for i, s := range seeds {
+		ids[i] = tg.ids[s]
+	}
+	return NewPersonalizedPageRankService().Rank(tg.edgeList(), ids, nil)
+}
+
+func (tg *testGraph) score(ppr *PersonalizedPageRank, name string) float64 {
+	return ppr.Scores[tg.ids[name].String()]
+}
+
+func TestPersonalizedPageRank_DecaysWithDistance(t *testing.T) {
+	tg := newTestGraph(t, []string{"A", "B", "C", "D", "E"})
+	tg.addEdge(t, "A", "B", 1)
+	tg.addEdge(t, "B", "C", 1)
+	tg.addEdge(t, "C", "D", 1)
+
+	ppr := tg.rank("A")
+
+	total := 0.0
+	for _, s := range ppr.Scores {
+		total += s
+	}
+	if math.Abs(total-1) > 1e-4 {
+		t.Errorf("scores sum to %f, want 1", total)
+	}
+	if !(tg.score(ppr, "B") > tg.score(ppr, "C") && tg.score(ppr, "C") > tg.score(ppr, "D")) {
+		t.Errorf("expected B > C > D, got %f %f %f",
+			tg.score(ppr, "B"), tg.score(ppr, "C"), tg.score(ppr, "D"))
+	}
+	if tg.score(ppr, "E") != 0 {
+		t.Errorf("unconnected node should not be reached, got %f", tg.score(ppr, "E"))
+	}
+}
+
+func TestPersonalizedPageRank_FollowsWeights(t *testing.T) {
+	tg := newTestGraph(t, []string{"A", "B", "C"})
+	tg.addEdge(t, "A", "B", 0.9)
+	tg.addEdge(t, "C", "A", 0.1)
+
+	ppr := tg.rank("A")
+	if tg.score(ppr, "B") <= tg.score(ppr, "C") {
+		t.Errorf("strong edge should rank higher: B=%f C=%f", tg.score(ppr, "B"), tg.score(ppr, "C"))
+	}
+}
+
+func TestPersonalizedPageRank_MultipleSeeds(t *testing.T) {
+	// Both seeds link to shared; only A links to single
+	tg := newTestGraph(t, []string{"A", "B", "shared", "single"})
+	tg.addEdge(t, "A", "shared", 1)
+	tg.addEdge(t, "B", "shared", 1)
+	tg.addEdge(t, "A", "single", 1)
+
+	ppr := tg.rank("A", "B")
+	if tg.score(ppr, "shared") <= tg.score(ppr, "single") {
+		t.Errorf("node near both seeds should rank higher: shared=%f single=%f",
+			tg.score(ppr, "shared"), tg.score(ppr, "single"))
+	}
+}
+
+func TestPersonalizedPageRank_Paths(t *testing.T) {
+	tg := newTestGraph(t, []string{"A", "B", "C", "D"})
+	tg.addEdge(t, "A", "B", 0.9)
+	tg.addEdge(t, "B", "C", 0.9)
+	tg.addEdge(t, "A", "D", 0.1)
+	tg.addEdge(t, "D", "C", 0.1)
+
+	ppr := tg.rank("A")
+	paths := ppr.Paths(tg.ids["C"].String(), 3)
+	if len(paths) != 1 {
+		t.Fatalf("expected one path from the only seed, got %d", len(paths))
+	}
+	want := []string{tg.ids["A"].String(), tg.ids["B"].String(), tg.ids["C"].String()}
+	if len(paths[0].Steps) != len(want) {
+		t.Fatalf("expected path A-B-C, got %v", paths[0].Steps)
+	}
+	for i := range want {
+		if paths[0].Steps[i] != want[i] {
+			t.Fatalf("expected path A-B-C, got %v", paths[0].Steps)
+		}
+	}
+
+	// A splits 0.9/0.1 between B and D; B splits 0.9/0.9 between A and C
+	wantStrength := 0.85 * 0.9 * 0.85 * 0.5
+	if math.Abs(paths[0].Strength-wantStrength) > 1e-9 {
+		t.Errorf("strength = %f, want %f", paths[0].Strength, wantStrength)
+	}
+
+	if got := ppr.Paths(tg.ids["A"].String(), 3); len(got) != 0 {
+		t.Errorf("seeds have no path to themselves, got %v", got)
+	}
+}
+
+func TestPersonalizedPageRank_NoSeeds(t *testing.T) {
+	tg := newTestGraph(t, []string{"A", "B"})
+	tg.addEdge(t, "A", "B", 1)
+
+	ppr := NewPersonalizedPageRankService().Rank(tg.edgeList(), nil, nil)
+	if len(ppr.Scores) != 0 {
+		t.Errorf("expected no scores without seeds, got %v", ppr.Scores)
+	}
+}
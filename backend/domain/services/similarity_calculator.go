@@ -78,7 +78,9 @@ func (sc *HybridSimilarityCalculator) CalculateDetailed(node1, node2 *entities.N
 	}
 
 	keywordSim := sc.keywordSimilarity(node1, node2)
-	hasBothEmbeddings := node1.HasEmbedding() && node2.HasEmbedding()
+	// Vectors from different models are not comparable
+	hasBothEmbeddings := node1.HasEmbedding() && node2.HasEmbedding() &&
+		node1.Embedding().IsCompatible(node2.Embedding())
 
 	if hasBothEmbeddings {
 		semanticSim := sc.semanticSimilarity(node1.Embedding(), node2.Embedding())
@@ -106,7 +108,7 @@ func (sc *HybridSimilarityCalculator) CalculateWithKeywords(node *entities.Node,
 	nodeKeywords := sc.extractNodeKeywords(node)
 	nodeTags := sc.extractNodeTags(node)
 
-	kwSim := jaccardSimilarity(nodeKeywords, keywords)
+	kwSim := jaccardSimilarity(nodeKeywords, sc.analyzeKeywords(keywords))
 	tagSim := jaccardSimilarity(nodeTags, tags)
 
 	return math.Min((kwSim*(1-sc.config.TagWeight))+(tagSim*sc.config.TagWeight), 1.0)
@@ -170,19 +172,37 @@ func (sc *HybridSimilarityCalculator) extractNodeKeywords(node *entities.Node) m
 	text := content.Title() + " " + content.Body()
 
 	if sc.config.UseStopWords {
-		keywords := sc.textAnalyzer.ExtractKeywords(text)
-		set := make(map[string]bool)
-		for _, kw := range keywords {
-			if len(kw) >= sc.config.MinWordLength {
-				set[strings.ToLower(kw)] = true
-			}
-		}
-		return set
+		return sc.termSet(sc.textAnalyzer.Analyze(text))
 	}
 
 	return sc.textAnalyzer.TokenizeWords(text)
 }
 
+// analyzeKeywords brings pre-extracted keywords into the form
+// extractNodeKeywords produces
+func (sc *HybridSimilarityCalculator) analyzeKeywords(keywords map[string]bool) map[string]bool {
+	if !sc.config.UseStopWords {
+		return keywords
+	}
+	words := make([]string, 0, len(keywords))
+	for kw := range keywords {
+		words = append(words, kw)
+	}
+	return sc.termSet(sc.textAnalyzer.Analyze(strings.Join(words, " ")))
+}
+
+// termSet collects the stemmed terms of at least MinWordLength characters, so
+// inflections of a word count as the same keyword
+func (sc *HybridSimilarityCalculator) termSet(terms []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, term := range terms {
+		if len([]rune(term)) >= sc.config.MinWordLength {
+			set[term] = true
+		}
+	}
+	return set
+}
+
 func (sc *HybridSimilarityCalculator) extractNodeTags(node *entities.Node) map[string]bool {
 	tags := node.GetTags()
 	set := make(map[string]bool)
@@ -0,0 +1,332 @@
+package services
+
+import "strings"
+
+// Stem reduces a normalized word to its stem in language. English uses the
+// Porter algorithm; German, French and Spanish use light Snowball-style
+// suffix stripping. Words with characters other than a-z are left alone.
+func Stem(word string, language Language) string {
+	if len(word) <= 2 || !isASCIIWord(word) {
+		return word
+	}
+	switch language {
+	case LanguageGerman:
+		return germanStemmer.stem(word)
+	case LanguageFrench:
+		return frenchStemmer.stem(word)
+	case LanguageSpanish:
+		return spanishStemmer.stem(word)
+	default:
+		return porterStem(word)
+	}
+}
+
+func isASCIIWord(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Porter stemmer ---
+
+// porterStem implements the Porter (1980) stemming algorithm.
+func porterStem(word string) string {
+	w := []byte(word)
+	w = porterStep1a(w)
+	w = porterStep1b(w)
+	w = porterStep1c(w)
+	w = porterReplace(w, porterStep2, 0)
+	w = porterReplace(w, porterStep3, 0)
+	w = porterStep4(w)
+	w = porterStep5(w)
+	return string(w)
+}
+
+// isConsonant reports whether w[i] is a consonant. 'y' is a consonant at the
+// start of a word or after a vowel.
+func isConsonant(w []byte, i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(w, i-1)
+	}
+	return true
+}
+
+// porterMeasure counts the vowel-consonant sequences of w
+func porterMeasure(w []byte) int {
+	m, i := 0, 0
+	for i < len(w) && isConsonant(w, i) {
+		i++
+	}
+	for i < len(w) {
+		for i < len(w) && !isConsonant(w, i) {
+			i++
+		}
+		if i == len(w) {
+			break
+		}
+		for i < len(w) && isConsonant(w, i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+func hasVowel(w []byte) bool {
+	for i := range w {
+		if !isConsonant(w, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func endsDoubleConsonant(w []byte) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && isConsonant(w, n-1)
+}
+
+// endsCVC reports whether w ends consonant-vowel-consonant, where the last
+// consonant is not w, x or y, as in "hop"
+func endsCVC(w []byte) bool {
+	n := len(w)
+	if n < 3 || !isConsonant(w, n-3) || isConsonant(w, n-2) || !isConsonant(w, n-1) {
+		return false
+	}
+	c := w[n-1]
+	return c != 'w' && c != 'x' && c != 'y'
+}
+
+func hasSuffix(w []byte, suffix string) bool {
+	return len(w) >= len(suffix) && string(w[len(w)-len(suffix):]) == suffix
+}
+
+func porterStep1a(w []byte) []byte {
+	switch {
+	case hasSuffix(w, "sses"), hasSuffix(w, "ies"):
+		return w[:len(w)-2]
+	case hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func porterStep1b(w []byte) []byte {
+	if hasSuffix(w, "eed") {
+		if porterMeasure(w[:len(w)-3]) > 0 {
+			return w[:len(w)-1]
+		}
+		return w
+	}
+
+	var stem []byte
+	switch {
+	case hasSuffix(w, "ed") && hasVowel(w[:len(w)-2]):
+		stem = w[:len(w)-2]
+	case hasSuffix(w, "ing") && hasVowel(w[:len(w)-3]):
+		stem = w[:len(w)-3]
+	default:
+		return w
+	}
+
+	switch {
+	case hasSuffix(stem, "at"), hasSuffix(stem, "bl"), hasSuffix(stem, "iz"):
+		return append(stem, 'e')
+	case endsDoubleConsonant(stem):
+		if c := stem[len(stem)-1]; c != 'l' && c != 's' && c != 'z' {
+			return stem[:len(stem)-1]
+		}
+	case porterMeasure(stem) == 1 && endsCVC(stem):
+		return append(stem, 'e')
+	}
+	return stem
+}
+
+func porterStep1c(w []byte) []byte {
+	if hasSuffix(w, "y") && hasVowel(w[:len(w)-1]) {
+		w[len(w)-1] = 'i'
+	}
+	return w
+}
+
+// porterRule replaces a suffix
+type porterRule struct {
+	suffix, replacement string
+}
+
+var porterStep2 = []porterRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"},
+	{"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"},
+	{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"},
+	{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"}, {"logi", "log"},
+}
+
+var porterStep3 = []porterRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// porterReplace applies the rule of the longest matching suffix when the
+// remaining stem measures more than minMeasure
+func porterReplace(w []byte, rules []porterRule, minMeasure int) []byte {
+	match := -1
+	for i, rule := range rules {
+		if hasSuffix(w, rule.suffix) && (match < 0 || len(rule.suffix) > len(rules[match].suffix)) {
+			match = i
+		}
+	}
+	if match < 0 {
+		return w
+	}
+	stem := w[:len(w)-len(rules[match].suffix)]
+	if porterMeasure(stem) <= minMeasure {
+		return w
+	}
+	return append(stem, rules[match].replacement...)
+}
+
+var porterStep4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+	"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+func porterStep4(w []byte) []byte {
+	longest := ""
+	for _, suffix := range porterStep4Suffixes {
+		if hasSuffix(w, suffix) && len(suffix) > len(longest) {
+			longest = suffix
+		}
+	}
+	if longest == "" {
+		return w
+	}
+	stem := w[:len(w)-len(longest)]
+	if longest == "ion" && !hasSuffix(stem, "s") && !hasSuffix(stem, "t") {
+		return w
+	}
+	if porterMeasure(stem) > 1 {
+		return stem
+	}
+	return w
+}
+
+func porterStep5(w []byte) []byte {
+	if hasSuffix(w, "e") {
+		stem := w[:len(w)-1]
+		if m := porterMeasure(stem); m > 1 || (m == 1 && !endsCVC(stem)) {
+			w = stem
+		}
+	}
+	if porterMeasure(w) > 1 && hasSuffix(w, "ll") {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+// --- Light stemmers ---
+
+// suffixStemmer strips the longest matching suffix in each of its steps. A
+// suffix is only removed when it lies in the word's R1 region, the part after
+// the first consonant that follows a vowel, so short words keep their stem.
+type suffixStemmer struct {
+	vowels string
+	steps  [][]string
+	// derivational makes the first step exclusive with the second: when a
+	// derivational ending is removed, verb endings are not
+	derivational bool
+}
+
+func (s *suffixStemmer) stem(word string) string {
+	r1 := s.region(word)
+	removedDerivational := false
+	for i, suffixes := range s.steps {
+		if i == 1 && removedDerivational {
+			continue
+		}
+		var removed bool
+		word, removed = stripLongestSuffix(word, r1, suffixes)
+		removedDerivational = i == 0 && removed && s.derivational
+	}
+	return word
+}
+
+// region returns the start of R1, at least 3
+func (s *suffixStemmer) region(word string) int {
+	for i := 1; i < len(word); i++ {
+		if !strings.ContainsRune(s.vowels, rune(word[i])) && strings.ContainsRune(s.vowels, rune(word[i-1])) {
+			if i+1 < 3 {
+				return 3
+			}
+			return i + 1
+		}
+	}
+	return len(word)
+}
+
+func stripLongestSuffix(word string, region int, suffixes []string) (string, bool) {
+	longest := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(longest) && strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= region {
+			longest = suffix
+		}
+	}
+	if longest == "" {
+		return word, false
+	}
+	return word[:len(word)-len(longest)], true
+}
+
+var germanStemmer = &suffixStemmer{
+	vowels: "aeiouy",
+	steps: [][]string{
+		{"ern", "em", "er", "en", "es", "e", "s"},
+		{"est", "en", "er", "st"},
+		{"ung", "heit", "keit", "lich", "isch", "ig", "end"},
+	},
+}
+
+var frenchStemmer = &suffixStemmer{
+	vowels: "aeiouy",
+	steps: [][]string{
+		{
+			"issements", "issement", "atrices", "atrice", "ateurs", "ateur", "ations", "ation",
+			"ances", "ance", "ences", "ence", "ements", "ement", "ments", "ment", "ismes", "isme",
+			"istes", "iste", "ables", "able", "euses", "euse", "eux", "ites", "ite", "ives", "ive",
+			"ifs", "if",
+		},
+		{
+			"issions", "issons", "issiez", "issent", "issez", "erions", "erons", "eront",
+			"erez", "erent", "aient", "ions", "iez", "ait", "ais", "ant", "ees", "ee", "er", "ez", "ir",
+		},
+		{"es", "s", "e", "x"},
+	},
+	derivational: true,
+}
+
+var spanishStemmer = &suffixStemmer{
+	vowels: "aeiou",
+	steps: [][]string{
+		{
+			"amientos", "imientos", "amiento", "imiento", "aciones", "uciones", "adoras", "adores",
+			"ancias", "logias", "encias", "idades", "amente", "mente", "acion", "ucion", "adora",
+			"ador", "ancia", "logia", "encia", "idad", "ables", "ibles", "able", "ible", "istas",
+			"ista", "osos", "osas", "oso", "osa", "ivas", "ivos", "iva", "ivo", "ismos", "ismo",
+		},
+		{
+			"aremos", "eremos", "iremos", "ando", "iendo", "aron", "ieron", "aban", "aba",
+			"ados", "adas", "ado", "ada", "idos", "idas", "ido", "ida", "ar", "er", "ir", "an",
+			"en", "es",
+		},
+		{"os", "as", "a", "o", "e"},
+	},
+	derivational: true,
+}
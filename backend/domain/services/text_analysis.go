@@ -0,0 +1,223 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Language identifies the language a text is analyzed in. It selects the
+// stop words and the stemmer of an analysis chain.
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageGerman  Language = "de"
+	LanguageFrench  Language = "fr"
+	LanguageSpanish Language = "es"
+)
+
+// languages lists the supported languages; on a detection tie the earlier wins
+var languages = []Language{LanguageEnglish, LanguageGerman, LanguageFrench, LanguageSpanish}
+
+// TokenFilter is one stage of an analysis chain. It receives the normalized
+// tokens of a text in order, with repeats, and returns the tokens to pass on.
+type TokenFilter interface {
+	Filter(tokens []string, language Language) []string
+}
+
+// TokenFilterFunc adapts a function to a TokenFilter.
+type TokenFilterFunc func(tokens []string, language Language) []string
+
+// Filter calls f.
+func (f TokenFilterFunc) Filter(tokens []string, language Language) []string {
+	return f(tokens, language)
+}
+
+// AnalysisChain turns text into index terms. The text is Unicode normalized
+// (compatibility decomposed, diacritics removed, lowercased) and split into
+// words, its language is detected, and the words run through the filters in
+// order.
+type AnalysisChain struct {
+	filters  []TokenFilter
+	fallback Language
+}
+
+// NewAnalysisChain creates a chain of filters. Texts whose language cannot be
+// detected are analyzed as fallback.
+func NewAnalysisChain(fallback Language, filters ...TokenFilter) *AnalysisChain {
+	if fallback == "" {
+		fallback = LanguageEnglish
+	}
+	return &AnalysisChain{filters: filters, fallback: fallback}
+}
+
+// DefaultAnalysisChain drops stop words, stems, and drops single characters.
+func DefaultAnalysisChain() *AnalysisChain {
+	return NewAnalysisChain(LanguageEnglish, StopWordFilter(), StemFilter(), MinLengthFilter(2))
+}
+
+// Analyze returns the terms of text in order, with repeats.
+func (c *AnalysisChain) Analyze(text string) []string {
+	tokens := splitNormalized(NormalizeText(text))
+	language := detectLanguage(text, tokens, c.fallback)
+	for _, filter := range c.filters {
+		tokens = filter.Filter(tokens, language)
+	}
+	return tokens
+}
+
+// Language returns the detected language of text.
+func (c *AnalysisChain) Language(text string) Language {
+	return detectLanguage(text, splitNormalized(NormalizeText(text)), c.fallback)
+}
+
+// StopWordFilter drops the stop words of the text's language.
+func StopWordFilter() TokenFilter {
+	return TokenFilterFunc(func(tokens []string, language Language) []string {
+		stopWords := stopWordLists[language]
+		kept := tokens[:0]
+		for _, token := range tokens {
+			if !stopWords[token] {
+				kept = append(kept, token)
+			}
+		}
+		return kept
+	})
+}
+
+// StemFilter reduces words to their stem with the stemmer of the text's
+// language, so inflections like "running" and "runs" share a term.
+func StemFilter() TokenFilter {
+	return TokenFilterFunc(func(tokens []string, language Language) []string {
+		for i, token := range tokens {
+			tokens[i] = Stem(token, language)
+		}
+		return tokens
+	})
+}
+
+// MinLengthFilter drops tokens shorter than minLength characters.
+func MinLengthFilter(minLength int) TokenFilter {
+	return TokenFilterFunc(func(tokens []string, _ Language) []string {
+		kept := tokens[:0]
+		for _, token := range tokens {
+			if len([]rune(token)) >= minLength {
+				kept = append(kept, token)
+			}
+		}
+		return kept
+	})
+}
+
+// foldedLetters are letters without a canonical decomposition and their
+// ASCII spelling
+var foldedLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ł': "l", 'ı': "i", 'þ': "th",
+}
+
+// NormalizeText lowercases text, applies Unicode compatibility decomposition
+// (NFKD) and removes the combining marks it produces, so "Café", "cafe" and
+// full-width "ｃａｆｅ" are all spelled "cafe".
+func NormalizeText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range norm.NFKD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if folded, ok := foldedLetters[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// splitNormalized splits normalized text into runs of letters and digits
+func splitNormalized(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// languageMarks are letters that are common in one supported language only
+var languageMarks = map[rune]Language{
+	'ä': LanguageGerman, 'ö': LanguageGerman, 'ü': LanguageGerman, 'ß': LanguageGerman,
+	'è': LanguageFrench, 'ê': LanguageFrench, 'ë': LanguageFrench, 'à': LanguageFrench,
+	'â': LanguageFrench, 'î': LanguageFrench, 'ô': LanguageFrench, 'û': LanguageFrench,
+	'ç': LanguageFrench, 'œ': LanguageFrench, 'é': LanguageFrench,
+	'ñ': LanguageSpanish, 'á': LanguageSpanish, 'í': LanguageSpanish, 'ó': LanguageSpanish,
+	'ú': LanguageSpanish, '¿': LanguageSpanish, '¡': LanguageSpanish,
+}
+
+// DetectLanguage guesses the language of text from its stop words and
+// language specific letters. Text without either is reported as English.
+func DetectLanguage(text string) Language {
+	return detectLanguage(text, splitNormalized(NormalizeText(text)), LanguageEnglish)
+}
+
+// detectLanguage scores each language by the stop words among tokens, the
+// normalized words of text, and the language specific letters of text
+func detectLanguage(text string, tokens []string, fallback Language) Language {
+	scores := make(map[Language]int, len(languages))
+	for _, token := range tokens {
+		for _, language := range languages {
+			if stopWordLists[language][token] {
+				scores[language]++
+			}
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		if language, ok := languageMarks[r]; ok {
+			scores[language]++
+		}
+	}
+
+	best, bestScore := fallback, 0
+	for _, language := range languages {
+		if scores[language] > bestScore {
+			best, bestScore = language, scores[language]
+		}
+	}
+	return best
+}
+
+// stopWordLists holds the normalized stop words of each language
+var stopWordLists = map[Language]map[string]bool{
+	LanguageEnglish: getDefaultStopWords(),
+	LanguageGerman: wordSet(`
+		aber alle allem allen aller alles als also am an andere anderen anderer anderes
+		auch auf aus bei bin bis bist da damit dann das dass dein deine dem den denn der
+		des dich die dies diese diesem diesen dieser dieses dir doch dort du durch ein
+		eine einem einen einer eines er es etwas euch euer fur gegen habe haben hat hatte
+		hier hin hinter ich ihm ihn ihnen ihr ihre im in ist jede jedem jeden jeder jedes
+		jetzt kann kein keine konnen man mein meine mich mir mit muss nach nicht nichts
+		noch nun nur ob oder ohne sehr sein seine sich sie sind so soll sondern uber um
+		und uns unser unter viel vom von vor war waren warum was weil welche wenn wer
+		werden wie wieder wir wird wo zu zum zur zwischen`),
+	LanguageFrench: wordSet(`
+		ai au aussi aux avec avez avoir avons car ce ces cette comme dans de des donc du
+		elle elles en entre est et etait ete etre eux fait il ils je la le les leur leurs
+		lui ma mais me meme mes moi mon ne ni nos notre nous on ont ou par pas plus pour
+		qu que qui sa sans se ses si son sont sous sur ta te tes toi ton tous tout toute
+		toutes tres tu un une vos votre vous`),
+	LanguageSpanish: wordSet(`
+		al algo algunos ante antes como con contra cual cuando de del desde donde durante
+		el ella ellas ellos en entre era es esa esas ese eso esos esta estaba estan estar
+		este esto estos fue ha hay la las le les lo los mas me mi mis mucho muy nada ni
+		no nos nosotros os otra otro para pero poco por porque que quien se sea ser si sin
+		sobre solo son su sus tambien te tiene tienen todo todos tu tus un una uno unos y
+		ya yo`),
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+func TestPorterStem(t *testing.T) {
+	tests := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"running":         "run",
+		"runs":            "run",
+		"hopping":         "hop",
+		"agreed":          "agre",
+		"happy":           "happi",
+		"relational":      "relat",
+		"conditional":     "condit",
+		"hopeful":         "hope",
+		"goodness":        "good",
+		"connection":      "connect",
+		"connections":     "connect",
+		"connected":       "connect",
+		"adjustable":      "adjust",
+		"generalizations": "gener",
+		"controll":        "control",
+		"as":              "as",
+	}
+	for word, want := range tests {
+		if got := Stem(word, LanguageEnglish); got != want {
+			t.Errorf("Stem(%q) = %q, expected %q", word, got, want)
+		}
+	}
+}
+
+func TestLightStemmers(t *testing.T) {
+	tests := []struct {
+		language Language
+		words    []string
+	}{
+		{LanguageGerman, []string{"haus", "hauser", "hauses"}},
+		{LanguageGerman, []string{"entwicklung", "entwicklungen"}},
+		{LanguageFrench, []string{"rapide", "rapides", "rapidement"}},
+		{LanguageFrench, []string{"maison", "maisons"}},
+		{LanguageSpanish, []string{"gato", "gatos", "gata"}},
+		{LanguageSpanish, []string{"nacion", "naciones"}},
+	}
+	for _, tt := range tests {
+		want := Stem(tt.words[0], tt.language)
+		for _, word := range tt.words[1:] {
+			if got := Stem(word, tt.language); got != want {
+				t.Errorf("%s: Stem(%q) = %q, expected %q like %q", tt.language, word, got, want, tt.words[0])
+			}
+		}
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := map[string]string{
+		"Café":      "cafe",
+		"ＣＡＦＥ":      "cafe",
+		"Straße":    "strasse",
+		"Œuvre":     "oeuvre",
+		"naïve ﬁle": "naive file",
+	}
+	for text, want := range tests {
+		if got := NormalizeText(text); got != want {
+			t.Errorf("NormalizeText(%q) = %q, expected %q", text, got, want)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]Language{
+		"The cat is sitting on the mat with its friends":     LanguageEnglish,
+		"Die Katze sitzt auf der Matte und schläft":          LanguageGerman,
+		"Le chat est assis sur le tapis avec ses amis":       LanguageFrench,
+		"El gato está sentado en la alfombra con sus amigos": LanguageSpanish,
+		"quantum entanglement":                               LanguageEnglish,
+		"Größenordnung":                                      LanguageGerman,
+	}
+	for text, want := range tests {
+		if got := DetectLanguage(text); got != want {
+			t.Errorf("DetectLanguage(%q) = %s, expected %s", text, got, want)
+		}
+	}
+}
+
+func TestAnalysisChain_Analyze(t *testing.T) {
+	analyzer := NewDefaultTextAnalyzer()
+
+	got := analyzer.Analyze("The runner was running, and runs again!")
+	want := []string{"runner", "run", "run", "again"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// German stop words are only dropped from German text
+	got = analyzer.Analyze("Die Häuser und das Haus")
+	want = []string{"haus", "haus"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAnalysisChain_CustomFilters(t *testing.T) {
+	tagLanguage := TokenFilterFunc(func(tokens []string, language Language) []string {
+		for i := range tokens {
+			tokens[i] = string(language) + ":" + tokens[i]
+		}
+		return tokens
+	})
+	analyzer := NewTextAnalyzer(NewAnalysisChain(LanguageFrench, MinLengthFilter(3), tagLanguage))
+
+	got := analyzer.Analyze("Ne-uf Éléphants")
+	want := []string{"fr:elephants"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestTextAnalyzer_ExtractKeywordsByLanguage(t *testing.T) {
+	analyzer := NewDefaultTextAnalyzer()
+
+	keywords := analyzer.ExtractKeywords("Die Katze und der Hund schlafen über dem Sofa")
+	set := make(map[string]bool)
+	for _, kw := range keywords {
+		set[kw] = true
+	}
+	for _, stop := range []string{"die", "und", "der", "über", "dem"} {
+		if set[stop] {
+			t.Errorf("German stop word %q should be dropped", stop)
+		}
+	}
+	for _, kw := range []string{"katze", "hund", "schlafen", "sofa"} {
+		if !set[kw] {
+			t.Errorf("expected keyword %q in %v", kw, keywords)
+		}
+	}
+}
+
+func TestHybridSimilarity_KeywordsMatchInflections(t *testing.T) {
+	newNode := func(title, body string) *entities.Node {
+		content, _ := valueobjects.NewNodeContent(title, body, valueobjects.FormatPlainText)
+		pos, _ := valueobjects.NewPosition2D(0, 0)
+		node, err := entities.NewNode("test-user", content, pos)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		return node
+	}
+	calc := NewHybridSimilarityCalculator(nil, nil)
+
+	a := newNode("Running", "training plans for runners")
+	b := newNode("Runs", "a training plan for a runner")
+	if sim := calc.Calculate(a, b); sim < 0.5 {
+		t.Errorf("inflections should share keywords, got similarity %f", sim)
+	}
+
+	keywords := map[string]bool{"running": true, "plans": true}
+	if sim := calc.CalculateWithKeywords(b, keywords, nil); sim == 0 {
+		t.Error("pre-extracted keywords should match inflections")
+	}
+}
@@ -16,28 +16,47 @@ type TextAnalyzer interface {
 	
 	// ExtractSignificantWords gets words above a certain length threshold
 	ExtractSignificantWords(text string, minLength int) []string
+	
+	// Analyze returns the index terms of text in order, with repeats:
+	// normalized, without stop words and stemmed, so that inflections of
+	// a word match each other
+	Analyze(text string) []string
 }
 
 // DefaultTextAnalyzer provides a default implementation of TextAnalyzer
 type DefaultTextAnalyzer struct {
-	stopWords map[string]bool
+	chain *AnalysisChain
 }
 
-// NewDefaultTextAnalyzer creates a new text analyzer with common English stop words
+// NewDefaultTextAnalyzer creates a new text analyzer with the default analysis
+// chain: stop words of the detected language and stemming
 func NewDefaultTextAnalyzer() *DefaultTextAnalyzer {
-	return &DefaultTextAnalyzer{
-		stopWords: getDefaultStopWords(),
+	return NewTextAnalyzer(DefaultAnalysisChain())
+}
+
+// NewTextAnalyzer creates a text analyzer whose Analyze runs chain
+func NewTextAnalyzer(chain *AnalysisChain) *DefaultTextAnalyzer {
+	if chain == nil {
+		chain = DefaultAnalysisChain()
 	}
+	return &DefaultTextAnalyzer{chain: chain}
 }
 
-// ExtractKeywords extracts meaningful keywords from text
+// Analyze returns the index terms of text
+func (ta *DefaultTextAnalyzer) Analyze(text string) []string {
+	return ta.chain.Analyze(text)
+}
+
+// ExtractKeywords extracts meaningful keywords from text. Keywords are
+// lowercase words as written, without the stop words of the text's language.
 func (ta *DefaultTextAnalyzer) ExtractKeywords(text string) []string {
 	words := ta.TokenizeWords(text)
+	stopWords := ta.stopWords(text)
 	keywords := make([]string, 0)
 	
 	for word := range words {
 		// Skip stop words and very short words
-		if !ta.stopWords[word] && len(word) > 2 {
+		if !stopWords[NormalizeText(word)] && len(word) > 2 {
 			keywords = append(keywords, word)
 		}
 	}
@@ -78,10 +97,11 @@ func (ta *DefaultTextAnalyzer) TokenizeWords(text string) map[string]bool {
 // ExtractSignificantWords gets words above a certain length threshold
 func (ta *DefaultTextAnalyzer) ExtractSignificantWords(text string, minLength int) []string {
 	words := ta.TokenizeWords(text)
+	stopWords := ta.stopWords(text)
 	significant := make([]string, 0)
 	
 	for word := range words {
-		if len(word) >= minLength && !ta.stopWords[word] {
+		if len(word) >= minLength && !stopWords[NormalizeText(word)] {
 			significant = append(significant, word)
 		}
 	}
@@ -89,6 +109,11 @@ func (ta *DefaultTextAnalyzer) ExtractSignificantWords(text string, minLength in
 	return significant
 }
 
+// stopWords returns the stop words of the language text is written in
+func (ta *DefaultTextAnalyzer) stopWords(text string) map[string]bool {
+	return stopWordLists[ta.chain.Language(text)]
+}
+
 // getDefaultStopWords returns a set of common English stop words
 func getDefaultStopWords() map[string]bool {
 	stopWords := map[string]bool{
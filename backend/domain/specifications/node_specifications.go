@@ -2,6 +2,8 @@ package specifications
 
 import (
 	"strings"
+	"time"
+	"unicode"
 
 	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
@@ -289,6 +291,163 @@ func (s *NodeMetadataSpec) evaluate(node *entities.Node) bool {
 	return true
 }
 
+// NodeTimeRangeSpec validates that a node timestamp falls within a range
+type NodeTimeRangeSpec struct {
+	BaseSpecification[*entities.Node]
+	timestamp func(*entities.Node) time.Time
+	from      time.Time // Inclusive; zero means unbounded
+	to        time.Time // Exclusive; zero means unbounded
+}
+
+// NewNodeCreatedBetweenSpec creates a specification for the node creation time
+func NewNodeCreatedBetweenSpec(from, to time.Time) *NodeTimeRangeSpec {
+	return newNodeTimeRangeSpec((*entities.Node).CreatedAt, from, to)
+}
+
+// NewNodeUpdatedBetweenSpec creates a specification for the node's last update time
+func NewNodeUpdatedBetweenSpec(from, to time.Time) *NodeTimeRangeSpec {
+	return newNodeTimeRangeSpec((*entities.Node).UpdatedAt, from, to)
+}
+
+func newNodeTimeRangeSpec(timestamp func(*entities.Node) time.Time, from, to time.Time) *NodeTimeRangeSpec {
+	spec := &NodeTimeRangeSpec{
+		timestamp: timestamp,
+		from:      from,
+		to:        to,
+	}
+	spec.BaseSpecification = BaseSpecification[*entities.Node]{
+		evaluator: spec.evaluate,
+	}
+	return spec
+}
+
+func (s *NodeTimeRangeSpec) evaluate(node *entities.Node) bool {
+	if node == nil {
+		return false
+	}
+
+	t := s.timestamp(node)
+	if !s.from.IsZero() && t.Before(s.from) {
+		return false
+	}
+	if !s.to.IsZero() && !t.Before(s.to) {
+		return false
+	}
+	return true
+}
+
+// NodeCommunitySpec validates that a node belongs to one of the given communities
+type NodeCommunitySpec struct {
+	BaseSpecification[*entities.Node]
+	communityIDs []string
+}
+
+// NewNodeCommunitySpec creates a specification for node community membership
+func NewNodeCommunitySpec(communityIDs ...string) *NodeCommunitySpec {
+	spec := &NodeCommunitySpec{
+		communityIDs: communityIDs,
+	}
+	spec.BaseSpecification = BaseSpecification[*entities.Node]{
+		evaluator: spec.evaluate,
+	}
+	return spec
+}
+
+func (s *NodeCommunitySpec) evaluate(node *entities.Node) bool {
+	if node == nil || node.CommunityID() == "" {
+		return false
+	}
+
+	for _, id := range s.communityIDs {
+		if node.CommunityID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeGraphSpec validates that a node belongs to one of the given graphs
+type NodeGraphSpec struct {
+	BaseSpecification[*entities.Node]
+	graphIDs []string
+}
+
+// NewNodeGraphSpec creates a specification for the node's graph
+func NewNodeGraphSpec(graphIDs ...string) *NodeGraphSpec {
+	spec := &NodeGraphSpec{
+		graphIDs: graphIDs,
+	}
+	spec.BaseSpecification = BaseSpecification[*entities.Node]{
+		evaluator: spec.evaluate,
+	}
+	return spec
+}
+
+func (s *NodeGraphSpec) evaluate(node *entities.Node) bool {
+	if node == nil {
+		return false
+	}
+
+	for _, id := range s.graphIDs {
+		if node.GraphID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeContainsPhraseSpec validates that a node's title or body contains a
+// phrase, comparing whole words case-insensitively and ignoring punctuation
+type NodeContainsPhraseSpec struct {
+	BaseSpecification[*entities.Node]
+	words []string
+}
+
+// NewNodeContainsPhraseSpec creates a specification for a phrase in the node content
+func NewNodeContainsPhraseSpec(phrase string) *NodeContainsPhraseSpec {
+	spec := &NodeContainsPhraseSpec{
+		words: phraseWords(phrase),
+	}
+	spec.BaseSpecification = BaseSpecification[*entities.Node]{
+		evaluator: spec.evaluate,
+	}
+	return spec
+}
+
+func (s *NodeContainsPhraseSpec) evaluate(node *entities.Node) bool {
+	if node == nil || len(s.words) == 0 {
+		return false
+	}
+
+	content := node.Content()
+	return containsWords(phraseWords(content.Title()), s.words) ||
+		containsWords(phraseWords(content.Body()), s.words)
+}
+
+// phraseWords splits text into lower case words
+func phraseWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether words contains phrase as a contiguous run
+func containsWords(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, w := range phrase {
+			if words[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
 // Common pre-configured specifications
 
 // NewValidNodeSpec creates a specification for a valid node
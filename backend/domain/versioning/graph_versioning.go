@@ -20,6 +20,7 @@ type GraphVersion struct {
 	EdgeCount   int       `json:"edge_count"`
 	CreatedAt   time.Time `json:"created_at"`
 	CreatedBy   string    `json:"created_by"`
+	Name        string    `json:"name,omitempty"`
 	Description string    `json:"description"`
 	Metadata    Metadata  `json:"metadata"`
 }
@@ -0,0 +1,340 @@
+package versioning
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+// GraphSnapshot is a stored graph version: the version record together with
+// the complete state of the graph's nodes and edges at that version
+type GraphSnapshot struct {
+	Version GraphVersion          `json:"version"`
+	Nodes   []entities.NodeBackup `json:"nodes"`
+	Edges   []*aggregates.Edge    `json:"edges"`
+}
+
+// SnapshotDiff is the difference between two graph snapshots, with one
+// Change per node or edge that was added, removed or updated
+type SnapshotDiff struct {
+	VersionDiff
+	Changes []Change `json:"changes"`
+}
+
+// CaptureState captures the current state of a graph. The snapshot is not
+// numbered; CreateSnapshot turns it into the next version of the graph.
+func (s *VersioningService) CaptureState(
+	graph *aggregates.Graph,
+	nodes []*entities.Node,
+	edges []*aggregates.Edge,
+) (*GraphSnapshot, error) {
+	if graph == nil {
+		return nil, fmt.Errorf("graph cannot be nil")
+	}
+
+	snapshot := &GraphSnapshot{
+		Version: GraphVersion{
+			GraphID:   graph.ID().String(),
+			NodeCount: len(nodes),
+			EdgeCount: len(edges),
+			CreatedAt: time.Now(),
+			Metadata: Metadata{
+				Tags:       []string{},
+				Properties: make(map[string]interface{}),
+				Changes:    []Change{},
+			},
+		},
+		Nodes: make([]entities.NodeBackup, 0, len(nodes)),
+		Edges: make([]*aggregates.Edge, 0, len(edges)),
+	}
+	for _, node := range nodes {
+		snapshot.Nodes = append(snapshot.Nodes, node.Backup())
+	}
+	sort.Slice(snapshot.Nodes, func(i, j int) bool {
+		return snapshot.Nodes[i].Snapshot.ID < snapshot.Nodes[j].Snapshot.ID
+	})
+	snapshot.Edges = append(snapshot.Edges, edges...)
+	sort.Slice(snapshot.Edges, func(i, j int) bool {
+		return EdgeKey(snapshot.Edges[i]) < EdgeKey(snapshot.Edges[j])
+	})
+
+	checksum, err := snapshotChecksum(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+	snapshot.Version.Checksum = checksum
+
+	return snapshot, nil
+}
+
+// CreateSnapshot captures the current state of a graph as the version
+// following previous, or as its first version when previous is nil. The
+// changes since previous are recorded in the version's metadata.
+func (s *VersioningService) CreateSnapshot(
+	graph *aggregates.Graph,
+	nodes []*entities.Node,
+	edges []*aggregates.Edge,
+	previous *GraphSnapshot,
+	userID string,
+	name string,
+	description string,
+) (*GraphSnapshot, error) {
+	snapshot, err := s.CaptureState(graph, nodes, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Version.Version = 1
+	snapshot.Version.CreatedBy = userID
+	snapshot.Version.Name = name
+	snapshot.Version.Description = description
+
+	if previous != nil {
+		snapshot.Version.Version = previous.Version.Version + 1
+		diff, err := s.DiffSnapshots(previous, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Version.Metadata.Changes = diff.Changes
+	}
+
+	return snapshot, nil
+}
+
+// DiffSnapshots compares two snapshots of the same graph node by node and
+// edge by edge. Nodes are matched by ID and edges by their endpoints.
+func (s *VersioningService) DiffSnapshots(from, to *GraphSnapshot) (*SnapshotDiff, error) {
+	if from == nil || to == nil {
+		return nil, fmt.Errorf("snapshots cannot be nil")
+	}
+	if from.Version.GraphID != to.Version.GraphID {
+		return nil, fmt.Errorf("snapshots belong to different graphs")
+	}
+
+	diff := &SnapshotDiff{
+		VersionDiff: VersionDiff{
+			FromVersion: from.Version.Version,
+			ToVersion:   to.Version.Version,
+			TimeDiff:    to.Version.CreatedAt.Sub(from.Version.CreatedAt),
+		},
+		Changes: []Change{},
+	}
+	at := to.Version.CreatedAt
+	record := func(changeType ChangeType, entityID, description string) {
+		diff.Changes = append(diff.Changes, Change{
+			Type:        changeType,
+			EntityID:    entityID,
+			Description: description,
+			Timestamp:   at,
+		})
+	}
+
+	fromNodes := make(map[string]entities.NodeBackup, len(from.Nodes))
+	for _, node := range from.Nodes {
+		fromNodes[node.Snapshot.ID] = node
+	}
+	toNodes := make(map[string]entities.NodeBackup, len(to.Nodes))
+	for _, node := range to.Nodes {
+		toNodes[node.Snapshot.ID] = node
+	}
+
+	for _, id := range sortedKeys(fromNodes) {
+		if _, ok := toNodes[id]; !ok {
+			diff.NodesDiff.Removed++
+			record(ChangeTypeNodeRemoved, id, fmt.Sprintf("Removed node %q", fromNodes[id].Snapshot.Content.Title()))
+		}
+	}
+	for _, id := range sortedKeys(toNodes) {
+		node := toNodes[id]
+		previous, ok := fromNodes[id]
+		if !ok {
+			diff.NodesDiff.Added++
+			record(ChangeTypeNodeAdded, id, fmt.Sprintf("Added node %q", node.Snapshot.Content.Title()))
+			continue
+		}
+		if changed := ChangedNodeFields(previous, node); len(changed) > 0 {
+			diff.NodesDiff.Updated++
+			record(ChangeTypeNodeUpdated, id, fmt.Sprintf("Updated %s of node %q", strings.Join(changed, ", "), node.Snapshot.Content.Title()))
+		}
+	}
+
+	fromEdges := make(map[string]*aggregates.Edge, len(from.Edges))
+	for _, edge := range from.Edges {
+		fromEdges[EdgeKey(edge)] = edge
+	}
+	toEdges := make(map[string]*aggregates.Edge, len(to.Edges))
+	for _, edge := range to.Edges {
+		toEdges[EdgeKey(edge)] = edge
+	}
+
+	for _, key := range sortedKeys(fromEdges) {
+		if _, ok := toEdges[key]; !ok {
+			diff.EdgesDiff.Removed++
+			record(ChangeTypeEdgeRemoved, key, fmt.Sprintf("Removed %s edge", fromEdges[key].Type))
+		}
+	}
+	for _, key := range sortedKeys(toEdges) {
+		edge := toEdges[key]
+		previous, ok := fromEdges[key]
+		if !ok {
+			diff.EdgesDiff.Added++
+			record(ChangeTypeEdgeAdded, key, fmt.Sprintf("Added %s edge", edge.Type))
+			continue
+		}
+		if EdgeChanged(previous, edge) {
+			diff.EdgesDiff.Updated++
+			record(ChangeTypeEdgeUpdated, key, fmt.Sprintf("Updated %s edge (weight %.2f)", edge.Type, edge.Weight))
+		}
+	}
+
+	return diff, nil
+}
+
+// ChangedNodeFields lists the parts of a node that differ between two backups
+// of it. Versions, timestamps and derived attributes such as the embedding are
+// not compared.
+func ChangedNodeFields(from, to entities.NodeBackup) []string {
+	changed := make([]string, 0)
+	if !from.Snapshot.Content.Equals(to.Snapshot.Content) {
+		changed = append(changed, "content")
+	}
+	if !from.Snapshot.Position.Equals(to.Snapshot.Position) {
+		changed = append(changed, "position")
+	}
+	if from.Snapshot.Status != to.Snapshot.Status {
+		changed = append(changed, "status")
+	}
+	if !sameStrings(from.Snapshot.Tags, to.Snapshot.Tags) {
+		changed = append(changed, "tags")
+	}
+	if !sameMetadata(from.Metadata, to.Metadata) {
+		changed = append(changed, "metadata")
+	}
+	return changed
+}
+
+// EdgeChanged reports whether two edges between the same nodes differ
+func EdgeChanged(from, to *aggregates.Edge) bool {
+	return from.Type != to.Type || from.Weight != to.Weight || from.Bidirectional != to.Bidirectional
+}
+
+// EdgeKey identifies an edge within a graph by its endpoints
+func EdgeKey(edge *aggregates.Edge) string {
+	return fmt.Sprintf("%s->%s", edge.SourceID.String(), edge.TargetID.String())
+}
+
+// VersionsToPrune returns the versions beyond the retention limit, which are
+// all but the newest maxVersions; nothing is pruned when the limit is not
+// positive
+func (s *VersioningService) VersionsToPrune(versions []*GraphVersion) []*GraphVersion {
+	if s.maxVersions <= 0 || len(versions) <= s.maxVersions {
+		return nil
+	}
+	ordered := append([]*GraphVersion{}, versions...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Version > ordered[j].Version
+	})
+	return ordered[s.maxVersions:]
+}
+
+// snapshotChecksum hashes the compared state of a snapshot, so two snapshots
+// of an unchanged graph have the same checksum
+func snapshotChecksum(snapshot *GraphSnapshot) (string, error) {
+	type nodeState struct {
+		ID      string                   `json:"id"`
+		Content valueobjects.NodeContent `json:"content"`
+		Pos     valueobjects.Position    `json:"position"`
+		Status  entities.NodeStatus      `json:"status"`
+		Tags    []string                 `json:"tags"`
+		Meta    entities.Metadata        `json:"metadata"`
+	}
+	type edgeState struct {
+		Key           string            `json:"key"`
+		Type          entities.EdgeType `json:"type"`
+		Weight        float64           `json:"weight"`
+		Bidirectional bool              `json:"bidirectional"`
+	}
+
+	data := struct {
+		GraphID string      `json:"graph_id"`
+		Nodes   []nodeState `json:"nodes"`
+		Edges   []edgeState `json:"edges"`
+	}{GraphID: snapshot.Version.GraphID}
+	for _, node := range snapshot.Nodes {
+		meta := node.Metadata
+		meta.Tags = nil
+		data.Nodes = append(data.Nodes, nodeState{
+			ID:      node.Snapshot.ID,
+			Content: node.Snapshot.Content,
+			Pos:     node.Snapshot.Position,
+			Status:  node.Snapshot.Status,
+			Tags:    sortedStrings(node.Snapshot.Tags),
+			Meta:    meta,
+		})
+	}
+	for _, edge := range snapshot.Edges {
+		data.Edges = append(data.Edges, edgeState{
+			Key:           EdgeKey(edge),
+			Type:          edge.Type,
+			Weight:        edge.Weight,
+			Bidirectional: edge.Bidirectional,
+		})
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(jsonData)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// sameMetadata compares node metadata apart from tags, which are compared on
+// their own. Stored snapshots have been through JSON, so both sides are
+// compared in encoded form.
+func sameMetadata(a, b entities.Metadata) bool {
+	a.Tags, b.Tags = nil, nil
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(encodedA, encodedB)
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA, sortedB := sortedStrings(a), sortedStrings(b)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -20,6 +20,7 @@ type EdgeCreationConfig struct {
 
 // EmbeddingConfig holds configuration for the embedding service.
 type EmbeddingConfig struct {
+	Provider   string  // One of the EmbeddingProvider* constants
 	BaseURL    string  // OpenAI-compatible endpoint (e.g. "https://api.openai.com/v1")
 	APIKey     string  // API key (empty for local endpoints like Ollama)
 	Model      string  // Model name (e.g. "text-embedding-3-small")
@@ -27,6 +28,98 @@ type EmbeddingConfig struct {
 	Enabled    bool    // Whether embedding generation is active
 }
 
+// ChatConfig holds configuration for the chat completion client that answers
+// questions about a user's notes.
+type ChatConfig struct {
+	BaseURL        string // OpenAI-compatible endpoint (e.g. "https://api.openai.com/v1")
+	APIKey         string // API key (empty for local endpoints like Ollama)
+	Model          string // Model name (e.g. "gpt-4o-mini")
+	TimeoutSeconds int    // Upper bound of one streamed answer
+	Enabled        bool   // Whether POST /ask is served
+}
+
+// AskConfig tunes the retrieval behind POST /ask.
+type AskConfig struct {
+	Candidates    int     // Search results a question starts from
+	Hops          int     // Edges followed from each search result, 0 to 2
+	MinEdgeWeight float64 // Weakest edge followed
+	ContextTokens int     // Estimated token budget of the notes sent to the model
+	AnswerTokens  int     // Upper bound of the answer length
+}
+
+// Embedding providers supported by the embedding service provider.
+const (
+	// EmbeddingProviderOpenAI calls an OpenAI-compatible /v1/embeddings
+	// endpoint (the default).
+	EmbeddingProviderOpenAI = "openai"
+	// EmbeddingProviderLocal embeds text in process with hashed n-grams; it
+	// needs no network and gives deterministic vectors.
+	EmbeddingProviderLocal = "local"
+)
+
+// Persistence backends supported by the repository providers.
+const (
+	// PersistenceDynamoDB stores all aggregates in DynamoDB (the default).
+	PersistenceDynamoDB = "dynamodb"
+	// PersistenceFile stores all aggregates in a single local file, for
+	// running the API and worker without AWS access.
+	PersistenceFile = "file"
+	// PersistenceMemory keeps all aggregates in process memory; data is lost
+	// on exit. Meant for tests and throwaway local runs.
+	PersistenceMemory = "memory"
+)
+
+// PersistenceConfig selects and configures the storage backend.
+type PersistenceConfig struct {
+	Backend  string // One of the Persistence* constants
+	FilePath string // Data file used by the file backend
+
+	// EventSourced loads nodes and graphs by replaying their event streams
+	// on top of the latest snapshot instead of reading the state tables
+	EventSourced bool
+	// SnapshotInterval is the number of replayed events after which an
+	// event-sourced load writes a fresh snapshot
+	SnapshotInterval int
+}
+
+// OutboxConfig controls delivery of stored events from the outbox
+type OutboxConfig struct {
+	// RelayEnabled makes the worker publish DynamoDB outbox events to the
+	// event bus. The file backend always relays to local handlers.
+	RelayEnabled bool
+	BatchSize    int // Events fetched per poll
+	MaxAttempts  int // Failed attempts before an event is dead-lettered
+
+	PollIntervalSeconds int
+	BaseBackoffSeconds  int // Delay after the first failure, doubled after each further one
+	MaxBackoffSeconds   int // Upper bound of the retry delay
+
+	// LegacySweep makes DynamoDB outbox polls also pick up pending events
+	// written before the outbox index existed. It can be turned off once a
+	// sweep has completed.
+	LegacySweep bool
+}
+
+// VectorIndexConfig tunes the approximate nearest-neighbour index used for
+// semantic search
+type VectorIndexConfig struct {
+	Enabled        bool // Query the index instead of scanning every embedding
+	M              int  // Links per node on each graph layer
+	EfConstruction int  // Candidate list size while inserting
+	EfSearch       int  // Candidate list size while searching
+
+	SyncIntervalSeconds int // How often a loaded index is reconciled with the stored nodes
+	SaveEvery           int // Changes after which an index is persisted
+}
+
+// KeywordIndexConfig tunes the inverted index used for BM25 keyword search
+type KeywordIndexConfig struct {
+	Enabled bool // Read postings from the index instead of tokenizing every node
+
+	SyncIntervalSeconds int // How often a loaded index is reconciled with the stored nodes
+	SaveEvery           int // Changes after which an index is persisted
+}
+
 // Features holds feature flags for the application
 type Features struct {
 	// EnableSagaOrchestrator enables saga pattern for complex operations
@@ -53,6 +146,9 @@ type Config struct {
 	GSI2IndexName string // GSI2 - for direct NodeID lookups
 	GSI3IndexName string // GSI3 - for target node lookups in edges
 	GSI4IndexName string // GSI4 - for tag-based queries
+	GSI5IndexName string // GSI5 - for recently updated nodes and graphs
+	GSI6IndexName string // GSI6 - sparse index of public graphs
+	GSI7IndexName string // GSI7 - sparse index of undelivered outbox events
 	EventBusName  string
 
 	// Lambda configuration
@@ -83,21 +179,46 @@ type Config struct {
 	// Embedding configuration
 	Embedding EmbeddingConfig
 
+	// Chat completion and question answering configuration
+	Chat ChatConfig
+	Ask  AskConfig
+
+	// Persistence configuration
+	Persistence PersistenceConfig
+
+	// Outbox delivery configuration
+	Outbox OutboxConfig
+
+	// Vector index configuration
+	VectorIndex VectorIndexConfig
+
+	// Keyword index configuration
+	KeywordIndex KeywordIndexConfig
+
 	// Feature flags
 	Features Features
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
+	embeddingProvider := getEnv("EMBEDDING_PROVIDER", EmbeddingProviderOpenAI)
+	embeddingModel, embeddingDimensions := "text-embedding-3-small", 1536
+	if embeddingProvider == EmbeddingProviderLocal {
+		embeddingModel, embeddingDimensions = "local-hashed-ngrams-v1", 384
+	}
+
 	cfg := &Config{
 		ServerAddress: getEnv("SERVER_ADDRESS", ":8080"),
 		Environment:   getEnv("ENVIRONMENT", "development"),
 		AWSRegion:     getEnv("AWS_REGION", "us-west-2"),
 		DynamoDBTable: getEnv("TABLE_NAME", getEnv("DYNAMODB_TABLE", "brain2")),
-		IndexName:     getEnv("INDEX_NAME", "KeywordIndex"),         // GSI1
-		GSI2IndexName: getEnv("GSI2_INDEX_NAME", "EdgeIndex"),       // GSI2 - Used for both node and edge lookups
-		GSI3IndexName: getEnv("GSI3_INDEX_NAME", "TargetNodeIndex"), // GSI3 - For target node lookups
-		GSI4IndexName: getEnv("GSI4_INDEX_NAME", "TagIndex"),        // GSI4 - For tag-based queries
+		IndexName:     getEnv("INDEX_NAME", "KeywordIndex"),          // GSI1
+		GSI2IndexName: getEnv("GSI2_INDEX_NAME", "EdgeIndex"),        // GSI2 - Used for both node and edge lookups
+		GSI3IndexName: getEnv("GSI3_INDEX_NAME", "TargetNodeIndex"),  // GSI3 - For target node lookups
+		GSI4IndexName: getEnv("GSI4_INDEX_NAME", "TagIndex"),         // GSI4 - For tag-based queries
+		GSI5IndexName: getEnv("GSI5_INDEX_NAME", "ActivityIndex"),    // GSI5 - For recency-ordered queries
+		GSI6IndexName: getEnv("GSI6_INDEX_NAME", "PublicGraphIndex"), // GSI6 - For public graph listings
+		GSI7IndexName: getEnv("GSI7_INDEX_NAME", "OutboxIndex"),      // GSI7 - For the outbox queue
 		EventBusName:  getEnv("EVENT_BUS_NAME", "brain2-events"),
 
 		// Lambda configuration
@@ -130,13 +251,68 @@ func LoadConfig() (*Config, error) {
 
 		// Embedding configuration
 		Embedding: EmbeddingConfig{
+			Provider:   embeddingProvider,
 			BaseURL:    getEnv("EMBEDDING_BASE_URL", "https://api.openai.com/v1"),
 			APIKey:     getEnv("EMBEDDING_API_KEY", ""),
-			Model:      getEnv("EMBEDDING_MODEL", "text-embedding-3-small"),
-			Dimensions: getEnvInt("EMBEDDING_DIMENSIONS", 1536),
+			Model:      getEnv("EMBEDDING_MODEL", embeddingModel),
+			Dimensions: getEnvInt("EMBEDDING_DIMENSIONS", embeddingDimensions),
 			Enabled:    getEnvBool("EMBEDDING_ENABLED", false),
 		},
 
+		// Chat completion configuration
+		Chat: ChatConfig{
+			BaseURL:        getEnv("CHAT_BASE_URL", "https://api.openai.com/v1"),
+			APIKey:         getEnv("CHAT_API_KEY", ""),
+			Model:          getEnv("CHAT_MODEL", "gpt-4o-mini"),
+			TimeoutSeconds: getEnvInt("CHAT_TIMEOUT_SECONDS", 120),
+			Enabled:        getEnvBool("CHAT_ENABLED", false),
+		},
+
+		// Question answering configuration
+		Ask: AskConfig{
+			Candidates:    getEnvInt("ASK_CANDIDATES", 8),
+			Hops:          getEnvInt("ASK_HOPS", 1),
+			MinEdgeWeight: getEnvFloat("ASK_MIN_EDGE_WEIGHT", 0.5),
+			ContextTokens: getEnvInt("ASK_CONTEXT_TOKENS", 3000),
+			AnswerTokens:  getEnvInt("ASK_ANSWER_TOKENS", 800),
+		},
+
+		// Persistence configuration
+		Persistence: PersistenceConfig{
+			Backend:          getEnv("PERSISTENCE_BACKEND", PersistenceDynamoDB),
+			FilePath:         getEnv("PERSISTENCE_FILE_PATH", "./data/brain2.db"),
+			EventSourced:     getEnvBool("PERSISTENCE_EVENT_SOURCED", false),
+			SnapshotInterval: getEnvInt("PERSISTENCE_SNAPSHOT_INTERVAL", 50),
+		},
+
+		// Outbox delivery configuration
+		Outbox: OutboxConfig{
+			RelayEnabled:        getEnvBool("OUTBOX_RELAY_ENABLED", false),
+			BatchSize:           getEnvInt("OUTBOX_BATCH_SIZE", 50),
+			MaxAttempts:         getEnvInt("OUTBOX_MAX_ATTEMPTS", 8),
+			PollIntervalSeconds: getEnvInt("OUTBOX_POLL_INTERVAL_SECONDS", 5),
+			BaseBackoffSeconds:  getEnvInt("OUTBOX_BASE_BACKOFF_SECONDS", 5),
+			MaxBackoffSeconds:   getEnvInt("OUTBOX_MAX_BACKOFF_SECONDS", 900),
+			LegacySweep:         getEnvBool("OUTBOX_LEGACY_SWEEP", true),
+		},
+
+		// Vector index configuration
+		VectorIndex: VectorIndexConfig{
+			Enabled:             getEnvBool("VECTOR_INDEX_ENABLED", true),
+			M:                   getEnvInt("VECTOR_INDEX_M", 16),
+			EfConstruction:      getEnvInt("VECTOR_INDEX_EF_CONSTRUCTION", 200),
+			EfSearch:            getEnvInt("VECTOR_INDEX_EF_SEARCH", 64),
+			SyncIntervalSeconds: getEnvInt("VECTOR_INDEX_SYNC_INTERVAL_SECONDS", 300),
+			SaveEvery:           getEnvInt("VECTOR_INDEX_SAVE_EVERY", 100),
+		},
+
+		// Keyword index configuration
+		KeywordIndex: KeywordIndexConfig{
+			Enabled:             getEnvBool("KEYWORD_INDEX_ENABLED", true),
+			SyncIntervalSeconds: getEnvInt("KEYWORD_INDEX_SYNC_INTERVAL_SECONDS", 900),
+			SaveEvery:           getEnvInt("KEYWORD_INDEX_SAVE_EVERY", 100),
+		},
+
 		// Feature flags
 		Features: Features{
 			EnableSagaOrchestrator: true, // Deprecated toggle – saga handler is always enabled
@@ -161,6 +337,28 @@ func Load() (*Config, error) {
 
 // Validate checks if all required configuration is present
 func (c *Config) Validate() error {
+	switch c.Persistence.Backend {
+	case "", PersistenceDynamoDB:
+	case PersistenceFile:
+		if c.Persistence.FilePath == "" {
+			return fmt.Errorf("PERSISTENCE_FILE_PATH is required for the file backend")
+		}
+	case PersistenceMemory:
+	default:
+		return fmt.Errorf("unknown PERSISTENCE_BACKEND: %s", c.Persistence.Backend)
+	}
+	switch c.Embedding.Provider {
+	case "", EmbeddingProviderOpenAI, EmbeddingProviderLocal:
+	default:
+		return fmt.Errorf("unknown EMBEDDING_PROVIDER: %s", c.Embedding.Provider)
+	}
+	if c.Ask.Hops < 0 || c.Ask.Hops > 2 {
+		return fmt.Errorf("ASK_HOPS must be between 0 and 2")
+	}
+	if c.Persistence.EventSourced && c.Persistence.SnapshotInterval <= 0 {
+		return fmt.Errorf("PERSISTENCE_SNAPSHOT_INTERVAL must be positive")
+	}
+
 	if c.Environment == "production" {
 		if c.JWTSecret == "" {
 			return fmt.Errorf("JWT_SECRET is required in production")
@@ -186,6 +384,11 @@ func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
 
+// UsesDynamoDB reports whether repositories are backed by DynamoDB.
+func (c *Config) UsesDynamoDB() bool {
+	return c.Persistence.Backend == "" || c.Persistence.Backend == PersistenceDynamoDB
+}
+
 // getEnv gets an environment variable with a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
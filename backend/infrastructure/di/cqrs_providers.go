@@ -1,6 +1,7 @@
 package di
 
 import (
+	"fmt"
 	"time"
 
 	appevents "backend/application/events"
@@ -10,7 +11,15 @@ import (
 	"backend/application/projections"
 	commandbus "backend/application/commands/bus"
 	querybus "backend/application/queries/bus"
+	"backend/application/services"
+	domainservices "backend/domain/services"
+	"backend/infrastructure/config"
+	"backend/infrastructure/persistence/dynamodb"
+	"backend/infrastructure/persistence/filestore"
+	"backend/infrastructure/persistence/memory"
 	"backend/pkg/observability"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +36,57 @@ func ProvideOperationEventListener(
 	return listeners.NewOperationEventListener(operationStore, logger)
 }
 
+// ProvideVectorIndexListener creates the listener keeping the vector index in
+// sync with node events, or nil when the index is disabled
+func ProvideVectorIndexListener(
+	vectorIndex *services.VectorIndexService,
+	nodeRepo ports.NodeRepository,
+	logger *zap.Logger,
+) *listeners.VectorIndexListener {
+	if vectorIndex == nil {
+		return nil
+	}
+	return listeners.NewVectorIndexListener(vectorIndex, nodeRepo, logger)
+}
+
+// ProvideKeywordIndexListener creates the listener keeping the keyword index
+// in sync with node events, or nil when the index is disabled
+func ProvideKeywordIndexListener(
+	keywordIndex *services.KeywordIndexService,
+	nodeRepo ports.NodeRepository,
+	logger *zap.Logger,
+) *listeners.KeywordIndexListener {
+	if keywordIndex == nil {
+		return nil
+	}
+	return listeners.NewKeywordIndexListener(keywordIndex, nodeRepo, logger)
+}
+
+// ProvideEmbeddingListener creates the listener embedding new and edited
+// nodes in process, or nil unless embeddings are computed locally. Remote
+// providers are called by the embed-node Lambda instead.
+func ProvideEmbeddingListener(
+	embeddingService domainservices.EmbeddingService,
+	nodeRepo ports.NodeRepository,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *listeners.EmbeddingListener {
+	if embeddingService == nil || cfg.Embedding.Provider != config.EmbeddingProviderLocal {
+		return nil
+	}
+	return listeners.NewEmbeddingListener(embeddingService, nodeRepo, logger)
+}
+
+// ProvideCommunityListener creates the listener keeping stored communities in
+// sync with node and edge events
+func ProvideCommunityListener(
+	communityService *services.CommunityDetectionService,
+	nodeRepo ports.NodeRepository,
+	logger *zap.Logger,
+) *listeners.CommunityListener {
+	return listeners.NewCommunityListener(communityService, nodeRepo, logger)
+}
+
 // ProvideGraphStatsProjection creates the graph statistics projection
 func ProvideGraphStatsProjection(
 	cache ports.Cache,
@@ -73,6 +133,10 @@ func ProvideMediator(
 func WireEventHandlers(
 	registry *appevents.HandlerRegistry,
 	operationListener *listeners.OperationEventListener,
+	embeddingListener *listeners.EmbeddingListener,
+	vectorIndexListener *listeners.VectorIndexListener,
+	keywordIndexListener *listeners.KeywordIndexListener,
+	communityListener *listeners.CommunityListener,
 	graphStatsProjection *projections.GraphStatsProjection,
 	logger *zap.Logger,
 ) error {
@@ -82,19 +146,82 @@ func WireEventHandlers(
 		return err
 	}
 	
-	// Register graph statistics projection
-	// Use the actual event type strings returned by GetEventType()
-	eventTypes := []string{
-		"node.created.with.pending.edges", // From TypeNodeCreatedWithPending
-		"NodeDeleted",                      // From NodeDeletedEvent
-		"BulkNodesDeleted",                 // From BulkNodesDeletedEvent
+	// Subscribe embedding listener (nil unless embeddings are computed locally)
+	if embeddingListener != nil {
+		if err := embeddingListener.Subscribe(registry); err != nil {
+			return err
+		}
+	}
+	
+	// Subscribe vector index listener (nil when the index is disabled)
+	if vectorIndexListener != nil {
+		if err := vectorIndexListener.Subscribe(registry); err != nil {
+			return err
+		}
 	}
 	
-	if err := registry.Register(eventTypes, graphStatsProjection); err != nil {
+	// Subscribe keyword index listener (nil when the index is disabled)
+	if keywordIndexListener != nil {
+		if err := keywordIndexListener.Subscribe(registry); err != nil {
+			return err
+		}
+	}
+	
+	// Subscribe community listener
+	if err := communityListener.Subscribe(registry); err != nil {
+		return err
+	}
+	
+	// Register graph statistics projection
+	if err := registry.Register(graphStatsProjection.GetEventTypes(), graphStatsProjection); err != nil {
 		logger.Error("Failed to register graph stats projection", zap.Error(err))
 		return err
 	}
 	
 	logger.Info("Event handlers and projections wired successfully")
 	return nil
-}
\ No newline at end of file
+}
+// ProvideCheckpointStore creates the durable projection checkpoint store
+func ProvideCheckpointStore(
+	client *awsdynamodb.Client,
+	store *filestore.Store,
+	memDB *memory.InMemoryDatabase,
+	cfg *config.Config,
+) projections.CheckpointStore {
+	if memDB != nil {
+		return memory.NewInMemoryCheckpointStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewCheckpointStore(store)
+	}
+	return dynamodb.NewCheckpointStore(client, cfg.DynamoDBTable)
+}
+
+// ProvideProjectionRegistry creates the projection registry with all
+// replayable projections registered
+func ProvideProjectionRegistry(
+	checkpointStore projections.CheckpointStore,
+	graphStatsProjection *projections.GraphStatsProjection,
+	logger *zap.Logger,
+) (*projections.ProjectionRegistry, error) {
+	registry := projections.NewProjectionRegistry(checkpointStore, logger)
+	if err := registry.Register(graphStatsProjection); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// ProvideProjectionReplayer creates the replayer that rebuilds projections
+// from the event log
+func ProvideProjectionReplayer(
+	registry *projections.ProjectionRegistry,
+	eventStore ports.EventStore,
+	checkpointStore projections.CheckpointStore,
+	logger *zap.Logger,
+) (*projections.ProjectionReplayer, error) {
+	stream, ok := eventStore.(ports.EventStream)
+	if !ok {
+		return nil, fmt.Errorf("event store does not support reading the event log")
+	}
+	return projections.NewProjectionReplayer(registry, stream, checkpointStore, logger), nil
+}
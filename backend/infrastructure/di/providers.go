@@ -14,12 +14,19 @@ import (
 	querybus "backend/application/queries/bus"
 	queries_handlers "backend/application/queries/handlers"
 	"backend/application/services"
+	domainconfig "backend/domain/config"
 	"backend/domain/events"
 	domainservices "backend/domain/services"
+	"backend/domain/versioning"
 	"backend/infrastructure/config"
 	"backend/infrastructure/embeddings"
+	"backend/infrastructure/llm"
+	"backend/infrastructure/messaging"
 	"backend/infrastructure/messaging/eventbridge"
 	"backend/infrastructure/persistence/dynamodb"
+	"backend/infrastructure/persistence/eventsourced"
+	"backend/infrastructure/persistence/filestore"
+	"backend/infrastructure/persistence/memory"
 	"backend/interfaces/http/rest/middleware"
 	"backend/pkg/auth"
 	"backend/pkg/errors"
@@ -74,13 +81,59 @@ func ProvideEventBridgeClient(awsCfg aws.Config) *awseventbridge.Client {
 	return awseventbridge.NewFromConfig(awsCfg)
 }
 
-// ProvideNodeRepository creates a node repository
-func ProvideNodeRepository(client *awsdynamodb.Client, cfg *config.Config, logger *zap.Logger) ports.NodeRepository {
-	return dynamodb.NewNodeRepository(
-		client,
-		cfg.DynamoDBTable,
-		cfg.IndexName,     // GSI1 for user-level queries
-		cfg.GSI2IndexName, // GSI2 for direct NodeID lookups
+// ProvideFileStore opens the embedded data file when the file persistence
+// backend is selected; it returns nil when DynamoDB is in use
+func ProvideFileStore(cfg *config.Config, logger *zap.Logger) (*filestore.Store, error) {
+	if cfg.UsesDynamoDB() {
+		return nil, nil
+	}
+	store, err := filestore.Open(cfg.Persistence.FilePath, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file store: %w", err)
+	}
+	logger.Info("Using file persistence backend", zap.String("path", store.Path()))
+	return store, nil
+}
+
+// ProvideMemoryDatabase creates the shared in-memory database when the memory
+// persistence backend is selected; it returns nil otherwise
+func ProvideMemoryDatabase(cfg *config.Config, logger *zap.Logger) *memory.InMemoryDatabase {
+	if cfg.Persistence.Backend != config.PersistenceMemory {
+		return nil
+	}
+	logger.Warn("Using in-memory persistence backend; data will be lost on exit")
+	return memory.NewInMemoryDatabase()
+}
+
+// ProvideNodeRepository creates a node repository. With event sourcing
+// enabled the state repository is wrapped so that nodes are rebuilt from
+// their event streams.
+func ProvideNodeRepository(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, eventStore ports.EventStore, cfg *config.Config, logger *zap.Logger) ports.NodeRepository {
+	var nodeRepo ports.NodeRepository
+	switch {
+	case memDB != nil:
+		nodeRepo = memory.NewInMemoryNodeRepository(memDB)
+	case store != nil:
+		nodeRepo = filestore.NewNodeRepository(store, logger)
+	default:
+		nodeRepo = dynamodb.NewNodeRepository(
+			client,
+			cfg.DynamoDBTable,
+			cfg.IndexName,     // GSI1 for user-level queries
+			cfg.GSI2IndexName, // GSI2 for direct NodeID lookups
+			cfg.GSI5IndexName, // GSI5 for recently updated nodes
+			logger,
+		)
+	}
+
+	if !cfg.Persistence.EventSourced {
+		return nodeRepo
+	}
+	return eventsourced.NewNodeRepository(
+		nodeRepo,
+		eventStore,
+		snapshotStore(eventStore),
+		cfg.Persistence.SnapshotInterval,
 		logger,
 	)
 }
@@ -88,32 +141,85 @@ func ProvideNodeRepository(client *awsdynamodb.Client, cfg *config.Config, logge
 // ProvideGraphRepository creates a graph repository
 func ProvideGraphRepository(
 	client *awsdynamodb.Client,
+	store *filestore.Store,
+	memDB *memory.InMemoryDatabase,
 	nodeRepo ports.NodeRepository,
 	edgeRepo ports.EdgeRepository,
+	eventStore ports.EventStore,
 	cfg *config.Config,
 	logger *zap.Logger,
 ) ports.GraphRepository {
-	graphRepo := dynamodb.NewGraphRepository(
-		client,
-		cfg.DynamoDBTable,
-		logger,
-	)
+	// The state repositories join the stored nodes, not the event-sourced ones
+	stateNodeRepo := nodeRepo
+	if esNodeRepo, ok := nodeRepo.(*eventsourced.NodeRepository); ok {
+		stateNodeRepo = esNodeRepo.Unwrap()
+	}
+
+	var graphRepo ports.GraphRepository
+	switch {
+	case memDB != nil:
+		graphRepo = memory.NewInMemoryGraphRepository(
+			memDB,
+			stateNodeRepo.(*memory.InMemoryNodeRepository),
+			edgeRepo.(*memory.InMemoryEdgeRepository),
+		)
+	case store != nil:
+		graphRepo = filestore.NewGraphRepository(
+			store,
+			stateNodeRepo.(*filestore.NodeRepository),
+			edgeRepo.(*filestore.EdgeRepository),
+			logger,
+		)
+	default:
+		graphRepo = dynamodb.NewGraphRepository(
+			client,
+			cfg.DynamoDBTable,
+			cfg.GSI5IndexName, // GSI5 for recently updated graphs
+			cfg.GSI6IndexName, // GSI6 for public graphs
+			logger,
+		)
 
-	// Set the edge repository for saving edges
-	if gr, ok := graphRepo.(*dynamodb.GraphRepository); ok {
-		gr.SetEdgeRepository(edgeRepo)
-		gr.SetNodeRepository(nodeRepo)
+		// Set the edge repository for saving edges
+		if gr, ok := graphRepo.(*dynamodb.GraphRepository); ok {
+			gr.SetEdgeRepository(edgeRepo)
+			gr.SetNodeRepository(stateNodeRepo)
+		}
 	}
 
-	return graphRepo
+	esNodeRepo, ok := nodeRepo.(*eventsourced.NodeRepository)
+	if !ok {
+		return graphRepo
+	}
+	return eventsourced.NewGraphRepository(
+		graphRepo,
+		esNodeRepo,
+		eventStore,
+		snapshotStore(eventStore),
+		cfg.Persistence.SnapshotInterval,
+		logger,
+	)
+}
+
+// snapshotStore returns the event store's snapshot support, if any
+func snapshotStore(eventStore ports.EventStore) ports.SnapshotStore {
+	snapshots, _ := eventStore.(ports.SnapshotStore)
+	return snapshots
 }
 
 // ProvideEdgeRepository creates an edge repository
 func ProvideEdgeRepository(
 	client *awsdynamodb.Client,
+	store *filestore.Store,
+	memDB *memory.InMemoryDatabase,
 	cfg *config.Config,
 	logger *zap.Logger,
 ) ports.EdgeRepository {
+	if memDB != nil {
+		return memory.NewInMemoryEdgeRepository(memDB)
+	}
+	if store != nil {
+		return filestore.NewEdgeRepository(store, logger)
+	}
 	return dynamodb.NewEdgeRepository(
 		client,
 		cfg.DynamoDBTable,
@@ -142,8 +248,12 @@ func ProvideGraphLazyService(
 	return services.NewGraphLazyService(nodeRepo, edgeRepo, cfg, logger)
 }
 
-// ProvideEventBus creates an event bus
+// ProvideEventBus creates an event bus. Without DynamoDB the service runs
+// offline, so events stay in-process instead of going to EventBridge.
 func ProvideEventBus(client *awseventbridge.Client, cfg *config.Config, logger *zap.Logger) ports.EventBus {
+	if !cfg.UsesDynamoDB() {
+		return messaging.NewLocalEventBus(logger)
+	}
 	return eventbridge.NewEventBridgePublisher(
 		client,
 		cfg.EventBusName,
@@ -212,12 +322,32 @@ func ProvideAuthMiddleware(cfg *config.Config, logger *zap.Logger) (func(http.Ha
 // ProvideUnitOfWork creates a unit of work for transactions
 func ProvideUnitOfWork(
 	client *awsdynamodb.Client,
+	store *filestore.Store,
+	memDB *memory.InMemoryDatabase,
 	nodeRepo ports.NodeRepository,
 	edgeRepo ports.EdgeRepository,
 	graphRepo ports.GraphRepository,
 	eventStore ports.EventStore,
 	eventPublisher ports.EventPublisher,
 ) ports.UnitOfWork {
+	if memDB != nil {
+		return memory.NewInMemoryUnitOfWork(
+			memDB,
+			nodeRepo,
+			edgeRepo,
+			graphRepo,
+			eventStore.(*memory.InMemoryEventStore),
+		)
+	}
+	if store != nil {
+		return filestore.NewUnitOfWork(
+			store,
+			nodeRepo,
+			edgeRepo,
+			graphRepo,
+			eventStore.(*filestore.EventStore),
+		)
+	}
 	return dynamodb.NewDynamoDBUnitOfWork(
 		client,
 		nodeRepo,
@@ -229,9 +359,42 @@ func ProvideUnitOfWork(
 }
 
 // ProvideEventStore creates an event store
-func ProvideEventStore(client *awsdynamodb.Client, cfg *config.Config) ports.EventStore {
+func ProvideEventStore(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.EventStore {
+	if memDB != nil {
+		return memory.NewInMemoryEventStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewEventStore(store)
+	}
 	// Use a separate table for events or the same table with different keys
-	return dynamodb.NewDynamoDBEventStore(client, cfg.DynamoDBTable)
+	eventStore := dynamodb.NewDynamoDBEventStore(client, cfg.DynamoDBTable).
+		WithOutboxIndex(cfg.GSI7IndexName). // GSI7 for undelivered events
+		WithLegacySweep(cfg.Outbox.LegacySweep)
+	if cfg.Persistence.EventSourced {
+		// Replays need the full history, so events must not expire
+		eventStore.WithEventTTL(0)
+	}
+	return eventStore
+}
+
+// ProvideOutbox exposes the event store's outbox; nil for the memory backend,
+// which publishes through the local event bus only
+func ProvideOutbox(eventStore ports.EventStore) ports.Outbox {
+	if outbox, ok := eventStore.(ports.Outbox); ok {
+		return outbox
+	}
+	return nil
+}
+
+// OutboxProcessorConfig converts the outbox settings for the outbox processor
+func OutboxProcessorConfig(cfg *config.Config) messaging.OutboxConfig {
+	return messaging.OutboxConfig{
+		BatchSize:    cfg.Outbox.BatchSize,
+		PollInterval: time.Duration(cfg.Outbox.PollIntervalSeconds) * time.Second,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		BaseBackoff:  time.Duration(cfg.Outbox.BaseBackoffSeconds) * time.Second,
+		MaxBackoff:   time.Duration(cfg.Outbox.MaxBackoffSeconds) * time.Second,
+	}
 }
 
 // ProvideCloudWatchClient creates a CloudWatch client
@@ -239,9 +402,12 @@ func ProvideCloudWatchClient(awsCfg aws.Config) *awscloudwatch.Client {
 	return awscloudwatch.NewFromConfig(awsCfg)
 }
 
-// ProvideMetrics creates metrics instance
+// ProvideMetrics creates metrics instance; metrics are not sent when running offline
 func ProvideMetrics(client *awscloudwatch.Client, cfg *config.Config) *observability.Metrics {
 	namespace := fmt.Sprintf("Brain2/%s", cfg.Environment)
+	if !cfg.UsesDynamoDB() {
+		return observability.NewMetrics(namespace, nil)
+	}
 	return observability.NewMetrics(namespace, client)
 }
 
@@ -259,14 +425,186 @@ func ProvideDistributedRateLimiter(client *awsdynamodb.Client, cfg *config.Confi
 }
 
 // ProvideDistributedLock creates a distributed lock instance
-func ProvideDistributedLock(client *awsdynamodb.Client, cfg *config.Config, logger *zap.Logger) *dynamodb.DistributedLock {
+func ProvideDistributedLock(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config, logger *zap.Logger) ports.DistributedLock {
+	if memDB != nil {
+		return memory.NewInMemoryDistributedLock()
+	}
+	if store != nil {
+		return filestore.NewDistributedLock(store, logger)
+	}
 	return dynamodb.NewDistributedLock(client, cfg.DynamoDBTable, logger)
 }
 
+// ProvideDomainConfig loads the business rules for the configured environment
+func ProvideDomainConfig(cfg *config.Config) *domainconfig.DomainConfig {
+	return domainconfig.LoadDomainConfig(cfg.Environment)
+}
+
+// ProvideTrashStore creates the store backing the trash bin
+func ProvideTrashStore(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.TrashStore {
+	if memDB != nil {
+		return memory.NewInMemoryTrashStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewTrashStore(store)
+	}
+	return dynamodb.NewTrashStore(client, cfg.DynamoDBTable)
+}
+
+// ProvideTrashService creates the trash bin service; trashed nodes are purged
+// once the domain's NodeTTL has passed
+func ProvideTrashService(
+	trash ports.TrashStore,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	eventStore ports.EventStore,
+	eventBus ports.EventBus,
+	domainCfg *domainconfig.DomainConfig,
+	logger *zap.Logger,
+) *services.TrashService {
+	return services.NewTrashService(trash, nodeRepo, edgeRepo, graphRepo, eventStore, eventBus, domainCfg, logger)
+}
+
+// ProvideGraphVersionStore creates the store backing persisted graph versions
+func ProvideGraphVersionStore(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.GraphVersionStore {
+	if memDB != nil {
+		return memory.NewInMemoryGraphVersionStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewGraphVersionStore(store)
+	}
+	return dynamodb.NewGraphVersionStore(client, cfg.DynamoDBTable)
+}
+
+// ProvideVersioningService creates the graph versioning service with the
+// default versioning policy
+func ProvideVersioningService() *versioning.VersioningService {
+	policy := versioning.DefaultVersioningPolicy()
+	return versioning.NewVersioningService(policy.MaxVersions, policy.AutoVersion)
+}
+
+// ProvideGraphVersionService creates the service recording and comparing
+// graph versions
+func ProvideGraphVersionService(
+	store ports.GraphVersionStore,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	versioningService *versioning.VersioningService,
+	logger *zap.Logger,
+) *services.GraphVersionService {
+	return services.NewGraphVersionService(store, nodeRepo, edgeRepo, graphRepo, versioningService, logger)
+}
+
+// ProvideVectorIndexStore creates the store persisting each user's vector index
+func ProvideVectorIndexStore(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.VectorIndexStore {
+	if memDB != nil {
+		return memory.NewInMemoryVectorIndexStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewVectorIndexStore(store)
+	}
+	return dynamodb.NewVectorIndexStore(client, cfg.DynamoDBTable)
+}
+
+// ProvideVectorIndexService creates the nearest-neighbour index over node
+// embeddings. Returns nil when the index is disabled, in which case semantic
+// search compares the query with every embedding.
+func ProvideVectorIndexService(
+	store ports.VectorIndexStore,
+	nodeRepo ports.NodeRepository,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *services.VectorIndexService {
+	if !cfg.VectorIndex.Enabled {
+		logger.Info("Vector index disabled")
+		return nil
+	}
+	return services.NewVectorIndexService(store, nodeRepo, &services.VectorIndexConfig{
+		HNSW: &domainservices.HNSWConfig{
+			M:              cfg.VectorIndex.M,
+			EfConstruction: cfg.VectorIndex.EfConstruction,
+			EfSearch:       cfg.VectorIndex.EfSearch,
+		},
+		SyncInterval: time.Duration(cfg.VectorIndex.SyncIntervalSeconds) * time.Second,
+		SaveEvery:    cfg.VectorIndex.SaveEvery,
+		Model:        cfg.Embedding.Model,
+	}, logger)
+}
+
+// ProvideKeywordIndexStore creates the store persisting each user's keyword index
+func ProvideKeywordIndexStore(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.KeywordIndexStore {
+	if memDB != nil {
+		return memory.NewInMemoryKeywordIndexStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewKeywordIndexStore(store)
+	}
+	return dynamodb.NewKeywordIndexStore(client, cfg.DynamoDBTable)
+}
+
+// ProvideKeywordIndexService creates the inverted index over node text.
+// Returns nil when the index is disabled, in which case keyword search
+// tokenizes every node.
+func ProvideKeywordIndexService(
+	store ports.KeywordIndexStore,
+	nodeRepo ports.NodeRepository,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *services.KeywordIndexService {
+	if !cfg.KeywordIndex.Enabled {
+		logger.Info("Keyword index disabled")
+		return nil
+	}
+	return services.NewKeywordIndexService(store, nodeRepo, &services.KeywordIndexConfig{
+		SyncInterval: time.Duration(cfg.KeywordIndex.SyncIntervalSeconds) * time.Second,
+		SaveEvery:    cfg.KeywordIndex.SaveEvery,
+	}, logger)
+}
+
+// ProvideEmbeddingService creates the embedding service selected by
+// EMBEDDING_PROVIDER for batch work such as re-embedding. Returns nil when
+// embedding is disabled.
+func ProvideEmbeddingService(cfg *config.Config, logger *zap.Logger) domainservices.EmbeddingService {
+	return newEmbeddingService(cfg, 64, 30*time.Second, logger)
+}
+
+// newEmbeddingService creates the configured embedding service; batchSize and
+// timeout apply to remote providers. Returns nil when embedding is disabled
+// or the remote provider has no endpoint.
+func newEmbeddingService(cfg *config.Config, batchSize int, timeout time.Duration, logger *zap.Logger) domainservices.EmbeddingService {
+	if !cfg.Embedding.Enabled {
+		return nil
+	}
+	if cfg.Embedding.Provider == config.EmbeddingProviderLocal {
+		return embeddings.NewLocalService(&embeddings.LocalConfig{
+			Model:      cfg.Embedding.Model,
+			Dimensions: cfg.Embedding.Dimensions,
+		})
+	}
+	if cfg.Embedding.BaseURL == "" {
+		return nil
+	}
+	return embeddings.NewOpenAICompatibleService(
+		&embeddings.OpenAICompatibleConfig{
+			BaseURL:    cfg.Embedding.BaseURL,
+			APIKey:     cfg.Embedding.APIKey,
+			Model:      cfg.Embedding.Model,
+			Dimensions: cfg.Embedding.Dimensions,
+			BatchSize:  batchSize,
+			Timeout:    timeout,
+		},
+		logger,
+	)
+}
+
 // ProvideHybridSearchService creates a hybrid search service for BM25 + semantic search.
 // If embedding is disabled in config, semantic search is skipped (BM25-only).
 func ProvideHybridSearchService(
 	nodeRepo ports.NodeRepository,
+	vectorIndex *services.VectorIndexService,
+	keywordIndex *services.KeywordIndexService,
 	cfg *config.Config,
 	logger *zap.Logger,
 ) *services.HybridSearchService {
@@ -274,28 +612,92 @@ func ProvideHybridSearchService(
 	bm25 := domainservices.NewBM25Scorer(textAnalyzer)
 
 	// Embedding service is optional — search degrades to BM25-only when nil.
-	// For the API server, we create it if embedding is enabled in config.
-	var embeddingService domainservices.EmbeddingService
-	if cfg.Embedding.Enabled && cfg.Embedding.BaseURL != "" {
-		embeddingService = embeddings.NewOpenAICompatibleService(
-			&embeddings.OpenAICompatibleConfig{
-				BaseURL:    cfg.Embedding.BaseURL,
-				APIKey:     cfg.Embedding.APIKey,
-				Model:      cfg.Embedding.Model,
-				Dimensions: cfg.Embedding.Dimensions,
-				BatchSize:  1, // Search only embeds one query at a time
-				Timeout:    10 * time.Second,
-			},
-			logger,
-		)
+	// Search only embeds one query at a time.
+	embeddingService := newEmbeddingService(cfg, 1, 10*time.Second, logger)
+	if embeddingService != nil {
 		logger.Info("Hybrid search: semantic search enabled",
+			zap.String("provider", cfg.Embedding.Provider),
 			zap.String("model", cfg.Embedding.Model),
 		)
 	} else {
 		logger.Info("Hybrid search: semantic search disabled, using BM25-only")
 	}
 
-	return services.NewHybridSearchService(bm25, embeddingService, textAnalyzer, nodeRepo, nil)
+	searchService := services.NewHybridSearchService(bm25, embeddingService, textAnalyzer, nodeRepo, nil)
+	if vectorIndex != nil {
+		searchService.WithVectorIndex(vectorIndex)
+	}
+	if keywordIndex != nil {
+		searchService.WithKeywordIndex(keywordIndex)
+	}
+	return searchService
+}
+
+// ProvideReembedService creates the job that re-embeds a user's nodes with
+// the configured embedding model. Returns nil when embedding is disabled.
+func ProvideReembedService(
+	nodeRepo ports.NodeRepository,
+	embeddingService domainservices.EmbeddingService,
+	operationStore ports.OperationStore,
+	vectorIndex *services.VectorIndexService,
+	logger *zap.Logger,
+) *services.ReembedService {
+	if embeddingService == nil {
+		return nil
+	}
+	reembedService := services.NewReembedService(nodeRepo, embeddingService, operationStore, nil, logger)
+	if vectorIndex != nil {
+		reembedService.WithVectorIndex(vectorIndex)
+	}
+	return reembedService
+}
+
+// ProvideChatClient creates the chat completion client answering questions.
+// Returns nil when chat is disabled.
+func ProvideChatClient(cfg *config.Config, logger *zap.Logger) ports.ChatClient {
+	if !cfg.Chat.Enabled || cfg.Chat.BaseURL == "" {
+		return nil
+	}
+	return llm.NewOpenAICompatibleChatClient(&llm.OpenAICompatibleChatConfig{
+		BaseURL: cfg.Chat.BaseURL,
+		APIKey:  cfg.Chat.APIKey,
+		Model:   cfg.Chat.Model,
+		Timeout: time.Duration(cfg.Chat.TimeoutSeconds) * time.Second,
+	}, logger)
+}
+
+// ProvideAskService creates the service answering questions about a user's
+// notes. Returns nil when there is no chat client.
+func ProvideAskService(
+	searchService *services.HybridSearchService,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	chat ports.ChatClient,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *services.AskService {
+	if chat == nil {
+		logger.Info("Ask disabled: no chat model configured")
+		return nil
+	}
+	askConfig := services.DefaultAskConfig()
+	askConfig.Candidates = cfg.Ask.Candidates
+	askConfig.Hops = cfg.Ask.Hops
+	askConfig.MinEdgeWeight = cfg.Ask.MinEdgeWeight
+	askConfig.ContextTokens = cfg.Ask.ContextTokens
+	askConfig.AnswerTokens = cfg.Ask.AnswerTokens
+	return services.NewAskService(searchService, nodeRepo, edgeRepo, chat, askConfig, logger)
+}
+
+// ProvideCommunityRepository creates the repository storing detected communities
+func ProvideCommunityRepository(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.CommunityRepository {
+	if memDB != nil {
+		return memory.NewInMemoryCommunityRepository(memDB)
+	}
+	if store != nil {
+		return filestore.NewCommunityRepository(store)
+	}
+	return dynamodb.NewCommunityRepository(client, cfg.DynamoDBTable)
 }
 
 // ProvideCommunityDetectionService creates the Leiden-based community detection service.
@@ -303,9 +705,35 @@ func ProvideCommunityDetectionService(
 	graphRepo ports.GraphRepository,
 	nodeRepo ports.NodeRepository,
 	edgeRepo ports.EdgeRepository,
+	communityRepo ports.CommunityRepository,
 	logger *zap.Logger,
 ) *services.CommunityDetectionService {
-	return services.NewCommunityDetectionService(graphRepo, nodeRepo, edgeRepo, logger)
+	return services.NewCommunityDetectionService(graphRepo, nodeRepo, edgeRepo, communityRepo, logger)
+}
+
+// ProvideLinkSuggestionStore creates the store backing link suggestions and feedback
+func ProvideLinkSuggestionStore(client *awsdynamodb.Client, store *filestore.Store, memDB *memory.InMemoryDatabase, cfg *config.Config) ports.LinkSuggestionStore {
+	if memDB != nil {
+		return memory.NewInMemoryLinkSuggestionStore(memDB)
+	}
+	if store != nil {
+		return filestore.NewLinkSuggestionStore(store)
+	}
+	return dynamodb.NewLinkSuggestionStore(client, cfg.DynamoDBTable)
+}
+
+// ProvideLinkPredictionService creates the link suggestion service; accepted
+// suggestions are turned into edges through the command bus
+func ProvideLinkPredictionService(
+	store ports.LinkSuggestionStore,
+	graphRepo ports.GraphRepository,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	communityRepo ports.CommunityRepository,
+	commandBus *bus.CommandBus,
+	logger *zap.Logger,
+) *services.LinkPredictionService {
+	return services.NewLinkPredictionService(store, graphRepo, nodeRepo, edgeRepo, communityRepo, commandBus, logger)
 }
 
 // ProvideAnalysisService creates an AnalysisService for thought chains and impact analysis.
@@ -345,9 +773,12 @@ func ProvideCommandBus(
 	eventStore ports.EventStore,
 	eventBus ports.EventBus,
 	eventPublisher ports.EventPublisher,
-	distributedLock *dynamodb.DistributedLock,
+	distributedLock ports.DistributedLock,
+	trashService *services.TrashService,
+	graphVersionService *services.GraphVersionService,
 	metrics *observability.Metrics,
 	cfg *config.Config,
+	domainCfg *domainconfig.DomainConfig,
 	logger *zap.Logger,
 ) *bus.CommandBus {
 	// Create command bus with dependencies
@@ -379,7 +810,7 @@ func ProvideCommandBus(
 		&cfg.EdgeCreation,
 		cfg,
 		logger,
-	)
+	).WithNodeLimit(domainCfg.MaxNodesPerGraph)
 
 	// Register CreateNodeCommand with the selected handler
 	commandBus.Register(commands.CreateNodeCommand{}, &CommandHandlerAdapter{
@@ -406,7 +837,8 @@ func ProvideCommandBus(
 	})
 
 	// Register DeleteNodeCommand handler
-	deleteNodeHandler := commands_handlers.NewDeleteNodeHandler(nodeRepo, edgeRepo, graphRepo, eventStore, eventBus, logger)
+	deleteNodeHandler := commands_handlers.NewDeleteNodeHandler(nodeRepo, edgeRepo, graphRepo, eventStore, eventBus, logger).
+		WithTrash(trashService)
 	commandBus.Register(commands.DeleteNodeCommand{}, &CommandHandlerAdapter{
 		handler: func(ctx context.Context, cmd bus.Command) error {
 			deleteCmd, ok := cmd.(commands.DeleteNodeCommand)
@@ -418,7 +850,8 @@ func ProvideCommandBus(
 	})
 
 	// Register BulkDeleteNodesCommand handler (now returns void per CQRS)
-	bulkDeleteHandler := commands_handlers.NewBulkDeleteNodesHandler(uow, nodeRepo, edgeRepo, graphRepo, eventStore, eventBus, logger)
+	bulkDeleteHandler := commands_handlers.NewBulkDeleteNodesHandler(uow, nodeRepo, edgeRepo, graphRepo, eventStore, eventBus, logger).
+		WithTrash(trashService)
 
 	if err := commandBus.Register(commands.BulkDeleteNodesCommand{}, &CommandHandlerAdapter{
 		handler: func(ctx context.Context, cmd bus.Command) error {
@@ -444,6 +877,64 @@ func ProvideCommandBus(
 		},
 	})
 
+	// Register RollbackGraphCommand handler
+	rollbackGraphHandler := commands_handlers.NewRollbackGraphHandler(graphVersionService, nodeRepo, edgeRepo, graphRepo, eventBus, distributedLock, logger)
+	commandBus.Register(commands.RollbackGraphCommand{}, &CommandHandlerAdapter{
+		handler: func(ctx context.Context, cmd bus.Command) error {
+			rollbackCmd, ok := cmd.(commands.RollbackGraphCommand)
+			if !ok {
+				return fmt.Errorf("invalid command type")
+			}
+			return rollbackGraphHandler.Handle(ctx, rollbackCmd)
+		},
+	})
+
+	// Register graph management command handlers
+	createGraphHandler := commands_handlers.NewCreateGraphHandler(graphRepo, eventBus, domainCfg, logger)
+	commandBus.Register(commands.CreateGraphCommand{}, &CommandHandlerAdapter{
+		handler: func(ctx context.Context, cmd bus.Command) error {
+			graphCmd, ok := cmd.(commands.CreateGraphCommand)
+			if !ok {
+				return fmt.Errorf("invalid command type")
+			}
+			return createGraphHandler.Handle(ctx, graphCmd)
+		},
+	})
+
+	updateGraphHandler := commands_handlers.NewUpdateGraphHandler(graphRepo, eventBus, logger)
+	commandBus.Register(commands.UpdateGraphCommand{}, &CommandHandlerAdapter{
+		handler: func(ctx context.Context, cmd bus.Command) error {
+			graphCmd, ok := cmd.(commands.UpdateGraphCommand)
+			if !ok {
+				return fmt.Errorf("invalid command type")
+			}
+			return updateGraphHandler.Handle(ctx, graphCmd)
+		},
+	})
+
+	deleteGraphHandler := commands_handlers.NewDeleteGraphHandler(nodeRepo, edgeRepo, graphRepo, graphVersionService, eventBus, distributedLock, logger).
+		WithTrash(trashService)
+	commandBus.Register(commands.DeleteGraphCommand{}, &CommandHandlerAdapter{
+		handler: func(ctx context.Context, cmd bus.Command) error {
+			graphCmd, ok := cmd.(commands.DeleteGraphCommand)
+			if !ok {
+				return fmt.Errorf("invalid command type")
+			}
+			return deleteGraphHandler.Handle(ctx, graphCmd)
+		},
+	})
+
+	moveNodesHandler := commands_handlers.NewMoveNodesToGraphHandler(nodeRepo, edgeRepo, graphRepo, eventBus, distributedLock, domainCfg.MaxNodesPerGraph, logger)
+	commandBus.Register(commands.MoveNodesToGraphCommand{}, &CommandHandlerAdapter{
+		handler: func(ctx context.Context, cmd bus.Command) error {
+			moveCmd, ok := cmd.(commands.MoveNodesToGraphCommand)
+			if !ok {
+				return fmt.Errorf("invalid command type")
+			}
+			return moveNodesHandler.Handle(ctx, moveCmd)
+		},
+	})
+
 	// Register CleanupNodeResourcesCommand handler
 	cleanupHandler := commands_handlers.NewCleanupNodeResourcesHandler()
 	commandBus.Register(&commands.CleanupNodeResourcesCommand{}, &CommandHandlerAdapter{
@@ -473,9 +964,11 @@ func ProvideQueryBus(
 	graphRepo ports.GraphRepository,
 	nodeRepo ports.NodeRepository,
 	edgeRepo ports.EdgeRepository,
+	communityRepo ports.CommunityRepository,
 	cache ports.Cache,
 	operationStore ports.OperationStore,
 	searchService *services.HybridSearchService,
+	vectorIndex *services.VectorIndexService,
 	logger *zap.Logger,
 ) *querybus.QueryBus {
 	queryBus := querybus.NewQueryBus()
@@ -554,6 +1047,9 @@ func ProvideQueryBus(
 
 	// Register FindSimilarNodesQuery handler
 	findSimilarHandler := queries.NewFindSimilarNodesHandler(nodeRepo)
+	if vectorIndex != nil {
+		findSimilarHandler.WithVectorIndex(vectorIndex)
+	}
 	queryBus.Register(&queries.FindSimilarNodesQuery{}, &QueryHandlerAdapter{
 		handler: func(ctx context.Context, query querybus.Query) (interface{}, error) {
 			findQuery, ok := query.(*queries.FindSimilarNodesQuery)
@@ -564,6 +1060,18 @@ func ProvideQueryBus(
 		},
 	})
 
+	// Register GetRelatedNodesQuery handler
+	getRelatedHandler := queries.NewGetRelatedNodesHandler(nodeRepo, edgeRepo)
+	queryBus.Register(&queries.GetRelatedNodesQuery{}, &QueryHandlerAdapter{
+		handler: func(ctx context.Context, query querybus.Query) (interface{}, error) {
+			relatedQuery, ok := query.(*queries.GetRelatedNodesQuery)
+			if !ok {
+				return nil, fmt.Errorf("invalid query type")
+			}
+			return getRelatedHandler.Handle(ctx, relatedQuery)
+		},
+	})
+
 	// Register GetGraphStatsQuery handler
 	getGraphStatsHandler := queries_handlers.NewGetGraphStatsHandler(cache, graphRepo, nodeRepo, edgeRepo, logger)
 	queryBus.Register(queries.GetGraphStatsQuery{}, &QueryHandlerAdapter{
@@ -576,6 +1084,42 @@ func ProvideQueryBus(
 		},
 	})
 
+	// Register GetGraphAnalyticsQuery handler
+	getGraphAnalyticsHandler := queries_handlers.NewGetGraphAnalyticsHandler(cache, graphRepo, nodeRepo, edgeRepo, logger)
+	queryBus.Register(queries.GetGraphAnalyticsQuery{}, &QueryHandlerAdapter{
+		handler: func(ctx context.Context, query querybus.Query) (interface{}, error) {
+			analyticsQuery, ok := query.(queries.GetGraphAnalyticsQuery)
+			if !ok {
+				return nil, fmt.Errorf("invalid query type")
+			}
+			return getGraphAnalyticsHandler.Handle(ctx, analyticsQuery)
+		},
+	})
+
+	// Register GenerateBrainReportQuery handler
+	brainReportHandler := queries_handlers.NewGenerateBrainReportHandler(cache, graphRepo, nodeRepo, edgeRepo, communityRepo, logger)
+	queryBus.Register(queries.GenerateBrainReportQuery{}, &QueryHandlerAdapter{
+		handler: func(ctx context.Context, query querybus.Query) (interface{}, error) {
+			reportQuery, ok := query.(queries.GenerateBrainReportQuery)
+			if !ok {
+				return nil, fmt.Errorf("invalid query type")
+			}
+			return brainReportHandler.Handle(ctx, reportQuery)
+		},
+	})
+
+	// Register FindPathsQuery handler
+	findPathsHandler := queries_handlers.NewFindPathsHandler(graphRepo, nodeRepo, edgeRepo, logger)
+	queryBus.Register(queries.FindPathsQuery{}, &QueryHandlerAdapter{
+		handler: func(ctx context.Context, query querybus.Query) (interface{}, error) {
+			pathsQuery, ok := query.(queries.FindPathsQuery)
+			if !ok {
+				return nil, fmt.Errorf("invalid query type")
+			}
+			return findPathsHandler.Handle(ctx, pathsQuery)
+		},
+	})
+
 	// Register HybridSearchQuery handler
 	hybridSearchHandler := queries.NewHybridSearchHandler(searchService)
 	queryBus.Register(&queries.HybridSearchQuery{}, &QueryHandlerAdapter{
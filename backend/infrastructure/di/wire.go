@@ -15,6 +15,7 @@ import (
 	"backend/application/projections"
 	querybus "backend/application/queries/bus"
 	"backend/application/services"
+	domainservices "backend/domain/services"
 	"backend/infrastructure/config"
 	"backend/pkg/auth"
 	"backend/pkg/errors"
@@ -33,6 +34,7 @@ type Container struct {
 	EdgeRepo               ports.EdgeRepository
 	EventBus               ports.EventBus
 	EventStore             ports.EventStore
+	Outbox                 ports.Outbox
 	UnitOfWork             ports.UnitOfWork
 	CommandBus             *bus.CommandBus
 	QueryBus               *querybus.QueryBus
@@ -43,11 +45,26 @@ type Container struct {
 	Mediator               *mediator.Mediator
 	EventHandlerRegistry   *appevents.HandlerRegistry
 	OperationEventListener *listeners.OperationEventListener
+	EmbeddingListener      *listeners.EmbeddingListener
+	VectorIndexListener    *listeners.VectorIndexListener
+	KeywordIndexListener   *listeners.KeywordIndexListener
+	CommunityListener      *listeners.CommunityListener
 	GraphStatsProjection   *projections.GraphStatsProjection
+	CheckpointStore        projections.CheckpointStore
+	ProjectionRegistry     *projections.ProjectionRegistry
+	ProjectionReplayer     *projections.ProjectionReplayer
 	GraphLazyService       *services.GraphLazyService
 	GraphLoader            *services.GraphLoader
 	CommunityService       *services.CommunityDetectionService
 	AnalysisService        *services.AnalysisService
+	TrashService           *services.TrashService
+	GraphVersionService    *services.GraphVersionService
+	VectorIndexService     *services.VectorIndexService
+	KeywordIndexService    *services.KeywordIndexService
+	EmbeddingService       domainservices.EmbeddingService
+	ReembedService         *services.ReembedService
+	AskService             *services.AskService
+	LinkPredictionService  *services.LinkPredictionService
 	AuthMiddleware         func(http.Handler) http.Handler
 }
 
@@ -69,19 +86,26 @@ var SuperSet = wire.NewSet(
     ProvideInMemoryCache, // leaf
     ProvideOperationStore, // leaf (in-memory)
 
+    // Embedded data file, only opened when PERSISTENCE_BACKEND=file (nil otherwise)
+    ProvideFileStore, // deps: config, logger
+    // Shared in-memory database, only created when PERSISTENCE_BACKEND=memory (nil otherwise)
+    ProvideMemoryDatabase, // deps: config, logger
+
     // 4) Infra utilities
     // Both depend on DynamoDB client + cfg; lock also logs
     ProvideDistributedRateLimiter, // deps: dynamodb client, config
-    ProvideDistributedLock,        // deps: dynamodb client, config, logger
+    ProvideDistributedLock,        // deps: dynamodb client, file store or memory database, config, logger
 
     // 5) Persistence layer (repos, event store)
+    // Each provider switches to the memory database or file store when it is non-nil.
     // Repositories needing DynamoDB client + config + logger:
-    ProvideNodeRepository, // deps: dynamodb client, config (table/index), logger
-    ProvideEdgeRepository, // deps: dynamodb client, config (table/index), logger
+    ProvideNodeRepository, // deps: dynamodb client, file store or memory database, event store, config (table/index, event sourcing), logger
+    ProvideEdgeRepository, // deps: dynamodb client, file store or memory database, config (table/index), logger
     // Graph repository additionally wires NodeRepo + EdgeRepo for aggregate saves:
-    ProvideGraphRepository, // deps: dynamodb client, node repo, edge repo, config, logger
+    ProvideGraphRepository, // deps: dynamodb client, file store or memory database, node repo, edge repo, event store, config, logger
     // Event store uses DynamoDB to persist outbox events
-    ProvideEventStore,      // deps: dynamodb client, config (table)
+    ProvideEventStore,      // deps: dynamodb client, file store or memory database, config (table, outbox index)
+    ProvideOutbox,          // deps: event store
 
     // 6) Messaging and metrics
     // Event bus and metrics (AWS clients + cfg + logger)
@@ -91,27 +115,51 @@ var SuperSet = wire.NewSet(
 
     // 7) Unit of Work (placed after event publisher for readability)
     // Coordinates transactional writes and outbox publishing
-    ProvideUnitOfWork,      // deps: dynamodb client, node/edge/graph repos, event store, event publisher
+    ProvideUnitOfWork,      // deps: dynamodb client, file store or memory database, node/edge/graph repos, event store, event publisher
 
     // 8) Application services
     // Services depending on repos + cfg + logger
     ProvideGraphLazyService,    // deps: node repo, edge repo, config, logger
     ProvideGraphLoader,         // deps: graph repo, node repo, edge repo, logger
     ProvideEdgeService,         // deps: node repo, graph repo, edge repo, cfg.EdgeCreation, logger
-    ProvideHybridSearchService,         // deps: node repo, config, logger
-    ProvideCommunityDetectionService,   // deps: graph repo, node repo, edge repo, logger
+    ProvideVectorIndexStore,            // deps: dynamodb client, file store, memory db, cfg
+    ProvideVectorIndexService,          // deps: vector index store, node repo, config, logger (nil when disabled)
+    ProvideKeywordIndexStore,           // deps: dynamodb client, file store, memory db, cfg
+    ProvideKeywordIndexService,         // deps: keyword index store, node repo, config, logger (nil when disabled)
+    ProvideHybridSearchService,         // deps: node repo, vector and keyword index services, config, logger
+    ProvideEmbeddingService,            // deps: config, logger (nil when disabled)
+    ProvideReembedService,              // deps: node repo, embedding service, operation store, vector index service, logger (nil when disabled)
+    ProvideChatClient,                  // deps: config, logger (nil when disabled)
+    ProvideAskService,                  // deps: hybrid search service, node/edge repos, chat client, config, logger (nil without chat client)
+    ProvideCommunityRepository,         // deps: dynamodb client, file store, memory db, cfg
+    ProvideCommunityDetectionService,   // deps: graph repo, node repo, edge repo, community repo, logger
+    ProvideLinkSuggestionStore,         // deps: dynamodb client, file store, memory db, cfg
+    ProvideLinkPredictionService,       // deps: link suggestion store, graph/node/edge/community repos, command bus, logger
     ProvideAnalysisService,             // deps: graph repo, node repo, edge repo, logger
+    ProvideDomainConfig,                // deps: cfg (environment)
+    ProvideTrashStore,                  // deps: dynamodb client, file store, memory db, cfg
+    ProvideTrashService,                // deps: trash store, node/edge/graph repos, event store, event bus, domain config, logger
+    ProvideGraphVersionStore,           // deps: dynamodb client, file store, memory db, cfg
+    ProvideVersioningService,           // leaf (default versioning policy)
+    ProvideGraphVersionService,         // deps: graph version store, node/edge/graph repos, versioning service, logger
 
     // 9) CQRS buses and mediator
     // Command bus wires handlers requiring many deps (UoW, repos, services, events)
-    ProvideCommandBus, // deps: uow, node/edge/graph repos, graph lazy service, event store, event bus/publisher, distributed lock, metrics, cfg, logger
-    ProvideQueryBus,   // deps: graph/node/edge repos, cache, operation store, logger
+    ProvideCommandBus, // deps: uow, node/edge/graph repos, graph lazy service, event store, event bus/publisher, distributed lock, trash service, graph version service, metrics, cfg, logger
+    ProvideQueryBus,   // deps: graph/node/edge/community repos, cache, operation store, search service, vector index service, logger
     ProvideMediator,   // deps: command bus, query bus, metrics, logger
 
     // 10) Event handlers and projections
     ProvideEventHandlerRegistry,   // deps: logger
     ProvideOperationEventListener, // deps: operation store, logger
+    ProvideEmbeddingListener,      // deps: embedding service, node repo, config, logger (nil unless local)
+    ProvideVectorIndexListener,    // deps: vector index service, node repo, logger
+    ProvideKeywordIndexListener,   // deps: keyword index service, node repo, logger
+    ProvideCommunityListener,      // deps: community detection service, node repo, logger
     ProvideGraphStatsProjection,   // deps: cache, logger
+    ProvideCheckpointStore,        // deps: dynamodb client, file store, memory db, cfg
+    ProvideProjectionRegistry,     // deps: checkpoint store, graph stats projection, logger
+    ProvideProjectionReplayer,     // deps: projection registry, event store, checkpoint store, logger
 
     // 11) HTTP
     ProvideAuthMiddleware, // deps: cfg, logger
@@ -15,6 +15,7 @@ import (
 	"backend/application/projections"
 	bus2 "backend/application/queries/bus"
 	"backend/application/services"
+	services2 "backend/domain/services"
 	"backend/infrastructure/config"
 	"backend/pkg/auth"
 	"backend/pkg/errors"
@@ -39,30 +40,66 @@ func InitializeContainer(ctx context.Context, cfg *config.Config) (*Container, e
 		return nil, err
 	}
 	client := ProvideDynamoDBClient(awsConfig)
-	nodeRepository := ProvideNodeRepository(client, cfg, logger)
-	edgeRepository := ProvideEdgeRepository(client, cfg, logger)
-	graphRepository := ProvideGraphRepository(client, nodeRepository, edgeRepository, cfg, logger)
+	store, err := ProvideFileStore(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	inMemoryDatabase := ProvideMemoryDatabase(cfg, logger)
+	eventStore := ProvideEventStore(client, store, inMemoryDatabase, cfg)
+	outbox := ProvideOutbox(eventStore)
+	nodeRepository := ProvideNodeRepository(client, store, inMemoryDatabase, eventStore, cfg, logger)
+	edgeRepository := ProvideEdgeRepository(client, store, inMemoryDatabase, cfg, logger)
+	graphRepository := ProvideGraphRepository(client, store, inMemoryDatabase, nodeRepository, edgeRepository, eventStore, cfg, logger)
 	eventbridgeClient := ProvideEventBridgeClient(awsConfig)
 	eventBus := ProvideEventBus(eventbridgeClient, cfg, logger)
-	eventStore := ProvideEventStore(client, cfg)
 	eventPublisher := ProvideEventPublisher(eventBus)
-	unitOfWork := ProvideUnitOfWork(client, nodeRepository, edgeRepository, graphRepository, eventStore, eventPublisher)
+	unitOfWork := ProvideUnitOfWork(client, store, inMemoryDatabase, nodeRepository, edgeRepository, graphRepository, eventStore, eventPublisher)
 	graphLazyService := ProvideGraphLazyService(nodeRepository, edgeRepository, cfg, logger)
-	distributedLock := ProvideDistributedLock(client, cfg, logger)
+	distributedLock := ProvideDistributedLock(client, store, inMemoryDatabase, cfg, logger)
 	cloudwatchClient := ProvideCloudWatchClient(awsConfig)
 	metrics := ProvideMetrics(cloudwatchClient, cfg)
-	commandBus := ProvideCommandBus(unitOfWork, nodeRepository, edgeRepository, graphRepository, graphLazyService, eventStore, eventBus, eventPublisher, distributedLock, metrics, cfg, logger)
+	trashStore := ProvideTrashStore(client, store, inMemoryDatabase, cfg)
+	domainConfig := ProvideDomainConfig(cfg)
+	trashService := ProvideTrashService(trashStore, nodeRepository, edgeRepository, graphRepository, eventStore, eventBus, domainConfig, logger)
+	graphVersionStore := ProvideGraphVersionStore(client, store, inMemoryDatabase, cfg)
+	versioningService := ProvideVersioningService()
+	graphVersionService := ProvideGraphVersionService(graphVersionStore, nodeRepository, edgeRepository, graphRepository, versioningService, logger)
+	commandBus := ProvideCommandBus(unitOfWork, nodeRepository, edgeRepository, graphRepository, graphLazyService, eventStore, eventBus, eventPublisher, distributedLock, trashService, graphVersionService, metrics, cfg, domainConfig, logger)
 	cache := ProvideInMemoryCache()
 	operationStore := ProvideOperationStore()
-	hybridSearchService := ProvideHybridSearchService(nodeRepository, cfg, logger)
-	queryBus := ProvideQueryBus(graphRepository, nodeRepository, edgeRepository, cache, operationStore, hybridSearchService, logger)
+	vectorIndexStore := ProvideVectorIndexStore(client, store, inMemoryDatabase, cfg)
+	vectorIndexService := ProvideVectorIndexService(vectorIndexStore, nodeRepository, cfg, logger)
+	keywordIndexStore := ProvideKeywordIndexStore(client, store, inMemoryDatabase, cfg)
+	keywordIndexService := ProvideKeywordIndexService(keywordIndexStore, nodeRepository, cfg, logger)
+	hybridSearchService := ProvideHybridSearchService(nodeRepository, vectorIndexService, keywordIndexService, cfg, logger)
+	embeddingService := ProvideEmbeddingService(cfg, logger)
+	reembedService := ProvideReembedService(nodeRepository, embeddingService, operationStore, vectorIndexService, logger)
+	chatClient := ProvideChatClient(cfg, logger)
+	askService := ProvideAskService(hybridSearchService, nodeRepository, edgeRepository, chatClient, cfg, logger)
+	communityRepository := ProvideCommunityRepository(client, store, inMemoryDatabase, cfg)
+	queryBus := ProvideQueryBus(graphRepository, nodeRepository, edgeRepository, communityRepository, cache, operationStore, hybridSearchService, vectorIndexService, logger)
 	distributedRateLimiter := ProvideDistributedRateLimiter(client, cfg)
 	mediator := ProvideMediator(commandBus, queryBus, metrics, logger)
 	handlerRegistry := ProvideEventHandlerRegistry(logger)
 	operationEventListener := ProvideOperationEventListener(operationStore, logger)
+	embeddingListener := ProvideEmbeddingListener(embeddingService, nodeRepository, cfg, logger)
+	vectorIndexListener := ProvideVectorIndexListener(vectorIndexService, nodeRepository, logger)
+	keywordIndexListener := ProvideKeywordIndexListener(keywordIndexService, nodeRepository, logger)
+	communityDetectionService := ProvideCommunityDetectionService(graphRepository, nodeRepository, edgeRepository, communityRepository, logger)
+	linkSuggestionStore := ProvideLinkSuggestionStore(client, store, inMemoryDatabase, cfg)
+	linkPredictionService := ProvideLinkPredictionService(linkSuggestionStore, graphRepository, nodeRepository, edgeRepository, communityRepository, commandBus, logger)
+	communityListener := ProvideCommunityListener(communityDetectionService, nodeRepository, logger)
 	graphStatsProjection := ProvideGraphStatsProjection(cache, logger)
+	checkpointStore := ProvideCheckpointStore(client, store, inMemoryDatabase, cfg)
+	projectionRegistry, err := ProvideProjectionRegistry(checkpointStore, graphStatsProjection, logger)
+	if err != nil {
+		return nil, err
+	}
+	projectionReplayer, err := ProvideProjectionReplayer(projectionRegistry, eventStore, checkpointStore, logger)
+	if err != nil {
+		return nil, err
+	}
 	graphLoader := ProvideGraphLoader(graphRepository, nodeRepository, edgeRepository, logger)
-	communityDetectionService := ProvideCommunityDetectionService(graphRepository, nodeRepository, edgeRepository, logger)
 	analysisService := ProvideAnalysisService(graphRepository, nodeRepository, edgeRepository, logger)
 	v, err := ProvideAuthMiddleware(cfg, logger)
 	if err != nil {
@@ -77,6 +114,7 @@ func InitializeContainer(ctx context.Context, cfg *config.Config) (*Container, e
 		EdgeRepo:               edgeRepository,
 		EventBus:               eventBus,
 		EventStore:             eventStore,
+		Outbox:                 outbox,
 		UnitOfWork:             unitOfWork,
 		CommandBus:             commandBus,
 		QueryBus:               queryBus,
@@ -87,11 +125,26 @@ func InitializeContainer(ctx context.Context, cfg *config.Config) (*Container, e
 		Mediator:               mediator,
 		EventHandlerRegistry:   handlerRegistry,
 		OperationEventListener: operationEventListener,
+		EmbeddingListener:      embeddingListener,
+		VectorIndexListener:    vectorIndexListener,
+		KeywordIndexListener:   keywordIndexListener,
+		CommunityListener:      communityListener,
 		GraphStatsProjection:   graphStatsProjection,
+		CheckpointStore:        checkpointStore,
+		ProjectionRegistry:     projectionRegistry,
+		ProjectionReplayer:     projectionReplayer,
 		GraphLazyService:       graphLazyService,
 		GraphLoader:            graphLoader,
 		CommunityService:       communityDetectionService,
 		AnalysisService:        analysisService,
+		TrashService:           trashService,
+		GraphVersionService:    graphVersionService,
+		VectorIndexService:     vectorIndexService,
+		KeywordIndexService:    keywordIndexService,
+		EmbeddingService:       embeddingService,
+		ReembedService:         reembedService,
+		AskService:             askService,
+		LinkPredictionService:  linkPredictionService,
 		AuthMiddleware:         v,
 	}
 	return container, nil
@@ -109,6 +162,7 @@ type Container struct {
 	EdgeRepo               ports.EdgeRepository
 	EventBus               ports.EventBus
 	EventStore             ports.EventStore
+	Outbox                 ports.Outbox
 	UnitOfWork             ports.UnitOfWork
 	CommandBus             *bus.CommandBus
 	QueryBus               *bus2.QueryBus
@@ -119,11 +173,26 @@ type Container struct {
 	Mediator               *mediator.Mediator
 	EventHandlerRegistry   *events.HandlerRegistry
 	OperationEventListener *listeners.OperationEventListener
+	EmbeddingListener      *listeners.EmbeddingListener
+	VectorIndexListener    *listeners.VectorIndexListener
+	KeywordIndexListener   *listeners.KeywordIndexListener
+	CommunityListener      *listeners.CommunityListener
 	GraphStatsProjection   *projections.GraphStatsProjection
+	CheckpointStore        projections.CheckpointStore
+	ProjectionRegistry     *projections.ProjectionRegistry
+	ProjectionReplayer     *projections.ProjectionReplayer
 	GraphLazyService       *services.GraphLazyService
 	GraphLoader            *services.GraphLoader
 	CommunityService       *services.CommunityDetectionService
 	AnalysisService        *services.AnalysisService
+	TrashService           *services.TrashService
+	GraphVersionService    *services.GraphVersionService
+	VectorIndexService     *services.VectorIndexService
+	KeywordIndexService    *services.KeywordIndexService
+	EmbeddingService       services2.EmbeddingService
+	ReembedService         *services.ReembedService
+	AskService             *services.AskService
+	LinkPredictionService  *services.LinkPredictionService
 	AuthMiddleware         func(http.Handler) http.Handler
 }
 
@@ -143,6 +212,9 @@ var SuperSet = wire.NewSet(
 	ProvideInMemoryCache,
 	ProvideOperationStore,
 
+	ProvideFileStore,
+	ProvideMemoryDatabase,
+
 	ProvideDistributedRateLimiter,
 	ProvideDistributedLock,
 
@@ -152,6 +224,7 @@ var SuperSet = wire.NewSet(
 	ProvideGraphRepository,
 
 	ProvideEventStore,
+	ProvideOutbox,
 
 	ProvideEventBus,
 	ProvideEventPublisher,
@@ -162,9 +235,26 @@ var SuperSet = wire.NewSet(
 	ProvideGraphLazyService,
 	ProvideGraphLoader,
 	ProvideEdgeService,
+	ProvideVectorIndexStore,
+	ProvideVectorIndexService,
+	ProvideKeywordIndexStore,
+	ProvideKeywordIndexService,
 	ProvideHybridSearchService,
+	ProvideEmbeddingService,
+	ProvideReembedService,
+	ProvideChatClient,
+	ProvideAskService,
+	ProvideCommunityRepository,
 	ProvideCommunityDetectionService,
+	ProvideLinkSuggestionStore,
+	ProvideLinkPredictionService,
 	ProvideAnalysisService,
+	ProvideDomainConfig,
+	ProvideTrashStore,
+	ProvideTrashService,
+	ProvideGraphVersionStore,
+	ProvideVersioningService,
+	ProvideGraphVersionService,
 
 	ProvideCommandBus,
 	ProvideQueryBus,
@@ -172,7 +262,14 @@ var SuperSet = wire.NewSet(
 
 	ProvideEventHandlerRegistry,
 	ProvideOperationEventListener,
+	ProvideEmbeddingListener,
+	ProvideVectorIndexListener,
+	ProvideKeywordIndexListener,
+	ProvideCommunityListener,
 	ProvideGraphStatsProjection,
+	ProvideCheckpointStore,
+	ProvideProjectionRegistry,
+	ProvideProjectionReplayer,
 
 	ProvideAuthMiddleware, wire.Struct(new(Container), "*"),
 )
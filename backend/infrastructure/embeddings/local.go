@@ -0,0 +1,178 @@
+package embeddings
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"backend/domain/core/valueobjects"
+	"backend/domain/services"
+)
+
+var _ services.EmbeddingService = (*LocalService)(nil)
+
+// Feature weights of the local embedding. Words dominate; word pairs add
+// some word order, and character trigrams let misspellings and compounds
+// land near the words they share letters with.
+const (
+	localWordWeight    = 1.0
+	localBigramWeight  = 0.5
+	localTrigramWeight = 0.3
+)
+
+// LocalConfig configures the local embedding service.
+type LocalConfig struct {
+	Model      string // model ID recorded with each embedding; change it when the features change
+	Dimensions int    // output dimensions
+}
+
+// DefaultLocalConfig returns the configuration used when none is given.
+func DefaultLocalConfig() *LocalConfig {
+	return &LocalConfig{
+		Model:      "local-hashed-ngrams-v1",
+		Dimensions: 384,
+	}
+}
+
+// LocalService embeds text in process, without a model endpoint. Text is
+// analyzed into stemmed words without stop words, adjacent word pairs and
+// character trigrams of each word; each feature is weighted by its
+// sublinear frequency and hashed to a signed dimension, which projects the
+// sparse n-gram vector onto a fixed number of dimensions. The result is unit
+// length and deterministic: the same text always gets the same vector.
+//
+// The vectors capture shared vocabulary rather than meaning, so they are a
+// fallback for air-gapped setups and tests, not a substitute for a trained
+// model.
+type LocalService struct {
+	config   *LocalConfig
+	analyzer *services.AnalysisChain
+}
+
+// NewLocalService creates a local embedding service, filling in defaults for
+// unset fields of config.
+func NewLocalService(config *LocalConfig) *LocalService {
+	if config == nil {
+		config = DefaultLocalConfig()
+	}
+	if config.Dimensions <= 0 {
+		config.Dimensions = DefaultLocalConfig().Dimensions
+	}
+	if config.Model == "" {
+		config.Model = DefaultLocalConfig().Model
+	}
+	return &LocalService{
+		config:   config,
+		analyzer: services.DefaultAnalysisChain(),
+	}
+}
+
+// GenerateEmbedding embeds a single text.
+func (s *LocalService) GenerateEmbedding(ctx context.Context, text string) (valueobjects.Embedding, error) {
+	if err := ctx.Err(); err != nil {
+		return valueobjects.Embedding{}, err
+	}
+	emb, err := valueobjects.NewEmbedding(s.vector(text))
+	if err != nil {
+		return valueobjects.Embedding{}, err
+	}
+	return emb.WithProvenance(s.config.Model, valueobjects.EmbeddingContentHash(text)), nil
+}
+
+// GenerateEmbeddings embeds texts in order.
+func (s *LocalService) GenerateEmbeddings(ctx context.Context, texts []string) ([]valueobjects.Embedding, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	results := make([]valueobjects.Embedding, len(texts))
+	for i, text := range texts {
+		emb, err := s.GenerateEmbedding(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = emb
+	}
+	return results, nil
+}
+
+// Dimensions returns the length of the vectors.
+func (s *LocalService) Dimensions() int {
+	return s.config.Dimensions
+}
+
+// Model returns the model ID recorded with each embedding.
+func (s *LocalService) Model() string {
+	return s.config.Model
+}
+
+// vector returns the unit length feature vector of text
+func (s *LocalService) vector(text string) []float64 {
+	features := s.features(text)
+	// Add features in a fixed order so rounding is the same on every call
+	names := make([]string, 0, len(features))
+	for feature := range features {
+		names = append(names, feature)
+	}
+	sort.Strings(names)
+
+	vector := make([]float64, s.config.Dimensions)
+	for _, feature := range names {
+		weight := features[feature]
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		// The top bit picks the sign, so colliding features tend to cancel
+		// out instead of adding up
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vector[sum%uint64(len(vector))] += weight
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm == 0 {
+		// Nothing to hash; still give equal texts equal vectors
+		vector[0] = 1
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+	return vector
+}
+
+// features returns the weighted features of text. Feature kinds are
+// prefixed so a word and a trigram with the same letters stay apart.
+func (s *LocalService) features(text string) map[string]float64 {
+	terms := s.analyzer.Analyze(text)
+	if len(terms) == 0 {
+		// Text of stop words only: use its words as they are
+		terms = services.NewAnalysisChain(services.LanguageEnglish).Analyze(text)
+	}
+
+	counts := make(map[string]float64)
+	for i, term := range terms {
+		counts["w:"+term] += localWordWeight
+		if i > 0 {
+			counts["b:"+terms[i-1]+" "+term] += localBigramWeight
+		}
+		padded := []rune("#" + term + "#")
+		trigrams := len(padded) - 2
+		for j := 0; j < trigrams; j++ {
+			// Spread the trigram weight so long words do not outweigh short ones
+			counts["t:"+string(padded[j:j+3])] += localTrigramWeight / float64(trigrams)
+		}
+	}
+
+	// Sublinear frequency: a word repeated ten times is not ten times as
+	// characteristic
+	for feature, count := range counts {
+		counts[feature] = math.Log1p(count)
+	}
+	return counts
+}
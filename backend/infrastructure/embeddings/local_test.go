@@ -0,0 +1,69 @@
+package embeddings
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"backend/domain/core/valueobjects"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalService_Deterministic(t *testing.T) {
+	ctx := context.Background()
+	text := "Gradient descent optimizes neural network weights"
+
+	a, err := NewLocalService(nil).GenerateEmbedding(ctx, text)
+	require.NoError(t, err)
+	b, err := NewLocalService(nil).GenerateEmbedding(ctx, text)
+	require.NoError(t, err)
+
+	assert.Equal(t, a.Vector(), b.Vector(), "separate instances embed equal texts equally")
+	assert.Equal(t, 384, a.Dimensions())
+	assert.Equal(t, "local-hashed-ngrams-v1", a.Model())
+	assert.Equal(t, valueobjects.EmbeddingContentHash(text), a.ContentHash())
+
+	var norm float64
+	for _, v := range a.Vector() {
+		norm += v * v
+	}
+	assert.InDelta(t, 1.0, math.Sqrt(norm), 1e-9)
+}
+
+func TestLocalService_SimilarTextsAreClose(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLocalService(&LocalConfig{Model: "local-test", Dimensions: 256})
+
+	embed := func(text string) valueobjects.Embedding {
+		e, err := svc.GenerateEmbedding(ctx, text)
+		require.NoError(t, err)
+		return e
+	}
+	query := embed("training neural networks")
+	related := embed("How a neural network is trained with backpropagation")
+	misspelled := embed("traning nueral netwroks")
+	unrelated := embed("Sourdough bread needs a long cold fermentation")
+
+	assert.Greater(t, query.CosineSimilarity(related), query.CosineSimilarity(unrelated))
+	assert.Greater(t, query.CosineSimilarity(misspelled), query.CosineSimilarity(unrelated),
+		"character trigrams keep misspellings close")
+	assert.Equal(t, 256, query.Dimensions())
+}
+
+func TestLocalService_Batch(t *testing.T) {
+	svc := NewLocalService(nil)
+	texts := []string{"first note", "second note", "", "the and of"}
+
+	batch, err := svc.GenerateEmbeddings(context.Background(), texts)
+	require.NoError(t, err)
+	require.Len(t, batch, len(texts))
+	for i, text := range texts {
+		single, err := svc.GenerateEmbedding(context.Background(), text)
+		require.NoError(t, err)
+		assert.Equal(t, single.Vector(), batch[i].Vector())
+		assert.False(t, batch[i].IsZero())
+	}
+	assert.Less(t, batch[0].CosineSimilarity(batch[3]), 0.5, "stop word only texts still get their own vector")
+}
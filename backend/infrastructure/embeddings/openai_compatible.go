@@ -111,6 +111,10 @@ func (s *OpenAICompatibleService) Dimensions() int {
 	return s.config.Dimensions
 }
 
+func (s *OpenAICompatibleService) Model() string {
+	return s.config.Model
+}
+
 func (s *OpenAICompatibleService) callAPI(ctx context.Context, texts []string) ([]valueobjects.Embedding, error) {
 	reqBody := embeddingRequest{
 		Input: texts,
@@ -163,10 +167,10 @@ func (s *OpenAICompatibleService) callAPI(ctx context.Context, texts []string) (
 		if err != nil {
 			return nil, fmt.Errorf("invalid embedding at index %d: %w", d.Index, err)
 		}
-		if d.Index >= len(results) {
+		if d.Index < 0 || d.Index >= len(results) {
 			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
 		}
-		results[d.Index] = emb
+		results[d.Index] = emb.WithProvenance(s.config.Model, valueobjects.EmbeddingContentHash(texts[d.Index]))
 	}
 
 	s.logger.Debug("Generated embeddings batch",
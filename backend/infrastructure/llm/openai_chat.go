@@ -0,0 +1,168 @@
+// Package llm implements the ports.ChatClient language model clients.
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/application/ports"
+
+	"go.uber.org/zap"
+)
+
+var _ ports.ChatClient = (*OpenAICompatibleChatClient)(nil)
+
+// OpenAICompatibleChatConfig configures the chat client.
+type OpenAICompatibleChatConfig struct {
+	BaseURL string // e.g. "https://api.openai.com/v1" or "http://localhost:11434/v1"
+	APIKey  string // empty for local endpoints like Ollama
+	Model   string // e.g. "gpt-4o-mini"
+	Timeout time.Duration
+}
+
+func DefaultOpenAICompatibleChatConfig() *OpenAICompatibleChatConfig {
+	return &OpenAICompatibleChatConfig{
+		BaseURL: "https://api.openai.com/v1",
+		Model:   "gpt-4o-mini",
+		Timeout: 2 * time.Minute,
+	}
+}
+
+// OpenAICompatibleChatClient streams answers from any OpenAI-compatible
+// /v1/chat/completions endpoint. Works with OpenAI, Ollama, vLLM, LiteLLM,
+// Azure OpenAI, etc.
+type OpenAICompatibleChatClient struct {
+	client *http.Client
+	config *OpenAICompatibleChatConfig
+	logger *zap.Logger
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+	Stream      bool          `json:"stream"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatChunk is one server-sent event of a streamed completion
+type chatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewOpenAICompatibleChatClient(config *OpenAICompatibleChatConfig, logger *zap.Logger) *OpenAICompatibleChatClient {
+	if config == nil {
+		config = DefaultOpenAICompatibleChatConfig()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &OpenAICompatibleChatClient{
+		client: &http.Client{Timeout: config.Timeout},
+		config: config,
+		logger: logger,
+	}
+}
+
+func (c *OpenAICompatibleChatClient) Model() string {
+	return c.config.Model
+}
+
+func (c *OpenAICompatibleChatClient) StreamChat(ctx context.Context, req ports.ChatRequest, onDelta func(delta string) error) error {
+	messages := make([]chatMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = chatMessage{Role: m.Role, Content: m.Content}
+	}
+	bodyBytes, err := json.Marshal(chatRequest{
+		Model:       c.config.Model,
+		Messages:    messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := c.config.BaseURL + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if c.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	chunks := 0
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Blank lines separate events; lines starting with ':' are comments
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("API returned error: %s", chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			chunks++
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	c.logger.Debug("Streamed chat completion",
+		zap.String("model", c.config.Model),
+		zap.Int("chunks", chunks),
+	)
+	return nil
+}
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/application/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAICompatibleChatClient_StreamsDeltas(t *testing.T) {
+	var received chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		for _, piece := range []string{"Hello", "", " world"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", piece)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewOpenAICompatibleChatClient(&OpenAICompatibleChatConfig{
+		BaseURL: server.URL,
+		APIKey:  "secret",
+		Model:   "test-model",
+		Timeout: 5 * time.Second,
+	}, nil)
+
+	var deltas []string
+	err := client.StreamChat(context.Background(), ports.ChatRequest{
+		Messages:  []ports.ChatMessage{{Role: ports.ChatRoleUser, Content: "Hi"}},
+		MaxTokens: 50,
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hello", " world"}, deltas)
+
+	assert.Equal(t, "test-model", received.Model)
+	assert.True(t, received.Stream)
+	assert.Equal(t, 50, received.MaxTokens)
+	assert.Equal(t, []chatMessage{{Role: "user", Content: "Hi"}}, received.Messages)
+}
+
+func TestOpenAICompatibleChatClient_Errors(t *testing.T) {
+	stream := func(handler http.HandlerFunc) error {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+		client := NewOpenAICompatibleChatClient(&OpenAICompatibleChatConfig{BaseURL: server.URL, Model: "m", Timeout: 5 * time.Second}, nil)
+		return client.StreamChat(context.Background(), ports.ChatRequest{}, func(string) error { return nil })
+	}
+
+	err := stream(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+
+	err = stream(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"error\":{\"message\":\"context too long\"}}\n\n")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context too long")
+}
@@ -58,6 +58,17 @@ func (d *EventDispatcher) DispatchLocal(ctx context.Context, event events.Domain
 	return nil
 }
 
+// Publish dispatches an event to local handlers, so the dispatcher can
+// stand in for the event bus when relaying outbox events
+func (d *EventDispatcher) Publish(ctx context.Context, event events.DomainEvent) error {
+	return d.DispatchLocal(ctx, event)
+}
+
+// PublishBatch dispatches events to local handlers
+func (d *EventDispatcher) PublishBatch(ctx context.Context, events []events.DomainEvent) error {
+	return d.DispatchBatchLocal(ctx, events)
+}
+
 // DispatchBatchLocal dispatches multiple events to local handlers
 func (d *EventDispatcher) DispatchBatchLocal(ctx context.Context, events []events.DomainEvent) error {
 	if d.registry == nil {
@@ -0,0 +1,104 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+
+	"backend/application/ports"
+	"backend/domain/events"
+
+	"go.uber.org/zap"
+)
+
+// LocalEventBus implements ports.EventBus entirely in-process. It is used when
+// the service runs without AWS: published events go to subscribed handlers
+// and, once configured, to the local EventDispatcher.
+type LocalEventBus struct {
+	logger *zap.Logger
+
+	mu         sync.RWMutex
+	handlers   map[string][]ports.EventHandler
+	dispatcher *EventDispatcher
+}
+
+// NewLocalEventBus creates a new in-process event bus
+func NewLocalEventBus(logger *zap.Logger) *LocalEventBus {
+	return &LocalEventBus{
+		logger:   logger,
+		handlers: make(map[string][]ports.EventHandler),
+	}
+}
+
+// SetLocalDispatcher sets the dispatcher that receives every published event
+func (b *LocalEventBus) SetLocalDispatcher(dispatcher *EventDispatcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dispatcher = dispatcher
+}
+
+// Publish delivers a single event
+func (b *LocalEventBus) Publish(ctx context.Context, event events.DomainEvent) error {
+	return b.PublishBatch(ctx, []events.DomainEvent{event})
+}
+
+// PublishBatch delivers events to subscribers synchronously and to the
+// dispatcher in the background, mirroring the EventBridge publisher
+func (b *LocalEventBus) PublishBatch(ctx context.Context, domainEvents []events.DomainEvent) error {
+	if len(domainEvents) == 0 {
+		return nil
+	}
+
+	b.mu.RLock()
+	dispatcher := b.dispatcher
+	subscribed := make(map[string][]ports.EventHandler, len(b.handlers))
+	for eventType, handlers := range b.handlers {
+		subscribed[eventType] = handlers
+	}
+	b.mu.RUnlock()
+
+	for _, event := range domainEvents {
+		for _, handler := range subscribed[event.GetEventType()] {
+			if err := handler.Handle(ctx, event); err != nil {
+				b.logger.Warn("Local event handler failed",
+					zap.String("eventType", event.GetEventType()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	if dispatcher != nil {
+		go func() {
+			// Dispatch in background so publishers are not blocked by projections
+			if err := dispatcher.DispatchBatchLocal(context.Background(), domainEvents); err != nil {
+				b.logger.Warn("Failed to dispatch events locally", zap.Error(err))
+			}
+		}()
+	}
+
+	b.logger.Debug("Events published locally", zap.Int("count", len(domainEvents)))
+	return nil
+}
+
+// Subscribe registers a handler for an event type
+func (b *LocalEventBus) Subscribe(eventType string, handler ports.EventHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	return nil
+}
+
+// Unsubscribe removes a handler
+func (b *LocalEventBus) Unsubscribe(eventType string, handler ports.EventHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	handlers := b.handlers[eventType]
+	for i, h := range handlers {
+		if h == handler {
+			b.handlers[eventType] = append(handlers[:i:i], handlers[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/events"
+	"backend/pkg/observability"
+
+	"go.uber.org/zap"
+)
+
+// OutboxConfig tunes delivery of outbox events
+type OutboxConfig struct {
+	BatchSize    int           // Events fetched per poll
+	PollInterval time.Duration // Time between polls
+	MaxAttempts  int           // Failed attempts before an event is dead-lettered
+	BaseBackoff  time.Duration // Delay after the first failure, doubled after each further one
+	MaxBackoff   time.Duration // Upper bound of the retry delay
+}
+
+// DefaultOutboxConfig returns the delivery settings used when none are configured
+func DefaultOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		BatchSize:    50,
+		PollInterval: 5 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   15 * time.Minute,
+	}
+}
+
+// Backoff returns the delay before the next attempt of an event that has
+// failed the given number of times
+func (c OutboxConfig) Backoff(attempts int) time.Duration {
+	delay := c.BaseBackoff
+	for i := 1; i < attempts && delay < c.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.MaxBackoff {
+		delay = c.MaxBackoff
+	}
+	return delay
+}
+
+// OutboxBatchResult summarizes one pass over the due events
+type OutboxBatchResult struct {
+	Published    int
+	Retried      int
+	DeadLettered int
+}
+
+// OutboxProcessor publishes stored events from the outbox using the Outbox
+// pattern. Failed events are retried with exponential backoff and
+// dead-lettered once they run out of attempts.
+type OutboxProcessor struct {
+	outbox    ports.Outbox
+	publisher ports.EventPublisher
+	metrics   *observability.Metrics
+	logger    *zap.Logger
+	config    OutboxConfig
+	now       func() time.Time
+
+	// Control channels
+	stopChan    chan struct{}
+	stoppedChan chan struct{}
+}
+
+// NewOutboxProcessor creates a new outbox processor; metrics may be nil
+func NewOutboxProcessor(
+	outbox ports.Outbox,
+	publisher ports.EventPublisher,
+	metrics *observability.Metrics,
+	logger *zap.Logger,
+	config OutboxConfig,
+) *OutboxProcessor {
+	defaults := DefaultOutboxConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = config.BaseBackoff
+	}
+
+	return &OutboxProcessor{
+		outbox:      outbox,
+		publisher:   publisher,
+		metrics:     metrics,
+		logger:      logger,
+		config:      config,
+		now:         time.Now,
+		stopChan:    make(chan struct{}),
+		stoppedChan: make(chan struct{}),
+	}
+}
+
+// Start begins the background processing of outbox events
+func (op *OutboxProcessor) Start(ctx context.Context) {
+	op.logger.Info("Starting outbox processor",
+		zap.Int("batchSize", op.config.BatchSize),
+		zap.Duration("interval", op.config.PollInterval),
+		zap.Int("maxAttempts", op.config.MaxAttempts),
+	)
+
+	go op.processLoop(ctx)
+}
+
+// Stop gracefully stops the outbox processor
+func (op *OutboxProcessor) Stop() {
+	op.logger.Info("Stopping outbox processor")
+	close(op.stopChan)
+	<-op.stoppedChan
+	op.logger.Info("Outbox processor stopped")
+}
+
+// processLoop is the main processing loop
+func (op *OutboxProcessor) processLoop(ctx context.Context) {
+	defer close(op.stoppedChan)
+
+	ticker := time.NewTicker(op.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			op.logger.Info("Context cancelled, stopping outbox processor")
+			return
+		case <-op.stopChan:
+			op.logger.Info("Stop signal received")
+			return
+		case <-ticker.C:
+			if _, err := op.ProcessBatch(ctx); err != nil {
+				op.logger.Error("Error processing outbox batch", zap.Error(err))
+			}
+			op.reportState(ctx)
+		}
+	}
+}
+
+// ProcessBatch publishes the events that are due and records each outcome
+func (op *OutboxProcessor) ProcessBatch(ctx context.Context) (*OutboxBatchResult, error) {
+	due, err := op.outbox.DueEntries(ctx, op.now(), op.config.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due events: %w", err)
+	}
+
+	result := &OutboxBatchResult{}
+	for _, entry := range due {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		publishErr := op.publish(ctx, entry)
+		if publishErr == nil {
+			if err := op.outbox.MarkPublished(ctx, entry.ID); err != nil {
+				op.logger.Error("Failed to mark event as published",
+					zap.String("entryID", entry.ID),
+					zap.Error(err),
+				)
+				continue
+			}
+			result.Published++
+			continue
+		}
+
+		deadLettered, err := op.recordFailure(ctx, entry, publishErr)
+		if err != nil {
+			op.logger.Error("Failed to record outbox failure",
+				zap.String("entryID", entry.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if deadLettered {
+			result.DeadLettered++
+		} else {
+			result.Retried++
+		}
+	}
+
+	if len(due) > 0 {
+		op.logger.Debug("Completed outbox batch processing",
+			zap.Int("published", result.Published),
+			zap.Int("retried", result.Retried),
+			zap.Int("deadLettered", result.DeadLettered),
+		)
+		op.metrics.RecordOutboxDeliveries(ctx, result.Published, result.Retried, result.DeadLettered)
+	}
+	return result, nil
+}
+
+// publish decodes the stored event and hands it to the publisher
+func (op *OutboxProcessor) publish(ctx context.Context, entry *ports.OutboxEntry) error {
+	event, err := events.Decode(entry.EventType, entry.AggregateID, entry.Version, entry.OccurredAt, entry.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+	if err := op.publisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("publish failed: %w", err)
+	}
+	return nil
+}
+
+// recordFailure schedules the next attempt, or dead-letters the event once
+// it has used up its attempts
+func (op *OutboxProcessor) recordFailure(ctx context.Context, entry *ports.OutboxEntry, cause error) (bool, error) {
+	attempts := entry.Attempts + 1
+	message := cause.Error()
+
+	if attempts >= op.config.MaxAttempts {
+		op.logger.Warn("Dead-lettering event after max attempts",
+			zap.String("entryID", entry.ID),
+			zap.String("eventType", entry.EventType),
+			zap.Int("attempts", attempts),
+			zap.String("error", message),
+		)
+		return true, op.outbox.DeadLetter(ctx, entry.ID, attempts, message)
+	}
+
+	next := op.now().Add(op.config.Backoff(attempts))
+	op.logger.Debug("Event scheduled for retry",
+		zap.String("entryID", entry.ID),
+		zap.String("eventType", entry.EventType),
+		zap.Int("attempts", attempts),
+		zap.Time("nextAttemptAt", next),
+		zap.String("error", message),
+	)
+	return false, op.outbox.ScheduleRetry(ctx, entry.ID, attempts, message, next)
+}
+
+// reportState publishes the outbox depth and lag
+func (op *OutboxProcessor) reportState(ctx context.Context) {
+	stats, err := op.outbox.Stats(ctx)
+	if err != nil {
+		op.logger.Warn("Failed to read outbox stats", zap.Error(err))
+		return
+	}
+	op.metrics.RecordOutboxState(ctx, stats.Pending, stats.DeadLettered, stats.Lag(op.now()))
+}
+
+// GetStats returns the outbox state and processing settings
+func (op *OutboxProcessor) GetStats(ctx context.Context) (map[string]interface{}, error) {
+	stats, err := op.outbox.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"pending":            stats.Pending,
+		"deadLettered":       stats.DeadLettered,
+		"lag":                stats.Lag(op.now()).String(),
+		"batchSize":          op.config.BatchSize,
+		"processingInterval": op.config.PollInterval.String(),
+		"maxAttempts":        op.config.MaxAttempts,
+	}, nil
+}
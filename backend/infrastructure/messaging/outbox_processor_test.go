@@ -0,0 +1,118 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+	"backend/infrastructure/persistence/filestore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubPublisher struct {
+	err       error
+	published []events.DomainEvent
+}
+
+func (p *stubPublisher) Publish(ctx context.Context, event events.DomainEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *stubPublisher) PublishBatch(ctx context.Context, batch []events.DomainEvent) error {
+	for _, event := range batch {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestOutboxConfig_BackoffDoublesUpToMax(t *testing.T) {
+	config := OutboxConfig{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	assert.Equal(t, time.Second, config.Backoff(1))
+	assert.Equal(t, 2*time.Second, config.Backoff(2))
+	assert.Equal(t, 8*time.Second, config.Backoff(4))
+	assert.Equal(t, 10*time.Second, config.Backoff(5))
+	assert.Equal(t, 10*time.Second, config.Backoff(60))
+}
+
+func TestOutboxProcessor_RetriesThenDeadLettersAndRedrives(t *testing.T) {
+	ctx := context.Background()
+	store, err := filestore.Open(filepath.Join(t.TempDir(), "brain2.db"), zap.NewNop())
+	require.NoError(t, err)
+	outbox := filestore.NewEventStore(store)
+
+	graph, err := aggregates.NewGraph("user-1", "Outbox")
+	require.NoError(t, err)
+	require.NoError(t, outbox.SaveEvents(ctx, graph.GetUncommittedEvents()[:1]))
+
+	publisher := &stubPublisher{err: errors.New("bus unavailable")}
+	processor := NewOutboxProcessor(outbox, publisher, nil, zap.NewNop(), OutboxConfig{
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+	})
+	clock := time.Now().Add(time.Second)
+	processor.now = func() time.Time { return clock }
+
+	result, err := processor.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Retried)
+
+	// Not due again until the backoff has passed
+	result, err = processor.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, result.Retried)
+
+	clock = clock.Add(time.Minute)
+	result, err = processor.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Retried)
+
+	clock = clock.Add(2 * time.Minute)
+	result, err = processor.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.DeadLettered)
+
+	stats, err := outbox.Stats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Pending)
+	assert.Equal(t, 1, stats.DeadLettered)
+
+	dead, err := outbox.ListDeadLettered(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Contains(t, dead[0].LastError, "bus unavailable")
+
+	// Redriving puts it back in the queue with a fresh attempt budget
+	redriven, err := outbox.Redrive(ctx, dead[0].ID)
+	require.NoError(t, err)
+	assert.Zero(t, redriven.Attempts)
+	_, err = outbox.Redrive(ctx, dead[0].ID)
+	assert.Error(t, err, "only dead-lettered events can be redriven")
+
+	publisher.err = nil
+	result, err = processor.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Published)
+	require.Len(t, publisher.published, 1)
+	assert.Equal(t, graph.ID().String(), publisher.published[0].GetAggregateID())
+
+	stats, err = outbox.Stats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Pending)
+	assert.Zero(t, stats.DeadLettered)
+}
@@ -0,0 +1,162 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// ActivityIndexBackfill writes the GSI5 keys onto node and graph items saved
+// before the activity index existed, so FindRecentlyUpdated and FindMostActive
+// see them. Public graphs also get their GSI6 keys, which share the activity
+// sort key. Items that already carry GSI5 keys are left alone, so the backfill
+// can be rerun and can run while the application is writing.
+type ActivityIndexBackfill struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    *zap.Logger
+}
+
+// ActivityBackfillResult counts what a backfill run did
+type ActivityBackfillResult struct {
+	Scanned int `json:"scanned"` // Node and graph items without GSI5 keys
+	Nodes   int `json:"nodes"`
+	Graphs  int `json:"graphs"`
+	Public  int `json:"public"`  // Graphs that also got GSI6 keys
+	Skipped int `json:"skipped"` // Items written or deleted concurrently
+	Failed  int `json:"failed"`
+}
+
+// backfillItem is the part of a node or graph item the backfill reads
+type backfillItem struct {
+	PK         string                 `dynamodbav:"PK"`
+	SK         string                 `dynamodbav:"SK"`
+	EntityType string                 `dynamodbav:"EntityType"`
+	UserID     string                 `dynamodbav:"UserID"`
+	NodeID     string                 `dynamodbav:"NodeID"`
+	GraphID    string                 `dynamodbav:"GraphID"`
+	Metadata   map[string]interface{} `dynamodbav:"Metadata"`
+	CreatedAt  string                 `dynamodbav:"CreatedAt"`
+	UpdatedAt  string                 `dynamodbav:"UpdatedAt"`
+}
+
+// NewActivityIndexBackfill creates a backfill for the given table
+func NewActivityIndexBackfill(client *dynamodb.Client, tableName string, logger *zap.Logger) *ActivityIndexBackfill {
+	return &ActivityIndexBackfill{client: client, tableName: tableName, logger: logger}
+}
+
+// Run scans the table for node and graph items without GSI5 keys and writes
+// them. With dryRun set it only counts the items.
+func (b *ActivityIndexBackfill) Run(ctx context.Context, dryRun bool) (*ActivityBackfillResult, error) {
+	result := &ActivityBackfillResult{}
+	paginator := dynamodb.NewScanPaginator(b.client, &dynamodb.ScanInput{
+		TableName:            aws.String(b.tableName),
+		FilterExpression:     aws.String("EntityType IN (:node, :graph) AND attribute_not_exists(GSI5PK)"),
+		ProjectionExpression: aws.String("PK, SK, EntityType, UserID, NodeID, GraphID, Metadata, CreatedAt, UpdatedAt"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":node":  &types.AttributeValueMemberS{Value: "NODE"},
+			":graph": &types.AttributeValueMemberS{Value: "GRAPH"},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to scan table: %w", err)
+		}
+		for _, raw := range page.Items {
+			result.Scanned++
+			var item backfillItem
+			if err := attributevalue.UnmarshalMap(raw, &item); err != nil {
+				b.logger.Warn("Skipping unreadable item", zap.Error(err))
+				result.Failed++
+				continue
+			}
+			if dryRun {
+				continue
+			}
+			b.backfill(ctx, item, result)
+		}
+
+		b.logger.Info("Activity index backfill progress",
+			zap.Int("scanned", result.Scanned),
+			zap.Int("nodes", result.Nodes),
+			zap.Int("graphs", result.Graphs),
+			zap.Int("failed", result.Failed),
+		)
+	}
+
+	return result, nil
+}
+
+// backfill writes the index keys of one item. The write is conditioned on the
+// item still lacking them: a concurrent save has already written fresher ones.
+func (b *ActivityIndexBackfill) backfill(ctx context.Context, item backfillItem, result *ActivityBackfillResult) {
+	id, partition := item.NodeID, fmt.Sprintf("USER#%s#NODE", item.UserID)
+	if item.EntityType == "GRAPH" {
+		id, partition = item.GraphID, fmt.Sprintf("USER#%s#GRAPH", item.UserID)
+	}
+	activity := activitySortKey(backfillTime(item), id)
+
+	update := "SET GSI5PK = :pk, GSI5SK = :activity"
+	values := map[string]types.AttributeValue{
+		":pk":       &types.AttributeValueMemberS{Value: partition},
+		":activity": &types.AttributeValueMemberS{Value: activity},
+	}
+	public := false
+	if item.EntityType == "GRAPH" {
+		public, _ = item.Metadata["isPublic"].(bool)
+	}
+	if public {
+		update += ", GSI6PK = :public, GSI6SK = :activity"
+		values[":public"] = &types.AttributeValueMemberS{Value: publicGraphPartition}
+	}
+
+	_, err := b.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(b.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: item.PK},
+			"SK": &types.AttributeValueMemberS{Value: item.SK},
+		},
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String("attribute_exists(PK) AND attribute_not_exists(GSI5PK)"),
+		ExpressionAttributeValues: values,
+	})
+	var ccf *types.ConditionalCheckFailedException
+	switch {
+	case errors.As(err, &ccf):
+		result.Skipped++
+	case err != nil:
+		b.logger.Error("Failed to backfill activity index keys",
+			zap.String("pk", item.PK),
+			zap.String("sk", item.SK),
+			zap.Error(err),
+		)
+		result.Failed++
+	case item.EntityType == "GRAPH":
+		result.Graphs++
+		if public {
+			result.Public++
+		}
+	default:
+		result.Nodes++
+	}
+}
+
+// backfillTime is the time an item was last updated, falling back to its
+// creation time and then to the zero time, which sorts it as the oldest
+func backfillTime(item backfillItem) time.Time {
+	for _, value := range []string{item.UpdatedAt, item.CreatedAt} {
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
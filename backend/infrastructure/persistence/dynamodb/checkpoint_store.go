@@ -0,0 +1,136 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"backend/application/projections"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CheckpointStore keeps projection checkpoints in the main table. Writes are
+// conditional on the stored position not being further along, so two replays
+// of the same projection cannot both advance it.
+type CheckpointStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// checkpointRecord is how a projection checkpoint is stored in DynamoDB
+type checkpointRecord struct {
+	PK             string `dynamodbav:"PK"` // PROJECTION#<name>
+	SK             string `dynamodbav:"SK"` // CHECKPOINT
+	ProjectionName string `dynamodbav:"ProjectionName"`
+	Position       int64  `dynamodbav:"Position"`
+	LastEventID    string `dynamodbav:"LastEventID"`
+	UpdatedAt      int64  `dynamodbav:"UpdatedAt"`
+	State          string `dynamodbav:"State,omitempty"` // JSON encoded projection state
+}
+
+// Compile-time interface check
+var _ projections.CheckpointStore = (*CheckpointStore)(nil)
+
+// NewCheckpointStore creates a new DynamoDB checkpoint store
+func NewCheckpointStore(client *dynamodb.Client, tableName string) *CheckpointStore {
+	return &CheckpointStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func checkpointKey(projectionName string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PROJECTION#%s", projectionName)},
+		"SK": &types.AttributeValueMemberS{Value: "CHECKPOINT"},
+	}
+}
+
+// SavePosition stores the position unless a further one is already stored
+func (s *CheckpointStore) SavePosition(ctx context.Context, position *projections.ProjectionPosition) error {
+	item, err := attributevalue.MarshalMap(checkpointRecord{
+		PK:             fmt.Sprintf("PROJECTION#%s", position.ProjectionName),
+		SK:             "CHECKPOINT",
+		ProjectionName: position.ProjectionName,
+		Position:       position.Position,
+		LastEventID:    position.LastEventID,
+		UpdatedAt:      position.UpdatedAt,
+		State:          string(position.State),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+		ConditionExpression: aws.String(
+			"attribute_not_exists(PK) OR #pos < :pos OR (#pos = :pos AND LastEventID <= :eventID)"),
+		ExpressionAttributeNames: map[string]string{
+			"#pos": "Position",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pos":     &types.AttributeValueMemberN{Value: strconv.FormatInt(position.Position, 10)},
+			":eventID": &types.AttributeValueMemberS{Value: position.LastEventID},
+		},
+	}
+
+	if _, err := s.client.PutItem(ctx, input); err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return pkgerrors.NewConflictError(fmt.Sprintf(
+				"checkpoint of projection %s is already past position %d", position.ProjectionName, position.Position))
+		}
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// GetPosition returns the stored position, or nil if there is none
+func (s *CheckpointStore) GetPosition(ctx context.Context, projectionName string) (*projections.ProjectionPosition, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            checkpointKey(projectionName),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var record checkpointRecord
+	if err := attributevalue.UnmarshalMap(result.Item, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+
+	position := &projections.ProjectionPosition{
+		ProjectionName: record.ProjectionName,
+		Position:       record.Position,
+		LastEventID:    record.LastEventID,
+		UpdatedAt:      record.UpdatedAt,
+	}
+	if record.State != "" {
+		position.State = []byte(record.State)
+	}
+	return position, nil
+}
+
+// DeletePosition removes the stored position, if any
+func (s *CheckpointStore) DeletePosition(ctx context.Context, projectionName string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       checkpointKey(projectionName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return nil
+}
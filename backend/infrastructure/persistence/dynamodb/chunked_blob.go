@@ -0,0 +1,258 @@
+package dynamodb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// blobChunkSize keeps every chunk item well below the 400KB item limit
+const blobChunkSize = 350 * 1024
+
+// chunkedBlobStore keeps one opaque blob per user in the main table. A blob is
+// usually far larger than one item, so it is gzip compressed and split into
+// chunks. Chunks are written under a new generation before the manifest is
+// switched to it, so readers never see a half written blob; chunks of older
+// generations are deleted afterwards.
+type chunkedBlobStore struct {
+	client    *dynamodb.Client
+	tableName string
+	prefix    string // partition key prefix, e.g. VECTORINDEX
+	resource  string // name used in errors, e.g. vector index
+}
+
+// blobManifest points at the chunks of the current generation
+type blobManifest struct {
+	PK         string `dynamodbav:"PK"` // <prefix>#<userID>
+	SK         string `dynamodbav:"SK"` // MANIFEST
+	Generation string `dynamodbav:"Generation"`
+	Chunks     int    `dynamodbav:"Chunks"`
+	Size       int    `dynamodbav:"Size"`
+	UpdatedAt  string `dynamodbav:"UpdatedAt"`
+}
+
+// blobChunk is one slice of a compressed blob
+type blobChunk struct {
+	PK   string `dynamodbav:"PK"` // <prefix>#<userID>
+	SK   string `dynamodbav:"SK"` // CHUNK#<generation>#<zero padded chunk>
+	Data []byte `dynamodbav:"Data"`
+}
+
+func (s *chunkedBlobStore) pk(userID string) string {
+	return fmt.Sprintf("%s#%s", s.prefix, userID)
+}
+
+func blobChunkSK(generation string, chunk int) string {
+	return fmt.Sprintf("CHUNK#%s#%06d", generation, chunk)
+}
+
+// load returns a user's blob
+func (s *chunkedBlobStore) load(ctx context.Context, userID string) ([]byte, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: s.pk(userID)},
+			"SK": &types.AttributeValueMemberS{Value: "MANIFEST"},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s manifest: %w", s.resource, err)
+	}
+	if result.Item == nil {
+		return nil, pkgerrors.NewNotFoundError(s.resource)
+	}
+	var manifest blobManifest
+	if err := attributevalue.UnmarshalMap(result.Item, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s manifest: %w", s.resource, err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: s.pk(userID)},
+			":sk": &types.AttributeValueMemberS{Value: "CHUNK#" + manifest.Generation + "#"},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	compressed := make([]byte, 0, manifest.Size)
+	chunks := 0
+	for {
+		page, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s chunks: %w", s.resource, err)
+		}
+		for _, item := range page.Items {
+			var chunk blobChunk
+			if err := attributevalue.UnmarshalMap(item, &chunk); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal %s chunk: %w", s.resource, err)
+			}
+			compressed = append(compressed, chunk.Data...)
+			chunks++
+		}
+		if page.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = page.LastEvaluatedKey
+	}
+	if chunks != manifest.Chunks {
+		return nil, fmt.Errorf("%s of user %s has %d of %d chunks", s.resource, userID, chunks, manifest.Chunks)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", s.resource, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", s.resource, err)
+	}
+	return data, nil
+}
+
+// save stores a user's blob as a new generation
+func (s *chunkedBlobStore) save(ctx context.Context, userID string, data []byte) error {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", s.resource, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", s.resource, err)
+	}
+	compressed := buf.Bytes()
+
+	now := time.Now().UTC()
+	generation := strconv.FormatInt(now.UnixNano(), 36)
+	pk := s.pk(userID)
+
+	chunks := 0
+	for offset := 0; offset < len(compressed); offset += blobChunkSize {
+		end := offset + blobChunkSize
+		if end > len(compressed) {
+			end = len(compressed)
+		}
+		item, err := attributevalue.MarshalMap(blobChunk{
+			PK:   pk,
+			SK:   blobChunkSK(generation, chunks),
+			Data: compressed[offset:end],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s chunk: %w", s.resource, err)
+		}
+		if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(s.tableName),
+			Item:      item,
+		}); err != nil {
+			return fmt.Errorf("failed to save %s chunk: %w", s.resource, err)
+		}
+		chunks++
+	}
+
+	manifest, err := attributevalue.MarshalMap(blobManifest{
+		PK:         pk,
+		SK:         "MANIFEST",
+		Generation: generation,
+		Chunks:     chunks,
+		Size:       len(compressed),
+		UpdatedAt:  now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s manifest: %w", s.resource, err)
+	}
+	if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      manifest,
+	}); err != nil {
+		return fmt.Errorf("failed to save %s manifest: %w", s.resource, err)
+	}
+
+	return s.deleteStaleChunks(ctx, userID, generation)
+}
+
+// remove deletes a user's blob; removing a missing blob is not an error
+func (s *chunkedBlobStore) remove(ctx context.Context, userID string) error {
+	if _, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: s.pk(userID)},
+			"SK": &types.AttributeValueMemberS{Value: "MANIFEST"},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to delete %s manifest: %w", s.resource, err)
+	}
+	// No generation matches the empty one, so every chunk is stale
+	return s.deleteStaleChunks(ctx, userID, "")
+}
+
+// deleteStaleChunks removes the chunks of every generation but the current one
+func (s *chunkedBlobStore) deleteStaleChunks(ctx context.Context, userID, generation string) error {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: s.pk(userID)},
+			":sk": &types.AttributeValueMemberS{Value: "CHUNK#"},
+		},
+		ProjectionExpression: aws.String("PK, SK"),
+	}
+
+	current := "CHUNK#" + generation + "#"
+	stale := make([]types.WriteRequest, 0)
+	for {
+		page, err := s.client.Query(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to query %s chunks: %w", s.resource, err)
+		}
+		for _, item := range page.Items {
+			sk, ok := item["SK"].(*types.AttributeValueMemberS)
+			if !ok || strings.HasPrefix(sk.Value, current) {
+				continue
+			}
+			stale = append(stale, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{
+					"PK": item["PK"],
+					"SK": item["SK"],
+				}},
+			})
+		}
+		if page.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = page.LastEvaluatedKey
+	}
+
+	// BatchWriteItem accepts at most 25 requests per call
+	for start := 0; start < len(stale); start += 25 {
+		end := start + 25
+		if end > len(stale) {
+			end = len(stale)
+		}
+		requests := stale[start:end]
+		for len(requests) > 0 {
+			result, err := s.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{s.tableName: requests},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete stale %s chunks: %w", s.resource, err)
+			}
+			requests = result.UnprocessedItems[s.tableName]
+		}
+	}
+	return nil
+}
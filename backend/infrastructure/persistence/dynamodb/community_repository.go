@@ -0,0 +1,61 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// CommunityRepository keeps each graph's community partition in the main
+// table as a chunked blob under COMMUNITIES#<graphID>, as the member lists of
+// a large graph do not fit in one item.
+type CommunityRepository struct {
+	blobs *chunkedBlobStore
+}
+
+// Compile-time interface check
+var _ ports.CommunityRepository = (*CommunityRepository)(nil)
+
+// NewCommunityRepository creates a new DynamoDB community repository
+func NewCommunityRepository(client *dynamodb.Client, tableName string) *CommunityRepository {
+	return &CommunityRepository{
+		blobs: &chunkedBlobStore{
+			client:    client,
+			tableName: tableName,
+			prefix:    "COMMUNITIES",
+			resource:  "community partition",
+		},
+	}
+}
+
+// GetByGraphID returns the partition of a graph
+func (r *CommunityRepository) GetByGraphID(ctx context.Context, graphID string) (*entities.CommunityPartition, error) {
+	data, err := r.blobs.load(ctx, graphID)
+	if err != nil {
+		return nil, err
+	}
+	var partition entities.CommunityPartition
+	if err := json.Unmarshal(data, &partition); err != nil {
+		return nil, fmt.Errorf("failed to decode community partition: %w", err)
+	}
+	return &partition, nil
+}
+
+// Save replaces the partition of a graph
+func (r *CommunityRepository) Save(ctx context.Context, partition *entities.CommunityPartition) error {
+	data, err := json.Marshal(partition)
+	if err != nil {
+		return fmt.Errorf("failed to encode community partition: %w", err)
+	}
+	return r.blobs.save(ctx, partition.GraphID, data)
+}
+
+// Delete removes the partition of a graph
+func (r *CommunityRepository) Delete(ctx context.Context, graphID string) error {
+	return r.blobs.remove(ctx, graphID)
+}
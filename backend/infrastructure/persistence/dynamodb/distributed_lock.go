@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"backend/application/ports"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -30,6 +32,9 @@ type LockRecord struct {
 	TTL        int64  `dynamodbav:"TTL"`        // Unix timestamp for DynamoDB TTL
 }
 
+// Compile-time interface check
+var _ ports.DistributedLock = (*DistributedLock)(nil)
+
 // NewDistributedLock creates a new distributed lock instance
 func NewDistributedLock(client *dynamodb.Client, tableName string, logger *zap.Logger) *DistributedLock {
 	return &DistributedLock{
@@ -136,6 +141,16 @@ func (dl *DistributedLock) TryAcquireLock(ctx context.Context, resourceName, own
 	return nil, fmt.Errorf("timeout acquiring lock for resource: %s", resourceName)
 }
 
+// TryAcquire implements ports.DistributedLock
+func (dl *DistributedLock) TryAcquire(ctx context.Context, resource, owner string, lockDuration, timeout time.Duration) (ports.Lock, error) {
+	lock, err := dl.TryAcquireLock(ctx, resource, owner, lockDuration, timeout)
+	if err != nil {
+		// Return an untyped nil so callers can compare against nil safely
+		return nil, err
+	}
+	return lock, nil
+}
+
 // ReleaseLock releases the specified lock
 func (dl *DistributedLock) ReleaseLock(ctx context.Context, resourceName, lockID, ownerID string) error {
 	input := &dynamodb.DeleteItemInput{
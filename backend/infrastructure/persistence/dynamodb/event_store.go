@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"backend/domain/core/valueobjects"
+	"backend/application/ports"
 	"backend/domain/events"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -19,18 +21,32 @@ import (
 
 // DynamoDBEventStore implements the EventStore interface using DynamoDB
 type DynamoDBEventStore struct {
-	client    *dynamodb.Client
-	tableName string
-	eventTTL  time.Duration // Configurable TTL for events
+	client      *dynamodb.Client
+	tableName   string
+	eventTTL    time.Duration // Configurable TTL for events
+	outboxIndex string        // Sparse GSI7 over undelivered events
+	legacySweep *legacySweep  // Nil when disabled
+}
+
+// legacySweep tracks the pass over the table that indexes pending events
+// written before the outbox index
+type legacySweep struct {
+	mu       sync.Mutex
+	startKey map[string]types.AttributeValue
+	done     bool
 }
 
 // PublishStatus represents the publishing status of an event
 type PublishStatus string
 
 const (
-	PublishStatusPending   PublishStatus = "pending"   // Event is saved but not yet published
-	PublishStatusPublished PublishStatus = "published" // Event successfully published
-	PublishStatusFailed    PublishStatus = "failed"    // Event publishing failed
+	PublishStatusPending      PublishStatus = "pending"       // Event is saved but not yet published
+	PublishStatusPublished    PublishStatus = "published"     // Event successfully published
+	PublishStatusDeadLettered PublishStatus = "dead_lettered" // Event parked after too many failed publishes
+
+	// publishStatusFailed is how earlier versions parked failed events; such
+	// records are reported as dead-lettered
+	publishStatusFailed PublishStatus = "failed"
 )
 
 // EventRecord represents how events are stored in DynamoDB with Outbox pattern
@@ -48,10 +64,12 @@ type EventRecord struct {
 	UserID        string                 `dynamodbav:"UserID"`
 
 	// Outbox pattern fields
-	PublishStatus   string `dynamodbav:"PublishStatus"`            // pending/published/failed
+	PublishStatus   string `dynamodbav:"PublishStatus"`            // pending/published/dead_lettered
 	PublishAttempts int    `dynamodbav:"PublishAttempts"`          // Number of publish attempts
 	LastPublishTry  string `dynamodbav:"LastPublishTry,omitempty"` // RFC3339 timestamp
+	NextPublishTry  string `dynamodbav:"NextPublishTry,omitempty"` // RFC3339 timestamp of the next retry
 	PublishedAt     string `dynamodbav:"PublishedAt,omitempty"`    // RFC3339 timestamp when published
+	DeadLetteredAt  string `dynamodbav:"DeadLetteredAt,omitempty"` // RFC3339 timestamp when parked
 	ErrorMessage    string `dynamodbav:"ErrorMessage,omitempty"`   // Last error message if failed
 
 	// GSI attributes for querying
@@ -60,25 +78,55 @@ type EventRecord struct {
 	GSI2PK string `dynamodbav:"GSI2PK"` // EVENTTYPE#<type>
 	GSI2SK string `dynamodbav:"GSI2SK"` // EVENT#<timestamp>
 
+	// Sparse outbox index, present only while the event is undelivered
+	GSI7PK string `dynamodbav:"GSI7PK,omitempty"` // OUTBOX#PENDING#<shard> or OUTBOX#DEAD
+	GSI7SK string `dynamodbav:"GSI7SK,omitempty"` // <due or dead-lettered time>#<event_id>
+
 	// TTL for automatic cleanup (optional)
 	TTL int64 `dynamodbav:"TTL,omitempty"`
 }
 
+// Compile-time interface checks
+var _ ports.EventStore = (*DynamoDBEventStore)(nil)
+var _ ports.SnapshotStore = (*DynamoDBEventStore)(nil)
+var _ ports.EventStream = (*DynamoDBEventStore)(nil)
+var _ ports.Outbox = (*DynamoDBEventStore)(nil)
+
 // NewDynamoDBEventStore creates a new DynamoDB event store
 func NewDynamoDBEventStore(client *dynamodb.Client, tableName string) *DynamoDBEventStore {
 	return &DynamoDBEventStore{
-		client:    client,
-		tableName: tableName,
-		eventTTL:  1 * time.Hour, // Default to 1 HR TTL for all events
+		client:      client,
+		tableName:   tableName,
+		eventTTL:    1 * time.Hour, // Default to 1 HR TTL for all events
+		outboxIndex: "OutboxIndex",
+		legacySweep: &legacySweep{},
 	}
 }
 
-// WithEventTTL sets a custom TTL for events
+// WithEventTTL sets a custom TTL for events; zero disables expiry
 func (es *DynamoDBEventStore) WithEventTTL(ttl time.Duration) *DynamoDBEventStore {
 	es.eventTTL = ttl
 	return es
 }
 
+// WithOutboxIndex sets the name of the sparse index over undelivered events
+func (es *DynamoDBEventStore) WithOutboxIndex(indexName string) *DynamoDBEventStore {
+	es.outboxIndex = indexName
+	return es
+}
+
+// WithLegacySweep turns the sweep for pending events written before the
+// outbox index on or off. It is on by default; once a sweep has completed
+// its pass the table holds no such events and it can be turned off.
+func (es *DynamoDBEventStore) WithLegacySweep(enabled bool) *DynamoDBEventStore {
+	if enabled {
+		es.legacySweep = &legacySweep{}
+	} else {
+		es.legacySweep = nil
+	}
+	return es
+}
+
 // SaveEvents persists domain events to the event store
 func (es *DynamoDBEventStore) SaveEvents(ctx context.Context, domainEvents []events.DomainEvent) error {
 	if len(domainEvents) == 0 {
@@ -233,6 +281,87 @@ func (es *DynamoDBEventStore) GetEventsAfter(ctx context.Context, aggregateID st
 	return filteredEvents, nil
 }
 
+// ReadEvents returns up to limit events at or after the given position. The
+// position of an event is its append time in Unix nanoseconds, taken from the
+// sort key. Events are partitioned by aggregate, so this scans the table and
+// is meant for offline work such as projection replays.
+func (es *DynamoDBEventStore) ReadEvents(ctx context.Context, fromPosition int64, limit int) ([]ports.RecordedEvent, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(es.tableName),
+		FilterExpression: aws.String("begins_with(PK, :pk) AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: "EVENTS#"},
+			":sk": &types.AttributeValueMemberS{Value: "EVENT#"},
+		},
+	}
+
+	type positioned struct {
+		position int64
+		record   EventRecord
+	}
+	var matches []positioned
+
+	for {
+		result, err := es.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan events: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var record EventRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event record: %w", err)
+			}
+			position, err := eventPosition(record.SK)
+			if err != nil {
+				return nil, err
+			}
+			if position >= fromPosition {
+				matches = append(matches, positioned{position: position, record: record})
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].position != matches[j].position {
+			return matches[i].position < matches[j].position
+		}
+		return matches[i].record.EventID < matches[j].record.EventID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	recorded := make([]ports.RecordedEvent, 0, len(matches))
+	for _, match := range matches {
+		event, err := es.recordToEvent(match.record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert record to event: %w", err)
+		}
+		recorded = append(recorded, ports.RecordedEvent{
+			Position: match.position,
+			EventID:  match.record.EventID,
+			Event:    event,
+		})
+	}
+	return recorded, nil
+}
+
+// eventPosition extracts the append time from an EVENT#<timestamp>#<event_id> sort key
+func eventPosition(sk string) (int64, error) {
+	parts := strings.SplitN(strings.TrimPrefix(sk, "EVENT#"), "#", 2)
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid event sort key %q: %w", sk, err)
+	}
+	return timestamp.UnixNano(), nil
+}
+
 // GetEventsByUser retrieves events for a specific user
 func (es *DynamoDBEventStore) GetEventsByUser(ctx context.Context, userID string, since time.Time, limit int) ([]events.DomainEvent, error) {
 	input := &dynamodb.QueryInput{
@@ -314,9 +443,13 @@ func (es *DynamoDBEventStore) eventToRecord(event events.DomainEvent) (*EventRec
 	// Generate a unique event ID since DomainEvent doesn't have GetEventID
 	eventID := uuid.New().String()
 
-	// Calculate TTL using configurable duration (default 12 hours)
-	// All events will be automatically deleted after this period
-	ttl := timestamp.Add(es.eventTTL).Unix()
+	// Calculate TTL using configurable duration (default 1 hour)
+	// All events will be automatically deleted after this period; a
+	// non-positive TTL keeps them forever, which event sourcing relies on
+	var ttl int64
+	if es.eventTTL > 0 {
+		ttl = timestamp.Add(es.eventTTL).Unix()
+	}
 
 	// Extract user ID from event data if available
 	userID := ""
@@ -355,107 +488,29 @@ func (es *DynamoDBEventStore) eventToRecord(event events.DomainEvent) (*EventRec
 		GSI1SK: fmt.Sprintf("EVENT#%s", timestamp.Format(time.RFC3339Nano)),
 		GSI2PK: fmt.Sprintf("EVENTTYPE#%s", event.GetEventType()),
 		GSI2SK: fmt.Sprintf("EVENT#%s", timestamp.Format(time.RFC3339Nano)),
+		GSI7PK: outboxPendingPartition(eventID),
+		GSI7SK: outboxSortKey(timestamp, eventID),
 		TTL:    ttl,
 	}, nil
 }
 
-// recordToEvent converts a DynamoDB record back to a domain event
+// recordToEvent converts a DynamoDB record back to its concrete domain event
 func (es *DynamoDBEventStore) recordToEvent(record EventRecord) (events.DomainEvent, error) {
-	// Parse timestamp
 	timestamp, err := time.Parse(time.RFC3339, record.Timestamp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
 	}
 
-	// Create a base event
-	// In a real implementation, you would use a factory pattern
-	// to create the specific event type based on EventType
-	baseEvent := &events.BaseEvent{
-		AggregateID: record.AggregateID,
-		EventType:   record.EventType,
-		Timestamp:   timestamp,
-		Version:     record.Version,
-	}
-
-	// Depending on the event type, create the appropriate concrete event
-	// This is a simplified version - in production, use a proper event factory
-	switch record.EventType {
-	case "node.created":
-		// Parse node ID from event data
-		nodeIDStr, _ := record.EventData["node_id"].(string)
-		nodeID, _ := valueobjects.NewNodeIDFromString(nodeIDStr)
-		userID, _ := record.EventData["user_id"].(string)
-
-		return &events.NodeCreated{
-			BaseEvent: *baseEvent,
-			NodeID:    nodeID,
-			UserID:    userID,
-		}, nil
-
-	case "node.archived":
-		nodeIDStr, _ := record.EventData["node_id"].(string)
-		nodeID, _ := valueobjects.NewNodeIDFromString(nodeIDStr)
-
-		return &events.NodeArchived{
-			BaseEvent: *baseEvent,
-			NodeID:    nodeID,
-		}, nil
-
-	case "NodeDeleted":
-		nodeIDStr, _ := record.EventData["node_id"].(string)
-		nodeID, _ := valueobjects.NewNodeIDFromString(nodeIDStr)
-		userID, _ := record.EventData["user_id"].(string)
-		content, _ := record.EventData["content"].(string)
-
-		// Handle keywords and tags arrays
-		var keywords []string
-		if kwInterface, ok := record.EventData["keywords"].([]any); ok {
-			for _, kw := range kwInterface {
-				if str, ok := kw.(string); ok {
-					keywords = append(keywords, str)
-				}
-			}
-		}
-
-		var tags []string
-		if tagsInterface, ok := record.EventData["tags"].([]any); ok {
-			for _, tag := range tagsInterface {
-				if str, ok := tag.(string); ok {
-					tags = append(tags, str)
-				}
-			}
-		}
-
-		return &events.NodeDeletedEvent{
-			BaseEvent: *baseEvent,
-			NodeID:    nodeID,
-			UserID:    userID,
-			Content:   content,
-			Keywords:  keywords,
-			Tags:      tags,
-		}, nil
-
-	case "graph.created":
-		graphID, _ := record.EventData["graph_id"].(string)
-		userID, _ := record.EventData["user_id"].(string)
-		name, _ := record.EventData["name"].(string)
-
-		return &events.GraphCreated{
-			BaseEvent: *baseEvent,
-			GraphID:   graphID,
-			UserID:    userID,
-			Name:      name,
-		}, nil
-
-	default:
-		// For unknown event types, return the base event
-		// In production, you might want to handle this differently
-		return baseEvent, nil
+	data, err := json.Marshal(record.EventData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
 	}
+
+	return events.Decode(record.EventType, record.AggregateID, record.Version, timestamp, data)
 }
 
 // GetSnapshot retrieves the latest snapshot for an aggregate
-func (es *DynamoDBEventStore) GetSnapshot(ctx context.Context, aggregateID string) (*EventSnapshot, error) {
+func (es *DynamoDBEventStore) GetSnapshot(ctx context.Context, aggregateID string) (*ports.AggregateSnapshot, error) {
 	input := &dynamodb.GetItemInput{
 		TableName: aws.String(es.tableName),
 		Key: map[string]types.AttributeValue{
@@ -478,12 +533,27 @@ func (es *DynamoDBEventStore) GetSnapshot(ctx context.Context, aggregateID strin
 		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
 	}
 
-	return &snapshot, nil
+	timestamp, _ := time.Parse(time.RFC3339Nano, snapshot.Timestamp)
+	return &ports.AggregateSnapshot{
+		AggregateID:   snapshot.AggregateID,
+		AggregateType: snapshot.AggregateType,
+		Version:       snapshot.Version,
+		State:         []byte(snapshot.State),
+		Timestamp:     timestamp,
+	}, nil
 }
 
 // SaveSnapshot saves a snapshot of an aggregate's state
-func (es *DynamoDBEventStore) SaveSnapshot(ctx context.Context, snapshot *EventSnapshot) error {
-	item, err := attributevalue.MarshalMap(snapshot)
+func (es *DynamoDBEventStore) SaveSnapshot(ctx context.Context, snapshot *ports.AggregateSnapshot) error {
+	item, err := attributevalue.MarshalMap(EventSnapshot{
+		PK:            fmt.Sprintf("SNAPSHOT#%s", snapshot.AggregateID),
+		SK:            "LATEST",
+		AggregateID:   snapshot.AggregateID,
+		AggregateType: snapshot.AggregateType,
+		Version:       snapshot.Version,
+		State:         string(snapshot.State),
+		Timestamp:     snapshot.Timestamp.UTC().Format(time.RFC3339Nano),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal snapshot: %w", err)
 	}
@@ -508,107 +578,10 @@ type EventSnapshot struct {
 	AggregateID   string                 `dynamodbav:"AggregateID"`
 	AggregateType string                 `dynamodbav:"AggregateType"`
 	Version       int                    `dynamodbav:"Version"`
-	State         map[string]any `dynamodbav:"State"`
+	State         string                 `dynamodbav:"State"` // JSON encoded aggregate snapshot
 	Timestamp     string                 `dynamodbav:"Timestamp"`
 }
 
-// Outbox Pattern Methods
-
-// GetPendingEvents retrieves events that haven't been published yet
-func (es *DynamoDBEventStore) GetPendingEvents(ctx context.Context, limit int32) ([]*EventRecord, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 100
-	}
-
-	// Query events with PublishStatus = "pending"
-	// We'll use a scan with filter for simplicity, but in production, consider adding a GSI
-	input := &dynamodb.ScanInput{
-		TableName:        aws.String(es.tableName),
-		FilterExpression: aws.String("PublishStatus = :status AND begins_with(PK, :prefix)"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":status": &types.AttributeValueMemberS{Value: string(PublishStatusPending)},
-			":prefix": &types.AttributeValueMemberS{Value: "EVENTS#"},
-		},
-		Limit: aws.Int32(limit),
-	}
-
-	result, err := es.client.Scan(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to scan pending events: %w", err)
-	}
-
-	records := make([]*EventRecord, 0, len(result.Items))
-	for _, item := range result.Items {
-		var record EventRecord
-		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
-			continue // Skip malformed records
-		}
-		records = append(records, &record)
-	}
-
-	return records, nil
-}
-
-// MarkEventAsPublished marks an event as successfully published
-func (es *DynamoDBEventStore) MarkEventAsPublished(ctx context.Context, eventPK, eventSK string) error {
-	now := time.Now().Format(time.RFC3339)
-
-	input := &dynamodb.UpdateItemInput{
-		TableName: aws.String(es.tableName),
-		Key: map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: eventPK},
-			"SK": &types.AttributeValueMemberS{Value: eventSK},
-		},
-		UpdateExpression: aws.String("SET PublishStatus = :published, PublishedAt = :publishedAt"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":published":   &types.AttributeValueMemberS{Value: string(PublishStatusPublished)},
-			":publishedAt": &types.AttributeValueMemberS{Value: now},
-		},
-		ConditionExpression: aws.String("attribute_exists(PK)"), // Ensure event exists
-	}
-
-	_, err := es.client.UpdateItem(ctx, input)
-	if err != nil {
-		return fmt.Errorf("failed to mark event as published: %w", err)
-	}
-
-	return nil
-}
-
-// MarkEventAsFailed marks an event as failed to publish with error details
-func (es *DynamoDBEventStore) MarkEventAsFailed(ctx context.Context, eventPK, eventSK string, errorMsg string, attempts int) error {
-	now := time.Now().Format(time.RFC3339)
-
-	// Determine status based on attempt count
-	status := string(PublishStatusFailed)
-	if attempts < 3 { // Max 3 attempts before marking as permanently failed
-		status = string(PublishStatusPending) // Keep as pending for retry
-	}
-
-	input := &dynamodb.UpdateItemInput{
-		TableName: aws.String(es.tableName),
-		Key: map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: eventPK},
-			"SK": &types.AttributeValueMemberS{Value: eventSK},
-		},
-		UpdateExpression: aws.String("SET PublishStatus = :status, PublishAttempts = :attempts, LastPublishTry = :lastTry, ErrorMessage = :error"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":status":   &types.AttributeValueMemberS{Value: status},
-			":attempts": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", attempts)},
-			":lastTry":  &types.AttributeValueMemberS{Value: now},
-			":error":    &types.AttributeValueMemberS{Value: errorMsg},
-		},
-		ConditionExpression: aws.String("attribute_exists(PK)"), // Ensure event exists
-	}
-
-	_, err := es.client.UpdateItem(ctx, input)
-	if err != nil {
-		return fmt.Errorf("failed to mark event as failed: %w", err)
-	}
-
-	return nil
-}
-
 // DeleteEvents removes all events for an aggregate
 func (es *DynamoDBEventStore) DeleteEvents(ctx context.Context, aggregateID string) error {
 	// First, query all events for this aggregate
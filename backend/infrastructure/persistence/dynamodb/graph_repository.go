@@ -19,21 +19,25 @@ import (
 
 // GraphRepository implements the GraphRepository interface using DynamoDB
 type GraphRepository struct {
-	client    *dynamodb.Client
-	tableName string
-	logger    *zap.Logger
-	edgeRepo  ports.EdgeRepository
-	nodeRepo  ports.NodeRepository
+	client        *dynamodb.Client
+	tableName     string
+	gsi5IndexName string // GSI5 for recency-ordered lookups
+	gsi6IndexName string // GSI6 for public graph listings
+	logger        *zap.Logger
+	edgeRepo      ports.EdgeRepository
+	nodeRepo      ports.NodeRepository
 }
 
 // NewGraphRepository creates a new GraphRepository
-func NewGraphRepository(client *dynamodb.Client, tableName string, logger *zap.Logger) ports.GraphRepository {
+func NewGraphRepository(client *dynamodb.Client, tableName, gsi5IndexName, gsi6IndexName string, logger *zap.Logger) ports.GraphRepository {
 	return &GraphRepository{
-		client:    client,
-		tableName: tableName,
-		logger:    logger,
-		edgeRepo:  nil, // Will be set via SetEdgeRepository
-		nodeRepo:  nil, // Will be set via SetNodeRepository
+		client:        client,
+		tableName:     tableName,
+		gsi5IndexName: gsi5IndexName,
+		gsi6IndexName: gsi6IndexName,
+		logger:        logger,
+		edgeRepo:      nil, // Will be set via SetEdgeRepository
+		nodeRepo:      nil, // Will be set via SetNodeRepository
 	}
 }
 
@@ -65,6 +69,26 @@ type graphItem struct {
 	CreatedAt   string                 `dynamodbav:"CreatedAt"`
 	UpdatedAt   string                 `dynamodbav:"UpdatedAt"`
 	Version     int                    `dynamodbav:"Version"`
+
+	// GSI5 attributes for listing a user's graphs by recent activity
+	GSI5PK string `dynamodbav:"GSI5PK,omitempty"` // USER#userId#GRAPH
+	GSI5SK string `dynamodbav:"GSI5SK,omitempty"` // UPDATED#updatedAt#graphId
+
+	// GSI6 attributes, only present on public graphs
+	GSI6PK string `dynamodbav:"GSI6PK,omitempty"` // PUBLIC#GRAPH
+	GSI6SK string `dynamodbav:"GSI6SK,omitempty"` // UPDATED#updatedAt#graphId
+}
+
+// setIndexKeys fills the secondary index attributes derived from the graph
+func (item *graphItem) setIndexKeys(graph *aggregates.Graph) {
+	activity := activitySortKey(graph.UpdatedAt(), graph.ID().String())
+	item.GSI5PK = fmt.Sprintf("USER#%s#GRAPH", graph.UserID())
+	item.GSI5SK = activity
+
+	if isPublic, _ := graph.Metadata()["isPublic"].(bool); isPublic {
+		item.GSI6PK = publicGraphPartition
+		item.GSI6SK = activity
+	}
 }
 
 // Save persists a graph to DynamoDB
@@ -126,26 +150,38 @@ func (r *GraphRepository) Save(ctx context.Context, graph *aggregates.Graph) err
 		Metadata:    graph.Metadata(),
 		CreatedAt:   graph.CreatedAt().Format(time.RFC3339),
 		UpdatedAt:   graph.UpdatedAt().Format(time.RFC3339),
-		Version:     1, // TODO: Implement versioning
+		Version:     graph.Version(),
 	}
 
+	item.setIndexKeys(graph)
+
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal graph: %w", err)
 	}
 
+	// Only replace the version this graph was loaded at
+	condition := newVersionCondition(graph.PersistedVersion())
 	input := &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+		TableName:                           aws.String(r.tableName),
+		Item:                                av,
+		ConditionExpression:                 condition.expression,
+		ExpressionAttributeNames:            condition.names,
+		ExpressionAttributeValues:           condition.values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	}
 
 	if _, err := r.client.PutItem(ctx, input); err != nil {
+		if conflict := versionConflict(err, "graph "+graph.ID().String(), graph.PersistedVersion()); conflict != nil {
+			return conflict
+		}
 		r.logger.Error("Failed to save graph to DynamoDB",
 			zap.Error(err),
 			zap.String("graphID", graph.ID().String()),
 		)
 		return fmt.Errorf("failed to save graph: %w", err)
 	}
+	graph.MarkPersisted()
 
 	r.logger.Info("Successfully saved graph to DynamoDB",
 		zap.String("graphID", graph.ID().String()),
@@ -186,22 +222,32 @@ func (r *GraphRepository) SaveWithUoW(ctx context.Context, graph *aggregates.Gra
 		Version:     graph.Version(),
 	}
 
+	item.setIndexKeys(graph)
+
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal graph: %w", err)
 	}
 
 	// Register the save operation with the unit of work
+	condition := newVersionCondition(graph.PersistedVersion())
 	transactItem := types.TransactWriteItem{
 		Put: &types.Put{
-			TableName: aws.String(r.tableName),
-			Item:      av,
+			TableName:                           aws.String(r.tableName),
+			Item:                                av,
+			ConditionExpression:                 condition.expression,
+			ExpressionAttributeNames:            condition.names,
+			ExpressionAttributeValues:           condition.values,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 		},
 	}
 
 	if err := dynamoUoW.RegisterSave(transactItem); err != nil {
 		return fmt.Errorf("failed to register graph save: %w", err)
 	}
+	if err := dynamoUoW.RegisterAfterCommit(graph.MarkPersisted); err != nil {
+		return fmt.Errorf("failed to register graph save: %w", err)
+	}
 
 	// Register any uncommitted events from the graph
 	for _, event := range graph.GetUncommittedEvents() {
@@ -288,6 +334,7 @@ func (r *GraphRepository) GetByID(ctx context.Context, id aggregates.GraphID) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to reconstruct graph: %w", err)
 	}
+	graph.RestorePersistedState(item.Version)
 
 	// CRITICAL: Load nodes and edges in parallel for performance
 	var nodes []*entities.Node
@@ -417,6 +464,7 @@ func (r *GraphRepository) GetByUserID(ctx context.Context, userID string) ([]*ag
 				zap.Error(err))
 			continue
 		}
+		graph.RestorePersistedState(graphItem.Version)
 		graphs = append(graphs, graph)
 	}
 
@@ -466,8 +514,10 @@ func (r *GraphRepository) GetUserDefaultGraph(ctx context.Context, userID string
 	if err != nil {
 		return nil, fmt.Errorf("failed to reconstruct graph: %w", err)
 	}
+	graph.RestorePersistedState(item.Version)
 
-	// Load nodes if nodeRepo is available
+	// Load nodes if nodeRepo is available. LoadNode keeps the stored version,
+	// unlike AddNode, which records a new membership.
 	if r.nodeRepo != nil {
 		nodes, err := r.nodeRepo.GetByGraphID(ctx, item.GraphID)
 		if err != nil {
@@ -477,7 +527,7 @@ func (r *GraphRepository) GetUserDefaultGraph(ctx context.Context, userID string
 			)
 		} else {
 			for _, node := range nodes {
-				if err := graph.AddNode(node); err != nil {
+				if err := graph.LoadNode(node); err != nil {
 					r.logger.Debug("Node already in graph or failed to add",
 						zap.String("nodeID", node.ID().String()),
 						zap.Error(err),
@@ -525,6 +575,8 @@ func (r *GraphRepository) GetOrCreateDefaultGraph(ctx context.Context, userID st
 		Version:     1,
 	}
 
+	item.setIndexKeys(graph)
+
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal graph: %w", err)
@@ -545,6 +597,7 @@ func (r *GraphRepository) GetOrCreateDefaultGraph(ctx context.Context, userID st
 		}
 		return nil, fmt.Errorf("failed to save default graph: %w", err)
 	}
+	graph.MarkPersisted()
 
 	r.logger.Info("Default graph created",
 		zap.String("graphID", graph.ID().String()),
@@ -655,23 +708,33 @@ func (r *GraphRepository) UpdateGraphMetadata(ctx context.Context, graphID strin
 		return fmt.Errorf("failed to unmarshal graph: %w", err)
 	}
 
-	// Update the graph metadata with the actual counts
+	// Update the graph metadata with the actual counts, moving the graph to
+	// the front of the activity indexes
+	now := time.Now()
+	activity := activitySortKey(now, graphID)
+	updateExpression := "SET NodeCount = :nodeCount, EdgeCount = :edgeCount, UpdatedAt = :updatedAt, #metadata.#nodeCount = :nodeCount, #metadata.#edgeCount = :edgeCount, GSI5PK = :activityPK, GSI5SK = :activity"
+	if graphItem.GSI6PK != "" {
+		updateExpression += ", GSI6SK = :activity"
+	}
+
 	updateInput := &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", graphItem.UserID)},
 			"SK": &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", graphID)},
 		},
-		UpdateExpression: aws.String("SET NodeCount = :nodeCount, EdgeCount = :edgeCount, UpdatedAt = :updatedAt, #metadata.#nodeCount = :nodeCount, #metadata.#edgeCount = :edgeCount"),
+		UpdateExpression: aws.String(updateExpression),
 		ExpressionAttributeNames: map[string]string{
 			"#metadata":  "Metadata",
 			"#nodeCount": "nodeCount",
 			"#edgeCount": "edgeCount",
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":nodeCount": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", nodeCount)},
-			":edgeCount": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", edgeCount)},
-			":updatedAt": &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+			":nodeCount":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", nodeCount)},
+			":edgeCount":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", edgeCount)},
+			":updatedAt":  &types.AttributeValueMemberS{Value: now.Format(time.RFC3339)},
+			":activityPK": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s#GRAPH", graphItem.UserID)},
+			":activity":   &types.AttributeValueMemberS{Value: activity},
 		},
 	}
 
@@ -0,0 +1,245 @@
+package dynamodb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/versioning"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// GraphVersionStore keeps graph versions in the main table, one partition per
+// graph. The version record is stored as is so versions can be listed without
+// reading the graph state, which is stored gzip compressed to stay well below
+// the item size limit.
+type GraphVersionStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// graphVersionRecord is how a graph version is stored in DynamoDB
+type graphVersionRecord struct {
+	PK      string `dynamodbav:"PK"` // GRAPHVERSION#<graphID>
+	SK      string `dynamodbav:"SK"` // VERSION#<zero padded version>
+	GraphID string `dynamodbav:"GraphID"`
+	Version int    `dynamodbav:"Version"`
+	Summary string `dynamodbav:"Summary"` // JSON encoded versioning.GraphVersion
+	State   []byte `dynamodbav:"State"`   // gzip compressed JSON of the nodes and edges
+}
+
+// graphVersionState is the graph state of a version
+type graphVersionState struct {
+	Nodes []entities.NodeBackup `json:"nodes"`
+	Edges []*aggregates.Edge    `json:"edges"`
+}
+
+// Compile-time interface check
+var _ ports.GraphVersionStore = (*GraphVersionStore)(nil)
+
+// NewGraphVersionStore creates a new DynamoDB graph version store
+func NewGraphVersionStore(client *dynamodb.Client, tableName string) *GraphVersionStore {
+	return &GraphVersionStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func graphVersionPK(graphID string) string {
+	return fmt.Sprintf("GRAPHVERSION#%s", graphID)
+}
+
+func graphVersionKey(graphID string, version int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: graphVersionPK(graphID)},
+		"SK": &types.AttributeValueMemberS{Value: fmt.Sprintf("VERSION#%010d", version)},
+	}
+}
+
+// Save stores a new version of a graph
+func (s *GraphVersionStore) Save(ctx context.Context, snapshot *versioning.GraphSnapshot) error {
+	summary, err := json.Marshal(snapshot.Version)
+	if err != nil {
+		return fmt.Errorf("failed to encode graph version: %w", err)
+	}
+	state, err := compressState(graphVersionState{Nodes: snapshot.Nodes, Edges: snapshot.Edges})
+	if err != nil {
+		return err
+	}
+
+	graphID, version := snapshot.Version.GraphID, snapshot.Version.Version
+	item, err := attributevalue.MarshalMap(graphVersionRecord{
+		PK:      graphVersionPK(graphID),
+		SK:      fmt.Sprintf("VERSION#%010d", version),
+		GraphID: graphID,
+		Version: version,
+		Summary: string(summary),
+		State:   state,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal graph version: %w", err)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return pkgerrors.NewConflictError(fmt.Sprintf("version %d of graph %s already exists", version, graphID)).WithCause(err)
+		}
+		return fmt.Errorf("failed to save graph version: %w", err)
+	}
+	return nil
+}
+
+// Get returns a stored version of a graph
+func (s *GraphVersionStore) Get(ctx context.Context, graphID string, version int) (*versioning.GraphSnapshot, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            graphVersionKey(graphID, version),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph version: %w", err)
+	}
+	if result.Item == nil {
+		return nil, pkgerrors.NewNotFoundError("graph version")
+	}
+	return decodeGraphVersionItem(result.Item)
+}
+
+// Latest returns the newest version of a graph
+func (s *GraphVersionStore) Latest(ctx context.Context, graphID string) (*versioning.GraphSnapshot, error) {
+	result, err := s.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: graphVersionPK(graphID)},
+			":sk": &types.AttributeValueMemberS{Value: "VERSION#"},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+		ConsistentRead:   aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest graph version: %w", err)
+	}
+	if len(result.Items) == 0 {
+		return nil, pkgerrors.NewNotFoundError("graph version")
+	}
+	return decodeGraphVersionItem(result.Items[0])
+}
+
+// List returns the version records of a graph, newest first
+func (s *GraphVersionStore) List(ctx context.Context, graphID string) ([]*versioning.GraphVersion, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: graphVersionPK(graphID)},
+			":sk": &types.AttributeValueMemberS{Value: "VERSION#"},
+		},
+		ProjectionExpression: aws.String("Summary"),
+		ScanIndexForward:     aws.Bool(false),
+	}
+
+	versions := make([]*versioning.GraphVersion, 0)
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query graph versions: %w", err)
+		}
+		for _, item := range result.Items {
+			var record graphVersionRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal graph version: %w", err)
+			}
+			var version versioning.GraphVersion
+			if err := json.Unmarshal([]byte(record.Summary), &version); err != nil {
+				return nil, fmt.Errorf("failed to decode graph version: %w", err)
+			}
+			versions = append(versions, &version)
+		}
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	return versions, nil
+}
+
+// Delete removes a version of a graph
+func (s *GraphVersionStore) Delete(ctx context.Context, graphID string, version int) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       graphVersionKey(graphID, version),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete graph version: %w", err)
+	}
+	return nil
+}
+
+func decodeGraphVersionItem(item map[string]types.AttributeValue) (*versioning.GraphSnapshot, error) {
+	var record graphVersionRecord
+	if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal graph version: %w", err)
+	}
+
+	snapshot := &versioning.GraphSnapshot{}
+	if err := json.Unmarshal([]byte(record.Summary), &snapshot.Version); err != nil {
+		return nil, fmt.Errorf("failed to decode graph version: %w", err)
+	}
+	state, err := decompressState(record.State)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode state of graph version %d: %w", record.Version, err)
+	}
+	snapshot.Nodes = state.Nodes
+	snapshot.Edges = state.Edges
+	return snapshot, nil
+}
+
+func compressState(state graphVersionState) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(state); err != nil {
+		return nil, fmt.Errorf("failed to encode graph state: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress graph state: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressState(data []byte) (*graphVersionState, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var state graphVersionState
+	if err := json.Unmarshal(decoded, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
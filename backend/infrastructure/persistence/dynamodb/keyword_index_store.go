@@ -0,0 +1,40 @@
+package dynamodb
+
+import (
+	"context"
+
+	"backend/application/ports"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// KeywordIndexStore keeps each user's serialized keyword index in the main
+// table as a chunked blob under KEYWORDINDEX#<userID>.
+type KeywordIndexStore struct {
+	blobs *chunkedBlobStore
+}
+
+// Compile-time interface check
+var _ ports.KeywordIndexStore = (*KeywordIndexStore)(nil)
+
+// NewKeywordIndexStore creates a new DynamoDB keyword index store
+func NewKeywordIndexStore(client *dynamodb.Client, tableName string) *KeywordIndexStore {
+	return &KeywordIndexStore{
+		blobs: &chunkedBlobStore{
+			client:    client,
+			tableName: tableName,
+			prefix:    "KEYWORDINDEX",
+			resource:  "keyword index",
+		},
+	}
+}
+
+// Load returns a user's serialized index
+func (s *KeywordIndexStore) Load(ctx context.Context, userID string) ([]byte, error) {
+	return s.blobs.load(ctx, userID)
+}
+
+// Save stores a user's serialized index as a new generation
+func (s *KeywordIndexStore) Save(ctx context.Context, userID string, data []byte) error {
+	return s.blobs.save(ctx, userID, data)
+}
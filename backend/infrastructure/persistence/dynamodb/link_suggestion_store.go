@@ -0,0 +1,178 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// LinkSuggestionStore keeps link suggestions and feedback in the main table,
+// one partition per user
+type LinkSuggestionStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// linkRecord is how a suggestion or a user's feedback is stored in DynamoDB
+type linkRecord struct {
+	PK   string `dynamodbav:"PK"`   // LINKS#<userID>
+	SK   string `dynamodbav:"SK"`   // SUGGESTION#<suggestionID> or FEEDBACK
+	Data string `dynamodbav:"Data"` // JSON encoded ports.LinkSuggestion or ports.LinkFeedback
+}
+
+const linkFeedbackSK = "FEEDBACK"
+
+// Compile-time interface check
+var _ ports.LinkSuggestionStore = (*LinkSuggestionStore)(nil)
+
+// NewLinkSuggestionStore creates a new DynamoDB link suggestion store
+func NewLinkSuggestionStore(client *dynamodb.Client, tableName string) *LinkSuggestionStore {
+	return &LinkSuggestionStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func linkKey(userID, sk string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("LINKS#%s", userID)},
+		"SK": &types.AttributeValueMemberS{Value: sk},
+	}
+}
+
+func suggestionSK(suggestionID string) string {
+	return fmt.Sprintf("SUGGESTION#%s", suggestionID)
+}
+
+// Put stores a suggestion, replacing an earlier one with the same ID
+func (s *LinkSuggestionStore) Put(ctx context.Context, suggestion *ports.LinkSuggestion) error {
+	return s.put(ctx, suggestion.UserID, suggestionSK(suggestion.ID), suggestion, "link suggestion")
+}
+
+// Get returns a user's suggestion
+func (s *LinkSuggestionStore) Get(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error) {
+	var suggestion ports.LinkSuggestion
+	if err := s.get(ctx, userID, suggestionSK(suggestionID), &suggestion, "link suggestion"); err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// ListByUser returns all of a user's suggestions, highest score first
+func (s *LinkSuggestionStore) ListByUser(ctx context.Context, userID string) ([]*ports.LinkSuggestion, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("LINKS#%s", userID)},
+			":sk": &types.AttributeValueMemberS{Value: "SUGGESTION#"},
+		},
+	}
+
+	suggestions := make([]*ports.LinkSuggestion, 0)
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query link suggestions: %w", err)
+		}
+		for _, item := range result.Items {
+			var suggestion ports.LinkSuggestion
+			if err := decodeLinkItem(item, &suggestion, "link suggestion"); err != nil {
+				return nil, err
+			}
+			suggestions = append(suggestions, &suggestion)
+		}
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions, nil
+}
+
+// Delete removes a suggestion
+func (s *LinkSuggestionStore) Delete(ctx context.Context, userID, suggestionID string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       linkKey(userID, suggestionSK(suggestionID)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete link suggestion: %w", err)
+	}
+	return nil
+}
+
+// GetFeedback returns a user's feedback
+func (s *LinkSuggestionStore) GetFeedback(ctx context.Context, userID string) (*ports.LinkFeedback, error) {
+	var feedback ports.LinkFeedback
+	if err := s.get(ctx, userID, linkFeedbackSK, &feedback, "link feedback"); err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// PutFeedback stores a user's feedback
+func (s *LinkSuggestionStore) PutFeedback(ctx context.Context, feedback *ports.LinkFeedback) error {
+	return s.put(ctx, feedback.UserID, linkFeedbackSK, feedback, "link feedback")
+}
+
+func (s *LinkSuggestionStore) put(ctx context.Context, userID, sk string, value interface{}, resource string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", resource, err)
+	}
+	item, err := attributevalue.MarshalMap(linkRecord{
+		PK:   fmt.Sprintf("LINKS#%s", userID),
+		SK:   sk,
+		Data: string(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", resource, err)
+	}
+	if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("failed to save %s: %w", resource, err)
+	}
+	return nil
+}
+
+func (s *LinkSuggestionStore) get(ctx context.Context, userID, sk string, value interface{}, resource string) error {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            linkKey(userID, sk),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", resource, err)
+	}
+	if result.Item == nil {
+		return pkgerrors.NewNotFoundError(resource)
+	}
+	return decodeLinkItem(result.Item, value, resource)
+}
+
+func decodeLinkItem(item map[string]types.AttributeValue, value interface{}, resource string) error {
+	var record linkRecord
+	if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", resource, err)
+	}
+	if err := json.Unmarshal([]byte(record.Data), value); err != nil {
+		return fmt.Errorf("failed to decode %s %s: %w", resource, record.SK, err)
+	}
+	return nil
+}
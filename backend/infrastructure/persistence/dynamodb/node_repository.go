@@ -10,6 +10,7 @@ import (
 	"backend/domain/core/aggregates"
 	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -23,6 +24,7 @@ import (
 type NodeRepository struct {
 	*GenericRepository[*NodeEntity]
 	gsi2IndexName string // For direct NodeID lookups
+	gsi5IndexName string // For recency-ordered lookups
 }
 
 // Compile-time interface check
@@ -99,9 +101,21 @@ func (c *NodeEntityConfig) ToItem(entity *NodeEntity) (map[string]types.Attribut
 	item["GSI2PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("NODE#%s", node.ID().String())}
 	item["GSI2SK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", node.GraphID())}
 
-	// Add embedding if present
+	// Add GSI5 attributes so a user's nodes can be read newest first
+	item["GSI5PK"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s#NODE", node.UserID())}
+	item["GSI5SK"] = &types.AttributeValueMemberS{Value: activitySortKey(node.UpdatedAt(), node.ID().String())}
+
+	// Add embedding and its provenance if present
 	if node.HasEmbedding() {
-		item["Embedding"] = &types.AttributeValueMemberB{Value: node.Embedding().ToBytes()}
+		embedding := node.Embedding()
+		item["Embedding"] = &types.AttributeValueMemberB{Value: embedding.ToBytes()}
+		item["EmbeddingDimensions"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", embedding.Dimensions())}
+		if model := embedding.Model(); model != "" {
+			item["EmbeddingModel"] = &types.AttributeValueMemberS{Value: model}
+		}
+		if hash := embedding.ContentHash(); hash != "" {
+			item["EmbeddingHash"] = &types.AttributeValueMemberS{Value: hash}
+		}
 	}
 
 	// Add community ID if assigned
@@ -247,7 +261,14 @@ func (c *NodeEntityConfig) ParseItem(item map[string]types.AttributeValue) (*Nod
 	if embAttr, ok := item["Embedding"].(*types.AttributeValueMemberB); ok && len(embAttr.Value) > 0 {
 		embedding, err := valueobjects.NewEmbeddingFromBytes(embAttr.Value)
 		if err == nil {
-			node.SetEmbedding(embedding)
+			var model, hash string
+			if attr, ok := item["EmbeddingModel"].(*types.AttributeValueMemberS); ok {
+				model = attr.Value
+			}
+			if attr, ok := item["EmbeddingHash"].(*types.AttributeValueMemberS); ok {
+				hash = attr.Value
+			}
+			node.SetEmbedding(embedding.WithProvenance(model, hash))
 		}
 	}
 
@@ -265,17 +286,26 @@ func (c *NodeEntityConfig) ParseItem(item map[string]types.AttributeValue) (*Nod
 		}
 	}
 
+	// Restore the stored version and modification time last, since the
+	// setters above touch both
+	version := 0
+	if v, ok := item["Version"].(*types.AttributeValueMemberN); ok {
+		fmt.Sscanf(v.Value, "%d", &version)
+	}
+	node.RestorePersistedState(version, updatedAt)
+
 	return &NodeEntity{node: node}, nil
 }
 
 // NewNodeRepository creates a new node repository
-func NewNodeRepository(client *dynamodb.Client, tableName, gsi1IndexName, gsi2IndexName string, logger *zap.Logger) ports.NodeRepository {
+func NewNodeRepository(client *dynamodb.Client, tableName, gsi1IndexName, gsi2IndexName, gsi5IndexName string, logger *zap.Logger) ports.NodeRepository {
 	config := &NodeEntityConfig{}
 	genericRepo := NewGenericRepository(client, tableName, gsi1IndexName, config, logger)
 
 	return &NodeRepository{
 		GenericRepository: genericRepo,
 		gsi2IndexName:     gsi2IndexName,
+		gsi5IndexName:     gsi5IndexName,
 	}
 }
 
@@ -306,15 +336,25 @@ func (r *NodeRepository) saveNode(ctx context.Context, node *entities.Node) erro
 		return err
 	}
 
+	// Only replace the version this node was loaded at
+	condition := newVersionCondition(node.PersistedVersion())
 	input := &dynamodb.PutItemInput{
-		TableName: aws.String(r.GenericRepository.tableName),
-		Item:      item,
+		TableName:                           aws.String(r.GenericRepository.tableName),
+		Item:                                item,
+		ConditionExpression:                 condition.expression,
+		ExpressionAttributeNames:            condition.names,
+		ExpressionAttributeValues:           condition.values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	}
 
 	_, err = r.GenericRepository.client.PutItem(ctx, input)
 	if err != nil {
+		if conflict := versionConflict(err, "node "+node.ID().String(), node.PersistedVersion()); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to save node: %w", err)
 	}
+	node.MarkPersisted()
 
 	r.GenericRepository.logger.Debug("Node saved",
 		zap.String("nodeID", node.ID().String()),
@@ -346,16 +386,24 @@ func (r *NodeRepository) SaveWithUoW(ctx context.Context, node *entities.Node, u
 	}
 
 	// Register the save operation with the unit of work
+	condition := newVersionCondition(node.PersistedVersion())
 	transactItem := types.TransactWriteItem{
 		Put: &types.Put{
-			TableName: aws.String(r.GenericRepository.tableName),
-			Item:      item,
+			TableName:                           aws.String(r.GenericRepository.tableName),
+			Item:                                item,
+			ConditionExpression:                 condition.expression,
+			ExpressionAttributeNames:            condition.names,
+			ExpressionAttributeValues:           condition.values,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 		},
 	}
 
 	if err := dynamoUoW.RegisterSave(transactItem); err != nil {
 		return fmt.Errorf("failed to register node save: %w", err)
 	}
+	if err := dynamoUoW.RegisterAfterCommit(node.MarkPersisted); err != nil {
+		return fmt.Errorf("failed to register node save: %w", err)
+	}
 
 	// Register any uncommitted events from the node
 	for _, event := range node.GetUncommittedEvents() {
@@ -461,6 +509,25 @@ func (r *NodeRepository) Delete(ctx context.Context, id valueobjects.NodeID) err
 	if err != nil {
 		return fmt.Errorf("failed to find node for deletion: %w", err)
 	}
+	return r.deleteNode(ctx, node, node.Version())
+}
+
+// DeleteWithVersion removes a node only if it is still at the expected
+// version, so a delete based on a stale read cannot discard newer changes
+func (r *NodeRepository) DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error {
+	node, err := r.searchForNodeByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find node for deletion: %w", err)
+	}
+	if node.Version() != expectedVersion {
+		return pkgerrors.NewVersionConflictError("node "+id.String(), expectedVersion, node.Version())
+	}
+	return r.deleteNode(ctx, node, expectedVersion)
+}
+
+// deleteNode removes the node item, conditioned on the version it was read at
+func (r *NodeRepository) deleteNode(ctx context.Context, node *entities.Node, expectedVersion int) error {
+	id := node.ID()
 
 	// Delete the node using its graph ID
 	key := map[string]types.AttributeValue{
@@ -468,13 +535,21 @@ func (r *NodeRepository) Delete(ctx context.Context, id valueobjects.NodeID) err
 		"SK": &types.AttributeValueMemberS{Value: fmt.Sprintf("NODE#%s", id.String())},
 	}
 
+	condition := newVersionCondition(expectedVersion)
 	input := &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.GenericRepository.tableName),
-		Key:       key,
+		TableName:                           aws.String(r.GenericRepository.tableName),
+		Key:                                 key,
+		ConditionExpression:                 condition.expression,
+		ExpressionAttributeNames:            condition.names,
+		ExpressionAttributeValues:           condition.values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	}
 
-	_, err = r.GenericRepository.client.DeleteItem(ctx, input)
+	_, err := r.GenericRepository.client.DeleteItem(ctx, input)
 	if err != nil {
+		if conflict := versionConflict(err, "node "+id.String(), expectedVersion); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to delete node: %w", err)
 	}
 
@@ -486,6 +561,67 @@ func (r *NodeRepository) Delete(ctx context.Context, id valueobjects.NodeID) err
 	return nil
 }
 
+// MoveToGraph stores a node that has moved to another graph. Nodes are keyed
+// by graph, so the item under the old graph is deleted and the new one written
+// in one transaction; the node keeps its ID. The delete is conditioned on the
+// version the node was loaded at, like any other write.
+func (r *NodeRepository) MoveToGraph(ctx context.Context, node *entities.Node, fromGraphID string) error {
+	if node.GraphID() == "" {
+		return fmt.Errorf("node must belong to a graph before saving")
+	}
+	if node.GraphID() == fromGraphID {
+		return r.saveNode(ctx, node)
+	}
+
+	config := &NodeEntityConfig{}
+	item, err := config.ToItem(&NodeEntity{node: node})
+	if err != nil {
+		return err
+	}
+
+	condition := newVersionCondition(node.PersistedVersion())
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Delete: &types.Delete{
+					TableName: aws.String(r.GenericRepository.tableName),
+					Key: map[string]types.AttributeValue{
+						"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", fromGraphID)},
+						"SK": &types.AttributeValueMemberS{Value: fmt.Sprintf("NODE#%s", node.ID().String())},
+					},
+					ConditionExpression:                 condition.expression,
+					ExpressionAttributeNames:            condition.names,
+					ExpressionAttributeValues:           condition.values,
+					ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.GenericRepository.tableName),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(PK)"),
+				},
+			},
+		},
+	}
+
+	if _, err := r.GenericRepository.client.TransactWriteItems(ctx, input); err != nil {
+		if conflict := transactionConflict(err); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("failed to move node: %w", err)
+	}
+	node.MarkPersisted()
+
+	r.GenericRepository.logger.Debug("Node moved",
+		zap.String("nodeID", node.ID().String()),
+		zap.String("fromGraphID", fromGraphID),
+		zap.String("graphID", node.GraphID()),
+	)
+
+	return nil
+}
+
 func (r *NodeRepository) Search(ctx context.Context, criteria ports.SearchCriteria) ([]*entities.Node, error) {
 	// For now, use GetByUserID as a simple search
 	// In a real implementation, you'd build complex queries based on criteria
@@ -498,7 +634,14 @@ func (r *NodeRepository) BulkSave(ctx context.Context, nodes []*entities.Node) e
 		nodeEntities[i] = &NodeEntity{node: node}
 	}
 
-	return r.GenericRepository.BatchSave(ctx, nodeEntities)
+	// Batch writes cannot be conditional; the nodes are taken as written
+	if err := r.GenericRepository.BatchSave(ctx, nodeEntities); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		node.MarkPersisted()
+	}
+	return nil
 }
 
 // searchForNodeByID searches for a node by ID using GSI2 for efficient O(1) lookup
@@ -703,7 +846,13 @@ func (r *NodeRepository) SaveBatch(ctx context.Context, nodes []*entities.Node)
 	}
 
 	// Use the improved generic BatchSave with retry logic and error handling
-	return r.GenericRepository.BatchSave(ctx, entities)
+	if err := r.GenericRepository.BatchSave(ctx, entities); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		node.MarkPersisted()
+	}
+	return nil
 }
 
 // DeleteBatch deletes multiple nodes in a batch using improved batch operations
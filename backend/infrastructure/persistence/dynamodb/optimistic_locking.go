@@ -0,0 +1,78 @@
+package dynamodb
+
+import (
+	"errors"
+	"strconv"
+
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// versionCondition is the condition of a write replacing the item stored at
+// persistedVersion. Version 0 stands for an aggregate that has never been
+// saved, so the item must not exist yet.
+type versionCondition struct {
+	expression *string
+	names      map[string]string
+	values     map[string]types.AttributeValue
+}
+
+func newVersionCondition(persistedVersion int) versionCondition {
+	if persistedVersion <= 0 {
+		return versionCondition{
+			expression: aws.String("attribute_not_exists(PK)"),
+		}
+	}
+	return versionCondition{
+		expression: aws.String("#version = :expectedVersion"),
+		names:      map[string]string{"#version": "Version"},
+		values: map[string]types.AttributeValue{
+			":expectedVersion": &types.AttributeValueMemberN{Value: strconv.Itoa(persistedVersion)},
+		},
+	}
+}
+
+// storedVersion reads the Version attribute of an item returned by a failed
+// condition check; 0 when the item does not exist
+func storedVersion(item map[string]types.AttributeValue) int {
+	if v, ok := item["Version"].(*types.AttributeValueMemberN); ok {
+		if version, err := strconv.Atoi(v.Value); err == nil {
+			return version
+		}
+	}
+	return 0
+}
+
+// versionConflict converts a failed version condition into a version
+// conflict; other errors are returned as nil
+func versionConflict(err error, resource string, expectedVersion int) error {
+	var ccf *types.ConditionalCheckFailedException
+	if !errors.As(err, &ccf) {
+		return nil
+	}
+	return pkgerrors.NewVersionConflictError(resource, expectedVersion, storedVersion(ccf.Item)).WithCause(err)
+}
+
+// transactionConflict converts a transaction cancelled by a failed condition
+// into a version conflict; other errors are returned as nil
+func transactionConflict(err error) error {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return nil
+	}
+	for _, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) != "ConditionalCheckFailed" {
+			continue
+		}
+		conflict := pkgerrors.NewConflictError("transaction conflicts with a concurrent write").
+			WithCode(pkgerrors.CodeVersionConflict).
+			WithCause(err)
+		if current := storedVersion(reason.Item); current > 0 {
+			conflict.Details = map[string]interface{}{"current_version": current}
+		}
+		return conflict
+	}
+	return nil
+}
@@ -0,0 +1,511 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Undelivered events carry the GSI7 attributes, so the outbox index holds
+// only the pending queue and the dead letters and never scans the event log.
+// Publishing an event removes it from the index.
+//
+// Every saved event is written to the pending queue, so a single partition
+// would cap event writes at the throughput of one index partition (about
+// 1,000 writes per second). The queue is spread over outboxPendingShards
+// partitions by event ID, and each poll reads all of them. Dead letters are
+// rare and stay in one partition.
+const (
+	outboxPendingPrefix = "OUTBOX#PENDING" // Followed by #<shard>; sorted by next attempt time
+	outboxDeadPartition = "OUTBOX#DEAD"    // Sorted by dead-letter time
+
+	// outboxPendingShards is fixed: entries in shards beyond a lowered count
+	// would never be read again
+	outboxPendingShards = 8
+
+	// outboxTimeFormat has fixed-width fractions so that keys sort by time
+	outboxTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+	// legacySweepPageSize bounds the items a legacy sweep reads per poll
+	legacySweepPageSize = 1000
+)
+
+// outboxSortKey orders index entries by time, then event ID
+func outboxSortKey(at time.Time, eventID string) string {
+	return at.UTC().Format(outboxTimeFormat) + "#" + eventID
+}
+
+// outboxPendingPartition is the pending queue shard holding an event
+func outboxPendingPartition(eventID string) string {
+	h := fnv.New32a()
+	h.Write([]byte(eventID))
+	return fmt.Sprintf("%s#%d", outboxPendingPrefix, h.Sum32()%outboxPendingShards)
+}
+
+// DueEntries returns pending events whose next attempt is due, earliest due
+// first. Each shard yields at most limit entries; the merged result is cut to
+// limit again.
+func (es *DynamoDBEventStore) DueEntries(ctx context.Context, now time.Time, limit int) ([]*ports.OutboxEntry, error) {
+	if err := es.sweepLegacyPending(ctx, now); err != nil {
+		return nil, err
+	}
+
+	var items []map[string]types.AttributeValue
+	for shard := 0; shard < outboxPendingShards; shard++ {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(es.tableName),
+			IndexName:              aws.String(es.outboxIndex),
+			KeyConditionExpression: aws.String("GSI7PK = :pk AND GSI7SK <= :due"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%d", outboxPendingPrefix, shard)},
+				// Event IDs are UUIDs, which sort before "~"
+				":due": &types.AttributeValueMemberS{Value: outboxSortKey(now, "~")},
+			},
+			ScanIndexForward: aws.Bool(true),
+		}
+		if limit > 0 {
+			input.Limit = aws.Int32(int32(limit))
+		}
+
+		result, err := es.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query due outbox events: %w", err)
+		}
+		items = append(items, result.Items...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return outboxItemSortKey(items[i]) < outboxItemSortKey(items[j])
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return es.toOutboxEntries(items)
+}
+
+// sweepLegacyPending moves one page of pending events the outbox index does
+// not see into it: events saved before the index existed, which have no GSI7
+// keys, and events queued before the pending queue was sharded. The sweep
+// resumes where the previous poll stopped and ends after one pass over the
+// table, since every event saved since then is indexed on write.
+func (es *DynamoDBEventStore) sweepLegacyPending(ctx context.Context, now time.Time) error {
+	sweep := es.legacySweep
+	if sweep == nil {
+		return nil
+	}
+	sweep.mu.Lock()
+	defer sweep.mu.Unlock()
+	if sweep.done {
+		return nil
+	}
+
+	condition := "begins_with(PK, :prefix) AND PublishStatus = :pending AND (attribute_not_exists(GSI7PK) OR GSI7PK = :unsharded)"
+	result, err := es.client.Scan(ctx, &dynamodb.ScanInput{
+		TableName:            aws.String(es.tableName),
+		FilterExpression:     aws.String(condition),
+		ProjectionExpression: aws.String("PK, SK, NextPublishTry"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prefix":    &types.AttributeValueMemberS{Value: "EVENTS#"},
+			":pending":   &types.AttributeValueMemberS{Value: string(PublishStatusPending)},
+			":unsharded": &types.AttributeValueMemberS{Value: outboxPendingPrefix},
+		},
+		ExclusiveStartKey: sweep.startKey,
+		Limit:             aws.Int32(legacySweepPageSize),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sweep legacy pending events: %w", err)
+	}
+
+	for _, item := range result.Items {
+		key := map[string]types.AttributeValue{"PK": item["PK"], "SK": item["SK"]}
+		due := now
+		if next, ok := item["NextPublishTry"].(*types.AttributeValueMemberS); ok {
+			if at := parseRecordTime(next.Value); at != nil {
+				due = *at
+			}
+		}
+		eventID := eventIDFromSortKey(key)
+
+		_, err := es.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:           aws.String(es.tableName),
+			Key:                 key,
+			UpdateExpression:    aws.String("SET GSI7PK = :pk, GSI7SK = :sk"),
+			ConditionExpression: aws.String("PublishStatus = :pending AND (attribute_not_exists(GSI7PK) OR GSI7PK = :unsharded)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":        &types.AttributeValueMemberS{Value: outboxPendingPartition(eventID)},
+				":sk":        &types.AttributeValueMemberS{Value: outboxSortKey(due, eventID)},
+				":pending":   &types.AttributeValueMemberS{Value: string(PublishStatusPending)},
+				":unsharded": &types.AttributeValueMemberS{Value: outboxPendingPrefix},
+			},
+		})
+		var ccf *types.ConditionalCheckFailedException
+		if err != nil && !errors.As(err, &ccf) {
+			// Published or rescheduled meanwhile if the condition failed
+			return fmt.Errorf("failed to index legacy pending event: %w", err)
+		}
+	}
+
+	sweep.startKey = result.LastEvaluatedKey
+	sweep.done = result.LastEvaluatedKey == nil
+	return nil
+}
+
+// outboxItemSortKey reads the GSI7 sort key of an index item
+func outboxItemSortKey(item map[string]types.AttributeValue) string {
+	if sk, ok := item["GSI7SK"].(*types.AttributeValueMemberS); ok {
+		return sk.Value
+	}
+	return ""
+}
+
+// MarkPublished records a successful delivery and drops the event from the index
+func (es *DynamoDBEventStore) MarkPublished(ctx context.Context, id string) error {
+	key, err := outboxItemKey(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = es.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(es.tableName),
+		Key:              key,
+		UpdateExpression: aws.String("SET PublishStatus = :published, PublishedAt = :publishedAt REMOVE NextPublishTry, GSI7PK, GSI7SK"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":published":   &types.AttributeValueMemberS{Value: string(PublishStatusPublished)},
+			":publishedAt": &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+		},
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	})
+	if err != nil {
+		return outboxWriteError(err, "failed to mark event as published")
+	}
+	return nil
+}
+
+// ScheduleRetry records a failed attempt and moves the event to its next due time
+func (es *DynamoDBEventStore) ScheduleRetry(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error {
+	key, err := outboxItemKey(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = es.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(es.tableName),
+		Key:       key,
+		UpdateExpression: aws.String("SET PublishStatus = :pending, PublishAttempts = :attempts, LastPublishTry = :lastTry, " +
+			"NextPublishTry = :nextTry, ErrorMessage = :error, GSI7PK = :pk, GSI7SK = :sk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending":  &types.AttributeValueMemberS{Value: string(PublishStatusPending)},
+			":attempts": &types.AttributeValueMemberN{Value: strconv.Itoa(attempts)},
+			":lastTry":  &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+			":nextTry":  &types.AttributeValueMemberS{Value: nextAttemptAt.Format(time.RFC3339)},
+			":error":    &types.AttributeValueMemberS{Value: lastError},
+			":pk":       &types.AttributeValueMemberS{Value: outboxPendingPartition(eventIDFromSortKey(key))},
+			":sk":       &types.AttributeValueMemberS{Value: outboxSortKey(nextAttemptAt, eventIDFromSortKey(key))},
+		},
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	})
+	if err != nil {
+		return outboxWriteError(err, "failed to schedule event retry")
+	}
+	return nil
+}
+
+// DeadLetter parks the event after its final failed attempt. Dead letters
+// are kept until redriven, so their expiry is cleared.
+func (es *DynamoDBEventStore) DeadLetter(ctx context.Context, id string, attempts int, lastError string) error {
+	key, err := outboxItemKey(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = es.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(es.tableName),
+		Key:       key,
+		UpdateExpression: aws.String("SET PublishStatus = :dead, PublishAttempts = :attempts, LastPublishTry = :now, " +
+			"DeadLetteredAt = :now, ErrorMessage = :error, GSI7PK = :pk, GSI7SK = :sk REMOVE NextPublishTry, #ttl"),
+		ExpressionAttributeNames: map[string]string{"#ttl": "TTL"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":dead":     &types.AttributeValueMemberS{Value: string(PublishStatusDeadLettered)},
+			":attempts": &types.AttributeValueMemberN{Value: strconv.Itoa(attempts)},
+			":now":      &types.AttributeValueMemberS{Value: now.Format(time.RFC3339)},
+			":error":    &types.AttributeValueMemberS{Value: lastError},
+			":pk":       &types.AttributeValueMemberS{Value: outboxDeadPartition},
+			":sk":       &types.AttributeValueMemberS{Value: outboxSortKey(now, eventIDFromSortKey(key))},
+		},
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	})
+	if err != nil {
+		return outboxWriteError(err, "failed to dead-letter event")
+	}
+	return nil
+}
+
+// GetEntry retrieves an outbox entry by ID
+func (es *DynamoDBEventStore) GetEntry(ctx context.Context, id string) (*ports.OutboxEntry, error) {
+	key, err := outboxItemKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := es.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(es.tableName),
+		Key:       key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox entry: %w", err)
+	}
+	if result.Item == nil {
+		return nil, pkgerrors.NewNotFoundError("outbox entry")
+	}
+
+	entries, err := es.toOutboxEntries([]map[string]types.AttributeValue{result.Item})
+	if err != nil {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// ListDeadLettered returns dead-lettered events, most recently parked first
+func (es *DynamoDBEventStore) ListDeadLettered(ctx context.Context, limit int) ([]*ports.OutboxEntry, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(es.tableName),
+		IndexName:              aws.String(es.outboxIndex),
+		KeyConditionExpression: aws.String("GSI7PK = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: outboxDeadPartition},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(int32(limit))
+	}
+
+	result, err := es.client.Query(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead-lettered events: %w", err)
+	}
+	return es.toOutboxEntries(result.Items)
+}
+
+// Redrive returns a dead-lettered event to the pending queue, due now, with
+// a fresh expiry window
+func (es *DynamoDBEventStore) Redrive(ctx context.Context, id string) (*ports.OutboxEntry, error) {
+	key, err := outboxItemKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	update := "SET PublishStatus = :pending, PublishAttempts = :zero, GSI7PK = :pk, GSI7SK = :sk REMOVE DeadLetteredAt, NextPublishTry"
+	values := map[string]types.AttributeValue{
+		":pending": &types.AttributeValueMemberS{Value: string(PublishStatusPending)},
+		":zero":    &types.AttributeValueMemberN{Value: "0"},
+		":pk":      &types.AttributeValueMemberS{Value: outboxPendingPartition(eventIDFromSortKey(key))},
+		":sk":      &types.AttributeValueMemberS{Value: outboxSortKey(now, eventIDFromSortKey(key))},
+		":dead":    &types.AttributeValueMemberS{Value: string(PublishStatusDeadLettered)},
+		":failed":  &types.AttributeValueMemberS{Value: string(publishStatusFailed)},
+	}
+	var names map[string]string
+	if es.eventTTL > 0 {
+		update = "SET PublishStatus = :pending, PublishAttempts = :zero, GSI7PK = :pk, GSI7SK = :sk, #ttl = :ttl REMOVE DeadLetteredAt, NextPublishTry"
+		values[":ttl"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(es.eventTTL).Unix(), 10)}
+		names = map[string]string{"#ttl": "TTL"}
+	}
+
+	result, err := es.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(es.tableName),
+		Key:                                 key,
+		UpdateExpression:                    aws.String(update),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ConditionExpression:                 aws.String("PublishStatus IN (:dead, :failed)"),
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			if ccf.Item == nil {
+				return nil, pkgerrors.NewNotFoundError("outbox entry")
+			}
+			return nil, pkgerrors.NewConflictError("only dead-lettered events can be redriven")
+		}
+		return nil, fmt.Errorf("failed to redrive event: %w", err)
+	}
+
+	entries, err := es.toOutboxEntries([]map[string]types.AttributeValue{result.Attributes})
+	if err != nil {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// Stats counts the pending and dead-lettered events. Only the index is
+// read, which holds nothing but the undelivered backlog.
+func (es *DynamoDBEventStore) Stats(ctx context.Context) (*ports.OutboxStats, error) {
+	stats := &ports.OutboxStats{}
+
+	for shard := 0; shard < outboxPendingShards; shard++ {
+		pending := &dynamodb.QueryInput{
+			TableName:                aws.String(es.tableName),
+			IndexName:                aws.String(es.outboxIndex),
+			KeyConditionExpression:   aws.String("GSI7PK = :pk"),
+			ProjectionExpression:     aws.String("#ts"),
+			ExpressionAttributeNames: map[string]string{"#ts": "Timestamp"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%d", outboxPendingPrefix, shard)},
+			},
+		}
+		for {
+			result, err := es.client.Query(ctx, pending)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query pending events: %w", err)
+			}
+			for _, item := range result.Items {
+				stats.Pending++
+				ts, ok := item["Timestamp"].(*types.AttributeValueMemberS)
+				if !ok {
+					continue
+				}
+				occurred, err := time.Parse(time.RFC3339, ts.Value)
+				if err == nil && (stats.OldestPendingAt == nil || occurred.Before(*stats.OldestPendingAt)) {
+					stats.OldestPendingAt = &occurred
+				}
+			}
+			if result.LastEvaluatedKey == nil {
+				break
+			}
+			pending.ExclusiveStartKey = result.LastEvaluatedKey
+		}
+	}
+
+	dead := &dynamodb.QueryInput{
+		TableName:              aws.String(es.tableName),
+		IndexName:              aws.String(es.outboxIndex),
+		KeyConditionExpression: aws.String("GSI7PK = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: outboxDeadPartition},
+		},
+		Select: types.SelectCount,
+	}
+	for {
+		result, err := es.client.Query(ctx, dead)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count dead-lettered events: %w", err)
+		}
+		stats.DeadLettered += int(result.Count)
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		dead.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	return stats, nil
+}
+
+// toOutboxEntries converts event items into outbox entries
+func (es *DynamoDBEventStore) toOutboxEntries(items []map[string]types.AttributeValue) ([]*ports.OutboxEntry, error) {
+	entries := make([]*ports.OutboxEntry, 0, len(items))
+	for _, item := range items {
+		var record EventRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event record: %w", err)
+		}
+		payload, err := json.Marshal(record.EventData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event data: %w", err)
+		}
+
+		entry := &ports.OutboxEntry{
+			ID:             outboxID(record.PK, record.SK),
+			EventType:      record.EventType,
+			AggregateID:    record.AggregateID,
+			UserID:         record.UserID,
+			Version:        record.Version,
+			Payload:        payload,
+			Status:         ports.OutboxStatus(record.PublishStatus),
+			Attempts:       record.PublishAttempts,
+			LastError:      record.ErrorMessage,
+			LastAttemptAt:  parseRecordTime(record.LastPublishTry),
+			DeadLetteredAt: parseRecordTime(record.DeadLetteredAt),
+		}
+		if occurred := parseRecordTime(record.Timestamp); occurred != nil {
+			entry.OccurredAt = *occurred
+		}
+		switch PublishStatus(record.PublishStatus) {
+		case PublishStatusPending:
+			entry.NextAttemptAt = parseRecordTime(record.NextPublishTry)
+			if entry.NextAttemptAt == nil {
+				entry.NextAttemptAt = &entry.OccurredAt
+			}
+		case publishStatusFailed:
+			entry.Status = ports.OutboxStatusDeadLettered
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// outboxID encodes the key of an event item for use in URLs
+func outboxID(pk, sk string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pk + "|" + sk))
+}
+
+// outboxItemKey decodes an outbox ID back into the event item key
+func outboxItemKey(id string) (map[string]types.AttributeValue, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, pkgerrors.NewValidationError("invalid outbox entry ID")
+	}
+	pk, sk, found := strings.Cut(string(raw), "|")
+	if !found || !strings.HasPrefix(pk, "EVENTS#") || !strings.HasPrefix(sk, "EVENT#") {
+		return nil, pkgerrors.NewValidationError("invalid outbox entry ID")
+	}
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: pk},
+		"SK": &types.AttributeValueMemberS{Value: sk},
+	}, nil
+}
+
+// eventIDFromSortKey extracts the event ID from an EVENT#<timestamp>#<event_id> key
+func eventIDFromSortKey(key map[string]types.AttributeValue) string {
+	sk := key["SK"].(*types.AttributeValueMemberS).Value
+	return sk[strings.LastIndex(sk, "#")+1:]
+}
+
+// outboxWriteError maps a failed existence condition to not found
+func outboxWriteError(err error, message string) error {
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		return pkgerrors.NewNotFoundError("outbox entry")
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// parseRecordTime parses an optional RFC3339 attribute
+func parseRecordTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
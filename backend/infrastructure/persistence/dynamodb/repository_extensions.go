@@ -3,107 +3,608 @@ package dynamodb
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"time"
 
 	"backend/application/ports"
 	"backend/domain/core/aggregates"
 	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
 )
 
-// Domain-specific query methods for NodeRepository
+// activityTimeLayout is a fixed-width UTC layout so activity sort keys order
+// lexically by time
+const activityTimeLayout = "2006-01-02T15:04:05.000000000Z"
 
+// publicGraphPartition is the single GSI6 partition holding public graphs
+const publicGraphPartition = "PUBLIC#GRAPH"
 
-// FindConnectedNodes finds all nodes connected to a given node up to a certain depth
-func (r *NodeRepository) FindConnectedNodes(ctx context.Context, nodeID valueobjects.NodeID, maxDepth int) ([]*entities.Node, error) {
-	// TODO: Implement graph traversal query
-	return nil, fmt.Errorf("FindConnectedNodes not yet implemented")
+// maxBatchGetKeys is the DynamoDB limit on keys per BatchGetItem request
+const maxBatchGetKeys = 100
+
+// activitySortKey builds the GSI5/GSI6 sort key for an entity updated at t
+func activitySortKey(t time.Time, id string) string {
+	return fmt.Sprintf("UPDATED#%s#%s", t.UTC().Format(activityTimeLayout), id)
+}
+
+// queryAll runs a query to completion, following LastEvaluatedKey
+func queryAll(ctx context.Context, client *dynamodb.Client, input *dynamodb.QueryInput) ([]map[string]types.AttributeValue, error) {
+	items := make([]map[string]types.AttributeValue, 0)
+	paginator := dynamodb.NewQueryPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+	}
+	return items, nil
+}
+
+// countAll runs a SELECT COUNT query to completion and sums the page counts
+func countAll(ctx context.Context, client *dynamodb.Client, input *dynamodb.QueryInput) (int, error) {
+	input.Select = types.SelectCount
+	total := 0
+	paginator := dynamodb.NewQueryPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, err
+		}
+		total += int(page.Count)
+	}
+	return total, nil
 }
 
+// graphAdjacency reads the source and target of every edge in a graph's
+// partition and returns an undirected adjacency list keyed by node ID
+func graphAdjacency(ctx context.Context, client *dynamodb.Client, tableName, graphID string) (map[string][]string, error) {
+	items, err := queryAll(ctx, client, &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ProjectionExpression:   aws.String("SourceID, TargetID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", graphID)},
+			":sk": &types.AttributeValueMemberS{Value: "EDGE#"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query graph edges: %w", err)
+	}
+
+	adjacency := make(map[string][]string)
+	for _, item := range items {
+		var endpoints struct {
+			SourceID string `dynamodbav:"SourceID"`
+			TargetID string `dynamodbav:"TargetID"`
+		}
+		if err := attributevalue.UnmarshalMap(item, &endpoints); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal edge endpoints: %w", err)
+		}
+		adjacency[endpoints.SourceID] = append(adjacency[endpoints.SourceID], endpoints.TargetID)
+		adjacency[endpoints.TargetID] = append(adjacency[endpoints.TargetID], endpoints.SourceID)
+	}
+	return adjacency, nil
+}
+
+// Domain-specific query methods for NodeRepository
+
+// FindConnectedNodes finds all nodes connected to a given node up to a certain depth.
+// Edges never cross graphs, so the traversal reads the node's graph partition
+// once and fetches the reached nodes with batch gets.
+func (r *NodeRepository) FindConnectedNodes(ctx context.Context, nodeID valueobjects.NodeID, maxDepth int) ([]*entities.Node, error) {
+	if maxDepth <= 0 {
+		maxDepth = 1
+	}
+
+	start, err := r.searchForNodeByID(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	adjacency, err := graphAdjacency(ctx, r.client, r.tableName, start.GraphID())
+	if err != nil {
+		return nil, err
+	}
+
+	visited := map[string]bool{nodeID.String(): true}
+	frontier := []string{nodeID.String()}
+	reached := make([]string, 0)
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, id := range frontier {
+			for _, neighbor := range adjacency[id] {
+				if visited[neighbor] {
+					continue
+				}
+				visited[neighbor] = true
+				next = append(next, neighbor)
+				reached = append(reached, neighbor)
+			}
+		}
+		frontier = next
+	}
 
-// FindRecentlyUpdated finds nodes updated within a time range
+	return r.batchGetGraphNodes(ctx, start.GraphID(), reached)
+}
+
+// FindRecentlyUpdated returns a user's nodes newest first, reading GSI5 in
+// descending order
 func (r *NodeRepository) FindRecentlyUpdated(ctx context.Context, userID string, limit int) ([]*entities.Node, error) {
-	// TODO: Implement time-based query
-	return nil, fmt.Errorf("FindRecentlyUpdated not yet implemented")
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(r.gsi5IndexName),
+		KeyConditionExpression: aws.String("GSI5PK = :pk AND begins_with(GSI5SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s#NODE", userID)},
+			":sk": &types.AttributeValueMemberS{Value: "UPDATED#"},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	var items []map[string]types.AttributeValue
+	if limit > 0 {
+		input.Limit = aws.Int32(int32(limit))
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query recently updated nodes: %w", err)
+		}
+		items = result.Items
+	} else {
+		var err error
+		items, err = queryAll(ctx, r.client, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query recently updated nodes: %w", err)
+		}
+	}
+
+	return r.parseNodes(items), nil
 }
 
-// FindByContentPattern finds nodes with content matching a pattern
+// FindByContentPattern finds a user's nodes whose title or body matches a
+// case-insensitive regular expression. DynamoDB cannot evaluate regular
+// expressions, so the user's nodes are read from GSI1 and matched here.
 func (r *NodeRepository) FindByContentPattern(ctx context.Context, userID string, pattern string) ([]*entities.Node, error) {
-	// TODO: Implement content search
-	return nil, fmt.Errorf("FindByContentPattern not yet implemented")
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content pattern: %w", err)
+	}
+
+	items, err := queryAll(ctx, r.client, r.userNodesQuery(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nodes by user: %w", err)
+	}
+
+	matches := make([]*entities.Node, 0)
+	for _, node := range r.parseNodes(items) {
+		if re.MatchString(node.Content().Title()) || re.MatchString(node.Content().Body()) {
+			matches = append(matches, node)
+		}
+	}
+	return matches, nil
 }
 
-// CountByStatus counts nodes by their status
+// CountByStatus counts a user's nodes per status, projecting only the status
+// attribute from GSI1
 func (r *NodeRepository) CountByStatus(ctx context.Context, userID string) (map[entities.NodeStatus]int, error) {
-	// TODO: Implement status counting
-	return nil, fmt.Errorf("CountByStatus not yet implemented")
+	input := r.userNodesQuery(userID)
+	input.ProjectionExpression = aws.String("#status")
+	input.ExpressionAttributeNames = map[string]string{"#status": "Status"}
+
+	items, err := queryAll(ctx, r.client, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count nodes by status: %w", err)
+	}
+
+	counts := make(map[entities.NodeStatus]int)
+	for _, item := range items {
+		status := entities.StatusDraft
+		if v, ok := item["Status"].(*types.AttributeValueMemberS); ok && v.Value != "" {
+			status = entities.NodeStatus(v.Value)
+		}
+		counts[status]++
+	}
+	return counts, nil
 }
 
-// GetMostConnected finds the most connected nodes in a graph
+// GetMostConnected returns the nodes of a graph with the highest edge degree.
+// Degrees come from the edge endpoints alone, so only the returned nodes are
+// read in full; nodes without edges are not included.
 func (r *NodeRepository) GetMostConnected(ctx context.Context, graphID string, limit int) ([]*entities.Node, error) {
-	// TODO: Implement connection-based query
-	return nil, fmt.Errorf("GetMostConnected not yet implemented")
+	adjacency, err := graphAdjacency(ctx, r.client, r.tableName, graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := make([]string, 0, len(adjacency))
+	for id := range adjacency {
+		ranked = append(ranked, id)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		di, dj := len(adjacency[ranked[i]]), len(adjacency[ranked[j]])
+		if di != dj {
+			return di > dj
+		}
+		return ranked[i] < ranked[j]
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	return r.batchGetGraphNodes(ctx, graphID, ranked)
+}
+
+// userNodesQuery builds the GSI1 query for all of a user's nodes
+func (r *NodeRepository) userNodesQuery(userID string) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(r.indexName),
+		KeyConditionExpression: aws.String("GSI1PK = :pk AND begins_with(GSI1SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", userID)},
+			":sk": &types.AttributeValueMemberS{Value: "NODE#"},
+		},
+	}
+}
+
+// parseNodes converts node items, skipping any that fail to parse
+func (r *NodeRepository) parseNodes(items []map[string]types.AttributeValue) []*entities.Node {
+	nodes := make([]*entities.Node, 0, len(items))
+	for _, item := range items {
+		entity, err := r.config.ParseItem(item)
+		if err != nil {
+			r.logger.Warn("Failed to unmarshal node", zap.Error(err))
+			continue
+		}
+		nodes = append(nodes, entity.node)
+	}
+	return nodes
+}
+
+// batchGetGraphNodes reads nodes of one graph by ID with BatchGetItem and
+// returns them in the order of nodeIDs; IDs that no longer exist are skipped
+func (r *NodeRepository) batchGetGraphNodes(ctx context.Context, graphID string, nodeIDs []string) ([]*entities.Node, error) {
+	found := make(map[string]*entities.Node, len(nodeIDs))
+	for i := 0; i < len(nodeIDs); i += maxBatchGetKeys {
+		end := i + maxBatchGetKeys
+		if end > len(nodeIDs) {
+			end = len(nodeIDs)
+		}
+
+		keys := make([]map[string]types.AttributeValue, 0, end-i)
+		for _, id := range nodeIDs[i:end] {
+			keys = append(keys, r.config.BuildKey(graphID, id))
+		}
+
+		request := map[string]types.KeysAndAttributes{r.tableName: {Keys: keys}}
+		for len(request) > 0 {
+			result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get nodes: %w", err)
+			}
+			for _, node := range r.parseNodes(result.Responses[r.tableName]) {
+				found[node.ID().String()] = node
+			}
+			request = result.UnprocessedKeys
+		}
+	}
+
+	nodes := make([]*entities.Node, 0, len(found))
+	for _, id := range nodeIDs {
+		if node, ok := found[id]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
 }
 
 // Domain-specific query methods for EdgeRepository
 
 // FindByType finds edges of a specific type
 func (r *EdgeRepository) FindByType(ctx context.Context, graphID string, edgeType entities.EdgeType) ([]*aggregates.Edge, error) {
-	// TODO: Implement type-based edge query
-	return nil, fmt.Errorf("FindByType not yet implemented")
+	return r.queryGraphEdges(ctx, graphID, "#type = :type", map[string]string{"#type": "Type"}, map[string]types.AttributeValue{
+		":type": &types.AttributeValueMemberS{Value: string(edgeType)},
+	})
 }
 
-// FindStrongConnections finds edges with weight above threshold
+// FindStrongConnections finds edges with weight at or above the threshold
 func (r *EdgeRepository) FindStrongConnections(ctx context.Context, graphID string, minWeight float64) ([]*aggregates.Edge, error) {
-	// TODO: Implement weight-based query
-	return nil, fmt.Errorf("FindStrongConnections not yet implemented")
+	return r.queryGraphEdges(ctx, graphID, "Weight >= :minWeight", nil, map[string]types.AttributeValue{
+		":minWeight": &types.AttributeValueMemberN{Value: fmt.Sprintf("%g", minWeight)},
+	})
 }
 
 // FindBidirectionalEdges finds all bidirectional edges in a graph
 func (r *EdgeRepository) FindBidirectionalEdges(ctx context.Context, graphID string) ([]*aggregates.Edge, error) {
-	// TODO: Implement bidirectional edge query
-	return nil, fmt.Errorf("FindBidirectionalEdges not yet implemented")
+	return r.queryGraphEdges(ctx, graphID, "Bidirectional = :bidirectional", nil, map[string]types.AttributeValue{
+		":bidirectional": &types.AttributeValueMemberBOOL{Value: true},
+	})
 }
 
-// CountByType counts edges by their type
+// CountByType counts edges by their type, projecting only the type attribute
 func (r *EdgeRepository) CountByType(ctx context.Context, graphID string) (map[entities.EdgeType]int, error) {
-	// TODO: Implement edge type counting
-	return nil, fmt.Errorf("CountByType not yet implemented")
+	input := r.graphEdgesQuery(graphID)
+	input.ProjectionExpression = aws.String("#type")
+	input.ExpressionAttributeNames = map[string]string{"#type": "Type"}
+
+	items, err := queryAll(ctx, r.client, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count edges by type: %w", err)
+	}
+
+	counts := make(map[entities.EdgeType]int)
+	for _, item := range items {
+		if v, ok := item["Type"].(*types.AttributeValueMemberS); ok {
+			counts[entities.EdgeType(v.Value)]++
+		}
+	}
+	return counts, nil
 }
 
-// GetEdgesBetweenNodes finds edges between a set of nodes
+// GetEdgesBetweenNodes finds edges whose endpoints are both in the node set.
+// Edge sort keys start with the source node, so each member's outgoing edges
+// are read with a key prefix query.
 func (r *EdgeRepository) GetEdgesBetweenNodes(ctx context.Context, graphID string, nodeIDs []valueobjects.NodeID) ([]*aggregates.Edge, error) {
-	// TODO: Implement node-set edge query
-	return nil, fmt.Errorf("GetEdgesBetweenNodes not yet implemented")
+	members := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		members[id.String()] = true
+	}
+
+	edges := make([]*aggregates.Edge, 0)
+	for source := range members {
+		items, err := queryAll(ctx, r.client, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", graphID)},
+				":sk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EDGE#%s#", source)},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query edges from node %s: %w", source, err)
+		}
+
+		for _, edge := range r.parseEdges(items) {
+			if members[edge.TargetID.String()] {
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return edges, nil
+}
+
+// graphEdgesQuery builds the partition query for all edges of a graph
+func (r *EdgeRepository) graphEdgesQuery(graphID string) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", graphID)},
+			":sk": &types.AttributeValueMemberS{Value: "EDGE#"},
+		},
+	}
+}
+
+// queryGraphEdges reads a graph's edges that satisfy a filter expression
+func (r *EdgeRepository) queryGraphEdges(ctx context.Context, graphID, filter string, names map[string]string, values map[string]types.AttributeValue) ([]*aggregates.Edge, error) {
+	input := r.graphEdgesQuery(graphID)
+	input.FilterExpression = aws.String(filter)
+	if len(names) > 0 {
+		input.ExpressionAttributeNames = names
+	}
+	for k, v := range values {
+		input.ExpressionAttributeValues[k] = v
+	}
+
+	items, err := queryAll(ctx, r.client, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query edges: %w", err)
+	}
+	return r.parseEdges(items), nil
+}
+
+// parseEdges converts edge items, skipping any that fail to parse
+func (r *EdgeRepository) parseEdges(items []map[string]types.AttributeValue) []*aggregates.Edge {
+	edges := make([]*aggregates.Edge, 0, len(items))
+	for _, item := range items {
+		edge, err := r.parseEdgeItem(item)
+		if err != nil {
+			r.logger.Warn("Failed to parse edge item", zap.Error(err))
+			continue
+		}
+		edges = append(edges, edge)
+	}
+	return edges
 }
 
 // Domain-specific query methods for GraphRepository
 
-// FindByNodeCount finds graphs with node count in range
+// FindByNodeCount finds a user's graphs whose maintained node count is within
+// the bounds; a non-positive maxNodes means no upper bound
 func (r *GraphRepository) FindByNodeCount(ctx context.Context, userID string, minNodes, maxNodes int) ([]*aggregates.Graph, error) {
-	// TODO: Implement node count range query
-	return nil, fmt.Errorf("FindByNodeCount not yet implemented")
+	input := r.userGraphsQuery(userID)
+	input.FilterExpression = aws.String("NodeCount >= :minNodes")
+	input.ExpressionAttributeValues[":minNodes"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", minNodes)}
+	if maxNodes > 0 {
+		input.FilterExpression = aws.String("NodeCount BETWEEN :minNodes AND :maxNodes")
+		input.ExpressionAttributeValues[":maxNodes"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", maxNodes)}
+	}
+
+	items, err := queryAll(ctx, r.client, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query graphs by node count: %w", err)
+	}
+	return r.parseGraphs(items), nil
 }
 
-// FindMostActive finds the most recently updated graphs
+// FindMostActive returns a user's most recently updated graphs from GSI5
 func (r *GraphRepository) FindMostActive(ctx context.Context, userID string, limit int) ([]*aggregates.Graph, error) {
-	// TODO: Implement activity-based query
-	return nil, fmt.Errorf("FindMostActive not yet implemented")
+	return r.queryByActivity(ctx, r.gsi5IndexName, "GSI5", fmt.Sprintf("USER#%s#GRAPH", userID), limit)
 }
 
-// FindPublicGraphs finds all public graphs
+// FindPublicGraphs returns public graphs, most recently updated first, from
+// the sparse GSI6
 func (r *GraphRepository) FindPublicGraphs(ctx context.Context, limit int) ([]*aggregates.Graph, error) {
-	// TODO: Implement public graph query
-	return nil, fmt.Errorf("FindPublicGraphs not yet implemented")
+	return r.queryByActivity(ctx, r.gsi6IndexName, "GSI6", publicGraphPartition, limit)
 }
 
-// GetGraphStatistics gets statistics for a graph
+// GetGraphStatistics computes connectivity statistics from the graph's
+// partition, projecting only node IDs and edge endpoints
 func (r *GraphRepository) GetGraphStatistics(ctx context.Context, graphID aggregates.GraphID) (ports.GraphStatistics, error) {
-	// TODO: Implement statistics aggregation
-	return ports.GraphStatistics{}, fmt.Errorf("GetGraphStatistics not yet implemented")
+	var stats ports.GraphStatistics
+
+	items, err := queryAll(ctx, r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ProjectionExpression:   aws.String("NodeID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("GRAPH#%s", graphID.String())},
+			":sk": &types.AttributeValueMemberS{Value: "NODE#"},
+		},
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to query graph nodes: %w", err)
+	}
+	nodeIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		if v, ok := item["NodeID"].(*types.AttributeValueMemberS); ok {
+			nodeIDs = append(nodeIDs, v.Value)
+		}
+	}
+
+	adjacency, err := graphAdjacency(ctx, r.client, r.tableName, graphID.String())
+	if err != nil {
+		return stats, err
+	}
+
+	stats.NodeCount = len(nodeIDs)
+	totalConnections := 0
+	for _, id := range nodeIDs {
+		degree := len(adjacency[id])
+		totalConnections += degree
+		if degree == 0 {
+			stats.OrphanedNodeCount++
+		}
+		if degree > stats.MaxConnections {
+			stats.MaxConnections = degree
+		}
+	}
+	// Each edge contributes one entry to both endpoints' adjacency
+	for _, neighbors := range adjacency {
+		stats.EdgeCount += len(neighbors)
+	}
+	stats.EdgeCount /= 2
+	if stats.NodeCount > 0 {
+		stats.AverageConnections = float64(totalConnections) / float64(stats.NodeCount)
+	}
+
+	// Connected components over the undirected edge set
+	visited := make(map[string]bool, len(nodeIDs))
+	for _, start := range nodeIDs {
+		if visited[start] {
+			continue
+		}
+		stats.ClusterCount++
+		stack := []string{start}
+		visited[start] = true
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, neighbor := range adjacency[current] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					stack = append(stack, neighbor)
+				}
+			}
+		}
+	}
+
+	return stats, nil
 }
 
-// CountUserGraphs counts graphs for a user
+// CountUserGraphs counts a user's graphs with a key-only COUNT query
 func (r *GraphRepository) CountUserGraphs(ctx context.Context, userID string) (int, error) {
-	// TODO: Implement user graph counting
-	return 0, fmt.Errorf("CountUserGraphs not yet implemented")
-}
\ No newline at end of file
+	count, err := countAll(ctx, r.client, r.userGraphsQuery(userID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user graphs: %w", err)
+	}
+	return count, nil
+}
+
+// userGraphsQuery builds the partition query for all of a user's graphs
+func (r *GraphRepository) userGraphsQuery(userID string) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", userID)},
+			":sk": &types.AttributeValueMemberS{Value: "GRAPH#"},
+		},
+	}
+}
+
+// queryByActivity reads graph metadata from an activity-ordered index, newest
+// first; prefix names the index's key attributes (GSI5 or GSI6)
+func (r *GraphRepository) queryByActivity(ctx context.Context, indexName, prefix, partition string, limit int) ([]*aggregates.Graph, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(indexName),
+		KeyConditionExpression: aws.String(fmt.Sprintf("%sPK = :pk AND begins_with(%sSK, :sk)", prefix, prefix)),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: partition},
+			":sk": &types.AttributeValueMemberS{Value: "UPDATED#"},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	if limit > 0 {
+		input.Limit = aws.Int32(int32(limit))
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query graphs by activity: %w", err)
+		}
+		return r.parseGraphs(result.Items), nil
+	}
+
+	items, err := queryAll(ctx, r.client, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query graphs by activity: %w", err)
+	}
+	return r.parseGraphs(items), nil
+}
+
+// parseGraphs reconstructs graph metadata items, skipping any that fail to parse
+func (r *GraphRepository) parseGraphs(items []map[string]types.AttributeValue) []*aggregates.Graph {
+	graphs := make([]*aggregates.Graph, 0, len(items))
+	for _, item := range items {
+		var gi graphItem
+		if err := attributevalue.UnmarshalMap(item, &gi); err != nil {
+			r.logger.Warn("Failed to unmarshal graph item", zap.Error(err))
+			continue
+		}
+		graph, err := aggregates.ReconstructGraph(
+			gi.GraphID,
+			gi.UserID,
+			gi.Name,
+			gi.Description,
+			gi.IsDefault,
+			gi.CreatedAt,
+			gi.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Warn("Failed to reconstruct graph from item",
+				zap.String("graphID", gi.GraphID),
+				zap.Error(err))
+			continue
+		}
+		graph.RestorePersistedState(gi.Version)
+		graphs = append(graphs, graph)
+	}
+	return graphs
+}
@@ -0,0 +1,204 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TrashStore keeps trashed nodes in the main table, one partition per user.
+// Expiry is not left to DynamoDB TTL because purging a node also removes its
+// event history, which the purge job does before deleting the entry.
+type TrashStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// trashRecord is how a trashed node is stored in DynamoDB
+type trashRecord struct {
+	PK        string `dynamodbav:"PK"` // TRASH#<userID>
+	SK        string `dynamodbav:"SK"` // NODE#<nodeID>
+	UserID    string `dynamodbav:"UserID"`
+	NodeID    string `dynamodbav:"NodeID"`
+	TrashedAt int64  `dynamodbav:"TrashedAt"`         // Unix nanoseconds
+	PurgeAt   int64  `dynamodbav:"PurgeAt,omitempty"` // Unix seconds; absent keeps the entry
+	Data      string `dynamodbav:"Data"`              // JSON encoded ports.TrashedNode
+}
+
+// Compile-time interface check
+var _ ports.TrashStore = (*TrashStore)(nil)
+
+// NewTrashStore creates a new DynamoDB trash store
+func NewTrashStore(client *dynamodb.Client, tableName string) *TrashStore {
+	return &TrashStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func trashKey(userID, nodeID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("TRASH#%s", userID)},
+		"SK": &types.AttributeValueMemberS{Value: fmt.Sprintf("NODE#%s", nodeID)},
+	}
+}
+
+// Put stores a trashed node, replacing an earlier entry for the same node
+func (s *TrashStore) Put(ctx context.Context, trashed *ports.TrashedNode) error {
+	data, err := json.Marshal(trashed)
+	if err != nil {
+		return fmt.Errorf("failed to encode trashed node: %w", err)
+	}
+
+	record := trashRecord{
+		PK:        fmt.Sprintf("TRASH#%s", trashed.UserID),
+		SK:        fmt.Sprintf("NODE#%s", trashed.NodeID),
+		UserID:    trashed.UserID,
+		NodeID:    trashed.NodeID,
+		TrashedAt: trashed.TrashedAt.UnixNano(),
+		Data:      string(data),
+	}
+	if trashed.PurgeAt != nil {
+		record.PurgeAt = trashed.PurgeAt.Unix()
+	}
+
+	item, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trashed node: %w", err)
+	}
+	if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("failed to save trashed node: %w", err)
+	}
+	return nil
+}
+
+// Get returns a user's trashed node
+func (s *TrashStore) Get(ctx context.Context, userID, nodeID string) (*ports.TrashedNode, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            trashKey(userID, nodeID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed node: %w", err)
+	}
+	if result.Item == nil {
+		return nil, pkgerrors.NewNotFoundError("trashed node")
+	}
+	return decodeTrashItem(result.Item)
+}
+
+// ListByUser returns a user's trashed nodes, most recently trashed first
+func (s *TrashStore) ListByUser(ctx context.Context, userID string) ([]*ports.TrashedNode, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :sk)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("TRASH#%s", userID)},
+			":sk": &types.AttributeValueMemberS{Value: "NODE#"},
+		},
+	}
+
+	trashed := make([]*ports.TrashedNode, 0)
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query trashed nodes: %w", err)
+		}
+		for _, item := range result.Items {
+			entry, err := decodeTrashItem(item)
+			if err != nil {
+				return nil, err
+			}
+			trashed = append(trashed, entry)
+		}
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	sort.SliceStable(trashed, func(i, j int) bool {
+		return trashed[i].TrashedAt.After(trashed[j].TrashedAt)
+	})
+	return trashed, nil
+}
+
+// ListExpired returns up to limit trashed nodes that are due to be purged.
+// Trash partitions are per user, so this scans the table; it is meant for the
+// periodic purge job.
+func (s *TrashStore) ListExpired(ctx context.Context, now time.Time, limit int) ([]*ports.TrashedNode, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(s.tableName),
+		FilterExpression: aws.String("begins_with(PK, :pk) AND PurgeAt <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":  &types.AttributeValueMemberS{Value: "TRASH#"},
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	}
+
+	expired := make([]*ports.TrashedNode, 0)
+	for {
+		result, err := s.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trashed nodes: %w", err)
+		}
+		for _, item := range result.Items {
+			entry, err := decodeTrashItem(item)
+			if err != nil {
+				return nil, err
+			}
+			expired = append(expired, entry)
+		}
+		if result.LastEvaluatedKey == nil || (limit > 0 && len(expired) >= limit) {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].PurgeAt.Before(*expired[j].PurgeAt)
+	})
+	if limit > 0 && len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// Delete removes a trashed node
+func (s *TrashStore) Delete(ctx context.Context, userID, nodeID string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       trashKey(userID, nodeID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete trashed node: %w", err)
+	}
+	return nil
+}
+
+func decodeTrashItem(item map[string]types.AttributeValue) (*ports.TrashedNode, error) {
+	var record trashRecord
+	if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trashed node: %w", err)
+	}
+	var trashed ports.TrashedNode
+	if err := json.Unmarshal([]byte(record.Data), &trashed); err != nil {
+		return nil, fmt.Errorf("failed to decode trashed node %s: %w", record.NodeID, err)
+	}
+	return &trashed, nil
+}
@@ -26,6 +26,7 @@ type DynamoDBUnitOfWork struct {
 	transactItems   []types.TransactWriteItem
 	pendingEvents   []events.DomainEvent
 	rollbackActions []func() error
+	commitActions   []func()
 	inTransaction   bool
 }
 
@@ -106,6 +107,16 @@ func (uow *DynamoDBUnitOfWork) RegisterRollback(action func() error) error {
 	return nil
 }
 
+// RegisterAfterCommit registers an action to run once the transaction has
+// been committed, such as recording the versions that were written
+func (uow *DynamoDBUnitOfWork) RegisterAfterCommit(action func()) error {
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	uow.commitActions = append(uow.commitActions, action)
+	return nil
+}
+
 // Commit executes all registered operations atomically
 func (uow *DynamoDBUnitOfWork) Commit(ctx context.Context) error {
 	if !uow.inTransaction {
@@ -148,10 +159,17 @@ func (uow *DynamoDBUnitOfWork) Commit(ctx context.Context) error {
 		_, err := uow.client.TransactWriteItems(ctx, input)
 		if err != nil {
 			uow.executeRollback()
+			if conflict := transactionConflict(err); conflict != nil {
+				return conflict
+			}
 			return fmt.Errorf("transaction failed: %w", err)
 		}
 	}
 
+	for _, action := range uow.commitActions {
+		action()
+	}
+
 	// Events are now persisted with "pending" status using the Outbox pattern
 	// A separate background process will handle publishing them to EventBridge
 	// This ensures that events are never lost even if publishing fails
@@ -196,6 +214,7 @@ func (uow *DynamoDBUnitOfWork) Clear() {
 	uow.transactItems = make([]types.TransactWriteItem, 0)
 	uow.pendingEvents = make([]events.DomainEvent, 0)
 	uow.rollbackActions = make([]func() error, 0)
+	uow.commitActions = make([]func(), 0)
 }
 
 // NodeRepository returns the node repository
@@ -0,0 +1,40 @@
+package dynamodb
+
+import (
+	"context"
+
+	"backend/application/ports"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// VectorIndexStore keeps each user's serialized vector index in the main
+// table as a chunked blob under VECTORINDEX#<userID>.
+type VectorIndexStore struct {
+	blobs *chunkedBlobStore
+}
+
+// Compile-time interface check
+var _ ports.VectorIndexStore = (*VectorIndexStore)(nil)
+
+// NewVectorIndexStore creates a new DynamoDB vector index store
+func NewVectorIndexStore(client *dynamodb.Client, tableName string) *VectorIndexStore {
+	return &VectorIndexStore{
+		blobs: &chunkedBlobStore{
+			client:    client,
+			tableName: tableName,
+			prefix:    "VECTORINDEX",
+			resource:  "vector index",
+		},
+	}
+}
+
+// Load returns a user's serialized index
+func (s *VectorIndexStore) Load(ctx context.Context, userID string) ([]byte, error) {
+	return s.blobs.load(ctx, userID)
+}
+
+// Save stores a user's serialized index as a new generation
+func (s *VectorIndexStore) Save(ctx context.Context, userID string, data []byte) error {
+	return s.blobs.save(ctx, userID, data)
+}
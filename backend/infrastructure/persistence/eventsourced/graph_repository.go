@@ -0,0 +1,200 @@
+package eventsourced
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// GraphRepository decorates a state-based graph repository with event
+// sourcing. The graph stream records membership and edges; member nodes are
+// taken from the stored graph, so they reflect their own current state.
+type GraphRepository struct {
+	ports.GraphRepository
+	nodes  *NodeRepository
+	stream stream
+}
+
+// Compile-time interface check
+var _ ports.GraphRepository = (*GraphRepository)(nil)
+
+// NewGraphRepository wraps inner with event-sourced loading. nodes resolves
+// members that are no longer part of the stored graph when loading an older
+// version.
+func NewGraphRepository(
+	inner ports.GraphRepository,
+	nodes *NodeRepository,
+	eventStore ports.EventStore,
+	snapshots ports.SnapshotStore,
+	snapshotInterval int,
+	logger *zap.Logger,
+) *GraphRepository {
+	return &GraphRepository{
+		GraphRepository: inner,
+		nodes:           nodes,
+		stream:          newStream(eventStore, snapshots, aggregateTypeGraph, snapshotInterval, logger),
+	}
+}
+
+// Unwrap returns the wrapped state repository
+func (r *GraphRepository) Unwrap() ports.GraphRepository {
+	return r.GraphRepository
+}
+
+// Save persists the graph state, then appends the uncommitted events of the
+// graph and its nodes, as the unit of work does
+func (r *GraphRepository) Save(ctx context.Context, graph *aggregates.Graph) error {
+	if err := r.GraphRepository.Save(ctx, graph); err != nil {
+		return err
+	}
+	pending := graph.GetUncommittedEvents()
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := r.stream.eventStore.SaveEvents(ctx, pending); err != nil {
+		return fmt.Errorf("failed to append graph events: %w", err)
+	}
+	return nil
+}
+
+// SaveWithUoW stages the graph in a unit of work, which records its events
+func (r *GraphRepository) SaveWithUoW(ctx context.Context, graph *aggregates.Graph, uow interface{}) error {
+	saver, ok := r.GraphRepository.(interface {
+		SaveWithUoW(ctx context.Context, graph *aggregates.Graph, uow interface{}) error
+	})
+	if !ok {
+		return fmt.Errorf("graph repository does not support unit of work")
+	}
+	return saver.SaveWithUoW(ctx, graph, uow)
+}
+
+// GetByID loads the graph from its event stream. Graphs without a complete
+// history, or whose stream disagrees with the stored version, are returned as
+// stored.
+func (r *GraphRepository) GetByID(ctx context.Context, id aggregates.GraphID) (*aggregates.Graph, error) {
+	stored, err := r.GraphRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, nil
+	}
+	return r.rehydrate(ctx, stored)
+}
+
+// GetGraphsByIDs loads the given graphs, skipping any that no longer exist
+func (r *GraphRepository) GetGraphsByIDs(ctx context.Context, graphIDs []aggregates.GraphID) (map[aggregates.GraphID]*aggregates.Graph, error) {
+	batch, ok := r.GraphRepository.(interface {
+		GetGraphsByIDs(ctx context.Context, graphIDs []aggregates.GraphID) (map[aggregates.GraphID]*aggregates.Graph, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("graph repository does not support batch loading")
+	}
+	stored, err := batch.GetGraphsByIDs(ctx, graphIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[aggregates.GraphID]*aggregates.Graph, len(stored))
+	for id, graph := range stored {
+		if graph == nil {
+			continue
+		}
+		rehydrated, err := r.rehydrate(ctx, graph)
+		if err != nil {
+			return nil, err
+		}
+		result[id] = rehydrated
+	}
+	return result, nil
+}
+
+// History returns the recorded events of a graph in version order
+func (r *GraphRepository) History(ctx context.Context, id aggregates.GraphID) ([]events.DomainEvent, error) {
+	return r.stream.all(ctx, id.String())
+}
+
+// LoadAtVersion rebuilds the graph structure as it was at the given version.
+// Member nodes are resolved at their current state; nodes that have since
+// been deleted are left out together with their edges.
+func (r *GraphRepository) LoadAtVersion(ctx context.Context, id aggregates.GraphID, version int) (*aggregates.Graph, error) {
+	history, err := r.stream.all(ctx, id.String())
+	if err != nil {
+		return nil, err
+	}
+	history = upTo(history, version)
+	if len(history) == 0 {
+		return nil, pkgerrors.NewNotFoundError("graph version")
+	}
+
+	graph, err := aggregates.RehydrateGraph(nil, history, r.nodeSource(ctx, nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rehydrate graph %s at version %d: %w", id, version, err)
+	}
+	return graph, nil
+}
+
+// rehydrate rebuilds a stored graph from its snapshot and newer events
+func (r *GraphRepository) rehydrate(ctx context.Context, stored *aggregates.Graph) (*aggregates.Graph, error) {
+	id := stored.ID().String()
+
+	var snapshot aggregates.GraphSnapshot
+	history, hasSnapshot, found, err := r.stream.latest(ctx, id, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return stored, nil
+	}
+
+	var base *aggregates.GraphSnapshot
+	if hasSnapshot {
+		base = &snapshot
+	} else if _, ok := history[0].(events.GraphCreated); !ok {
+		// The graph predates event sourcing; the stored state is authoritative
+		return stored, nil
+	}
+
+	graph, err := aggregates.RehydrateGraph(base, history, r.nodeSource(ctx, stored))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rehydrate graph %s: %w", id, err)
+	}
+	if graph.Version() != stored.Version() {
+		r.stream.logger.Warn("Graph event stream diverges from stored state",
+			zap.String("graphID", id),
+			zap.Int("streamVersion", graph.Version()),
+			zap.Int("storedVersion", stored.Version()))
+		return stored, nil
+	}
+
+	r.stream.maybeSnapshot(ctx, id, len(history), graph.Version(), graph.Snapshot())
+	return graph, nil
+}
+
+// nodeSource resolves member nodes from the stored graph first and falls back
+// to the node repository for nodes the stored graph no longer holds
+func (r *GraphRepository) nodeSource(ctx context.Context, stored *aggregates.Graph) aggregates.NodeSource {
+	return func(id valueobjects.NodeID) (*entities.Node, bool) {
+		if stored != nil {
+			if node, err := stored.GetNode(id); err == nil {
+				return node, true
+			}
+		}
+		if r.nodes == nil {
+			return nil, false
+		}
+		node, err := r.nodes.GetByID(ctx, id)
+		if err != nil || node == nil {
+			return nil, false
+		}
+		return node, true
+	}
+}
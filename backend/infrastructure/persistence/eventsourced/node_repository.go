@@ -0,0 +1,256 @@
+package eventsourced
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// NodeRepository decorates a state-based node repository with event sourcing.
+// Writes go to the wrapped repository and append the node's uncommitted
+// events to the event store; loads by ID rebuild the node from its latest
+// snapshot and the events recorded after it.
+type NodeRepository struct {
+	ports.NodeRepository
+	stream stream
+}
+
+// Compile-time interface check
+var _ ports.NodeRepository = (*NodeRepository)(nil)
+
+// NewNodeRepository wraps inner with event-sourced loading. A snapshot is
+// taken whenever a load has to replay snapshotInterval events or more.
+func NewNodeRepository(
+	inner ports.NodeRepository,
+	eventStore ports.EventStore,
+	snapshots ports.SnapshotStore,
+	snapshotInterval int,
+	logger *zap.Logger,
+) *NodeRepository {
+	return &NodeRepository{
+		NodeRepository: inner,
+		stream:         newStream(eventStore, snapshots, aggregateTypeNode, snapshotInterval, logger),
+	}
+}
+
+// Unwrap returns the wrapped state repository
+func (r *NodeRepository) Unwrap() ports.NodeRepository {
+	return r.NodeRepository
+}
+
+// Save persists the node state, then appends its uncommitted events. The
+// events stay uncommitted on the node so that callers can still publish them.
+func (r *NodeRepository) Save(ctx context.Context, node *entities.Node) error {
+	if err := r.NodeRepository.Save(ctx, node); err != nil {
+		return err
+	}
+	return r.appendEvents(ctx, node.GetUncommittedEvents())
+}
+
+// BulkSave persists the nodes, then appends their uncommitted events
+func (r *NodeRepository) BulkSave(ctx context.Context, nodes []*entities.Node) error {
+	if err := r.NodeRepository.BulkSave(ctx, nodes); err != nil {
+		return err
+	}
+	var pending []events.DomainEvent
+	for _, node := range nodes {
+		pending = append(pending, node.GetUncommittedEvents()...)
+	}
+	return r.appendEvents(ctx, pending)
+}
+
+// SaveWithUoW stages the node in a unit of work. The wrapped repositories
+// already register the node's events with the unit of work, which writes them
+// to the event store on commit.
+func (r *NodeRepository) SaveWithUoW(ctx context.Context, node *entities.Node, uow interface{}) error {
+	saver, ok := r.NodeRepository.(interface {
+		SaveWithUoW(ctx context.Context, node *entities.Node, uow interface{}) error
+	})
+	if !ok {
+		return fmt.Errorf("node repository does not support unit of work")
+	}
+	return saver.SaveWithUoW(ctx, node, uow)
+}
+
+// DeleteWithVersion removes the node if it is still at the expected version
+func (r *NodeRepository) DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error {
+	deleter, ok := r.NodeRepository.(interface {
+		DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error
+	})
+	if !ok {
+		return fmt.Errorf("node repository does not support conditional deletes")
+	}
+	return deleter.DeleteWithVersion(ctx, id, expectedVersion)
+}
+
+// MoveToGraph stores a node that has moved to another graph. Moves raise no
+// events, so there is nothing to append. Repositories keying nodes by ID alone
+// store the move with a plain save.
+func (r *NodeRepository) MoveToGraph(ctx context.Context, node *entities.Node, fromGraphID string) error {
+	if mover, ok := r.NodeRepository.(interface {
+		MoveToGraph(ctx context.Context, node *entities.Node, fromGraphID string) error
+	}); ok {
+		return mover.MoveToGraph(ctx, node, fromGraphID)
+	}
+	return r.NodeRepository.Save(ctx, node)
+}
+
+// GetByID loads the node from its event stream. The wrapped repository is
+// still consulted first so that deleted nodes stay deleted and so that
+// attributes outside the stream, such as the embedding, are kept. Nodes
+// without any recorded history are returned as stored.
+func (r *NodeRepository) GetByID(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	stored, err := r.NodeRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, nil
+	}
+	return r.rehydrate(ctx, stored)
+}
+
+// FindByID is an alias for GetByID
+func (r *NodeRepository) FindByID(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	return r.GetByID(ctx, id)
+}
+
+// LoadNode implements aggregates.NodeLoader - loads a single node
+func (r *NodeRepository) LoadNode(ctx context.Context, nodeID valueobjects.NodeID) (*entities.Node, error) {
+	return r.GetByID(ctx, nodeID)
+}
+
+// LoadNodes implements aggregates.NodeLoader - loads multiple nodes, skipping missing ones
+func (r *NodeRepository) LoadNodes(ctx context.Context, nodeIDs []valueobjects.NodeID) ([]*entities.Node, error) {
+	byID, err := r.GetNodesByIDs(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*entities.Node, 0, len(byID))
+	for _, id := range nodeIDs {
+		if node, ok := byID[id]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// GetNodesByIDs reads the stored nodes in one batch, then rehydrates each of
+// them. Missing nodes are left out of the result.
+func (r *NodeRepository) GetNodesByIDs(ctx context.Context, nodeIDs []valueobjects.NodeID) (map[valueobjects.NodeID]*entities.Node, error) {
+	batch, ok := r.NodeRepository.(interface {
+		GetNodesByIDs(ctx context.Context, nodeIDs []valueobjects.NodeID) (map[valueobjects.NodeID]*entities.Node, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("node repository does not support batch loading")
+	}
+	stored, err := batch.GetNodesByIDs(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[valueobjects.NodeID]*entities.Node, len(stored))
+	for id, node := range stored {
+		if node == nil {
+			continue
+		}
+		rehydrated, err := r.rehydrate(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+		result[id] = rehydrated
+	}
+	return result, nil
+}
+
+// History returns the recorded events of a node in version order
+func (r *NodeRepository) History(ctx context.Context, id valueobjects.NodeID) ([]events.DomainEvent, error) {
+	return r.stream.all(ctx, id.String())
+}
+
+// LoadAtVersion rebuilds the node as it was at the given version. It works
+// from the full stream, so it is also available for deleted nodes as long as
+// their events are retained.
+func (r *NodeRepository) LoadAtVersion(ctx context.Context, id valueobjects.NodeID, version int) (*entities.Node, error) {
+	history, err := r.stream.all(ctx, id.String())
+	if err != nil {
+		return nil, err
+	}
+	history = upTo(history, version)
+	if len(history) == 0 {
+		return nil, pkgerrors.NewNotFoundError("node version")
+	}
+
+	if !entities.HistoryRecordsState(nil, history) {
+		return nil, pkgerrors.NewConflictError("the history of this node predates recorded content and positions")
+	}
+
+	node, err := entities.RehydrateNode(nil, history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rehydrate node %s at version %d: %w", id, version, err)
+	}
+	return node, nil
+}
+
+// rehydrate rebuilds a stored node from its snapshot and newer events
+func (r *NodeRepository) rehydrate(ctx context.Context, stored *entities.Node) (*entities.Node, error) {
+	id := stored.ID().String()
+
+	var snapshot entities.NodeSnapshot
+	history, hasSnapshot, found, err := r.stream.latest(ctx, id, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return stored, nil
+	}
+
+	var base *entities.NodeSnapshot
+	if hasSnapshot {
+		base = &snapshot
+	} else if _, ok := history[0].(events.NodeCreated); !ok {
+		// The stream starts after the node was created, e.g. for nodes written
+		// before event sourcing was enabled; the stored state is authoritative
+		return stored, nil
+	}
+	if !entities.HistoryRecordsState(base, history) {
+		// Events written before content and positions were serialized cannot
+		// rebuild the node; the stored state is authoritative
+		return stored, nil
+	}
+
+	node, err := entities.RehydrateNode(base, history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rehydrate node %s: %w", id, err)
+	}
+	if node.Version() != stored.Version() {
+		// Some change reached the store without its event; serving the replay
+		// would hand out a stale version and fail the next optimistic save
+		r.stream.logger.Warn("Node event stream diverges from stored state",
+			zap.String("nodeID", id),
+			zap.Int("streamVersion", node.Version()),
+			zap.Int("storedVersion", stored.Version()))
+		return stored, nil
+	}
+	node.AdoptUnsourcedState(stored)
+
+	r.stream.maybeSnapshot(ctx, id, len(history), node.Version(), node.Snapshot())
+	return node, nil
+}
+
+func (r *NodeRepository) appendEvents(ctx context.Context, pending []events.DomainEvent) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := r.stream.eventStore.SaveEvents(ctx, pending); err != nil {
+		return fmt.Errorf("failed to append node events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package eventsourced
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	"backend/infrastructure/persistence/memory"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRepos struct {
+	events *memory.InMemoryEventStore
+	nodes  *NodeRepository
+	graphs *GraphRepository
+}
+
+func newTestRepos(snapshotInterval int) *testRepos {
+	db := memory.NewInMemoryDatabase()
+	stateNodes := memory.NewInMemoryNodeRepository(db)
+	stateGraphs := memory.NewInMemoryGraphRepository(db, stateNodes, memory.NewInMemoryEdgeRepository(db))
+	eventStore := memory.NewInMemoryEventStore(db)
+
+	nodes := NewNodeRepository(stateNodes, eventStore, eventStore, snapshotInterval, nil)
+	return &testRepos{
+		events: eventStore,
+		nodes:  nodes,
+		graphs: NewGraphRepository(stateGraphs, nodes, eventStore, eventStore, snapshotInterval, nil),
+	}
+}
+
+func newTestNode(t *testing.T, graphID aggregates.GraphID, title string) *entities.Node {
+	t.Helper()
+	content, err := valueobjects.NewNodeContent(title, "body of "+title, valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	position, err := valueobjects.NewPosition3D(1, 2, 0)
+	require.NoError(t, err)
+	node, err := entities.NewNode("user-1", content, position)
+	require.NoError(t, err)
+	node.SetGraphID(graphID.String())
+	return node
+}
+
+// save persists the node and marks its events committed, as handlers do
+// after publishing them
+func save(t *testing.T, repo *NodeRepository, node *entities.Node) {
+	t.Helper()
+	require.NoError(t, repo.Save(context.Background(), node))
+	node.MarkEventsAsCommitted()
+}
+
+func TestNodeRepository_RebuildsFromEvents(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(50)
+
+	node := newTestNode(t, "graph-1", "Alpha")
+	save(t, r.nodes, node)
+
+	content, err := valueobjects.NewNodeContent("Alpha v2", "revised", valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	require.NoError(t, node.UpdateContent(content))
+	require.NoError(t, node.AddTag("history"))
+	save(t, r.nodes, node)
+
+	history, err := r.nodes.History(ctx, node.ID())
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "node.created", history[0].GetEventType())
+
+	loaded, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	assert.Equal(t, node.Version(), loaded.Version())
+	assert.Equal(t, "Alpha v2", loaded.Content().Title())
+	assert.Equal(t, []string{"history"}, loaded.GetTags())
+
+	original, err := r.nodes.LoadAtVersion(ctx, node.ID(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", original.Content().Title())
+	assert.Empty(t, original.GetTags())
+}
+
+func TestNodeRepository_TakesSnapshots(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(3)
+
+	node := newTestNode(t, "graph-1", "Busy")
+	save(t, r.nodes, node)
+
+	_, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	snapshot, err := r.events.GetSnapshot(ctx, node.ID().String())
+	require.NoError(t, err)
+	assert.Nil(t, snapshot, "no snapshot before the interval is reached")
+
+	for i := 0; i < 3; i++ {
+		position, err := valueobjects.NewPosition3D(float64(i), 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, node.MoveTo(position))
+		save(t, r.nodes, node)
+	}
+
+	_, err = r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	snapshot, err = r.events.GetSnapshot(ctx, node.ID().String())
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, node.Version(), snapshot.Version)
+	assert.Equal(t, "node", snapshot.AggregateType)
+
+	// Later loads start from the snapshot and replay only newer events
+	require.NoError(t, node.Publish())
+	save(t, r.nodes, node)
+
+	loaded, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	assert.True(t, loaded.IsPublished())
+	assert.Equal(t, node.Version(), loaded.Version())
+}
+
+func TestNodeRepository_FallsBackWithoutHistory(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(50)
+
+	// Written straight to the state repository, so it has no history
+	node := newTestNode(t, "graph-1", "Legacy")
+	require.NoError(t, r.nodes.Unwrap().Save(ctx, node))
+
+	loaded, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	assert.Equal(t, node.Version(), loaded.Version())
+	assert.Equal(t, "Legacy", loaded.Content().Title())
+}
+
+func TestNodeRepository_FallsBackOnLegacyEvents(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(50)
+
+	node := newTestNode(t, "graph-1", "Alpha")
+	save(t, r.nodes, node)
+
+	content, err := valueobjects.NewNodeContent("Alpha v2", "revised", valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	require.NoError(t, node.UpdateContent(content))
+	require.NoError(t, r.nodes.Unwrap().Save(ctx, node))
+
+	// Content updates used to be stored with their content as an empty object
+	id := node.ID().String()
+	legacy, err := events.Decode("node.content_updated", id, 2, time.Now(), []byte(fmt.Sprintf(
+		`{"aggregate_id":%q,"event_type":"node.content_updated","version":2,"old_content":{},"new_content":{}}`, id,
+	)))
+	require.NoError(t, err)
+	require.NoError(t, r.events.SaveEvents(ctx, []events.DomainEvent{legacy}))
+
+	loaded, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.Version())
+	assert.Equal(t, "Alpha v2", loaded.Content().Title())
+
+	_, err = r.nodes.LoadAtVersion(ctx, node.ID(), 2)
+	assert.True(t, pkgerrors.IsConflict(err))
+}
+
+func TestGraphRepository_RebuildsFromEvents(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(50)
+
+	graph, err := aggregates.NewGraph("user-1", "Research")
+	require.NoError(t, err)
+	a := newTestNode(t, graph.ID(), "Alpha")
+	b := newTestNode(t, graph.ID(), "Beta")
+	require.NoError(t, graph.AddNode(a))
+	require.NoError(t, graph.AddNode(b))
+	save(t, r.nodes, a)
+	save(t, r.nodes, b)
+	require.NoError(t, r.graphs.Save(ctx, graph))
+	graph.MarkEventsAsCommitted()
+	beforeEdge := graph.Version()
+
+	_, err = graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+	require.NoError(t, r.graphs.Save(ctx, graph))
+	graph.MarkEventsAsCommitted()
+
+	loaded, err := r.graphs.GetByID(ctx, graph.ID())
+	require.NoError(t, err)
+	assert.Equal(t, graph.Version(), loaded.Version())
+	assert.Equal(t, 2, loaded.NodeCount())
+	assert.Equal(t, 1, loaded.EdgeCount())
+
+	earlier, err := r.graphs.LoadAtVersion(ctx, graph.ID(), beforeEdge)
+	require.NoError(t, err)
+	assert.Equal(t, beforeEdge, earlier.Version())
+	assert.Equal(t, 2, earlier.NodeCount())
+	assert.Equal(t, 0, earlier.EdgeCount())
+}
@@ -0,0 +1,140 @@
+// Package eventsourced provides repository decorators that rebuild aggregates
+// from their event streams. The wrapped state repositories keep serving
+// queries and stay the write target, so switching the mode on or off needs no
+// migration; loads by ID replay the latest snapshot plus newer events.
+package eventsourced
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/events"
+
+	"go.uber.org/zap"
+)
+
+// Aggregate types recorded with snapshots
+const (
+	aggregateTypeNode  = "node"
+	aggregateTypeGraph = "graph"
+)
+
+// DefaultSnapshotInterval is used when a non-positive interval is configured
+const DefaultSnapshotInterval = 50
+
+// stream reads event streams and snapshots for one aggregate type
+type stream struct {
+	eventStore    ports.EventStore
+	snapshots     ports.SnapshotStore
+	aggregateType string
+	interval      int
+	logger        *zap.Logger
+}
+
+func newStream(eventStore ports.EventStore, snapshots ports.SnapshotStore, aggregateType string, interval int, logger *zap.Logger) stream {
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return stream{
+		eventStore:    eventStore,
+		snapshots:     snapshots,
+		aggregateType: aggregateType,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// latest loads the latest snapshot into state and returns the events recorded
+// after it, in version order. found is false when the aggregate has neither a
+// snapshot nor any events, i.e. it predates event sourcing.
+func (s stream) latest(ctx context.Context, aggregateID string, state any) (history []events.DomainEvent, hasSnapshot, found bool, err error) {
+	version := 0
+	if s.snapshots != nil {
+		snapshot, err := s.snapshots.GetSnapshot(ctx, aggregateID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to load snapshot: %w", err)
+		}
+		if snapshot != nil {
+			if err := json.Unmarshal(snapshot.State, state); err != nil {
+				return nil, false, false, fmt.Errorf("failed to decode %s snapshot: %w", s.aggregateType, err)
+			}
+			version = snapshot.Version
+			hasSnapshot = true
+		}
+	}
+
+	history, err = s.eventStore.GetEventsAfter(ctx, aggregateID, version)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("failed to load events: %w", err)
+	}
+	sortByVersion(history)
+
+	return history, hasSnapshot, hasSnapshot || len(history) > 0, nil
+}
+
+// all returns the full event stream of an aggregate in version order
+func (s stream) all(ctx context.Context, aggregateID string) ([]events.DomainEvent, error) {
+	history, err := s.eventStore.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	sortByVersion(history)
+	return history, nil
+}
+
+// maybeSnapshot saves a snapshot once enough events had to be replayed. A
+// failed snapshot only costs a longer replay next time, so it is logged
+// rather than returned.
+func (s stream) maybeSnapshot(ctx context.Context, aggregateID string, replayed, version int, state any) {
+	if s.snapshots == nil || replayed < s.interval {
+		return
+	}
+
+	data, err := json.Marshal(state)
+	if err == nil {
+		err = s.snapshots.SaveSnapshot(ctx, &ports.AggregateSnapshot{
+			AggregateID:   aggregateID,
+			AggregateType: s.aggregateType,
+			Version:       version,
+			State:         data,
+			Timestamp:     time.Now(),
+		})
+	}
+	if err != nil {
+		s.logger.Warn("Failed to save snapshot",
+			zap.String("aggregateType", s.aggregateType),
+			zap.String("aggregateID", aggregateID),
+			zap.Error(err))
+		return
+	}
+
+	s.logger.Debug("Saved snapshot",
+		zap.String("aggregateType", s.aggregateType),
+		zap.String("aggregateID", aggregateID),
+		zap.Int("version", version),
+		zap.Int("replayedEvents", replayed))
+}
+
+// upTo returns the events at or below version
+func upTo(history []events.DomainEvent, version int) []events.DomainEvent {
+	result := make([]events.DomainEvent, 0, len(history))
+	for _, event := range history {
+		if event.GetVersion() <= version {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func sortByVersion(history []events.DomainEvent) {
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].GetVersion() < history[j].GetVersion()
+	})
+}
@@ -0,0 +1,62 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/projections"
+	pkgerrors "backend/pkg/errors"
+)
+
+// CheckpointStore keeps projection checkpoints in the checkpoints bucket. A
+// checkpoint and the projection state it carries are written in one
+// transaction, so they survive restarts together.
+type CheckpointStore struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ projections.CheckpointStore = (*CheckpointStore)(nil)
+
+// NewCheckpointStore creates a new file-backed checkpoint store
+func NewCheckpointStore(store *Store) *CheckpointStore {
+	return &CheckpointStore{store: store}
+}
+
+// SavePosition stores the position unless a further one is already stored
+func (s *CheckpointStore) SavePosition(ctx context.Context, position *projections.ProjectionPosition) error {
+	return s.store.Update(func(tx *Tx) error {
+		var current projections.ProjectionPosition
+		err := tx.Get(bucketCheckpoints, position.ProjectionName, &current)
+		if err != nil && err != ErrNotFound {
+			return err
+		}
+		if err == nil && current.IsAfter(position) {
+			return pkgerrors.NewConflictError(fmt.Sprintf(
+				"checkpoint of projection %s is already at position %d", position.ProjectionName, current.Position))
+		}
+		return tx.Put(bucketCheckpoints, position.ProjectionName, position)
+	})
+}
+
+// GetPosition returns the stored position, or nil if there is none
+func (s *CheckpointStore) GetPosition(ctx context.Context, projectionName string) (*projections.ProjectionPosition, error) {
+	var position projections.ProjectionPosition
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketCheckpoints, projectionName, &position)
+	})
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	return &position, nil
+}
+
+// DeletePosition removes the stored position, if any
+func (s *CheckpointStore) DeletePosition(ctx context.Context, projectionName string) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Delete(bucketCheckpoints, projectionName)
+	})
+}
@@ -0,0 +1,53 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	pkgerrors "backend/pkg/errors"
+)
+
+// CommunityRepository keeps community partitions in the communities bucket,
+// keyed by graph
+type CommunityRepository struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ ports.CommunityRepository = (*CommunityRepository)(nil)
+
+// NewCommunityRepository creates a new file-backed community repository
+func NewCommunityRepository(store *Store) *CommunityRepository {
+	return &CommunityRepository{store: store}
+}
+
+// GetByGraphID returns the partition of a graph
+func (r *CommunityRepository) GetByGraphID(ctx context.Context, graphID string) (*entities.CommunityPartition, error) {
+	var partition entities.CommunityPartition
+	err := r.store.View(func(tx *Tx) error {
+		return tx.Get(bucketCommunities, graphID, &partition)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("community partition")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community partition: %w", err)
+	}
+	return &partition, nil
+}
+
+// Save replaces the partition of a graph
+func (r *CommunityRepository) Save(ctx context.Context, partition *entities.CommunityPartition) error {
+	return r.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketCommunities, partition.GraphID, partition)
+	})
+}
+
+// Delete removes the partition of a graph
+func (r *CommunityRepository) Delete(ctx context.Context, graphID string) error {
+	return r.store.Update(func(tx *Tx) error {
+		return tx.Delete(bucketCommunities, graphID)
+	})
+}
@@ -0,0 +1,141 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/application/ports"
+
+	"go.uber.org/zap"
+)
+
+// errLockHeld signals lock contention to the retry loop
+var errLockHeld = errors.New("lock already held")
+
+// lockRecord is the stored form of a held lock
+type lockRecord struct {
+	LockID     string    `json:"lock_id"`
+	Owner      string    `json:"owner"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// DistributedLock provides leases over the locks bucket. Because the store
+// serializes writers across processes, it coordinates the API and the worker
+// the same way the DynamoDB conditional-write lock does.
+type DistributedLock struct {
+	store  *Store
+	logger *zap.Logger
+}
+
+// Compile-time interface check
+var _ ports.DistributedLock = (*DistributedLock)(nil)
+
+// NewDistributedLock creates a new file-backed distributed lock
+func NewDistributedLock(store *Store, logger *zap.Logger) *DistributedLock {
+	return &DistributedLock{store: store, logger: logger}
+}
+
+// AcquireLock attempts to acquire the lock for resource once
+func (dl *DistributedLock) AcquireLock(ctx context.Context, resource, owner string, lockDuration time.Duration) (*Lock, error) {
+	now := time.Now()
+	record := lockRecord{
+		LockID:     fmt.Sprintf("%s_%d", owner, now.UnixNano()),
+		Owner:      owner,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(lockDuration),
+	}
+
+	err := dl.store.Update(func(tx *Tx) error {
+		var held lockRecord
+		if err := tx.Get(bucketLocks, resource, &held); err == nil && held.ExpiresAt.After(now) {
+			return errLockHeld
+		}
+		return tx.Put(bucketLocks, resource, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dl.logger.Debug("Lock acquired successfully",
+		zap.String("resource", resource),
+		zap.String("lockID", record.LockID),
+		zap.String("owner", owner),
+		zap.Duration("duration", lockDuration),
+	)
+	return &Lock{
+		distributedLock: dl,
+		resource:        resource,
+		lockID:          record.LockID,
+		expiresAt:       record.ExpiresAt,
+	}, nil
+}
+
+// TryAcquire implements ports.DistributedLock, retrying with backoff until timeout
+func (dl *DistributedLock) TryAcquire(ctx context.Context, resource, owner string, lockDuration, timeout time.Duration) (ports.Lock, error) {
+	deadline := time.Now().Add(timeout)
+	retryInterval := 50 * time.Millisecond
+
+	for {
+		lock, err := dl.AcquireLock(ctx, resource, owner, lockDuration)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("timeout acquiring lock for resource: %s", resource)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryInterval):
+			if retryInterval < time.Second {
+				retryInterval = retryInterval * 3 / 2
+			}
+		}
+	}
+}
+
+// ReleaseLock releases the lock if it is still held under lockID
+func (dl *DistributedLock) ReleaseLock(ctx context.Context, resource, lockID string) error {
+	return dl.store.Update(func(tx *Tx) error {
+		var held lockRecord
+		if err := tx.Get(bucketLocks, resource, &held); err != nil {
+			if err == ErrNotFound {
+				return nil // Lock is already gone, which is what we wanted
+			}
+			return err
+		}
+		if held.LockID != lockID {
+			dl.logger.Warn("Lock already released or owned by someone else",
+				zap.String("resource", resource),
+				zap.String("lockID", lockID),
+			)
+			return nil
+		}
+		return tx.Delete(bucketLocks, resource)
+	})
+}
+
+// Lock represents an acquired file-backed lock
+type Lock struct {
+	distributedLock *DistributedLock
+	resource        string
+	lockID          string
+	expiresAt       time.Time
+}
+
+// Release releases the lock
+func (l *Lock) Release(ctx context.Context) error {
+	return l.distributedLock.ReleaseLock(ctx, l.resource, l.lockID)
+}
+
+// IsExpired checks if the lock has expired
+func (l *Lock) IsExpired() bool {
+	return time.Now().After(l.expiresAt)
+}
@@ -0,0 +1,275 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+
+	"go.uber.org/zap"
+)
+
+// EdgeRepository implements ports.EdgeRepository on the file store.
+// Edges are keyed graph|source|target so a graph's edges are read by prefix.
+type EdgeRepository struct {
+	store  *Store
+	logger *zap.Logger
+}
+
+// Compile-time interface check
+var _ ports.EdgeRepository = (*EdgeRepository)(nil)
+
+// NewEdgeRepository creates a new file-backed edge repository
+func NewEdgeRepository(store *Store, logger *zap.Logger) *EdgeRepository {
+	return &EdgeRepository{store: store, logger: logger}
+}
+
+// Save persists a new edge; saving an edge that already exists is an error
+func (r *EdgeRepository) Save(ctx context.Context, graphID string, edge *aggregates.Edge) error {
+	record := newEdgeRecord(graphID, edge)
+	key := edgeKey(graphID, record.SourceID, record.TargetID)
+	return r.store.Update(func(tx *Tx) error {
+		if tx.Exists(bucketEdges, key) {
+			return fmt.Errorf("edge already exists between these nodes")
+		}
+		return tx.Put(bucketEdges, key, record)
+	})
+}
+
+// SaveWithUoW stages an edge write in the unit of work. Unlike Save it
+// upserts, because graph saves re-register every edge the graph holds.
+func (r *EdgeRepository) SaveWithUoW(ctx context.Context, graphID string, edge *aggregates.Edge, uow interface{}) error {
+	fileUoW, ok := uow.(*UnitOfWork)
+	if !ok {
+		return fmt.Errorf("invalid unit of work type")
+	}
+
+	record := newEdgeRecord(graphID, edge)
+	key := edgeKey(graphID, record.SourceID, record.TargetID)
+	if err := fileUoW.register(func(tx *Tx) error {
+		return tx.Put(bucketEdges, key, record)
+	}); err != nil {
+		return fmt.Errorf("failed to register edge save: %w", err)
+	}
+	return nil
+}
+
+// GetByGraphID retrieves all edges for a graph
+func (r *EdgeRepository) GetByGraphID(ctx context.Context, graphID string) ([]*aggregates.Edge, error) {
+	return r.scan(edgePrefix(graphID), nil)
+}
+
+// GetByNodeID retrieves all edges where the node is source or target
+func (r *EdgeRepository) GetByNodeID(ctx context.Context, nodeID string) ([]*aggregates.Edge, error) {
+	return r.scan("", func(rec *edgeRecord) bool {
+		return rec.SourceID == nodeID || rec.TargetID == nodeID
+	})
+}
+
+// Delete removes the edge between two nodes
+func (r *EdgeRepository) Delete(ctx context.Context, graphID string, sourceID, targetID string) error {
+	return r.store.Update(func(tx *Tx) error {
+		return tx.Delete(bucketEdges, edgeKey(graphID, sourceID, targetID))
+	})
+}
+
+// DeleteByNodeID removes all edges of a graph touching a node
+func (r *EdgeRepository) DeleteByNodeID(ctx context.Context, graphID string, nodeID string) error {
+	return r.DeleteByNodeIDs(ctx, graphID, []string{nodeID})
+}
+
+// DeleteByNodeIDs removes all edges of a graph touching any of the nodes
+func (r *EdgeRepository) DeleteByNodeIDs(ctx context.Context, graphID string, nodeIDs []string) error {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	targets := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		targets[id] = true
+	}
+
+	var deleted int
+	err := r.store.Update(func(tx *Tx) error {
+		var keys []string
+		err := tx.ForEach(bucketEdges, edgePrefix(graphID), func(key string, value json.RawMessage) error {
+			var record edgeRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode edge %s: %w", key, err)
+			}
+			if targets[record.SourceID] || targets[record.TargetID] {
+				keys = append(keys, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := tx.Delete(bucketEdges, key); err != nil {
+				return err
+			}
+		}
+		deleted = len(keys)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug("Deleted edges for nodes",
+		zap.String("graphID", graphID),
+		zap.Int("nodes", len(nodeIDs)),
+		zap.Int("edges", deleted),
+	)
+	return nil
+}
+
+// FindByType finds edges of a given type in a graph
+func (r *EdgeRepository) FindByType(ctx context.Context, graphID string, edgeType entities.EdgeType) ([]*aggregates.Edge, error) {
+	return r.scan(edgePrefix(graphID), func(rec *edgeRecord) bool {
+		return rec.Type == string(edgeType)
+	})
+}
+
+// FindStrongConnections finds edges with at least the given weight
+func (r *EdgeRepository) FindStrongConnections(ctx context.Context, graphID string, minWeight float64) ([]*aggregates.Edge, error) {
+	return r.scan(edgePrefix(graphID), func(rec *edgeRecord) bool {
+		return rec.Weight >= minWeight
+	})
+}
+
+// FindBidirectionalEdges finds edges marked bidirectional
+func (r *EdgeRepository) FindBidirectionalEdges(ctx context.Context, graphID string) ([]*aggregates.Edge, error) {
+	return r.scan(edgePrefix(graphID), func(rec *edgeRecord) bool {
+		return rec.Bidirectional
+	})
+}
+
+// CountByType counts a graph's edges per type
+func (r *EdgeRepository) CountByType(ctx context.Context, graphID string) (map[entities.EdgeType]int, error) {
+	edges, err := r.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[entities.EdgeType]int)
+	for _, edge := range edges {
+		counts[edge.Type]++
+	}
+	return counts, nil
+}
+
+// GetEdgesBetweenNodes returns edges whose endpoints are both in nodeIDs
+func (r *EdgeRepository) GetEdgesBetweenNodes(ctx context.Context, graphID string, nodeIDs []valueobjects.NodeID) ([]*aggregates.Edge, error) {
+	members := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		members[id.String()] = true
+	}
+	return r.scan(edgePrefix(graphID), func(rec *edgeRecord) bool {
+		return members[rec.SourceID] && members[rec.TargetID]
+	})
+}
+
+// Compile-time interface check for lazy graph loading
+var _ aggregates.EdgeLoader = (*EdgeRepository)(nil)
+
+// LoadEdge implements aggregates.EdgeLoader - loads an edge by its
+// "sourceID->targetID" key
+func (r *EdgeRepository) LoadEdge(ctx context.Context, edgeKey string) (*aggregates.Edge, error) {
+	parts := strings.Split(edgeKey, "->")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid edge key format: %s", edgeKey)
+	}
+	edges, err := r.scan("", func(rec *edgeRecord) bool {
+		return rec.SourceID == parts[0] && rec.TargetID == parts[1]
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("edge not found: %s", edgeKey)
+	}
+	return edges[0], nil
+}
+
+// LoadEdges implements aggregates.EdgeLoader - loads edges by key, skipping missing ones
+func (r *EdgeRepository) LoadEdges(ctx context.Context, edgeKeys []string) ([]*aggregates.Edge, error) {
+	wanted := make(map[string]bool, len(edgeKeys))
+	for _, key := range edgeKeys {
+		wanted[key] = true
+	}
+	return r.scan("", func(rec *edgeRecord) bool {
+		return wanted[rec.SourceID+"->"+rec.TargetID]
+	})
+}
+
+// LoadEdgesByNodeID implements aggregates.EdgeLoader
+func (r *EdgeRepository) LoadEdgesByNodeID(ctx context.Context, nodeID valueobjects.NodeID) ([]*aggregates.Edge, error) {
+	return r.GetByNodeID(ctx, nodeID.String())
+}
+
+// GetEdgesByNodeIDs retrieves edges for multiple nodes in a single pass
+func (r *EdgeRepository) GetEdgesByNodeIDs(ctx context.Context, nodeIDs []string) (map[string][]*aggregates.Edge, error) {
+	result := make(map[string][]*aggregates.Edge, len(nodeIDs))
+	for _, id := range nodeIDs {
+		result[id] = []*aggregates.Edge{}
+	}
+	err := r.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketEdges, "", func(key string, value json.RawMessage) error {
+			var record edgeRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode edge %s: %w", key, err)
+			}
+			_, hasSource := result[record.SourceID]
+			_, hasTarget := result[record.TargetID]
+			if !hasSource && !hasTarget {
+				return nil
+			}
+			edge, err := record.toEdge()
+			if err != nil {
+				return err
+			}
+			if hasSource {
+				result[record.SourceID] = append(result[record.SourceID], edge)
+			}
+			if hasTarget && record.TargetID != record.SourceID {
+				result[record.TargetID] = append(result[record.TargetID], edge)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scan returns the edges under prefix whose record satisfies keep (nil keeps all)
+func (r *EdgeRepository) scan(prefix string, keep func(*edgeRecord) bool) ([]*aggregates.Edge, error) {
+	edges := make([]*aggregates.Edge, 0)
+	err := r.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketEdges, prefix, func(key string, value json.RawMessage) error {
+			var record edgeRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode edge %s: %w", key, err)
+			}
+			if keep != nil && !keep(&record) {
+				return nil
+			}
+			edge, err := record.toEdge()
+			if err != nil {
+				return err
+			}
+			edges = append(edges, edge)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
@@ -0,0 +1,311 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/events"
+)
+
+// PublishStatus represents the outbox publishing status of a stored event
+type PublishStatus string
+
+const (
+	PublishStatusPending      PublishStatus = "pending"       // Event is saved but not yet published
+	PublishStatusPublished    PublishStatus = "published"     // Event successfully published
+	PublishStatusDeadLettered PublishStatus = "dead_lettered" // Event parked after too many failed publishes
+
+	// publishStatusFailed is how earlier versions parked failed events; such
+	// records are reported as dead-lettered
+	publishStatusFailed PublishStatus = "failed"
+)
+
+// EventRecord is the stored form of a domain event, with outbox bookkeeping
+type EventRecord struct {
+	Key         string          `json:"key"`
+	Sequence    uint64          `json:"sequence"`
+	EventType   string          `json:"event_type"`
+	AggregateID string          `json:"aggregate_id"`
+	UserID      string          `json:"user_id,omitempty"`
+	Version     int             `json:"version"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Data        json.RawMessage `json:"data"`
+
+	// Outbox pattern fields
+	PublishStatus   string    `json:"publish_status"`
+	PublishAttempts int       `json:"publish_attempts"`
+	LastPublishTry  time.Time `json:"last_publish_try,omitempty"`
+	NextPublishTry  time.Time `json:"next_publish_try,omitempty"` // Zero means due immediately
+	PublishedAt     time.Time `json:"published_at,omitempty"`
+	DeadLetteredAt  time.Time `json:"dead_lettered_at,omitempty"`
+	ErrorMessage    string    `json:"error_message,omitempty"`
+}
+
+// EventStore implements ports.EventStore on top of the file store. Events are
+// keyed by aggregate and a store-wide sequence, so per-aggregate reads are
+// ordered and cheap and the sequence gives a global append order.
+type EventStore struct {
+	store *Store
+}
+
+// Compile-time interface checks
+var (
+	_ ports.EventStore    = (*EventStore)(nil)
+	_ ports.SnapshotStore = (*EventStore)(nil)
+	_ ports.EventStream   = (*EventStore)(nil)
+	_ ports.Outbox        = (*EventStore)(nil)
+)
+
+// NewEventStore creates a new file-backed event store
+func NewEventStore(store *Store) *EventStore {
+	return &EventStore{store: store}
+}
+
+// eventKey orders events by aggregate, then by append sequence
+func eventKey(aggregateID string, sequence uint64) string {
+	return fmt.Sprintf("%s|%020d", aggregateID, sequence)
+}
+
+// SaveEvents appends events in a single transaction
+func (es *EventStore) SaveEvents(ctx context.Context, domainEvents []events.DomainEvent) error {
+	if len(domainEvents) == 0 {
+		return nil
+	}
+	return es.store.Update(func(tx *Tx) error {
+		for _, event := range domainEvents {
+			if err := es.appendEvent(tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// appendEvent writes an event inside an existing transaction; used by the unit of work
+func (es *EventStore) appendEvent(tx *Tx, event events.DomainEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	// Events spell the user field either way
+	var envelope struct {
+		UserID      string `json:"user_id"`
+		UserIDCamel string `json:"userId"`
+	}
+	_ = json.Unmarshal(data, &envelope)
+	if envelope.UserID == "" {
+		envelope.UserID = envelope.UserIDCamel
+	}
+
+	sequence, err := tx.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	record := &EventRecord{
+		Key:           eventKey(event.GetAggregateID(), sequence),
+		Sequence:      sequence,
+		EventType:     event.GetEventType(),
+		AggregateID:   event.GetAggregateID(),
+		UserID:        envelope.UserID,
+		Version:       event.GetVersion(),
+		Timestamp:     event.GetTimestamp(),
+		Data:          data,
+		PublishStatus: string(PublishStatusPending),
+	}
+	return tx.Put(bucketEvents, record.Key, record)
+}
+
+// GetEvents retrieves all events for an aggregate in append order
+func (es *EventStore) GetEvents(ctx context.Context, aggregateID string) ([]events.DomainEvent, error) {
+	records, err := es.records(aggregateID+"|", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecords(records)
+}
+
+// GetEventsByType retrieves the most recent events of a type
+func (es *EventStore) GetEventsByType(ctx context.Context, eventType string, limit int) ([]events.DomainEvent, error) {
+	records, err := es.records("", func(r *EventRecord) bool { return r.EventType == eventType })
+	if err != nil {
+		return nil, err
+	}
+	sortBySequence(records, true)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return decodeRecords(records)
+}
+
+// GetEventsAfter retrieves events for an aggregate after a specific version
+func (es *EventStore) GetEventsAfter(ctx context.Context, aggregateID string, version int) ([]events.DomainEvent, error) {
+	records, err := es.records(aggregateID+"|", func(r *EventRecord) bool { return r.Version > version })
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecords(records)
+}
+
+// ReadEvents returns up to limit events at or after the given sequence number
+func (es *EventStore) ReadEvents(ctx context.Context, fromPosition int64, limit int) ([]ports.RecordedEvent, error) {
+	records, err := es.records("", func(r *EventRecord) bool { return int64(r.Sequence) >= fromPosition })
+	if err != nil {
+		return nil, err
+	}
+	sortBySequence(records, false)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	result := make([]ports.RecordedEvent, 0, len(records))
+	for _, record := range records {
+		event, err := DecodeEvent(record)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ports.RecordedEvent{
+			Position: int64(record.Sequence),
+			EventID:  record.Key,
+			Event:    event,
+		})
+	}
+	return result, nil
+}
+
+// GetEventsByUser retrieves events raised for a user since a point in time
+func (es *EventStore) GetEventsByUser(ctx context.Context, userID string, since time.Time, limit int) ([]events.DomainEvent, error) {
+	records, err := es.records("", func(r *EventRecord) bool {
+		return r.UserID == userID && r.Timestamp.After(since)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortBySequence(records, false)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return decodeRecords(records)
+}
+
+// DeleteEvents removes all events for an aggregate
+func (es *EventStore) DeleteEvents(ctx context.Context, aggregateID string) error {
+	return es.DeleteEventsBatch(ctx, []string{aggregateID})
+}
+
+// DeleteEventsBatch removes all events for multiple aggregates in one transaction
+func (es *EventStore) DeleteEventsBatch(ctx context.Context, aggregateIDs []string) error {
+	if len(aggregateIDs) == 0 {
+		return nil
+	}
+	return es.store.Update(func(tx *Tx) error {
+		for _, aggregateID := range aggregateIDs {
+			var keys []string
+			_ = tx.ForEach(bucketEvents, aggregateID+"|", func(key string, _ json.RawMessage) error {
+				keys = append(keys, key)
+				return nil
+			})
+			for _, key := range keys {
+				if err := tx.Delete(bucketEvents, key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetSnapshot retrieves the latest snapshot for an aggregate, or nil if none exists
+func (es *EventStore) GetSnapshot(ctx context.Context, aggregateID string) (*ports.AggregateSnapshot, error) {
+	var snapshot ports.AggregateSnapshot
+	err := es.store.View(func(tx *Tx) error {
+		return tx.Get(bucketSnapshots, aggregateID, &snapshot)
+	})
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot replaces the snapshot of an aggregate
+func (es *EventStore) SaveSnapshot(ctx context.Context, snapshot *ports.AggregateSnapshot) error {
+	return es.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketSnapshots, snapshot.AggregateID, snapshot)
+	})
+}
+
+// Outbox Pattern Methods
+
+// GetPendingEvents retrieves events that haven't been published yet, oldest first
+func (es *EventStore) GetPendingEvents(ctx context.Context, limit int) ([]*EventRecord, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	records, err := es.records("", func(r *EventRecord) bool {
+		return r.PublishStatus == string(PublishStatusPending)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortBySequence(records, false)
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// records loads event records under prefix that satisfy keep (nil keeps all)
+func (es *EventStore) records(prefix string, keep func(*EventRecord) bool) ([]*EventRecord, error) {
+	var records []*EventRecord
+	err := es.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketEvents, prefix, func(key string, value json.RawMessage) error {
+			var record EventRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to unmarshal event record %s: %w", key, err)
+			}
+			if keep == nil || keep(&record) {
+				records = append(records, &record)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return records, nil
+}
+
+func sortBySequence(records []*EventRecord, desc bool) {
+	sort.Slice(records, func(i, j int) bool {
+		if desc {
+			return records[i].Sequence > records[j].Sequence
+		}
+		return records[i].Sequence < records[j].Sequence
+	})
+}
+
+func decodeRecords(records []*EventRecord) ([]events.DomainEvent, error) {
+	domainEvents := make([]events.DomainEvent, 0, len(records))
+	for _, record := range records {
+		event, err := DecodeEvent(record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert record to event: %w", err)
+		}
+		domainEvents = append(domainEvents, event)
+	}
+	return domainEvents, nil
+}
+
+// DecodeEvent rebuilds the concrete domain event from a stored record. Unknown
+// event types are returned as a BaseEvent carrying the envelope fields.
+func DecodeEvent(record *EventRecord) (events.DomainEvent, error) {
+	return events.Decode(record.EventType, record.AggregateID, record.Version, record.Timestamp, record.Data)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package filestore
+
+import "os"
+
+// Without flock the store is only safe for a single process at a time
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) error { return nil }
@@ -0,0 +1,470 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+
+	"go.uber.org/zap"
+)
+
+// defaultGraphName matches aggregates.Graph.IsDefault
+const defaultGraphName = "Default Graph"
+
+// GraphRepository implements ports.GraphRepository on the file store.
+// Graph metadata lives in the graphs bucket; nodes and edges are joined in
+// from their own buckets when a graph is loaded.
+type GraphRepository struct {
+	store    *Store
+	nodeRepo *NodeRepository
+	edgeRepo *EdgeRepository
+	logger   *zap.Logger
+}
+
+// Compile-time interface check
+var _ ports.GraphRepository = (*GraphRepository)(nil)
+
+// NewGraphRepository creates a new file-backed graph repository
+func NewGraphRepository(store *Store, nodeRepo *NodeRepository, edgeRepo *EdgeRepository, logger *zap.Logger) *GraphRepository {
+	return &GraphRepository{
+		store:    store,
+		nodeRepo: nodeRepo,
+		edgeRepo: edgeRepo,
+		logger:   logger,
+	}
+}
+
+// Save persists the graph metadata and every edge it holds
+func (r *GraphRepository) Save(ctx context.Context, graph *aggregates.Graph) error {
+	return r.store.Update(func(tx *Tx) error {
+		return r.writeGraph(tx, graph)
+	})
+}
+
+// SaveWithUoW stages the graph write, its edges, edge removals implied by its
+// uncommitted events, and the events themselves in the unit of work
+func (r *GraphRepository) SaveWithUoW(ctx context.Context, graph *aggregates.Graph, uow interface{}) error {
+	fileUoW, ok := uow.(*UnitOfWork)
+	if !ok {
+		return fmt.Errorf("invalid unit of work type")
+	}
+
+	uncommitted := graph.GetUncommittedEvents()
+	if err := fileUoW.register(func(tx *Tx) error {
+		return r.writeGraph(tx, graph, uncommitted...)
+	}); err != nil {
+		return fmt.Errorf("failed to register graph save: %w", err)
+	}
+
+	for _, event := range uncommitted {
+		if err := fileUoW.RegisterEvent(event); err != nil {
+			return fmt.Errorf("failed to register graph event: %w", err)
+		}
+	}
+
+	r.logger.Debug("Graph registered for transactional save",
+		zap.String("graphID", graph.ID().String()),
+		zap.Int("edgeCount", len(graph.GetEdges())),
+	)
+	return nil
+}
+
+// writeGraph puts the graph record and edges, and removes edges for
+// disconnections and node removals recorded in the given events
+func (r *GraphRepository) writeGraph(tx *Tx, graph *aggregates.Graph, pending ...events.DomainEvent) error {
+	graphID := graph.ID().String()
+
+	for _, event := range pending {
+		switch e := event.(type) {
+		case events.NodesDisconnected:
+			if e.AggregateID != graphID {
+				continue
+			}
+			if err := tx.Delete(bucketEdges, edgeKey(graphID, e.SourceID.String(), e.TargetID.String())); err != nil {
+				return err
+			}
+		case events.NodeRemovedFromGraph:
+			if err := deleteNodeEdges(tx, graphID, e.NodeID.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, edge := range graph.GetEdges() {
+		record := newEdgeRecord(graphID, edge)
+		if err := tx.Put(bucketEdges, edgeKey(graphID, record.SourceID, record.TargetID), record); err != nil {
+			return err
+		}
+	}
+
+	record := newGraphRecord(graph)
+	record.EdgeCount = tx.Count(bucketEdges, edgePrefix(graphID))
+	return tx.Put(bucketGraphs, graphID, record)
+}
+
+func deleteNodeEdges(tx *Tx, graphID, nodeID string) error {
+	var keys []string
+	err := tx.ForEach(bucketEdges, edgePrefix(graphID), func(key string, value json.RawMessage) error {
+		var record edgeRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return fmt.Errorf("failed to decode edge %s: %w", key, err)
+		}
+		if record.SourceID == nodeID || record.TargetID == nodeID {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := tx.Delete(bucketEdges, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves a graph with its nodes and edges
+func (r *GraphRepository) GetByID(ctx context.Context, id aggregates.GraphID) (*aggregates.Graph, error) {
+	record, err := r.getRecord(id.String())
+	if err != nil {
+		return nil, err
+	}
+	graph, err := record.toGraph()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct graph: %w", err)
+	}
+
+	nodes, err := r.nodeRepo.GetByGraphID(ctx, id.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes for graph: %w", err)
+	}
+	for _, node := range nodes {
+		if err := graph.LoadNode(node); err != nil {
+			r.logger.Error("Failed to load node into graph",
+				zap.String("nodeID", node.ID().String()),
+				zap.String("graphID", id.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	edges, err := r.edgeRepo.GetByGraphID(ctx, id.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edges for graph: %w", err)
+	}
+	for _, edge := range edges {
+		if err := graph.LoadEdge(edge); err != nil {
+			r.logger.Debug("Edge failed to load",
+				zap.String("edgeID", edge.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return graph, nil
+}
+
+// GetByUserID retrieves all graphs for a user (metadata only)
+func (r *GraphRepository) GetByUserID(ctx context.Context, userID string) ([]*aggregates.Graph, error) {
+	return r.scan(func(rec *graphRecord) bool { return rec.UserID == userID })
+}
+
+// GetUserDefaultGraph retrieves the user's default graph with its nodes
+func (r *GraphRepository) GetUserDefaultGraph(ctx context.Context, userID string) (*aggregates.Graph, error) {
+	graphs, err := r.scan(func(rec *graphRecord) bool {
+		return rec.UserID == userID && rec.Name == defaultGraphName
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query default graph: %w", err)
+	}
+	if len(graphs) == 0 {
+		return nil, fmt.Errorf("no default graph found for user")
+	}
+	graph := graphs[0]
+
+	nodes, err := r.nodeRepo.GetByGraphID(ctx, graph.ID().String())
+	if err != nil {
+		r.logger.Warn("Failed to load nodes for graph",
+			zap.String("graphID", graph.ID().String()),
+			zap.Error(err),
+		)
+		return graph, nil
+	}
+	for _, node := range nodes {
+		if err := graph.LoadNode(node); err != nil {
+			r.logger.Debug("Node already in graph or failed to load",
+				zap.String("nodeID", node.ID().String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return graph, nil
+}
+
+// GetOrCreateDefaultGraph gets or creates a default graph for a user. The
+// check and the insert run in one store transaction, so concurrent callers
+// always end up with the same graph.
+func (r *GraphRepository) GetOrCreateDefaultGraph(ctx context.Context, userID string) (*aggregates.Graph, error) {
+	if existing, err := r.GetUserDefaultGraph(ctx, userID); err == nil {
+		return existing, nil
+	}
+
+	graph, err := aggregates.NewGraph(userID, defaultGraphName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create default graph: %w", err)
+	}
+
+	created := false
+	err = r.store.Update(func(tx *Tx) error {
+		exists := false
+		err := tx.ForEach(bucketGraphs, "", func(key string, value json.RawMessage) error {
+			var record graphRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode graph %s: %w", key, err)
+			}
+			if record.UserID == userID && record.Name == defaultGraphName {
+				exists = true
+			}
+			return nil
+		})
+		if err != nil || exists {
+			return err
+		}
+		created = true
+		return tx.Put(bucketGraphs, graph.ID().String(), newGraphRecord(graph))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save default graph: %w", err)
+	}
+	if !created {
+		return r.GetUserDefaultGraph(ctx, userID)
+	}
+
+	r.logger.Info("Default graph created",
+		zap.String("graphID", graph.ID().String()),
+		zap.String("userID", userID),
+	)
+	return graph, nil
+}
+
+// CreateDefaultGraph creates a default graph for a user (deprecated - use GetOrCreateDefaultGraph)
+func (r *GraphRepository) CreateDefaultGraph(ctx context.Context, userID string) (*aggregates.Graph, error) {
+	return r.GetOrCreateDefaultGraph(ctx, userID)
+}
+
+// UpdateGraphMetadata recomputes the node and edge counts from stored state
+func (r *GraphRepository) UpdateGraphMetadata(ctx context.Context, graphID string) error {
+	nodeCount, err := r.nodeRepo.CountNodesByGraph(ctx, graphID)
+	if err != nil {
+		return fmt.Errorf("failed to count nodes: %w", err)
+	}
+
+	return r.store.Update(func(tx *Tx) error {
+		var record graphRecord
+		if err := tx.Get(bucketGraphs, graphID, &record); err != nil {
+			if err == ErrNotFound {
+				return fmt.Errorf("graph not found: %s", graphID)
+			}
+			return err
+		}
+		record.NodeCount = int(nodeCount)
+		record.EdgeCount = tx.Count(bucketEdges, edgePrefix(graphID))
+		if record.Metadata != nil {
+			record.Metadata["nodeCount"] = record.NodeCount
+			record.Metadata["edgeCount"] = record.EdgeCount
+		}
+		return tx.Put(bucketGraphs, graphID, &record)
+	})
+}
+
+// Delete removes a graph's metadata record
+func (r *GraphRepository) Delete(ctx context.Context, id aggregates.GraphID) error {
+	return r.store.Update(func(tx *Tx) error {
+		if !tx.Exists(bucketGraphs, id.String()) {
+			return fmt.Errorf("failed to get graph for deletion: graph not found: %s", id.String())
+		}
+		return tx.Delete(bucketGraphs, id.String())
+	})
+}
+
+// FindByNodeCount finds a user's graphs whose stored node count is within the bounds
+func (r *GraphRepository) FindByNodeCount(ctx context.Context, userID string, minNodes, maxNodes int) ([]*aggregates.Graph, error) {
+	return r.scan(func(rec *graphRecord) bool {
+		return rec.UserID == userID && rec.NodeCount >= minNodes && (maxNodes <= 0 || rec.NodeCount <= maxNodes)
+	})
+}
+
+// FindMostActive returns a user's most recently updated graphs
+func (r *GraphRepository) FindMostActive(ctx context.Context, userID string, limit int) ([]*aggregates.Graph, error) {
+	graphs, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(graphs, func(i, j int) bool {
+		return graphs[i].UpdatedAt().After(graphs[j].UpdatedAt())
+	})
+	return paginate(graphs, 0, limit), nil
+}
+
+// FindPublicGraphs returns graphs flagged public in their metadata
+func (r *GraphRepository) FindPublicGraphs(ctx context.Context, limit int) ([]*aggregates.Graph, error) {
+	graphs, err := r.scan(func(rec *graphRecord) bool {
+		isPublic, _ := rec.Metadata["isPublic"].(bool)
+		return isPublic
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paginate(graphs, 0, limit), nil
+}
+
+// GetGraphStatistics computes connectivity statistics from stored nodes and edges
+func (r *GraphRepository) GetGraphStatistics(ctx context.Context, graphID aggregates.GraphID) (ports.GraphStatistics, error) {
+	var stats ports.GraphStatistics
+
+	nodes, err := r.nodeRepo.GetByGraphID(ctx, graphID.String())
+	if err != nil {
+		return stats, err
+	}
+	var adjacency map[string][]string
+	err = r.store.View(func(tx *Tx) error {
+		stats.EdgeCount = tx.Count(bucketEdges, edgePrefix(graphID.String()))
+		adjacency, err = adjacencyList(tx, edgePrefix(graphID.String()))
+		return err
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	stats.NodeCount = len(nodes)
+	totalConnections := 0
+	for _, node := range nodes {
+		degree := len(adjacency[node.ID().String()])
+		totalConnections += degree
+		if degree == 0 {
+			stats.OrphanedNodeCount++
+		}
+		if degree > stats.MaxConnections {
+			stats.MaxConnections = degree
+		}
+	}
+	if stats.NodeCount > 0 {
+		stats.AverageConnections = float64(totalConnections) / float64(stats.NodeCount)
+	}
+
+	// Connected components over the undirected edge set
+	visited := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		start := node.ID().String()
+		if visited[start] {
+			continue
+		}
+		stats.ClusterCount++
+		stack := []string{start}
+		visited[start] = true
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, neighbor := range adjacency[current] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					stack = append(stack, neighbor)
+				}
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// CountUserGraphs counts the graphs owned by a user
+func (r *GraphRepository) CountUserGraphs(ctx context.Context, userID string) (int, error) {
+	graphs, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return len(graphs), nil
+}
+
+// GetGraphsByIDs retrieves graph metadata for multiple IDs in one read
+func (r *GraphRepository) GetGraphsByIDs(ctx context.Context, graphIDs []aggregates.GraphID) (map[aggregates.GraphID]*aggregates.Graph, error) {
+	result := make(map[aggregates.GraphID]*aggregates.Graph, len(graphIDs))
+	err := r.store.View(func(tx *Tx) error {
+		for _, id := range graphIDs {
+			var record graphRecord
+			if err := tx.Get(bucketGraphs, id.String(), &record); err != nil {
+				if err == ErrNotFound {
+					continue
+				}
+				return err
+			}
+			graph, err := record.toGraph()
+			if err != nil {
+				return err
+			}
+			result[id] = graph
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *GraphRepository) getRecord(graphID string) (*graphRecord, error) {
+	var record graphRecord
+	err := r.store.View(func(tx *Tx) error {
+		return tx.Get(bucketGraphs, graphID, &record)
+	})
+	if err == ErrNotFound {
+		return nil, fmt.Errorf("graph not found: %s", graphID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// scan returns the graphs whose record satisfies keep, oldest first
+func (r *GraphRepository) scan(keep func(*graphRecord) bool) ([]*aggregates.Graph, error) {
+	var records []*graphRecord
+	err := r.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketGraphs, "", func(key string, value json.RawMessage) error {
+			var record graphRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode graph %s: %w", key, err)
+			}
+			if keep(&record) {
+				records = append(records, &record)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt < records[j].CreatedAt
+	})
+
+	graphs := make([]*aggregates.Graph, 0, len(records))
+	for _, record := range records {
+		graph, err := record.toGraph()
+		if err != nil {
+			r.logger.Warn("Failed to reconstruct graph from record",
+				zap.String("graphID", record.GraphID),
+				zap.Error(err))
+			continue
+		}
+		graphs = append(graphs, graph)
+	}
+	return graphs, nil
+}
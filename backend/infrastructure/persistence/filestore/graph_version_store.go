@@ -0,0 +1,113 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/versioning"
+	pkgerrors "backend/pkg/errors"
+)
+
+// GraphVersionStore keeps graph versions in the graph_versions bucket, keyed
+// graph|version with the version zero padded so a graph's versions are read
+// by prefix in version order
+type GraphVersionStore struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ ports.GraphVersionStore = (*GraphVersionStore)(nil)
+
+// NewGraphVersionStore creates a new file-backed graph version store
+func NewGraphVersionStore(store *Store) *GraphVersionStore {
+	return &GraphVersionStore{store: store}
+}
+
+// Save stores a new version of a graph
+func (s *GraphVersionStore) Save(ctx context.Context, snapshot *versioning.GraphSnapshot) error {
+	key := versionKey(snapshot.Version.GraphID, snapshot.Version.Version)
+	return s.store.Update(func(tx *Tx) error {
+		if tx.Exists(bucketVersions, key) {
+			return pkgerrors.NewConflictError(fmt.Sprintf("version %d of graph %s already exists", snapshot.Version.Version, snapshot.Version.GraphID))
+		}
+		return tx.Put(bucketVersions, key, snapshot)
+	})
+}
+
+// Get returns a stored version of a graph
+func (s *GraphVersionStore) Get(ctx context.Context, graphID string, version int) (*versioning.GraphSnapshot, error) {
+	var snapshot versioning.GraphSnapshot
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketVersions, versionKey(graphID, version), &snapshot)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("graph version")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph version: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// Latest returns the newest version of a graph
+func (s *GraphVersionStore) Latest(ctx context.Context, graphID string) (*versioning.GraphSnapshot, error) {
+	var latest json.RawMessage
+	err := s.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketVersions, graphID+"|", func(_ string, value json.RawMessage) error {
+			latest = value
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest graph version: %w", err)
+	}
+	if latest == nil {
+		return nil, pkgerrors.NewNotFoundError("graph version")
+	}
+
+	var snapshot versioning.GraphSnapshot
+	if err := json.Unmarshal(latest, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode graph version: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// List returns the version records of a graph, newest first
+func (s *GraphVersionStore) List(ctx context.Context, graphID string) ([]*versioning.GraphVersion, error) {
+	versions := make([]*versioning.GraphVersion, 0)
+	err := s.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketVersions, graphID+"|", func(key string, value json.RawMessage) error {
+			// Only the version record is decoded, not the graph state
+			var record struct {
+				Version versioning.GraphVersion `json:"version"`
+			}
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode graph version %s: %w", key, err)
+			}
+			versions = append(versions, &record.Version)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list graph versions: %w", err)
+	}
+
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
+
+// Delete removes a version of a graph
+func (s *GraphVersionStore) Delete(ctx context.Context, graphID string, version int) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Delete(bucketVersions, versionKey(graphID, version))
+	})
+}
+
+// versionKey identifies a graph version within the graph_versions bucket
+func versionKey(graphID string, version int) string {
+	return fmt.Sprintf("%s|%010d", graphID, version)
+}
@@ -0,0 +1,45 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// KeywordIndexStore keeps serialized keyword indexes in the keyword_indexes
+// bucket, keyed by user
+type KeywordIndexStore struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ ports.KeywordIndexStore = (*KeywordIndexStore)(nil)
+
+// NewKeywordIndexStore creates a new file-backed keyword index store
+func NewKeywordIndexStore(store *Store) *KeywordIndexStore {
+	return &KeywordIndexStore{store: store}
+}
+
+// Load returns a user's serialized index
+func (s *KeywordIndexStore) Load(ctx context.Context, userID string) ([]byte, error) {
+	var data []byte
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketKeywords, userID, &data)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("keyword index")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keyword index: %w", err)
+	}
+	return data, nil
+}
+
+// Save stores a user's serialized index
+func (s *KeywordIndexStore) Save(ctx context.Context, userID string, data []byte) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketKeywords, userID, data)
+	})
+}
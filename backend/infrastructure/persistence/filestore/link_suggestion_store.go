@@ -0,0 +1,104 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// LinkSuggestionStore keeps link suggestions in the link_suggestions bucket,
+// keyed user|suggestion so a user's suggestions are read by prefix, and
+// feedback in the link_feedback bucket, keyed by user
+type LinkSuggestionStore struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ ports.LinkSuggestionStore = (*LinkSuggestionStore)(nil)
+
+// NewLinkSuggestionStore creates a new file-backed link suggestion store
+func NewLinkSuggestionStore(store *Store) *LinkSuggestionStore {
+	return &LinkSuggestionStore{store: store}
+}
+
+// Put stores a suggestion, replacing an earlier one with the same ID
+func (s *LinkSuggestionStore) Put(ctx context.Context, suggestion *ports.LinkSuggestion) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketSuggestions, suggestionKey(suggestion.UserID, suggestion.ID), suggestion)
+	})
+}
+
+// Get returns a user's suggestion
+func (s *LinkSuggestionStore) Get(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error) {
+	var suggestion ports.LinkSuggestion
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketSuggestions, suggestionKey(userID, suggestionID), &suggestion)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("link suggestion")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link suggestion: %w", err)
+	}
+	return &suggestion, nil
+}
+
+// ListByUser returns all of a user's suggestions, highest score first
+func (s *LinkSuggestionStore) ListByUser(ctx context.Context, userID string) ([]*ports.LinkSuggestion, error) {
+	suggestions := make([]*ports.LinkSuggestion, 0)
+	err := s.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketSuggestions, userID+"|", func(key string, value json.RawMessage) error {
+			var suggestion ports.LinkSuggestion
+			if err := json.Unmarshal(value, &suggestion); err != nil {
+				return fmt.Errorf("failed to decode link suggestion %s: %w", key, err)
+			}
+			suggestions = append(suggestions, &suggestion)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list link suggestions: %w", err)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions, nil
+}
+
+// Delete removes a suggestion
+func (s *LinkSuggestionStore) Delete(ctx context.Context, userID, suggestionID string) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Delete(bucketSuggestions, suggestionKey(userID, suggestionID))
+	})
+}
+
+// GetFeedback returns a user's feedback
+func (s *LinkSuggestionStore) GetFeedback(ctx context.Context, userID string) (*ports.LinkFeedback, error) {
+	var feedback ports.LinkFeedback
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketFeedback, userID, &feedback)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("link feedback")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link feedback: %w", err)
+	}
+	return &feedback, nil
+}
+
+// PutFeedback stores a user's feedback
+func (s *LinkSuggestionStore) PutFeedback(ctx context.Context, feedback *ports.LinkFeedback) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketFeedback, feedback.UserID, feedback)
+	})
+}
+
+// suggestionKey identifies a suggestion within the link_suggestions bucket
+func suggestionKey(userID, suggestionID string) string {
+	return userID + "|" + suggestionID
+}
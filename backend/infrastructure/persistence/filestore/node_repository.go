@@ -0,0 +1,508 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// NodeRepository implements ports.NodeRepository on the file store.
+// Nodes are keyed by node ID; graph and user lookups scan the bucket, which is
+// fine for the single-user data sets this backend is meant for.
+type NodeRepository struct {
+	store  *Store
+	logger *zap.Logger
+}
+
+// Compile-time interface check
+var _ ports.NodeRepository = (*NodeRepository)(nil)
+
+// NewNodeRepository creates a new file-backed node repository
+func NewNodeRepository(store *Store, logger *zap.Logger) *NodeRepository {
+	return &NodeRepository{store: store, logger: logger}
+}
+
+// Save creates or replaces a node
+func (r *NodeRepository) Save(ctx context.Context, node *entities.Node) error {
+	record, err := newNodeRecord(node)
+	if err != nil {
+		return err
+	}
+	return r.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketNodes, record.NodeID, record)
+	})
+}
+
+// Update replaces an existing node
+func (r *NodeRepository) Update(ctx context.Context, node *entities.Node) error {
+	return r.Save(ctx, node)
+}
+
+// SaveWithUoW stages the node write and its uncommitted events in the unit of work
+func (r *NodeRepository) SaveWithUoW(ctx context.Context, node *entities.Node, uow interface{}) error {
+	fileUoW, ok := uow.(*UnitOfWork)
+	if !ok {
+		return fmt.Errorf("invalid unit of work type")
+	}
+
+	record, err := newNodeRecord(node)
+	if err != nil {
+		return err
+	}
+	if err := fileUoW.register(func(tx *Tx) error {
+		return tx.Put(bucketNodes, record.NodeID, record)
+	}); err != nil {
+		return fmt.Errorf("failed to register node save: %w", err)
+	}
+
+	for _, event := range node.GetUncommittedEvents() {
+		if err := fileUoW.RegisterEvent(event); err != nil {
+			return fmt.Errorf("failed to register node event: %w", err)
+		}
+	}
+
+	r.logger.Debug("Node registered for transactional save",
+		zap.String("nodeID", record.NodeID),
+		zap.String("graphID", record.GraphID),
+	)
+	return nil
+}
+
+// GetByID retrieves a node by its ID
+func (r *NodeRepository) GetByID(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	var record nodeRecord
+	err := r.store.View(func(tx *Tx) error {
+		return tx.Get(bucketNodes, id.String(), &record)
+	})
+	if err == ErrNotFound {
+		return nil, fmt.Errorf("node not found: %s", id.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record.toNode()
+}
+
+// FindByID retrieves a node by ID (alias for GetByID)
+func (r *NodeRepository) FindByID(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	return r.GetByID(ctx, id)
+}
+
+// GetByUserID retrieves all nodes owned by a user
+func (r *NodeRepository) GetByUserID(ctx context.Context, userID string) ([]*entities.Node, error) {
+	return r.scan(func(rec *nodeRecord) bool { return rec.UserID == userID })
+}
+
+// GetByGraphID retrieves all nodes in a graph
+func (r *NodeRepository) GetByGraphID(ctx context.Context, graphID string) ([]*entities.Node, error) {
+	return r.scan(func(rec *nodeRecord) bool { return rec.GraphID == graphID })
+}
+
+// Delete removes a node
+func (r *NodeRepository) Delete(ctx context.Context, id valueobjects.NodeID) error {
+	return r.store.Update(func(tx *Tx) error {
+		if !tx.Exists(bucketNodes, id.String()) {
+			return fmt.Errorf("failed to find node for deletion: node not found: %s", id.String())
+		}
+		return tx.Delete(bucketNodes, id.String())
+	})
+}
+
+// DeleteWithVersion removes a node only if it is still at the expected version
+func (r *NodeRepository) DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error {
+	return r.store.Update(func(tx *Tx) error {
+		var record nodeRecord
+		if err := tx.Get(bucketNodes, id.String(), &record); err == ErrNotFound {
+			return fmt.Errorf("failed to find node for deletion: node not found: %s", id.String())
+		} else if err != nil {
+			return err
+		}
+		if record.Version != expectedVersion {
+			return pkgerrors.NewVersionConflictError("node "+id.String(), expectedVersion, record.Version)
+		}
+		return tx.Delete(bucketNodes, id.String())
+	})
+}
+
+// Search filters a user's nodes by query, tags and status, then orders and pages them
+func (r *NodeRepository) Search(ctx context.Context, criteria ports.SearchCriteria) ([]*entities.Node, error) {
+	query := strings.ToLower(criteria.Query)
+	nodes, err := r.scan(func(rec *nodeRecord) bool {
+		if criteria.UserID != "" && rec.UserID != criteria.UserID {
+			return false
+		}
+		if criteria.Status != "" && rec.Status != criteria.Status {
+			return false
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(rec.Title), query) &&
+			!strings.Contains(strings.ToLower(rec.Content), query) {
+			return false
+		}
+		return hasAllTags(rec.Tags, criteria.Tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		var less bool
+		switch criteria.OrderBy {
+		case "title":
+			less = nodes[i].Content().Title() < nodes[j].Content().Title()
+		case "updated_at":
+			less = nodes[i].UpdatedAt().Before(nodes[j].UpdatedAt())
+		default:
+			less = nodes[i].CreatedAt().Before(nodes[j].CreatedAt())
+		}
+		if criteria.OrderDesc {
+			return !less
+		}
+		return less
+	})
+
+	return paginate(nodes, criteria.Offset, criteria.Limit), nil
+}
+
+// BulkSave saves many nodes in one transaction
+func (r *NodeRepository) BulkSave(ctx context.Context, nodes []*entities.Node) error {
+	records := make([]*nodeRecord, 0, len(nodes))
+	for _, node := range nodes {
+		record, err := newNodeRecord(node)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	return r.store.Update(func(tx *Tx) error {
+		for _, record := range records {
+			if err := tx.Put(bucketNodes, record.NodeID, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch removes many nodes in one transaction; missing nodes are ignored
+func (r *NodeRepository) DeleteBatch(ctx context.Context, nodeIDs []valueobjects.NodeID) error {
+	return r.store.Update(func(tx *Tx) error {
+		for _, id := range nodeIDs {
+			if err := tx.Delete(bucketNodes, id.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindByTags finds a user's nodes carrying any of the given tags
+func (r *NodeRepository) FindByTags(ctx context.Context, userID string, tags []string) ([]*entities.Node, error) {
+	if len(tags) == 0 {
+		return []*entities.Node{}, nil
+	}
+	wanted := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		wanted[strings.ToLower(tag)] = true
+	}
+	return r.scan(func(rec *nodeRecord) bool {
+		if rec.UserID != userID {
+			return false
+		}
+		for _, tag := range rec.Tags {
+			if wanted[strings.ToLower(tag)] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FindConnectedNodes returns nodes reachable from nodeID within maxDepth hops
+func (r *NodeRepository) FindConnectedNodes(ctx context.Context, nodeID valueobjects.NodeID, maxDepth int) ([]*entities.Node, error) {
+	if maxDepth <= 0 {
+		maxDepth = 1
+	}
+
+	var result []*entities.Node
+	err := r.store.View(func(tx *Tx) error {
+		adjacency, err := adjacencyList(tx, "")
+		if err != nil {
+			return err
+		}
+
+		visited := map[string]bool{nodeID.String(): true}
+		frontier := []string{nodeID.String()}
+		for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+			var next []string
+			for _, id := range frontier {
+				for _, neighbor := range adjacency[id] {
+					if visited[neighbor] {
+						continue
+					}
+					visited[neighbor] = true
+					next = append(next, neighbor)
+
+					var record nodeRecord
+					if err := tx.Get(bucketNodes, neighbor, &record); err != nil {
+						continue
+					}
+					node, err := record.toNode()
+					if err != nil {
+						return err
+					}
+					result = append(result, node)
+				}
+			}
+			frontier = next
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []*entities.Node{}
+	}
+	return result, nil
+}
+
+// FindOrphanedNodes finds nodes in a graph with no edges
+func (r *NodeRepository) FindOrphanedNodes(ctx context.Context, graphID string) ([]*entities.Node, error) {
+	var connected map[string][]string
+	err := r.store.View(func(tx *Tx) error {
+		var err error
+		connected, err = adjacencyList(tx, edgePrefix(graphID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.scan(func(rec *nodeRecord) bool {
+		return rec.GraphID == graphID && len(connected[rec.NodeID]) == 0
+	})
+}
+
+// FindRecentlyUpdated returns a user's most recently updated nodes
+func (r *NodeRepository) FindRecentlyUpdated(ctx context.Context, userID string, limit int) ([]*entities.Node, error) {
+	nodes, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].UpdatedAt().After(nodes[j].UpdatedAt())
+	})
+	return paginate(nodes, 0, limit), nil
+}
+
+// FindByContentPattern finds a user's nodes whose title or body matches a regular expression
+func (r *NodeRepository) FindByContentPattern(ctx context.Context, userID string, pattern string) ([]*entities.Node, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content pattern: %w", err)
+	}
+	return r.scan(func(rec *nodeRecord) bool {
+		return rec.UserID == userID && (re.MatchString(rec.Title) || re.MatchString(rec.Content))
+	})
+}
+
+// CountByStatus counts a user's nodes per status
+func (r *NodeRepository) CountByStatus(ctx context.Context, userID string) (map[entities.NodeStatus]int, error) {
+	counts := make(map[entities.NodeStatus]int)
+	err := r.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketNodes, "", func(key string, value json.RawMessage) error {
+			var record nodeRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode node %s: %w", key, err)
+			}
+			if record.UserID == userID {
+				counts[entities.NodeStatus(record.Status)]++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetMostConnected returns the nodes of a graph with the highest edge degree
+func (r *NodeRepository) GetMostConnected(ctx context.Context, graphID string, limit int) ([]*entities.Node, error) {
+	var adjacency map[string][]string
+	err := r.store.View(func(tx *Tx) error {
+		var err error
+		adjacency, err = adjacencyList(tx, edgePrefix(graphID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := r.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return len(adjacency[nodes[i].ID().String()]) > len(adjacency[nodes[j].ID().String()])
+	})
+	return paginate(nodes, 0, limit), nil
+}
+
+// CountNodesByGraph counts the number of nodes in a graph
+func (r *NodeRepository) CountNodesByGraph(ctx context.Context, graphID string) (int64, error) {
+	nodes, err := r.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(nodes)), nil
+}
+
+// FindSimilarNodes finds nodes similar to the given node
+func (r *NodeRepository) FindSimilarNodes(ctx context.Context, nodeID valueobjects.NodeID, threshold float64) ([]*entities.Node, error) {
+	node, err := r.GetByID(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source node: %w", err)
+	}
+
+	candidates, err := r.GetByUserID(ctx, node.UserID())
+	if err != nil {
+		return nil, err
+	}
+	similar := make([]*entities.Node, 0)
+	for _, candidate := range candidates {
+		if candidate.ID().Equals(nodeID) {
+			continue
+		}
+		if node.IsSimilarTo(candidate, threshold) {
+			similar = append(similar, candidate)
+		}
+	}
+	return similar, nil
+}
+
+// LoadNode implements aggregates.NodeLoader - loads a single node
+func (r *NodeRepository) LoadNode(ctx context.Context, nodeID valueobjects.NodeID) (*entities.Node, error) {
+	return r.GetByID(ctx, nodeID)
+}
+
+// LoadNodes implements aggregates.NodeLoader - loads multiple nodes, skipping missing ones
+func (r *NodeRepository) LoadNodes(ctx context.Context, nodeIDs []valueobjects.NodeID) ([]*entities.Node, error) {
+	byID, err := r.GetNodesByIDs(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*entities.Node, 0, len(byID))
+	for _, id := range nodeIDs {
+		if node, ok := byID[id]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// GetNodesByIDs retrieves multiple nodes in a single read transaction
+func (r *NodeRepository) GetNodesByIDs(ctx context.Context, nodeIDs []valueobjects.NodeID) (map[valueobjects.NodeID]*entities.Node, error) {
+	result := make(map[valueobjects.NodeID]*entities.Node, len(nodeIDs))
+	err := r.store.View(func(tx *Tx) error {
+		for _, id := range nodeIDs {
+			var record nodeRecord
+			if err := tx.Get(bucketNodes, id.String(), &record); err != nil {
+				if err == ErrNotFound {
+					continue
+				}
+				return err
+			}
+			node, err := record.toNode()
+			if err != nil {
+				return err
+			}
+			result[id] = node
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scan returns all nodes whose record satisfies keep, ordered by node ID
+func (r *NodeRepository) scan(keep func(*nodeRecord) bool) ([]*entities.Node, error) {
+	nodes := make([]*entities.Node, 0)
+	err := r.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketNodes, "", func(key string, value json.RawMessage) error {
+			var record nodeRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode node %s: %w", key, err)
+			}
+			if !keep(&record) {
+				return nil
+			}
+			node, err := record.toNode()
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, node)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// adjacencyList builds an undirected adjacency list from the edges under prefix
+func adjacencyList(tx *Tx, prefix string) (map[string][]string, error) {
+	adjacency := make(map[string][]string)
+	err := tx.ForEach(bucketEdges, prefix, func(key string, value json.RawMessage) error {
+		var record edgeRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return fmt.Errorf("failed to decode edge %s: %w", key, err)
+		}
+		adjacency[record.SourceID] = append(adjacency[record.SourceID], record.TargetID)
+		adjacency[record.TargetID] = append(adjacency[record.TargetID], record.SourceID)
+		return nil
+	})
+	return adjacency, err
+}
+
+func hasAllTags(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return items[:0]
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,236 @@
+package filestore
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// The file store keeps every record in memory, so the outbox queries filter
+// the event bucket directly rather than maintaining a separate index.
+
+// DueEntries returns pending events whose next attempt is due, earliest due first
+func (es *EventStore) DueEntries(ctx context.Context, now time.Time, limit int) ([]*ports.OutboxEntry, error) {
+	records, err := es.records("", func(r *EventRecord) bool {
+		return r.PublishStatus == string(PublishStatusPending) && !r.NextPublishTry.After(now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		di, dj := dueAt(records[i]), dueAt(records[j])
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return records[i].Sequence < records[j].Sequence
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return toOutboxEntries(records), nil
+}
+
+// MarkPublished records a successful delivery
+func (es *EventStore) MarkPublished(ctx context.Context, id string) error {
+	return es.updateOutboxRecord(id, func(r *EventRecord) error {
+		r.PublishStatus = string(PublishStatusPublished)
+		r.PublishedAt = time.Now()
+		r.NextPublishTry = time.Time{}
+		return nil
+	})
+}
+
+// ScheduleRetry records a failed attempt and defers the event until nextAttemptAt
+func (es *EventStore) ScheduleRetry(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error {
+	return es.updateOutboxRecord(id, func(r *EventRecord) error {
+		r.PublishStatus = string(PublishStatusPending)
+		r.PublishAttempts = attempts
+		r.LastPublishTry = time.Now()
+		r.NextPublishTry = nextAttemptAt
+		r.ErrorMessage = lastError
+		return nil
+	})
+}
+
+// DeadLetter records the final failed attempt and parks the event
+func (es *EventStore) DeadLetter(ctx context.Context, id string, attempts int, lastError string) error {
+	return es.updateOutboxRecord(id, func(r *EventRecord) error {
+		now := time.Now()
+		r.PublishStatus = string(PublishStatusDeadLettered)
+		r.PublishAttempts = attempts
+		r.LastPublishTry = now
+		r.NextPublishTry = time.Time{}
+		r.DeadLetteredAt = now
+		r.ErrorMessage = lastError
+		return nil
+	})
+}
+
+// GetEntry retrieves an outbox entry by ID
+func (es *EventStore) GetEntry(ctx context.Context, id string) (*ports.OutboxEntry, error) {
+	key, err := outboxKey(id)
+	if err != nil {
+		return nil, err
+	}
+	var record EventRecord
+	err = es.store.View(func(tx *Tx) error {
+		return tx.Get(bucketEvents, key, &record)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("outbox entry")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox entry: %w", err)
+	}
+	return toOutboxEntry(&record), nil
+}
+
+// ListDeadLettered returns dead-lettered events, most recently parked first
+func (es *EventStore) ListDeadLettered(ctx context.Context, limit int) ([]*ports.OutboxEntry, error) {
+	records, err := es.records("", isDeadLettered)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].DeadLetteredAt.After(records[j].DeadLetteredAt)
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return toOutboxEntries(records), nil
+}
+
+// Redrive returns a dead-lettered event to the pending queue
+func (es *EventStore) Redrive(ctx context.Context, id string) (*ports.OutboxEntry, error) {
+	var redriven *EventRecord
+	err := es.updateOutboxRecord(id, func(r *EventRecord) error {
+		if !isDeadLettered(r) {
+			return pkgerrors.NewConflictError("only dead-lettered events can be redriven")
+		}
+		r.PublishStatus = string(PublishStatusPending)
+		r.PublishAttempts = 0
+		r.NextPublishTry = time.Time{}
+		r.DeadLetteredAt = time.Time{}
+		redriven = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toOutboxEntry(redriven), nil
+}
+
+// Stats counts pending and dead-lettered events
+func (es *EventStore) Stats(ctx context.Context) (*ports.OutboxStats, error) {
+	records, err := es.records("", func(r *EventRecord) bool {
+		return r.PublishStatus == string(PublishStatusPending) || isDeadLettered(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ports.OutboxStats{}
+	for _, record := range records {
+		if isDeadLettered(record) {
+			stats.DeadLettered++
+			continue
+		}
+		stats.Pending++
+		if stats.OldestPendingAt == nil || record.Timestamp.Before(*stats.OldestPendingAt) {
+			occurred := record.Timestamp
+			stats.OldestPendingAt = &occurred
+		}
+	}
+	return stats, nil
+}
+
+// updateOutboxRecord applies mutate to the record behind an outbox ID
+func (es *EventStore) updateOutboxRecord(id string, mutate func(r *EventRecord) error) error {
+	key, err := outboxKey(id)
+	if err != nil {
+		return err
+	}
+	return es.store.Update(func(tx *Tx) error {
+		var record EventRecord
+		if err := tx.Get(bucketEvents, key, &record); err != nil {
+			if err == ErrNotFound {
+				return pkgerrors.NewNotFoundError("outbox entry")
+			}
+			return err
+		}
+		if err := mutate(&record); err != nil {
+			return err
+		}
+		return tx.Put(bucketEvents, key, &record)
+	})
+}
+
+// outboxID encodes an event key for use in URLs
+func outboxID(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// outboxKey decodes an outbox ID back into the event key
+func outboxKey(id string) (string, error) {
+	key, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil || len(key) == 0 {
+		return "", pkgerrors.NewValidationError("invalid outbox entry ID")
+	}
+	return string(key), nil
+}
+
+func isDeadLettered(r *EventRecord) bool {
+	return r.PublishStatus == string(PublishStatusDeadLettered) || r.PublishStatus == string(publishStatusFailed)
+}
+
+// dueAt is when a pending record is next attempted; new records are due when raised
+func dueAt(r *EventRecord) time.Time {
+	if r.NextPublishTry.IsZero() {
+		return r.Timestamp
+	}
+	return r.NextPublishTry
+}
+
+func toOutboxEntries(records []*EventRecord) []*ports.OutboxEntry {
+	entries := make([]*ports.OutboxEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, toOutboxEntry(record))
+	}
+	return entries
+}
+
+func toOutboxEntry(r *EventRecord) *ports.OutboxEntry {
+	entry := &ports.OutboxEntry{
+		ID:          outboxID(r.Key),
+		EventType:   r.EventType,
+		AggregateID: r.AggregateID,
+		UserID:      r.UserID,
+		Version:     r.Version,
+		OccurredAt:  r.Timestamp,
+		Payload:     r.Data,
+		Status:      ports.OutboxStatus(r.PublishStatus),
+		Attempts:    r.PublishAttempts,
+		LastError:   r.ErrorMessage,
+	}
+	if isDeadLettered(r) {
+		entry.Status = ports.OutboxStatusDeadLettered
+	}
+	if !r.LastPublishTry.IsZero() {
+		lastTry := r.LastPublishTry
+		entry.LastAttemptAt = &lastTry
+	}
+	if entry.Status == ports.OutboxStatusPending {
+		next := dueAt(r)
+		entry.NextAttemptAt = &next
+	}
+	if !r.DeadLetteredAt.IsZero() {
+		deadLettered := r.DeadLetteredAt
+		entry.DeadLetteredAt = &deadLettered
+	}
+	return entry
+}
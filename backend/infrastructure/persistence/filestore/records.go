@@ -0,0 +1,239 @@
+package filestore
+
+import (
+	"fmt"
+	"time"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+// nodeRecord is the stored form of a node
+type nodeRecord struct {
+	NodeID         string                 `json:"node_id"`
+	UserID         string                 `json:"user_id"`
+	GraphID        string                 `json:"graph_id"`
+	Title          string                 `json:"title"`
+	Content        string                 `json:"content"`
+	Format         string                 `json:"format"`
+	X              float64                `json:"x"`
+	Y              float64                `json:"y"`
+	Z              float64                `json:"z"`
+	Status         string                 `json:"status"`
+	Version        int                    `json:"version"`
+	Tags           []string               `json:"tags,omitempty"`
+	URL            string                 `json:"url,omitempty"`
+	Color          string                 `json:"color,omitempty"`
+	Properties     map[string]interface{} `json:"properties,omitempty"`
+	Embedding      []byte                 `json:"embedding,omitempty"`
+	EmbeddingModel string                 `json:"embedding_model,omitempty"`
+	EmbeddingHash  string                 `json:"embedding_hash,omitempty"`
+	CommunityID    string                 `json:"community_id,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+func newNodeRecord(node *entities.Node) (*nodeRecord, error) {
+	if node.GraphID() == "" {
+		return nil, fmt.Errorf("node must belong to a graph before saving")
+	}
+
+	record := &nodeRecord{
+		NodeID:      node.ID().String(),
+		UserID:      node.UserID(),
+		GraphID:     node.GraphID(),
+		Title:       node.Content().Title(),
+		Content:     node.Content().Body(),
+		Format:      string(node.Content().Format()),
+		X:           node.Position().X(),
+		Y:           node.Position().Y(),
+		Z:           node.Position().Z(),
+		Status:      string(node.Status()),
+		Version:     node.Version(),
+		Tags:        node.GetTags(),
+		URL:         node.GetURL(),
+		Color:       node.GetColor(),
+		CommunityID: node.CommunityID(),
+		CreatedAt:   node.CreatedAt(),
+		UpdatedAt:   node.UpdatedAt(),
+	}
+	for key, value := range node.GetMetadata() {
+		switch key {
+		case "tags", "url", "color", "category":
+			// Stored in dedicated fields or derived
+		default:
+			if record.Properties == nil {
+				record.Properties = make(map[string]interface{})
+			}
+			record.Properties[key] = value
+		}
+	}
+	if node.HasEmbedding() {
+		embedding := node.Embedding()
+		record.Embedding = embedding.ToBytes()
+		record.EmbeddingModel = embedding.Model()
+		record.EmbeddingHash = embedding.ContentHash()
+	}
+	return record, nil
+}
+
+func (r *nodeRecord) toNode() (*entities.Node, error) {
+	nodeID, err := valueobjects.NewNodeIDFromString(r.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node ID: %w", err)
+	}
+	content, err := valueobjects.NewNodeContent(r.Title, r.Content, valueobjects.ContentFormat(r.Format))
+	if err != nil {
+		return nil, fmt.Errorf("invalid content: %w", err)
+	}
+	position, err := valueobjects.NewPosition3D(r.X, r.Y, r.Z)
+	if err != nil {
+		return nil, fmt.Errorf("invalid position: %w", err)
+	}
+
+	node, err := entities.ReconstructNode(
+		nodeID,
+		r.UserID,
+		content,
+		position,
+		r.GraphID,
+		r.CreatedAt,
+		r.UpdatedAt,
+		entities.NodeStatus(r.Status),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct node: %w", err)
+	}
+
+	for _, tag := range r.Tags {
+		node.AddTag(tag)
+	}
+	if r.URL != "" {
+		node.SetURL(r.URL)
+	}
+	if r.Color != "" {
+		node.SetColor(r.Color)
+	}
+	for key, value := range r.Properties {
+		node.SetMetadataProperty(key, value)
+	}
+	if len(r.Embedding) > 0 {
+		if embedding, err := valueobjects.NewEmbeddingFromBytes(r.Embedding); err == nil {
+			node.SetEmbedding(embedding.WithProvenance(r.EmbeddingModel, r.EmbeddingHash))
+		}
+	}
+	if r.CommunityID != "" {
+		node.SetCommunityID(r.CommunityID)
+	}
+	node.RestorePersistedState(r.Version, r.UpdatedAt)
+
+	return node, nil
+}
+
+// edgeRecord is the stored form of an edge
+type edgeRecord struct {
+	EdgeID        string                 `json:"edge_id"`
+	GraphID       string                 `json:"graph_id"`
+	SourceID      string                 `json:"source_id"`
+	TargetID      string                 `json:"target_id"`
+	Type          string                 `json:"type"`
+	Weight        float64                `json:"weight"`
+	Bidirectional bool                   `json:"bidirectional"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+func newEdgeRecord(graphID string, edge *aggregates.Edge) *edgeRecord {
+	return &edgeRecord{
+		EdgeID:        edge.ID,
+		GraphID:       graphID,
+		SourceID:      edge.SourceID.String(),
+		TargetID:      edge.TargetID.String(),
+		Type:          string(edge.Type),
+		Weight:        edge.Weight,
+		Bidirectional: edge.Bidirectional,
+		Metadata:      edge.Metadata,
+		CreatedAt:     edge.CreatedAt,
+	}
+}
+
+func (r *edgeRecord) toEdge() (*aggregates.Edge, error) {
+	sourceID, err := valueobjects.NewNodeIDFromString(r.SourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source node ID: %w", err)
+	}
+	targetID, err := valueobjects.NewNodeIDFromString(r.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target node ID: %w", err)
+	}
+	return &aggregates.Edge{
+		ID:            r.EdgeID,
+		SourceID:      sourceID,
+		TargetID:      targetID,
+		Type:          entities.EdgeType(r.Type),
+		Weight:        r.Weight,
+		Bidirectional: r.Bidirectional,
+		Metadata:      r.Metadata,
+		CreatedAt:     r.CreatedAt,
+	}, nil
+}
+
+// graphRecord is the stored form of a graph's metadata; nodes and edges are
+// stored in their own buckets and joined back in on load
+type graphRecord struct {
+	GraphID     string                 `json:"graph_id"`
+	UserID      string                 `json:"user_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	IsDefault   bool                   `json:"is_default"`
+	NodeCount   int                    `json:"node_count"`
+	EdgeCount   int                    `json:"edge_count"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Version     int                    `json:"version"`
+	CreatedAt   string                 `json:"created_at"`
+	UpdatedAt   string                 `json:"updated_at"`
+}
+
+func newGraphRecord(graph *aggregates.Graph) *graphRecord {
+	return &graphRecord{
+		GraphID:     graph.ID().String(),
+		UserID:      graph.UserID(),
+		Name:        graph.Name(),
+		Description: graph.Description(),
+		IsDefault:   graph.IsDefault(),
+		NodeCount:   graph.NodeCount(),
+		EdgeCount:   graph.EdgeCount(),
+		Metadata:    graph.Metadata(),
+		Version:     graph.Version(),
+		CreatedAt:   graph.CreatedAt().Format(time.RFC3339),
+		UpdatedAt:   graph.UpdatedAt().Format(time.RFC3339),
+	}
+}
+
+func (r *graphRecord) toGraph() (*aggregates.Graph, error) {
+	graph, err := aggregates.ReconstructGraph(
+		r.GraphID,
+		r.UserID,
+		r.Name,
+		r.Description,
+		r.IsDefault,
+		r.CreatedAt,
+		r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	graph.RestorePersistedState(r.Version)
+	return graph, nil
+}
+
+// edgeKey builds the edges bucket key; the graph prefix keeps a graph's edges contiguous
+func edgeKey(graphID, sourceID, targetID string) string {
+	return graphID + "|" + sourceID + "|" + targetID
+}
+
+// edgePrefix is the key prefix shared by all edges of a graph
+func edgePrefix(graphID string) string {
+	return graphID + "|"
+}
@@ -0,0 +1,448 @@
+// Package filestore provides an embedded, single-file persistence backend for
+// every repository port. It is meant for running the API and worker on a
+// laptop or in CI without AWS: the whole data set is held in memory and every
+// committed transaction rewrites the data file atomically (temp file, fsync,
+// rename), so a crash leaves either the previous or the new state on disk.
+package filestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Bucket names used by the repositories
+const (
+	bucketNodes       = "nodes"
+	bucketEdges       = "edges"
+	bucketGraphs      = "graphs"
+	bucketEvents      = "events"
+	bucketSnapshots   = "snapshots"
+	bucketLocks       = "locks"
+	bucketCheckpoints = "checkpoints"
+	bucketTrash       = "trash"
+	bucketVersions    = "graph_versions"
+	bucketVectors     = "vector_indexes"
+	bucketKeywords    = "keyword_indexes"
+	bucketCommunities = "communities"
+	bucketSuggestions = "link_suggestions"
+	bucketFeedback    = "link_feedback"
+)
+
+// storeFormatVersion is bumped whenever the on-disk layout changes incompatibly
+const storeFormatVersion = 1
+
+// ErrNotFound is returned by Tx.Get when a key does not exist
+var ErrNotFound = errors.New("filestore: not found")
+
+// storeFile is the on-disk representation of the store
+type storeFile struct {
+	Format   int                                   `json:"format"`
+	Sequence uint64                                `json:"sequence"`
+	Buckets  map[string]map[string]json.RawMessage `json:"buckets"`
+}
+
+// Store is a transactional key/value store persisted to a single file.
+// Readers run concurrently; writers are serialized and see their own writes.
+// Several processes (the API and the worker) may share one file: writers take
+// an exclusive lock on a sidecar lock file, and every transaction reloads the
+// data file first if another process has replaced it.
+type Store struct {
+	path     string
+	lockPath string
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	sequence uint64
+	buckets  map[string]map[string]json.RawMessage
+	loaded   os.FileInfo
+	closed   bool
+}
+
+// Open loads the store at path, creating the file and its directory if needed
+func Open(path string, logger *zap.Logger) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("filestore: path is required")
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	s := &Store{
+		path:     path,
+		lockPath: path + ".lock",
+		logger:   logger,
+		buckets:  make(map[string]map[string]json.RawMessage),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("filestore: failed to create data directory: %w", err)
+	}
+
+	err := s.withFileLock(func() error {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			logger.Info("Creating file store", zap.String("path", path))
+			return s.persist()
+		}
+		return s.reload()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Opened file store",
+		zap.String("path", path),
+		zap.Int("nodes", len(s.buckets[bucketNodes])),
+		zap.Int("edges", len(s.buckets[bucketEdges])),
+		zap.Int("graphs", len(s.buckets[bucketGraphs])),
+	)
+	return s, nil
+}
+
+// reload replaces the in-memory state with the data file; callers hold s.mu
+func (s *Store) reload() error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("filestore: failed to open %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("filestore: failed to stat %s: %w", s.path, err)
+	}
+	var file storeFile
+	if err := json.NewDecoder(f).Decode(&file); err != nil {
+		return fmt.Errorf("filestore: %s is corrupt: %w", s.path, err)
+	}
+	if file.Format != storeFormatVersion {
+		return fmt.Errorf("filestore: unsupported format version %d", file.Format)
+	}
+
+	s.sequence = file.Sequence
+	s.buckets = make(map[string]map[string]json.RawMessage, len(file.Buckets))
+	for name, bucket := range file.Buckets {
+		s.buckets[name] = bucket
+	}
+	s.loaded = info
+	return nil
+}
+
+// stale reports whether another process has replaced the data file since it was loaded
+func (s *Store) stale() bool {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false
+	}
+	// Every commit renames a fresh file into place, so a different inode means
+	// another process has written; size and mtime catch in-place edits
+	return s.loaded == nil || !os.SameFile(info, s.loaded) ||
+		info.Size() != s.loaded.Size() || !info.ModTime().Equal(s.loaded.ModTime())
+}
+
+// withFileLock runs fn while holding the cross-process lock
+func (s *Store) withFileLock(fn func() error) error {
+	f, err := os.OpenFile(s.lockPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("filestore: failed to open lock file: %w", err)
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("filestore: failed to lock %s: %w", s.lockPath, err)
+	}
+	defer unlockFile(f)
+
+	return fn()
+}
+
+// Path returns the data file location
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Close flushes nothing (every commit is already durable) but rejects further use
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// View runs fn in a read-only transaction
+func (s *Store) View(fn func(tx *Tx) error) error {
+	s.mu.RLock()
+	if s.stale() {
+		// Upgrade to a write lock to pick up another process's commit
+		s.mu.RUnlock()
+		s.mu.Lock()
+		if !s.closed && s.stale() {
+			if err := s.reload(); err != nil {
+				s.mu.Unlock()
+				return err
+			}
+		}
+		s.mu.Unlock()
+		s.mu.RLock()
+	}
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return fmt.Errorf("filestore: store is closed")
+	}
+	return fn(&Tx{store: s})
+}
+
+// Update runs fn in a read-write transaction. All writes made by fn become
+// visible and durable together when fn returns nil, and are discarded otherwise.
+func (s *Store) Update(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("filestore: store is closed")
+	}
+
+	return s.withFileLock(func() error {
+		if s.stale() {
+			if err := s.reload(); err != nil {
+				return err
+			}
+		}
+		return s.update(fn)
+	})
+}
+
+// update runs fn and commits its writes; callers hold s.mu and the file lock
+func (s *Store) update(fn func(tx *Tx) error) error {
+	tx := &Tx{
+		store:    s,
+		writable: true,
+		writes:   make(map[string]map[string]json.RawMessage),
+		sequence: s.sequence,
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if len(tx.writes) == 0 && tx.sequence == s.sequence {
+		return nil
+	}
+
+	undo := s.apply(tx)
+	if err := s.persist(); err != nil {
+		undo()
+		return err
+	}
+	return nil
+}
+
+// apply merges the transaction's writes into the live buckets and returns a
+// function restoring the previous state
+func (s *Store) apply(tx *Tx) func() {
+	type previous struct {
+		bucket, key string
+		value       json.RawMessage
+		existed     bool
+	}
+	var saved []previous
+	prevSequence := s.sequence
+
+	for name, writes := range tx.writes {
+		bucket := s.buckets[name]
+		if bucket == nil {
+			bucket = make(map[string]json.RawMessage)
+			s.buckets[name] = bucket
+		}
+		for key, value := range writes {
+			old, existed := bucket[key]
+			saved = append(saved, previous{bucket: name, key: key, value: old, existed: existed})
+			if value == nil {
+				delete(bucket, key)
+			} else {
+				bucket[key] = value
+			}
+		}
+	}
+	s.sequence = tx.sequence
+
+	return func() {
+		for i := len(saved) - 1; i >= 0; i-- {
+			p := saved[i]
+			if p.existed {
+				s.buckets[p.bucket][p.key] = p.value
+			} else {
+				delete(s.buckets[p.bucket], p.key)
+			}
+		}
+		s.sequence = prevSequence
+	}
+}
+
+// persist atomically replaces the data file with the current state
+func (s *Store) persist() error {
+	data, err := json.Marshal(storeFile{
+		Format:   storeFormatVersion,
+		Sequence: s.sequence,
+		Buckets:  s.buckets,
+	})
+	if err != nil {
+		return fmt.Errorf("filestore: failed to encode data: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("filestore: failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("filestore: failed to write data: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("filestore: failed to sync data: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("filestore: failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpName, s.path); err != nil {
+		return fmt.Errorf("filestore: failed to replace data file: %w", err)
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.loaded = info
+	}
+
+	// Make the rename itself durable; not all platforms support syncing directories
+	if d, err := os.Open(dir); err == nil {
+		if err := d.Sync(); err != nil {
+			s.logger.Debug("Directory sync not supported", zap.Error(err))
+		}
+		d.Close()
+	}
+
+	return nil
+}
+
+// Tx is a view of the store inside View or Update. Writes are buffered until
+// the enclosing Update returns, and reads observe them immediately.
+type Tx struct {
+	store    *Store
+	writable bool
+	writes   map[string]map[string]json.RawMessage // nil value marks a delete
+	sequence uint64
+}
+
+// raw returns the encoded value for key, honouring buffered writes
+func (tx *Tx) raw(bucket, key string) (json.RawMessage, bool) {
+	if writes, ok := tx.writes[bucket]; ok {
+		if value, ok := writes[key]; ok {
+			return value, value != nil
+		}
+	}
+	value, ok := tx.store.buckets[bucket][key]
+	return value, ok
+}
+
+// Exists reports whether key is present in bucket
+func (tx *Tx) Exists(bucket, key string) bool {
+	_, ok := tx.raw(bucket, key)
+	return ok
+}
+
+// Get decodes the value stored under key into v, returning ErrNotFound if absent
+func (tx *Tx) Get(bucket, key string, v interface{}) error {
+	value, ok := tx.raw(bucket, key)
+	if !ok {
+		return ErrNotFound
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return fmt.Errorf("filestore: failed to decode %s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// Put encodes v and stores it under key
+func (tx *Tx) Put(bucket, key string, v interface{}) error {
+	if !tx.writable {
+		return fmt.Errorf("filestore: write in read-only transaction")
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("filestore: failed to encode %s/%s: %w", bucket, key, err)
+	}
+	tx.bucketWrites(bucket)[key] = value
+	return nil
+}
+
+// Delete removes key from bucket; deleting a missing key is not an error
+func (tx *Tx) Delete(bucket, key string) error {
+	if !tx.writable {
+		return fmt.Errorf("filestore: write in read-only transaction")
+	}
+	tx.bucketWrites(bucket)[key] = nil
+	return nil
+}
+
+func (tx *Tx) bucketWrites(bucket string) map[string]json.RawMessage {
+	writes := tx.writes[bucket]
+	if writes == nil {
+		writes = make(map[string]json.RawMessage)
+		tx.writes[bucket] = writes
+	}
+	return writes
+}
+
+// NextSequence returns a store-wide monotonically increasing number
+func (tx *Tx) NextSequence() (uint64, error) {
+	if !tx.writable {
+		return 0, fmt.Errorf("filestore: sequence in read-only transaction")
+	}
+	tx.sequence++
+	return tx.sequence, nil
+}
+
+// ForEach calls fn for every key in bucket with the given prefix, in key order.
+// Returning a non-nil error from fn stops the iteration and is returned.
+func (tx *Tx) ForEach(bucket, prefix string, fn func(key string, value json.RawMessage) error) error {
+	keys := make([]string, 0, len(tx.store.buckets[bucket]))
+	for key := range tx.store.buckets[bucket] {
+		if strings.HasPrefix(key, prefix) {
+			if _, overwritten := tx.writes[bucket][key]; !overwritten {
+				keys = append(keys, key)
+			}
+		}
+	}
+	for key, value := range tx.writes[bucket] {
+		if value != nil && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, _ := tx.raw(bucket, key)
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the number of keys in bucket with the given prefix
+func (tx *Tx) Count(bucket, prefix string) int {
+	count := 0
+	_ = tx.ForEach(bucket, prefix, func(string, json.RawMessage) error {
+		count++
+		return nil
+	})
+	return count
+}
@@ -0,0 +1,107 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// TrashStore keeps trashed nodes in the trash bucket, keyed user|node so a
+// user's trash bin is read by prefix
+type TrashStore struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ ports.TrashStore = (*TrashStore)(nil)
+
+// NewTrashStore creates a new file-backed trash store
+func NewTrashStore(store *Store) *TrashStore {
+	return &TrashStore{store: store}
+}
+
+// Put stores a trashed node, replacing an earlier entry for the same node
+func (s *TrashStore) Put(ctx context.Context, trashed *ports.TrashedNode) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketTrash, trashKey(trashed.UserID, trashed.NodeID), trashed)
+	})
+}
+
+// Get returns a user's trashed node
+func (s *TrashStore) Get(ctx context.Context, userID, nodeID string) (*ports.TrashedNode, error) {
+	var trashed ports.TrashedNode
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketTrash, trashKey(userID, nodeID), &trashed)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("trashed node")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed node: %w", err)
+	}
+	return &trashed, nil
+}
+
+// ListByUser returns a user's trashed nodes, most recently trashed first
+func (s *TrashStore) ListByUser(ctx context.Context, userID string) ([]*ports.TrashedNode, error) {
+	trashed, err := s.list(userID+"|", func(*ports.TrashedNode) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(trashed, func(i, j int) bool {
+		return trashed[i].TrashedAt.After(trashed[j].TrashedAt)
+	})
+	return trashed, nil
+}
+
+// ListExpired returns up to limit trashed nodes that are due to be purged
+func (s *TrashStore) ListExpired(ctx context.Context, now time.Time, limit int) ([]*ports.TrashedNode, error) {
+	expired, err := s.list("", func(trashed *ports.TrashedNode) bool { return trashed.IsExpired(now) })
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].PurgeAt.Before(*expired[j].PurgeAt)
+	})
+	if limit > 0 && len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// Delete removes a trashed node
+func (s *TrashStore) Delete(ctx context.Context, userID, nodeID string) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Delete(bucketTrash, trashKey(userID, nodeID))
+	})
+}
+
+func (s *TrashStore) list(prefix string, keep func(*ports.TrashedNode) bool) ([]*ports.TrashedNode, error) {
+	result := make([]*ports.TrashedNode, 0)
+	err := s.store.View(func(tx *Tx) error {
+		return tx.ForEach(bucketTrash, prefix, func(key string, value json.RawMessage) error {
+			var trashed ports.TrashedNode
+			if err := json.Unmarshal(value, &trashed); err != nil {
+				return fmt.Errorf("failed to decode trashed node %s: %w", key, err)
+			}
+			if keep(&trashed) {
+				result = append(result, &trashed)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trashed nodes: %w", err)
+	}
+	return result, nil
+}
+
+// trashKey identifies a trashed node within the trash bucket
+func trashKey(userID, nodeID string) string {
+	return userID + "|" + nodeID
+}
@@ -0,0 +1,181 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"backend/application/ports"
+	"backend/domain/events"
+)
+
+// operation is a staged write applied inside the commit transaction
+type operation func(tx *Tx) error
+
+// UnitOfWork stages repository writes and domain events and applies them in a
+// single store transaction on Commit. Nothing is written before Commit, so
+// reads made while the unit of work is open see the last committed state.
+type UnitOfWork struct {
+	store      *Store
+	nodeRepo   ports.NodeRepository
+	edgeRepo   ports.EdgeRepository
+	graphRepo  ports.GraphRepository
+	eventStore *EventStore
+
+	mu            sync.Mutex
+	operations    []operation
+	pendingEvents []events.DomainEvent
+	inTransaction bool
+}
+
+// Compile-time interface check
+var _ ports.UnitOfWork = (*UnitOfWork)(nil)
+
+// NewUnitOfWork creates a unit of work over the given store
+func NewUnitOfWork(
+	store *Store,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	eventStore *EventStore,
+) *UnitOfWork {
+	return &UnitOfWork{
+		store:      store,
+		nodeRepo:   nodeRepo,
+		edgeRepo:   edgeRepo,
+		graphRepo:  graphRepo,
+		eventStore: eventStore,
+	}
+}
+
+// Begin starts a new transaction
+func (uow *UnitOfWork) Begin(ctx context.Context) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if uow.inTransaction {
+		return fmt.Errorf("transaction already in progress")
+	}
+	uow.inTransaction = true
+	uow.clear()
+	return nil
+}
+
+// register stages a write for the current transaction
+func (uow *UnitOfWork) register(op operation) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	uow.operations = append(uow.operations, op)
+	return nil
+}
+
+// RegisterEvent stages a domain event to be appended to the event store on
+// commit. An event that is already staged is ignored: a graph reports its
+// nodes' events too, so saving both a node and its graph registers them twice.
+func (uow *UnitOfWork) RegisterEvent(event events.DomainEvent) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	for _, staged := range uow.pendingEvents {
+		if sameEvent(staged, event) {
+			return nil
+		}
+	}
+	uow.pendingEvents = append(uow.pendingEvents, event)
+	return nil
+}
+
+// sameEvent reports whether two events are the same occurrence
+func sameEvent(a, b events.DomainEvent) bool {
+	return a.GetAggregateID() == b.GetAggregateID() &&
+		a.GetEventType() == b.GetEventType() &&
+		a.GetVersion() == b.GetVersion() &&
+		a.GetTimestamp().Equal(b.GetTimestamp())
+}
+
+// Commit applies all staged writes and events atomically
+func (uow *UnitOfWork) Commit(ctx context.Context) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	defer func() {
+		uow.inTransaction = false
+		uow.clear()
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("transaction aborted: %w", err)
+	}
+
+	err := uow.store.Update(func(tx *Tx) error {
+		for _, op := range uow.operations {
+			if err := op(tx); err != nil {
+				return err
+			}
+		}
+		if uow.eventStore != nil {
+			for _, event := range uow.pendingEvents {
+				if err := uow.eventStore.appendEvent(tx, event); err != nil {
+					return fmt.Errorf("failed to append event: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("transaction failed: %w", err)
+	}
+
+	return nil
+}
+
+// Rollback discards all staged writes and events
+func (uow *UnitOfWork) Rollback() error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	uow.inTransaction = false
+	uow.clear()
+	return nil
+}
+
+// clear resets the staged state; callers hold uow.mu
+func (uow *UnitOfWork) clear() {
+	uow.operations = nil
+	uow.pendingEvents = nil
+}
+
+// NodeRepository returns the node repository
+func (uow *UnitOfWork) NodeRepository() ports.NodeRepository {
+	return uow.nodeRepo
+}
+
+// EdgeRepository returns the edge repository
+func (uow *UnitOfWork) EdgeRepository() ports.EdgeRepository {
+	return uow.edgeRepo
+}
+
+// GraphRepository returns the graph repository
+func (uow *UnitOfWork) GraphRepository() ports.GraphRepository {
+	return uow.graphRepo
+}
+
+// IsInTransaction returns whether a transaction is currently active
+func (uow *UnitOfWork) IsInTransaction() bool {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+	return uow.inTransaction
+}
@@ -0,0 +1,146 @@
+package filestore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testRepos struct {
+	store  *Store
+	nodes  *NodeRepository
+	edges  *EdgeRepository
+	graphs *GraphRepository
+	events *EventStore
+	uow    *UnitOfWork
+}
+
+func openTestRepos(t *testing.T, path string) *testRepos {
+	t.Helper()
+	store, err := Open(path, zap.NewNop())
+	require.NoError(t, err)
+
+	r := &testRepos{store: store}
+	r.nodes = NewNodeRepository(store, zap.NewNop())
+	r.edges = NewEdgeRepository(store, zap.NewNop())
+	r.graphs = NewGraphRepository(store, r.nodes, r.edges, zap.NewNop())
+	r.events = NewEventStore(store)
+	r.uow = NewUnitOfWork(store, r.nodes, r.edges, r.graphs, r.events)
+	return r
+}
+
+func newTestNode(t *testing.T, graph *aggregates.Graph, title string) *entities.Node {
+	t.Helper()
+	content, err := valueobjects.NewNodeContent(title, "body of "+title, valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	position, err := valueobjects.NewPosition3D(1, 2, 0)
+	require.NoError(t, err)
+	node, err := entities.NewNode(graph.UserID(), content, position)
+	require.NoError(t, err)
+	node.SetGraphID(graph.ID().String())
+	require.NoError(t, graph.AddNode(node))
+	return node
+}
+
+func TestUnitOfWork_CommitPersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "brain2.db")
+	r := openTestRepos(t, path)
+
+	graph, err := aggregates.NewGraph("user-1", "Research")
+	require.NoError(t, err)
+	a := newTestNode(t, graph, "Alpha")
+	b := newTestNode(t, graph, "Beta")
+	a.AddTag("go")
+	_, err = graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+
+	require.NoError(t, r.uow.Begin(ctx))
+	require.NoError(t, r.graphs.SaveWithUoW(ctx, graph, r.uow))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, a, r.uow))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, b, r.uow))
+
+	// Nothing is visible before commit
+	_, err = r.nodes.GetByID(ctx, a.ID())
+	assert.Error(t, err)
+
+	require.NoError(t, r.uow.Commit(ctx))
+	require.NoError(t, r.store.Close())
+
+	reopened := openTestRepos(t, path)
+	loaded, err := reopened.graphs.GetByID(ctx, graph.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Research", loaded.Name())
+	assert.Equal(t, 2, loaded.NodeCount())
+	assert.Len(t, loaded.GetEdges(), 1)
+
+	node, err := reopened.nodes.GetByID(ctx, a.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", node.Content().Title())
+	assert.Equal(t, []string{"go"}, node.GetTags())
+	assert.Equal(t, a.Version(), node.Version())
+
+	stored, err := reopened.events.GetEvents(ctx, graph.ID().String())
+	require.NoError(t, err)
+	assert.NotEmpty(t, stored)
+	pending, err := reopened.events.GetPendingEvents(ctx, 100)
+	require.NoError(t, err)
+	assert.NotEmpty(t, pending)
+}
+
+func TestUnitOfWork_RollbackDiscardsWrites(t *testing.T) {
+	ctx := context.Background()
+	r := openTestRepos(t, filepath.Join(t.TempDir(), "brain2.db"))
+
+	graph, err := aggregates.NewGraph("user-1", "Scratch")
+	require.NoError(t, err)
+	node := newTestNode(t, graph, "Draft")
+
+	require.NoError(t, r.uow.Begin(ctx))
+	require.NoError(t, r.graphs.SaveWithUoW(ctx, graph, r.uow))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, node, r.uow))
+	require.NoError(t, r.uow.Rollback())
+
+	_, err = r.nodes.GetByID(ctx, node.ID())
+	assert.Error(t, err)
+	_, err = r.graphs.GetByID(ctx, graph.ID())
+	assert.Error(t, err)
+
+	pending, err := r.events.GetPendingEvents(ctx, 100)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestGraphRepository_DisconnectRemovesEdge(t *testing.T) {
+	ctx := context.Background()
+	r := openTestRepos(t, filepath.Join(t.TempDir(), "brain2.db"))
+
+	graph, err := aggregates.NewGraph("user-1", "Links")
+	require.NoError(t, err)
+	a := newTestNode(t, graph, "Alpha")
+	b := newTestNode(t, graph, "Beta")
+	_, err = graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+	require.NoError(t, r.nodes.BulkSave(ctx, []*entities.Node{a, b}))
+	require.NoError(t, r.graphs.Save(ctx, graph))
+
+	loaded, err := r.graphs.GetByID(ctx, graph.ID())
+	require.NoError(t, err)
+	_, err = loaded.DisconnectNodes(a.ID(), b.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, r.uow.Begin(ctx))
+	require.NoError(t, r.graphs.SaveWithUoW(ctx, loaded, r.uow))
+	require.NoError(t, r.uow.Commit(ctx))
+
+	edges, err := r.edges.GetByGraphID(ctx, graph.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, edges)
+}
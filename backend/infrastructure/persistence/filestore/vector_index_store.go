@@ -0,0 +1,45 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// VectorIndexStore keeps serialized vector indexes in the vector_indexes
+// bucket, keyed by user
+type VectorIndexStore struct {
+	store *Store
+}
+
+// Compile-time interface check
+var _ ports.VectorIndexStore = (*VectorIndexStore)(nil)
+
+// NewVectorIndexStore creates a new file-backed vector index store
+func NewVectorIndexStore(store *Store) *VectorIndexStore {
+	return &VectorIndexStore{store: store}
+}
+
+// Load returns a user's serialized index
+func (s *VectorIndexStore) Load(ctx context.Context, userID string) ([]byte, error) {
+	var data []byte
+	err := s.store.View(func(tx *Tx) error {
+		return tx.Get(bucketVectors, userID, &data)
+	})
+	if err == ErrNotFound {
+		return nil, pkgerrors.NewNotFoundError("vector index")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vector index: %w", err)
+	}
+	return data, nil
+}
+
+// Save stores a user's serialized index
+func (s *VectorIndexStore) Save(ctx context.Context, userID string, data []byte) error {
+	return s.store.Update(func(tx *Tx) error {
+		return tx.Put(bucketVectors, userID, data)
+	})
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"backend/application/projections"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryCheckpointStore keeps projection checkpoints in the in-memory database
+type InMemoryCheckpointStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ projections.CheckpointStore = (*InMemoryCheckpointStore)(nil)
+
+// NewInMemoryCheckpointStore creates a new in-memory checkpoint store
+func NewInMemoryCheckpointStore(db *InMemoryDatabase) *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{db: db}
+}
+
+// SavePosition stores the position unless a further one is already stored
+func (s *InMemoryCheckpointStore) SavePosition(ctx context.Context, position *projections.ProjectionPosition) error {
+	copied := *position
+	return s.db.update(func(tx *memTx) error {
+		if current, ok := tx.db.checkpoints[position.ProjectionName]; ok && current.IsAfter(position) {
+			return pkgerrors.NewConflictError(fmt.Sprintf(
+				"checkpoint of projection %s is already at position %d", position.ProjectionName, current.Position))
+		}
+		setKey(&tx.undo, tx.db.checkpoints, position.ProjectionName, &copied)
+		return nil
+	})
+}
+
+// GetPosition returns the stored position, or nil if there is none
+func (s *InMemoryCheckpointStore) GetPosition(ctx context.Context, projectionName string) (*projections.ProjectionPosition, error) {
+	var position *projections.ProjectionPosition
+	err := s.db.view(func() error {
+		if stored, ok := s.db.checkpoints[projectionName]; ok {
+			copied := *stored
+			position = &copied
+		}
+		return nil
+	})
+	return position, err
+}
+
+// DeletePosition removes the stored position, if any
+func (s *InMemoryCheckpointStore) DeletePosition(ctx context.Context, projectionName string) error {
+	return s.db.update(func(tx *memTx) error {
+		deleteKey(&tx.undo, tx.db.checkpoints, projectionName)
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryCommunityRepository keeps community partitions in the in-memory
+// database. Partitions are stored encoded, so callers never share state with
+// the stored copy.
+type InMemoryCommunityRepository struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.CommunityRepository = (*InMemoryCommunityRepository)(nil)
+
+// NewInMemoryCommunityRepository creates a new in-memory community repository
+func NewInMemoryCommunityRepository(db *InMemoryDatabase) *InMemoryCommunityRepository {
+	return &InMemoryCommunityRepository{db: db}
+}
+
+// GetByGraphID returns the partition of a graph
+func (r *InMemoryCommunityRepository) GetByGraphID(ctx context.Context, graphID string) (*entities.CommunityPartition, error) {
+	var partition entities.CommunityPartition
+	err := r.db.view(func() error {
+		encoded, ok := r.db.communities[graphID]
+		if !ok {
+			return pkgerrors.NewNotFoundError("community partition")
+		}
+		if err := json.Unmarshal(encoded, &partition); err != nil {
+			return fmt.Errorf("failed to decode community partition: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &partition, nil
+}
+
+// Save replaces the partition of a graph
+func (r *InMemoryCommunityRepository) Save(ctx context.Context, partition *entities.CommunityPartition) error {
+	encoded, err := json.Marshal(partition)
+	if err != nil {
+		return fmt.Errorf("failed to encode community partition: %w", err)
+	}
+	return r.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.communities, partition.GraphID, encoded)
+		return nil
+	})
+}
+
+// Delete removes the partition of a graph
+func (r *InMemoryCommunityRepository) Delete(ctx context.Context, graphID string) error {
+	return r.db.update(func(tx *memTx) error {
+		deleteKey(&tx.undo, tx.db.communities, graphID)
+		return nil
+	})
+}
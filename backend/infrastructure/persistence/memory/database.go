@@ -0,0 +1,355 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"backend/application/ports"
+	"backend/application/projections"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+// InMemoryDatabase holds the state shared by the in-memory repositories, event
+// store and unit of work. All access goes through one RWMutex so a unit of work
+// can apply writes across repositories atomically.
+//
+// Stored entities are private copies: callers never receive a pointer into the
+// database, so mutating a loaded aggregate has no effect until it is saved,
+// exactly as with the DynamoDB repositories.
+type InMemoryDatabase struct {
+	mu          sync.RWMutex
+	nodes       map[string]*entities.Node
+	edges       map[string]*storedEdge
+	graphs      map[string]*storedGraph
+	events      []*storedEvent
+	snapshots   map[string]*ports.AggregateSnapshot
+	checkpoints map[string]*projections.ProjectionPosition
+	trash       map[string][]byte // JSON encoded ports.TrashedNode, keyed user|node
+	versions    map[string][]byte // JSON encoded versioning.GraphSnapshot, keyed graph|version
+	vectors     map[string][]byte // serialized vector index, keyed by user
+	keywords    map[string][]byte // serialized keyword index, keyed by user
+	communities map[string][]byte // JSON encoded entities.CommunityPartition, keyed by graph
+	suggestions map[string][]byte // JSON encoded ports.LinkSuggestion, keyed user|suggestion
+	feedback    map[string][]byte // JSON encoded ports.LinkFeedback, keyed by user
+	sequence    uint64
+}
+
+// storedEdge is an edge together with the graph it belongs to
+type storedEdge struct {
+	graphID string
+	edge    *aggregates.Edge
+}
+
+// storedGraph is the metadata of a graph; nodes and edges are stored on their own
+type storedGraph struct {
+	id          string
+	userID      string
+	name        string
+	description string
+	isDefault   bool
+	version     int
+	nodeCount   int
+	edgeCount   int
+	isPublic    bool
+	createdAt   string
+	updatedAt   string
+}
+
+// NewInMemoryDatabase creates an empty in-memory database
+func NewInMemoryDatabase() *InMemoryDatabase {
+	return &InMemoryDatabase{
+		nodes:       make(map[string]*entities.Node),
+		edges:       make(map[string]*storedEdge),
+		graphs:      make(map[string]*storedGraph),
+		snapshots:   make(map[string]*ports.AggregateSnapshot),
+		checkpoints: make(map[string]*projections.ProjectionPosition),
+		trash:       make(map[string][]byte),
+		versions:    make(map[string][]byte),
+		vectors:     make(map[string][]byte),
+		keywords:    make(map[string][]byte),
+		communities: make(map[string][]byte),
+		suggestions: make(map[string][]byte),
+		feedback:    make(map[string][]byte),
+	}
+}
+
+// Reset removes all stored data, which lets tests reuse one wiring
+func (db *InMemoryDatabase) Reset() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.nodes = make(map[string]*entities.Node)
+	db.edges = make(map[string]*storedEdge)
+	db.graphs = make(map[string]*storedGraph)
+	db.events = nil
+	db.snapshots = make(map[string]*ports.AggregateSnapshot)
+	db.checkpoints = make(map[string]*projections.ProjectionPosition)
+	db.trash = make(map[string][]byte)
+	db.versions = make(map[string][]byte)
+	db.vectors = make(map[string][]byte)
+	db.keywords = make(map[string][]byte)
+	db.communities = make(map[string][]byte)
+	db.suggestions = make(map[string][]byte)
+	db.feedback = make(map[string][]byte)
+	db.sequence = 0
+}
+
+// view runs fn under the read lock
+func (db *InMemoryDatabase) view(fn func() error) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return fn()
+}
+
+// update runs fn under the write lock. If fn fails, every write it made
+// through the transaction is undone, so updates are all-or-nothing.
+func (db *InMemoryDatabase) update(fn func(tx *memTx) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	tx := &memTx{db: db}
+	if err := fn(tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	return nil
+}
+
+// memTx records an undo action for every write so a failed update can be reverted
+type memTx struct {
+	db   *InMemoryDatabase
+	undo []func()
+}
+
+func (tx *memTx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = nil
+}
+
+func (tx *memTx) putNode(node *entities.Node) error {
+	stored, err := cloneNode(node)
+	if err != nil {
+		return err
+	}
+	id := node.ID().String()
+	if current, ok := tx.db.nodes[id]; ok && current.Version() > node.Version() {
+		return fmt.Errorf("optimistic lock failed: node %s is at version %d, got version %d",
+			id, current.Version(), node.Version())
+	}
+	setKey(&tx.undo, tx.db.nodes, id, stored)
+	return nil
+}
+
+func (tx *memTx) deleteNode(id string) bool {
+	return deleteKey(&tx.undo, tx.db.nodes, id)
+}
+
+func (tx *memTx) putEdge(graphID string, edge *aggregates.Edge) {
+	key := edgeKey(graphID, edge.SourceID.String(), edge.TargetID.String())
+	setKey(&tx.undo, tx.db.edges, key, &storedEdge{graphID: graphID, edge: cloneEdge(edge)})
+}
+
+func (tx *memTx) deleteEdge(graphID, sourceID, targetID string) bool {
+	return deleteKey(&tx.undo, tx.db.edges, edgeKey(graphID, sourceID, targetID))
+}
+
+// deleteNodeEdges removes the edges of a graph touching any of the nodes
+func (tx *memTx) deleteNodeEdges(graphID string, nodeIDs map[string]bool) int {
+	var keys []string
+	for key, stored := range tx.db.edges {
+		if stored.graphID == graphID &&
+			(nodeIDs[stored.edge.SourceID.String()] || nodeIDs[stored.edge.TargetID.String()]) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		deleteKey(&tx.undo, tx.db.edges, key)
+	}
+	return len(keys)
+}
+
+func (tx *memTx) putGraph(graph *storedGraph) error {
+	if current, ok := tx.db.graphs[graph.id]; ok && current.version > graph.version {
+		return fmt.Errorf("optimistic lock failed: graph %s is at version %d, got version %d",
+			graph.id, current.version, graph.version)
+	}
+	setKey(&tx.undo, tx.db.graphs, graph.id, graph)
+	return nil
+}
+
+func (tx *memTx) deleteGraph(id string) bool {
+	return deleteKey(&tx.undo, tx.db.graphs, id)
+}
+
+func (tx *memTx) appendEvent(event *storedEvent) {
+	db := tx.db
+	db.sequence++
+	event.sequence = db.sequence
+	db.events = append(db.events, event)
+
+	tx.undo = append(tx.undo, func() {
+		db.events = db.events[:len(db.events)-1]
+		db.sequence--
+	})
+}
+
+// replaceEvents swaps the event log, used when events are deleted
+func (tx *memTx) replaceEvents(kept []*storedEvent) {
+	db := tx.db
+	previous := db.events
+	db.events = kept
+	tx.undo = append(tx.undo, func() { db.events = previous })
+}
+
+// countGraphEdges counts the stored edges of a graph; callers hold the lock
+func (db *InMemoryDatabase) countGraphEdges(graphID string) int {
+	count := 0
+	for _, stored := range db.edges {
+		if stored.graphID == graphID {
+			count++
+		}
+	}
+	return count
+}
+
+// graphEdges returns the stored edges of a graph ordered by key; callers hold the lock
+func (db *InMemoryDatabase) graphEdges(graphID string) []*storedEdge {
+	keys := make([]string, 0)
+	for key, stored := range db.edges {
+		if graphID == "" || stored.graphID == graphID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := make([]*storedEdge, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, db.edges[key])
+	}
+	return result
+}
+
+// adjacency builds an undirected adjacency list over a graph's edges, or all
+// edges when graphID is empty; callers hold the lock
+func (db *InMemoryDatabase) adjacency(graphID string) map[string][]string {
+	adjacency := make(map[string][]string)
+	for _, stored := range db.graphEdges(graphID) {
+		source, target := stored.edge.SourceID.String(), stored.edge.TargetID.String()
+		adjacency[source] = append(adjacency[source], target)
+		adjacency[target] = append(adjacency[target], source)
+	}
+	return adjacency
+}
+
+func setKey[V any](undo *[]func(), m map[string]V, key string, value V) {
+	previous, existed := m[key]
+	*undo = append(*undo, func() {
+		if existed {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+	})
+	m[key] = value
+}
+
+func deleteKey[V any](undo *[]func(), m map[string]V, key string) bool {
+	previous, existed := m[key]
+	if !existed {
+		return false
+	}
+	*undo = append(*undo, func() { m[key] = previous })
+	delete(m, key)
+	return true
+}
+
+// edgeKey identifies an edge within the database
+func edgeKey(graphID, sourceID, targetID string) string {
+	return graphID + "|" + sourceID + "|" + targetID
+}
+
+// cloneNode returns a detached copy of a node, rebuilt the way repositories
+// rehydrate nodes so that no state is shared with the caller
+func cloneNode(node *entities.Node) (*entities.Node, error) {
+	if node.GraphID() == "" {
+		return nil, fmt.Errorf("node must belong to a graph before saving")
+	}
+
+	copied, err := entities.ReconstructNode(
+		node.ID(),
+		node.UserID(),
+		node.Content(),
+		node.Position(),
+		node.GraphID(),
+		node.CreatedAt(),
+		node.UpdatedAt(),
+		node.Status(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy node: %w", err)
+	}
+
+	for _, tag := range node.GetTags() {
+		copied.AddTag(tag)
+	}
+	if url := node.GetURL(); url != "" {
+		copied.SetURL(url)
+	}
+	if color := node.GetColor(); color != "" {
+		copied.SetColor(color)
+	}
+	if icon := node.GetIcon(); icon != "" {
+		copied.SetIcon(icon)
+	}
+	if priority := node.GetPriority(); priority != 0 {
+		copied.SetPriority(priority)
+	}
+	for _, category := range node.GetCategories() {
+		copied.AddCategory(category)
+	}
+	for key, value := range node.GetMetadata() {
+		switch key {
+		case "tags", "url", "color", "category":
+			// Restored through their dedicated setters above
+		default:
+			copied.SetMetadataProperty(key, value)
+		}
+	}
+	if node.HasEmbedding() {
+		original := node.Embedding()
+		embedding, err := valueobjects.NewEmbedding(original.Vector())
+		if err == nil {
+			copied.SetEmbedding(embedding.WithProvenance(original.Model(), original.ContentHash()))
+		}
+	}
+	if communityID := node.CommunityID(); communityID != "" {
+		copied.SetCommunityID(communityID)
+	}
+	copied.RestorePersistedState(node.Version(), node.UpdatedAt())
+
+	return copied, nil
+}
+
+// cloneEdge returns a copy of an edge with its own metadata map
+func cloneEdge(edge *aggregates.Edge) *aggregates.Edge {
+	copied := *edge
+	if edge.Metadata != nil {
+		copied.Metadata = make(map[string]interface{}, len(edge.Metadata))
+		for key, value := range edge.Metadata {
+			copied.Metadata[key] = value
+		}
+	}
+	return &copied
+}
+
+// matchesText reports whether the node's title or body contains the lower-cased query
+func matchesText(node *entities.Node, query string) bool {
+	return strings.Contains(strings.ToLower(node.Content().Title()), query) ||
+		strings.Contains(strings.ToLower(node.Content().Body()), query)
+}
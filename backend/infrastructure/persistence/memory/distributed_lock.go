@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/application/ports"
+)
+
+// InMemoryDistributedLock provides leases that are exclusive within the
+// process, which is all the in-memory backend needs since its data is not
+// shared with other processes.
+type InMemoryDistributedLock struct {
+	mu    sync.Mutex
+	locks map[string]*InMemoryLock
+}
+
+// Compile-time interface check
+var _ ports.DistributedLock = (*InMemoryDistributedLock)(nil)
+
+// NewInMemoryDistributedLock creates a new in-process lock manager
+func NewInMemoryDistributedLock() *InMemoryDistributedLock {
+	return &InMemoryDistributedLock{locks: make(map[string]*InMemoryLock)}
+}
+
+// TryAcquire implements ports.DistributedLock, retrying until timeout
+func (dl *InMemoryDistributedLock) TryAcquire(ctx context.Context, resource, owner string, lockDuration, timeout time.Duration) (ports.Lock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if lock := dl.acquire(resource, owner, lockDuration); lock != nil {
+			return lock, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("timeout acquiring lock for resource: %s", resource)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// acquire takes the lock if it is free or expired, returning nil otherwise
+func (dl *InMemoryDistributedLock) acquire(resource, owner string, lockDuration time.Duration) *InMemoryLock {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	now := time.Now()
+	if held, ok := dl.locks[resource]; ok && held.expiresAt.After(now) {
+		return nil
+	}
+	lock := &InMemoryLock{
+		manager:   dl,
+		resource:  resource,
+		owner:     owner,
+		expiresAt: now.Add(lockDuration),
+	}
+	dl.locks[resource] = lock
+	return lock
+}
+
+// InMemoryLock represents an acquired in-process lock
+type InMemoryLock struct {
+	manager   *InMemoryDistributedLock
+	resource  string
+	owner     string
+	expiresAt time.Time
+}
+
+// Release releases the lock if it is still held by this lease
+func (l *InMemoryLock) Release(ctx context.Context) error {
+	l.manager.mu.Lock()
+	defer l.manager.mu.Unlock()
+
+	if l.manager.locks[l.resource] == l {
+		delete(l.manager.locks, l.resource)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+)
+
+// InMemoryEdgeRepository provides an in-memory implementation of ports.EdgeRepository
+type InMemoryEdgeRepository struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface checks
+var (
+	_ ports.EdgeRepository  = (*InMemoryEdgeRepository)(nil)
+	_ aggregates.EdgeLoader = (*InMemoryEdgeRepository)(nil)
+)
+
+// NewInMemoryEdgeRepository creates a new in-memory edge repository
+func NewInMemoryEdgeRepository(db *InMemoryDatabase) *InMemoryEdgeRepository {
+	return &InMemoryEdgeRepository{db: db}
+}
+
+// Save persists a new edge; saving an edge that already exists is an error
+func (r *InMemoryEdgeRepository) Save(ctx context.Context, graphID string, edge *aggregates.Edge) error {
+	return r.db.update(func(tx *memTx) error {
+		key := edgeKey(graphID, edge.SourceID.String(), edge.TargetID.String())
+		if _, exists := r.db.edges[key]; exists {
+			return fmt.Errorf("edge already exists between these nodes")
+		}
+		tx.putEdge(graphID, edge)
+		return nil
+	})
+}
+
+// SaveWithUoW stages an edge write in the unit of work. Unlike Save it
+// upserts, because graph saves re-register every edge the graph holds.
+func (r *InMemoryEdgeRepository) SaveWithUoW(ctx context.Context, graphID string, edge *aggregates.Edge, uow interface{}) error {
+	memUoW, ok := uow.(*InMemoryUnitOfWork)
+	if !ok {
+		return fmt.Errorf("invalid unit of work type")
+	}
+
+	staged := cloneEdge(edge)
+	if err := memUoW.register(func(tx *memTx) error {
+		tx.putEdge(graphID, staged)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to register edge save: %w", err)
+	}
+	return nil
+}
+
+// GetByGraphID retrieves all edges for a graph
+func (r *InMemoryEdgeRepository) GetByGraphID(ctx context.Context, graphID string) ([]*aggregates.Edge, error) {
+	return r.filter(graphID, nil), nil
+}
+
+// GetByNodeID retrieves all edges where the node is source or target
+func (r *InMemoryEdgeRepository) GetByNodeID(ctx context.Context, nodeID string) ([]*aggregates.Edge, error) {
+	return r.filter("", func(edge *aggregates.Edge) bool {
+		return edge.SourceID.String() == nodeID || edge.TargetID.String() == nodeID
+	}), nil
+}
+
+// Delete removes the edge between two nodes
+func (r *InMemoryEdgeRepository) Delete(ctx context.Context, graphID string, sourceID, targetID string) error {
+	return r.db.update(func(tx *memTx) error {
+		tx.deleteEdge(graphID, sourceID, targetID)
+		return nil
+	})
+}
+
+// DeleteByNodeID removes all edges of a graph touching a node
+func (r *InMemoryEdgeRepository) DeleteByNodeID(ctx context.Context, graphID string, nodeID string) error {
+	return r.DeleteByNodeIDs(ctx, graphID, []string{nodeID})
+}
+
+// DeleteByNodeIDs removes all edges of a graph touching any of the nodes
+func (r *InMemoryEdgeRepository) DeleteByNodeIDs(ctx context.Context, graphID string, nodeIDs []string) error {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	targets := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		targets[id] = true
+	}
+	return r.db.update(func(tx *memTx) error {
+		tx.deleteNodeEdges(graphID, targets)
+		return nil
+	})
+}
+
+// FindByType finds edges of a given type in a graph
+func (r *InMemoryEdgeRepository) FindByType(ctx context.Context, graphID string, edgeType entities.EdgeType) ([]*aggregates.Edge, error) {
+	return r.filter(graphID, func(edge *aggregates.Edge) bool {
+		return edge.Type == edgeType
+	}), nil
+}
+
+// FindStrongConnections finds edges with at least the given weight
+func (r *InMemoryEdgeRepository) FindStrongConnections(ctx context.Context, graphID string, minWeight float64) ([]*aggregates.Edge, error) {
+	return r.filter(graphID, func(edge *aggregates.Edge) bool {
+		return edge.Weight >= minWeight
+	}), nil
+}
+
+// FindBidirectionalEdges finds edges marked bidirectional
+func (r *InMemoryEdgeRepository) FindBidirectionalEdges(ctx context.Context, graphID string) ([]*aggregates.Edge, error) {
+	return r.filter(graphID, func(edge *aggregates.Edge) bool {
+		return edge.Bidirectional
+	}), nil
+}
+
+// CountByType counts a graph's edges per type
+func (r *InMemoryEdgeRepository) CountByType(ctx context.Context, graphID string) (map[entities.EdgeType]int, error) {
+	counts := make(map[entities.EdgeType]int)
+	for _, edge := range r.filter(graphID, nil) {
+		counts[edge.Type]++
+	}
+	return counts, nil
+}
+
+// GetEdgesBetweenNodes returns edges whose endpoints are both in nodeIDs
+func (r *InMemoryEdgeRepository) GetEdgesBetweenNodes(ctx context.Context, graphID string, nodeIDs []valueobjects.NodeID) ([]*aggregates.Edge, error) {
+	members := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		members[id.String()] = true
+	}
+	return r.filter(graphID, func(edge *aggregates.Edge) bool {
+		return members[edge.SourceID.String()] && members[edge.TargetID.String()]
+	}), nil
+}
+
+// LoadEdge implements aggregates.EdgeLoader - loads an edge by its
+// "sourceID->targetID" key
+func (r *InMemoryEdgeRepository) LoadEdge(ctx context.Context, edgeKey string) (*aggregates.Edge, error) {
+	parts := strings.Split(edgeKey, "->")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid edge key format: %s", edgeKey)
+	}
+	edges := r.filter("", func(edge *aggregates.Edge) bool {
+		return edge.SourceID.String() == parts[0] && edge.TargetID.String() == parts[1]
+	})
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("edge not found: %s", edgeKey)
+	}
+	return edges[0], nil
+}
+
+// LoadEdges implements aggregates.EdgeLoader - loads edges by key, skipping missing ones
+func (r *InMemoryEdgeRepository) LoadEdges(ctx context.Context, edgeKeys []string) ([]*aggregates.Edge, error) {
+	wanted := make(map[string]bool, len(edgeKeys))
+	for _, key := range edgeKeys {
+		wanted[key] = true
+	}
+	return r.filter("", func(edge *aggregates.Edge) bool {
+		return wanted[edge.SourceID.String()+"->"+edge.TargetID.String()]
+	}), nil
+}
+
+// LoadEdgesByNodeID implements aggregates.EdgeLoader
+func (r *InMemoryEdgeRepository) LoadEdgesByNodeID(ctx context.Context, nodeID valueobjects.NodeID) ([]*aggregates.Edge, error) {
+	return r.GetByNodeID(ctx, nodeID.String())
+}
+
+// GetEdgesByNodeIDs retrieves edges for multiple nodes in a single pass
+func (r *InMemoryEdgeRepository) GetEdgesByNodeIDs(ctx context.Context, nodeIDs []string) (map[string][]*aggregates.Edge, error) {
+	result := make(map[string][]*aggregates.Edge, len(nodeIDs))
+	for _, id := range nodeIDs {
+		result[id] = []*aggregates.Edge{}
+	}
+	for _, edge := range r.filter("", nil) {
+		source, target := edge.SourceID.String(), edge.TargetID.String()
+		if _, ok := result[source]; ok {
+			result[source] = append(result[source], edge)
+		}
+		if _, ok := result[target]; ok && target != source {
+			result[target] = append(result[target], edge)
+		}
+	}
+	return result, nil
+}
+
+// filter returns copies of the edges of a graph (all graphs when graphID is
+// empty) that satisfy keep; a nil keep returns every edge
+func (r *InMemoryEdgeRepository) filter(graphID string, keep func(*aggregates.Edge) bool) []*aggregates.Edge {
+	edges := make([]*aggregates.Edge, 0)
+	r.db.view(func() error {
+		for _, stored := range r.db.graphEdges(graphID) {
+			if keep == nil || keep(stored.edge) {
+				edges = append(edges, cloneEdge(stored.edge))
+			}
+		}
+		return nil
+	})
+	return edges
+}
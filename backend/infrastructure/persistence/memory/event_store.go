@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+
+	"backend/application/ports"
+	"backend/domain/events"
+)
+
+// storedEvent is a domain event with its position in the global append order
+type storedEvent struct {
+	sequence uint64
+	event    events.DomainEvent
+}
+
+// InMemoryEventStore provides an in-memory implementation of ports.EventStore.
+// Events are kept in append order; there is no outbox, since the in-memory
+// backend publishes through the local event bus.
+type InMemoryEventStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface checks
+var (
+	_ ports.EventStore    = (*InMemoryEventStore)(nil)
+	_ ports.SnapshotStore = (*InMemoryEventStore)(nil)
+	_ ports.EventStream   = (*InMemoryEventStore)(nil)
+)
+
+// NewInMemoryEventStore creates a new in-memory event store
+func NewInMemoryEventStore(db *InMemoryDatabase) *InMemoryEventStore {
+	return &InMemoryEventStore{db: db}
+}
+
+// SaveEvents appends events atomically
+func (es *InMemoryEventStore) SaveEvents(ctx context.Context, domainEvents []events.DomainEvent) error {
+	if len(domainEvents) == 0 {
+		return nil
+	}
+	return es.db.update(func(tx *memTx) error {
+		for _, event := range domainEvents {
+			tx.appendEvent(&storedEvent{event: event})
+		}
+		return nil
+	})
+}
+
+// GetEvents retrieves all events for an aggregate in append order
+func (es *InMemoryEventStore) GetEvents(ctx context.Context, aggregateID string) ([]events.DomainEvent, error) {
+	return es.filter(func(e events.DomainEvent) bool {
+		return e.GetAggregateID() == aggregateID
+	}, false, 0), nil
+}
+
+// GetEventsByType retrieves the most recent events of a type, newest first
+func (es *InMemoryEventStore) GetEventsByType(ctx context.Context, eventType string, limit int) ([]events.DomainEvent, error) {
+	return es.filter(func(e events.DomainEvent) bool {
+		return e.GetEventType() == eventType
+	}, true, limit), nil
+}
+
+// GetEventsAfter retrieves events for an aggregate after a specific version
+func (es *InMemoryEventStore) GetEventsAfter(ctx context.Context, aggregateID string, version int) ([]events.DomainEvent, error) {
+	return es.filter(func(e events.DomainEvent) bool {
+		return e.GetAggregateID() == aggregateID && e.GetVersion() > version
+	}, false, 0), nil
+}
+
+// DeleteEvents removes all events for an aggregate
+func (es *InMemoryEventStore) DeleteEvents(ctx context.Context, aggregateID string) error {
+	return es.DeleteEventsBatch(ctx, []string{aggregateID})
+}
+
+// DeleteEventsBatch removes all events for multiple aggregates atomically
+func (es *InMemoryEventStore) DeleteEventsBatch(ctx context.Context, aggregateIDs []string) error {
+	if len(aggregateIDs) == 0 {
+		return nil
+	}
+	targets := make(map[string]bool, len(aggregateIDs))
+	for _, id := range aggregateIDs {
+		targets[id] = true
+	}
+
+	return es.db.update(func(tx *memTx) error {
+		kept := make([]*storedEvent, 0, len(tx.db.events))
+		for _, stored := range tx.db.events {
+			if !targets[stored.event.GetAggregateID()] {
+				kept = append(kept, stored)
+			}
+		}
+		tx.replaceEvents(kept)
+		return nil
+	})
+}
+
+// GetSnapshot returns the latest snapshot of an aggregate, or nil if none exists
+func (es *InMemoryEventStore) GetSnapshot(ctx context.Context, aggregateID string) (*ports.AggregateSnapshot, error) {
+	var snapshot *ports.AggregateSnapshot
+	es.db.view(func() error {
+		if stored, ok := es.db.snapshots[aggregateID]; ok {
+			copied := *stored
+			snapshot = &copied
+		}
+		return nil
+	})
+	return snapshot, nil
+}
+
+// SaveSnapshot replaces the snapshot of an aggregate
+func (es *InMemoryEventStore) SaveSnapshot(ctx context.Context, snapshot *ports.AggregateSnapshot) error {
+	copied := *snapshot
+	return es.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.snapshots, snapshot.AggregateID, &copied)
+		return nil
+	})
+}
+
+// ReadEvents returns up to limit events at or after the given sequence number
+func (es *InMemoryEventStore) ReadEvents(ctx context.Context, fromPosition int64, limit int) ([]ports.RecordedEvent, error) {
+	result := make([]ports.RecordedEvent, 0)
+	err := es.db.view(func() error {
+		for _, stored := range es.db.events {
+			position := int64(stored.sequence)
+			if position < fromPosition {
+				continue
+			}
+			result = append(result, ports.RecordedEvent{
+				Position: position,
+				EventID:  strconv.FormatUint(stored.sequence, 10),
+				Event:    stored.event,
+			})
+			if limit > 0 && len(result) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// filter returns the events satisfying keep in append order, or newest first
+// when newestFirst is set; a positive limit caps the result
+func (es *InMemoryEventStore) filter(keep func(events.DomainEvent) bool, newestFirst bool, limit int) []events.DomainEvent {
+	result := make([]events.DomainEvent, 0)
+	es.db.view(func() error {
+		n := len(es.db.events)
+		for i := 0; i < n; i++ {
+			stored := es.db.events[i]
+			if newestFirst {
+				stored = es.db.events[n-1-i]
+			}
+			if !keep(stored.event) {
+				continue
+			}
+			result = append(result, stored.event)
+			if limit > 0 && len(result) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return result
+}
@@ -0,0 +1,425 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/application/ports"
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+)
+
+// defaultGraphName matches aggregates.Graph.IsDefault
+const defaultGraphName = "Default Graph"
+
+// InMemoryGraphRepository provides an in-memory implementation of ports.GraphRepository.
+// Graph metadata is stored on its own; nodes and edges are joined in from the
+// node and edge repositories when a graph is loaded.
+type InMemoryGraphRepository struct {
+	db       *InMemoryDatabase
+	nodeRepo *InMemoryNodeRepository
+	edgeRepo *InMemoryEdgeRepository
+}
+
+// Compile-time interface check
+var _ ports.GraphRepository = (*InMemoryGraphRepository)(nil)
+
+// NewInMemoryGraphRepository creates a new in-memory graph repository
+func NewInMemoryGraphRepository(db *InMemoryDatabase, nodeRepo *InMemoryNodeRepository, edgeRepo *InMemoryEdgeRepository) *InMemoryGraphRepository {
+	return &InMemoryGraphRepository{
+		db:       db,
+		nodeRepo: nodeRepo,
+		edgeRepo: edgeRepo,
+	}
+}
+
+// Save persists the graph metadata and every edge it holds
+func (r *InMemoryGraphRepository) Save(ctx context.Context, graph *aggregates.Graph) error {
+	return r.db.update(func(tx *memTx) error {
+		return writeGraph(tx, newStoredGraph(graph), graph.GetEdges())
+	})
+}
+
+// SaveWithUoW stages the graph write, its edges, edge removals implied by its
+// uncommitted events, and the events themselves in the unit of work
+func (r *InMemoryGraphRepository) SaveWithUoW(ctx context.Context, graph *aggregates.Graph, uow interface{}) error {
+	memUoW, ok := uow.(*InMemoryUnitOfWork)
+	if !ok {
+		return fmt.Errorf("invalid unit of work type")
+	}
+
+	stored := newStoredGraph(graph)
+	edges := make([]*aggregates.Edge, 0, len(graph.GetEdges()))
+	for _, edge := range graph.GetEdges() {
+		edges = append(edges, cloneEdge(edge))
+	}
+	uncommitted := graph.GetUncommittedEvents()
+
+	if err := memUoW.register(func(tx *memTx) error {
+		return writeGraph(tx, stored, edges, uncommitted...)
+	}); err != nil {
+		return fmt.Errorf("failed to register graph save: %w", err)
+	}
+
+	for _, event := range uncommitted {
+		if err := memUoW.RegisterEvent(event); err != nil {
+			return fmt.Errorf("failed to register graph event: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeGraph puts the graph metadata and edges, and removes edges for
+// disconnections and node removals recorded in the given events
+func writeGraph(tx *memTx, graph *storedGraph, edges []*aggregates.Edge, pending ...events.DomainEvent) error {
+	for _, event := range pending {
+		switch e := event.(type) {
+		case events.NodesDisconnected:
+			if e.AggregateID == graph.id {
+				tx.deleteEdge(graph.id, e.SourceID.String(), e.TargetID.String())
+			}
+		case events.NodeRemovedFromGraph:
+			tx.deleteNodeEdges(graph.id, map[string]bool{e.NodeID.String(): true})
+		}
+	}
+
+	for _, edge := range edges {
+		tx.putEdge(graph.id, edge)
+	}
+
+	graph.edgeCount = tx.db.countGraphEdges(graph.id)
+	return tx.putGraph(graph)
+}
+
+// GetByID retrieves a graph with its nodes and edges
+func (r *InMemoryGraphRepository) GetByID(ctx context.Context, id aggregates.GraphID) (*aggregates.Graph, error) {
+	graph, err := r.get(id.String())
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadContents(ctx, graph, true); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// GetByUserID retrieves all graphs for a user (metadata only)
+func (r *InMemoryGraphRepository) GetByUserID(ctx context.Context, userID string) ([]*aggregates.Graph, error) {
+	return r.filter(func(g *storedGraph) bool { return g.userID == userID })
+}
+
+// GetUserDefaultGraph retrieves the user's default graph with its nodes
+func (r *InMemoryGraphRepository) GetUserDefaultGraph(ctx context.Context, userID string) (*aggregates.Graph, error) {
+	graphs, err := r.filter(func(g *storedGraph) bool {
+		return g.userID == userID && g.name == defaultGraphName
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query default graph: %w", err)
+	}
+	if len(graphs) == 0 {
+		return nil, fmt.Errorf("no default graph found for user")
+	}
+	graph := graphs[0]
+	if err := r.loadContents(ctx, graph, false); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// GetOrCreateDefaultGraph gets or creates a default graph for a user. The
+// check and the insert happen under one write lock, so concurrent callers
+// always end up with the same graph.
+func (r *InMemoryGraphRepository) GetOrCreateDefaultGraph(ctx context.Context, userID string) (*aggregates.Graph, error) {
+	if existing, err := r.GetUserDefaultGraph(ctx, userID); err == nil {
+		return existing, nil
+	}
+
+	graph, err := aggregates.NewGraph(userID, defaultGraphName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create default graph: %w", err)
+	}
+
+	created := false
+	err = r.db.update(func(tx *memTx) error {
+		for _, stored := range tx.db.graphs {
+			if stored.userID == userID && stored.name == defaultGraphName {
+				return nil
+			}
+		}
+		created = true
+		return tx.putGraph(newStoredGraph(graph))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save default graph: %w", err)
+	}
+	if !created {
+		return r.GetUserDefaultGraph(ctx, userID)
+	}
+	return graph, nil
+}
+
+// CreateDefaultGraph creates a default graph for a user (deprecated - use GetOrCreateDefaultGraph)
+func (r *InMemoryGraphRepository) CreateDefaultGraph(ctx context.Context, userID string) (*aggregates.Graph, error) {
+	return r.GetOrCreateDefaultGraph(ctx, userID)
+}
+
+// UpdateGraphMetadata recomputes the node and edge counts from stored state
+func (r *InMemoryGraphRepository) UpdateGraphMetadata(ctx context.Context, graphID string) error {
+	return r.db.update(func(tx *memTx) error {
+		current, ok := tx.db.graphs[graphID]
+		if !ok {
+			return fmt.Errorf("graph not found: %s", graphID)
+		}
+
+		updated := *current
+		updated.nodeCount = 0
+		for _, node := range tx.db.nodes {
+			if node.GraphID() == graphID {
+				updated.nodeCount++
+			}
+		}
+		updated.edgeCount = tx.db.countGraphEdges(graphID)
+		return tx.putGraph(&updated)
+	})
+}
+
+// Delete removes a graph's metadata
+func (r *InMemoryGraphRepository) Delete(ctx context.Context, id aggregates.GraphID) error {
+	return r.db.update(func(tx *memTx) error {
+		if !tx.deleteGraph(id.String()) {
+			return fmt.Errorf("failed to get graph for deletion: graph not found: %s", id.String())
+		}
+		return nil
+	})
+}
+
+// FindByNodeCount finds a user's graphs whose stored node count is within the bounds
+func (r *InMemoryGraphRepository) FindByNodeCount(ctx context.Context, userID string, minNodes, maxNodes int) ([]*aggregates.Graph, error) {
+	return r.filter(func(g *storedGraph) bool {
+		return g.userID == userID && g.nodeCount >= minNodes && (maxNodes <= 0 || g.nodeCount <= maxNodes)
+	})
+}
+
+// FindMostActive returns a user's most recently updated graphs
+func (r *InMemoryGraphRepository) FindMostActive(ctx context.Context, userID string, limit int) ([]*aggregates.Graph, error) {
+	graphs, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(graphs, func(i, j int) bool {
+		return graphs[i].UpdatedAt().After(graphs[j].UpdatedAt())
+	})
+	return paginate(graphs, 0, limit), nil
+}
+
+// FindPublicGraphs returns graphs flagged public
+func (r *InMemoryGraphRepository) FindPublicGraphs(ctx context.Context, limit int) ([]*aggregates.Graph, error) {
+	graphs, err := r.filter(func(g *storedGraph) bool { return g.isPublic })
+	if err != nil {
+		return nil, err
+	}
+	return paginate(graphs, 0, limit), nil
+}
+
+// GetGraphStatistics computes connectivity statistics from stored nodes and edges
+func (r *InMemoryGraphRepository) GetGraphStatistics(ctx context.Context, graphID aggregates.GraphID) (ports.GraphStatistics, error) {
+	var stats ports.GraphStatistics
+
+	r.db.view(func() error {
+		adjacency := r.db.adjacency(graphID.String())
+		stats.EdgeCount = r.db.countGraphEdges(graphID.String())
+
+		var nodeIDs []string
+		for id, node := range r.db.nodes {
+			if node.GraphID() == graphID.String() {
+				nodeIDs = append(nodeIDs, id)
+			}
+		}
+		sort.Strings(nodeIDs)
+
+		stats.NodeCount = len(nodeIDs)
+		totalConnections := 0
+		for _, id := range nodeIDs {
+			degree := len(adjacency[id])
+			totalConnections += degree
+			if degree == 0 {
+				stats.OrphanedNodeCount++
+			}
+			if degree > stats.MaxConnections {
+				stats.MaxConnections = degree
+			}
+		}
+		if stats.NodeCount > 0 {
+			stats.AverageConnections = float64(totalConnections) / float64(stats.NodeCount)
+		}
+
+		// Connected components over the undirected edge set
+		visited := make(map[string]bool, len(nodeIDs))
+		for _, start := range nodeIDs {
+			if visited[start] {
+				continue
+			}
+			stats.ClusterCount++
+			stack := []string{start}
+			visited[start] = true
+			for len(stack) > 0 {
+				current := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, neighbor := range adjacency[current] {
+					if !visited[neighbor] {
+						visited[neighbor] = true
+						stack = append(stack, neighbor)
+					}
+				}
+			}
+		}
+		return nil
+	})
+
+	return stats, nil
+}
+
+// CountUserGraphs counts the graphs owned by a user
+func (r *InMemoryGraphRepository) CountUserGraphs(ctx context.Context, userID string) (int, error) {
+	count := 0
+	r.db.view(func() error {
+		for _, stored := range r.db.graphs {
+			if stored.userID == userID {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, nil
+}
+
+// GetGraphsByIDs retrieves graph metadata for multiple IDs in one read
+func (r *InMemoryGraphRepository) GetGraphsByIDs(ctx context.Context, graphIDs []aggregates.GraphID) (map[aggregates.GraphID]*aggregates.Graph, error) {
+	result := make(map[aggregates.GraphID]*aggregates.Graph, len(graphIDs))
+	err := r.db.view(func() error {
+		for _, id := range graphIDs {
+			stored, ok := r.db.graphs[id.String()]
+			if !ok {
+				continue
+			}
+			graph, err := stored.toGraph()
+			if err != nil {
+				return err
+			}
+			result[id] = graph
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadContents joins the graph's stored nodes, and optionally edges, into it
+func (r *InMemoryGraphRepository) loadContents(ctx context.Context, graph *aggregates.Graph, withEdges bool) error {
+	nodes, err := r.nodeRepo.GetByGraphID(ctx, graph.ID().String())
+	if err != nil {
+		return fmt.Errorf("failed to load nodes for graph: %w", err)
+	}
+	for _, node := range nodes {
+		// Nodes the graph rejects (e.g. over its node limit) are skipped, as the
+		// DynamoDB repository does
+		graph.LoadNode(node)
+	}
+	if !withEdges {
+		return nil
+	}
+
+	edges, err := r.edgeRepo.GetByGraphID(ctx, graph.ID().String())
+	if err != nil {
+		return fmt.Errorf("failed to load edges for graph: %w", err)
+	}
+	for _, edge := range edges {
+		// Edges whose endpoints are gone are skipped the same way
+		graph.LoadEdge(edge)
+	}
+	return nil
+}
+
+func (r *InMemoryGraphRepository) get(graphID string) (*aggregates.Graph, error) {
+	var graph *aggregates.Graph
+	err := r.db.view(func() error {
+		stored, ok := r.db.graphs[graphID]
+		if !ok {
+			return fmt.Errorf("graph not found: %s", graphID)
+		}
+		var err error
+		graph, err = stored.toGraph()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// filter returns the graphs whose metadata satisfies keep, oldest first
+func (r *InMemoryGraphRepository) filter(keep func(*storedGraph) bool) ([]*aggregates.Graph, error) {
+	var matched []*storedGraph
+	r.db.view(func() error {
+		for _, stored := range r.db.graphs {
+			if keep(stored) {
+				matched = append(matched, stored)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].createdAt != matched[j].createdAt {
+			return matched[i].createdAt < matched[j].createdAt
+		}
+		return matched[i].id < matched[j].id
+	})
+
+	graphs := make([]*aggregates.Graph, 0, len(matched))
+	for _, stored := range matched {
+		graph, err := stored.toGraph()
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, graph)
+	}
+	return graphs, nil
+}
+
+func newStoredGraph(graph *aggregates.Graph) *storedGraph {
+	isPublic, _ := graph.Metadata()["isPublic"].(bool)
+	return &storedGraph{
+		id:          graph.ID().String(),
+		userID:      graph.UserID(),
+		name:        graph.Name(),
+		description: graph.Description(),
+		isDefault:   graph.IsDefault(),
+		version:     graph.Version(),
+		nodeCount:   graph.NodeCount(),
+		edgeCount:   graph.EdgeCount(),
+		isPublic:    isPublic,
+		createdAt:   graph.CreatedAt().Format(time.RFC3339Nano),
+		updatedAt:   graph.UpdatedAt().Format(time.RFC3339Nano),
+	}
+}
+
+func (g *storedGraph) toGraph() (*aggregates.Graph, error) {
+	graph, err := aggregates.ReconstructGraph(
+		g.id,
+		g.userID,
+		g.name,
+		g.description,
+		g.isDefault,
+		g.createdAt,
+		g.updatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct graph: %w", err)
+	}
+	graph.RestorePersistedState(g.version)
+	return graph, nil
+}
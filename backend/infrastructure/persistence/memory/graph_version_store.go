@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"backend/application/ports"
+	"backend/domain/versioning"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryGraphVersionStore keeps graph versions in the in-memory database.
+// Snapshots are stored encoded, so callers never share state with the stored
+// copy.
+type InMemoryGraphVersionStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.GraphVersionStore = (*InMemoryGraphVersionStore)(nil)
+
+// NewInMemoryGraphVersionStore creates a new in-memory graph version store
+func NewInMemoryGraphVersionStore(db *InMemoryDatabase) *InMemoryGraphVersionStore {
+	return &InMemoryGraphVersionStore{db: db}
+}
+
+// Save stores a new version of a graph
+func (s *InMemoryGraphVersionStore) Save(ctx context.Context, snapshot *versioning.GraphSnapshot) error {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode graph version: %w", err)
+	}
+	key := versionKey(snapshot.Version.GraphID, snapshot.Version.Version)
+	return s.db.update(func(tx *memTx) error {
+		if _, exists := tx.db.versions[key]; exists {
+			return pkgerrors.NewConflictError(fmt.Sprintf("version %d of graph %s already exists", snapshot.Version.Version, snapshot.Version.GraphID))
+		}
+		setKey(&tx.undo, tx.db.versions, key, encoded)
+		return nil
+	})
+}
+
+// Get returns a stored version of a graph
+func (s *InMemoryGraphVersionStore) Get(ctx context.Context, graphID string, version int) (*versioning.GraphSnapshot, error) {
+	var snapshot *versioning.GraphSnapshot
+	err := s.db.view(func() error {
+		encoded, ok := s.db.versions[versionKey(graphID, version)]
+		if !ok {
+			return pkgerrors.NewNotFoundError("graph version")
+		}
+		var err error
+		snapshot, err = decodeGraphSnapshot(encoded)
+		return err
+	})
+	return snapshot, err
+}
+
+// Latest returns the newest version of a graph
+func (s *InMemoryGraphVersionStore) Latest(ctx context.Context, graphID string) (*versioning.GraphSnapshot, error) {
+	var snapshot *versioning.GraphSnapshot
+	err := s.db.view(func() error {
+		keys := s.keys(graphID)
+		if len(keys) == 0 {
+			return pkgerrors.NewNotFoundError("graph version")
+		}
+		var err error
+		snapshot, err = decodeGraphSnapshot(s.db.versions[keys[len(keys)-1]])
+		return err
+	})
+	return snapshot, err
+}
+
+// List returns the version records of a graph, newest first
+func (s *InMemoryGraphVersionStore) List(ctx context.Context, graphID string) ([]*versioning.GraphVersion, error) {
+	versions := make([]*versioning.GraphVersion, 0)
+	err := s.db.view(func() error {
+		keys := s.keys(graphID)
+		for i := len(keys) - 1; i >= 0; i-- {
+			snapshot, err := decodeGraphSnapshot(s.db.versions[keys[i]])
+			if err != nil {
+				return err
+			}
+			versions = append(versions, &snapshot.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Delete removes a version of a graph
+func (s *InMemoryGraphVersionStore) Delete(ctx context.Context, graphID string, version int) error {
+	return s.db.update(func(tx *memTx) error {
+		deleteKey(&tx.undo, tx.db.versions, versionKey(graphID, version))
+		return nil
+	})
+}
+
+// keys returns the stored keys of a graph's versions, oldest first. The
+// caller holds the database lock.
+func (s *InMemoryGraphVersionStore) keys(graphID string) []string {
+	prefix := graphID + "|"
+	keys := make([]string, 0)
+	for key := range s.db.versions {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func decodeGraphSnapshot(encoded []byte) (*versioning.GraphSnapshot, error) {
+	var snapshot versioning.GraphSnapshot
+	if err := json.Unmarshal(encoded, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode graph version: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// versionKey identifies a graph version within the database. Versions are
+// zero padded so keys sort in version order.
+func versionKey(graphID string, version int) string {
+	return fmt.Sprintf("%s|%010d", graphID, version)
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryKeywordIndexStore keeps serialized keyword indexes in the in-memory
+// database
+type InMemoryKeywordIndexStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.KeywordIndexStore = (*InMemoryKeywordIndexStore)(nil)
+
+// NewInMemoryKeywordIndexStore creates a new in-memory keyword index store
+func NewInMemoryKeywordIndexStore(db *InMemoryDatabase) *InMemoryKeywordIndexStore {
+	return &InMemoryKeywordIndexStore{db: db}
+}
+
+// Load returns a user's serialized index
+func (s *InMemoryKeywordIndexStore) Load(ctx context.Context, userID string) ([]byte, error) {
+	var data []byte
+	err := s.db.view(func() error {
+		stored, ok := s.db.keywords[userID]
+		if !ok {
+			return pkgerrors.NewNotFoundError("keyword index")
+		}
+		data = append([]byte(nil), stored...)
+		return nil
+	})
+	return data, err
+}
+
+// Save stores a user's serialized index
+func (s *InMemoryKeywordIndexStore) Save(ctx context.Context, userID string, data []byte) error {
+	stored := append([]byte(nil), data...)
+	return s.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.keywords, userID, stored)
+		return nil
+	})
+}
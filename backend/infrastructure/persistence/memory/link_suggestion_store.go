@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryLinkSuggestionStore keeps link suggestions and feedback in the
+// in-memory database. Entries are stored encoded, so callers never share
+// state with the stored copy.
+type InMemoryLinkSuggestionStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.LinkSuggestionStore = (*InMemoryLinkSuggestionStore)(nil)
+
+// NewInMemoryLinkSuggestionStore creates a new in-memory link suggestion store
+func NewInMemoryLinkSuggestionStore(db *InMemoryDatabase) *InMemoryLinkSuggestionStore {
+	return &InMemoryLinkSuggestionStore{db: db}
+}
+
+// Put stores a suggestion, replacing an earlier one with the same ID
+func (s *InMemoryLinkSuggestionStore) Put(ctx context.Context, suggestion *ports.LinkSuggestion) error {
+	encoded, err := json.Marshal(suggestion)
+	if err != nil {
+		return fmt.Errorf("failed to encode link suggestion: %w", err)
+	}
+	return s.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.suggestions, suggestionKey(suggestion.UserID, suggestion.ID), encoded)
+		return nil
+	})
+}
+
+// Get returns a user's suggestion
+func (s *InMemoryLinkSuggestionStore) Get(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error) {
+	var suggestion ports.LinkSuggestion
+	err := s.db.view(func() error {
+		encoded, ok := s.db.suggestions[suggestionKey(userID, suggestionID)]
+		if !ok {
+			return pkgerrors.NewNotFoundError("link suggestion")
+		}
+		if err := json.Unmarshal(encoded, &suggestion); err != nil {
+			return fmt.Errorf("failed to decode link suggestion: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// ListByUser returns all of a user's suggestions, highest score first
+func (s *InMemoryLinkSuggestionStore) ListByUser(ctx context.Context, userID string) ([]*ports.LinkSuggestion, error) {
+	prefix := userID + "|"
+	suggestions := make([]*ports.LinkSuggestion, 0)
+	err := s.db.view(func() error {
+		keys := make([]string, 0)
+		for key := range s.db.suggestions {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var suggestion ports.LinkSuggestion
+			if err := json.Unmarshal(s.db.suggestions[key], &suggestion); err != nil {
+				return fmt.Errorf("failed to decode link suggestion: %w", err)
+			}
+			suggestions = append(suggestions, &suggestion)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions, nil
+}
+
+// Delete removes a suggestion
+func (s *InMemoryLinkSuggestionStore) Delete(ctx context.Context, userID, suggestionID string) error {
+	return s.db.update(func(tx *memTx) error {
+		deleteKey(&tx.undo, tx.db.suggestions, suggestionKey(userID, suggestionID))
+		return nil
+	})
+}
+
+// GetFeedback returns a user's feedback
+func (s *InMemoryLinkSuggestionStore) GetFeedback(ctx context.Context, userID string) (*ports.LinkFeedback, error) {
+	var feedback ports.LinkFeedback
+	err := s.db.view(func() error {
+		encoded, ok := s.db.feedback[userID]
+		if !ok {
+			return pkgerrors.NewNotFoundError("link feedback")
+		}
+		if err := json.Unmarshal(encoded, &feedback); err != nil {
+			return fmt.Errorf("failed to decode link feedback: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// PutFeedback stores a user's feedback
+func (s *InMemoryLinkSuggestionStore) PutFeedback(ctx context.Context, feedback *ports.LinkFeedback) error {
+	encoded, err := json.Marshal(feedback)
+	if err != nil {
+		return fmt.Errorf("failed to encode link feedback: %w", err)
+	}
+	return s.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.feedback, feedback.UserID, encoded)
+		return nil
+	})
+}
+
+// suggestionKey identifies a suggestion within the database
+func suggestionKey(userID, suggestionID string) string {
+	return userID + "|" + suggestionID
+}
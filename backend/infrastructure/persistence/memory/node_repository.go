@@ -0,0 +1,427 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"backend/application/ports"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryNodeRepository provides an in-memory implementation of ports.NodeRepository.
+// Saves reject a node whose version is older than the stored one.
+type InMemoryNodeRepository struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.NodeRepository = (*InMemoryNodeRepository)(nil)
+
+// NewInMemoryNodeRepository creates a new in-memory node repository
+func NewInMemoryNodeRepository(db *InMemoryDatabase) *InMemoryNodeRepository {
+	return &InMemoryNodeRepository{db: db}
+}
+
+// Save creates or replaces a node
+func (r *InMemoryNodeRepository) Save(ctx context.Context, node *entities.Node) error {
+	return r.db.update(func(tx *memTx) error {
+		return tx.putNode(node)
+	})
+}
+
+// Update replaces an existing node
+func (r *InMemoryNodeRepository) Update(ctx context.Context, node *entities.Node) error {
+	return r.Save(ctx, node)
+}
+
+// SaveWithUoW stages the node write and its uncommitted events in the unit of work
+func (r *InMemoryNodeRepository) SaveWithUoW(ctx context.Context, node *entities.Node, uow interface{}) error {
+	memUoW, ok := uow.(*InMemoryUnitOfWork)
+	if !ok {
+		return fmt.Errorf("invalid unit of work type")
+	}
+
+	// Copy now so later changes to the node do not leak into the commit
+	staged, err := cloneNode(node)
+	if err != nil {
+		return err
+	}
+	if err := memUoW.register(func(tx *memTx) error {
+		return tx.putNode(staged)
+	}); err != nil {
+		return fmt.Errorf("failed to register node save: %w", err)
+	}
+
+	for _, event := range node.GetUncommittedEvents() {
+		if err := memUoW.RegisterEvent(event); err != nil {
+			return fmt.Errorf("failed to register node event: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves a node by its ID
+func (r *InMemoryNodeRepository) GetByID(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	var node *entities.Node
+	err := r.db.view(func() error {
+		stored, ok := r.db.nodes[id.String()]
+		if !ok {
+			return fmt.Errorf("node not found: %s", id.String())
+		}
+		var err error
+		node, err = cloneNode(stored)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// FindByID retrieves a node by ID (alias for GetByID)
+func (r *InMemoryNodeRepository) FindByID(ctx context.Context, id valueobjects.NodeID) (*entities.Node, error) {
+	return r.GetByID(ctx, id)
+}
+
+// GetByUserID retrieves all nodes owned by a user
+func (r *InMemoryNodeRepository) GetByUserID(ctx context.Context, userID string) ([]*entities.Node, error) {
+	return r.filter(func(node *entities.Node) bool { return node.UserID() == userID })
+}
+
+// GetByGraphID retrieves all nodes in a graph
+func (r *InMemoryNodeRepository) GetByGraphID(ctx context.Context, graphID string) ([]*entities.Node, error) {
+	return r.filter(func(node *entities.Node) bool { return node.GraphID() == graphID })
+}
+
+// Delete removes a node
+func (r *InMemoryNodeRepository) Delete(ctx context.Context, id valueobjects.NodeID) error {
+	return r.db.update(func(tx *memTx) error {
+		if !tx.deleteNode(id.String()) {
+			return fmt.Errorf("failed to find node for deletion: node not found: %s", id.String())
+		}
+		return nil
+	})
+}
+
+// DeleteWithVersion removes a node only if it is still at the expected version
+func (r *InMemoryNodeRepository) DeleteWithVersion(ctx context.Context, id valueobjects.NodeID, expectedVersion int) error {
+	return r.db.update(func(tx *memTx) error {
+		current, ok := tx.db.nodes[id.String()]
+		if !ok {
+			return fmt.Errorf("failed to find node for deletion: node not found: %s", id.String())
+		}
+		if current.Version() != expectedVersion {
+			return pkgerrors.NewVersionConflictError("node "+id.String(), expectedVersion, current.Version())
+		}
+		tx.deleteNode(id.String())
+		return nil
+	})
+}
+
+// Search filters a user's nodes by query, tags and status, then orders and pages them
+func (r *InMemoryNodeRepository) Search(ctx context.Context, criteria ports.SearchCriteria) ([]*entities.Node, error) {
+	query := strings.ToLower(criteria.Query)
+	nodes, err := r.filter(func(node *entities.Node) bool {
+		if criteria.UserID != "" && node.UserID() != criteria.UserID {
+			return false
+		}
+		if criteria.Status != "" && string(node.Status()) != criteria.Status {
+			return false
+		}
+		if query != "" && !matchesText(node, query) {
+			return false
+		}
+		for _, tag := range criteria.Tags {
+			if !node.HasTag(tag) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		var less bool
+		switch criteria.OrderBy {
+		case "title":
+			less = nodes[i].Content().Title() < nodes[j].Content().Title()
+		case "updated_at":
+			less = nodes[i].UpdatedAt().Before(nodes[j].UpdatedAt())
+		default:
+			less = nodes[i].CreatedAt().Before(nodes[j].CreatedAt())
+		}
+		if criteria.OrderDesc {
+			return !less
+		}
+		return less
+	})
+
+	return paginate(nodes, criteria.Offset, criteria.Limit), nil
+}
+
+// BulkSave saves many nodes atomically; if any node fails, none are saved
+func (r *InMemoryNodeRepository) BulkSave(ctx context.Context, nodes []*entities.Node) error {
+	return r.db.update(func(tx *memTx) error {
+		for _, node := range nodes {
+			if err := tx.putNode(node); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch removes many nodes atomically; missing nodes are ignored
+func (r *InMemoryNodeRepository) DeleteBatch(ctx context.Context, nodeIDs []valueobjects.NodeID) error {
+	return r.db.update(func(tx *memTx) error {
+		for _, id := range nodeIDs {
+			tx.deleteNode(id.String())
+		}
+		return nil
+	})
+}
+
+// FindByTags finds a user's nodes carrying any of the given tags
+func (r *InMemoryNodeRepository) FindByTags(ctx context.Context, userID string, tags []string) ([]*entities.Node, error) {
+	if len(tags) == 0 {
+		return []*entities.Node{}, nil
+	}
+	return r.filter(func(node *entities.Node) bool {
+		if node.UserID() != userID {
+			return false
+		}
+		for _, tag := range tags {
+			if node.HasTag(tag) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FindConnectedNodes returns nodes reachable from nodeID within maxDepth hops
+func (r *InMemoryNodeRepository) FindConnectedNodes(ctx context.Context, nodeID valueobjects.NodeID, maxDepth int) ([]*entities.Node, error) {
+	if maxDepth <= 0 {
+		maxDepth = 1
+	}
+
+	result := make([]*entities.Node, 0)
+	err := r.db.view(func() error {
+		adjacency := r.db.adjacency("")
+		visited := map[string]bool{nodeID.String(): true}
+		frontier := []string{nodeID.String()}
+		for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+			var next []string
+			for _, id := range frontier {
+				for _, neighbor := range adjacency[id] {
+					if visited[neighbor] {
+						continue
+					}
+					visited[neighbor] = true
+					next = append(next, neighbor)
+
+					stored, ok := r.db.nodes[neighbor]
+					if !ok {
+						continue
+					}
+					node, err := cloneNode(stored)
+					if err != nil {
+						return err
+					}
+					result = append(result, node)
+				}
+			}
+			frontier = next
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindOrphanedNodes finds nodes in a graph with no edges
+func (r *InMemoryNodeRepository) FindOrphanedNodes(ctx context.Context, graphID string) ([]*entities.Node, error) {
+	var connected map[string][]string
+	r.db.view(func() error {
+		connected = r.db.adjacency(graphID)
+		return nil
+	})
+	return r.filter(func(node *entities.Node) bool {
+		return node.GraphID() == graphID && len(connected[node.ID().String()]) == 0
+	})
+}
+
+// FindRecentlyUpdated returns a user's most recently updated nodes
+func (r *InMemoryNodeRepository) FindRecentlyUpdated(ctx context.Context, userID string, limit int) ([]*entities.Node, error) {
+	nodes, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].UpdatedAt().After(nodes[j].UpdatedAt())
+	})
+	return paginate(nodes, 0, limit), nil
+}
+
+// FindByContentPattern finds a user's nodes whose title or body matches a regular expression
+func (r *InMemoryNodeRepository) FindByContentPattern(ctx context.Context, userID string, pattern string) ([]*entities.Node, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content pattern: %w", err)
+	}
+	return r.filter(func(node *entities.Node) bool {
+		return node.UserID() == userID &&
+			(re.MatchString(node.Content().Title()) || re.MatchString(node.Content().Body()))
+	})
+}
+
+// CountByStatus counts a user's nodes per status
+func (r *InMemoryNodeRepository) CountByStatus(ctx context.Context, userID string) (map[entities.NodeStatus]int, error) {
+	counts := make(map[entities.NodeStatus]int)
+	r.db.view(func() error {
+		for _, node := range r.db.nodes {
+			if node.UserID() == userID {
+				counts[node.Status()]++
+			}
+		}
+		return nil
+	})
+	return counts, nil
+}
+
+// GetMostConnected returns the nodes of a graph with the highest edge degree
+func (r *InMemoryNodeRepository) GetMostConnected(ctx context.Context, graphID string, limit int) ([]*entities.Node, error) {
+	var adjacency map[string][]string
+	r.db.view(func() error {
+		adjacency = r.db.adjacency(graphID)
+		return nil
+	})
+
+	nodes, err := r.GetByGraphID(ctx, graphID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return len(adjacency[nodes[i].ID().String()]) > len(adjacency[nodes[j].ID().String()])
+	})
+	return paginate(nodes, 0, limit), nil
+}
+
+// CountNodesByGraph counts the number of nodes in a graph
+func (r *InMemoryNodeRepository) CountNodesByGraph(ctx context.Context, graphID string) (int64, error) {
+	var count int64
+	r.db.view(func() error {
+		for _, node := range r.db.nodes {
+			if node.GraphID() == graphID {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, nil
+}
+
+// FindSimilarNodes finds nodes similar to the given node
+func (r *InMemoryNodeRepository) FindSimilarNodes(ctx context.Context, nodeID valueobjects.NodeID, threshold float64) ([]*entities.Node, error) {
+	node, err := r.GetByID(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source node: %w", err)
+	}
+	return r.filter(func(candidate *entities.Node) bool {
+		return candidate.UserID() == node.UserID() &&
+			!candidate.ID().Equals(nodeID) &&
+			node.IsSimilarTo(candidate, threshold)
+	})
+}
+
+// LoadNode implements aggregates.NodeLoader - loads a single node
+func (r *InMemoryNodeRepository) LoadNode(ctx context.Context, nodeID valueobjects.NodeID) (*entities.Node, error) {
+	return r.GetByID(ctx, nodeID)
+}
+
+// LoadNodes implements aggregates.NodeLoader - loads multiple nodes, skipping missing ones
+func (r *InMemoryNodeRepository) LoadNodes(ctx context.Context, nodeIDs []valueobjects.NodeID) ([]*entities.Node, error) {
+	byID, err := r.GetNodesByIDs(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*entities.Node, 0, len(byID))
+	for _, id := range nodeIDs {
+		if node, ok := byID[id]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// GetNodesByIDs retrieves multiple nodes in one read
+func (r *InMemoryNodeRepository) GetNodesByIDs(ctx context.Context, nodeIDs []valueobjects.NodeID) (map[valueobjects.NodeID]*entities.Node, error) {
+	result := make(map[valueobjects.NodeID]*entities.Node, len(nodeIDs))
+	err := r.db.view(func() error {
+		for _, id := range nodeIDs {
+			stored, ok := r.db.nodes[id.String()]
+			if !ok {
+				continue
+			}
+			node, err := cloneNode(stored)
+			if err != nil {
+				return err
+			}
+			result[id] = node
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// filter returns copies of the stored nodes that satisfy keep, ordered by node ID
+func (r *InMemoryNodeRepository) filter(keep func(*entities.Node) bool) ([]*entities.Node, error) {
+	nodes := make([]*entities.Node, 0)
+	err := r.db.view(func() error {
+		ids := make([]string, 0, len(r.db.nodes))
+		for id, stored := range r.db.nodes {
+			if keep(stored) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			node, err := cloneNode(r.db.nodes[id])
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, node)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return items[:0]
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryTrashStore keeps trashed nodes in the in-memory database. Entries are
+// stored encoded, so callers never share state with the stored copy.
+type InMemoryTrashStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.TrashStore = (*InMemoryTrashStore)(nil)
+
+// NewInMemoryTrashStore creates a new in-memory trash store
+func NewInMemoryTrashStore(db *InMemoryDatabase) *InMemoryTrashStore {
+	return &InMemoryTrashStore{db: db}
+}
+
+// Put stores a trashed node, replacing an earlier entry for the same node
+func (s *InMemoryTrashStore) Put(ctx context.Context, trashed *ports.TrashedNode) error {
+	encoded, err := json.Marshal(trashed)
+	if err != nil {
+		return fmt.Errorf("failed to encode trashed node: %w", err)
+	}
+	return s.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.trash, trashKey(trashed.UserID, trashed.NodeID), encoded)
+		return nil
+	})
+}
+
+// Get returns a user's trashed node
+func (s *InMemoryTrashStore) Get(ctx context.Context, userID, nodeID string) (*ports.TrashedNode, error) {
+	var trashed *ports.TrashedNode
+	err := s.db.view(func() error {
+		encoded, ok := s.db.trash[trashKey(userID, nodeID)]
+		if !ok {
+			return pkgerrors.NewNotFoundError("trashed node")
+		}
+		var err error
+		trashed, err = decodeTrashedNode(encoded)
+		return err
+	})
+	return trashed, err
+}
+
+// ListByUser returns a user's trashed nodes, most recently trashed first
+func (s *InMemoryTrashStore) ListByUser(ctx context.Context, userID string) ([]*ports.TrashedNode, error) {
+	prefix := userID + "|"
+	trashed, err := s.filter(func(key string, _ *ports.TrashedNode) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(trashed, func(i, j int) bool {
+		return trashed[i].TrashedAt.After(trashed[j].TrashedAt)
+	})
+	return trashed, nil
+}
+
+// ListExpired returns up to limit trashed nodes that are due to be purged
+func (s *InMemoryTrashStore) ListExpired(ctx context.Context, now time.Time, limit int) ([]*ports.TrashedNode, error) {
+	expired, err := s.filter(func(_ string, trashed *ports.TrashedNode) bool {
+		return trashed.IsExpired(now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].PurgeAt.Before(*expired[j].PurgeAt)
+	})
+	if limit > 0 && len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// Delete removes a trashed node
+func (s *InMemoryTrashStore) Delete(ctx context.Context, userID, nodeID string) error {
+	return s.db.update(func(tx *memTx) error {
+		deleteKey(&tx.undo, tx.db.trash, trashKey(userID, nodeID))
+		return nil
+	})
+}
+
+// filter decodes the stored entries the predicate keeps, in key order
+func (s *InMemoryTrashStore) filter(keep func(key string, trashed *ports.TrashedNode) bool) ([]*ports.TrashedNode, error) {
+	result := make([]*ports.TrashedNode, 0)
+	err := s.db.view(func() error {
+		keys := make([]string, 0, len(s.db.trash))
+		for key := range s.db.trash {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			trashed, err := decodeTrashedNode(s.db.trash[key])
+			if err != nil {
+				return err
+			}
+			if keep(key, trashed) {
+				result = append(result, trashed)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func decodeTrashedNode(encoded []byte) (*ports.TrashedNode, error) {
+	var trashed ports.TrashedNode
+	if err := json.Unmarshal(encoded, &trashed); err != nil {
+		return nil, fmt.Errorf("failed to decode trashed node: %w", err)
+	}
+	return &trashed, nil
+}
+
+// trashKey identifies a trashed node within the database
+func trashKey(userID, nodeID string) string {
+	return userID + "|" + nodeID
+}
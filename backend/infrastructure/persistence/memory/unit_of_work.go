@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"backend/application/ports"
+	"backend/domain/events"
+)
+
+// operation is a staged write applied inside the commit transaction
+type operation func(tx *memTx) error
+
+// InMemoryUnitOfWork stages repository writes and domain events and applies
+// them under a single write lock on Commit. If any write fails, for example on
+// a version conflict, everything applied so far is undone.
+type InMemoryUnitOfWork struct {
+	db         *InMemoryDatabase
+	nodeRepo   ports.NodeRepository
+	edgeRepo   ports.EdgeRepository
+	graphRepo  ports.GraphRepository
+	eventStore *InMemoryEventStore
+
+	mu            sync.Mutex
+	operations    []operation
+	pendingEvents []events.DomainEvent
+	inTransaction bool
+}
+
+// Compile-time interface check
+var _ ports.UnitOfWork = (*InMemoryUnitOfWork)(nil)
+
+// NewInMemoryUnitOfWork creates a unit of work over the given database
+func NewInMemoryUnitOfWork(
+	db *InMemoryDatabase,
+	nodeRepo ports.NodeRepository,
+	edgeRepo ports.EdgeRepository,
+	graphRepo ports.GraphRepository,
+	eventStore *InMemoryEventStore,
+) *InMemoryUnitOfWork {
+	return &InMemoryUnitOfWork{
+		db:         db,
+		nodeRepo:   nodeRepo,
+		edgeRepo:   edgeRepo,
+		graphRepo:  graphRepo,
+		eventStore: eventStore,
+	}
+}
+
+// Begin starts a new transaction
+func (uow *InMemoryUnitOfWork) Begin(ctx context.Context) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if uow.inTransaction {
+		return fmt.Errorf("transaction already in progress")
+	}
+	uow.inTransaction = true
+	uow.clear()
+	return nil
+}
+
+// register stages a write for the current transaction
+func (uow *InMemoryUnitOfWork) register(op operation) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	uow.operations = append(uow.operations, op)
+	return nil
+}
+
+// RegisterEvent stages a domain event to be appended to the event store on
+// commit. An event that is already staged is ignored: a graph reports its
+// nodes' events too, so saving both a node and its graph registers them twice.
+func (uow *InMemoryUnitOfWork) RegisterEvent(event events.DomainEvent) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	for _, staged := range uow.pendingEvents {
+		if sameEvent(staged, event) {
+			return nil
+		}
+	}
+	uow.pendingEvents = append(uow.pendingEvents, event)
+	return nil
+}
+
+// sameEvent reports whether two events are the same occurrence
+func sameEvent(a, b events.DomainEvent) bool {
+	return a.GetAggregateID() == b.GetAggregateID() &&
+		a.GetEventType() == b.GetEventType() &&
+		a.GetVersion() == b.GetVersion() &&
+		a.GetTimestamp().Equal(b.GetTimestamp())
+}
+
+// Commit applies all staged writes and events atomically
+func (uow *InMemoryUnitOfWork) Commit(ctx context.Context) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	defer func() {
+		uow.inTransaction = false
+		uow.clear()
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("transaction aborted: %w", err)
+	}
+
+	err := uow.db.update(func(tx *memTx) error {
+		for _, op := range uow.operations {
+			if err := op(tx); err != nil {
+				return err
+			}
+		}
+		if uow.eventStore != nil {
+			for _, event := range uow.pendingEvents {
+				tx.appendEvent(&storedEvent{event: event})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("transaction failed: %w", err)
+	}
+	return nil
+}
+
+// Rollback discards all staged writes and events
+func (uow *InMemoryUnitOfWork) Rollback() error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if !uow.inTransaction {
+		return fmt.Errorf("no transaction in progress")
+	}
+	uow.inTransaction = false
+	uow.clear()
+	return nil
+}
+
+// clear resets the staged state; callers hold uow.mu
+func (uow *InMemoryUnitOfWork) clear() {
+	uow.operations = nil
+	uow.pendingEvents = nil
+}
+
+// NodeRepository returns the node repository
+func (uow *InMemoryUnitOfWork) NodeRepository() ports.NodeRepository {
+	return uow.nodeRepo
+}
+
+// EdgeRepository returns the edge repository
+func (uow *InMemoryUnitOfWork) EdgeRepository() ports.EdgeRepository {
+	return uow.edgeRepo
+}
+
+// GraphRepository returns the graph repository
+func (uow *InMemoryUnitOfWork) GraphRepository() ports.GraphRepository {
+	return uow.graphRepo
+}
+
+// IsInTransaction returns whether a transaction is currently active
+func (uow *InMemoryUnitOfWork) IsInTransaction() bool {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+	return uow.inTransaction
+}
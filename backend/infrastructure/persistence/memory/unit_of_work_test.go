@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRepos struct {
+	db     *InMemoryDatabase
+	nodes  *InMemoryNodeRepository
+	edges  *InMemoryEdgeRepository
+	graphs *InMemoryGraphRepository
+	events *InMemoryEventStore
+	uow    *InMemoryUnitOfWork
+}
+
+func newTestRepos() *testRepos {
+	r := &testRepos{db: NewInMemoryDatabase()}
+	r.nodes = NewInMemoryNodeRepository(r.db)
+	r.edges = NewInMemoryEdgeRepository(r.db)
+	r.graphs = NewInMemoryGraphRepository(r.db, r.nodes, r.edges)
+	r.events = NewInMemoryEventStore(r.db)
+	r.uow = NewInMemoryUnitOfWork(r.db, r.nodes, r.edges, r.graphs, r.events)
+	return r
+}
+
+func newTestNode(t *testing.T, graph *aggregates.Graph, title string) *entities.Node {
+	t.Helper()
+	content, err := valueobjects.NewNodeContent(title, "body of "+title, valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	position, err := valueobjects.NewPosition3D(1, 2, 0)
+	require.NoError(t, err)
+	node, err := entities.NewNode(graph.UserID(), content, position)
+	require.NoError(t, err)
+	node.SetGraphID(graph.ID().String())
+	require.NoError(t, graph.AddNode(node))
+	return node
+}
+
+func TestUnitOfWork_CommitAppliesWritesAndEvents(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+
+	graph, err := aggregates.NewGraph("user-1", "Research")
+	require.NoError(t, err)
+	a := newTestNode(t, graph, "Alpha")
+	b := newTestNode(t, graph, "Beta")
+	_, err = graph.ConnectNodes(a.ID(), b.ID(), entities.EdgeTypeNormal)
+	require.NoError(t, err)
+
+	require.NoError(t, r.uow.Begin(ctx))
+	require.NoError(t, r.graphs.SaveWithUoW(ctx, graph, r.uow))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, a, r.uow))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, b, r.uow))
+
+	// Nothing is visible before commit
+	_, err = r.nodes.GetByID(ctx, a.ID())
+	assert.Error(t, err)
+
+	require.NoError(t, r.uow.Commit(ctx))
+
+	loaded, err := r.graphs.GetByID(ctx, graph.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.NodeCount())
+	assert.Len(t, loaded.GetEdges(), 1)
+	assert.Equal(t, graph.Version(), loaded.Version())
+
+	stored, err := r.events.GetEvents(ctx, graph.ID().String())
+	require.NoError(t, err)
+	assert.NotEmpty(t, stored)
+}
+
+func TestUnitOfWork_VersionConflictUndoesEarlierWrites(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+
+	graph, err := aggregates.NewGraph("user-1", "Scratch")
+	require.NoError(t, err)
+	node := newTestNode(t, graph, "Draft")
+	require.NoError(t, r.nodes.Save(ctx, node))
+
+	// Advance the stored node, leaving a stale copy behind
+	current, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	content, err := valueobjects.NewNodeContent("Draft v2", "newer body", valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	require.NoError(t, current.UpdateContent(content))
+	require.NoError(t, r.nodes.Save(ctx, current))
+
+	other := newTestNode(t, graph, "Other")
+
+	require.NoError(t, r.uow.Begin(ctx))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, other, r.uow))
+	require.NoError(t, r.nodes.SaveWithUoW(ctx, node, r.uow))
+	err = r.uow.Commit(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "optimistic lock failed")
+
+	// The write staged before the conflicting one was undone
+	_, err = r.nodes.GetByID(ctx, other.ID())
+	assert.Error(t, err)
+	stored, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Draft v2", stored.Content().Title())
+}
+
+func TestNodeRepository_ReturnsDetachedCopies(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+
+	graph, err := aggregates.NewGraph("user-1", "Notes")
+	require.NoError(t, err)
+	node := newTestNode(t, graph, "Original")
+	require.NoError(t, r.nodes.Save(ctx, node))
+
+	loaded, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	require.NoError(t, loaded.AddTag("unsaved"))
+
+	again, err := r.nodes.GetByID(ctx, node.ID())
+	require.NoError(t, err)
+	assert.Empty(t, again.GetTags())
+}
+
+func TestNodeRepository_BulkSaveIsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+
+	graph, err := aggregates.NewGraph("user-1", "Batch")
+	require.NoError(t, err)
+	valid := newTestNode(t, graph, "Valid")
+
+	content, err := valueobjects.NewNodeContent("Orphan", "no graph", valueobjects.FormatMarkdown)
+	require.NoError(t, err)
+	position, err := valueobjects.NewPosition3D(0, 0, 0)
+	require.NoError(t, err)
+	orphan, err := entities.NewNode("user-1", content, position)
+	require.NoError(t, err)
+
+	err = r.nodes.BulkSave(ctx, []*entities.Node{valid, orphan})
+	require.Error(t, err)
+
+	nodes, err := r.nodes.GetByUserID(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+}
+
+func TestNodeRepository_DeleteWithVersionRejectsStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+
+	graph, err := aggregates.NewGraph("user-1", "Notes")
+	require.NoError(t, err)
+	node := newTestNode(t, graph, "Versioned")
+	require.NoError(t, r.nodes.Save(ctx, node))
+	stale := node.Version()
+
+	require.NoError(t, node.Publish())
+	require.NoError(t, r.nodes.Save(ctx, node))
+
+	err = r.nodes.DeleteWithVersion(ctx, node.ID(), stale)
+	require.Error(t, err)
+	assert.True(t, pkgerrors.IsVersionConflict(err))
+	current, ok := pkgerrors.CurrentVersion(err)
+	require.True(t, ok)
+	assert.Equal(t, node.Version(), current)
+
+	require.NoError(t, r.nodes.DeleteWithVersion(ctx, node.ID(), node.Version()))
+	_, err = r.nodes.GetByID(ctx, node.ID())
+	assert.Error(t, err)
+}
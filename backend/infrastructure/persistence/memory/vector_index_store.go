@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+
+	"backend/application/ports"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InMemoryVectorIndexStore keeps serialized vector indexes in the in-memory
+// database
+type InMemoryVectorIndexStore struct {
+	db *InMemoryDatabase
+}
+
+// Compile-time interface check
+var _ ports.VectorIndexStore = (*InMemoryVectorIndexStore)(nil)
+
+// NewInMemoryVectorIndexStore creates a new in-memory vector index store
+func NewInMemoryVectorIndexStore(db *InMemoryDatabase) *InMemoryVectorIndexStore {
+	return &InMemoryVectorIndexStore{db: db}
+}
+
+// Load returns a user's serialized index
+func (s *InMemoryVectorIndexStore) Load(ctx context.Context, userID string) ([]byte, error) {
+	var data []byte
+	err := s.db.view(func() error {
+		stored, ok := s.db.vectors[userID]
+		if !ok {
+			return pkgerrors.NewNotFoundError("vector index")
+		}
+		data = append([]byte(nil), stored...)
+		return nil
+	})
+	return data, err
+}
+
+// Save stores a user's serialized index
+func (s *InMemoryVectorIndexStore) Save(ctx context.Context, userID string, data []byte) error {
+	stored := append([]byte(nil), data...)
+	return s.db.update(func(tx *memTx) error {
+		setKey(&tx.undo, tx.db.vectors, userID, stored)
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/application/services"
+	"backend/pkg/auth"
+	"backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// maxQuestionLength is the longest question accepted, in characters
+const maxQuestionLength = 2000
+
+// AskHandler answers questions about the caller's notes
+type AskHandler struct {
+	askService   *services.AskService
+	logger       *zap.Logger
+	errorHandler *errors.ErrorHandler
+}
+
+// AskRequest is the body of POST /ask
+type AskRequest struct {
+	Question string `json:"question"`
+}
+
+// AskSourcesEvent is the first event of an answer stream: the notes the
+// answer is based on, which it cites by ref
+type AskSourcesEvent struct {
+	Question string               `json:"question"`
+	Model    string               `json:"model"`
+	Tokens   int                  `json:"tokens"`
+	Sources  []services.AskSource `json:"sources"`
+}
+
+// AskDeltaEvent carries the next piece of the answer
+type AskDeltaEvent struct {
+	Text string `json:"text"`
+}
+
+// AskErrorEvent ends an answer stream that failed after it started
+type AskErrorEvent struct {
+	Message string `json:"message"`
+}
+
+// NewAskHandler creates a new ask handler
+func NewAskHandler(askService *services.AskService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AskHandler {
+	return &AskHandler{
+		askService:   askService,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Ask handles POST /ask. The answer is streamed as server-sent events: one
+// "sources" event, "delta" events with the answer text as it is generated,
+// then "done", or "error" when generation fails midway.
+func (h *AskHandler) Ask(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	var req AskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid request body"))
+		return
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Question is required"))
+		return
+	}
+	if utf8.RuneCountInString(question) > maxQuestionLength {
+		h.errorHandler.Handle(w, r, errors.NewValidationError(fmt.Sprintf("Question must be at most %d characters", maxQuestionLength)))
+		return
+	}
+
+	pack, err := h.askService.Retrieve(r.Context(), userCtx.UserID, question)
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to retrieve notes for question").WithCause(err))
+		return
+	}
+
+	// Answers take longer than the server's write timeout; the chat client
+	// bounds them instead
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event string, payload interface{}) error {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+		// Buffered writers such as the Lambda proxy send everything at the end
+		if err := rc.Flush(); err != nil && !stderrors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	if err := send("sources", AskSourcesEvent{
+		Question: pack.Question,
+		Model:    h.askService.Model(),
+		Tokens:   pack.Tokens,
+		Sources:  pack.Sources,
+	}); err != nil {
+		return
+	}
+
+	err = h.askService.Answer(r.Context(), pack, func(delta string) error {
+		return send("delta", AskDeltaEvent{Text: delta})
+	})
+	if err != nil {
+		if r.Context().Err() == nil {
+			h.logger.Error("Failed to answer question", zap.String("userID", userCtx.UserID), zap.Error(err))
+			_ = send("error", AskErrorEvent{Message: "Failed to generate answer"})
+		}
+		return
+	}
+	_ = send("done", struct{}{})
+}
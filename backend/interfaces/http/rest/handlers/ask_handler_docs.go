@@ -0,0 +1,17 @@
+package handlers
+
+// This file contains OpenAPI/Swagger documentation for AskHandler endpoints
+
+// Ask answers a question about the caller's notes
+// @Summary Ask a question about your notes
+// @Description Finds the notes that best match the question with hybrid search, adds notes strongly connected to them, and has a language model answer from as many as fit the context budget. The answer is streamed as server-sent events: "sources" (handlers.AskSourcesEvent, the notes the answer cites as [ref]), then "delta" events (handlers.AskDeltaEvent) with the answer text, then "done"; "error" (handlers.AskErrorEvent) ends a stream that fails midway.
+// @Tags ask
+// @Accept json
+// @Produce text/event-stream
+// @Param request body handlers.AskRequest true "Question"
+// @Success 200 {string} string "Server-sent event stream"
+// @Failure 400 {object} docs.ErrorResponse "Missing or too long question"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /ask [post]
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"backend/application/services"
 	"backend/pkg/auth"
@@ -30,7 +31,30 @@ func NewCommunityHandler(
 	}
 }
 
-// Recompute handles POST /communities/recompute — runs Leiden detection.
+// List handles GET /communities — returns the stored communities, which node
+// and edge events keep current.
+func (h *CommunityHandler) List(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	result, err := h.communityService.GetCommunities(r.Context(), userCtx.UserID)
+	if err != nil {
+		h.logger.Error("Failed to get communities",
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to get communities").WithCause(err))
+		return
+	}
+
+	h.respond(w, result)
+}
+
+// Recompute handles POST /communities/recompute — runs Leiden detection from
+// scratch.
 func (h *CommunityHandler) Recompute(w http.ResponseWriter, r *http.Request) {
 	userCtx, err := auth.GetUserFromContext(r.Context())
 	if err != nil {
@@ -48,6 +72,45 @@ func (h *CommunityHandler) Recompute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.respond(w, result)
+}
+
+// Tree handles GET /communities/tree — returns the communities as a topic
+// hierarchy, from broad themes down to narrow subtopics.
+func (h *CommunityHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	depth := 0
+	if raw := r.URL.Query().Get("depth"); raw != "" {
+		depth, err = strconv.Atoi(raw)
+		if err != nil || depth < 1 {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("depth must be a positive integer"))
+			return
+		}
+	}
+
+	result, err := h.communityService.GetCommunityTree(r.Context(), userCtx.UserID, depth)
+	if err != nil {
+		if errors.IsValidation(err) {
+			h.errorHandler.Handle(w, r, err)
+			return
+		}
+		h.logger.Error("Failed to build community tree",
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to build community tree").WithCause(err))
+		return
+	}
+
+	h.respond(w, result)
+}
+
+func (h *CommunityHandler) respond(w http.ResponseWriter, result interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(result); err != nil {
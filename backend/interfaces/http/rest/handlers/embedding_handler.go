@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"backend/application/services"
+	"backend/pkg/auth"
+	"backend/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// EmbeddingHandler handles the embedding maintenance endpoints
+type EmbeddingHandler struct {
+	reembedService *services.ReembedService
+	logger         *zap.Logger
+	errorHandler   *errors.ErrorHandler
+}
+
+// ReembedResponse identifies a started re-embedding job
+type ReembedResponse struct {
+	OperationID string `json:"operation_id"`
+	Status      string `json:"status"`
+	Model       string `json:"model"`
+}
+
+// NewEmbeddingHandler creates a new embedding handler
+func NewEmbeddingHandler(reembedService *services.ReembedService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *EmbeddingHandler {
+	return &EmbeddingHandler{
+		reembedService: reembedService,
+		logger:         logger,
+		errorHandler:   errorHandler,
+	}
+}
+
+// Reembed handles POST /embeddings/reembed
+func (h *EmbeddingHandler) Reembed(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	operationID, err := h.reembedService.Start(r.Context(), userCtx.UserID)
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to start re-embedding").WithCause(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(ReembedResponse{
+		OperationID: operationID,
+		Status:      "pending",
+		Model:       h.reembedService.Model(),
+	}); err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
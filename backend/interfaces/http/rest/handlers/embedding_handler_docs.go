@@ -0,0 +1,14 @@
+package handlers
+
+// This file contains OpenAPI/Swagger documentation for EmbeddingHandler endpoints
+
+// Reembed starts re-embedding the caller's nodes
+// @Summary Re-embed all nodes
+// @Description Starts a background job that embeds every node of the caller with the configured embedding model. Nodes whose embedding already matches the model and their current content are skipped, so starting the job again after an interruption resumes it. Progress is reported through GET /operations/{operationID}. While a job runs, starting another returns the running job's operation.
+// @Tags embeddings
+// @Produce json
+// @Success 202 {object} handlers.ReembedResponse "Job started"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /embeddings/reembed [post]
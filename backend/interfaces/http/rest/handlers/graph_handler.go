@@ -2,16 +2,19 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"backend/application/commands"
 	"backend/application/mediator"
 	"backend/application/queries"
 	"backend/pkg/auth"
 	"backend/pkg/errors"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +25,25 @@ type GraphHandler struct {
 	errorHandler *errors.ErrorHandler
 }
 
+// CreateGraphRequest represents the request body for creating a graph
+type CreateGraphRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateGraphRequest represents the request body for renaming a graph
+type UpdateGraphRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// TransferNodesRequest represents the request body for moving or copying
+// nodes to another graph
+type TransferNodesRequest struct {
+	TargetGraphID string   `json:"target_graph_id"`
+	NodeIDs       []string `json:"node_ids"`
+}
+
 // NewGraphHandler creates a new graph handler
 func NewGraphHandler(med mediator.IMediator, logger *zap.Logger, errorHandler *errors.ErrorHandler) *GraphHandler {
 	return &GraphHandler{
@@ -138,8 +160,187 @@ func (h *GraphHandler) GetGraphData(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, result)
 }
 
+// CreateGraph handles POST /graphs
+func (h *GraphHandler) CreateGraph(w http.ResponseWriter, r *http.Request) {
+	var req CreateGraphRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	// Generate the ID here so the created graph can be returned
+	graphID := uuid.New().String()
+	cmd := commands.CreateGraphCommand{
+		GraphID:     graphID,
+		UserID:      userCtx.UserID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	if err := h.mediator.Send(r.Context(), cmd); err != nil {
+		h.logger.Error("Failed to create graph",
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respondGraph(w, r, userCtx.UserID, graphID, http.StatusCreated)
+}
+
+// UpdateGraph handles PUT /graphs/{graphID}
+func (h *GraphHandler) UpdateGraph(w http.ResponseWriter, r *http.Request) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return
+	}
+
+	var req UpdateGraphRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	cmd := commands.UpdateGraphCommand{
+		UserID:      userCtx.UserID,
+		GraphID:     graphID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := h.mediator.Send(r.Context(), cmd); err != nil {
+		h.logger.Error("Failed to update graph",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respondGraph(w, r, userCtx.UserID, graphID, http.StatusOK)
+}
+
+// DeleteGraph handles DELETE /graphs/{graphID}
+func (h *GraphHandler) DeleteGraph(w http.ResponseWriter, r *http.Request) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	cmd := commands.DeleteGraphCommand{
+		UserID:  userCtx.UserID,
+		GraphID: graphID,
+	}
+	if err := h.mediator.Send(r.Context(), cmd); err != nil {
+		h.logger.Error("Failed to delete graph",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveNodes handles POST /graphs/{graphID}/nodes/move
+func (h *GraphHandler) MoveNodes(w http.ResponseWriter, r *http.Request) {
+	h.transferNodes(w, r, false)
+}
+
+// CopyNodes handles POST /graphs/{graphID}/nodes/copy
+func (h *GraphHandler) CopyNodes(w http.ResponseWriter, r *http.Request) {
+	h.transferNodes(w, r, true)
+}
+
 // Helper methods
 
+// transferNodes moves or copies nodes from the graph in the URL to the
+// target graph in the request body
+func (h *GraphHandler) transferNodes(w http.ResponseWriter, r *http.Request, copyNodes bool) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return
+	}
+
+	var req TransferNodesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	cmd := commands.MoveNodesToGraphCommand{
+		UserID:        userCtx.UserID,
+		SourceGraphID: graphID,
+		TargetGraphID: req.TargetGraphID,
+		NodeIDs:       req.NodeIDs,
+		Copy:          copyNodes,
+	}
+	if err := h.mediator.Send(r.Context(), cmd); err != nil {
+		h.logger.Error("Failed to transfer nodes",
+			zap.String("sourceGraphID", graphID),
+			zap.String("targetGraphID", req.TargetGraphID),
+			zap.Bool("copy", copyNodes),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"source_graph_id": graphID,
+		"target_graph_id": req.TargetGraphID,
+		"node_count":      len(req.NodeIDs),
+		"copied":          copyNodes,
+	})
+}
+
+// respondGraph writes the graph as returned by GET /graphs/{graphID}
+func (h *GraphHandler) respondGraph(w http.ResponseWriter, r *http.Request, userID, graphID string, status int) {
+	result, err := h.mediator.Query(r.Context(), queries.GetGraphByIDQuery{
+		UserID:  userID,
+		GraphID: graphID,
+	})
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to load graph").WithCause(err))
+		return
+	}
+	h.respondJSON(w, status, result)
+}
+
 func (h *GraphHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -189,3 +390,173 @@ func (h *GraphHandler) GetGraphStats(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, result)
 }
 
+// GetGraphAnalytics handles GET /graphs/{graphID}/analytics
+func (h *GraphHandler) GetGraphAnalytics(w http.ResponseWriter, r *http.Request) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	top := 10
+	if raw := r.URL.Query().Get("top"); raw != "" {
+		top, err = strconv.Atoi(raw)
+		if err != nil || top < 1 || top > 100 {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("top must be between 1 and 100"))
+			return
+		}
+	}
+
+	query := queries.GetGraphAnalyticsQuery{
+		UserID:  userCtx.UserID,
+		GraphID: graphID,
+		Top:     top,
+	}
+
+	result, err := h.mediator.Query(r.Context(), query)
+	if err != nil {
+		h.logger.Error("Failed to get graph analytics",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		if strings.Contains(err.Error(), "not found") {
+			h.errorHandler.Handle(w, r, errors.NewNotFoundError("Graph not found"))
+		} else if strings.Contains(err.Error(), "unauthorized") {
+			h.errorHandler.Handle(w, r, errors.NewForbiddenError("Access denied"))
+		} else {
+			h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to compute graph analytics").WithCause(err))
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// GetGraphReport handles GET /graphs/{graphID}/report
+func (h *GraphHandler) GetGraphReport(w http.ResponseWriter, r *http.Request) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("format must be json or markdown"))
+		return
+	}
+
+	query := queries.GenerateBrainReportQuery{
+		UserID:  userCtx.UserID,
+		GraphID: graphID,
+	}
+
+	result, err := h.mediator.Query(r.Context(), query)
+	if err != nil {
+		h.logger.Error("Failed to generate graph report",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		if strings.Contains(err.Error(), "not found") {
+			h.errorHandler.Handle(w, r, errors.NewNotFoundError("Graph not found"))
+		} else if strings.Contains(err.Error(), "unauthorized") {
+			h.errorHandler.Handle(w, r, errors.NewForbiddenError("Access denied"))
+		} else {
+			h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to generate graph report").WithCause(err))
+		}
+		return
+	}
+
+	report, ok := result.(*queries.GenerateBrainReportResult)
+	if !ok || format != "markdown" {
+		h.respondJSON(w, http.StatusOK, result)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, report.Markdown()); err != nil {
+		h.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+// FindPaths handles GET /graphs/{graphID}/paths
+func (h *GraphHandler) FindPaths(w http.ResponseWriter, r *http.Request) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return
+	}
+
+	// Get user context
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	params := r.URL.Query()
+	query := queries.FindPathsQuery{
+		UserID:   userCtx.UserID,
+		GraphID:  graphID,
+		FromID:   params.Get("from"),
+		ToID:     params.Get("to"),
+		Directed: params.Get("directed") == "true",
+	}
+	if raw := params.Get("k"); raw != "" {
+		query.K, err = strconv.Atoi(raw)
+		if err != nil || query.K < 1 {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("k must be a positive integer"))
+			return
+		}
+	}
+	if raw := params.Get("exclude"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				query.ExcludeTypes = append(query.ExcludeTypes, t)
+			}
+		}
+	}
+	if err := query.Validate(); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	result, err := h.mediator.Query(r.Context(), query)
+	if err != nil {
+		if errors.IsValidation(err) || errors.IsNotFound(err) {
+			h.errorHandler.Handle(w, r, err)
+			return
+		}
+		h.logger.Error("Failed to find paths",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		if strings.Contains(err.Error(), "not found") {
+			h.errorHandler.Handle(w, r, errors.NewNotFoundError("Graph not found"))
+		} else if strings.Contains(err.Error(), "unauthorized") {
+			h.errorHandler.Handle(w, r, errors.NewForbiddenError("Access denied"))
+		} else {
+			h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to find paths").WithCause(err))
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
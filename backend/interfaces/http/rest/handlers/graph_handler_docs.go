@@ -4,7 +4,7 @@ package handlers
 
 // CreateGraph creates a new graph
 // @Summary Create a new graph
-// @Description Creates a new graph for organizing knowledge nodes
+// @Description Creates a new graph for organizing knowledge nodes. Graph names are unique per user.
 // @Tags graphs
 // @Accept json
 // @Produce json
@@ -12,6 +12,7 @@ package handlers
 // @Success 201 {object} docs.GraphResponse "Graph created successfully"
 // @Failure 400 {object} docs.ErrorResponse "Invalid request"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 409 {object} docs.ErrorResponse "A graph with this name already exists"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /graphs [post]
@@ -48,7 +49,7 @@ package handlers
 
 // UpdateGraph updates graph properties
 // @Summary Update a graph
-// @Description Updates graph name and metadata
+// @Description Updates graph name and description. The default graph cannot be renamed.
 // @Tags graphs
 // @Accept json
 // @Produce json
@@ -57,6 +58,7 @@ package handlers
 // @Success 200 {object} docs.GraphResponse "Updated graph"
 // @Failure 400 {object} docs.ErrorResponse "Invalid request"
 // @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 409 {object} docs.ErrorResponse "A graph with this name already exists"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
@@ -64,18 +66,53 @@ package handlers
 
 // DeleteGraph deletes a graph and all its contents
 // @Summary Delete a graph
-// @Description Deletes a graph including all nodes and edges
+// @Description Deletes a graph including all nodes, edges and versions. The default graph cannot be deleted.
 // @Tags graphs
 // @Accept json
 // @Produce json
 // @Param id path string true "Graph ID"
 // @Success 204 "Graph deleted successfully"
 // @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 409 {object} docs.ErrorResponse "The default graph cannot be deleted"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /graphs/{id} [delete]
 
+// MoveNodes moves nodes to another graph
+// @Summary Move nodes to another graph
+// @Description Moves nodes and the edges between them to another graph of the user. The moved nodes get new IDs; edges to nodes that stay behind are removed. A failed move is compensated and leaves both graphs as they were.
+// @Tags graphs
+// @Accept json
+// @Produce json
+// @Param id path string true "Source graph ID"
+// @Param request body docs.TransferNodesRequest true "Target graph and nodes"
+// @Success 200 {object} map[string]interface{} "Graphs and number of nodes moved"
+// @Failure 400 {object} docs.ErrorResponse "Invalid request or the target graph would exceed its node limit"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph or node not found"
+// @Failure 409 {object} docs.ErrorResponse "Another transfer into the target graph is in progress"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{id}/nodes/move [post]
+
+// CopyNodes copies nodes to another graph
+// @Summary Copy nodes to another graph
+// @Description Copies nodes and the edges between them to another graph of the user. The copies get new IDs; the originals are left untouched.
+// @Tags graphs
+// @Accept json
+// @Produce json
+// @Param id path string true "Source graph ID"
+// @Param request body docs.TransferNodesRequest true "Target graph and nodes"
+// @Success 200 {object} map[string]interface{} "Graphs and number of nodes copied"
+// @Failure 400 {object} docs.ErrorResponse "Invalid request or the target graph would exceed its node limit"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph or node not found"
+// @Failure 409 {object} docs.ErrorResponse "Another transfer into the target graph is in progress"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{id}/nodes/copy [post]
+
 // GetGraphStatistics retrieves graph statistics
 // @Summary Get graph statistics
 // @Description Retrieves detailed statistics and metrics for a graph
@@ -88,4 +125,57 @@ package handlers
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
-// @Router /graphs/{id}/stats [get]
\ No newline at end of file
+// @Router /graphs/{id}/stats [get]
+
+// GetGraphAnalytics ranks the nodes of a graph by centrality
+// @Summary Get graph analytics
+// @Description Computes PageRank, betweenness and eigenvector centrality, k-core numbers, bridges and articulation points of a graph in one pass, treating edges as undirected. Results are cached until the graph's nodes or edges change.
+// @Tags graphs
+// @Produce json
+// @Param id path string true "Graph ID"
+// @Param top query int false "Entries of each ranked list, 1 to 100" default:"10"
+// @Success 200 {object} queries.GetGraphAnalyticsResult "Ranked nodes and structural weak points"
+// @Failure 400 {object} docs.ErrorResponse "Invalid parameters"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Access denied"
+// @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{id}/analytics [get]
+
+// GetGraphReport summarises the shape of a graph
+// @Summary Get graph report
+// @Description Reports the largest communities with their keywords, hub notes by PageRank, bridge notes linking several communities with the impact of removing them, the newest clusters, orphaned notes, growth over the last 30 days and suggested follow-ups. Reports are cached until the graph's nodes, edges or communities change.
+// @Tags graphs
+// @Produce json
+// @Produce text/markdown
+// @Param id path string true "Graph ID"
+// @Param format query string false "json or markdown" default:"json"
+// @Success 200 {object} queries.GenerateBrainReportResult "Graph report"
+// @Failure 400 {object} docs.ErrorResponse "Invalid format"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Access denied"
+// @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{id}/report [get]
+
+// FindPaths finds the cheapest paths between two nodes
+// @Summary Find paths between nodes
+// @Description Finds up to k cheapest loopless paths between two nodes. An edge costs its type factor divided by its weight, so strong, heavy links are preferred over weak or light ones. Each hop explains the link it follows. The path list is empty when the nodes are not connected.
+// @Tags graphs
+// @Produce json
+// @Param id path string true "Graph ID"
+// @Param from query string true "Start node ID"
+// @Param to query string true "End node ID"
+// @Param k query int false "Alternative paths returned, 1 to 10" default:"3"
+// @Param exclude query string false "Comma separated edge types the paths may not use"
+// @Param directed query bool false "Only follow edges from source to target" default:"false"
+// @Success 200 {object} queries.FindPathsResult "Paths, cheapest first"
+// @Failure 400 {object} docs.ErrorResponse "Invalid parameters"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Access denied"
+// @Failure 404 {object} docs.ErrorResponse "Graph or node not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{id}/paths [get]
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/application/commands"
+	"backend/application/mediator"
+	"backend/application/services"
+	"backend/pkg/auth"
+	"backend/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GraphVersionHandler handles the graph version endpoints
+type GraphVersionHandler struct {
+	versionService *services.GraphVersionService
+	mediator       mediator.IMediator
+	logger         *zap.Logger
+	errorHandler   *errors.ErrorHandler
+}
+
+// CreateGraphVersionRequest names a new graph version
+type CreateGraphVersionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// NewGraphVersionHandler creates a new graph version handler
+func NewGraphVersionHandler(
+	versionService *services.GraphVersionService,
+	med mediator.IMediator,
+	logger *zap.Logger,
+	errorHandler *errors.ErrorHandler,
+) *GraphVersionHandler {
+	return &GraphVersionHandler{
+		versionService: versionService,
+		mediator:       med,
+		logger:         logger,
+		errorHandler:   errorHandler,
+	}
+}
+
+// ListVersions handles GET /graphs/{graphID}/versions
+func (h *GraphVersionHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	graphID, ok := h.graphID(w, r)
+	if !ok {
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	versions, err := h.versionService.ListVersions(r.Context(), userCtx.UserID, graphID)
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respond(w, http.StatusOK, map[string]interface{}{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+// CreateVersion handles POST /graphs/{graphID}/versions
+func (h *GraphVersionHandler) CreateVersion(w http.ResponseWriter, r *http.Request) {
+	graphID, ok := h.graphID(w, r)
+	if !ok {
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	var req CreateGraphVersionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid request body"))
+			return
+		}
+	}
+
+	version, err := h.versionService.CreateVersion(r.Context(), userCtx.UserID, graphID, req.Name, req.Description)
+	if err != nil {
+		h.logger.Error("Failed to create graph version",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respond(w, http.StatusCreated, version)
+}
+
+// GetVersion handles GET /graphs/{graphID}/versions/{version}
+func (h *GraphVersionHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	graphID, ok := h.graphID(w, r)
+	if !ok {
+		return
+	}
+	version, ok := h.version(w, r, chi.URLParam(r, "version"))
+	if !ok {
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	snapshot, err := h.versionService.GetVersion(r.Context(), userCtx.UserID, graphID, version)
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respond(w, http.StatusOK, snapshot)
+}
+
+// DiffVersions handles GET /graphs/{graphID}/versions/diff?from=&to=
+func (h *GraphVersionHandler) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	graphID, ok := h.graphID(w, r)
+	if !ok {
+		return
+	}
+	from, ok := h.version(w, r, r.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+	// Without a to version the from version is compared with the current graph
+	to := 0
+	if raw := r.URL.Query().Get("to"); raw != "" {
+		if to, ok = h.version(w, r, raw); !ok {
+			return
+		}
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	diff, err := h.versionService.Diff(r.Context(), userCtx.UserID, graphID, from, to)
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respond(w, http.StatusOK, diff)
+}
+
+// Rollback handles POST /graphs/{graphID}/versions/{version}/rollback
+func (h *GraphVersionHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	graphID, ok := h.graphID(w, r)
+	if !ok {
+		return
+	}
+	version, ok := h.version(w, r, chi.URLParam(r, "version"))
+	if !ok {
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	cmd := commands.RollbackGraphCommand{
+		UserID:  userCtx.UserID,
+		GraphID: graphID,
+		Version: version,
+	}
+	if err := h.mediator.Send(r.Context(), cmd); err != nil {
+		h.logger.Error("Failed to roll back graph",
+			zap.String("graphID", graphID),
+			zap.Int("version", version),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	// The rollback records the restored state as the graph's newest version
+	versions, err := h.versionService.ListVersions(r.Context(), userCtx.UserID, graphID)
+	if err != nil || len(versions) == 0 {
+		h.respond(w, http.StatusOK, map[string]interface{}{
+			"graph_id":    graphID,
+			"restored_to": version,
+		})
+		return
+	}
+	h.respond(w, http.StatusOK, map[string]interface{}{
+		"graph_id":    graphID,
+		"restored_to": version,
+		"version":     versions[0],
+	})
+}
+
+func (h *GraphVersionHandler) graphID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	graphID := chi.URLParam(r, "graphID")
+	if graphID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Graph ID is required"))
+		return "", false
+	}
+	return graphID, true
+}
+
+func (h *GraphVersionHandler) version(w http.ResponseWriter, r *http.Request, raw string) (int, bool) {
+	version, err := strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Version must be a positive number"))
+		return 0, false
+	}
+	return version, true
+}
+
+func (h *GraphVersionHandler) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
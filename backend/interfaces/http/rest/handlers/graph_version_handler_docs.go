@@ -0,0 +1,80 @@
+package handlers
+
+// This file contains OpenAPI/Swagger documentation for GraphVersionHandler endpoints
+
+// ListVersions lists the versions of a graph
+// @Summary List graph versions
+// @Description Lists the recorded versions of a graph, newest first. Only the version records are returned, not the graph state.
+// @Tags versions
+// @Produce json
+// @Param graphID path string true "Graph ID"
+// @Success 200 {object} map[string]interface{} "Versions and their count"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{graphID}/versions [get]
+
+// CreateVersion records the current state of a graph
+// @Summary Create a graph version
+// @Description Records the current nodes and edges of a graph as its next version. The oldest versions are pruned beyond the retention limit.
+// @Tags versions
+// @Accept json
+// @Produce json
+// @Param graphID path string true "Graph ID"
+// @Param request body handlers.CreateGraphVersionRequest false "Version name and description"
+// @Success 201 {object} versioning.GraphVersion "Created version"
+// @Failure 400 {object} docs.ErrorResponse "Invalid request body"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 409 {object} docs.ErrorResponse "A version was recorded concurrently"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{graphID}/versions [post]
+
+// GetVersion returns a version of a graph
+// @Summary Get a graph version
+// @Description Returns a version of a graph together with the nodes and edges it recorded
+// @Tags versions
+// @Produce json
+// @Param graphID path string true "Graph ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} versioning.GraphSnapshot "Version with its graph state"
+// @Failure 400 {object} docs.ErrorResponse "Invalid version"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph or version not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{graphID}/versions/{version} [get]
+
+// DiffVersions compares two versions of a graph
+// @Summary Diff graph versions
+// @Description Lists the nodes and edges added, removed and updated between two versions. Without to, the from version is compared with the current graph.
+// @Tags versions
+// @Produce json
+// @Param graphID path string true "Graph ID"
+// @Param from query int true "Version to compare from"
+// @Param to query int false "Version to compare to"
+// @Success 200 {object} versioning.SnapshotDiff "Differences between the versions"
+// @Failure 400 {object} docs.ErrorResponse "Invalid version"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph or version not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{graphID}/versions/diff [get]
+
+// Rollback restores a graph to a previous version
+// @Summary Roll back a graph
+// @Description Restores the nodes and edges of a graph to a previous version. The state before the rollback is recorded as a version first, so a rollback can itself be undone. A failed rollback is compensated and leaves the graph as it was.
+// @Tags versions
+// @Produce json
+// @Param graphID path string true "Graph ID"
+// @Param version path int true "Version to restore"
+// @Success 200 {object} map[string]interface{} "Restored version and the version recording the result"
+// @Failure 400 {object} docs.ErrorResponse "Invalid version"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph or version not found"
+// @Failure 409 {object} docs.ErrorResponse "Another rollback of the graph is in progress"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /graphs/{graphID}/versions/{version}/rollback [post]
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/pkg/auth"
+	"backend/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LinkSuggestionHandler handles the link suggestion endpoints
+type LinkSuggestionHandler struct {
+	linkService  *services.LinkPredictionService
+	logger       *zap.Logger
+	errorHandler *errors.ErrorHandler
+}
+
+// LinkSuggestionsResponse lists pending link suggestions together with the
+// score a suggestion must reach to be offered to the caller
+type LinkSuggestionsResponse struct {
+	Suggestions []*ports.LinkSuggestion `json:"suggestions"`
+	Count       int                     `json:"count"`
+	Threshold   float64                 `json:"threshold"`
+}
+
+// NewLinkSuggestionHandler creates a new link suggestion handler
+func NewLinkSuggestionHandler(linkService *services.LinkPredictionService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *LinkSuggestionHandler {
+	return &LinkSuggestionHandler{
+		linkService:  linkService,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// List handles GET /link-suggestions
+func (h *LinkSuggestionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	suggestions, err := h.linkService.List(r.Context(), userCtx.UserID, r.URL.Query().Get("graph_id"))
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to list link suggestions").WithCause(err))
+		return
+	}
+	h.respondList(w, r, userCtx.UserID, suggestions)
+}
+
+// Refresh handles POST /link-suggestions/refresh
+func (h *LinkSuggestionHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("limit must be a positive integer"))
+			return
+		}
+	}
+	graphID := r.URL.Query().Get("graph_id")
+
+	suggestions, err := h.linkService.Refresh(r.Context(), userCtx.UserID, graphID, limit)
+	if err != nil {
+		h.logger.Error("Failed to refresh link suggestions",
+			zap.String("graphID", graphID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.handleError(w, r, err, "Failed to refresh link suggestions")
+		return
+	}
+	h.respondList(w, r, userCtx.UserID, suggestions)
+}
+
+// Accept handles POST /link-suggestions/{suggestionID}/accept
+func (h *LinkSuggestionHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, "accept", h.linkService.Accept)
+}
+
+// Reject handles POST /link-suggestions/{suggestionID}/reject
+func (h *LinkSuggestionHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, "reject", h.linkService.Reject)
+}
+
+func (h *LinkSuggestionHandler) resolve(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	apply func(ctx context.Context, userID, suggestionID string) (*ports.LinkSuggestion, error),
+) {
+	suggestionID := chi.URLParam(r, "suggestionID")
+	if _, err := uuid.Parse(suggestionID); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid suggestion ID format"))
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	suggestion, err := apply(r.Context(), userCtx.UserID, suggestionID)
+	if err != nil {
+		h.logger.Error("Failed to "+action+" link suggestion",
+			zap.String("suggestionID", suggestionID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.handleError(w, r, err, "Failed to "+action+" link suggestion")
+		return
+	}
+	h.respond(w, http.StatusOK, suggestion)
+}
+
+func (h *LinkSuggestionHandler) respondList(w http.ResponseWriter, r *http.Request, userID string, suggestions []*ports.LinkSuggestion) {
+	feedback, err := h.linkService.Feedback(r.Context(), userID)
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to load link feedback").WithCause(err))
+		return
+	}
+	h.respond(w, http.StatusOK, LinkSuggestionsResponse{
+		Suggestions: suggestions,
+		Count:       len(suggestions),
+		Threshold:   feedback.Threshold,
+	})
+}
+
+func (h *LinkSuggestionHandler) handleError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.IsValidation(err), errors.IsNotFound(err), errors.IsConflict(err):
+		h.errorHandler.Handle(w, r, err)
+	case strings.Contains(err.Error(), "not found"):
+		h.errorHandler.Handle(w, r, errors.NewNotFoundError("Graph not found"))
+	case strings.Contains(err.Error(), "unauthorized"):
+		h.errorHandler.Handle(w, r, errors.NewForbiddenError("Access denied"))
+	default:
+		h.errorHandler.Handle(w, r, errors.NewInternalError(message).WithCause(err))
+	}
+}
+
+func (h *LinkSuggestionHandler) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
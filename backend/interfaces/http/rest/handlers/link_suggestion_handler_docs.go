@@ -0,0 +1,61 @@
+package handlers
+
+// This file contains OpenAPI/Swagger documentation for LinkSuggestionHandler endpoints
+
+// List lists pending link suggestions
+// @Summary List link suggestions
+// @Description Lists the caller's pending link suggestions, highest score first, with the score a suggestion must currently reach to be offered
+// @Tags link-suggestions
+// @Produce json
+// @Param graph_id query string false "Only list suggestions for this graph"
+// @Success 200 {object} handlers.LinkSuggestionsResponse "Pending suggestions"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /link-suggestions [get]
+
+// Refresh predicts missing edges
+// @Summary Refresh link suggestions
+// @Description Scores the missing edges of a graph from shared neighbours (common neighbours and Adamic-Adar), community co-membership and content similarity, and replaces its pending suggestions with the best ones above the caller's threshold. Pairs the caller already accepted or rejected are never suggested again.
+// @Tags link-suggestions
+// @Produce json
+// @Param graph_id query string false "Graph ID (defaults to the caller's default graph)"
+// @Param limit query int false "Maximum number of suggestions (default 20, max 100)"
+// @Success 200 {object} handlers.LinkSuggestionsResponse "Pending suggestions for the graph"
+// @Failure 400 {object} docs.ErrorResponse "Invalid limit"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Access denied"
+// @Failure 404 {object} docs.ErrorResponse "Graph not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /link-suggestions/refresh [post]
+
+// Accept accepts a link suggestion
+// @Summary Accept a link suggestion
+// @Description Creates the suggested edge, weighted by the suggestion's score, and lowers the caller's threshold when the score was below it
+// @Tags link-suggestions
+// @Produce json
+// @Param suggestionID path string true "Suggestion ID"
+// @Success 200 {object} ports.LinkSuggestion "Accepted suggestion"
+// @Failure 400 {object} docs.ErrorResponse "Invalid suggestion ID"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Suggestion or one of its nodes not found"
+// @Failure 409 {object} docs.ErrorResponse "Suggestion already resolved"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /link-suggestions/{suggestionID}/accept [post]
+
+// Reject rejects a link suggestion
+// @Summary Reject a link suggestion
+// @Description Dismisses a suggestion for good and raises the caller's threshold when the score was above it
+// @Tags link-suggestions
+// @Produce json
+// @Param suggestionID path string true "Suggestion ID"
+// @Success 200 {object} ports.LinkSuggestion "Rejected suggestion"
+// @Failure 400 {object} docs.ErrorResponse "Invalid suggestion ID"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Suggestion not found"
+// @Failure 409 {object} docs.ErrorResponse "Suggestion already resolved"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /link-suggestions/{suggestionID}/reject [post]
@@ -49,6 +49,7 @@ type CreateNodeRequest struct {
 	Y       *float64 `json:"y,omitempty"` // Optional, will be auto-generated if not provided
 	Z       *float64 `json:"z,omitempty"`
 	Tags    []string `json:"tags,omitempty" validate:"omitempty,max=10,dive,max=50"`
+	GraphID string   `json:"graph_id,omitempty"` // Optional, the user's default graph if not provided
 }
 
 // UpdateNodeRequest represents the request body for updating a node
@@ -146,6 +147,7 @@ func (h *NodeHandler) CreateNode(w http.ResponseWriter, r *http.Request) {
 		Y:       y,
 		Z:       z,
 		Tags:    req.Tags,
+		GraphID: req.GraphID,
 	}
 
 	// Execute command
@@ -154,7 +156,10 @@ func (h *NodeHandler) CreateNode(w http.ResponseWriter, r *http.Request) {
 			zap.String("userID", userCtx.UserID),
 			zap.Error(err),
 		)
-		if strings.Contains(err.Error(), "validation") {
+		if errors.IsNotFound(err) || errors.IsValidation(err) {
+			// An unknown target graph, or one at its node limit
+			h.errorHandler.Handle(w, r, err)
+		} else if strings.Contains(err.Error(), "validation") {
 			h.errorHandler.Handle(w, r, errors.NewValidationError(err.Error()))
 		} else {
 			h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to create node").WithCause(err))
@@ -214,6 +219,81 @@ func (h *NodeHandler) GetNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The ETag carries the node version for conditional updates and deletes
+	if node, ok := result.(*queries.GetNodeResult); ok {
+		etag := versionETag(node.Version)
+		w.Header().Set("ETag", etag)
+		if match := r.Header.Get("If-None-Match"); match != "" && strings.TrimPrefix(match, "W/") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// GetRelatedNodes handles GET /nodes/{nodeID}/related — nodes ranked by
+// personalized PageRank from the node and any further seeds, optionally
+// blended with semantic similarity
+func (h *NodeHandler) GetRelatedNodes(w http.ResponseWriter, r *http.Request) {
+	nodeID := chi.URLParam(r, "nodeID")
+	if nodeID == "" {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Node ID is required"))
+		return
+	}
+
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	params := r.URL.Query()
+	var seeds []string
+	for _, seed := range strings.Split(params.Get("seeds"), ",") {
+		if seed = strings.TrimSpace(seed); seed != "" {
+			seeds = append(seeds, seed)
+		}
+	}
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	maxPaths, _ := strconv.Atoi(params.Get("paths"))
+	semanticWeight := 0.0
+	if raw := params.Get("semantic"); raw != "" {
+		semanticWeight, err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("semantic must be a number between 0 and 1"))
+			return
+		}
+	}
+
+	query := &queries.GetRelatedNodesQuery{
+		UserID:         userCtx.UserID,
+		NodeID:         nodeID,
+		SeedIDs:        seeds,
+		Limit:          limit,
+		SemanticWeight: semanticWeight,
+		MaxPaths:       maxPaths,
+	}
+	if err := query.Validate(); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	result, err := h.mediator.Query(r.Context(), query)
+	if err != nil {
+		if errors.IsValidation(err) || errors.IsNotFound(err) {
+			h.errorHandler.Handle(w, r, err)
+			return
+		}
+		h.logger.Error("Failed to get related nodes",
+			zap.String("nodeID", nodeID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to get related nodes").WithCause(err))
+		return
+	}
+
 	h.respondJSON(w, http.StatusOK, result)
 }
 
@@ -231,6 +311,12 @@ func (h *NodeHandler) UpdateNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
 	var req UpdateNodeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid request body: "+err.Error()))
@@ -261,6 +347,8 @@ func (h *NodeHandler) UpdateNode(w http.ResponseWriter, r *http.Request) {
 		Y:       req.Y,
 		Z:       req.Z,
 		Tags:    req.Tags,
+
+		ExpectedVersion: expectedVersion,
 	}
 
 	// Execute command
@@ -270,7 +358,9 @@ func (h *NodeHandler) UpdateNode(w http.ResponseWriter, r *http.Request) {
 			zap.String("userID", userCtx.UserID),
 			zap.Error(err),
 		)
-		if strings.Contains(err.Error(), "not found") {
+		if errors.IsVersionConflict(err) {
+			h.handleVersionConflict(w, r, err, expectedVersion != nil)
+		} else if strings.Contains(err.Error(), "not found") {
 			h.errorHandler.Handle(w, r, errors.NewNotFoundError("Node not found"))
 		} else if strings.Contains(err.Error(), "validation") {
 			h.errorHandler.Handle(w, r, errors.NewValidationError(err.Error()))
@@ -366,10 +456,17 @@ func (h *NodeHandler) DeleteNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
 	// Create command
 	cmd := commands.DeleteNodeCommand{
-		UserID: userCtx.UserID,
-		NodeID: nodeID,
+		UserID:          userCtx.UserID,
+		NodeID:          nodeID,
+		ExpectedVersion: expectedVersion,
 	}
 
 	// Execute command
@@ -379,7 +476,9 @@ func (h *NodeHandler) DeleteNode(w http.ResponseWriter, r *http.Request) {
 			zap.String("userID", userCtx.UserID),
 			zap.Error(err),
 		)
-		if strings.Contains(err.Error(), "not found") {
+		if errors.IsVersionConflict(err) {
+			h.handleVersionConflict(w, r, err, expectedVersion != nil)
+		} else if strings.Contains(err.Error(), "not found") {
 			h.errorHandler.Handle(w, r, errors.NewNotFoundError("Node not found"))
 		} else {
 			h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to delete node").WithCause(err))
@@ -430,6 +529,51 @@ func (h *NodeHandler) ListNodes(w http.ResponseWriter, r *http.Request) {
 
 // Helper methods
 
+// handleVersionConflict reports a write based on a stale node version. A
+// failed If-Match precondition is answered with 412 and the current version
+// as ETag; a conflict with a concurrent write without one stays a 409.
+func (h *NodeHandler) handleVersionConflict(w http.ResponseWriter, r *http.Request, err error, conditional bool) {
+	if current, ok := errors.CurrentVersion(err); ok {
+		w.Header().Set("ETag", versionETag(current))
+	}
+	conflict := errors.GetAppError(err)
+	if conditional {
+		// The error may be shared further up the call chain; answer with a
+		// copy rather than changing its status in place
+		precondition := *conflict
+		precondition.HTTPStatus = http.StatusPreconditionFailed
+		conflict = &precondition
+	}
+	h.errorHandler.Handle(w, r, conflict)
+}
+
+// versionETag formats an aggregate version as an entity tag
+func versionETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// parseIfMatch returns the version required by the If-Match header, or nil
+// when the request is unconditional. "*" only requires the node to exist,
+// which every update and delete already does.
+func parseIfMatch(r *http.Request) (*int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	if strings.Contains(header, ",") {
+		return nil, errors.NewValidationError("If-Match must name a single version")
+	}
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return nil, errors.NewValidationError("If-Match must be a quoted entity tag")
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return nil, errors.NewValidationError("If-Match does not name a node version")
+	}
+	return &version, nil
+}
+
 func (h *NodeHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
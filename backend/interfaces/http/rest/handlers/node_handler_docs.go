@@ -4,7 +4,7 @@ package handlers
 
 // CreateNode creates a new node with automatic edge discovery
 // @Summary Create a new knowledge node
-// @Description Creates a new node in the graph with automatic edge discovery based on content similarity
+// @Description Creates a new node in the graph with automatic edge discovery based on content similarity. The node goes into the user's default graph unless graph_id names another of their graphs.
 // @Tags nodes
 // @Accept json
 // @Produce json
@@ -12,6 +12,7 @@ package handlers
 // @Success 201 {object} docs.CreateNodeResponse "Node created successfully"
 // @Failure 400 {object} docs.ErrorResponse "Invalid request parameters"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Graph not found"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /nodes [post]
@@ -23,13 +24,34 @@ package handlers
 // @Accept json
 // @Produce json
 // @Param id path string true "Node ID" example:"550e8400-e29b-41d4-a716-446655440000"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} docs.NodeResponse "Node details"
+// @Header 200 {string} ETag "Node version, for If-Match on update and delete"
+// @Success 304 "Cached copy is current"
 // @Failure 404 {object} docs.ErrorResponse "Node not found"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /nodes/{id} [get]
 
+// GetRelatedNodes ranks the nodes related to a node
+// @Summary Get related nodes
+// @Description Ranks nodes by personalized PageRank, a random walk over weighted edges that restarts at the node and any further seeds, optionally blended with embedding similarity to the seeds. Each related node lists the strongest paths from the seeds that explain its rank.
+// @Tags nodes
+// @Produce json
+// @Param id path string true "Node ID" example:"550e8400-e29b-41d4-a716-446655440000"
+// @Param seeds query string false "Comma separated IDs of further seed nodes"
+// @Param limit query int false "Related nodes to return" default:"10"
+// @Param semantic query number false "Share of the score from semantic similarity, 0 to 1" default:"0"
+// @Param paths query int false "Paths explaining each related node" default:"3"
+// @Success 200 {object} queries.GetRelatedNodesResult "Related nodes, most related first"
+// @Failure 400 {object} docs.ErrorResponse "Invalid parameters"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Seed node not found"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /nodes/{id}/related [get]
+
 // UpdateNode updates an existing node
 // @Summary Update a node
 // @Description Updates node properties including title, content, tags, and position
@@ -38,9 +60,12 @@ package handlers
 // @Produce json
 // @Param id path string true "Node ID"
 // @Param request body docs.UpdateNodeRequest true "Update request"
+// @Param If-Match header string false "ETag from GET; the update fails if the node changed since"
 // @Success 200 {object} docs.NodeResponse "Updated node"
 // @Failure 400 {object} docs.ErrorResponse "Invalid request"
 // @Failure 404 {object} docs.ErrorResponse "Node not found"
+// @Failure 409 {object} docs.ErrorResponse "Concurrent update"
+// @Failure 412 {object} docs.ErrorResponse "Node changed since the If-Match version; ETag holds the current version"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
@@ -54,8 +79,11 @@ package handlers
 // @Produce json
 // @Param id path string true "Node ID"
 // @Param delete_edges query bool false "Delete associated edges" default:"true"
+// @Param If-Match header string false "ETag from GET; the delete fails if the node changed since"
 // @Success 204 "Node deleted successfully"
 // @Failure 404 {object} docs.ErrorResponse "Node not found"
+// @Failure 409 {object} docs.ErrorResponse "Concurrent update"
+// @Failure 412 {object} docs.ErrorResponse "Node changed since the If-Match version; ETag holds the current version"
 // @Failure 401 {object} docs.ErrorResponse "Unauthorized"
 // @Failure 500 {object} docs.ErrorResponse "Internal server error"
 // @Security BearerAuth
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/application/ports"
+	"backend/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// defaultDeadLetterLimit is the page size of the dead-letter listing
+const defaultDeadLetterLimit = 50
+
+// OutboxHandler handles the admin endpoints of the event outbox
+type OutboxHandler struct {
+	outbox       ports.Outbox
+	logger       *zap.Logger
+	errorHandler *errors.ErrorHandler
+}
+
+// NewOutboxHandler creates a new outbox handler
+func NewOutboxHandler(outbox ports.Outbox, logger *zap.Logger, errorHandler *errors.ErrorHandler) *OutboxHandler {
+	return &OutboxHandler{
+		outbox:       outbox,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// GetStats handles GET /admin/outbox/stats
+func (h *OutboxHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.outbox.Stats(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to read outbox stats").WithCause(err))
+		return
+	}
+	h.respond(w, http.StatusOK, stats)
+}
+
+// ListDeadLetters handles GET /admin/outbox/dead-letters
+func (h *OutboxHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit := defaultDeadLetterLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 100 {
+			h.errorHandler.Handle(w, r, errors.NewValidationError("limit must be between 1 and 100"))
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := h.outbox.ListDeadLettered(r.Context(), limit)
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to list dead-lettered events").WithCause(err))
+		return
+	}
+	h.respond(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// GetEntry handles GET /admin/outbox/entries/{entryID}
+func (h *OutboxHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.outbox.GetEntry(r.Context(), chi.URLParam(r, "entryID"))
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+	h.respond(w, http.StatusOK, entry)
+}
+
+// Redrive handles POST /admin/outbox/entries/{entryID}/redrive
+func (h *OutboxHandler) Redrive(w http.ResponseWriter, r *http.Request) {
+	entryID := chi.URLParam(r, "entryID")
+	entry, err := h.outbox.Redrive(r.Context(), entryID)
+	if err != nil {
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.logger.Info("Redrove dead-lettered event",
+		zap.String("entryID", entryID),
+		zap.String("eventType", entry.EventType),
+		zap.String("aggregateID", entry.AggregateID),
+	)
+	h.respond(w, http.StatusOK, entry)
+}
+
+func (h *OutboxHandler) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
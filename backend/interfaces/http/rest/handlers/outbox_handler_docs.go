@@ -0,0 +1,58 @@
+package handlers
+
+// This file contains OpenAPI/Swagger documentation for OutboxHandler endpoints
+
+// GetStats reports the outbox backlog
+// @Summary Get outbox stats
+// @Description Counts pending and dead-lettered events and reports when the oldest pending event was raised
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ports.OutboxStats "Outbox backlog"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Admin role required"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/outbox/stats [get]
+
+// ListDeadLetters lists dead-lettered events
+// @Summary List dead-lettered events
+// @Description Lists events that exhausted their delivery attempts, most recently parked first
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Maximum number of entries (1-100)" default:"50"
+// @Success 200 {object} map[string]interface{} "Dead-lettered entries and their count"
+// @Failure 400 {object} docs.ErrorResponse "Invalid limit"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Admin role required"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/outbox/dead-letters [get]
+
+// GetEntry inspects an outbox entry
+// @Summary Get outbox entry
+// @Description Retrieves a stored event with its payload, delivery status, attempts and last error
+// @Tags admin
+// @Produce json
+// @Param entryID path string true "Outbox entry ID"
+// @Success 200 {object} ports.OutboxEntry "Outbox entry"
+// @Failure 400 {object} docs.ErrorResponse "Invalid entry ID"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Admin role required"
+// @Failure 404 {object} docs.ErrorResponse "Entry not found"
+// @Security BearerAuth
+// @Router /admin/outbox/entries/{entryID} [get]
+
+// Redrive re-queues a dead-lettered event
+// @Summary Redrive dead-lettered event
+// @Description Returns a dead-lettered event to the pending queue with a fresh attempt budget
+// @Tags admin
+// @Produce json
+// @Param entryID path string true "Outbox entry ID"
+// @Success 200 {object} ports.OutboxEntry "Requeued entry"
+// @Failure 400 {object} docs.ErrorResponse "Invalid entry ID"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 403 {object} docs.ErrorResponse "Admin role required"
+// @Failure 404 {object} docs.ErrorResponse "Entry not found"
+// @Failure 409 {object} docs.ErrorResponse "Entry is not dead-lettered"
+// @Security BearerAuth
+// @Router /admin/outbox/entries/{entryID}/redrive [post]
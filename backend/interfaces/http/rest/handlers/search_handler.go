@@ -28,7 +28,9 @@ func NewSearchHandler(mediator mediator.IMediator, logger *zap.Logger, errorHand
 	}
 }
 
-// Search handles GET /search — hybrid BM25 + semantic search with RRF fusion
+// Search handles GET /search — hybrid BM25 + semantic search with RRF fusion.
+// The q parameter accepts field filters (tag:, status:, created:, updated:,
+// community:, graph:), quoted phrases and '-' exclusions.
 func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
@@ -56,12 +58,20 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := searchQuery.Validate(); err != nil {
+		if queryErr := queries.NewSearchQueryError(err); errors.IsValidation(queryErr) {
+			h.errorHandler.Handle(w, r, queryErr)
+			return
+		}
 		h.errorHandler.Handle(w, r, errors.NewValidationError(err.Error()))
 		return
 	}
 
 	result, err := h.mediator.Query(r.Context(), searchQuery)
 	if err != nil {
+		if errors.IsValidation(err) {
+			h.errorHandler.Handle(w, r, err)
+			return
+		}
 		h.logger.Error("Search failed",
 			zap.String("query", query),
 			zap.String("userID", userCtx.UserID),
@@ -81,6 +91,7 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 		"query":    searchResult.Query,
 		"results":  searchResult.Results,
 		"total":    searchResult.Total,
+		"facets":   searchResult.Facets,
 		"offset":   offset,
 		"limit":    limit,
 		"has_more": offset+limit < searchResult.Total,
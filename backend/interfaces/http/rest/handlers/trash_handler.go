@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"backend/application/services"
+	"backend/pkg/auth"
+	"backend/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TrashHandler handles the trash bin endpoints
+type TrashHandler struct {
+	trashService *services.TrashService
+	logger       *zap.Logger
+	errorHandler *errors.ErrorHandler
+}
+
+// TrashItemResponse describes a trashed node
+type TrashItemResponse struct {
+	NodeID    string     `json:"node_id"`
+	GraphID   string     `json:"graph_id"`
+	Title     string     `json:"title"`
+	EdgeCount int        `json:"edge_count"`
+	TrashedAt time.Time  `json:"trashed_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+}
+
+// RestoreResponse describes a node restored from the trash bin
+type RestoreResponse struct {
+	NodeID        string `json:"node_id"`
+	GraphID       string `json:"graph_id"`
+	Title         string `json:"title"`
+	RestoredEdges int    `json:"restored_edges"`
+	SkippedEdges  int    `json:"skipped_edges"`
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService *services.TrashService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// ListTrash handles GET /trash
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	trashed, err := h.trashService.List(r.Context(), userCtx.UserID)
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewInternalError("Failed to list trash").WithCause(err))
+		return
+	}
+
+	items := make([]TrashItemResponse, 0, len(trashed))
+	for _, entry := range trashed {
+		items = append(items, TrashItemResponse{
+			NodeID:    entry.NodeID,
+			GraphID:   entry.GraphID,
+			Title:     entry.Title,
+			EdgeCount: len(entry.Edges),
+			TrashedAt: entry.TrashedAt,
+			PurgeAt:   entry.PurgeAt,
+		})
+	}
+	h.respond(w, http.StatusOK, map[string]interface{}{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+// Restore handles POST /trash/{nodeID}/restore
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := h.nodeID(w, r)
+	if !ok {
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	result, err := h.trashService.Restore(r.Context(), userCtx.UserID, nodeID)
+	if err != nil {
+		h.logger.Error("Failed to restore node",
+			zap.String("nodeID", nodeID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	h.respond(w, http.StatusOK, RestoreResponse{
+		NodeID:        result.Node.ID().String(),
+		GraphID:       result.Node.GraphID(),
+		Title:         result.Node.Content().Title(),
+		RestoredEdges: result.RestoredEdges,
+		SkippedEdges:  result.SkippedEdges,
+	})
+}
+
+// Purge handles DELETE /trash/{nodeID}
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := h.nodeID(w, r)
+	if !ok {
+		return
+	}
+	userCtx, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		h.errorHandler.Handle(w, r, errors.NewUnauthorizedError("Unauthorized"))
+		return
+	}
+
+	if err := h.trashService.Purge(r.Context(), userCtx.UserID, nodeID); err != nil {
+		h.logger.Error("Failed to purge node",
+			zap.String("nodeID", nodeID),
+			zap.String("userID", userCtx.UserID),
+			zap.Error(err),
+		)
+		h.errorHandler.Handle(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TrashHandler) nodeID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	nodeID := chi.URLParam(r, "nodeID")
+	if _, err := uuid.Parse(nodeID); err != nil {
+		h.errorHandler.Handle(w, r, errors.NewValidationError("Invalid node ID format"))
+		return "", false
+	}
+	return nodeID, true
+}
+
+func (h *TrashHandler) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
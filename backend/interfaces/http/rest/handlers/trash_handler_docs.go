@@ -0,0 +1,42 @@
+package handlers
+
+// This file contains OpenAPI/Swagger documentation for TrashHandler endpoints
+
+// ListTrash lists trashed nodes
+// @Summary List trashed nodes
+// @Description Lists the caller's deleted nodes that can still be restored, most recently deleted first. purge_at is omitted when trashed nodes are kept until purged by hand.
+// @Tags trash
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Trashed nodes and their count"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /trash [get]
+
+// Restore restores a trashed node
+// @Summary Restore a trashed node
+// @Description Brings a deleted node back into its graph and reconnects its edges. Edges to nodes that are gone or still in the trash are skipped.
+// @Tags trash
+// @Produce json
+// @Param nodeID path string true "Node ID"
+// @Success 200 {object} handlers.RestoreResponse "Restored node"
+// @Failure 400 {object} docs.ErrorResponse "Invalid node ID"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Node not in trash"
+// @Failure 409 {object} docs.ErrorResponse "The node's graph no longer exists"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /trash/{nodeID}/restore [post]
+
+// Purge permanently deletes a trashed node
+// @Summary Purge a trashed node
+// @Description Permanently deletes a node from the trash together with its event history
+// @Tags trash
+// @Param nodeID path string true "Node ID"
+// @Success 204 "Node purged"
+// @Failure 400 {object} docs.ErrorResponse "Invalid node ID"
+// @Failure 401 {object} docs.ErrorResponse "Unauthorized"
+// @Failure 404 {object} docs.ErrorResponse "Node not in trash"
+// @Failure 500 {object} docs.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /trash/{nodeID} [delete]
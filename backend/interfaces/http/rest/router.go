@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"backend/application/mediator"
+	"backend/application/ports"
 	"backend/application/services"
 	"backend/interfaces/http/rest/handlers"
 	"backend/interfaces/http/rest/middleware"
@@ -23,6 +24,12 @@ type Router struct {
 	authMiddleware   func(http.Handler) http.Handler
 	communityService *services.CommunityDetectionService
 	analysisService  *services.AnalysisService
+	outbox           ports.Outbox
+	trashService     *services.TrashService
+	versionService   *services.GraphVersionService
+	reembedService   *services.ReembedService
+	askService       *services.AskService
+	linkService      *services.LinkPredictionService
 }
 
 // NewRouter creates a new router instance
@@ -54,6 +61,36 @@ func (rt *Router) SetAnalysisService(svc *services.AnalysisService) {
 	rt.analysisService = svc
 }
 
+// SetOutbox sets the optional event outbox exposed through the admin endpoints.
+func (rt *Router) SetOutbox(outbox ports.Outbox) {
+	rt.outbox = outbox
+}
+
+// SetTrashService sets the optional trash bin service.
+func (rt *Router) SetTrashService(svc *services.TrashService) {
+	rt.trashService = svc
+}
+
+// SetReembedService sets the optional re-embedding service.
+func (rt *Router) SetReembedService(svc *services.ReembedService) {
+	rt.reembedService = svc
+}
+
+// SetAskService sets the optional question answering service.
+func (rt *Router) SetAskService(svc *services.AskService) {
+	rt.askService = svc
+}
+
+// SetLinkPredictionService sets the optional link suggestion service.
+func (rt *Router) SetLinkPredictionService(svc *services.LinkPredictionService) {
+	rt.linkService = svc
+}
+
+// SetGraphVersionService sets the optional graph version service.
+func (rt *Router) SetGraphVersionService(svc *services.GraphVersionService) {
+	rt.versionService = svc
+}
+
 // Setup configures all routes and middleware
 func (rt *Router) Setup() http.Handler {
 	// 1. Initialize Handlers ONCE at startup (Optimization)
@@ -78,8 +115,8 @@ func (rt *Router) Setup() http.Handler {
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "https://*.brain2.com"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"X-Request-ID", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -104,6 +141,8 @@ func (rt *Router) Setup() http.Handler {
 			r.Get("/", nodeHandler.ListNodes)
 			r.Post("/bulk-delete", nodeHandler.BulkDeleteNodes)
 
+			r.Get("/{nodeID}/related", nodeHandler.GetRelatedNodes)
+
 			r.Get("/{nodeID}/categories", categoryHandler.GetNodeCategories)
 			r.Post("/{nodeID}/categories", categoryHandler.CategorizeNode)
 
@@ -119,7 +158,27 @@ func (rt *Router) Setup() http.Handler {
 		r.Route("/graphs", func(r chi.Router) {
 			r.Get("/{graphID}", graphHandler.GetGraph)
 			r.Get("/{graphID}/stats", graphHandler.GetGraphStats)
+			r.Get("/{graphID}/analytics", graphHandler.GetGraphAnalytics)
+			r.Get("/{graphID}/paths", graphHandler.FindPaths)
+			r.Get("/{graphID}/report", graphHandler.GetGraphReport)
 			r.Get("/", graphHandler.ListGraphs)
+			r.Post("/", graphHandler.CreateGraph)
+			r.Put("/{graphID}", graphHandler.UpdateGraph)
+			r.Delete("/{graphID}", graphHandler.DeleteGraph)
+			r.Post("/{graphID}/nodes/move", graphHandler.MoveNodes)
+			r.Post("/{graphID}/nodes/copy", graphHandler.CopyNodes)
+
+			// Version endpoints (snapshots, diff and rollback)
+			if rt.versionService != nil {
+				versionHandler := handlers.NewGraphVersionHandler(rt.versionService, rt.mediator, rt.logger, rt.errorHandler)
+				r.Route("/{graphID}/versions", func(r chi.Router) {
+					r.Get("/", versionHandler.ListVersions)
+					r.Post("/", versionHandler.CreateVersion)
+					r.Get("/diff", versionHandler.DiffVersions)
+					r.Get("/{version}", versionHandler.GetVersion)
+					r.Post("/{version}/rollback", versionHandler.Rollback)
+				})
+			}
 		})
 
 		// Edge endpoints
@@ -142,10 +201,47 @@ func (rt *Router) Setup() http.Handler {
 		if rt.communityService != nil {
 			communityHandler := handlers.NewCommunityHandler(rt.communityService, rt.logger, rt.errorHandler)
 			r.Route("/communities", func(r chi.Router) {
+				r.Get("/", communityHandler.List)
+				r.Get("/tree", communityHandler.Tree)
 				r.Post("/recompute", communityHandler.Recompute)
 			})
 		}
 
+		// Trash bin endpoints
+		if rt.trashService != nil {
+			trashHandler := handlers.NewTrashHandler(rt.trashService, rt.logger, rt.errorHandler)
+			r.Route("/trash", func(r chi.Router) {
+				r.Get("/", trashHandler.ListTrash)
+				r.Post("/{nodeID}/restore", trashHandler.Restore)
+				r.Delete("/{nodeID}", trashHandler.Purge)
+			})
+		}
+
+		// Link suggestion endpoints
+		if rt.linkService != nil {
+			linkHandler := handlers.NewLinkSuggestionHandler(rt.linkService, rt.logger, rt.errorHandler)
+			r.Route("/link-suggestions", func(r chi.Router) {
+				r.Get("/", linkHandler.List)
+				r.Post("/refresh", linkHandler.Refresh)
+				r.Post("/{suggestionID}/accept", linkHandler.Accept)
+				r.Post("/{suggestionID}/reject", linkHandler.Reject)
+			})
+		}
+
+		// Embedding endpoints
+		if rt.reembedService != nil {
+			embeddingHandler := handlers.NewEmbeddingHandler(rt.reembedService, rt.logger, rt.errorHandler)
+			r.Route("/embeddings", func(r chi.Router) {
+				r.Post("/reembed", embeddingHandler.Reembed)
+			})
+		}
+
+		// Question answering endpoint
+		if rt.askService != nil {
+			askHandler := handlers.NewAskHandler(rt.askService, rt.logger, rt.errorHandler)
+			r.Post("/ask", askHandler.Ask)
+		}
+
 		// Graph data endpoint for visualization
 		r.Get("/graph-data", graphHandler.GetGraphData)
 
@@ -153,6 +249,18 @@ func (rt *Router) Setup() http.Handler {
 		r.Route("/operations", func(r chi.Router) {
 			r.Get("/{operationID}", operationHandler.GetOperationStatus)
 		})
+
+		// Outbox administration
+		if rt.outbox != nil {
+			outboxHandler := handlers.NewOutboxHandler(rt.outbox, rt.logger, rt.errorHandler)
+			r.Route("/admin/outbox", func(r chi.Router) {
+				r.Use(middleware.RequireRole("admin"))
+				r.Get("/stats", outboxHandler.GetStats)
+				r.Get("/dead-letters", outboxHandler.ListDeadLetters)
+				r.Get("/entries/{entryID}", outboxHandler.GetEntry)
+				r.Post("/entries/{entryID}/redrive", outboxHandler.Redrive)
+			})
+		}
 	})
 
 	return router
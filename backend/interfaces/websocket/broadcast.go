@@ -23,6 +23,8 @@ const (
 	EventNodeCreated   EventType = "NODE_CREATED"
 	EventNodeUpdated   EventType = "NODE_UPDATED"
 	EventNodeDeleted   EventType = "NODE_DELETED"
+	EventNodeTrashed   EventType = "NODE_TRASHED"
+	EventNodeRestored  EventType = "NODE_RESTORED"
 	EventEdgeCreated   EventType = "EDGE_CREATED"
 	EventEdgeDeleted   EventType = "EDGE_DELETED"
 	EventGraphUpdated  EventType = "GRAPH_UPDATED"
@@ -85,6 +87,32 @@ func (b *Broadcaster) BroadcastNodeDeleted(event events.NodeDeletedEvent) {
 	b.broadcastToUser(event.UserID, EventNodeDeleted, data)
 }
 
+// BroadcastNodeTrashed broadcasts a node moving into the trash bin
+func (b *Broadcaster) BroadcastNodeTrashed(event events.NodeTrashedEvent) {
+	data := map[string]interface{}{
+		"nodeId":    event.NodeID,
+		"graphId":   event.GraphID,
+		"title":     event.Title,
+		"edgeCount": event.EdgeCount,
+		"trashedAt": event.Timestamp.Format(time.RFC3339),
+	}
+
+	b.broadcastToUser(event.UserID, EventNodeTrashed, data)
+}
+
+// BroadcastNodeRestored broadcasts a node restored from the trash bin
+func (b *Broadcaster) BroadcastNodeRestored(event events.NodeRestoredEvent) {
+	data := map[string]interface{}{
+		"nodeId":        event.NodeID,
+		"graphId":       event.GraphID,
+		"title":         event.Title,
+		"restoredEdges": event.RestoredEdges,
+		"restoredAt":    event.Timestamp.Format(time.RFC3339),
+	}
+
+	b.broadcastToUser(event.UserID, EventNodeRestored, data)
+}
+
 // BroadcastEdgeCreated broadcasts an edge creation event
 func (b *Broadcaster) BroadcastEdgeCreated(event events.EdgeCreatedEvent) {
 	data := map[string]interface{}{
@@ -145,12 +173,19 @@ func (b *Broadcaster) BroadcastDomainEvent(event events.DomainEvent) {
 		b.BroadcastNodeUpdated(e)
 	case events.NodeDeletedEvent:
 		b.BroadcastNodeDeleted(e)
+	case events.NodeTrashedEvent:
+		b.BroadcastNodeTrashed(e)
+	case events.NodeRestoredEvent:
+		b.BroadcastNodeRestored(e)
 	case events.EdgeCreatedEvent:
 		b.BroadcastEdgeCreated(e)
 	case events.EdgeDeletedEvent:
 		b.BroadcastEdgeDeleted(e)
 	case events.GraphUpdatedEvent:
 		b.BroadcastGraphUpdated(e)
+	case events.GraphRolledBackEvent:
+		// Clients reload a rolled back graph like any other graph update
+		b.BroadcastGraphUpdated(events.NewGraphUpdatedEvent(e.GraphID, e.UserID, e.NodeCount, e.EdgeCount))
 	case events.GraphDeletedEvent:
 		b.BroadcastGraphDeleted(e)
 	default:
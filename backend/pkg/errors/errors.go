@@ -122,6 +122,32 @@ func NewConflictError(message string) *AppError {
 	}
 }
 
+// CodeVersionConflict is the code of conflicts caused by writing a stale
+// version of a resource
+const CodeVersionConflict = "VERSION_CONFLICT"
+
+// NewVersionConflictError creates a conflict error for a write that expected
+// a different version of the resource than the one stored. A currentVersion
+// of 0 means the stored version is unknown.
+func NewVersionConflictError(resource string, expectedVersion, currentVersion int) *AppError {
+	details := map[string]interface{}{
+		"expected_version": expectedVersion,
+	}
+	message := fmt.Sprintf("%s was modified concurrently: expected version %d", resource, expectedVersion)
+	if currentVersion > 0 {
+		details["current_version"] = currentVersion
+		message = fmt.Sprintf("%s, found version %d", message, currentVersion)
+	}
+	return &AppError{
+		Type:       ErrorTypeConflict,
+		Message:    message,
+		Code:       CodeVersionConflict,
+		Details:    details,
+		HTTPStatus: http.StatusConflict,
+		StackTrace: captureStackTrace(),
+	}
+}
+
 // NewUnauthorizedError creates an unauthorized error
 func NewUnauthorizedError(message string) *AppError {
 	if message == "" {
@@ -227,6 +253,22 @@ func IsConflict(err error) bool {
 	return IsType(err, ErrorTypeConflict)
 }
 
+// IsVersionConflict checks if an error is a conflict caused by a stale version
+func IsVersionConflict(err error) bool {
+	appErr := GetAppError(err)
+	return appErr != nil && appErr.Type == ErrorTypeConflict && appErr.Code == CodeVersionConflict
+}
+
+// CurrentVersion returns the stored version reported by a version conflict,
+// if it is known
+func CurrentVersion(err error) (int, bool) {
+	if !IsVersionConflict(err) {
+		return 0, false
+	}
+	current, ok := GetAppError(err).Details["current_version"].(int)
+	return current, ok
+}
+
 // IsInternal checks if an error is an internal error
 func IsInternal(err error) bool {
 	return IsType(err, ErrorTypeInternal)
@@ -177,3 +177,75 @@ func (m *Metrics) RecordBusinessMetric(ctx context.Context, metricName string, v
 
 	m.client.PutMetricData(ctx, input)
 }
+
+// RecordOutboxState records the outbox depth and how long the oldest pending
+// event has been waiting
+func (m *Metrics) RecordOutboxState(ctx context.Context, pending, deadLettered int, lag time.Duration) {
+	if m == nil || m.client == nil {
+		return
+	}
+
+	now := time.Now()
+	metricData := []types.MetricDatum{
+		{
+			MetricName: aws.String("OutboxDepth"),
+			Value:      aws.Float64(float64(pending)),
+			Unit:       types.StandardUnitCount,
+			Timestamp:  aws.Time(now),
+		},
+		{
+			MetricName: aws.String("OutboxDeadLettered"),
+			Value:      aws.Float64(float64(deadLettered)),
+			Unit:       types.StandardUnitCount,
+			Timestamp:  aws.Time(now),
+		},
+		{
+			MetricName: aws.String("OutboxLag"),
+			Value:      aws.Float64(lag.Seconds()),
+			Unit:       types.StandardUnitSeconds,
+			Timestamp:  aws.Time(now),
+		},
+	}
+
+	input := &cloudwatch.PutMetricDataInput{
+		Namespace:  aws.String(m.namespace),
+		MetricData: metricData,
+	}
+
+	m.client.PutMetricData(ctx, input)
+}
+
+// RecordOutboxDeliveries records the outcomes of one outbox processing pass
+func (m *Metrics) RecordOutboxDeliveries(ctx context.Context, published, retried, deadLettered int) {
+	if m == nil || m.client == nil {
+		return
+	}
+
+	now := time.Now()
+	var metricData []types.MetricDatum
+	for outcome, count := range map[string]int{
+		"published":     published,
+		"retried":       retried,
+		"dead_lettered": deadLettered,
+	} {
+		metricData = append(metricData, types.MetricDatum{
+			MetricName: aws.String("OutboxDeliveries"),
+			Dimensions: []types.Dimension{
+				{
+					Name:  aws.String("Outcome"),
+					Value: aws.String(outcome),
+				},
+			},
+			Value:     aws.Float64(float64(count)),
+			Unit:      types.StandardUnitCount,
+			Timestamp: aws.Time(now),
+		})
+	}
+
+	input := &cloudwatch.PutMetricDataInput{
+		Namespace:  aws.String(m.namespace),
+		MetricData: metricData,
+	}
+
+	m.client.PutMetricData(ctx, input)
+}
@@ -20,8 +20,12 @@ export ENABLE_METRICS=false
 export ENABLE_TRACING=false
 export IS_LAMBDA=false
 
+# Set PERSISTENCE_BACKEND=file to run without AWS (data kept in PERSISTENCE_FILE_PATH)
+export PERSISTENCE_BACKEND=${PERSISTENCE_BACKEND:-dynamodb}
+export PERSISTENCE_FILE_PATH=${PERSISTENCE_FILE_PATH:-./data/brain2.db}
+
 # AWS credentials should be set via AWS CLI or environment
-if [ -z "$AWS_ACCESS_KEY_ID" ]; then
+if [ "$PERSISTENCE_BACKEND" = "dynamodb" ] && [ -z "$AWS_ACCESS_KEY_ID" ]; then
     echo "⚠️  Warning: AWS credentials not set. DynamoDB operations will fail."
     echo "   Run 'aws configure' or set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY"
 fi
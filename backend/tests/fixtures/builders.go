@@ -1,6 +1,7 @@
 package fixtures
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,21 +9,23 @@ import (
 	"backend/domain/core/entities"
 	"backend/domain/core/valueobjects"
 	"backend/domain/events"
+	"backend/infrastructure/persistence/memory"
 	"github.com/google/uuid"
 )
 
 // NodeBuilder helps create test nodes with default values
 type NodeBuilder struct {
-	id       valueobjects.NodeID
-	userID   string
-	graphID  string
-	title    string
-	content  string
-	format   valueobjects.ContentFormat
-	x, y, z  float64
-	status   string
-	tags     []string
-	metadata map[string]interface{}
+	id        valueobjects.NodeID
+	userID    string
+	graphID   string
+	title     string
+	content   string
+	format    valueobjects.ContentFormat
+	x, y, z   float64
+	status    string
+	tags      []string
+	embedding []float64
+	metadata  map[string]interface{}
 }
 
 func NewNodeBuilder() *NodeBuilder {
@@ -41,6 +44,16 @@ func NewNodeBuilder() *NodeBuilder {
 	}
 }
 
+// NewNoteBuilder starts an untagged node of userID titled title, with a body
+// derived from the title
+func NewNoteBuilder(userID, title string) *NodeBuilder {
+	return NewNodeBuilder().
+		WithUserID(userID).
+		WithTitle(title).
+		WithContent("body of " + title).
+		WithTags()
+}
+
 func (b *NodeBuilder) WithID(id string) *NodeBuilder {
 	b.id, _ = valueobjects.NewNodeIDFromString(id)
 	return b
@@ -81,6 +94,12 @@ func (b *NodeBuilder) WithTags(tags ...string) *NodeBuilder {
 	return b
 }
 
+// WithEmbedding gives the node an embedding; without values it gets none
+func (b *NodeBuilder) WithEmbedding(vector ...float64) *NodeBuilder {
+	b.embedding = vector
+	return b
+}
+
 func (b *NodeBuilder) Build() (*entities.Node, error) {
 	content, err := valueobjects.NewNodeContent(b.title, b.content, b.format)
 	if err != nil {
@@ -101,6 +120,13 @@ func (b *NodeBuilder) Build() (*entities.Node, error) {
 	for _, tag := range b.tags {
 		node.AddTag(tag)
 	}
+	if len(b.embedding) > 0 {
+		embedding, err := valueobjects.NewEmbedding(b.embedding)
+		if err != nil {
+			return nil, err
+		}
+		node.SetEmbedding(embedding)
+	}
 
 	return node, nil
 }
@@ -471,4 +497,153 @@ func CreateComplexTestGraph(userID string) (*aggregates.Graph, []*entities.Node,
 		MustBuild()
 
 	return graph, allNodes, edges
-}
\ No newline at end of file
+}
+
+// MemoryBackend holds the in-memory repositories over one database, for tests
+// that run services against real persistence
+type MemoryBackend struct {
+	DB          *memory.InMemoryDatabase
+	Nodes       *memory.InMemoryNodeRepository
+	Edges       *memory.InMemoryEdgeRepository
+	Graphs      *memory.InMemoryGraphRepository
+	Communities *memory.InMemoryCommunityRepository
+}
+
+// NewMemoryBackend creates the repositories over a fresh in-memory database
+func NewMemoryBackend() *MemoryBackend {
+	db := memory.NewInMemoryDatabase()
+	nodes := memory.NewInMemoryNodeRepository(db)
+	edges := memory.NewInMemoryEdgeRepository(db)
+	return &MemoryBackend{
+		DB:          db,
+		Nodes:       nodes,
+		Edges:       edges,
+		Graphs:      memory.NewInMemoryGraphRepository(db, nodes, edges),
+		Communities: memory.NewInMemoryCommunityRepository(db),
+	}
+}
+
+// MustCreateGraph stores a new empty graph
+func (b *MemoryBackend) MustCreateGraph(userID, name string) *aggregates.Graph {
+	graph, err := aggregates.NewGraph(userID, name)
+	if err != nil {
+		panic(err)
+	}
+	if err := b.Graphs.Save(context.Background(), graph); err != nil {
+		panic(err)
+	}
+	return graph
+}
+
+// MustAddNode builds a node into graph and stores it
+func (b *MemoryBackend) MustAddNode(graph *aggregates.Graph, builder *NodeBuilder) *entities.Node {
+	node := builder.WithGraphID(graph.ID().String()).MustBuild()
+	if err := graph.AddNode(node); err != nil {
+		panic(err)
+	}
+	return b.mustSave(node)
+}
+
+// MustConnect connects two nodes of graph and stores the graph with the edge
+func (b *MemoryBackend) MustConnect(graph *aggregates.Graph, source, target *entities.Node, edgeType entities.EdgeType) {
+	if _, err := graph.ConnectNodes(source.ID(), target.ID(), edgeType); err != nil {
+		panic(err)
+	}
+	if err := b.Graphs.Save(context.Background(), graph); err != nil {
+		panic(err)
+	}
+}
+
+// MustLink stores an edge between two nodes straight in the edge repository,
+// leaving the graph aggregate untouched
+func (b *MemoryBackend) MustLink(graphID string, source, target *entities.Node, edgeType entities.EdgeType, weight float64) *aggregates.Edge {
+	edge := NewEdgeBuilder().
+		WithID(source.ID().String() + "-" + target.ID().String()).
+		WithSource(source.ID()).
+		WithTarget(target.ID()).
+		WithType(edgeType).
+		WithWeight(weight).
+		Build()
+	if err := b.Edges.Save(context.Background(), graphID, edge); err != nil {
+		panic(err)
+	}
+	return edge
+}
+
+// MustUpdateContent loads the stored node, gives it new content and stores it
+func (b *MemoryBackend) MustUpdateContent(node *entities.Node, title, body string) *entities.Node {
+	loaded, err := b.Nodes.GetByID(context.Background(), node.ID())
+	if err != nil {
+		panic(err)
+	}
+	content, err := valueobjects.NewNodeContent(title, body, valueobjects.FormatMarkdown)
+	if err != nil {
+		panic(err)
+	}
+	if err := loaded.UpdateContent(content); err != nil {
+		panic(err)
+	}
+	return b.mustSave(loaded)
+}
+
+func (b *MemoryBackend) mustSave(node *entities.Node) *entities.Node {
+	if err := b.Nodes.Save(context.Background(), node); err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Notebook is a graph stored in a memory backend, with its notes filed by
+// title so tests can refer to them by name
+type Notebook struct {
+	*MemoryBackend
+	Graph *aggregates.Graph
+	Notes map[string]*entities.Node
+}
+
+// NewNotebook creates an empty graph of userID over a fresh memory backend
+func NewNotebook(userID, name string) *Notebook {
+	return NewMemoryBackend().MustCreateNotebook(userID, name)
+}
+
+// MustCreateNotebook creates another empty graph of userID in the backend
+func (b *MemoryBackend) MustCreateNotebook(userID, name string) *Notebook {
+	return &Notebook{
+		MemoryBackend: b,
+		Graph:         b.MustCreateGraph(userID, name),
+		Notes:         make(map[string]*entities.Node),
+	}
+}
+
+// MustAdd builds a note into the graph, stores it and files it under its title
+func (n *Notebook) MustAdd(builder *NodeBuilder) *entities.Node {
+	node := n.MustAddNode(n.Graph, builder)
+	n.Notes[node.Content().Title()] = node
+	return node
+}
+
+// MustAddNotes adds untagged notes of the graph's owner with the given titles
+func (n *Notebook) MustAddNotes(titles ...string) {
+	for _, title := range titles {
+		n.MustAdd(NewNoteBuilder(n.Graph.UserID(), title))
+	}
+}
+
+// MustLoad loads the stored state of the note titled title
+func (n *Notebook) MustLoad(title string) *entities.Node {
+	node, err := n.Nodes.GetByID(context.Background(), n.Notes[title].ID())
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// MustConnect connects two notes through the graph aggregate
+func (n *Notebook) MustConnect(source, target string, edgeType entities.EdgeType) {
+	n.MemoryBackend.MustConnect(n.Graph, n.Notes[source], n.Notes[target], edgeType)
+}
+
+// MustLink stores an edge between two notes straight in the edge repository
+func (n *Notebook) MustLink(source, target string, edgeType entities.EdgeType, weight float64) *aggregates.Edge {
+	return n.MemoryBackend.MustLink(n.Graph.ID().String(), n.Notes[source], n.Notes[target], edgeType, weight)
+}
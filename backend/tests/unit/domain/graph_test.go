@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"testing"
+
+	"backend/domain/config"
+	"backend/domain/core/aggregates"
+	"backend/domain/events"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_NewGraphWithID(t *testing.T) {
+	graph, err := aggregates.NewGraphWithID("graph-1", "user-1", "  Research  ", "Papers", config.DefaultDomainConfig())
+	require.NoError(t, err)
+	assert.Equal(t, aggregates.GraphID("graph-1"), graph.ID())
+	assert.Equal(t, "Research", graph.Name())
+	assert.Equal(t, "Papers", graph.Description())
+	assert.False(t, graph.IsDefault())
+
+	_, err = aggregates.NewGraphWithID("", "user-1", " ", "", nil)
+	assert.True(t, pkgerrors.IsValidation(err), "a name is required")
+
+	_, err = aggregates.NewGraphWithID("", "user-1", "Default Graph", "", nil)
+	assert.True(t, pkgerrors.IsValidation(err), "the default graph's name is reserved")
+}
+
+func TestGraph_Rename(t *testing.T) {
+	graph, err := aggregates.NewGraphWithID("graph-1", "user-1", "Research", "", nil)
+	require.NoError(t, err)
+	graph.MarkEventsAsCommitted()
+	version := graph.Version()
+
+	require.NoError(t, graph.Rename("Research", graph.Description()))
+	assert.Empty(t, graph.GetUncommittedEvents(), "an unchanged rename is a no-op")
+
+	require.NoError(t, graph.Rename("Reading list", "Books to read"))
+	assert.Equal(t, "Reading list", graph.Name())
+	assert.Equal(t, "Books to read", graph.Description())
+	assert.Greater(t, graph.Version(), version)
+	require.Len(t, graph.GetUncommittedEvents(), 1)
+	renamed, ok := graph.GetUncommittedEvents()[0].(events.GraphRenamed)
+	require.True(t, ok)
+	assert.Equal(t, "Reading list", renamed.Name)
+
+	assert.True(t, pkgerrors.IsValidation(graph.Rename("Default Graph", "")))
+
+	defaultGraph, err := aggregates.NewGraph("user-1", "")
+	require.NoError(t, err)
+	require.True(t, defaultGraph.IsDefault())
+	assert.True(t, pkgerrors.IsValidation(defaultGraph.Rename("Mine", "")), "the default graph keeps its name")
+}
+
+func TestGraph_RenameIsReplayed(t *testing.T) {
+	graph, err := aggregates.NewGraphWithID("graph-1", "user-1", "Research", "Papers", nil)
+	require.NoError(t, err)
+	require.NoError(t, graph.Rename("Reading list", "Books to read"))
+
+	replayed, err := aggregates.RehydrateGraph(nil, graph.GetUncommittedEvents(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Reading list", replayed.Name())
+	assert.Equal(t, "Books to read", replayed.Description())
+	assert.Equal(t, graph.Version(), replayed.Version())
+}
@@ -0,0 +1,95 @@
+package sagas_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/application/sagas"
+	"backend/application/services"
+	"backend/infrastructure/config"
+	"backend/infrastructure/messaging"
+	"backend/infrastructure/persistence/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newMemorySaga wires the real CreateNodeSaga over the in-memory backend
+func newMemorySaga(t *testing.T, lazy bool) (*sagas.CreateNodeSaga, *memory.InMemoryGraphRepository, *memory.InMemoryNodeRepository, *memory.InMemoryEventStore) {
+	t.Helper()
+	logger := zap.NewNop()
+
+	db := memory.NewInMemoryDatabase()
+	nodeRepo := memory.NewInMemoryNodeRepository(db)
+	edgeRepo := memory.NewInMemoryEdgeRepository(db)
+	graphRepo := memory.NewInMemoryGraphRepository(db, nodeRepo, edgeRepo)
+	eventStore := memory.NewInMemoryEventStore(db)
+	uow := memory.NewInMemoryUnitOfWork(db, nodeRepo, edgeRepo, graphRepo, eventStore)
+
+	cfg := &config.Config{
+		EnableLazyLoading: lazy,
+		EdgeCreation: config.EdgeCreationConfig{
+			SyncEdgeLimit:       20,
+			SimilarityThreshold: 0.1,
+			MaxEdgesPerNode:     100,
+		},
+	}
+
+	saga := sagas.NewCreateNodeSaga(
+		uow,
+		nodeRepo,
+		graphRepo,
+		edgeRepo,
+		services.NewEdgeService(nodeRepo, graphRepo, edgeRepo, &cfg.EdgeCreation, logger),
+		services.NewGraphLazyService(nodeRepo, edgeRepo, cfg, logger),
+		messaging.NewLocalEventBus(logger),
+		memory.NewInMemoryDistributedLock(),
+		memory.NewInMemoryOperationStore(time.Hour),
+		&cfg.EdgeCreation,
+		cfg,
+		logger,
+	)
+	return saga, graphRepo, nodeRepo, eventStore
+}
+
+func TestCreateNodeSaga_InMemoryPipeline(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		lazy := lazy
+		name := "eager"
+		if lazy {
+			name = "lazy"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			saga, graphRepo, nodeRepo, eventStore := newMemorySaga(t, lazy)
+
+			for _, title := range []string{"Go concurrency patterns", "Go concurrency with channels"} {
+				err := saga.Execute(ctx, &sagas.CreateNodeSagaData{
+					UserID:    "user-1",
+					Title:     title,
+					Content:   "notes about go concurrency",
+					StartTime: time.Now(),
+				})
+				require.NoError(t, err)
+			}
+
+			graph, err := graphRepo.GetUserDefaultGraph(ctx, "user-1")
+			require.NoError(t, err)
+
+			nodes, err := nodeRepo.GetByGraphID(ctx, graph.ID().String())
+			require.NoError(t, err)
+			assert.Len(t, nodes, 2)
+
+			stats, err := graphRepo.GetGraphStatistics(ctx, graph.ID())
+			require.NoError(t, err)
+			assert.Equal(t, 2, stats.NodeCount)
+			assert.Equal(t, 1, stats.EdgeCount)
+
+			created, err := eventStore.GetEventsByType(ctx, "node.created", 10)
+			require.NoError(t, err)
+			assert.Len(t, created, 2)
+		})
+	}
+}
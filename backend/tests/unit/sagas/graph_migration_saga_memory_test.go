@@ -0,0 +1,239 @@
+package sagas_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/application/sagas"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// researchNote starts a note of user-1 tagged research
+func researchNote(title string) *fixtures.NodeBuilder {
+	return fixtures.NewNoteBuilder("user-1", title).WithTags("research")
+}
+
+func titlesIn(t *testing.T, nodes []*entities.Node) []string {
+	t.Helper()
+	titles := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		titles = append(titles, node.Content().Title())
+	}
+	return titles
+}
+
+func TestGraphMigrationSaga_CopyKeepsInternalEdges(t *testing.T) {
+	inbox := fixtures.NewNotebook("user-1", "Inbox")
+	projects := inbox.MustCreateNotebook("user-1", "Projects")
+	a := inbox.MustAdd(researchNote("Alpha"))
+	b := inbox.MustAdd(researchNote("Beta"))
+	inbox.MustAdd(researchNote("Gamma"))
+	inbox.MustConnect("Alpha", "Beta", entities.EdgeTypeReference)
+	inbox.MustConnect("Beta", "Gamma", entities.EdgeTypeReference)
+
+	ctx := context.Background()
+	result, err := sagas.NewGraphMigrationSaga(
+		inbox.Graph.ID().String(),
+		projects.Graph.ID().String(),
+		sagas.GraphMigrationOptions{UserID: "user-1", NodeIDs: []string{a.ID().String(), b.ID().String()}},
+		inbox.Nodes, inbox.Edges, inbox.Graphs,
+		zap.NewNop(),
+	).Execute(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.CopiedEdges)
+	assert.Equal(t, 1, result.SkippedEdges, "the edge to Gamma crosses the boundary")
+	require.Len(t, result.NodeMapping, 2)
+
+	copies, err := inbox.Nodes.GetByGraphID(ctx, projects.Graph.ID().String())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Alpha", "Beta"}, titlesIn(t, copies))
+	for _, node := range copies {
+		assert.NotEqual(t, a.ID(), node.ID())
+		assert.NotEqual(t, b.ID(), node.ID())
+		assert.Equal(t, []string{"research"}, node.GetTags())
+	}
+
+	edges, err := inbox.Edges.GetByGraphID(ctx, projects.Graph.ID().String())
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, result.NodeMapping[a.ID().String()], edges[0].SourceID.String())
+	assert.Equal(t, result.NodeMapping[b.ID().String()], edges[0].TargetID.String())
+	assert.Equal(t, entities.EdgeTypeReference, edges[0].Type)
+
+	// The originals are untouched
+	originals, err := inbox.Nodes.GetByGraphID(ctx, inbox.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, originals, 3)
+	sourceEdges, err := inbox.Edges.GetByGraphID(ctx, inbox.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, sourceEdges, 2)
+
+	stats, err := inbox.Graphs.GetGraphStatistics(ctx, projects.Graph.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.NodeCount)
+	assert.Equal(t, 1, stats.EdgeCount)
+}
+
+func TestGraphMigrationSaga_MoveKeepsIDs(t *testing.T) {
+	inbox := fixtures.NewNotebook("user-1", "Inbox")
+	projects := inbox.MustCreateNotebook("user-1", "Projects")
+	a := inbox.MustAdd(researchNote("Alpha"))
+	b := inbox.MustAdd(researchNote("Beta"))
+	c := inbox.MustAdd(researchNote("Gamma"))
+	inbox.MustConnect("Alpha", "Beta", entities.EdgeTypeReference)
+	inbox.MustConnect("Beta", "Gamma", entities.EdgeTypeReference)
+	sourceEdges, err := inbox.Edges.GetByGraphID(context.Background(), inbox.Graph.ID().String())
+	require.NoError(t, err)
+	edgeIDs := make(map[string]string, len(sourceEdges))
+	for _, edge := range sourceEdges {
+		edgeIDs[edge.TargetID.String()] = edge.ID
+	}
+
+	ctx := context.Background()
+	result, err := sagas.NewGraphMigrationSaga(
+		inbox.Graph.ID().String(),
+		projects.Graph.ID().String(),
+		sagas.GraphMigrationOptions{UserID: "user-1", NodeIDs: []string{a.ID().String(), b.ID().String()}, Move: true},
+		inbox.Nodes, inbox.Edges, inbox.Graphs,
+		zap.NewNop(),
+	).Execute(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, a.ID().String(), result.NodeMapping[a.ID().String()])
+	assert.Equal(t, b.ID().String(), result.NodeMapping[b.ID().String()])
+	require.Len(t, result.CrossingEdges, 1, "the edge to Gamma crosses the boundary")
+	assert.Equal(t, c.ID(), result.CrossingEdges[0].TargetID)
+
+	remaining, err := inbox.Nodes.GetByGraphID(ctx, inbox.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gamma"}, titlesIn(t, remaining))
+	sourceEdges, err = inbox.Edges.GetByGraphID(ctx, inbox.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, sourceEdges, "edges of moved nodes leave the source graph")
+
+	// The nodes and the edge between them are the same ones, now in the target
+	moved, err := inbox.Nodes.GetByGraphID(ctx, projects.Graph.ID().String())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Alpha", "Beta"}, titlesIn(t, moved))
+	for _, node := range moved {
+		assert.Contains(t, []valueobjects.NodeID{a.ID(), b.ID()}, node.ID())
+	}
+	loaded, err := inbox.Nodes.GetByID(ctx, a.ID())
+	require.NoError(t, err)
+	assert.Equal(t, projects.Graph.ID().String(), loaded.GraphID())
+	assert.Equal(t, []string{"research"}, loaded.GetTags())
+	targetEdges, err := inbox.Edges.GetByGraphID(ctx, projects.Graph.ID().String())
+	require.NoError(t, err)
+	require.Len(t, targetEdges, 1)
+	assert.Equal(t, edgeIDs[b.ID().String()], targetEdges[0].ID)
+	assert.Equal(t, a.ID(), targetEdges[0].SourceID)
+
+	stats, err := inbox.Graphs.GetGraphStatistics(ctx, inbox.Graph.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.NodeCount)
+}
+
+func TestGraphMigrationSaga_EnforcesNodeLimit(t *testing.T) {
+	inbox := fixtures.NewNotebook("user-1", "Inbox")
+	projects := inbox.MustCreateNotebook("user-1", "Projects")
+	a := inbox.MustAdd(researchNote("Alpha"))
+	b := inbox.MustAdd(researchNote("Beta"))
+	projects.MustAdd(researchNote("Existing"))
+
+	ctx := context.Background()
+	_, err := sagas.NewGraphMigrationSaga(
+		inbox.Graph.ID().String(),
+		projects.Graph.ID().String(),
+		sagas.GraphMigrationOptions{UserID: "user-1", NodeIDs: []string{a.ID().String(), b.ID().String()}, Move: true, MaxNodesPerGraph: 2},
+		inbox.Nodes, inbox.Edges, inbox.Graphs,
+		zap.NewNop(),
+	).Execute(ctx)
+	require.Error(t, err)
+	assert.True(t, pkgerrors.IsValidation(err))
+
+	// Nothing moved
+	remaining, err := inbox.Nodes.GetByGraphID(ctx, inbox.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2)
+	target, err := inbox.Nodes.GetByGraphID(ctx, projects.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, target, 1)
+}
+
+func TestGraphMigrationSaga_RejectsUnknownNodesAndForeignGraphs(t *testing.T) {
+	inbox := fixtures.NewNotebook("user-1", "Inbox")
+	projects := inbox.MustCreateNotebook("user-1", "Projects")
+	a := inbox.MustAdd(researchNote("Alpha"))
+	ctx := context.Background()
+
+	_, err := sagas.NewGraphMigrationSaga(
+		inbox.Graph.ID().String(),
+		projects.Graph.ID().String(),
+		sagas.GraphMigrationOptions{UserID: "user-1", NodeIDs: []string{a.ID().String(), valueobjects.NewNodeID().String()}},
+		inbox.Nodes, inbox.Edges, inbox.Graphs,
+		zap.NewNop(),
+	).Execute(ctx)
+	assert.True(t, pkgerrors.IsNotFound(err))
+
+	foreign := inbox.MustCreateGraph("user-2", "Theirs")
+	saga := sagas.NewGraphMigrationSaga(
+		inbox.Graph.ID().String(),
+		foreign.ID().String(),
+		sagas.GraphMigrationOptions{UserID: "user-1", NodeIDs: []string{a.ID().String()}},
+		inbox.Nodes, inbox.Edges, inbox.Graphs,
+		zap.NewNop(),
+	)
+	_, err = saga.Execute(ctx)
+	assert.True(t, pkgerrors.IsNotFound(err))
+
+	target, err := inbox.Nodes.GetByGraphID(ctx, foreign.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, target)
+}
+
+func TestCreateNodeSaga_TargetGraphAndNodeLimit(t *testing.T) {
+	ctx := context.Background()
+	saga, graphRepo, nodeRepo, _ := newMemorySaga(t, false)
+	saga.WithNodeLimit(1)
+
+	graph, err := aggregates.NewGraph("user-1", "Projects")
+	require.NoError(t, err)
+	require.NoError(t, graphRepo.Save(ctx, graph))
+
+	create := func(userID, graphID, title string) error {
+		return saga.Execute(ctx, &sagas.CreateNodeSagaData{
+			UserID:    userID,
+			GraphID:   graphID,
+			Title:     title,
+			Content:   "notes",
+			StartTime: time.Now(),
+		})
+	}
+
+	require.NoError(t, create("user-1", graph.ID().String(), "First"))
+	nodes, err := nodeRepo.GetByGraphID(ctx, graph.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First"}, titlesIn(t, nodes))
+
+	err = create("user-1", graph.ID().String(), "Second")
+	assert.True(t, pkgerrors.IsValidation(err), "the graph is at its node limit")
+
+	err = create("user-2", graph.ID().String(), "Intruder")
+	assert.True(t, pkgerrors.IsNotFound(err), "graphs of other users are not found")
+
+	// Without a graph the node still lands in the default graph
+	require.NoError(t, create("user-1", "", "Elsewhere"))
+	defaultGraph, err := graphRepo.GetUserDefaultGraph(ctx, "user-1")
+	require.NoError(t, err)
+	nodes, err = nodeRepo.GetByGraphID(ctx, defaultGraph.ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Elsewhere"}, titlesIn(t, nodes))
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/entities"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubChat answers with fixed pieces and records the requests it receives
+type stubChat struct {
+	deltas   []string
+	requests []ports.ChatRequest
+}
+
+func (c *stubChat) StreamChat(ctx context.Context, req ports.ChatRequest, onDelta func(delta string) error) error {
+	c.requests = append(c.requests, req)
+	for _, d := range c.deltas {
+		if err := onDelta(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *stubChat) Model() string { return "stub" }
+
+func sourceTitles(pack *services.AskContext) []string {
+	out := make([]string, len(pack.Sources))
+	for i, s := range pack.Sources {
+		out[i] = s.Title
+	}
+	return out
+}
+
+func TestAsk_RetrievesAndExpandsStrongEdges(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	starter := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Sourdough starter").WithContent("Feed the starter flour and water every morning."))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Weekend plan").WithContent("Mix on Friday evening, shape on Saturday."))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Tax returns").WithContent("File before April."))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Gardening").WithContent("Tomatoes need sun."))
+	nb.MustLink("Sourdough starter", "Weekend plan", entities.EdgeTypeReference, 0.9)
+	nb.MustLink("Tax returns", "Sourdough starter", entities.EdgeTypeReference, 0.2)
+	search := services.NewHybridSearchService(nil, nil, nil, nb.Nodes, nil)
+	chat := &stubChat{deltas: []string{"Feed it daily ", "[1]."}}
+
+	svc := services.NewAskService(search, nb.Nodes, nb.Edges, chat, nil, zap.NewNop())
+	pack, err := svc.Retrieve(ctx, "user-1", "How often should I feed my sourdough starter?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sourdough starter", "Weekend plan"}, sourceTitles(pack), "weak edges are not followed")
+
+	assert.Equal(t, 1, pack.Sources[0].Ref)
+	assert.Equal(t, 0, pack.Sources[0].Hop)
+	assert.Equal(t, 2, pack.Sources[1].Ref)
+	assert.Equal(t, 1, pack.Sources[1].Hop)
+	assert.Equal(t, starter.ID().String(), pack.Sources[1].ViaNodeID)
+	assert.Less(t, pack.Sources[1].Score, pack.Sources[0].Score)
+	assert.Greater(t, pack.Tokens, 0)
+
+	// Without hops only search results are packed
+	svc = services.NewAskService(search, nb.Nodes, nb.Edges, chat, &services.AskConfig{Candidates: 8, ContextTokens: 3000}, zap.NewNop())
+	pack, err = svc.Retrieve(ctx, "user-1", "feed sourdough")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sourdough starter"}, sourceTitles(pack))
+}
+
+func TestAsk_PackRespectsTokenBudget(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Sourdough starter").WithContent(strings.Repeat("Feed the starter flour and water. ", 40)))
+	search := services.NewHybridSearchService(nil, nil, nil, nb.Nodes, nil)
+
+	config := services.DefaultAskConfig()
+	config.ContextTokens = 80
+	svc := services.NewAskService(search, nb.Nodes, nb.Edges, &stubChat{}, config, zap.NewNop())
+	pack, err := svc.Retrieve(context.Background(), "user-1", "sourdough starter")
+	require.NoError(t, err)
+	require.Len(t, pack.Sources, 1)
+	assert.True(t, pack.Sources[0].Truncated)
+	assert.LessOrEqual(t, pack.Tokens, 80)
+}
+
+func TestAsk_AnswerCitesSources(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	starter := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Sourdough starter").WithContent("Feed the starter flour and water every morning."))
+	chat := &stubChat{deltas: []string{"Feed it daily ", "[1]."}}
+	search := services.NewHybridSearchService(nil, nil, nil, nb.Nodes, nil)
+	svc := services.NewAskService(search, nb.Nodes, nb.Edges, chat, nil, zap.NewNop())
+
+	pack, err := svc.Retrieve(ctx, "user-1", "feed sourdough")
+	require.NoError(t, err)
+
+	var answer strings.Builder
+	require.NoError(t, svc.Answer(ctx, pack, func(delta string) error {
+		answer.WriteString(delta)
+		return nil
+	}))
+	assert.Equal(t, "Feed it daily [1].", answer.String())
+
+	require.Len(t, chat.requests, 1)
+	messages := chat.requests[0].Messages
+	require.Len(t, messages, 2)
+	assert.Equal(t, ports.ChatRoleSystem, messages[0].Role)
+	assert.Contains(t, messages[0].Content, "[1]")
+	assert.Contains(t, messages[1].Content, "[1] Sourdough starter (node "+starter.ID().String()+")")
+	assert.Contains(t, messages[1].Content, "Feed the starter flour")
+	assert.True(t, strings.HasSuffix(messages[1].Content, "Question: feed sourdough"))
+
+	// Without sources the model is not asked
+	empty, err := svc.Retrieve(ctx, "user-1", "quantum chromodynamics")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Sources)
+	answer.Reset()
+	require.NoError(t, svc.Answer(ctx, empty, func(delta string) error {
+		answer.WriteString(delta)
+		return nil
+	}))
+	assert.NotEmpty(t, answer.String())
+	assert.Len(t, chat.requests, 1)
+}
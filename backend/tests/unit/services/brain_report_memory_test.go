@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"backend/application/queries"
+	queries_handlers "backend/application/queries/handlers"
+	"backend/application/services"
+	"backend/domain/core/entities"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func followUpKinds(report *queries.GenerateBrainReportResult) []string {
+	kinds := make([]string, 0, len(report.FollowUps))
+	for _, followUp := range report.FollowUps {
+		kinds = append(kinds, followUp.Kind)
+	}
+	return kinds
+}
+
+func TestBrainReport_SummarisesGraph(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	addTwoClusters(nb)
+	nb.MustAddNotes("Loose *end*")
+	communities := services.NewCommunityDetectionService(nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	_, err := communities.UpdateGraph(ctx, nb.Graph.ID().String(), nil)
+	require.NoError(t, err)
+	handler := queries_handlers.NewGenerateBrainReportHandler(&mapCache{items: make(map[string]interface{})},
+		nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	query := queries.GenerateBrainReportQuery{UserID: "user-1", GraphID: nb.Graph.ID().String()}
+
+	report, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "Ideas", report.GraphName)
+	assert.Equal(t, 7, report.NodeCount)
+	assert.Equal(t, 7, report.EdgeCount)
+	// The orphan makes a community of its own
+	assert.Equal(t, 3, report.CommunityCount)
+	require.Len(t, report.Communities, 3)
+	assert.Equal(t, 3, report.Communities[0].MemberCount)
+	assert.Equal(t, 1, report.Communities[2].MemberCount)
+
+	// The notes joining the two triangles are the hubs and the bridges
+	require.NotEmpty(t, report.Hubs)
+	assert.Equal(t, 3, report.Hubs[0].Degree)
+	assert.Contains(t, []string{"a3", "b1"}, report.Hubs[0].Title)
+	require.Len(t, report.Bridges, 2)
+	for _, bridge := range report.Bridges {
+		assert.Contains(t, []string{"a3", "b1"}, bridge.Title)
+		assert.Equal(t, 2, bridge.Communities)
+		assert.NotEmpty(t, bridge.Summary)
+	}
+
+	assert.Equal(t, 1, report.OrphanCount)
+	require.Len(t, report.Orphans, 1)
+	assert.Equal(t, "Loose *end*", report.Orphans[0].Title)
+
+	// Every note was written just now
+	assert.Equal(t, 7, report.Growth.NodesAdded)
+	require.Len(t, report.Growth.Weeks, 5)
+	assert.Equal(t, 7, report.Growth.Weeks[4].Nodes)
+	require.Len(t, report.NewestClusters, 3)
+	for _, cluster := range report.NewestClusters {
+		assert.Equal(t, cluster.MemberCount, cluster.RecentMembers)
+	}
+
+	kinds := followUpKinds(report)
+	assert.Contains(t, kinds, queries.FollowUpLinkOrphans)
+	assert.Contains(t, kinds, queries.FollowUpReinforceBridge)
+	assert.Contains(t, kinds, queries.FollowUpDevelopCluster)
+	assert.NotContains(t, kinds, queries.FollowUpRevisitHub)
+
+	markdown := report.Markdown()
+	assert.Contains(t, markdown, "# Brain report: Ideas")
+	assert.Contains(t, markdown, "**7 notes**, 7 links, 3 communities and 1 orphaned note.")
+	assert.Contains(t, markdown, "## Orphaned notes (1)")
+	assert.Contains(t, markdown, `- Loose \*end\*`)
+}
+
+func TestBrainReport_CachedUntilGraphChanges(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	addTwoClusters(nb)
+	nb.MustAddNotes("Loose end")
+	cache := &mapCache{items: make(map[string]interface{})}
+	handler := queries_handlers.NewGenerateBrainReportHandler(cache, nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	query := queries.GenerateBrainReportQuery{UserID: "user-1", GraphID: nb.Graph.ID().String()}
+
+	first, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	again, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+	assert.Equal(t, 1, cache.sets)
+
+	// Without detected communities the report asks for them
+	assert.Equal(t, 0, first.CommunityCount)
+	assert.Empty(t, first.Bridges)
+	assert.Contains(t, followUpKinds(first), queries.FollowUpDetectCommunities)
+
+	nb.MustLink("Loose end", "a1", entities.EdgeTypeReference, 1)
+	updated, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, updated.OrphanCount)
+	assert.Equal(t, 2, cache.sets)
+
+	// Detecting communities also invalidates the report
+	communities := services.NewCommunityDetectionService(nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	_, err = communities.UpdateGraph(ctx, nb.Graph.ID().String(), nil)
+	require.NoError(t, err)
+	detected, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Positive(t, detected.CommunityCount)
+}
+
+func TestBrainReport_RejectsOtherUsers(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	handler := queries_handlers.NewGenerateBrainReportHandler(&mapCache{items: make(map[string]interface{})},
+		nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+
+	_, err := handler.Handle(context.Background(), queries.GenerateBrainReportQuery{
+		UserID:  "user-2",
+		GraphID: nb.Graph.ID().String(),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
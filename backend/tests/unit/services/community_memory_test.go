@@ -0,0 +1,185 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"backend/application/services"
+	"backend/domain/core/entities"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// communityOf returns the ID of the community holding the node with the given title
+func communityOf(t *testing.T, nb *fixtures.Notebook, result *services.DetectionResult, title string) string {
+	t.Helper()
+	id := nb.Notes[title].ID().String()
+	for _, c := range result.Communities {
+		for _, member := range c.MemberIDs {
+			if member == id {
+				return c.ID
+			}
+		}
+	}
+	t.Fatalf("%s is in no community", title)
+	return ""
+}
+
+// addTwoClusters adds two triangles joined by a single edge
+func addTwoClusters(nb *fixtures.Notebook) {
+	nb.MustAddNotes("a1", "a2", "a3", "b1", "b2", "b3")
+	nb.MustLink("a1", "a2", entities.EdgeTypeReference, 1)
+	nb.MustLink("a2", "a3", entities.EdgeTypeReference, 1)
+	nb.MustLink("a1", "a3", entities.EdgeTypeReference, 1)
+	nb.MustLink("b1", "b2", entities.EdgeTypeReference, 1)
+	nb.MustLink("b2", "b3", entities.EdgeTypeReference, 1)
+	nb.MustLink("b1", "b3", entities.EdgeTypeReference, 1)
+	nb.MustLink("a3", "b1", entities.EdgeTypeReference, 1)
+}
+
+func TestCommunityDetection_UpdatesIncrementally(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	addTwoClusters(nb)
+	svc := services.NewCommunityDetectionService(nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	graphID := nb.Graph.ID().String()
+
+	// Without a stored partition the first update detects from scratch
+	first, err := svc.UpdateGraph(ctx, graphID, nil)
+	require.NoError(t, err)
+	assert.True(t, first.FullRecompute)
+	require.Len(t, first.Communities, 2)
+	aCommunity := communityOf(t, nb, first, "a1")
+	bCommunity := communityOf(t, nb, first, "b1")
+	assert.NotEqual(t, aCommunity, bCommunity)
+
+	nb.MustAddNotes("a4")
+	nb.MustLink("a4", "a1", entities.EdgeTypeReference, 1)
+	nb.MustLink("a4", "a2", entities.EdgeTypeReference, 1)
+
+	second, err := svc.UpdateGraph(ctx, graphID, []string{nb.Notes["a4"].ID().String()})
+	require.NoError(t, err)
+	assert.False(t, second.FullRecompute)
+	assert.True(t, first.RecomputedAt.Equal(second.RecomputedAt))
+	assert.Equal(t, 7, second.NodeCount)
+	assert.Equal(t, aCommunity, communityOf(t, nb, second, "a4"))
+	assert.Equal(t, bCommunity, communityOf(t, nb, second, "b1"))
+
+	// The partition and node assignments are stored
+	partition, err := nb.Communities.GetByGraphID(ctx, graphID)
+	require.NoError(t, err)
+	assert.Equal(t, aCommunity, partition.Assignments()[nb.Notes["a4"].ID().String()])
+	node, err := nb.Nodes.GetByID(ctx, nb.Notes["a4"].ID())
+	require.NoError(t, err)
+	assert.Equal(t, aCommunity, node.CommunityID())
+}
+
+func TestCommunityDetection_RecomputesWhenModularityDrifts(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	addTwoClusters(nb)
+	svc := services.NewCommunityDetectionService(nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	graphID := nb.Graph.ID().String()
+
+	_, err := svc.UpdateGraph(ctx, graphID, nil)
+	require.NoError(t, err)
+
+	// Linking the clusters tightly lowers modularity below the baseline
+	svc.SetModularityDrift(0)
+	nb.MustLink("a1", "b2", entities.EdgeTypeReference, 1)
+	nb.MustLink("a2", "b3", entities.EdgeTypeReference, 1)
+	result, err := svc.UpdateGraph(ctx, graphID, []string{nb.Notes["a1"].ID().String()})
+	require.NoError(t, err)
+	assert.True(t, result.FullRecompute)
+}
+
+func TestCommunityDetection_DeleteGraph(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	addTwoClusters(nb)
+	svc := services.NewCommunityDetectionService(nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+	graphID := nb.Graph.ID().String()
+
+	_, err := svc.RecomputeGraph(ctx, graphID)
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteGraph(ctx, graphID))
+
+	_, err = nb.Communities.GetByGraphID(ctx, graphID)
+	assert.True(t, pkgerrors.IsNotFound(err))
+}
+
+func TestCommunityDetection_Tree(t *testing.T) {
+	ctx := context.Background()
+	// The tree is built for the user's default graph
+	nb := fixtures.NewNotebook("user-1", "Default Graph")
+
+	// Two themes, each made of two cliques of four joined by one edge
+	for _, theme := range []string{"a", "b"} {
+		for _, sub := range []string{"0", "1"} {
+			clique := make([]string, 0, 4)
+			for _, k := range []string{"0", "1", "2", "3"} {
+				clique = append(clique, theme+sub+k)
+			}
+			nb.MustAddNotes(clique...)
+			for i := range clique {
+				for j := i + 1; j < len(clique); j++ {
+					nb.MustLink(clique[i], clique[j], entities.EdgeTypeReference, 1)
+				}
+			}
+		}
+		nb.MustLink(theme+"00", theme+"10", entities.EdgeTypeReference, 1)
+	}
+	nb.MustLink("a00", "b00", entities.EdgeTypeReference, 1)
+	svc := services.NewCommunityDetectionService(nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, zap.NewNop())
+
+	// Store the themes as the top level
+	theme := func(id, name, prefix string) *entities.Community {
+		c := &entities.Community{ID: id, GraphID: nb.Graph.ID().String(), Name: name}
+		for title, node := range nb.Notes {
+			if title[:1] == prefix {
+				c.MemberIDs = append(c.MemberIDs, node.ID().String())
+			}
+		}
+		c.MemberCount = len(c.MemberIDs)
+		return c
+	}
+	require.NoError(t, nb.Communities.Save(ctx, &entities.CommunityPartition{
+		GraphID:     nb.Graph.ID().String(),
+		Communities: []*entities.Community{theme("0", "Theme A", "a"), theme("1", "Theme B", "b")},
+	}))
+
+	tree, err := svc.GetCommunityTree(ctx, "user-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, services.DefaultCommunityTreeDepth, tree.Depth)
+	assert.Equal(t, 16, tree.NodeCount)
+	require.Len(t, tree.Communities, 2)
+	assert.Equal(t, "Theme A", tree.Communities[0].Name)
+	for _, c := range tree.Communities {
+		assert.Equal(t, 0, c.Level)
+		assert.Equal(t, 8, c.MemberCount)
+		require.Len(t, c.Children, 2, "each theme splits into its two cliques")
+		for i, child := range c.Children {
+			assert.Equal(t, fmt.Sprintf("%s.%d", c.ID, i), child.ID)
+			assert.Equal(t, 1, child.Level)
+			assert.Equal(t, 4, child.MemberCount)
+			assert.Equal(t, 1.0, child.CohesionScore)
+			assert.NotEmpty(t, child.Name)
+			assert.Empty(t, child.Children)
+		}
+	}
+
+	// One level only holds the stored communities
+	tree, err = svc.GetCommunityTree(ctx, "user-1", 1)
+	require.NoError(t, err)
+	for _, c := range tree.Communities {
+		assert.Empty(t, c.Children)
+	}
+
+	_, err = svc.GetCommunityTree(ctx, "user-1", services.MaxCommunityTreeDepth+1)
+	assert.True(t, pkgerrors.IsValidation(err))
+}
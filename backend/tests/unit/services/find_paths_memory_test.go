@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"backend/application/queries"
+	queries_handlers "backend/application/queries/handlers"
+	"backend/domain/core/entities"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFindPaths_RanksAlternativesAndExplainsHops(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	handler := queries_handlers.NewFindPathsHandler(nb.Graphs, nb.Nodes, nb.Edges, zap.NewNop())
+	nb.MustAddNotes("Go", "Concurrency", "Channels", "Erlang")
+	nb.MustLink("Go", "Concurrency", entities.EdgeTypeStrong, 0.9)
+	nb.MustLink("Channels", "Concurrency", entities.EdgeTypeReference, 0.8)
+	nb.MustLink("Go", "Erlang", entities.EdgeTypeWeak, 0.2)
+	nb.MustLink("Erlang", "Channels", entities.EdgeTypeWeak, 0.3)
+
+	result, err := handler.Handle(ctx, queries.FindPathsQuery{
+		UserID:  "user-1",
+		GraphID: nb.Graph.ID().String(),
+		FromID:  nb.Notes["Go"].ID().String(),
+		ToID:    nb.Notes["Channels"].ID().String(),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Paths, 2)
+
+	best := result.Paths[0]
+	assert.Equal(t, 1, best.Rank)
+	assert.Equal(t, 2, best.Hops)
+	assert.Less(t, best.Cost, result.Paths[1].Cost)
+	require.Len(t, best.Steps, 2)
+	assert.Equal(t, "Concurrency", best.Steps[0].ToTitle)
+	assert.Equal(t, "forward", best.Steps[0].Direction)
+	assert.Contains(t, best.Steps[0].Explanation, "is strongly connected to")
+	assert.Equal(t, "backward", best.Steps[1].Direction)
+	assert.Contains(t, best.Steps[1].Explanation, `"Concurrency" is referenced by "Channels"`)
+
+	// Without the strong link only the weak detour remains
+	result, err = handler.Handle(ctx, queries.FindPathsQuery{
+		UserID:       "user-1",
+		GraphID:      nb.Graph.ID().String(),
+		FromID:       nb.Notes["Go"].ID().String(),
+		ToID:         nb.Notes["Channels"].ID().String(),
+		ExcludeTypes: []string{"strong"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Paths, 1)
+	assert.Equal(t, "Erlang", result.Paths[0].Steps[0].ToTitle)
+
+	// Following edges only forward there is no way back into Channels
+	result, err = handler.Handle(ctx, queries.FindPathsQuery{
+		UserID:       "user-1",
+		GraphID:      nb.Graph.ID().String(),
+		FromID:       nb.Notes["Go"].ID().String(),
+		ToID:         nb.Notes["Channels"].ID().String(),
+		ExcludeTypes: []string{"weak"},
+		Directed:     true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Paths)
+}
+
+func TestFindPaths_Errors(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	handler := queries_handlers.NewFindPathsHandler(nb.Graphs, nb.Nodes, nb.Edges, zap.NewNop())
+	nb.MustAddNotes("a", "b")
+	a, b := nb.Notes["a"].ID().String(), nb.Notes["b"].ID().String()
+	graphID := nb.Graph.ID().String()
+
+	_, err := handler.Handle(ctx, queries.FindPathsQuery{UserID: "user-1", GraphID: graphID, FromID: a, ToID: b, ExcludeTypes: []string{"sideways"}})
+	assert.Error(t, err)
+
+	_, err = handler.Handle(ctx, queries.FindPathsQuery{UserID: "user-1", GraphID: graphID, FromID: a, ToID: "not-a-uuid"})
+	assert.True(t, pkgerrors.IsValidation(err))
+
+	_, err = handler.Handle(ctx, queries.FindPathsQuery{UserID: "user-1", GraphID: graphID, FromID: a, ToID: "7f1d2c8e-9a4b-4c3d-8e2f-1a2b3c4d5e6f"})
+	assert.True(t, pkgerrors.IsNotFound(err))
+
+	_, err = handler.Handle(ctx, queries.FindPathsQuery{UserID: "user-2", GraphID: graphID, FromID: a, ToID: b})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"backend/application/queries"
+	queries_handlers "backend/application/queries/handlers"
+	"backend/domain/core/entities"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// mapCache is a ports.Cache that counts writes
+type mapCache struct {
+	items map[string]interface{}
+	sets  int
+}
+
+func (c *mapCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+func (c *mapCache) Set(ctx context.Context, key string, value interface{}, ttl int) error {
+	c.items[key] = value
+	c.sets++
+	return nil
+}
+
+func (c *mapCache) Delete(ctx context.Context, key string) error {
+	delete(c.items, key)
+	return nil
+}
+
+func (c *mapCache) Clear(ctx context.Context) error {
+	c.items = make(map[string]interface{})
+	return nil
+}
+
+func TestGraphAnalytics_RanksHubsAndFragilePoints(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	handler := queries_handlers.NewGetGraphAnalyticsHandler(&mapCache{items: make(map[string]interface{})},
+		nb.Graphs, nb.Nodes, nb.Edges, zap.NewNop())
+	query := queries.GetGraphAnalyticsQuery{UserID: "user-1", GraphID: nb.Graph.ID().String()}
+	// Two clusters held together by a single link between their hubs
+	nb.MustAddNotes("ML hub", "Neural nets", "Transformers", "Cooking hub", "Bread", "Pasta")
+	nb.MustLink("ML hub", "Neural nets", entities.EdgeTypeReference, 1)
+	nb.MustLink("ML hub", "Transformers", entities.EdgeTypeReference, 1)
+	nb.MustLink("Neural nets", "Transformers", entities.EdgeTypeReference, 1)
+	nb.MustLink("Cooking hub", "Bread", entities.EdgeTypeReference, 1)
+	nb.MustLink("Cooking hub", "Pasta", entities.EdgeTypeReference, 1)
+	nb.MustLink("Bread", "Pasta", entities.EdgeTypeReference, 1)
+	nb.MustLink("ML hub", "Cooking hub", entities.EdgeTypeReference, 1)
+
+	result, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.NodeCount)
+	assert.Equal(t, 7, result.EdgeCount)
+	assert.Equal(t, 2, result.Degeneracy)
+
+	hubs := []string{result.Betweenness[0].Title, result.Betweenness[1].Title}
+	assert.ElementsMatch(t, []string{"ML hub", "Cooking hub"}, hubs)
+	assert.Contains(t, hubs, result.PageRank[0].Title)
+
+	require.Equal(t, 1, result.BridgeCount)
+	bridge := result.Bridges[0]
+	assert.ElementsMatch(t, []string{"ML hub", "Cooking hub"}, []string{bridge.SourceTitle, bridge.TargetTitle})
+
+	assert.Equal(t, 2, result.ArticulationCount)
+	assert.ElementsMatch(t, hubs, []string{result.ArticulationPoints[0].Title, result.ArticulationPoints[1].Title})
+
+	query.Top = 2
+	limited, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, limited.PageRank, 2)
+	assert.Len(t, limited.CoreNumber, 2)
+	assert.Len(t, result.PageRank, 6, "truncating does not change the cached result")
+}
+
+func TestGraphAnalytics_CachedUntilStructureChanges(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	cache := &mapCache{items: make(map[string]interface{})}
+	handler := queries_handlers.NewGetGraphAnalyticsHandler(cache, nb.Graphs, nb.Nodes, nb.Edges, zap.NewNop())
+	query := queries.GetGraphAnalyticsQuery{UserID: "user-1", GraphID: nb.Graph.ID().String(), Top: 10}
+	nb.MustAddNotes("a", "b", "c")
+	nb.MustLink("a", "b", entities.EdgeTypeReference, 1)
+
+	first, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.sets)
+	second, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.sets, "unchanged graph is served from the cache")
+	assert.Equal(t, first.ComputedAt, second.ComputedAt)
+
+	nb.MustLink("b", "c", entities.EdgeTypeReference, 1)
+	third, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.sets)
+	assert.Equal(t, 2, third.EdgeCount)
+}
+
+func TestGraphAnalytics_OtherUsersGraph(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	handler := queries_handlers.NewGetGraphAnalyticsHandler(&mapCache{items: make(map[string]interface{})},
+		nb.Graphs, nb.Nodes, nb.Edges, zap.NewNop())
+	_, err := handler.Handle(context.Background(), queries.GetGraphAnalyticsQuery{
+		UserID:  "user-2",
+		GraphID: nb.Graph.ID().String(),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
@@ -0,0 +1,208 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"backend/application/sagas"
+	"backend/application/services"
+	"backend/domain/core/entities"
+	"backend/domain/versioning"
+	"backend/infrastructure/persistence/memory"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// createVersion snapshots the notebook's graph under name
+func createVersion(t *testing.T, svc *services.GraphVersionService, nb *fixtures.Notebook, name string) *versioning.GraphVersion {
+	t.Helper()
+	version, err := svc.CreateVersion(context.Background(), "user-1", nb.Graph.ID().String(), name, "")
+	require.NoError(t, err)
+	return version
+}
+
+func changesOf(diff *versioning.SnapshotDiff, changeType versioning.ChangeType) []string {
+	ids := make([]string, 0)
+	for _, change := range diff.Changes {
+		if change.Type == changeType {
+			ids = append(ids, change.EntityID)
+		}
+	}
+	return ids
+}
+
+func TestGraphVersionService_CreateAndList(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Research")
+	svc := services.NewGraphVersionService(memory.NewInMemoryGraphVersionStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		versioning.NewVersioningService(10, true), zap.NewNop())
+	nb.MustAddNotes("Alpha", "Beta")
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+
+	first := createVersion(t, svc, nb, "initial")
+	assert.Equal(t, 1, first.Version)
+	assert.Equal(t, "initial", first.Name)
+	assert.Equal(t, 2, first.NodeCount)
+	assert.Equal(t, 1, first.EdgeCount)
+	assert.NotEmpty(t, first.Checksum)
+
+	nb.MustAddNotes("Gamma")
+	second := createVersion(t, svc, nb, "more")
+	assert.Equal(t, 2, second.Version)
+	assert.NotEqual(t, first.Checksum, second.Checksum)
+
+	versions, err := svc.ListVersions(context.Background(), "user-1", nb.Graph.ID().String())
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version, "newest version comes first")
+	assert.Equal(t, 1, versions[1].Version)
+
+	snapshot, err := svc.GetVersion(context.Background(), "user-1", nb.Graph.ID().String(), 1)
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Nodes, 2)
+	assert.Len(t, snapshot.Edges, 1)
+}
+
+func TestGraphVersionService_OtherUsersGraphIsNotFound(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Research")
+	svc := services.NewGraphVersionService(memory.NewInMemoryGraphVersionStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		versioning.NewVersioningService(10, true), zap.NewNop())
+	createVersion(t, svc, nb, "initial")
+
+	_, err := svc.ListVersions(context.Background(), "user-2", nb.Graph.ID().String())
+	assert.True(t, pkgerrors.IsNotFound(err))
+	_, err = svc.CreateVersion(context.Background(), "user-2", nb.Graph.ID().String(), "", "")
+	assert.True(t, pkgerrors.IsNotFound(err))
+}
+
+func TestGraphVersionService_Diff(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Research")
+	svc := services.NewGraphVersionService(memory.NewInMemoryGraphVersionStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		versioning.NewVersioningService(10, true), zap.NewNop())
+	a := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Alpha"))
+	b := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Beta"))
+	c := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Gamma"))
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+	createVersion(t, svc, nb, "before")
+
+	ctx := context.Background()
+	graphID := nb.Graph.ID().String()
+	nb.MustUpdateContent(a, "Alpha revised", "body of Alpha")
+	d := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Delta"))
+	nb.MustConnect("Beta", "Delta", entities.EdgeTypeNormal)
+	require.NoError(t, nb.Edges.Delete(ctx, graphID, a.ID().String(), b.ID().String()))
+	require.NoError(t, nb.Nodes.Delete(ctx, c.ID()))
+	createVersion(t, svc, nb, "after")
+
+	diff, err := svc.Diff(ctx, "user-1", graphID, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 2, diff.ToVersion)
+	assert.Equal(t, []string{d.ID().String()}, changesOf(diff, versioning.ChangeTypeNodeAdded))
+	assert.Equal(t, []string{c.ID().String()}, changesOf(diff, versioning.ChangeTypeNodeRemoved))
+	assert.Equal(t, []string{a.ID().String()}, changesOf(diff, versioning.ChangeTypeNodeUpdated))
+	assert.Len(t, changesOf(diff, versioning.ChangeTypeEdgeAdded), 1)
+	assert.Len(t, changesOf(diff, versioning.ChangeTypeEdgeRemoved), 1)
+	assert.Equal(t, 1, diff.NodesDiff.Added)
+	assert.Equal(t, 1, diff.NodesDiff.Removed)
+	assert.Equal(t, 1, diff.NodesDiff.Updated)
+
+	// Without a to version, the diff runs against the current graph
+	nb.MustUpdateContent(b, "Beta revised", "body of Beta")
+	current, err := svc.Diff(ctx, "user-1", graphID, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{b.ID().String()}, changesOf(current, versioning.ChangeTypeNodeUpdated))
+	assert.Len(t, current.Changes, 1)
+
+	_, err = svc.Diff(ctx, "user-1", graphID, 7, 0)
+	assert.True(t, pkgerrors.IsNotFound(err))
+}
+
+func TestGraphVersionService_PrunesBeyondRetention(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Research")
+	svc := services.NewGraphVersionService(memory.NewInMemoryGraphVersionStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		versioning.NewVersioningService(2, true), zap.NewNop())
+	nb.MustAddNotes("Alpha")
+	for i := 0; i < 4; i++ {
+		createVersion(t, svc, nb, "")
+	}
+
+	versions, err := svc.ListVersions(context.Background(), "user-1", nb.Graph.ID().String())
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 4, versions[0].Version)
+	assert.Equal(t, 3, versions[1].Version)
+
+	_, err = svc.GetVersion(context.Background(), "user-1", nb.Graph.ID().String(), 1)
+	assert.True(t, pkgerrors.IsNotFound(err))
+}
+
+func TestGraphRollbackSaga_RestoresVersion(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Research")
+	svc := services.NewGraphVersionService(memory.NewInMemoryGraphVersionStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		versioning.NewVersioningService(10, true), zap.NewNop())
+	a := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Alpha"))
+	b := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Beta"))
+	c := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Gamma"))
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+	nb.MustConnect("Beta", "Gamma", entities.EdgeTypeNormal)
+	createVersion(t, svc, nb, "good state")
+
+	ctx := context.Background()
+	graphID := nb.Graph.ID().String()
+	nb.MustUpdateContent(a, "Alpha broken", "body of Alpha")
+	d := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Delta"))
+	nb.MustConnect("Alpha", "Delta", entities.EdgeTypeNormal)
+	require.NoError(t, nb.Edges.Delete(ctx, graphID, b.ID().String(), c.ID().String()))
+	require.NoError(t, nb.Nodes.Delete(ctx, c.ID()))
+
+	saga := sagas.NewGraphRollbackSaga("user-1", graphID, 1, svc, nb.Nodes, nb.Edges, nb.Graphs, zap.NewNop())
+	result, err := saga.Execute(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, result.Checkpoint)
+	assert.Equal(t, 2, result.Checkpoint.Version, "the state before the rollback is kept")
+	require.NotNil(t, result.Result)
+	assert.Equal(t, 3, result.Result.Version)
+
+	// The graph matches version 1 again
+	diff, err := svc.Diff(ctx, "user-1", graphID, 1, 0)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Changes)
+
+	restoredA, err := nb.Nodes.GetByID(ctx, a.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", restoredA.Content().Title())
+	restoredC, err := nb.Nodes.GetByID(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Gamma", restoredC.Content().Title())
+	_, err = nb.Nodes.GetByID(ctx, d.ID())
+	assert.Error(t, err, "nodes added after the version are removed")
+
+	// Rolling back to the checkpoint undoes the rollback
+	undo := sagas.NewGraphRollbackSaga("user-1", graphID, 2, svc, nb.Nodes, nb.Edges, nb.Graphs, zap.NewNop())
+	_, err = undo.Execute(ctx)
+	require.NoError(t, err)
+	diff, err = svc.Diff(ctx, "user-1", graphID, 2, 0)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Changes)
+}
+
+func TestGraphRollbackSaga_UnknownVersion(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Research")
+	svc := services.NewGraphVersionService(memory.NewInMemoryGraphVersionStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		versioning.NewVersioningService(10, true), zap.NewNop())
+	nb.MustAddNotes("Alpha")
+	createVersion(t, svc, nb, "")
+
+	saga := sagas.NewGraphRollbackSaga("user-1", nb.Graph.ID().String(), 5, svc, nb.Nodes, nb.Edges, nb.Graphs, zap.NewNop())
+	_, err := saga.Execute(context.Background())
+	require.Error(t, err)
+	assert.True(t, pkgerrors.IsNotFound(err))
+
+	versions, err := svc.ListVersions(context.Background(), "user-1", nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, versions, 1, "no checkpoint is recorded for a missing version")
+}
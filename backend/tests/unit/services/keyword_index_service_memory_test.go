@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/application/events/listeners"
+	"backend/application/services"
+	"backend/domain/core/entities"
+	"backend/domain/events"
+	"backend/infrastructure/persistence/memory"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingKeywordStore records how often indexes are saved
+type countingKeywordStore struct {
+	*memory.InMemoryKeywordIndexStore
+	saves int
+}
+
+func (s *countingKeywordStore) Save(ctx context.Context, userID string, data []byte) error {
+	s.saves++
+	return s.InMemoryKeywordIndexStore.Save(ctx, userID, data)
+}
+
+// scanCountingRepo records how often all of a user's nodes are loaded
+type scanCountingRepo struct {
+	*memory.InMemoryNodeRepository
+	scans int
+}
+
+func (r *scanCountingRepo) GetByUserID(ctx context.Context, userID string) ([]*entities.Node, error) {
+	r.scans++
+	return r.InMemoryNodeRepository.GetByUserID(ctx, userID)
+}
+
+func keywordIDs(t *testing.T, svc *services.KeywordIndexService, terms ...string) []string {
+	t.Helper()
+	results, err := svc.Score(context.Background(), "user-1", terms, 0)
+	require.NoError(t, err)
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func TestKeywordIndexService_BuildsAndRestores(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	nb.MustAddNotes("Gardening", "Cooking")
+	store := &countingKeywordStore{InMemoryKeywordIndexStore: memory.NewInMemoryKeywordIndexStore(nb.DB)}
+	cfg := services.DefaultKeywordIndexConfig()
+	cfg.SyncInterval = 0
+	gardening := nb.Notes["Gardening"].ID().String()
+
+	svc := services.NewKeywordIndexService(store, nb.Nodes, cfg, zap.NewNop())
+	assert.Equal(t, []string{gardening}, keywordIDs(t, svc, "gardening"))
+	assert.Equal(t, 1, store.saves, "a freshly built index is saved right away")
+	assert.Len(t, keywordIDs(t, svc, "body"), 2, "titles and bodies are indexed")
+
+	// A cold start loads the saved index; nothing changed, so nothing is saved
+	restarted := services.NewKeywordIndexService(store, nb.Nodes, cfg, zap.NewNop())
+	assert.Equal(t, []string{gardening}, keywordIDs(t, restarted, "gardening"))
+	assert.Equal(t, 1, store.saves)
+}
+
+func TestKeywordIndexService_ListenerKeepsIndexInSync(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	other := nb.MustCreateNotebook("user-1", "Other")
+	a := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Alpha"))
+	cfg := services.DefaultKeywordIndexConfig()
+	cfg.SyncInterval = 0
+	svc := services.NewKeywordIndexService(memory.NewInMemoryKeywordIndexStore(nb.DB), nb.Nodes, cfg, zap.NewNop())
+	listener := listeners.NewKeywordIndexListener(svc, nb.Nodes, zap.NewNop())
+	ctx := context.Background()
+
+	require.Equal(t, []string{a.ID().String()}, keywordIDs(t, svc, "alpha"))
+
+	b := other.MustAdd(fixtures.NewNoteBuilder("user-1", "Beta"))
+	require.NoError(t, listener.Handle(ctx, events.NodeCreated{NodeID: b.ID(), UserID: "user-1"}))
+	assert.Equal(t, []string{b.ID().String()}, keywordIDs(t, svc, "beta"))
+
+	// Edited text replaces the old postings
+	nb.MustUpdateContent(b, "Gamma", "rewritten")
+	require.NoError(t, listener.Handle(ctx, &events.NodeContentUpdated{NodeID: b.ID()}))
+	assert.Empty(t, keywordIDs(t, svc, "beta"))
+	assert.Equal(t, []string{b.ID().String()}, keywordIDs(t, svc, "gamma"))
+
+	require.NoError(t, listener.Handle(ctx, events.NodeDeletedEvent{NodeID: a.ID(), UserID: "user-1"}))
+	assert.Empty(t, keywordIDs(t, svc, "alpha"))
+
+	require.NoError(t, listener.Handle(ctx, events.GraphDeletedEvent{GraphID: other.Graph.ID().String(), UserID: "user-1"}))
+	assert.Empty(t, keywordIDs(t, svc, "gamma"))
+}
+
+func TestKeywordIndexService_ReconcilesAfterSyncInterval(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	nb.MustAddNotes("Alpha")
+	cfg := services.DefaultKeywordIndexConfig()
+	cfg.SyncInterval = time.Millisecond
+	svc := services.NewKeywordIndexService(memory.NewInMemoryKeywordIndexStore(nb.DB), nb.Nodes, cfg, zap.NewNop())
+	require.Len(t, keywordIDs(t, svc, "alpha"), 1)
+
+	// Written without an event, e.g. by another process
+	late := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Late"))
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, []string{late.ID().String()}, keywordIDs(t, svc, "late"))
+}
+
+func TestHybridSearch_KeywordIndexAvoidsFullScan(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	nb.MustAddNotes("Gardening tips", "Cooking pasta")
+	repo := &scanCountingRepo{InMemoryNodeRepository: nb.Nodes}
+	cfg := services.DefaultKeywordIndexConfig()
+	cfg.SyncInterval = 0
+	svc := services.NewKeywordIndexService(memory.NewInMemoryKeywordIndexStore(nb.DB), repo, cfg, zap.NewNop())
+
+	search := services.NewHybridSearchService(nil, nil, nil, repo, nil).WithKeywordIndex(svc)
+	results, err := search.Search(context.Background(), "user-1", "pasta", 5)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Cooking pasta", results[0].Node.Content().Title())
+	assert.Equal(t, []string{"bm25"}, results[0].Sources)
+	assert.Equal(t, 1, repo.scans, "only the first load reads every node")
+
+	results, err = search.Search(context.Background(), "user-1", "gardening", 5)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 1, repo.scans)
+
+	results, err = search.Search(context.Background(), "user-2", "pasta", 5)
+	require.NoError(t, err)
+	assert.Empty(t, results, "other users' nodes are not found")
+}
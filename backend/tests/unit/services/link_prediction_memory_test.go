@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"backend/application/commands"
+	"backend/application/commands/bus"
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/aggregates"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	"backend/infrastructure/persistence/memory"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// edgeCommandSender stands in for the command bus, saving created edges
+// straight to the edge repository
+type edgeCommandSender struct {
+	edges *memory.InMemoryEdgeRepository
+	sent  []commands.CreateEdgeCommand
+}
+
+func (s *edgeCommandSender) Send(ctx context.Context, cmd bus.Command) error {
+	create, ok := cmd.(commands.CreateEdgeCommand)
+	if !ok {
+		return pkgerrors.NewValidationError("unexpected command")
+	}
+	sourceID, err := valueobjects.NewNodeIDFromString(create.SourceID)
+	if err != nil {
+		return err
+	}
+	targetID, err := valueobjects.NewNodeIDFromString(create.TargetID)
+	if err != nil {
+		return err
+	}
+	s.sent = append(s.sent, create)
+	return s.edges.Save(ctx, create.GraphID, &aggregates.Edge{
+		ID:       create.EdgeID,
+		SourceID: sourceID,
+		TargetID: targetID,
+		Type:     entities.EdgeType(create.Type),
+		Weight:   create.Weight,
+	})
+}
+
+// newSquare builds the square a-b-c-d-a in the user's default graph, all in
+// one community; its diagonals are the links to predict
+func newSquare(t *testing.T) *fixtures.Notebook {
+	t.Helper()
+	nb := fixtures.NewNotebook("user-1", "Default Graph")
+	nb.MustAddNotes("a", "b", "c", "d")
+	nb.MustLink("a", "b", entities.EdgeTypeReference, 1)
+	nb.MustLink("b", "c", entities.EdgeTypeReference, 1)
+	nb.MustLink("c", "d", entities.EdgeTypeReference, 1)
+	nb.MustLink("d", "a", entities.EdgeTypeReference, 1)
+
+	square := &entities.Community{ID: "0", GraphID: nb.Graph.ID().String(), Name: "Square"}
+	for _, node := range nb.Notes {
+		square.MemberIDs = append(square.MemberIDs, node.ID().String())
+	}
+	square.MemberCount = len(square.MemberIDs)
+	require.NoError(t, nb.Communities.Save(context.Background(), &entities.CommunityPartition{
+		GraphID:     nb.Graph.ID().String(),
+		Communities: []*entities.Community{square},
+	}))
+	return nb
+}
+
+// suggestionFor returns the suggestion joining two nodes, if any
+func suggestionFor(nb *fixtures.Notebook, suggestions []*ports.LinkSuggestion, a, b string) *ports.LinkSuggestion {
+	first, second := nb.Notes[a].ID().String(), nb.Notes[b].ID().String()
+	for _, s := range suggestions {
+		if (s.SourceID == first && s.TargetID == second) || (s.SourceID == second && s.TargetID == first) {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestLinkPrediction_AcceptCreatesEdge(t *testing.T) {
+	ctx := context.Background()
+	nb := newSquare(t)
+	sender := &edgeCommandSender{edges: nb.Edges}
+	svc := services.NewLinkPredictionService(memory.NewInMemoryLinkSuggestionStore(nb.DB),
+		nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, sender, zap.NewNop())
+
+	suggestions, err := svc.Refresh(ctx, "user-1", "", 0)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	diagonal := suggestionFor(nb, suggestions, "a", "c")
+	require.NotNil(t, diagonal)
+	assert.Equal(t, ports.LinkSuggestionPending, diagonal.Status)
+	assert.Equal(t, 2, diagonal.CommonNeighbours)
+	assert.True(t, diagonal.SameCommunity)
+	assert.Equal(t, nb.Graph.ID().String(), diagonal.GraphID)
+
+	// Refreshing again keeps the same suggestions rather than adding more
+	again, err := svc.Refresh(ctx, "user-1", "", 0)
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	assert.NotNil(t, suggestionFor(nb, again, "a", "c"))
+	assert.Equal(t, diagonal.ID, suggestionFor(nb, again, "a", "c").ID)
+
+	accepted, err := svc.Accept(ctx, "user-1", diagonal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ports.LinkSuggestionAccepted, accepted.Status)
+	assert.NotNil(t, accepted.ResolvedAt)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, diagonal.Score, sender.sent[0].Weight)
+	assert.Equal(t, sender.sent[0].EdgeID, accepted.EdgeID)
+
+	edges, err := nb.Edges.GetByGraphID(ctx, nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, edges, 5)
+
+	_, err = svc.Accept(ctx, "user-1", diagonal.ID)
+	assert.True(t, pkgerrors.IsConflict(err), "a resolved suggestion cannot be accepted again")
+
+	pending, err := svc.List(ctx, "user-1", "")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.NotNil(t, suggestionFor(nb, pending, "b", "d"))
+}
+
+func TestLinkPrediction_RejectTunesThreshold(t *testing.T) {
+	ctx := context.Background()
+	nb := newSquare(t)
+	svc := services.NewLinkPredictionService(memory.NewInMemoryLinkSuggestionStore(nb.DB),
+		nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, &edgeCommandSender{edges: nb.Edges}, zap.NewNop())
+
+	suggestions, err := svc.Refresh(ctx, "user-1", "", 0)
+	require.NoError(t, err)
+	diagonal := suggestionFor(nb, suggestions, "a", "c")
+	require.NotNil(t, diagonal)
+
+	rejected, err := svc.Reject(ctx, "user-1", diagonal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ports.LinkSuggestionRejected, rejected.Status)
+
+	feedback, err := svc.Feedback(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, feedback.Rejected)
+	assert.Greater(t, feedback.Threshold, services.DefaultLinkThreshold)
+
+	// The rejected pair is not offered again
+	refreshed, err := svc.Refresh(ctx, "user-1", "", 0)
+	require.NoError(t, err)
+	assert.Nil(t, suggestionFor(nb, refreshed, "a", "c"))
+
+	// Other users neither see the suggestion nor share the threshold
+	_, err = svc.Reject(ctx, "user-2", diagonal.ID)
+	assert.True(t, pkgerrors.IsNotFound(err))
+	other, err := svc.Feedback(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, services.DefaultLinkThreshold, other.Threshold)
+}
+
+func TestLinkPrediction_Validation(t *testing.T) {
+	ctx := context.Background()
+	nb := newSquare(t)
+	svc := services.NewLinkPredictionService(memory.NewInMemoryLinkSuggestionStore(nb.DB),
+		nb.Graphs, nb.Nodes, nb.Edges, nb.Communities, &edgeCommandSender{edges: nb.Edges}, zap.NewNop())
+
+	_, err := svc.Refresh(ctx, "user-1", "", services.MaxLinkSuggestionLimit+1)
+	assert.True(t, pkgerrors.IsValidation(err))
+
+	_, err = svc.Refresh(ctx, "user-2", nb.Graph.ID().String(), 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
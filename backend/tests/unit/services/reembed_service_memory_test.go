@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/core/valueobjects"
+	"backend/infrastructure/persistence/memory"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingEmbeddings embeds texts with a named model and counts the texts it
+// was asked to embed
+type countingEmbeddings struct {
+	model string
+	dims  int
+	texts int
+}
+
+func (c *countingEmbeddings) GenerateEmbedding(ctx context.Context, text string) (valueobjects.Embedding, error) {
+	out, err := c.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return valueobjects.Embedding{}, err
+	}
+	return out[0], nil
+}
+
+func (c *countingEmbeddings) GenerateEmbeddings(ctx context.Context, texts []string) ([]valueobjects.Embedding, error) {
+	c.texts += len(texts)
+	out := make([]valueobjects.Embedding, len(texts))
+	for i, text := range texts {
+		vector := make([]float64, c.dims)
+		vector[len(text)%c.dims] = 1
+		e, err := valueobjects.NewEmbedding(vector)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = e.WithProvenance(c.model, valueobjects.EmbeddingContentHash(text))
+	}
+	return out, nil
+}
+
+func (c *countingEmbeddings) Dimensions() int { return c.dims }
+
+func (c *countingEmbeddings) Model() string { return c.model }
+
+func TestReembed_SkipsUnchangedContent(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("alpha", "beta", "gamma")
+	embeddings := &countingEmbeddings{model: "model-a", dims: 4}
+	svc := services.NewReembedService(nb.Nodes, embeddings, memory.NewInMemoryOperationStore(time.Hour),
+		&services.ReembedConfig{BatchSize: 2}, zap.NewNop())
+
+	progress, err := svc.Run(ctx, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, progress.Total)
+	assert.Equal(t, 3, progress.Embedded)
+	assert.Equal(t, 3, embeddings.texts)
+	for title := range nb.Notes {
+		node := nb.MustLoad(title)
+		assert.Equal(t, "model-a", node.Embedding().Model())
+		assert.Equal(t, valueobjects.EmbeddingContentHash(node.EmbeddingText()), node.Embedding().ContentHash())
+		assert.False(t, node.NeedsEmbedding("model-a"))
+	}
+
+	// A second run finds every embedding current
+	progress, err = svc.Run(ctx, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, progress.Embedded)
+	assert.Equal(t, 3, progress.Skipped)
+	assert.Equal(t, 3, embeddings.texts)
+
+	// Editing a node only re-embeds that node
+	node := nb.MustUpdateContent(nb.MustLoad("alpha"), "alpha", "a new body")
+	assert.True(t, node.NeedsEmbedding("model-a"))
+
+	progress, err = svc.Run(ctx, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, progress.Embedded)
+	assert.Equal(t, 2, progress.Skipped)
+	assert.Equal(t, 4, embeddings.texts)
+}
+
+func TestReembed_ModelChangeReembedsEverything(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("alpha", "beta", "gamma")
+	operations := memory.NewInMemoryOperationStore(time.Hour)
+	config := &services.ReembedConfig{BatchSize: 2}
+
+	first := &countingEmbeddings{model: "model-a", dims: 4}
+	_, err := services.NewReembedService(nb.Nodes, first, operations, config, zap.NewNop()).Run(ctx, "user-1", "")
+	require.NoError(t, err)
+
+	next := &countingEmbeddings{model: "model-b", dims: 8}
+	progress, err := services.NewReembedService(nb.Nodes, next, operations, config, zap.NewNop()).Run(ctx, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "model-b", progress.Model)
+	assert.Equal(t, 8, progress.Dimensions)
+	assert.Equal(t, 3, progress.Embedded)
+	assert.Equal(t, 3, next.texts)
+	for title := range nb.Notes {
+		node := nb.MustLoad(title)
+		assert.Equal(t, "model-b", node.Embedding().Model())
+		assert.Equal(t, 8, node.Embedding().Dimensions())
+	}
+}
+
+func TestReembed_ReportsProgressThroughOperation(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("alpha", "beta", "gamma")
+	operations := memory.NewInMemoryOperationStore(time.Hour)
+	svc := services.NewReembedService(nb.Nodes, &countingEmbeddings{model: "model-a", dims: 4}, operations,
+		&services.ReembedConfig{BatchSize: 2}, zap.NewNop())
+
+	operationID, err := svc.Start(ctx, "user-1")
+	require.NoError(t, err)
+	require.NotEmpty(t, operationID)
+
+	var op *ports.OperationResult
+	require.Eventually(t, func() bool {
+		op, err = operations.Get(ctx, operationID)
+		return err == nil && op.Status == ports.OperationStatusCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "user-1", op.Metadata["user_id"])
+	assert.Equal(t, "model-a", op.Metadata["model"])
+	assert.NotNil(t, op.CompletedAt)
+	progress, ok := op.Result.(services.ReembedProgress)
+	require.True(t, ok)
+	assert.Equal(t, 3, progress.Total)
+	assert.Equal(t, 3, progress.Processed)
+	assert.Equal(t, 3, progress.Embedded)
+}
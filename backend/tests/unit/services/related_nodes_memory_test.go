@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"backend/application/queries"
+	"backend/domain/core/entities"
+	"backend/domain/core/valueobjects"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func relatedTitles(result *queries.GetRelatedNodesResult) []string {
+	out := make([]string, len(result.Nodes))
+	for i, n := range result.Nodes {
+		out[i] = n.Title
+	}
+	return out
+}
+
+func related(t *testing.T, nb *fixtures.Notebook, query *queries.GetRelatedNodesQuery) *queries.GetRelatedNodesResult {
+	t.Helper()
+	query.UserID = "user-1"
+	require.NoError(t, query.Validate())
+	result, err := queries.NewGetRelatedNodesHandler(nb.Nodes, nb.Edges).Handle(context.Background(), query)
+	require.NoError(t, err)
+	return result.(*queries.GetRelatedNodesResult)
+}
+
+func TestRelatedNodes_RanksByStructuralProximity(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	seed := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Sourdough").WithContent("Wild yeast bread."))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Starter").WithContent("Flour and water."))
+	flour := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Flour types").WithContent("Rye, spelt, wheat."))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Taxes").WithContent("File before April."))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Gardening").WithContent("Tomatoes need sun."))
+	nb.MustLink("Sourdough", "Starter", entities.EdgeTypeReference, 0.9)
+	nb.MustLink("Starter", "Flour types", entities.EdgeTypeReference, 0.9)
+	nb.MustLink("Taxes", "Sourdough", entities.EdgeTypeReference, 0.1)
+
+	result := related(t, nb, &queries.GetRelatedNodesQuery{NodeID: seed.ID().String()})
+	assert.Equal(t, []string{seed.ID().String()}, result.Seeds)
+	assert.Equal(t, []string{"Starter", "Flour types", "Taxes"}, relatedTitles(result), "unconnected notes are not related")
+	assert.Equal(t, 3, result.Total)
+	assert.InDelta(t, 1.0, result.Nodes[0].Structural, 1e-9)
+
+	// The flour note is explained by the walk through the starter
+	require.Len(t, result.Nodes[1].Paths, 1)
+	path := result.Nodes[1].Paths[0]
+	require.Len(t, path.Steps, 3)
+	assert.Equal(t, "Sourdough", path.Steps[0].Title)
+	assert.Equal(t, "Starter", path.Steps[1].Title)
+	assert.Equal(t, flour.ID().String(), path.Steps[2].NodeID)
+	assert.Greater(t, path.Strength, 0.0)
+
+	limited := related(t, nb, &queries.GetRelatedNodesQuery{NodeID: seed.ID().String(), Limit: 1})
+	assert.Equal(t, []string{"Starter"}, relatedTitles(limited))
+	assert.Equal(t, 3, limited.Total)
+}
+
+func TestRelatedNodes_MultipleSeeds(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	nb.MustAddNotes("Bread", "Pizza", "Dough", "Oven")
+	nb.MustLink("Bread", "Oven", entities.EdgeTypeReference, 1)
+	nb.MustLink("Bread", "Dough", entities.EdgeTypeReference, 1)
+	nb.MustLink("Pizza", "Dough", entities.EdgeTypeReference, 1)
+
+	result := related(t, nb, &queries.GetRelatedNodesQuery{
+		NodeID:  nb.Notes["Bread"].ID().String(),
+		SeedIDs: []string{nb.Notes["Pizza"].ID().String()},
+	})
+	assert.Equal(t, []string{"Dough", "Oven"}, relatedTitles(result), "the note near both seeds comes first")
+	assert.Len(t, result.Nodes[0].Paths, 2, "one path from each seed")
+}
+
+func TestRelatedNodes_BlendsSemanticSimilarity(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	seed := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Sourdough").WithEmbedding(1, 0, 0))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Linked").WithEmbedding(0, 1, 0))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Similar").WithEmbedding(0.9, 0.1, 0))
+	nb.MustLink("Sourdough", "Linked", entities.EdgeTypeReference, 1)
+
+	structural := related(t, nb, &queries.GetRelatedNodesQuery{NodeID: seed.ID().String()})
+	assert.Equal(t, []string{"Linked"}, relatedTitles(structural))
+
+	blended := related(t, nb, &queries.GetRelatedNodesQuery{NodeID: seed.ID().String(), SemanticWeight: 0.6})
+	assert.Equal(t, []string{"Similar", "Linked"}, relatedTitles(blended))
+	assert.Greater(t, blended.Nodes[0].Semantic, 0.9)
+	assert.Empty(t, blended.Nodes[0].Paths, "no edges lead to a node related by content only")
+}
+
+func TestRelatedNodes_Errors(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Kitchen")
+	seed := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Sourdough").WithContent(""))
+	handler := queries.NewGetRelatedNodesHandler(nb.Nodes, nb.Edges)
+
+	_, err := handler.Handle(context.Background(), &queries.GetRelatedNodesQuery{
+		UserID: "user-2",
+		NodeID: seed.ID().String(),
+	})
+	assert.True(t, pkgerrors.IsNotFound(err), "other users' nodes are not found")
+
+	_, err = handler.Handle(context.Background(), &queries.GetRelatedNodesQuery{
+		UserID:  "user-1",
+		NodeID:  seed.ID().String(),
+		SeedIDs: []string{valueobjects.NewNodeID().String()},
+	})
+	assert.True(t, pkgerrors.IsNotFound(err))
+
+	invalid := &queries.GetRelatedNodesQuery{UserID: "user-1", NodeID: seed.ID().String(), SemanticWeight: 2}
+	assert.Error(t, invalid.Validate())
+}
@@ -0,0 +1,194 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appevents "backend/application/events"
+	"backend/application/events/listeners"
+	"backend/application/queries"
+	"backend/application/services"
+	"backend/domain/core/entities"
+	"backend/domain/events"
+	"backend/domain/search"
+	"backend/infrastructure/embeddings"
+	"backend/infrastructure/persistence/memory"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func titles(results []services.SearchResult) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.Node.Content().Title()
+	}
+	return out
+}
+
+func TestHybridSearch_QueryLanguageFilters(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	garden := nb.MustCreateNotebook("user-1", "Garden")
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Neural networks").WithTags("ml"))
+	nb.MustAddNotes("Neural cooking")
+	garden.MustAddNotes("Gardening")
+
+	for _, withIndex := range []bool{false, true} {
+		search := services.NewHybridSearchService(nil, nil, nil, nb.Nodes, nil)
+		if withIndex {
+			search.WithKeywordIndex(services.NewKeywordIndexService(memory.NewInMemoryKeywordIndexStore(nb.DB), nb.Nodes, nil, zap.NewNop()))
+		}
+		ctx := context.Background()
+
+		results, err := search.Search(ctx, "user-1", "neural tag:ml", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Neural networks"}, titles(results))
+
+		results, err = search.Search(ctx, "user-1", "neural -cooking", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Neural networks"}, titles(results))
+
+		results, err = search.Search(ctx, "user-1", `"body of neural cooking"`, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Neural cooking"}, titles(results))
+
+		// Filters alone list the matching nodes
+		results, err = search.Search(ctx, "user-1", "graph:"+garden.Graph.ID().String()+" status:draft", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Gardening"}, titles(results))
+		assert.Equal(t, []string{"filter"}, results[0].Sources)
+	}
+}
+
+func TestHybridSearchQuery_ParseErrors(t *testing.T) {
+	q := &queries.HybridSearchQuery{UserID: "user-1", Query: `tag:ml "open`}
+	err := q.Validate()
+	var parseErr *search.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 7, parseErr.Position)
+
+	converted := queries.NewSearchQueryError(err)
+	require.True(t, pkgerrors.IsValidation(converted))
+	appErr := pkgerrors.GetAppError(converted)
+	assert.Equal(t, "INVALID_SEARCH_QUERY", appErr.Code)
+	assert.Equal(t, 7, appErr.Details["position"])
+
+	handler := queries.NewHybridSearchHandler(services.NewHybridSearchService(nil, nil, nil, fixtures.NewMemoryBackend().Nodes, nil))
+	_, err = handler.Handle(context.Background(), &queries.HybridSearchQuery{UserID: "user-1", Query: "status:deleted", Limit: 5})
+	assert.True(t, pkgerrors.IsValidation(err), "unvalidated queries are still rejected as invalid")
+
+	// Colons outside known fields are ordinary text
+	for _, text := range []string{"re: budget", "meeting at 10:30", "https://example.com"} {
+		assert.NoError(t, (&queries.HybridSearchQuery{UserID: "user-1", Query: text}).Validate(), text)
+	}
+}
+
+func TestHybridSearchHandler_SnippetsFacetsAndTotals(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	ctx := context.Background()
+	for _, title := range []string{"Neural one", "Neural two", "Neural three"} {
+		nb.MustAdd(fixtures.NewNoteBuilder("user-1", title).WithTags("ml"))
+	}
+	nb.MustAddNotes("Neural untagged", "Gardening")
+
+	keywordIndex := services.NewKeywordIndexService(memory.NewInMemoryKeywordIndexStore(nb.DB), nb.Nodes, nil, zap.NewNop())
+	search := services.NewHybridSearchService(nil, nil, nil, nb.Nodes, nil).WithKeywordIndex(keywordIndex)
+	handler := queries.NewHybridSearchHandler(search)
+
+	query := &queries.HybridSearchQuery{UserID: "user-1", Query: "neural", Limit: 2, Offset: 1}
+	require.NoError(t, query.Validate())
+	out, err := handler.Handle(ctx, query)
+	require.NoError(t, err)
+	result := out.(*queries.HybridSearchResult)
+
+	assert.Equal(t, 4, result.Total, "the total covers every match, not just the page")
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, []queries.HybridSearchFacetValue{{Value: "ml", Count: 3}}, result.Facets.Tags)
+	assert.Equal(t, []queries.HybridSearchFacetValue{{Value: "draft", Count: 4}}, result.Facets.Status)
+	require.Len(t, result.Facets.CreatedMonth, 1)
+
+	item := result.Results[0]
+	require.NotEmpty(t, item.Snippets)
+	assert.Equal(t, "title", item.Snippets[0].Field)
+	assert.Contains(t, item.Snippets[0].Highlighted, "<mark>Neural</mark>")
+
+	// Filters narrow the facets too
+	query = &queries.HybridSearchQuery{UserID: "user-1", Query: "neural -tag:ml", Limit: 10}
+	require.NoError(t, query.Validate())
+	out, err = handler.Handle(ctx, query)
+	require.NoError(t, err)
+	result = out.(*queries.HybridSearchResult)
+	assert.Equal(t, 1, result.Total)
+	assert.Empty(t, result.Facets.Tags)
+}
+
+func TestHybridSearch_StemmingAndTypos(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	nb.MustAddNotes("Running shoes", "Gardening")
+
+	for _, withIndex := range []bool{false, true} {
+		search := services.NewHybridSearchService(nil, nil, nil, nb.Nodes, nil)
+		if withIndex {
+			search.WithKeywordIndex(services.NewKeywordIndexService(memory.NewInMemoryKeywordIndexStore(nb.DB), nb.Nodes, nil, zap.NewNop()))
+		}
+		ctx := context.Background()
+
+		// Inflections and misspellings match and are highlighted
+		for _, query := range []string{"runs", "runing", "shoe runs"} {
+			results, err := search.Search(ctx, "user-1", query, 10)
+			require.NoError(t, err)
+			require.Equal(t, []string{"Running shoes"}, titles(results), query)
+			assert.Contains(t, results[0].Snippets[0].Highlighted, "<mark>Running", query)
+		}
+
+		results, err := search.Search(ctx, "user-1", "runs tag:ml", 10)
+		require.NoError(t, err)
+		assert.Empty(t, results, "filters still apply")
+	}
+}
+
+func TestHybridSearch_SemanticWithLocalEmbeddings(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	local := embeddings.NewLocalService(nil)
+	index := services.NewVectorIndexService(memory.NewInMemoryVectorIndexStore(nb.DB), nb.Nodes, nil, zap.NewNop())
+
+	// New and edited notes are embedded by their events, as the local
+	// provider wires it
+	registry := appevents.NewHandlerRegistry(zap.NewNop())
+	require.NoError(t, listeners.NewEmbeddingListener(local, nb.Nodes, zap.NewNop()).Subscribe(registry))
+	require.NoError(t, listeners.NewVectorIndexListener(index, nb.Nodes, zap.NewNop()).Subscribe(registry))
+	created := func(title string) *entities.Node {
+		node := nb.MustAdd(fixtures.NewNoteBuilder("user-1", title))
+		require.NoError(t, registry.Dispatch(ctx, events.NewNodeCreated(
+			node.ID(), "user-1", nb.Graph.ID().String(), title, "body of "+title, nil, nil, time.Now())))
+		return node
+	}
+	created("Neural network training")
+	bread := created("Sourdough baking")
+
+	search := services.NewHybridSearchService(nil, local, nil, nb.Nodes, nil).WithVectorIndex(index)
+	results, err := search.Search(ctx, "user-1", "trained neural networks", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Neural network training", results[0].Node.Content().Title())
+	assert.Contains(t, results[0].Sources, "semantic")
+	assert.Greater(t, results[0].SemanticScore, 0.0)
+
+	edited := nb.MustUpdateContent(bread, "Sourdough starters", "feeding wild yeast")
+	require.NoError(t, registry.Dispatch(ctx, events.NewNodeContentUpdated(
+		bread.ID(), bread.Content(), edited.Content(), time.Now())))
+	stored, err := nb.Nodes.GetByID(ctx, bread.ID())
+	require.NoError(t, err)
+	assert.False(t, stored.NeedsEmbedding(local.Model()))
+	results, err = search.Search(ctx, "user-1", "wild yeast starters", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Sourdough starters", results[0].Node.Content().Title())
+	assert.Contains(t, results[0].Sources, "semantic")
+}
@@ -0,0 +1,258 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/application/commands"
+	commands_handlers "backend/application/commands/handlers"
+	"backend/application/ports"
+	"backend/application/services"
+	"backend/domain/config"
+	"backend/domain/core/entities"
+	"backend/infrastructure/messaging"
+	"backend/infrastructure/persistence/memory"
+	pkgerrors "backend/pkg/errors"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTrashService_TrashRemovesNodeAndEdges(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("Alpha", "Beta", "Gamma")
+	cfg := config.DefaultDomainConfig()
+	cfg.NodeTTL = 24 * time.Hour
+	svc := services.NewTrashService(memory.NewInMemoryTrashStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		memory.NewInMemoryEventStore(nb.DB), messaging.NewLocalEventBus(zap.NewNop()), cfg, zap.NewNop())
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+	nb.MustConnect("Alpha", "Gamma", entities.EdgeTypeNormal)
+
+	entry, err := svc.Trash(ctx, nb.MustLoad("Alpha"))
+	require.NoError(t, err)
+	assert.Len(t, entry.Edges, 2)
+	require.NotNil(t, entry.PurgeAt)
+	assert.WithinDuration(t, entry.TrashedAt.Add(24*time.Hour), *entry.PurgeAt, time.Second)
+
+	_, err = nb.Nodes.GetByID(ctx, nb.Notes["Alpha"].ID())
+	assert.Error(t, err)
+	remaining, err := nb.Nodes.GetByGraphID(ctx, nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2)
+	edges, err := nb.Edges.GetByGraphID(ctx, nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, edges)
+
+	listed, err := svc.List(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "Alpha", listed[0].Title)
+
+	other, err := svc.List(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestTrashService_RestoreReconnectsEdges(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("Alpha", "Beta", "Gamma")
+	cfg := config.DefaultDomainConfig()
+	cfg.NodeTTL = 24 * time.Hour
+	svc := services.NewTrashService(memory.NewInMemoryTrashStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		memory.NewInMemoryEventStore(nb.DB), messaging.NewLocalEventBus(zap.NewNop()), cfg, zap.NewNop())
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+	nb.MustConnect("Alpha", "Gamma", entities.EdgeTypeNormal)
+
+	alpha := nb.MustLoad("Alpha")
+	require.NoError(t, alpha.AddTag("keep"))
+	require.NoError(t, nb.Nodes.Save(ctx, alpha))
+
+	_, err := svc.TrashNodes(ctx, []*entities.Node{nb.MustLoad("Alpha"), nb.MustLoad("Beta")})
+	require.NoError(t, err)
+
+	// Beta is still in the trash, so only the edge to Gamma comes back
+	result, err := svc.Restore(ctx, "user-1", nb.Notes["Alpha"].ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.RestoredEdges)
+	assert.Equal(t, 1, result.SkippedEdges)
+
+	restored := nb.MustLoad("Alpha")
+	assert.Equal(t, "Alpha", restored.Content().Title())
+	assert.Equal(t, nb.Graph.ID().String(), restored.GraphID())
+	assert.Contains(t, restored.GetTags(), "keep")
+
+	// Restoring Beta reconnects the edge from its own trash entry
+	result, err = svc.Restore(ctx, "user-1", nb.Notes["Beta"].ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.RestoredEdges)
+	assert.Equal(t, 0, result.SkippedEdges)
+
+	edges, err := nb.Edges.GetByNodeID(ctx, nb.Notes["Alpha"].ID().String())
+	require.NoError(t, err)
+	assert.Len(t, edges, 2)
+
+	listed, err := svc.List(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	_, err = svc.Restore(ctx, "user-1", nb.Notes["Alpha"].ID().String())
+	assert.True(t, pkgerrors.IsNotFound(err))
+}
+
+func TestTrashService_RestoreRequiresGraph(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("Alpha")
+	svc := services.NewTrashService(memory.NewInMemoryTrashStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		memory.NewInMemoryEventStore(nb.DB), messaging.NewLocalEventBus(zap.NewNop()), config.DefaultDomainConfig(), zap.NewNop())
+	require.NoError(t, nb.Graphs.Save(ctx, nb.Graph))
+
+	_, err := svc.Trash(ctx, nb.MustLoad("Alpha"))
+	require.NoError(t, err)
+	require.NoError(t, nb.Graphs.Delete(ctx, nb.Graph.ID()))
+
+	_, err = svc.Restore(ctx, "user-1", nb.Notes["Alpha"].ID().String())
+	assert.True(t, pkgerrors.IsConflict(err))
+
+	listed, err := svc.List(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+}
+
+func TestTrashService_PurgeExpiredHonorsNodeTTL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expired", func(t *testing.T) {
+		nb := fixtures.NewNotebook("user-1", "Research")
+		nb.MustAddNotes("Alpha", "Beta")
+		cfg := config.DefaultDomainConfig()
+		cfg.NodeTTL = time.Millisecond
+		svc := services.NewTrashService(memory.NewInMemoryTrashStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+			memory.NewInMemoryEventStore(nb.DB), messaging.NewLocalEventBus(zap.NewNop()), cfg, zap.NewNop())
+		_, err := svc.TrashNodes(ctx, []*entities.Node{nb.MustLoad("Alpha"), nb.MustLoad("Beta")})
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		purged, err := svc.PurgeExpired(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		purged, err = svc.PurgeExpired(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		listed, err := svc.List(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+		_, err = svc.Restore(ctx, "user-1", nb.Notes["Alpha"].ID().String())
+		assert.True(t, pkgerrors.IsNotFound(err))
+	})
+
+	t.Run("no ttl keeps trash", func(t *testing.T) {
+		nb := fixtures.NewNotebook("user-1", "Research")
+		nb.MustAddNotes("Alpha")
+		svc := services.NewTrashService(memory.NewInMemoryTrashStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+			memory.NewInMemoryEventStore(nb.DB), messaging.NewLocalEventBus(zap.NewNop()), config.DefaultDomainConfig(), zap.NewNop())
+		entry, err := svc.Trash(ctx, nb.MustLoad("Alpha"))
+		require.NoError(t, err)
+		assert.Nil(t, entry.PurgeAt)
+
+		purged, err := svc.PurgeExpired(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, purged)
+
+		require.NoError(t, svc.Purge(ctx, "user-1", nb.Notes["Alpha"].ID().String()))
+		listed, err := svc.List(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+	})
+}
+
+func TestDeleteGraph_TrashesNodes(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("Alpha", "Beta")
+	cfg := config.DefaultDomainConfig()
+	cfg.NodeTTL = 24 * time.Hour
+	svc := services.NewTrashService(memory.NewInMemoryTrashStore(nb.DB), nb.Nodes, nb.Edges, nb.Graphs,
+		memory.NewInMemoryEventStore(nb.DB), messaging.NewLocalEventBus(zap.NewNop()), cfg, zap.NewNop())
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+
+	handler := commands_handlers.NewDeleteGraphHandler(
+		nb.Nodes, nb.Edges, nb.Graphs, nil, messaging.NewLocalEventBus(zap.NewNop()), nil, zap.NewNop(),
+	).WithTrash(svc)
+	require.NoError(t, handler.Handle(ctx, commands.DeleteGraphCommand{
+		UserID:  "user-1",
+		GraphID: nb.Graph.ID().String(),
+	}))
+
+	_, err := nb.Graphs.GetByID(ctx, nb.Graph.ID())
+	assert.Error(t, err)
+	remaining, err := nb.Nodes.GetByGraphID(ctx, nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	// The nodes wait in the trash for their history to be purged
+	listed, err := svc.List(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+	require.NoError(t, svc.Purge(ctx, "user-1", nb.Notes["Alpha"].ID().String()))
+}
+
+// failingTrashStore refuses to store one node
+type failingTrashStore struct {
+	ports.TrashStore
+	nodeID string
+}
+
+func (s *failingTrashStore) Put(ctx context.Context, trashed *ports.TrashedNode) error {
+	if trashed.NodeID == s.nodeID {
+		return errors.New("trash store unavailable")
+	}
+	return s.TrashStore.Put(ctx, trashed)
+}
+
+func TestBulkDelete_RestoresTrashedNodesOnFailure(t *testing.T) {
+	ctx := context.Background()
+	nb := fixtures.NewNotebook("user-1", "Research")
+	nb.MustAddNotes("Alpha", "Beta", "Gamma")
+	nb.MustConnect("Alpha", "Beta", entities.EdgeTypeNormal)
+	nb.MustConnect("Beta", "Gamma", entities.EdgeTypeNormal)
+
+	logger := zap.NewNop()
+	bus := messaging.NewLocalEventBus(logger)
+	trash := services.NewTrashService(
+		&failingTrashStore{TrashStore: memory.NewInMemoryTrashStore(nb.DB), nodeID: nb.Notes["Gamma"].ID().String()},
+		nb.Nodes, nb.Edges, nb.Graphs, nil, bus, config.DefaultDomainConfig(), logger,
+	)
+	uow := memory.NewInMemoryUnitOfWork(nb.DB, nb.Nodes, nb.Edges, nb.Graphs, memory.NewInMemoryEventStore(nb.DB))
+	handler := commands_handlers.NewBulkDeleteNodesHandler(uow, nb.Nodes, nb.Edges, nb.Graphs, nil, bus, logger).
+		WithTrash(trash)
+
+	err := handler.Handle(ctx, commands.BulkDeleteNodesCommand{
+		UserID:      "user-1",
+		OperationID: "op-1",
+		NodeIDs: []string{
+			nb.Notes["Alpha"].ID().String(),
+			nb.Notes["Beta"].ID().String(),
+			nb.Notes["Gamma"].ID().String(),
+		},
+	})
+	require.Error(t, err)
+
+	remaining, err := nb.Nodes.GetByGraphID(ctx, nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, remaining, 3)
+	edges, err := nb.Edges.GetByGraphID(ctx, nb.Graph.ID().String())
+	require.NoError(t, err)
+	assert.Len(t, edges, 2)
+	listed, err := trash.List(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
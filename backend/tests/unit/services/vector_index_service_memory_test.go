@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/application/events/listeners"
+	"backend/application/queries"
+	"backend/application/services"
+	"backend/domain/core/valueobjects"
+	"backend/domain/events"
+	"backend/infrastructure/persistence/memory"
+	"backend/tests/fixtures"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingVectorStore records how often indexes are saved
+type countingVectorStore struct {
+	*memory.InMemoryVectorIndexStore
+	saves int
+}
+
+func (s *countingVectorStore) Save(ctx context.Context, userID string, data []byte) error {
+	s.saves++
+	return s.InMemoryVectorIndexStore.Save(ctx, userID, data)
+}
+
+// fixedEmbeddings embeds every text as the same vector
+type fixedEmbeddings struct {
+	vector []float64
+}
+
+func (f fixedEmbeddings) GenerateEmbedding(ctx context.Context, text string) (valueobjects.Embedding, error) {
+	return valueobjects.NewEmbedding(f.vector)
+}
+
+func (f fixedEmbeddings) GenerateEmbeddings(ctx context.Context, texts []string) ([]valueobjects.Embedding, error) {
+	out := make([]valueobjects.Embedding, len(texts))
+	for i := range texts {
+		out[i], _ = valueobjects.NewEmbedding(f.vector)
+	}
+	return out, nil
+}
+
+func (f fixedEmbeddings) Dimensions() int { return len(f.vector) }
+
+func (f fixedEmbeddings) Model() string { return "fixed" }
+
+func embedding(t *testing.T, vector ...float64) valueobjects.Embedding {
+	t.Helper()
+	e, err := valueobjects.NewEmbedding(vector)
+	require.NoError(t, err)
+	return e
+}
+
+func idsOf(t *testing.T, svc *services.VectorIndexService, query valueobjects.Embedding, k int) []string {
+	t.Helper()
+	results, err := svc.Search(context.Background(), "user-1", query, k)
+	require.NoError(t, err)
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func TestVectorIndexService_BuildsAndRestores(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	north := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "North").WithEmbedding(0, 1, 0))
+	east := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "East").WithEmbedding(1, 0, 0))
+	nb.MustAddNotes("Plain")
+	store := &countingVectorStore{InMemoryVectorIndexStore: memory.NewInMemoryVectorIndexStore(nb.DB)}
+
+	svc := services.NewVectorIndexService(store, nb.Nodes, nil, zap.NewNop())
+	ids := idsOf(t, svc, embedding(t, 0.1, 0.9, 0), 2)
+	assert.Equal(t, []string{north.ID().String(), east.ID().String()}, ids, "nodes without embeddings are not indexed")
+	assert.Equal(t, 1, store.saves, "a freshly built index is saved right away")
+
+	// A cold start loads the saved index; nothing changed, so nothing is saved
+	restarted := services.NewVectorIndexService(store, nb.Nodes, nil, zap.NewNop())
+	ids = idsOf(t, restarted, embedding(t, 1, 0.1, 0), 1)
+	assert.Equal(t, []string{east.ID().String()}, ids)
+	assert.Equal(t, 1, store.saves)
+}
+
+func TestVectorIndexService_SkipsEmbeddingsOfOtherModels(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	current := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Current"))
+	current.SetEmbedding(embedding(t, 0, 1, 0).WithProvenance("model-b", ""))
+	require.NoError(t, nb.Nodes.Save(context.Background(), current))
+	stale := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Stale"))
+	stale.SetEmbedding(embedding(t, 0, 1, 0).WithProvenance("model-a", ""))
+	require.NoError(t, nb.Nodes.Save(context.Background(), stale))
+	legacy := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Legacy").WithEmbedding(0, 0.9, 0.1))
+
+	cfg := services.DefaultVectorIndexConfig()
+	cfg.Model = "model-b"
+	svc := services.NewVectorIndexService(memory.NewInMemoryVectorIndexStore(nb.DB), nb.Nodes, cfg, zap.NewNop())
+	ids := idsOf(t, svc, embedding(t, 0, 1, 0), 3)
+	assert.Equal(t, []string{current.ID().String(), legacy.ID().String()}, ids,
+		"embeddings of another model stay out of the index until re-embedded")
+}
+
+func TestVectorIndexService_ListenerKeepsIndexInSync(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	other := nb.MustCreateNotebook("user-1", "Other")
+	a := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "A").WithEmbedding(1, 0, 0))
+	svc := services.NewVectorIndexService(memory.NewInMemoryVectorIndexStore(nb.DB), nb.Nodes, nil, zap.NewNop())
+	listener := listeners.NewVectorIndexListener(svc, nb.Nodes, zap.NewNop())
+	ctx := context.Background()
+
+	require.Equal(t, []string{a.ID().String()}, idsOf(t, svc, embedding(t, 1, 0, 0), 5))
+
+	b := other.MustAdd(fixtures.NewNoteBuilder("user-1", "B").WithEmbedding(0, 1, 0))
+	require.NoError(t, listener.Handle(ctx, events.NodeCreated{NodeID: b.ID(), UserID: "user-1"}))
+	assert.Equal(t, []string{b.ID().String()}, idsOf(t, svc, embedding(t, 0, 1, 0), 1))
+
+	// A changed embedding moves the node
+	loaded := other.MustLoad("B")
+	loaded.SetEmbedding(embedding(t, 1, 0.05, 0))
+	require.NoError(t, nb.Nodes.Save(ctx, loaded))
+	require.NoError(t, listener.Handle(ctx, &events.NodeContentUpdated{NodeID: b.ID()}))
+	assert.Equal(t, []string{a.ID().String(), b.ID().String()}, idsOf(t, svc, embedding(t, 1, 0.01, 0), 2))
+
+	require.NoError(t, listener.Handle(ctx, events.NodeDeletedEvent{NodeID: a.ID(), UserID: "user-1"}))
+	assert.Equal(t, []string{b.ID().String()}, idsOf(t, svc, embedding(t, 1, 0, 0), 5))
+
+	require.NoError(t, listener.Handle(ctx, events.GraphDeletedEvent{GraphID: other.Graph.ID().String(), UserID: "user-1"}))
+	assert.Empty(t, idsOf(t, svc, embedding(t, 1, 0, 0), 5))
+}
+
+func TestVectorIndexService_ReconcilesAfterSyncInterval(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "A").WithEmbedding(1, 0, 0))
+	cfg := services.DefaultVectorIndexConfig()
+	cfg.SyncInterval = time.Millisecond
+	svc := services.NewVectorIndexService(memory.NewInMemoryVectorIndexStore(nb.DB), nb.Nodes, cfg, zap.NewNop())
+	require.Len(t, idsOf(t, svc, embedding(t, 1, 0, 0), 5), 1)
+
+	// Written without an event, as the embedding worker does
+	late := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Late").WithEmbedding(0, 0, 1))
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, late.ID().String(), idsOf(t, svc, embedding(t, 0, 0, 1), 1)[0])
+}
+
+func TestVectorIndexService_SimilarNodesAndSearch(t *testing.T) {
+	nb := fixtures.NewNotebook("user-1", "Ideas")
+	ref := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Reference").WithEmbedding(1, 0, 0))
+	near := nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Near").WithEmbedding(0.9, 0.1, 0))
+	nb.MustAdd(fixtures.NewNoteBuilder("user-1", "Far").WithEmbedding(0, 0, 1))
+	svc := services.NewVectorIndexService(memory.NewInMemoryVectorIndexStore(nb.DB), nb.Nodes, nil, zap.NewNop())
+
+	similar, err := svc.SimilarTo(context.Background(), "user-1", ref, 1)
+	require.NoError(t, err)
+	require.Len(t, similar, 1)
+	assert.Equal(t, near.ID().String(), similar[0].ID, "the node itself is excluded")
+
+	handler := queries.NewFindSimilarNodesHandler(nb.Nodes).WithVectorIndex(svc)
+	result, err := handler.Handle(context.Background(), &queries.FindSimilarNodesQuery{
+		NodeID: ref.ID().String(), UserID: "user-1", MaxResults: 1, SimilarityType: "semantic",
+	})
+	require.NoError(t, err)
+	found := result.(*queries.FindSimilarNodesResult)
+	require.Len(t, found.Nodes, 1)
+	assert.Equal(t, near.ID(), found.Nodes[0].Node.ID())
+	assert.Greater(t, found.Nodes[0].Similarity, 0.9)
+
+	_, err = handler.Handle(context.Background(), &queries.FindSimilarNodesQuery{
+		NodeID: ref.ID().String(), UserID: "user-2", MaxResults: 1, SimilarityType: "semantic",
+	})
+	assert.Error(t, err, "other users' nodes are not found")
+
+	search := services.NewHybridSearchService(nil, fixedEmbeddings{vector: []float64{0, 0, 1}}, nil, nb.Nodes, nil).
+		WithVectorIndex(svc)
+	results, err := search.Search(context.Background(), "user-1", "zzz", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Far", results[0].Node.Content().Title())
+	assert.Equal(t, []string{"semantic"}, results[0].Sources)
+}
@@ -8,6 +8,9 @@ export const RESOURCE_NAMES = {
   CONNECTIONS_TABLE: 'B2-Connections',
   KEYWORD_INDEX: 'KeywordIndex',
   EDGE_INDEX: 'EdgeIndex',
+  ACTIVITY_INDEX: 'ActivityIndex',
+  PUBLIC_GRAPH_INDEX: 'PublicGraphIndex',
+  OUTBOX_INDEX: 'OutboxIndex',
   CONNECTION_INDEX: 'connection-id-index',
   
   // EventBridge
@@ -62,6 +65,12 @@ export const DYNAMODB_CONFIG = {
   GSI1_SORT_KEY: 'GSI1SK',
   GSI2_PARTITION_KEY: 'GSI2PK',
   GSI2_SORT_KEY: 'GSI2SK',
+  GSI5_PARTITION_KEY: 'GSI5PK',
+  GSI5_SORT_KEY: 'GSI5SK',
+  GSI6_PARTITION_KEY: 'GSI6PK',
+  GSI6_SORT_KEY: 'GSI6SK',
+  GSI7_PARTITION_KEY: 'GSI7PK',
+  GSI7_SORT_KEY: 'GSI7SK',
   TTL_ATTRIBUTE: 'expireAt',
 } as const;
 
@@ -8,6 +8,11 @@ import * as dynamodb from 'aws-cdk-lib/aws-dynamodb';
 import { EnvironmentConfig } from '../config/environments';
 import { RESOURCE_NAMES, DYNAMODB_CONFIG, getResourceName } from '../config/constants';
 
+/**
+ * Context key limiting how many staged memory table indexes are deployed
+ */
+export const MEMORY_INDEX_STAGE_CONTEXT = 'memoryIndexStage';
+
 export interface DatabaseStackProps extends StackProps {
   config: EnvironmentConfig;
 }
@@ -86,6 +91,55 @@ export class DatabaseStack extends Stack {
       projectionType: dynamodb.ProjectionType.ALL,
     });
 
+    // Indexes added to the live table. DynamoDB creates at most one global
+    // secondary index per table update, so an existing table gets them one
+    // deploy at a time, in this order (see docs/deployment.md). A new table
+    // can take them all at once.
+    const stagedIndexes: dynamodb.GlobalSecondaryIndexProps[] = [
+      // Global Secondary Index for recency-ordered node and graph queries
+      {
+        indexName: RESOURCE_NAMES.ACTIVITY_INDEX,
+        partitionKey: { 
+          name: DYNAMODB_CONFIG.GSI5_PARTITION_KEY, 
+          type: dynamodb.AttributeType.STRING 
+        }, // GSI5PK: USER#{userId}#NODE or USER#{userId}#GRAPH
+        sortKey: { 
+          name: DYNAMODB_CONFIG.GSI5_SORT_KEY, 
+          type: dynamodb.AttributeType.STRING 
+        }, // GSI5SK: UPDATED#{updatedAt}#{id}
+        projectionType: dynamodb.ProjectionType.ALL,
+      },
+      // Sparse Global Secondary Index holding only public graphs
+      {
+        indexName: RESOURCE_NAMES.PUBLIC_GRAPH_INDEX,
+        partitionKey: { 
+          name: DYNAMODB_CONFIG.GSI6_PARTITION_KEY, 
+          type: dynamodb.AttributeType.STRING 
+        }, // GSI6PK: PUBLIC#GRAPH
+        sortKey: { 
+          name: DYNAMODB_CONFIG.GSI6_SORT_KEY, 
+          type: dynamodb.AttributeType.STRING 
+        }, // GSI6SK: UPDATED#{updatedAt}#{graphId}
+        projectionType: dynamodb.ProjectionType.ALL,
+      },
+      // Sparse Global Secondary Index holding only undelivered outbox events
+      {
+        indexName: RESOURCE_NAMES.OUTBOX_INDEX,
+        partitionKey: { 
+          name: DYNAMODB_CONFIG.GSI7_PARTITION_KEY, 
+          type: dynamodb.AttributeType.STRING 
+        }, // GSI7PK: OUTBOX#PENDING#{shard} or OUTBOX#DEAD
+        sortKey: { 
+          name: DYNAMODB_CONFIG.GSI7_SORT_KEY, 
+          type: dynamodb.AttributeType.STRING 
+        }, // GSI7SK: {dueAt or deadLetteredAt}#{eventId}
+        projectionType: dynamodb.ProjectionType.ALL,
+      },
+    ];
+    stagedIndexes
+      .slice(0, this.memoryIndexStage(stagedIndexes.length))
+      .forEach((index) => this.memoryTable.addGlobalSecondaryIndex(index));
+
     // DynamoDB table for Event Sourcing
     this.eventsTable = new dynamodb.Table(this, 'EventsTable', {
       tableName: 'b2-events',
@@ -194,4 +248,20 @@ export class DatabaseStack extends Stack {
       Tags.of(this.rateLimitsTable).add(key, value);
     });
   }
+
+  /**
+   * Number of staged memory table indexes to deploy, read from the
+   * memoryIndexStage context value. Without it every stage is deployed.
+   */
+  private memoryIndexStage(stages: number): number {
+    const value = this.node.tryGetContext(MEMORY_INDEX_STAGE_CONTEXT);
+    if (value === undefined) {
+      return stages;
+    }
+    const stage = Number(value);
+    if (!Number.isInteger(stage) || stage < 0 || stage > stages) {
+      throw new Error(`${MEMORY_INDEX_STAGE_CONTEXT} must be a whole number from 0 to ${stages}, got ${value}`);
+    }
+    return stage;
+  }
 }
\ No newline at end of file